@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	alertCmd.AddCommand(addAlertCmd)
+	alertCmd.AddCommand(removeAlertCmd)
+	alertCmd.AddCommand(listAlertsCmd)
+	rootCmd.AddCommand(alertCmd)
+
+	alertCmd.PersistentFlags().StringVarP(&ServerHost, "host", "", "localhost", "Server host")
+	alertCmd.PersistentFlags().IntVarP(&ServerPort, "port", "", -1, "Server HTTP port")
+
+	addAlertCmd.Flags().StringVarP(&AlertName, "name", "", "", "Alert rule name")
+	addAlertCmd.MarkFlagRequired("name")
+	addAlertCmd.Flags().StringVarP(&AlertType, "type", "", "", "Alert rule type, "+core.WaitingProcessesAlert+", "+core.NoExecutorsAlert+" or "+core.FailureRateAlert)
+	addAlertCmd.MarkFlagRequired("type")
+	addAlertCmd.Flags().StringVarP(&TargetExecutorType, "executortype", "", "", "Only consider processes, executors or function runs of this executor type")
+	addAlertCmd.Flags().StringVarP(&FuncName, "func", "", "", "Only consider function runs of this function, failurerate rules only")
+	addAlertCmd.Flags().Float64VarP(&AlertThreshold, "threshold", "", 0, "Max number of waiting processes, max number of alive executors, or max failure rate (0-1) before the alert fires")
+	addAlertCmd.Flags().StringVarP(&AlertFor, "for", "", "0s", "How long the condition must hold before the alert fires, e.g. 5m")
+	addAlertCmd.Flags().StringVarP(&AlertWindow, "window", "", "1h", "Period of function runs the failure rate is calculated from, failurerate rules only")
+
+	removeAlertCmd.Flags().StringVarP(&AlertName, "name", "", "", "Alert rule name")
+	removeAlertCmd.MarkFlagRequired("name")
+
+	listAlertsCmd.Flags().BoolVarP(&JSON, "json", "", false, "Print JSON instead of tables")
+}
+
+var alertCmd = &cobra.Command{
+	Use:   "alert",
+	Short: "Manage alert rules",
+	Long:  "Manage alert rules",
+}
+
+var addAlertCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add an alert rule",
+	Long:  "Add an alert rule",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		forDuration, err := time.ParseDuration(AlertFor)
+		CheckError(err)
+
+		var window time.Duration
+		if AlertType == core.FailureRateAlert {
+			window, err = time.ParseDuration(AlertWindow)
+			CheckError(err)
+		}
+
+		rule := core.CreateAlertRule(ColonyName, AlertName, AlertType, TargetExecutorType, FuncName, AlertThreshold, int64(forDuration.Seconds()), int64(window.Seconds()))
+		addedRule, err := client.AddAlertRule(rule, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"AlertRuleID": addedRule.ID, "Name": addedRule.Name, "Type": addedRule.Type}).Info("Alert rule added")
+	},
+}
+
+var removeAlertCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove an alert rule",
+	Long:  "Remove an alert rule",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		if AlertName == "" {
+			CheckError(errors.New("Alert rule name not specified"))
+		}
+
+		err := client.RemoveAlertRule(ColonyName, AlertName, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"Name": AlertName}).Info("Alert rule removed")
+	},
+}
+
+var listAlertsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List all alert rules and their state",
+	Long:  "List all alert rules and their state",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		rules, err := client.GetAlertRules(ColonyName, PrvKey)
+		CheckError(err)
+
+		if JSON {
+			jsonString, err := core.ConvertAlertRuleArrayToJSON(rules)
+			CheckError(err)
+			fmt.Println(jsonString)
+			os.Exit(0)
+		}
+
+		if len(rules) == 0 {
+			log.WithFields(log.Fields{"ColonyName": ColonyName}).Info("No alert rules found")
+			os.Exit(0)
+		}
+
+		printAlertRulesTable(rules)
+	},
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/colonyos/colonies/internal/table"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/muesli/termenv"
+)
+
+func alertCondition(rule *core.AlertRule) string {
+	threshold := strconv.FormatFloat(rule.Threshold, 'f', -1, 64)
+	target := ""
+	if rule.ExecutorType != "" {
+		target = rule.ExecutorType
+	}
+	if rule.FuncName != "" {
+		if target != "" {
+			target += "/"
+		}
+		target += rule.FuncName
+	}
+	if target == "" {
+		target = "*"
+	}
+
+	condition := ""
+	switch rule.Type {
+	case core.WaitingProcessesAlert:
+		condition = "waiting(" + target + ") > " + threshold
+	case core.NoExecutorsAlert:
+		condition = "alive(" + target + ") <= " + threshold
+	case core.FailureRateAlert:
+		condition = "failurerate(" + target + ", " + (time.Duration(rule.Window) * time.Second).String() + ") > " + threshold
+	}
+
+	if rule.For > 0 {
+		condition += " for " + (time.Duration(rule.For) * time.Second).String()
+	}
+
+	return condition
+}
+
+func printAlertRulesTable(rules []*core.AlertRule) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "name", Name: "Name", SortIndex: 1},
+		{ID: "condition", Name: "Condition", SortIndex: 2},
+		{ID: "state", Name: "State", SortIndex: 3},
+		{ID: "value", Name: "Value", SortIndex: 4},
+		{ID: "since", Name: "Since", SortIndex: 5},
+	}
+	t.SetCols(cols)
+
+	for _, rule := range rules {
+		state := termenv.String(rule.State).Foreground(theme.ColorGreen)
+		since := ""
+		switch rule.State {
+		case core.AlertFiring:
+			state = termenv.String(rule.State).Foreground(theme.ColorRed)
+			since = rule.FiredTime.Format(TimeLayout)
+		case core.AlertPending:
+			state = termenv.String(rule.State).Foreground(theme.ColorYellow)
+			since = rule.PendingSince.Format(TimeLayout)
+		default:
+			if !rule.ResolvedTime.IsZero() {
+				since = rule.ResolvedTime.Format(TimeLayout)
+			}
+		}
+		row := []interface{}{
+			termenv.String(rule.Name).Foreground(theme.ColorCyan),
+			termenv.String(alertCondition(rule)).Foreground(theme.ColorViolet),
+			state,
+			termenv.String(strconv.FormatFloat(rule.Value, 'f', -1, 64)).Foreground(theme.ColorBlue),
+			termenv.String(since).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
@@ -1,16 +1,36 @@
 package cli
 
 import (
+	"strconv"
+	"strings"
+
+	"github.com/colonyos/colonies/pkg/cluster"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	clusterCmd.AddCommand(clusterInfoCmd)
+	clusterCmd.AddCommand(clusterJoinCmd)
+	clusterCmd.AddCommand(clusterLeaveCmd)
 	rootCmd.AddCommand(clusterCmd)
 
-	clusterInfoCmd.Flags().StringVarP(&ServerHost, "host", "", "localhost", "Server host")
-	clusterInfoCmd.Flags().IntVarP(&ServerPort, "port", "", -1, "Server HTTP port")
-	clusterInfoCmd.Flags().StringVarP(&ServerPrvKey, "serverprvkey", "", "", "Colonies server private key")
+	clusterCmd.PersistentFlags().StringVarP(&ServerHost, "host", "", "localhost", "Server host")
+	clusterCmd.PersistentFlags().IntVarP(&ServerPort, "port", "", -1, "Server HTTP port")
+	clusterCmd.PersistentFlags().StringVarP(&ServerPrvKey, "serverprvkey", "", "", "Colonies server private key")
+
+	clusterJoinCmd.Flags().StringVarP(&EtcdName, "name", "", "", "Name of the node")
+	clusterJoinCmd.MarkFlagRequired("name")
+	clusterJoinCmd.Flags().StringVarP(&EtcdHost, "nodehost", "", "", "Host name of the node")
+	clusterJoinCmd.MarkFlagRequired("nodehost")
+	clusterJoinCmd.Flags().IntVarP(&EtcdClientPort, "etcdclientport", "", 2379, "Etcd port of the node")
+	clusterJoinCmd.Flags().IntVarP(&EtcdPeerPort, "etcdpeerport", "", 2380, "Etcd peer port of the node")
+	clusterJoinCmd.Flags().IntVarP(&RelayPort, "relayport", "", 2381, "Relay port of the node")
+	clusterJoinCmd.Flags().IntVarP(&NodeAPIPort, "apiport", "", -1, "Server HTTP port of the node")
+	clusterJoinCmd.MarkFlagRequired("apiport")
+
+	clusterLeaveCmd.Flags().StringVarP(&EtcdName, "name", "", "", "Name of the node")
+	clusterLeaveCmd.MarkFlagRequired("name")
 }
 
 var clusterCmd = &cobra.Command{
@@ -33,6 +53,47 @@ var clusterInfoCmd = &cobra.Command{
 	},
 }
 
+var clusterJoinCmd = &cobra.Command{
+	Use:   "join",
+	Short: "Add a node to a Colonies cluster",
+	Long:  "Add a node to a Colonies cluster, the node must then be started with --join and the printed --initial-cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		node := cluster.Node{Name: EtcdName, Host: EtcdHost, EtcdClientPort: EtcdClientPort, EtcdPeerPort: EtcdPeerPort, RelayPort: RelayPort, APIPort: NodeAPIPort}
+		clusterConfig, err := client.AddClusterNode(node, ServerPrvKey)
+		CheckError(err)
+
+		printClusterInfoTable(clusterConfig)
+
+		log.WithFields(log.Fields{"Name": node.Name, "InitialCluster": initialClusterStr(clusterConfig)}).Info("Node added to cluster, start it with: colonies server start --join --initial-cluster <InitialCluster>")
+	},
+}
+
+var clusterLeaveCmd = &cobra.Command{
+	Use:   "leave",
+	Short: "Remove a node from a Colonies cluster",
+	Long:  "Remove a node from a Colonies cluster, if the node is the leader, leadership is first transferred to another node",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		err := client.RemoveClusterNode(EtcdName, ServerPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"Name": EtcdName}).Info("Node removed from cluster")
+	},
+}
+
+// initialClusterStr returns the cluster in the --initial-cluster format of colonies server start
+func initialClusterStr(clusterConfig *cluster.Config) string {
+	var nodes []string
+	for _, node := range clusterConfig.Nodes {
+		nodes = append(nodes, node.Name+"="+node.Host+":"+strconv.Itoa(node.EtcdPeerPort)+":"+strconv.Itoa(node.RelayPort)+":"+strconv.Itoa(node.APIPort))
+	}
+
+	return strings.Join(nodes, ",")
+}
+
 func isLeader(leader string, name string) string {
 	if leader == name {
 		return "True"
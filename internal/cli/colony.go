@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	log "github.com/sirupsen/logrus"
@@ -16,7 +17,14 @@ func init() {
 	colonyCmd.AddCommand(removeColonyCmd)
 	colonyCmd.AddCommand(lsColoniesCmd)
 	colonyCmd.AddCommand(colonyStatsCmd)
+	colonyCmd.AddCommand(colonyStatsHistoryCmd)
+	colonyCmd.AddCommand(colonyUsageCmd)
 	colonyCmd.AddCommand(checkColonyCmd)
+	colonyCmd.AddCommand(colonyRetentionCmd)
+	colonyCmd.AddCommand(resolveColonyCmd)
+	colonyRetentionCmd.AddCommand(setColonyRetentionCmd)
+	colonyRetentionCmd.AddCommand(getColonyRetentionCmd)
+	colonyRetentionCmd.AddCommand(removeColonyRetentionCmd)
 	rootCmd.AddCommand(colonyCmd)
 
 	colonyCmd.PersistentFlags().StringVarP(&ServerHost, "host", "", DefaultServerHost, "Server host")
@@ -40,6 +48,29 @@ func init() {
 
 	checkColonyCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Colonies server private key")
 	checkColonyCmd.Flags().StringVarP(&TargetColonyName, "name", "", "", "Unique name of the Colony")
+
+	setColonyRetentionCmd.Flags().Int64VarP(&RetainSuccessful, "successful", "", 0, "Seconds to keep successful processes, 0 uses the server retention policy if retention is enabled, otherwise keeps them forever")
+	setColonyRetentionCmd.Flags().Int64VarP(&RetainFailed, "failed", "", 0, "Seconds to keep failed processes, 0 keeps them forever")
+	setColonyRetentionCmd.Flags().Int64VarP(&RetainProcessGraphs, "workflows", "", 0, "Seconds to keep completed workflows, 0 uses the server retention policy if retention is enabled, otherwise keeps them forever")
+	setColonyRetentionCmd.Flags().Int64VarP(&RetainLogs, "logs", "", 0, "Seconds to keep logs, 0 uses the server retention policy if retention is enabled, otherwise keeps them forever")
+	setColonyRetentionCmd.Flags().Int64VarP(&RetainFiles, "files", "", 0, "Seconds to keep old file revisions, 0 keeps them forever")
+	setColonyRetentionCmd.Flags().BoolVarP(&Archive, "archive", "", false, "Archive expired data to object storage before removing it")
+
+	getColonyRetentionCmd.Flags().BoolVarP(&JSON, "json", "", false, "Print JSON instead of tables")
+
+	colonyStatsHistoryCmd.Flags().StringVarP(&StatsSince, "since", "", "24h", "Period back in time to show statistics snapshots for, e.g. 30m, 24h")
+	colonyStatsHistoryCmd.Flags().BoolVarP(&JSON, "json", "", false, "Print JSON instead of tables")
+	colonyStatsHistoryCmd.Flags().BoolVarP(&CSV, "csv", "", false, "Print CSV instead of tables")
+
+	colonyUsageCmd.Flags().StringVarP(&UsageFrom, "from", "", "", "Start of period, YYYY-MM-DD or RFC3339, defaults to start of current month")
+	colonyUsageCmd.Flags().StringVarP(&UsageTo, "to", "", "", "End of period, YYYY-MM-DD or RFC3339, defaults to now")
+	colonyUsageCmd.Flags().StringVarP(&UsageGroupBy, "groupby", "", core.UsageByUser, "Group usage by user, executor or label")
+	colonyUsageCmd.Flags().BoolVarP(&JSON, "json", "", false, "Print JSON instead of tables")
+	colonyUsageCmd.Flags().BoolVarP(&CSV, "csv", "", false, "Print CSV instead of tables")
+
+	resolveColonyCmd.Flags().StringVarP(&TargetColonyName, "name", "", "", "Colony name, defaults to COLONIES_COLONY_NAME")
+	resolveColonyCmd.Flags().StringVarP(&DHTBootstrap, "dhtbootstrap", "", "", "Address of a DHT node, defaults to COLONIES_DHT_BOOTSTRAP")
+	resolveColonyCmd.Flags().BoolVarP(&JSON, "json", "", false, "Print JSON instead of tables")
 }
 
 var colonyCmd = &cobra.Command{
@@ -154,6 +185,95 @@ var colonyStatsCmd = &cobra.Command{
 	},
 }
 
+var colonyStatsHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show periodic statistics snapshots of a Colony",
+	Long:  "Show periodic statistics snapshots of a Colony",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		since, err := time.ParseDuration(StatsSince)
+		CheckError(err)
+
+		to := time.Now()
+		snapshots, err := client.ColonyStatisticsHistory(ColonyName, to.Add(-since), to, PrvKey)
+		CheckError(err)
+
+		if JSON {
+			jsonStr, err := core.ConvertStatisticsSnapshotArrayToJSON(snapshots)
+			CheckError(err)
+			fmt.Println(jsonStr)
+			os.Exit(0)
+		}
+
+		if CSV {
+			CheckError(writeStatisticsSnapshotsCSV(os.Stdout, snapshots))
+			os.Exit(0)
+		}
+
+		if len(snapshots) == 0 {
+			log.WithFields(log.Fields{"ColonyName": ColonyName, "Since": StatsSince}).Info("No statistics snapshots found")
+			os.Exit(0)
+		}
+
+		printStatisticsSnapshotsTable(snapshots)
+	},
+}
+
+var colonyUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show CPU, GPU and storage usage of a Colony for chargeback",
+	Long:  "Show CPU, GPU and storage usage of a Colony for chargeback",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		now := time.Now()
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		to := now
+		var err error
+		if UsageFrom != "" {
+			from, err = parseUsageTime(UsageFrom)
+			CheckError(err)
+		}
+		if UsageTo != "" {
+			to, err = parseUsageTime(UsageTo)
+			CheckError(err)
+		}
+
+		usage, err := client.GetColonyUsage(ColonyName, from, to, UsageGroupBy, PrvKey)
+		CheckError(err)
+
+		if JSON {
+			jsonStr, err := usage.ToJSON()
+			CheckError(err)
+			fmt.Println(jsonStr)
+			os.Exit(0)
+		}
+
+		if CSV {
+			CheckError(writeColonyUsageCSV(os.Stdout, usage))
+			os.Exit(0)
+		}
+
+		printColonyUsageTable(usage)
+	},
+}
+
+// parseUsageTime parses a date (YYYY-MM-DD) in local time or an RFC3339 timestamp
+func parseUsageTime(str string) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", str, time.Local)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(time.RFC3339, str)
+	if err != nil {
+		return time.Time{}, errors.New("Invalid time <" + str + ">, must be YYYY-MM-DD or RFC3339")
+	}
+
+	return t, nil
+}
+
 var checkColonyCmd = &cobra.Command{
 	Use:   "check",
 	Short: "Check if a Colony exists",
@@ -171,3 +291,96 @@ var checkColonyCmd = &cobra.Command{
 		log.WithFields(log.Fields{"ColonyName": TargetColonyName}).Info("Colony exists")
 	},
 }
+
+var colonyRetentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Manage Colony retention policy",
+	Long:  "Manage Colony retention policy",
+}
+
+var setColonyRetentionCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set a Colony retention policy",
+	Long:  "Set a Colony retention policy, colony retention policies are applied even if server wide retention is disabled",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		policy := core.CreateRetentionPolicy(ColonyName)
+		policy.SuccessfulProcesses = RetainSuccessful
+		policy.FailedProcesses = RetainFailed
+		policy.ProcessGraphs = RetainProcessGraphs
+		policy.Logs = RetainLogs
+		policy.Files = RetainFiles
+		policy.Archive = Archive
+
+		_, err := client.SetRetentionPolicy(policy, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"ColonyName": ColonyName}).Info("Retention policy set")
+	},
+}
+
+var getColonyRetentionCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Show a Colony retention policy",
+	Long:  "Show a Colony retention policy",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		policy, err := client.GetRetentionPolicy(ColonyName, ColonyPrvKey)
+		CheckError(err)
+
+		if JSON {
+			jsonString, err := policy.ToJSON()
+			CheckError(err)
+			fmt.Println(jsonString)
+			os.Exit(0)
+		}
+
+		printRetentionPolicyTable(policy)
+	},
+}
+
+var removeColonyRetentionCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove a Colony retention policy",
+	Long:  "Remove a Colony retention policy",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		err := client.RemoveRetentionPolicy(ColonyName, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"ColonyName": ColonyName}).Info("Retention policy removed")
+	},
+}
+
+var resolveColonyCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Find the servers of a Colony in the DHT",
+	Long:  "Find the servers of a Colony in the DHT",
+	Run: func(cmd *cobra.Command, args []string) {
+		parseEnv()
+
+		colonyName := TargetColonyName
+		if colonyName == "" {
+			colonyName = ColonyName
+		}
+
+		if colonyName == "" {
+			CheckError(errors.New("Colony name not set, use --name or COLONIES_COLONY_NAME"))
+		}
+
+		endpoints, err := resolveColony(colonyName)
+		CheckError(err)
+
+		if JSON {
+			jsonString, err := core.ConvertServerEndpointArrayToJSON(endpoints)
+			CheckError(err)
+			fmt.Println(jsonString)
+			os.Exit(0)
+		}
+
+		printServerEndpointTable(endpoints)
+	},
+}
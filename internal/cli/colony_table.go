@@ -1,7 +1,11 @@
 package cli
 
 import (
+	"encoding/csv"
+	"fmt"
+	"io"
 	"strconv"
+	"time"
 
 	"github.com/colonyos/colonies/internal/table"
 	"github.com/colonyos/colonies/pkg/core"
@@ -93,3 +97,191 @@ func printColonyStatTable(stat *core.Statistics) {
 
 	t.Render()
 }
+
+func retentionPeriodText(seconds int64) string {
+	if seconds == 0 {
+		return "Forever"
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+func printRetentionPolicyTable(policy *core.RetentionPolicy) {
+	t, theme := createTable(0)
+
+	rows := [][]string{
+		{"Colony", policy.ColonyName},
+		{"Successful processes", retentionPeriodText(policy.SuccessfulProcesses)},
+		{"Failed processes", retentionPeriodText(policy.FailedProcesses)},
+		{"Workflows", retentionPeriodText(policy.ProcessGraphs)},
+		{"Logs", retentionPeriodText(policy.Logs)},
+		{"File revisions", retentionPeriodText(policy.Files)},
+		{"Archive", strconv.FormatBool(policy.Archive)},
+	}
+
+	for _, r := range rows {
+		row := []interface{}{
+			termenv.String(r[0]).Foreground(theme.ColorCyan),
+			termenv.String(r[1]).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
+func printStatisticsSnapshotsTable(snapshots []*core.StatisticsSnapshot) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "time", Name: "Time", SortIndex: 1},
+		{ID: "executors", Name: "Executors", SortIndex: 2},
+		{ID: "waiting", Name: "Waiting", SortIndex: 3},
+		{ID: "running", Name: "Running", SortIndex: 4},
+		{ID: "successful", Name: "Successful", SortIndex: 5},
+		{ID: "failed", Name: "Failed", SortIndex: 6},
+		{ID: "waitingwf", Name: "WaitingWF", SortIndex: 7},
+		{ID: "runningwf", Name: "RunningWF", SortIndex: 8},
+	}
+	t.SetCols(cols)
+
+	for _, snapshot := range snapshots {
+		stat := snapshot.Statistics
+		row := []interface{}{
+			termenv.String(snapshot.Time.Format(TimeLayout)).Foreground(theme.ColorCyan),
+			termenv.String(strconv.Itoa(stat.Executors)).Foreground(theme.ColorGray),
+			termenv.String(strconv.Itoa(stat.WaitingProcesses)).Foreground(theme.ColorGray),
+			termenv.String(strconv.Itoa(stat.RunningProcesses)).Foreground(theme.ColorGray),
+			termenv.String(strconv.Itoa(stat.SuccessfulProcesses)).Foreground(theme.ColorGreen),
+			termenv.String(strconv.Itoa(stat.FailedProcesses)).Foreground(theme.ColorRed),
+			termenv.String(strconv.Itoa(stat.WaitingWorkflows)).Foreground(theme.ColorGray),
+			termenv.String(strconv.Itoa(stat.RunningWorkflows)).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
+func writeStatisticsSnapshotsCSV(w io.Writer, snapshots []*core.StatisticsSnapshot) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"time", "executors", "waiting_processes", "running_processes", "successful_processes", "failed_processes", "waiting_workflows", "running_workflows", "successful_workflows", "failed_workflows"})
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range snapshots {
+		stat := snapshot.Statistics
+		err = writer.Write([]string{
+			snapshot.Time.Format(time.RFC3339),
+			strconv.Itoa(stat.Executors),
+			strconv.Itoa(stat.WaitingProcesses),
+			strconv.Itoa(stat.RunningProcesses),
+			strconv.Itoa(stat.SuccessfulProcesses),
+			strconv.Itoa(stat.FailedProcesses),
+			strconv.Itoa(stat.WaitingWorkflows),
+			strconv.Itoa(stat.RunningWorkflows),
+			strconv.Itoa(stat.SuccessfulWorkflows),
+			strconv.Itoa(stat.FailedWorkflows),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func printColonyUsageTable(usage *core.ColonyUsage) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "key", Name: usage.GroupBy, SortIndex: 1},
+		{ID: "processes", Name: "Processes", SortIndex: 2},
+		{ID: "failed", Name: "Failed", SortIndex: 3},
+		{ID: "cpuseconds", Name: "CPU-seconds", SortIndex: 4},
+		{ID: "gpuseconds", Name: "GPU-seconds", SortIndex: 5},
+		{ID: "storage", Name: "Storage", SortIndex: 6},
+	}
+	t.SetCols(cols)
+
+	addRow := func(u *core.Usage, key string, color termenv.Color) {
+		row := []interface{}{
+			termenv.String(key).Foreground(color),
+			termenv.String(strconv.Itoa(u.Processes)).Foreground(theme.ColorGray),
+			termenv.String(strconv.Itoa(u.Failed)).Foreground(theme.ColorGray),
+			termenv.String(fmt.Sprintf("%.1f", u.CPUSeconds)).Foreground(theme.ColorGray),
+			termenv.String(fmt.Sprintf("%.1f", u.GPUSeconds)).Foreground(theme.ColorGray),
+			termenv.String(strconv.FormatInt(u.StorageBytes, 10) + " B").Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	for _, u := range usage.Usage {
+		key := u.Key
+		if key == "" {
+			key = "-"
+		}
+		addRow(u, key, theme.ColorCyan)
+	}
+	addRow(usage.Total, "Total", theme.ColorViolet)
+
+	t.Render()
+}
+
+func writeColonyUsageCSV(w io.Writer, usage *core.ColonyUsage) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"colony", "from", "to", usage.GroupBy, "processes", "successful", "failed", "cpu_seconds", "gpu_seconds", "storage_bytes"})
+	if err != nil {
+		return err
+	}
+
+	from := usage.From.Format(time.RFC3339)
+	to := usage.To.Format(time.RFC3339)
+	for _, u := range usage.Usage {
+		err = writer.Write([]string{
+			usage.ColonyName,
+			from,
+			to,
+			u.Key,
+			strconv.Itoa(u.Processes),
+			strconv.Itoa(u.Successful),
+			strconv.Itoa(u.Failed),
+			strconv.FormatFloat(u.CPUSeconds, 'f', 3, 64),
+			strconv.FormatFloat(u.GPUSeconds, 'f', 3, 64),
+			strconv.FormatInt(u.StorageBytes, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func printServerEndpointTable(endpoints []*core.ServerEndpoint) {
+	t, theme := createTable(1)
+
+	var cols = []table.Column{
+		{ID: "name", Name: "Name", SortIndex: 1},
+		{ID: "host", Name: "Host", SortIndex: 2},
+		{ID: "port", Name: "Port", SortIndex: 3},
+		{ID: "tls", Name: "TLS", SortIndex: 4},
+		{ID: "published", Name: "Published", SortIndex: 5},
+	}
+	t.SetCols(cols)
+
+	for _, endpoint := range endpoints {
+		row := []interface{}{
+			termenv.String(endpoint.Name).Foreground(theme.ColorCyan),
+			termenv.String(endpoint.Host).Foreground(theme.ColorViolet),
+			termenv.String(strconv.Itoa(endpoint.Port)).Foreground(theme.ColorBlue),
+			termenv.String(strconv.FormatBool(endpoint.TLS)).Foreground(theme.ColorMagenta),
+			termenv.String(endpoint.Published.Format(TimeLayout)).Foreground(theme.ColorGreen),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -12,6 +14,7 @@ import (
 	"github.com/colonyos/colonies/pkg/client"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/server"
+	"github.com/colonyos/colonies/pkg/tracing"
 	"github.com/colonyos/colonies/pkg/validate"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
@@ -154,6 +157,25 @@ func setupProfiler() {
 	}
 }
 
+// setupTracing exports OpenTelemetry spans to the OTLP gRPC endpoint in COLONIES_OTLP_ENDPOINT, e.g. localhost:4317.
+// Tracing is disabled if no endpoint is set.
+func setupTracing() func(context.Context) error {
+	endpoint := os.Getenv("COLONIES_OTLP_ENDPOINT")
+	insecure := os.Getenv("COLONIES_OTLP_INSECURE") == "true"
+
+	shutdown, err := tracing.Setup("colonies-server", endpoint, insecure)
+	if err != nil {
+		log.Error("Failed to setup tracing")
+	}
+	CheckError(err)
+
+	if endpoint != "" {
+		log.WithFields(log.Fields{"Endpoint": endpoint, "Insecure": insecure}).Info("Exporting traces")
+	}
+
+	return shutdown
+}
+
 func parseEnv() {
 	var err error
 	ASCII = false
@@ -167,6 +189,23 @@ func parseEnv() {
 		ServerHost = ServerHostEnv
 	}
 
+	if DHTBootstrap == "" {
+		DHTBootstrap = os.Getenv("COLONIES_DHT_BOOTSTRAP")
+	}
+
+	if DHTDataDir == "" {
+		DHTDataDir = os.Getenv("COLONIES_DHT_DATA_DIR")
+	}
+
+	DHTPortEnvStr := os.Getenv("COLONIES_DHT_PORT")
+	if DHTPortEnvStr != "" && DHTPort == 0 {
+		DHTPort, err = strconv.Atoi(DHTPortEnvStr)
+		if err != nil {
+			log.Error("Failed to parse COLONIES_DHT_PORT")
+		}
+		CheckError(err)
+	}
+
 	ServerPortEnvStr := os.Getenv("COLONIES_SERVER_PORT")
 	if ServerPortEnvStr != "" {
 		if ServerPort == -1 {
@@ -340,6 +379,74 @@ func parseEnv() {
 		CheckError(err)
 	}
 
+	AnomalyFactor = server.DEFAULT_ANOMALY_FACTOR
+	anomalyFactorStr := os.Getenv("COLONIES_ANOMALY_FACTOR")
+	if anomalyFactorStr != "" {
+		AnomalyFactor, err = strconv.ParseFloat(anomalyFactorStr, 64)
+		if err != nil {
+			log.Error("Failed to parse COLONIES_ANOMALY_FACTOR")
+		}
+		CheckError(err)
+	}
+
+	StatisticsPeriod = int(server.STATISTICS_SNAPSHOT_PERIOD.Seconds())
+	statisticsPeriodStr := os.Getenv("COLONIES_STATISTICS_PERIOD")
+	if statisticsPeriodStr != "" {
+		StatisticsPeriod, err = strconv.Atoi(statisticsPeriodStr)
+		if err != nil {
+			log.Error("Failed to parse COLONIES_STATISTICS_PERIOD")
+		}
+		CheckError(err)
+	}
+
+	ColonyDBBudget = server.DEFAULT_COLONY_DB_BUDGET
+	colonyDBBudgetStr := os.Getenv("COLONIES_COLONY_DB_BUDGET")
+	if colonyDBBudgetStr != "" {
+		ColonyDBBudget, err = strconv.Atoi(colonyDBBudgetStr)
+		if err != nil {
+			log.Error("Failed to parse COLONIES_COLONY_DB_BUDGET")
+		}
+		CheckError(err)
+	}
+
+	ColonyWeights, err = parseColonyWeights(os.Getenv("COLONIES_COLONY_WEIGHTS"))
+	if err != nil {
+		log.Error("Failed to parse COLONIES_COLONY_WEIGHTS")
+	}
+	CheckError(err)
+
+}
+
+// parseColonyWeights parses colony weights on the form colony1=4,colony2=2
+func parseColonyWeights(str string) (map[string]int, error) {
+	weights := make(map[string]int)
+	if str == "" {
+		return weights, nil
+	}
+
+	for _, pair := range strings.Split(str, ",") {
+		s := strings.Split(pair, "=")
+		if len(s) != 2 || strings.TrimSpace(s[0]) == "" {
+			return nil, errors.New("Invalid colony weight <" + pair + ">, expected colony=weight")
+		}
+
+		weight, err := strconv.Atoi(strings.TrimSpace(s[1]))
+		if err != nil {
+			return nil, err
+		}
+
+		weights[strings.TrimSpace(s[0])] = weight
+	}
+
+	return weights, nil
+}
+
+// setColonyLimits configures the weights and database budgets used to isolate the colonies on the server
+func setColonyLimits(coloniesServer *server.ColoniesServer) {
+	coloniesServer.SetColonyDBBudget(ColonyDBBudget)
+	for colonyName, weight := range ColonyWeights {
+		coloniesServer.SetColonyWeight(colonyName, weight)
+	}
 }
 
 func checkDevEnv() {
@@ -486,6 +593,11 @@ func setup() *client.ColoniesClient {
 		envError()
 	}
 
+	// Colonies can be found without knowing the server address, if the servers publish their endpoints in a DHT
+	if os.Getenv("COLONIES_SERVER_HOST") == "" && DHTBootstrap != "" {
+		resolveServer()
+	}
+
 	if ServerHost == "" {
 		log.Error("COLONIES_SERVER_HOST not set")
 		envError()
@@ -497,6 +609,12 @@ func setup() *client.ColoniesClient {
 	}
 
 	log.WithFields(log.Fields{"ServerHost": ServerHost, "ServerPort": ServerPort, "Insecure": Insecure}).Debug("Starting a Colonies client")
+	if len(resolvedEndpoints) > 0 {
+		c, err := client.CreateColoniesClientFromEndpoints(resolvedEndpoints, SkipTLSVerify)
+		CheckError(err)
+		return c
+	}
+
 	return client.CreateColoniesClient(ServerHost, ServerPort, Insecure, SkipTLSVerify)
 }
 
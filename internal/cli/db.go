@@ -2,12 +2,16 @@ package cli
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/colonyos/colonies/pkg/database"
 	"github.com/colonyos/colonies/pkg/database/postgresql"
+	"github.com/colonyos/colonies/pkg/database/sqlite"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -15,6 +19,8 @@ import (
 func init() {
 	dbCmd.AddCommand(dbCreateCmd)
 	dbCmd.AddCommand(dbDropCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbStatusCmd)
 	rootCmd.AddCommand(dbCmd)
 
 	dbCmd.PersistentFlags().StringVarP(&DBHost, "dbhost", "", DefaultDBHost, "Colonies database host")
@@ -61,6 +67,107 @@ func parseDBEnv() {
 	if initDBStr == "true" {
 		InitDB = true
 	}
+
+	DBReadReplicas = nil
+	readReplicasEnv := os.Getenv("COLONIES_DB_READ_REPLICAS")
+	for _, dsn := range strings.Split(readReplicasEnv, ",") {
+		dsn = strings.TrimSpace(dsn)
+		if dsn != "" {
+			DBReadReplicas = append(DBReadReplicas, dsn)
+		}
+	}
+
+	DBReplicaMaxLag = DefaultReplicaMaxLag
+	replicaMaxLagStr := os.Getenv("COLONIES_DB_REPLICA_MAX_LAG")
+	if replicaMaxLagStr != "" {
+		DBReplicaMaxLag, err = strconv.Atoi(replicaMaxLagStr)
+		CheckError(err)
+	}
+
+	DBType = os.Getenv("COLONIES_DB_TYPE")
+	if DBType == "" {
+		DBType = DBTypePostgreSQL
+	}
+
+	if DBType != DBTypePostgreSQL && DBType != DBTypeSQLite {
+		CheckError(errors.New("Invalid COLONIES_DB_TYPE " + DBType + ", must be " + DBTypePostgreSQL + " or " + DBTypeSQLite))
+	}
+
+	DBPath = os.Getenv("COLONIES_DB_PATH")
+	if DBPath == "" {
+		DBPath = DefaultSQLitePath
+	}
+
+	// A new SQLite database file is always initialized since there is no separate database server to prepare
+	if DBType == DBTypeSQLite {
+		if _, err := os.Stat(DBPath); os.IsNotExist(err) {
+			InitDB = true
+		}
+	}
+}
+
+func connectDB() database.Database {
+	if DBType == DBTypeSQLite {
+		db := sqlite.CreateSQLiteDatabase(DBPath, DBPrefix)
+		err := db.Connect()
+		CheckError(err)
+
+		log.WithFields(log.Fields{"DBPath": DBPath}).Info("Connected to SQLite database")
+
+		return db
+	}
+
+	var db *postgresql.PQDatabase
+	for {
+		db = postgresql.CreatePQDatabase(DBHost, DBPort, DBUser, DBPassword, DBName, DBPrefix, TimescaleDB)
+		db.SetReadReplicas(DBReadReplicas, time.Duration(DBReplicaMaxLag)*time.Second)
+		err := db.Connect()
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err}).Error("Failed to connect to PostgreSQL database, retrying in 1 second ...")
+			time.Sleep(1 * time.Second)
+		} else {
+			break
+		}
+	}
+
+	log.WithFields(log.Fields{"DBHost": DBHost, "DBPort": DBPort, "DBUser": DBUser, "DBPassword": "*******************", "DBName": DBName, "TimescaleDB": TimescaleDB, "ReadReplicas": len(DBReadReplicas), "ReplicaMaxLag": DBReplicaMaxLag}).Info("Connected to PostgreSQL database")
+
+	return db
+}
+
+// migrateDB upgrades the database schema while holding the database lock, so that only one server migrates the
+// database when several servers are started at the same time
+func migrateDB(db database.Database) {
+	version, err := db.SchemaVersion()
+	CheckError(err)
+
+	if version == 0 {
+		log.Warn("Colonies database is not initialized, skipping schema migration")
+		return
+	}
+
+	if version == db.LatestSchemaVersion() {
+		log.WithFields(log.Fields{"SchemaVersion": version}).Info("Colonies database schema is up to date")
+		return
+	}
+
+	log.WithFields(log.Fields{"Timeout": MigrationLockTimeout}).Info("Acquiring database lock to migrate database schema")
+	err = db.Lock(MigrationLockTimeout)
+	CheckError(err)
+
+	err = db.Migrate()
+	if err != nil {
+		db.Unlock()
+		CheckError(err)
+	}
+
+	err = db.Unlock()
+	CheckError(err)
+
+	version, err = db.SchemaVersion()
+	CheckError(err)
+
+	log.WithFields(log.Fields{"SchemaVersion": version}).Info("Colonies database schema migrated")
 }
 
 var dbCreateCmd = &cobra.Command{
@@ -71,19 +178,8 @@ var dbCreateCmd = &cobra.Command{
 		parseEnv()
 		parseDBEnv()
 
-		var db *postgresql.PQDatabase
-		for {
-			db = postgresql.CreatePQDatabase(DBHost, DBPort, DBUser, DBPassword, DBName, DBPrefix, TimescaleDB)
-			err := db.Connect()
-			if err != nil {
-				log.WithFields(log.Fields{"Error": err}).Error("Failed to call db.Connect(), retrying in 1 second ...")
-				time.Sleep(1 * time.Second)
-			} else {
-				break
-			}
-		}
-
-		log.WithFields(log.Fields{"Host": DBHost, "Port": DBPort, "User": DBUser, "Password": "**********************", "Prefix": DBPrefix}).Error("Connected to PostgreSQL database")
+		InitDB = true
+		db := connectDB()
 
 		err := db.Initialize()
 		if err != nil {
@@ -111,13 +207,10 @@ var dbDropCmd = &cobra.Command{
 		reply, _ := reader.ReadString('\n')
 
 		if reply == "YES\n" {
-			log.WithFields(log.Fields{"DBHost": DBHost, "DBPort": DBPort, "DBUser": DBUser, "DBPassword": "*******************", "DBName": DBName, "UseTLS": UseTLS, "TimescaleDB": TimescaleDB}).Info("Connecting to PostgreSQL database")
-
-			db := postgresql.CreatePQDatabase(DBHost, DBPort, DBUser, DBPassword, DBName, DBPrefix, TimescaleDB)
-			err := db.Connect()
-			CheckError(err)
+			InitDB = true
+			db := connectDB()
 
-			err = db.Drop()
+			err := db.Drop()
 			CheckError(err)
 			log.Info("Colonies database dropped")
 		} else {
@@ -125,3 +218,48 @@ var dbDropCmd = &cobra.Command{
 		}
 	},
 }
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate the database schema to the latest version",
+	Long:  "Migrate the database schema to the latest version",
+	Run: func(cmd *cobra.Command, args []string) {
+		parseDBEnv()
+
+		InitDB = false
+		db := connectDB()
+		defer db.Close()
+
+		migrateDB(db)
+	},
+}
+
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show database schema version and pending migrations",
+	Long:  "Show database schema version and pending migrations",
+	Run: func(cmd *cobra.Command, args []string) {
+		parseDBEnv()
+
+		InitDB = false
+		db := connectDB()
+		defer db.Close()
+
+		version, err := db.SchemaVersion()
+		CheckError(err)
+
+		printSchemaStatusTable(version, db.LatestSchemaVersion())
+
+		var pending []database.Migration
+		for _, migration := range db.Migrations() {
+			if version > 0 && migration.Version > version {
+				pending = append(pending, migration)
+			}
+		}
+
+		if len(pending) > 0 {
+			fmt.Println()
+			printMigrationsTable(pending)
+		}
+	},
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"strconv"
+
+	"github.com/colonyos/colonies/internal/table"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/muesli/termenv"
+)
+
+func printSchemaStatusTable(version int, latestVersion int) {
+	t, theme := createTable(0)
+
+	status := "Up to date"
+	versionStr := strconv.Itoa(version)
+	if version == 0 {
+		status = "Not initialized"
+		versionStr = "-"
+	} else if version < latestVersion {
+		status = strconv.Itoa(latestVersion-version) + " pending migrations"
+	} else if version > latestVersion {
+		status = "Newer than supported"
+	}
+
+	rows := [][]string{
+		{"Database", DBType},
+		{"Schema version", versionStr},
+		{"Latest version", strconv.Itoa(latestVersion)},
+		{"Status", status},
+	}
+
+	for _, r := range rows {
+		row := []interface{}{
+			termenv.String(r[0]).Foreground(theme.ColorCyan),
+			termenv.String(r[1]).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
+func printMigrationsTable(migrations []database.Migration) {
+	t, theme := createTable(1)
+
+	var cols = []table.Column{
+		{ID: "version", Name: "Version", SortIndex: 1},
+		{ID: "description", Name: "Pending Migration", SortIndex: 2},
+	}
+	t.SetCols(cols)
+
+	for _, migration := range migrations {
+		row := []interface{}{
+			termenv.String(strconv.Itoa(migration.Version)).Foreground(theme.ColorCyan),
+			termenv.String(migration.Description).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
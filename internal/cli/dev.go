@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"io"
 	"os"
 	"os/signal"
@@ -11,7 +12,9 @@ import (
 	"github.com/colonyos/colonies/pkg/client"
 	"github.com/colonyos/colonies/pkg/cluster"
 	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
 	"github.com/colonyos/colonies/pkg/database/postgresql"
+	"github.com/colonyos/colonies/pkg/database/sqlite"
 	"github.com/colonyos/colonies/pkg/monitoring"
 	"github.com/colonyos/colonies/pkg/server"
 	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
@@ -44,41 +47,28 @@ var devCmd = &cobra.Command{
 			CheckError(err)
 		}
 
-		err = os.Mkdir(coloniesPath+"embedded-postgres-go", 0700)
-		CheckError(err)
-		err = os.Mkdir(coloniesPath+"embedded-postgres-go/extracted", 0700)
-		CheckError(err)
-		err = os.Mkdir(coloniesPath+"embedded-postgres-go/extracted/data", 0700)
-		CheckError(err)
-
-		log.WithFields(log.Fields{"DBHost": DBHost, "DBPort": DBPort, "DBUser": DBUser, "DBPassword": DBPassword, "DBName": DBName}).Info("Starting embedded PostgreSQL server")
-		postgres := embeddedpostgres.NewDatabase(embeddedpostgres.DefaultConfig().
-			RuntimePath(coloniesPath + "embedded-postgres-go/extracted").
-			BinariesPath(coloniesPath + "embedded-postgres-go/extracted").
-			DataPath(coloniesPath + "embedded-postgres-go/extracted/data").
-			Username(DBUser).
-			Version(embeddedpostgres.V12).
-			Password(DBPassword).
-			Port(50070))
-		defer postgres.Stop()
-		err = postgres.Start()
-		CheckError(err)
-
-		c := make(chan os.Signal)
-		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-		go func() {
-			<-c
-			postgres.Stop()
-			log.Info("Colonies development server stopped")
-			os.Exit(0)
-		}()
-
-		log.WithFields(log.Fields{"DBHost": DBHost, "DBPort": DBPort, "DBUser": DBUser, "DBPassword": DBPassword, "DBName": DBName}).Info("Connecting to PostgreSQL server")
-		coloniesDB := postgresql.CreatePQDatabase(DBHost, DBPort, DBUser, DBPassword, DBName, DBPrefix, false)
-		err = coloniesDB.Connect()
-		CheckError(err)
+		var coloniesDB database.Database
+		if DBType == DBTypeSQLite {
+			DBPath = coloniesPath + "colonies.db"
+			log.WithFields(log.Fields{"DBPath": DBPath}).Info("Connecting to SQLite database")
+			sqliteDB := sqlite.CreateSQLiteDatabase(DBPath, DBPrefix)
+			err = sqliteDB.Connect()
+			CheckError(err)
+			coloniesDB = sqliteDB
+
+			c := make(chan os.Signal, 1)
+			signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+			go func() {
+				<-c
+				sqliteDB.Close()
+				log.Info("Colonies development server stopped")
+				os.Exit(0)
+			}()
+		} else {
+			coloniesDB = startEmbeddedPostgres(coloniesPath)
+		}
 
-		log.Info("Initialize a Colonies PostgreSQL database")
+		log.Info("Initialize a Colonies database")
 		err = coloniesDB.Initialize()
 		CheckError(err)
 
@@ -98,6 +88,8 @@ var devCmd = &cobra.Command{
 		retentionPeriod := 60000 // Run retention worker once a minute
 
 		setupProfiler()
+		shutdownTracing := setupTracing()
+		defer shutdownTracing(context.Background())
 
 		serverIdentity, err := crypto.CreateIdendityFromString(ServerPrvKey)
 		CheckError(err)
@@ -121,6 +113,10 @@ var devCmd = &cobra.Command{
 			retentionPeriod,
 			UnprivilegedExecutors)
 
+		coloniesServer.SetAnomalyFactor(AnomalyFactor)
+		coloniesServer.SetStatisticsPeriod(time.Duration(StatisticsPeriod) * time.Second)
+		setColonyLimits(coloniesServer)
+
 		go coloniesServer.ServeForever()
 
 		log.WithFields(log.Fields{"ServerHost": ServerHost, "ServerPort": ServerPort}).Info("Connecting to Colonies server")
@@ -165,3 +161,40 @@ var devCmd = &cobra.Command{
 		<-wait
 	},
 }
+
+func startEmbeddedPostgres(coloniesPath string) *postgresql.PQDatabase {
+	err := os.Mkdir(coloniesPath+"embedded-postgres-go", 0700)
+	CheckError(err)
+	err = os.Mkdir(coloniesPath+"embedded-postgres-go/extracted", 0700)
+	CheckError(err)
+	err = os.Mkdir(coloniesPath+"embedded-postgres-go/extracted/data", 0700)
+	CheckError(err)
+
+	log.WithFields(log.Fields{"DBHost": DBHost, "DBPort": DBPort, "DBUser": DBUser, "DBPassword": DBPassword, "DBName": DBName}).Info("Starting embedded PostgreSQL server")
+	postgres := embeddedpostgres.NewDatabase(embeddedpostgres.DefaultConfig().
+		RuntimePath(coloniesPath + "embedded-postgres-go/extracted").
+		BinariesPath(coloniesPath + "embedded-postgres-go/extracted").
+		DataPath(coloniesPath + "embedded-postgres-go/extracted/data").
+		Username(DBUser).
+		Version(embeddedpostgres.V12).
+		Password(DBPassword).
+		Port(50070))
+	err = postgres.Start()
+	CheckError(err)
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		postgres.Stop()
+		log.Info("Colonies development server stopped")
+		os.Exit(0)
+	}()
+
+	log.WithFields(log.Fields{"DBHost": DBHost, "DBPort": DBPort, "DBUser": DBUser, "DBPassword": DBPassword, "DBName": DBName}).Info("Connecting to PostgreSQL server")
+	coloniesDB := postgresql.CreatePQDatabase(DBHost, DBPort, DBUser, DBPassword, DBName, DBPrefix, false)
+	err = coloniesDB.Connect()
+	CheckError(err)
+
+	return coloniesDB
+}
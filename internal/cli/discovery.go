@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/p2p/dht"
+	"github.com/colonyos/colonies/pkg/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const discoveryTimeout = 10 * time.Second
+
+// Servers of the colony resolved in the DHT, the client fails over to the other servers when a server is draining
+var resolvedEndpoints []*core.ServerEndpoint
+
+// joinDHT creates a DHT node and registers it with the bootstrap node, port 0 picks a free port. Values stored by the
+// node are persisted in dataDir, unless dataDir is empty.
+func joinDHT(port int, name string, bootstrapAddr string, dataDir string) (dht.DHT, error) {
+	storagePath := ""
+	if dataDir != "" {
+		storagePath = filepath.Join(dataDir, "dht.log")
+	}
+
+	d, err := dht.CreateDHTWithStorage(port, name, storagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if bootstrapAddr == "" {
+		return d, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	contact := d.GetContact()
+	err = d.RegisterNetworkWithAddr(bootstrapAddr, contact.ID.String(), ctx)
+	if err != nil {
+		d.Shutdown()
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// resolveColony looks up the servers of a colony in the DHT, the bootstrap address and server ID are taken from
+// COLONIES_DHT_BOOTSTRAP and COLONIES_SERVER_ID
+func resolveColony(colonyName string) ([]*core.ServerEndpoint, error) {
+	if DHTBootstrap == "" {
+		return nil, errors.New("COLONIES_DHT_BOOTSTRAP not set")
+	}
+
+	if ServerID == "" {
+		return nil, errors.New("COLONIES_SERVER_ID not set")
+	}
+
+	d, err := joinDHT(0, "client", DHTBootstrap, "")
+	if err != nil {
+		return nil, err
+	}
+	defer d.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	return client.ResolveColony(d, ServerID, colonyName, ctx)
+}
+
+// resolveServer sets ServerHost and ServerPort to the most recently published server of the colony
+func resolveServer() {
+	endpoints, err := resolveColony(ColonyName)
+	CheckError(err)
+
+	resolvedEndpoints = endpoints
+
+	endpoint := endpoints[0]
+	ServerHost = endpoint.Host
+	ServerPort = endpoint.Port
+	Insecure = !endpoint.TLS
+
+	log.WithFields(log.Fields{"ColonyName": ColonyName, "Server": endpoint.Name, "ServerHost": ServerHost, "ServerPort": ServerPort}).Debug("Resolved server in DHT")
+}
+
+// publishServer joins the DHT and makes the server publish its endpoint and colonies, a server that fails to join
+// the DHT still serves requests
+func publishServer(s *server.ColoniesServer) {
+	d, err := joinDHT(DHTPort, EtcdName, DHTBootstrap, DHTDataDir)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "DHTPort": DHTPort, "DHTBootstrap": DHTBootstrap}).Warn("Failed to join DHT, server endpoint will not be published")
+		return
+	}
+
+	log.WithFields(log.Fields{"Addr": d.GetContact().Node.Addr}).Info("Joined DHT, other servers and clients can use this address as --dhtbootstrap")
+
+	host := PublicHost
+	if host == "" {
+		host = EtcdHost
+	}
+
+	err = s.SetDiscovery(d, ServerPrvKey, core.CreateServerEndpoint(EtcdName, host, ServerPort, UseTLS))
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Warn("Failed to enable DHT discovery, make sure COLONIES_SERVER_PRVKEY is set")
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	federationCmd.AddCommand(addFederationPeerCmd)
+	federationCmd.AddCommand(removeFederationPeerCmd)
+	federationCmd.AddCommand(listFederationPeersCmd)
+	rootCmd.AddCommand(federationCmd)
+
+	federationCmd.PersistentFlags().StringVarP(&ServerHost, "host", "", "localhost", "Server host")
+	federationCmd.PersistentFlags().IntVarP(&ServerPort, "port", "", -1, "Server HTTP port")
+
+	addFederationPeerCmd.Flags().StringVarP(&PeerName, "name", "", "", "Federation peer name")
+	addFederationPeerCmd.MarkFlagRequired("name")
+	addFederationPeerCmd.Flags().StringVarP(&TargetServerID, "serverid", "", "", "Server Id of the peer server")
+	addFederationPeerCmd.MarkFlagRequired("serverid")
+	addFederationPeerCmd.Flags().StringVarP(&PeerHost, "peerhost", "", "", "Host name of the peer server")
+	addFederationPeerCmd.MarkFlagRequired("peerhost")
+	addFederationPeerCmd.Flags().IntVarP(&PeerPort, "peerport", "", 443, "HTTP port of the peer server")
+	addFederationPeerCmd.Flags().BoolVarP(&PeerTLS, "peertls", "", true, "Use TLS when connecting to the peer server")
+	addFederationPeerCmd.Flags().StringVarP(&TargetColonyName, "remotecolony", "", "", "Name of the colony on the peer server processes are forwarded to")
+	addFederationPeerCmd.MarkFlagRequired("remotecolony")
+	addFederationPeerCmd.Flags().StringSliceVarP(&PeerExecutorTypes, "executortypes", "", make([]string, 0), "Executor types of processes forwarded to the peer, e.g. --executortypes gpu,hpc")
+	addFederationPeerCmd.Flags().StringSliceVarP(&PeerLabels, "labels", "", make([]string, 0), "Only forward processes with one of these labels")
+
+	removeFederationPeerCmd.Flags().StringVarP(&PeerName, "name", "", "", "Federation peer name")
+	removeFederationPeerCmd.MarkFlagRequired("name")
+
+	listFederationPeersCmd.Flags().BoolVarP(&JSON, "json", "", false, "Print JSON instead of tables")
+}
+
+var federationCmd = &cobra.Command{
+	Use:   "federation",
+	Short: "Manage federation peers",
+	Long:  "Manage federation peers",
+}
+
+var addFederationPeerCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a federation peer, waiting processes of the given executor types are forwarded to the peer",
+	Long:  "Add a federation peer, waiting processes of the given executor types are forwarded to the peer",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		peer := core.CreateFederationPeer(ColonyName, PeerName, TargetServerID, PeerHost, PeerPort, PeerTLS, TargetColonyName, PeerExecutorTypes, PeerLabels)
+		addedPeer, err := client.AddFederationPeer(peer, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"FederationPeerID": addedPeer.ID, "Name": addedPeer.Name, "ServerID": addedPeer.ServerID, "RemoteColonyName": addedPeer.RemoteColonyName}).Info("Federation peer added")
+	},
+}
+
+var removeFederationPeerCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove a federation peer",
+	Long:  "Remove a federation peer",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		if PeerName == "" {
+			CheckError(errors.New("Federation peer name not specified"))
+		}
+
+		err := client.RemoveFederationPeer(ColonyName, PeerName, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"Name": PeerName}).Info("Federation peer removed")
+	},
+}
+
+var listFederationPeersCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List all federation peers",
+	Long:  "List all federation peers",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		peers, err := client.GetFederationPeers(ColonyName, PrvKey)
+		CheckError(err)
+
+		if JSON {
+			jsonString, err := core.ConvertFederationPeerArrayToJSON(peers)
+			CheckError(err)
+			fmt.Println(jsonString)
+			os.Exit(0)
+		}
+
+		if len(peers) == 0 {
+			log.WithFields(log.Fields{"ColonyName": ColonyName}).Info("No federation peers found")
+			os.Exit(0)
+		}
+
+		printFederationPeersTable(peers)
+	},
+}
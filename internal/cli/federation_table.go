@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/colonyos/colonies/internal/table"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/muesli/termenv"
+)
+
+func printFederationPeersTable(peers []*core.FederationPeer) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "name", Name: "Name", SortIndex: 1},
+		{ID: "endpoint", Name: "Endpoint", SortIndex: 2},
+		{ID: "remotecolony", Name: "RemoteColony", SortIndex: 3},
+		{ID: "executortypes", Name: "ExecutorTypes", SortIndex: 4},
+		{ID: "labels", Name: "Labels", SortIndex: 5},
+	}
+	t.SetCols(cols)
+
+	for _, peer := range peers {
+		scheme := "http://"
+		if peer.TLS {
+			scheme = "https://"
+		}
+		labels := strings.Join(peer.Labels, ",")
+		if labels == "" {
+			labels = "*"
+		}
+		row := []interface{}{
+			termenv.String(peer.Name).Foreground(theme.ColorCyan),
+			termenv.String(scheme + peer.Host + ":" + strconv.Itoa(peer.Port)).Foreground(theme.ColorViolet),
+			termenv.String(peer.RemoteColonyName).Foreground(theme.ColorBlue),
+			termenv.String(strings.Join(peer.ExecutorTypes, ",")).Foreground(theme.ColorMagenta),
+			termenv.String(labels).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
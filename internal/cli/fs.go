@@ -34,6 +34,8 @@ func init() {
 	fsCmd.AddCommand(getFileInfoCmd)
 	fsCmd.AddCommand(getFileCmd)
 	fsCmd.AddCommand(removeFileCmd)
+	fsCmd.AddCommand(listVersionsCmd)
+	fsCmd.AddCommand(pruneCmd)
 	fsCmd.AddCommand(snapshotCmd)
 	rootCmd.AddCommand(fsCmd)
 
@@ -43,8 +45,11 @@ func init() {
 	syncCmd.Flags().BoolVarP(&Dry, "dry", "", false, "Dry run")
 	syncCmd.Flags().BoolVarP(&Yes, "yes", "", false, "Anser yes to all questions")
 	syncCmd.Flags().BoolVarP(&KeepLocal, "keeplocal", "", true, "Keep local files in case of conflicts")
+	syncCmd.Flags().StringVarP(&ConflictStrategy, "conflict", "", "", "Conflict strategy, keeplocal, keepremote, keepboth, newestwins or fail, overrides --keeplocal")
 	syncCmd.Flags().BoolVarP(&SyncPlans, "syncplans", "", false, "Print sync plans details")
 	syncCmd.Flags().BoolVarP(&Quite, "quite", "", false, "No outputs")
+	syncCmd.Flags().StringVarP(&AsOf, "as-of", "", "", "Sync directory to the state it had at a point in time, e.g. \"2006-01-02 15:04:05\"")
+	syncCmd.Flags().IntVarP(&MaxVersions, "maxversions", "", 0, "Max number of revisions to keep of each file, 0 keeps all revisions")
 
 	cleanCmd.Flags().StringVarP(&SyncDir, "dir", "d", "", "Local directory to clean")
 	cleanCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
@@ -63,6 +68,15 @@ func init() {
 	getFileCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
 	getFileCmd.Flags().StringVarP(&Filename, "name", "n", "", "Filename")
 	getFileCmd.Flags().StringVarP(&DownloadDir, "dir", "d", "", "Local directory to download file to")
+	getFileCmd.Flags().StringVarP(&AsOf, "as-of", "", "", "Download the revision that was latest at a point in time, e.g. \"2006-01-02 15:04:05\"")
+
+	listVersionsCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
+	listVersionsCmd.Flags().StringVarP(&Filename, "name", "n", "", "Filename")
+	listVersionsCmd.MarkFlagRequired("name")
+
+	pruneCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
+	pruneCmd.Flags().IntVarP(&MaxVersions, "maxversions", "", 0, "Max number of revisions to keep of each file")
+	pruneCmd.MarkFlagRequired("maxversions")
 
 	removeFileCmd.Flags().StringVarP(&FileID, "fileid", "i", "", "File Id")
 	removeFileCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
@@ -104,22 +118,19 @@ var snapshotCmd = &cobra.Command{
 func printSyncPlans(syncPlans []*fs.SyncPlan) {
 	filesToDownload := 0
 	filesToUpload := 0
+	filesToRemove := 0
 	conflicts := 0
 
 	for _, syncPlan := range syncPlans {
 		filesToDownload += len(syncPlan.LocalMissing)
 		filesToUpload += len(syncPlan.RemoteMissing)
+		filesToRemove += len(syncPlan.LocalRemove) + len(syncPlan.RemoteRemove)
 		conflicts += len(syncPlan.Conflicts)
 	}
 
-	var conflictResolution string
-	if KeepLocal {
-		conflictResolution = "replace-remote"
-	} else {
-		conflictResolution = "replace-local"
-	}
+	conflictResolution := core.ResolveConflictStrategy(ConflictStrategy, KeepLocal)
 
-	log.WithFields(log.Fields{"Conflict resolution": conflictResolution, "Download": filesToDownload, "Upload": filesToUpload, "Conflicts": conflicts}).Info("Sync plans completed")
+	log.WithFields(log.Fields{"Conflict resolution": conflictResolution, "Download": filesToDownload, "Upload": filesToUpload, "Remove": filesToRemove, "Conflicts": conflicts}).Info("Sync plans completed")
 
 	if SyncPlans {
 		printSyncPlansDetails(syncPlans)
@@ -170,8 +181,8 @@ var syncCmd = &cobra.Command{
 			SyncDir = currentDir
 		}
 
+		cfsFile := getCFSFile(SyncDir)
 		if Label == "" {
-			cfsFile := getCFSFile(SyncDir)
 			if cfsFile.Label == "" {
 				CheckError(errors.New("No label found in .cfs file, use --label flag"))
 			} else {
@@ -179,6 +190,10 @@ var syncCmd = &cobra.Command{
 			}
 		}
 
+		if !cmd.Flags().Changed("maxversions") {
+			MaxVersions = cfsFile.MaxVersions
+		}
+
 		Label = strings.TrimRight(Label, "/")
 		Label = strings.TrimLeft(Label, "/")
 		Label = "/" + Label
@@ -194,6 +209,24 @@ var syncCmd = &cobra.Command{
 			fsClient.Quiet = true
 		}
 
+		fsClient.MaxVersions = MaxVersions
+
+		if ConflictStrategy != "" && !core.IsValidConflictStrategy(ConflictStrategy) {
+			CheckError(errors.New("Invalid conflict strategy <" + ConflictStrategy + ">, must be keeplocal, keepremote, keepboth, newestwins or fail"))
+		}
+		fsClient.ConflictStrategy = ConflictStrategy
+
+		if AsOf != "" {
+			asOf, err := parseAsOf(AsOf)
+			CheckError(err)
+			fsClient.AsOf = asOf
+			KeepLocal = false
+			ConflictStrategy = core.ConflictStrategyKeepRemote
+			if !Quite {
+				log.WithFields(log.Fields{"AsOf": asOf.Format(TimeLayout)}).Info("Restoring directory to a past state, local changes will be replaced")
+			}
+		}
+
 		if !Quite {
 			log.Info("Calculating sync plans")
 		}
@@ -203,7 +236,7 @@ var syncCmd = &cobra.Command{
 
 		counter := 0
 		for _, syncPlan := range syncPlans {
-			if len(syncPlan.LocalMissing) == 0 && len(syncPlan.RemoteMissing) == 0 && len(syncPlan.Conflicts) == 0 {
+			if len(syncPlan.LocalMissing) == 0 && len(syncPlan.RemoteMissing) == 0 && len(syncPlan.LocalRemove) == 0 && len(syncPlan.RemoteRemove) == 0 && len(syncPlan.Conflicts) == 0 {
 				counter++
 			}
 		}
@@ -499,8 +532,12 @@ var getFileCmd = &cobra.Command{
 		if FileID != "" {
 			coloniesFiles, err = client.GetFileByID(ColonyName, FileID, PrvKey)
 			CheckError(err)
+		} else if Filename != "" && Label != "" && AsOf != "" {
+			asOf, err := parseAsOf(AsOf)
+			CheckError(err)
+			coloniesFiles, err = client.GetFileByNameAsOf(ColonyName, Label, Filename, asOf, PrvKey)
+			CheckError(err)
 		} else if Filename != "" && Label != "" {
-			fmt.Println(Label)
 			coloniesFiles, err = client.GetLatestFileByName(ColonyName, Label, Filename, PrvKey)
 			CheckError(err)
 		} else {
@@ -552,6 +589,81 @@ var removeFileCmd = &cobra.Command{
 	},
 }
 
+func parseAsOf(asOfStr string) (time.Time, error) {
+	asOf, err := time.Parse(time.RFC3339, asOfStr)
+	if err == nil {
+		return asOf, nil
+	}
+
+	asOf, err = time.ParseInLocation(TimeLayout, asOfStr, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("Failed to parse --as-of, use format \"" + TimeLayout + "\" or RFC3339")
+	}
+
+	return asOf, nil
+}
+
+var listVersionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "List all revisions of a file",
+	Long:  "List all revisions of a file",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		currentDir, err := os.Getwd()
+		CheckError(err)
+
+		if Label == "" {
+			cfsFile := getCFSFile(currentDir)
+			if cfsFile.Label == "" {
+				CheckError(errors.New("No label found in .cfs file, use --label flag"))
+			} else {
+				Label = cfsFile.Label
+			}
+		}
+
+		revisions, err := client.GetFileByName(ColonyName, Label, Filename, PrvKey)
+		CheckError(err)
+
+		if len(revisions) == 0 {
+			log.Info("No revisions found")
+			os.Exit(0)
+		}
+
+		printFileVersionsTable(revisions)
+	},
+}
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old file revisions from file storage",
+	Long:  "Remove old file revisions from file storage, revisions part of a snapshot are kept",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		currentDir, err := os.Getwd()
+		CheckError(err)
+
+		if Label == "" {
+			cfsFile := getCFSFile(currentDir)
+			if cfsFile.Label == "" {
+				CheckError(errors.New("No label found in .cfs file, use --label flag"))
+			} else {
+				Label = cfsFile.Label
+			}
+		}
+
+		log.Debug("Starting a file storage client")
+		fsClient, err := fs.CreateFSClient(client, ColonyName, PrvKey)
+		CheckError(err)
+
+		pruned, err := fsClient.PruneRevisions(Label, MaxVersions)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"Label": Label, "MaxVersions": MaxVersions, "Removed": len(pruned)}).Info("Pruned file revisions")
+	},
+}
+
 var createSnapshotCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a snapshot",
@@ -26,13 +26,22 @@ func printSyncPlansDetails(syncPlans []*fs.SyncPlan) {
 	localMissingTable.SetTitle("These files will be downloaded to " + SyncDir)
 	localMissingTable.SetCols(cols)
 
+	localRemoveTable, theme := createTable(3)
+	localRemoveTable.SetTitle("These files have been removed remotely and will be removed from " + SyncDir)
+	localRemoveTable.SetCols(cols)
+
+	remoteRemoveTable, theme := createTable(3)
+	remoteRemoveTable.SetTitle("These files have been removed locally and will be removed remotely")
+	remoteRemoveTable.SetCols(cols)
+
 	conflictTable, theme := createTable(3)
-	if KeepLocal {
-		conflictTable.SetTitle("These files will be replaced at the server")
-	} else {
-		conflictTable.SetTitle("These files will be replaced locally")
-	}
-	conflictTable.SetCols(cols)
+	conflictTable.SetTitle("These files have conflicting changes")
+	conflictTable.SetCols([]table.Column{
+		{ID: "file", Name: "File", SortIndex: 1},
+		{ID: "size", Name: "Size", SortIndex: 2},
+		{ID: "label", Name: "Label", SortIndex: 3},
+		{ID: "resolution", Name: "Resolution", SortIndex: 4},
+	})
 
 	for _, syncPlan := range syncPlans {
 		if len(syncPlan.RemoteMissing) > 0 {
@@ -57,12 +66,31 @@ func printSyncPlansDetails(syncPlans []*fs.SyncPlan) {
 			}
 		}
 
+		for _, file := range syncPlan.LocalRemove {
+			row := []interface{}{
+				termenv.String(file.Name).Foreground(theme.ColorRed),
+				termenv.String(strconv.FormatInt(file.Size/1024, 10) + " KiB").Foreground(theme.ColorCyan),
+				termenv.String(syncPlan.Label).Foreground(theme.ColorViolet),
+			}
+			localRemoveTable.AddRow(row)
+		}
+
+		for _, file := range syncPlan.RemoteRemove {
+			row := []interface{}{
+				termenv.String(file.Name).Foreground(theme.ColorRed),
+				termenv.String(strconv.FormatInt(file.Size/1024, 10) + " KiB").Foreground(theme.ColorCyan),
+				termenv.String(syncPlan.Label).Foreground(theme.ColorViolet),
+			}
+			remoteRemoveTable.AddRow(row)
+		}
+
 		if len(syncPlan.Conflicts) > 0 {
 			for _, file := range syncPlan.Conflicts {
 				row := []interface{}{
 					termenv.String(file.Name).Foreground(theme.ColorBlue),
 					termenv.String(strconv.FormatInt(file.Size/1024, 10) + " KiB").Foreground(theme.ColorCyan),
 					termenv.String(syncPlan.Label).Foreground(theme.ColorViolet),
+					termenv.String(conflictResolutionText(file)).Foreground(theme.ColorYellow),
 				}
 				conflictTable.AddRow(row)
 			}
@@ -70,9 +98,30 @@ func printSyncPlansDetails(syncPlans []*fs.SyncPlan) {
 	}
 	remoteMissingTable.Render()
 	localMissingTable.Render()
+	localRemoveTable.Render()
+	remoteRemoveTable.Render()
 	conflictTable.Render()
 }
 
+func conflictResolutionText(file *fs.FileInfo) string {
+	switch file.Resolution {
+	case core.ConflictStrategyKeepLocal:
+		if file.Remove {
+			return "remove remote"
+		}
+		return "replace remote"
+	case core.ConflictStrategyKeepRemote:
+		if file.Remove {
+			return "remove local"
+		}
+		return "replace local"
+	case core.ConflictStrategyKeepBoth:
+		return "keep local as " + file.ConflictName
+	}
+
+	return file.Resolution
+}
+
 func printCleanPlansDetails(cleanPlans []*fs.CleanPlan) {
 	removeTable, theme := createTable(1)
 	removeTable.SetTitle("These files will be removed from local filesystem")
@@ -140,6 +189,34 @@ func printFilesTable(files []fileInfo) {
 	t.Render()
 }
 
+func printFileVersionsTable(revisions []*core.File) {
+	sortCol := 0
+
+	t, theme := createTable(sortCol)
+
+	var cols = []table.Column{
+		{ID: "seqnr", Name: "Sequence Number", SortIndex: 1},
+		{ID: "fileid", Name: "FileId", SortIndex: 2},
+		{ID: "size", Name: "Size", SortIndex: 3},
+		{ID: "checksum", Name: "Checksum", SortIndex: 4},
+		{ID: "added", Name: "Added", SortIndex: 5},
+	}
+	t.SetCols(cols)
+
+	for _, revision := range revisions {
+		row := []interface{}{
+			termenv.String(strconv.FormatInt(revision.SequenceNumber, 10)).Foreground(theme.ColorYellow),
+			termenv.String(revision.ID).Foreground(theme.ColorMagenta),
+			termenv.String(strconv.FormatInt(revision.Size/1024, 10) + " KiB").Foreground(theme.ColorBlue),
+			termenv.String(revision.Checksum).Foreground(theme.ColorCyan),
+			termenv.String(revision.Added.Format(TimeLayout)).Foreground(theme.ColorGreen),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
 func printFileInfoTable(file *core.File) {
 	sortCol := 0
 	t, theme := createTable(sortCol)
@@ -21,6 +21,7 @@ func init() {
 	functionCmd.AddCommand(registerFuncCmd)
 	functionCmd.AddCommand(removeFuncCmd)
 	functionCmd.AddCommand(listFuncCmd)
+	functionCmd.AddCommand(funcStatsCmd)
 
 	registerFuncCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
 	registerFuncCmd.Flags().StringVarP(&TargetExecutorName, "name", "", "", "Executor name")
@@ -56,6 +57,12 @@ func init() {
 
 	removeFuncCmd.Flags().StringVarP(&FunctionID, "functionid", "", "", "FunctionID")
 	removeFuncCmd.MarkFlagRequired("functionid")
+
+	funcStatsCmd.Flags().StringVarP(&StatsSince, "since", "", "24h", "Period back in time to calculate statistics for, e.g. 30m, 24h")
+	funcStatsCmd.Flags().StringVarP(&BucketSize, "bucket", "", "", "Also show statistics for periods of this length, e.g. 1h")
+	funcStatsCmd.Flags().StringVarP(&FuncName, "func", "", "", "Only show statistics for this function")
+	funcStatsCmd.Flags().StringVarP(&TargetExecutorType, "targettype", "", "", "Only show statistics for this executor type")
+	funcStatsCmd.Flags().BoolVarP(&JSON, "json", "", false, "Print statistics as JSON")
 }
 
 var functionCmd = &cobra.Command{
@@ -166,6 +173,47 @@ var listFuncCmd = &cobra.Command{
 	},
 }
 
+var funcStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show p50/p95/p99 execution times, failure rate and throughput of Functions",
+	Long:  "Show p50/p95/p99 execution times, failure rate and throughput of Functions",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		since, err := time.ParseDuration(StatsSince)
+		CheckError(err)
+
+		var bucketSize time.Duration
+		if BucketSize != "" {
+			bucketSize, err = time.ParseDuration(BucketSize)
+			CheckError(err)
+		}
+
+		stats, err := client.GetFunctionStats(ColonyName, FuncName, TargetExecutorType, int(since.Seconds()), int(bucketSize.Seconds()), PrvKey)
+		CheckError(err)
+
+		if JSON {
+			jsonStr, err := core.ConvertFunctionStatsArrayToJSON(stats)
+			CheckError(err)
+			fmt.Println(jsonStr)
+			os.Exit(0)
+		}
+
+		if len(stats) == 0 {
+			log.WithFields(log.Fields{"ColonyName": ColonyName, "Since": StatsSince}).Info("No function runs found")
+			os.Exit(0)
+		}
+
+		printFunctionStatsTable(stats)
+		if bucketSize > 0 {
+			for _, s := range stats {
+				fmt.Println()
+				printFunctionStatsBucketsTable(s)
+			}
+		}
+	},
+}
+
 func follow(client *client.ColoniesClient, process *core.Process) {
 	log.WithFields(log.Fields{"ProcessId": process.ID}).Info("Printing logs from process")
 	var lastTimestamp int64
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/colonyos/colonies/internal/table"
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/muesli/termenv"
 )
 
@@ -69,3 +71,82 @@ func printFunctionTable(statMap map[string]statsEntry) {
 		t.Render()
 	}
 }
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f s", seconds)
+}
+
+func printFunctionStatsTable(stats []*core.FunctionStats) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "function", Name: "Function", SortIndex: 1},
+		{ID: "executortype", Name: "ExecutorType", SortIndex: 2},
+		{ID: "runs", Name: "Runs", SortIndex: 3},
+		{ID: "failurerate", Name: "FailureRate", SortIndex: 4},
+		{ID: "throughput", Name: "Runs/min", SortIndex: 5},
+		{ID: "waitp95", Name: "WaitP95", SortIndex: 6},
+		{ID: "execp50", Name: "ExecP50", SortIndex: 7},
+		{ID: "execp95", Name: "ExecP95", SortIndex: 8},
+		{ID: "execp99", Name: "ExecP99", SortIndex: 9},
+		{ID: "anomalies", Name: "Anomalies", SortIndex: 10},
+	}
+	t.SetCols(cols)
+
+	for _, s := range stats {
+		anomalies := termenv.String(strconv.Itoa(s.Anomalies)).Foreground(theme.ColorGray)
+		if s.Anomalies > 0 {
+			anomalies = termenv.String(strconv.Itoa(s.Anomalies)).Foreground(theme.ColorRed)
+		}
+		row := []interface{}{
+			termenv.String(s.FuncName).Foreground(theme.ColorCyan),
+			termenv.String(s.ExecutorType).Foreground(theme.ColorViolet),
+			termenv.String(strconv.Itoa(s.Runs)).Foreground(theme.ColorGray),
+			termenv.String(fmt.Sprintf("%.1f %%", s.FailureRate*100)).Foreground(theme.ColorGray),
+			termenv.String(fmt.Sprintf("%.2f", s.Throughput)).Foreground(theme.ColorGray),
+			termenv.String(formatSeconds(s.WaitTimeP95)).Foreground(theme.ColorGray),
+			termenv.String(formatSeconds(s.ExecTimeP50)).Foreground(theme.ColorGray),
+			termenv.String(formatSeconds(s.ExecTimeP95)).Foreground(theme.ColorGray),
+			termenv.String(formatSeconds(s.ExecTimeP99)).Foreground(theme.ColorGray),
+			anomalies,
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
+func printFunctionStatsBucketsTable(stats *core.FunctionStats) {
+	t, theme := createTable(0)
+	t.SetTitle("Function: " + stats.FuncName + " (" + stats.ExecutorType + ")")
+
+	var cols = []table.Column{
+		{ID: "start", Name: "Start", SortIndex: 1},
+		{ID: "runs", Name: "Runs", SortIndex: 2},
+		{ID: "failed", Name: "Failed", SortIndex: 3},
+		{ID: "execp50", Name: "ExecP50", SortIndex: 4},
+		{ID: "execp95", Name: "ExecP95", SortIndex: 5},
+		{ID: "execp99", Name: "ExecP99", SortIndex: 6},
+		{ID: "anomalies", Name: "Anomalies", SortIndex: 7},
+	}
+	t.SetCols(cols)
+
+	for _, b := range stats.Buckets {
+		anomalies := termenv.String(strconv.Itoa(b.Anomalies)).Foreground(theme.ColorGray)
+		if b.Anomalies > 0 {
+			anomalies = termenv.String(strconv.Itoa(b.Anomalies)).Foreground(theme.ColorRed)
+		}
+		row := []interface{}{
+			termenv.String(b.Start.Local().Format(TimeLayout)).Foreground(theme.ColorGray),
+			termenv.String(strconv.Itoa(b.Runs)).Foreground(theme.ColorGray),
+			termenv.String(strconv.Itoa(b.Failed)).Foreground(theme.ColorGray),
+			termenv.String(formatSeconds(b.ExecTimeP50)).Foreground(theme.ColorGray),
+			termenv.String(formatSeconds(b.ExecTimeP95)).Foreground(theme.ColorGray),
+			termenv.String(formatSeconds(b.ExecTimeP99)).Foreground(theme.ColorGray),
+			anomalies,
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
@@ -1,8 +1,10 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/colonyos/colonies/internal/table"
@@ -23,20 +25,40 @@ func init() {
 	addLogCmd.MarkFlagRequired("processid")
 	addLogCmd.Flags().StringVarP(&LogMsg, "msg", "m", "", "Message")
 	addLogCmd.MarkFlagRequired("msg")
+	addLogCmd.Flags().StringVarP(&LogLevel, "level", "", core.LogLevelInfo, "Log level (debug, info, warn or error)")
+	addLogCmd.Flags().StringVarP(&LogStream, "stream", "", "", "Log stream (stdout or stderr)")
+	addLogCmd.Flags().StringSliceVarP(&LogFields, "field", "", make([]string, 0), "Structured field, e.g. --field key1=value1,key2=value2")
 
 	getLogsCmd.Flags().StringVarP(&ProcessID, "processid", "p", "", "Process Id")
 	getLogsCmd.Flags().StringVarP(&TargetExecutorName, "executorname", "e", "", "Executor name")
 	getLogsCmd.Flags().Int64VarP(&Since, "since", "", 0, "Fetch log generated since (unix nano) time")
 	getLogsCmd.Flags().IntVarP(&Count, "count", "", 100, "Number of messages to fetch")
 	getLogsCmd.Flags().BoolVarP(&Follow, "follow", "", false, "Follow process")
+	getLogsCmd.Flags().StringVarP(&LogLevel, "level", "", "", "Only follow logs with at least this level")
+	getLogsCmd.Flags().StringSliceVarP(&LogFields, "field", "", make([]string, 0), "Only follow logs with this field, e.g. --field key1=value1")
 
 	searchLogsCmd.Flags().StringVarP(&Text, "text", "t", "", "Text to search")
+	searchLogsCmd.Flags().StringVarP(&LogLevel, "level", "", "", "Only find logs with at least this level")
+	searchLogsCmd.Flags().StringSliceVarP(&LogFields, "field", "", make([]string, 0), "Only find logs with this field, e.g. --field key1=value1")
 	searchLogsCmd.Flags().IntVarP(&Days, "days", "d", 1, "Number of days back in time to search")
 	searchLogsCmd.Flags().IntVarP(&Count, "count", "", 10, "Number of search results to fetch")
 	searchLogsCmd.Flags().BoolVarP(&Print, "print", "", false, "Print logs")
 	searchLogsCmd.Flags().IntVarP(&SecondsBack, "seconds", "", 1, "Seconds back in time to print logs before the search result occurred")
 }
 
+func parseLogFields() map[string]string {
+	fields := make(map[string]string)
+	for _, v := range LogFields {
+		s := strings.SplitN(v, "=", 2)
+		if len(s) != 2 {
+			CheckError(errors.New("Invalid key-value pair, try e.g. --field key1=value1,key2=value2"))
+		}
+		fields[s[0]] = s[1]
+	}
+
+	return fields
+}
+
 var logCmd = &cobra.Command{
 	Use:   "log",
 	Short: "Manage logging",
@@ -50,10 +72,11 @@ var addLogCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client := setup()
 
-		err := client.AddLog(ProcessID, LogMsg, PrvKey)
+		fields := parseLogFields()
+		err := client.AddStructuredLog(ProcessID, LogLevel, LogStream, LogMsg, fields, PrvKey)
 		CheckError(err)
 
-		log.WithFields(log.Fields{"ProcessID": ProcessID, "LogMsg": LogMsg}).Info("Adding log")
+		log.WithFields(log.Fields{"ProcessID": ProcessID, "LogMsg": LogMsg, "Level": LogLevel, "Stream": LogStream, "Fields": fields}).Info("Adding log")
 	},
 }
 
@@ -64,27 +87,29 @@ var getLogsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client := setup()
 
-		var err error
 		if Follow {
-			var logs []*core.Log
-			var lastTimestamp int64
-			lastTimestamp = 0
+			fields := parseLogFields()
+			// Replay logs added after the last received log whenever the subscription times out
+			lastTimestamp := Since
+			if lastTimestamp == 0 {
+				lastTimestamp = 1
+			}
 			for {
-				if TargetExecutorName == "" {
-					logs, err = client.GetLogsByProcessSince(ColonyName, ProcessID, Count, lastTimestamp, PrvKey)
-				} else {
-					logs, err = client.GetLogsByExecutorSince(ColonyName, TargetExecutorName, Count, lastTimestamp, PrvKey)
-				}
+				subscription, err := client.SubscribeLogs(ColonyName, ProcessID, TargetExecutorName, LogLevel, fields, lastTimestamp, 3600, PrvKey)
 				CheckError(err)
-				if len(logs) == 0 {
-					time.Sleep(1 * time.Second)
-					continue
-				} else {
-					for _, log := range logs {
-						fmt.Print(log.Message)
+			FollowLoop:
+				for {
+					select {
+					case l := <-subscription.LogChan:
+						fmt.Print(l.Message)
+						lastTimestamp = l.Timestamp
+					case err := <-subscription.ErrChan:
+						log.WithFields(log.Fields{"Error": err}).Debug("Log subscription closed, resubscribing")
+						break FollowLoop
 					}
-					lastTimestamp = logs[len(logs)-1].Timestamp
 				}
+				subscription.Close()
+				time.Sleep(1 * time.Second)
 			}
 		} else {
 			var err error
@@ -109,11 +134,12 @@ var searchLogsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client := setup()
 
-		if Text == "" {
-			CheckError(fmt.Errorf("--text flag is required"))
+		fields := parseLogFields()
+		if Text == "" && LogLevel == "" && len(fields) == 0 {
+			CheckError(fmt.Errorf("--text, --level or --field flag is required"))
 		}
 
-		logs, err := client.SearchLogs(ColonyName, Text, Days, Count, PrvKey)
+		logs, err := client.SearchLogsWithFilter(ColonyName, Text, LogLevel, fields, Days, Count, PrvKey)
 		CheckError(err)
 
 		if len(logs) == 0 {
@@ -151,7 +177,7 @@ var searchLogsCmd = &cobra.Command{
 			os.Exit(0)
 		}
 
-		log.WithFields(log.Fields{"Text": Text, "Days": Days, "Count": Count}).Info("Searching for logs")
+		log.WithFields(log.Fields{"Text": Text, "Level": LogLevel, "Fields": fields, "Days": Days, "Count": Count}).Info("Searching for logs")
 		printLogTable(logs)
 	},
 }
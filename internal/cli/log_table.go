@@ -29,6 +29,20 @@ func printLogTable(logs []*core.Log) {
 		}
 		t.AddRow(row)
 
+		row = []interface{}{
+			termenv.String("Level").Foreground(theme.ColorYellow),
+			termenv.String(log.Level).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+
+		if len(log.Fields) > 0 {
+			row = []interface{}{
+				termenv.String("Fields").Foreground(theme.ColorYellow),
+				termenv.String(StrMap2Str(log.Fields)).Foreground(theme.ColorGray),
+			}
+			t.AddRow(row)
+		}
+
 		row = []interface{}{
 			termenv.String("Text").Foreground(theme.ColorMagenta),
 			termenv.String(insertNewLines(strings.TrimSpace(log.Message), 64)).Foreground(theme.ColorGray),
@@ -13,6 +13,11 @@ const KEYCHAIN_PATH = ".colonies"
 const TimeLayout = "2006-01-02 15:04:05"
 const DefaultDBHost = "localhost"
 const DefaultDBPort = 5432
+const DBTypePostgreSQL = "postgresql"
+const DBTypeSQLite = "sqlite"
+const DefaultSQLitePath = "colonies.db"
+const DefaultReplicaMaxLag = 5     // s
+const MigrationLockTimeout = 60000 // ms
 const DefaultServerHost = "localhost"
 const MaxAttributeLength = 30
 const MaxArgLength = 20
@@ -27,6 +32,10 @@ var DBHost string
 var DBPort int
 var DBUser string
 var DBPassword string
+var DBType string
+var DBPath string
+var DBReadReplicas []string
+var DBReplicaMaxLag int
 var BindAddr string
 var Insecure bool
 var SkipTLSVerify bool
@@ -85,6 +94,12 @@ var EtcdPeerPort int
 var EtcdCluster []string
 var EtcdDataDir string
 var RelayPort int
+var JoinCluster bool
+var DHTPort int
+var DHTBootstrap string
+var PublicHost string
+var DHTDataDir string
+var NodeAPIPort int
 var Timeout int
 var CronID string
 var CronName string
@@ -103,6 +118,19 @@ var Successful bool
 var Failed bool
 var TimescaleDB bool
 var LogMsg string
+var LogLevel string
+var LogStream string
+var LogFields []string
+var AnomalyFactor float64
+var StatsSince string
+var BucketSize string
+var StatisticsPeriod int
+var ColonyWeights map[string]int
+var ColonyDBBudget int
+var UsageFrom string
+var UsageTo string
+var UsageGroupBy string
+var CSV bool
 var Since int64
 var Follow bool
 var SyncDir string
@@ -132,6 +160,7 @@ var SyncPlans bool
 var CleanPlans bool
 var Quite bool
 var TargetServerID string
+var DrainTimeout int
 var Text string
 var Days int
 var ASCII bool
@@ -141,6 +170,32 @@ var Snapshot bool
 var IDPath string
 var PrvKeyPath string
 var UnprivilegedExecutors bool
+var AsOf string
+var MaxVersions int
+var ConflictStrategy string
+var RetainSuccessful int64
+var RetainFailed int64
+var RetainProcessGraphs int64
+var RetainLogs int64
+var RetainFiles int64
+var Archive bool
+var WebhookID string
+var WebhookName string
+var WebhookURL string
+var WebhookSecret string
+var EventTypes []string
+var AlertName string
+var AlertType string
+var AlertThreshold float64
+var AlertFor string
+var AlertWindow string
+var PeerName string
+var PeerHost string
+var PeerPort int
+var PeerTLS bool
+var PeerExecutorTypes []string
+var PeerLabels []string
+var Federation bool
 
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "Verbose (debugging)")
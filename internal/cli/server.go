@@ -1,15 +1,18 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/colonyos/colonies/pkg/client"
 	"github.com/colonyos/colonies/pkg/cluster"
-	"github.com/colonyos/colonies/pkg/database/postgresql"
+	"github.com/colonyos/colonies/pkg/fs"
 	"github.com/colonyos/colonies/pkg/server"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -21,6 +24,7 @@ func init() {
 	serverCmd.AddCommand(serverStatusCmd)
 	serverCmd.AddCommand(serverStatisticsCmd)
 	serverCmd.AddCommand(serverAliveCmd)
+	serverCmd.AddCommand(serverDrainCmd)
 	rootCmd.AddCommand(serverCmd)
 
 	chServerIDCmd.Flags().StringVarP(&TargetServerID, "serverid", "", "", "Server Id")
@@ -40,7 +44,14 @@ func init() {
 	serverCmd.PersistentFlags().IntVarP(&RelayPort, "relayport", "", 2381, "Colonies server relay port")
 	serverCmd.PersistentFlags().StringSliceVarP(&EtcdCluster, "initial-cluster", "", make([]string, 0), "Cluster config, e.g. --etcdcluster server1=localhost:peerport:relayport:apiport,server2=localhost:peerport:relayport:apiport")
 	serverCmd.PersistentFlags().StringVarP(&EtcdDataDir, "etcddatadir", "", "", "Etcd data dir")
+	serverCmd.PersistentFlags().BoolVarP(&JoinCluster, "join", "", false, "Join an existing cluster, the node must first have been added with colonies cluster join")
 	serverCmd.PersistentFlags().BoolVarP(&InitDB, "initdb", "", false, "Initialize DB")
+	serverCmd.PersistentFlags().IntVarP(&DHTPort, "dhtport", "", 0, "DHT port, the server publishes its endpoint and colonies in the DHT if set")
+	serverCmd.PersistentFlags().StringVarP(&DHTBootstrap, "dhtbootstrap", "", "", "Address of a DHT node to join, e.g. /ip4/10.0.0.1/tcp/4001/p2p/12D3KooW...")
+	serverCmd.PersistentFlags().BoolVarP(&Federation, "federation", "", false, "Forward processes to federation peers, requires COLONIES_SERVER_PRVKEY")
+	serverCmd.PersistentFlags().StringVarP(&DHTDataDir, "dhtdatadir", "", "", "Directory where the DHT values stored by the server are persisted, values are only kept in memory if not set")
+	serverCmd.PersistentFlags().StringVarP(&PublicHost, "publichost", "", "", "Host name clients use to reach the server, published in the DHT, defaults to --etcdhost")
+	serverCmd.PersistentFlags().IntVarP(&DrainTimeout, "draintimeout", "", int(server.DEFAULT_DRAIN_TIMEOUT.Seconds()), "Max time in seconds draining the server may take before it is shut down anyway")
 
 	serverStatusCmd.PersistentFlags().StringVarP(&ServerHost, "host", "", "localhost", "Server host")
 	serverStatusCmd.PersistentFlags().IntVarP(&ServerPort, "port", "", -1, "Server HTTP port")
@@ -110,22 +121,13 @@ var serverStartCmd = &cobra.Command{
 			}
 		}
 
-		var db *postgresql.PQDatabase
-		for {
-			db = postgresql.CreatePQDatabase(DBHost, DBPort, DBUser, DBPassword, DBName, DBPrefix, TimescaleDB)
-			err := db.Connect()
-			if err != nil {
-				log.WithFields(log.Fields{"Error": err}).Error("Failed to connect to PostgreSQL database")
-				time.Sleep(1 * time.Second)
-			} else {
-				break
-			}
+		db := connectDB()
+		if !InitDB {
+			migrateDB(db)
 		}
 
-		log.WithFields(log.Fields{"DBHost": DBHost, "DBPort": DBPort, "DBUser": DBUser, "DBPassword": "*******************", "DBName": DBName, "UseTLS": UseTLS, "TimescaleDB": TimescaleDB}).Info("Connected to PostgreSQL database")
-
 		node := cluster.Node{Name: EtcdName, Host: EtcdHost, APIPort: ServerPort, EtcdClientPort: EtcdClientPort, EtcdPeerPort: EtcdPeerPort, RelayPort: RelayPort}
-		clusterConfig := cluster.Config{}
+		clusterConfig := cluster.Config{Join: JoinCluster}
 
 		if len(EtcdCluster) > 0 {
 			// Parse EtcdCluster flag
@@ -165,6 +167,8 @@ var serverStartCmd = &cobra.Command{
 		retentionPeriod := 60000 // Run retention worker once a minute
 
 		setupProfiler()
+		shutdownTracing := setupTracing()
+		defer shutdownTracing(context.Background())
 
 		server := server.CreateColoniesServer(db,
 			ServerPort,
@@ -183,6 +187,20 @@ var serverStartCmd = &cobra.Command{
 			retentionPeriod,
 			UnprivilegedExecutors)
 
+		server.SetAnomalyFactor(AnomalyFactor)
+		server.SetStatisticsPeriod(time.Duration(StatisticsPeriod) * time.Second)
+		setColonyLimits(server)
+
+		// Colony retention policies need object storage to archive rows and remove expired file revisions
+		if os.Getenv("AWS_S3_ENDPOINT") != "" {
+			retentionStore, err := fs.CreateRetentionStore(db)
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err}).Warn("Failed to create retention store, archiving is disabled")
+			} else {
+				server.SetFileStore(retentionStore)
+			}
+		}
+
 		if InitDB {
 			err := db.Initialize()
 			if err != nil {
@@ -195,13 +213,57 @@ var serverStartCmd = &cobra.Command{
 			}
 		}
 
-		for {
-			err := server.ServeForever()
+		if DHTPort > 0 {
+			publishServer(server)
+		}
+
+		if Federation {
+			err := server.SetFederation(ServerPrvKey, SkipTLSVerify)
 			if err != nil {
-				log.WithFields(log.Fields{"Error": err}).Error("Failed to start Colonies Server")
-				time.Sleep(1 * time.Second)
+				log.WithFields(log.Fields{"Error": err}).Warn("Failed to enable federation, processes are not forwarded to federation peers")
+			} else {
+				log.Info("Federation enabled, forwarding processes to federation peers")
 			}
 		}
+
+		go func() {
+			for !server.IsDraining() {
+				err := server.ServeForever()
+				if err != nil && !server.IsDraining() {
+					log.WithFields(log.Fields{"Error": err}).Error("Failed to start Colonies Server")
+					time.Sleep(1 * time.Second)
+				}
+			}
+		}()
+
+		// SIGTERM, e.g. sent by Kubernetes or systemd, drains the server so that clients can reconnect to other nodes,
+		// a second signal exits immediately
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+		select {
+		case sig := <-signals:
+			log.WithFields(log.Fields{"Signal": sig}).Info("Received signal, draining Colonies server, send the signal again to exit immediately")
+			signal.Reset(os.Interrupt, syscall.SIGTERM)
+			server.Drain(time.Duration(DrainTimeout) * time.Second)
+		case <-server.Drained():
+		}
+
+		log.Info("Colonies server stopped")
+	},
+}
+
+var serverDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Drain a server before it is upgraded or removed",
+	Long:  "Drain a server before it is upgraded or removed, executors and subscribers are told to reconnect to another server, in-flight requests are completed and leader-only jobs are handed off to other nodes, then the server exits",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		err := client.DrainServer(DrainTimeout, ServerPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"ServerHost": ServerHost, "ServerPort": ServerPort, "Timeout": DrainTimeout}).Info("Draining server")
 	},
 }
 
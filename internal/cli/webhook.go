@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	webhookCmd.AddCommand(addWebhookCmd)
+	webhookCmd.AddCommand(removeWebhookCmd)
+	webhookCmd.AddCommand(listWebhooksCmd)
+	webhookCmd.AddCommand(listWebhookDeliveriesCmd)
+	rootCmd.AddCommand(webhookCmd)
+
+	webhookCmd.PersistentFlags().StringVarP(&ServerHost, "host", "", "localhost", "Server host")
+	webhookCmd.PersistentFlags().IntVarP(&ServerPort, "port", "", -1, "Server HTTP port")
+
+	addWebhookCmd.Flags().StringVarP(&WebhookName, "name", "", "", "Webhook name")
+	addWebhookCmd.MarkFlagRequired("name")
+	addWebhookCmd.Flags().StringVarP(&WebhookURL, "url", "", "", "URL events are posted to")
+	addWebhookCmd.MarkFlagRequired("url")
+	addWebhookCmd.Flags().StringSliceVarP(&EventTypes, "events", "", []string{}, "Event types or kinds to deliver, e.g. process.failed,processgraph, all events if not set")
+	addWebhookCmd.Flags().StringVarP(&Label, "label", "", "", "Only deliver events with this label")
+	addWebhookCmd.Flags().StringVarP(&WebhookSecret, "secret", "", "", "Secret used to sign payloads with HMAC-SHA256")
+
+	removeWebhookCmd.Flags().StringVarP(&WebhookID, "webhookid", "", "", "Webhook Id")
+	removeWebhookCmd.MarkFlagRequired("webhookid")
+
+	listWebhooksCmd.Flags().BoolVarP(&JSON, "json", "", false, "Print JSON instead of tables")
+
+	listWebhookDeliveriesCmd.Flags().StringVarP(&WebhookID, "webhookid", "", "", "Webhook Id")
+	listWebhookDeliveriesCmd.MarkFlagRequired("webhookid")
+	listWebhookDeliveriesCmd.Flags().IntVarP(&Count, "count", "", DefaultCount, "Number of deliveries to list")
+	listWebhookDeliveriesCmd.Flags().BoolVarP(&JSON, "json", "", false, "Print JSON instead of tables")
+}
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Manage webhooks",
+	Long:  "Manage webhooks",
+}
+
+var addWebhookCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a webhook",
+	Long:  "Add a webhook",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		if WebhookSecret == "" {
+			log.Warn("No secret specified, payloads will not be signed")
+		}
+
+		webhook := core.CreateWebhook(ColonyName, WebhookName, WebhookURL, EventTypes, Label, WebhookSecret)
+		addedWebhook, err := client.AddWebhook(webhook, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"WebhookID": addedWebhook.ID, "URL": addedWebhook.URL}).Info("Webhook added")
+	},
+}
+
+var removeWebhookCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove a webhook",
+	Long:  "Remove a webhook",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		if WebhookID == "" {
+			CheckError(errors.New("Webhook Id not specified"))
+		}
+
+		err := client.RemoveWebhook(WebhookID, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"WebhookID": WebhookID}).Info("Webhook removed")
+	},
+}
+
+var listWebhooksCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List all webhooks",
+	Long:  "List all webhooks",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		webhooks, err := client.GetWebhooks(ColonyName, ColonyPrvKey)
+		CheckError(err)
+
+		if JSON {
+			jsonString, err := core.ConvertWebhookArrayToJSON(webhooks)
+			CheckError(err)
+			fmt.Println(jsonString)
+			os.Exit(0)
+		}
+
+		if len(webhooks) == 0 {
+			log.WithFields(log.Fields{"ColonyName": ColonyName}).Info("No webhooks found")
+			os.Exit(0)
+		}
+
+		printWebhooksTable(webhooks)
+	},
+}
+
+var listWebhookDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries",
+	Short: "List the latest deliveries of a webhook",
+	Long:  "List the latest deliveries of a webhook",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		if WebhookID == "" {
+			CheckError(errors.New("Webhook Id not specified"))
+		}
+
+		deliveries, err := client.GetWebhookDeliveries(WebhookID, Count, ColonyPrvKey)
+		CheckError(err)
+
+		if JSON {
+			jsonString, err := core.ConvertWebhookDeliveryArrayToJSON(deliveries)
+			CheckError(err)
+			fmt.Println(jsonString)
+			os.Exit(0)
+		}
+
+		if len(deliveries) == 0 {
+			log.WithFields(log.Fields{"WebhookID": WebhookID}).Info("No deliveries found")
+			os.Exit(0)
+		}
+
+		printWebhookDeliveriesTable(deliveries)
+	},
+}
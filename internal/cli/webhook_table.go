@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/colonyos/colonies/internal/table"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/muesli/termenv"
+)
+
+func printWebhooksTable(webhooks []*core.Webhook) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "webhookid", Name: "WebhookId", SortIndex: 1},
+		{ID: "name", Name: "Name", SortIndex: 2},
+		{ID: "url", Name: "URL", SortIndex: 3},
+		{ID: "events", Name: "Events", SortIndex: 4},
+		{ID: "label", Name: "Label", SortIndex: 5},
+	}
+	t.SetCols(cols)
+
+	for _, webhook := range webhooks {
+		events := strings.Join(webhook.Types, ",")
+		if events == "" {
+			events = "*"
+		}
+		row := []interface{}{
+			termenv.String(webhook.ID).Foreground(theme.ColorGray),
+			termenv.String(webhook.Name).Foreground(theme.ColorCyan),
+			termenv.String(webhook.URL).Foreground(theme.ColorViolet),
+			termenv.String(events).Foreground(theme.ColorBlue),
+			termenv.String(webhook.Label).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
+func printWebhookDeliveriesTable(deliveries []*core.WebhookDelivery) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "time", Name: "Time", SortIndex: 1},
+		{ID: "event", Name: "Event", SortIndex: 2},
+		{ID: "objectid", Name: "ObjectId", SortIndex: 3},
+		{ID: "attempts", Name: "Attempts", SortIndex: 4},
+		{ID: "status", Name: "Status", SortIndex: 5},
+		{ID: "error", Name: "Error", SortIndex: 6},
+	}
+	t.SetCols(cols)
+
+	for _, delivery := range deliveries {
+		status := termenv.String(strconv.Itoa(delivery.StatusCode)).Foreground(theme.ColorGreen)
+		if !delivery.Delivered {
+			status = termenv.String(strconv.Itoa(delivery.StatusCode)).Foreground(theme.ColorRed)
+		}
+		row := []interface{}{
+			termenv.String(delivery.Time.Format(TimeLayout)).Foreground(theme.ColorGray),
+			termenv.String(delivery.EventType).Foreground(theme.ColorCyan),
+			termenv.String(delivery.ObjectID).Foreground(theme.ColorGray),
+			termenv.String(strconv.Itoa(delivery.Attempts)).Foreground(theme.ColorViolet),
+			status,
+			termenv.String(delivery.Error).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
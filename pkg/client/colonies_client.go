@@ -4,30 +4,34 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/cluster"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/tracing"
 	"github.com/go-resty/resty/v2"
 	"github.com/gorilla/websocket"
 )
 
 type ColoniesClient struct {
-	restyClient   *resty.Client
-	host          string
-	port          int
-	insecure      bool
-	skipTLSVerify bool
+	restyClient     *resty.Client
+	address         string   // host:port of the server requests are sent to
+	failoverServers []string // host:port of servers to switch to when the server is draining, in order
+	serverMutex     sync.Mutex
+	insecure        bool
+	skipTLSVerify   bool
 }
 
 func CreateColoniesClient(host string, port int, insecure bool, skipTLSVerify bool) *ColoniesClient {
 	client := &ColoniesClient{}
 	client.restyClient = resty.New()
 
-	client.host = host
-	client.port = port
+	client.address = host + ":" + strconv.Itoa(port)
 	client.insecure = insecure
 	client.skipTLSVerify = skipTLSVerify
 
@@ -38,6 +42,50 @@ func CreateColoniesClient(host string, port int, insecure bool, skipTLSVerify bo
 	return client
 }
 
+// AddFailoverServer adds a server that requests and subscriptions are moved to when the server the client is connected
+// to is draining. Failover servers are tried in the order they were added, and must use the same TLS settings.
+func (client *ColoniesClient) AddFailoverServer(host string, port int) {
+	client.serverMutex.Lock()
+	defer client.serverMutex.Unlock()
+
+	client.failoverServers = append(client.failoverServers, host+":"+strconv.Itoa(port))
+}
+
+func (client *ColoniesClient) serverAddress() string {
+	client.serverMutex.Lock()
+	defer client.serverMutex.Unlock()
+
+	return client.address
+}
+
+func (client *ColoniesClient) numberOfFailoverServers() int {
+	client.serverMutex.Lock()
+	defer client.serverMutex.Unlock()
+
+	return len(client.failoverServers)
+}
+
+// failover switches from a draining server to the next failover server, the draining server is put last so that it is
+// used again once it has been restarted. It returns false if there is no server to switch to. If another request has
+// already switched server, the client stays on the new server.
+func (client *ColoniesClient) failover(drainingAddress string) bool {
+	client.serverMutex.Lock()
+	defer client.serverMutex.Unlock()
+
+	if len(client.failoverServers) == 0 {
+		return false
+	}
+
+	if client.address != drainingAddress {
+		return true
+	}
+
+	client.address = client.failoverServers[0]
+	client.failoverServers = append(client.failoverServers[1:], drainingAddress)
+
+	return true
+}
+
 func (client *ColoniesClient) SendRawMessage(jsonString string, insecure bool) (string, error) {
 	protocol := "https"
 	if client.insecure {
@@ -45,7 +93,7 @@ func (client *ColoniesClient) SendRawMessage(jsonString string, insecure bool) (
 	}
 	resp, err := client.restyClient.R().
 		SetBody(jsonString).
-		Post(protocol + "://" + client.host + ":" + strconv.Itoa(client.port) + "/api")
+		Post(protocol + "://" + client.serverAddress() + "/api")
 	if err != nil {
 		return "", err
 	}
@@ -67,11 +115,26 @@ func (client *ColoniesClient) sendMessage(method string, jsonString string, prvK
 			return "", err
 		}
 	}
+	rpcMsg.TraceParent, rpcMsg.TraceState = tracing.Inject(ctx)
 	jsonString, err = rpcMsg.ToJSON()
 	if err != nil {
 		return "", err
 	}
 
+	// Requests rejected by a draining server have not been handled, e.g. no process has been assigned, and are sent
+	// again to the next server
+	for attempt := 0; ; attempt++ {
+		address := client.serverAddress()
+		reply, err := client.post(address, jsonString, ctx)
+		if IsServerDraining(err) && attempt < client.numberOfFailoverServers() && client.failover(address) {
+			continue
+		}
+
+		return reply, err
+	}
+}
+
+func (client *ColoniesClient) post(address string, jsonString string, ctx context.Context) (string, error) {
 	protocol := "https"
 	if client.insecure {
 		protocol = "http"
@@ -79,7 +142,7 @@ func (client *ColoniesClient) sendMessage(method string, jsonString string, prvK
 	resp, err := client.restyClient.R().
 		SetContext(ctx).
 		SetBody(jsonString).
-		Post(protocol + "://" + client.host + ":" + strconv.Itoa(client.port) + "/api")
+		Post(protocol + "://" + address + "/api")
 	if err != nil {
 		return "", err
 	}
@@ -103,14 +166,14 @@ func (client *ColoniesClient) sendMessage(method string, jsonString string, prvK
 	return rpcReplyMsg.DecodePayload(), nil
 }
 
-func (client *ColoniesClient) establishWebSocketConn(jsonString string) (*websocket.Conn, error) {
+func (client *ColoniesClient) establishWebSocketConn(address string, jsonString string) (*websocket.Conn, error) {
 	dialer := *websocket.DefaultDialer
 	var u url.URL
 
 	if client.insecure {
-		u = url.URL{Scheme: "ws", Host: client.host + ":" + strconv.Itoa(client.port), Path: "/pubsub"}
+		u = url.URL{Scheme: "ws", Host: address, Path: "/pubsub"}
 	} else {
-		u = url.URL{Scheme: "wss", Host: client.host + ":" + strconv.Itoa(client.port), Path: "/pubsub"}
+		u = url.URL{Scheme: "wss", Host: address, Path: "/pubsub"}
 		if client.skipTLSVerify {
 			dialer.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 		}
@@ -129,36 +192,80 @@ func (client *ColoniesClient) establishWebSocketConn(jsonString string) (*websoc
 	return wsConn, nil
 }
 
-func (client *ColoniesClient) SubscribeProcesses(colonyName string, executorType string, state int, timeout int, prvKey string) (*ProcessSubscription, error) {
-	msg := rpc.CreateSubscribeProcessesMsg(colonyName, executorType, state, timeout)
-	jsonString, err := msg.ToJSON()
+// subscribe opens a websocket to the server and sends the subscription message created by createMsg. The address of
+// the server is returned, so that the subscription can fail over if the server starts draining.
+func (client *ColoniesClient) subscribe(payloadType string, createMsg func() (string, error), prvKey string) (*websocket.Conn, string, error) {
+	jsonString, err := createMsg()
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	rpcMsg, err := rpc.CreateRPCMsg(rpc.SubscribeProcessesPayloadType, jsonString, prvKey)
+	rpcMsg, err := rpc.CreateRPCMsg(payloadType, jsonString, prvKey)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	jsonString, err = rpcMsg.ToJSON()
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	address := client.serverAddress()
+	wsConn, err := client.establishWebSocketConn(address, jsonString)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return wsConn, address, nil
+}
+
+// resubscribe moves a subscription closed by a draining server to the next failover server. Each failover server is
+// tried at most once in a row, failovers counts the attempts since a message was last received.
+func (client *ColoniesClient) resubscribe(conn *subscriptionConn, address *string, failovers *int, payloadType string, createMsg func() (string, error), prvKey string) bool {
+	for *failovers < client.numberOfFailoverServers() && client.failover(*address) {
+		*failovers++
+		wsConn, newAddress, err := client.subscribe(payloadType, createMsg, prvKey)
+		if err != nil {
+			*address = client.serverAddress()
+			continue
+		}
+
+		if !conn.replace(wsConn) {
+			return false
+		}
+		*address = newAddress
+
+		return true
+	}
+
+	return false
+}
+
+// SubscribeProcesses subscribes to processes changing to state. If the server starts draining, the subscription is
+// moved to a failover server, see AddFailoverServer.
+func (client *ColoniesClient) SubscribeProcesses(colonyName string, executorType string, state int, timeout int, prvKey string) (*ProcessSubscription, error) {
+	createMsg := func() (string, error) {
+		return rpc.CreateSubscribeProcessesMsg(colonyName, executorType, state, timeout).ToJSON()
 	}
 
-	wsConn, err := client.establishWebSocketConn(jsonString)
+	wsConn, address, err := client.subscribe(rpc.SubscribeProcessesPayloadType, createMsg, prvKey)
 	if err != nil {
 		return nil, err
 	}
 
 	subscription := createProcessSubscription(wsConn)
 	go func(subscription *ProcessSubscription) {
+		failovers := 0
 		for {
-			_, jsonBytes, err := subscription.wsConn.ReadMessage()
+			_, jsonBytes, err := subscription.conn.get().ReadMessage()
 			if err != nil {
+				if IsServerDraining(err) && client.resubscribe(subscription.conn, &address, &failovers, rpc.SubscribeProcessesPayloadType, createMsg, prvKey) {
+					continue
+				}
 				subscription.ErrChan <- err
 				continue
 			}
+			failovers = 0
 
 			rpcReplyMsg, err := rpc.CreateRPCReplyMsgFromJSON(string(jsonBytes))
 			if err != nil {
@@ -187,36 +294,145 @@ func (client *ColoniesClient) SubscribeProcesses(colonyName string, executorType
 	return subscription, nil
 }
 
+// SubscribeProcess subscribes to a process changing to state. If the server starts draining, the subscription is moved
+// to a failover server, see AddFailoverServer.
 func (client *ColoniesClient) SubscribeProcess(colonyName string, processID string, executorType string, state int, timeout int, prvKey string) (*ProcessSubscription, error) {
-	msg := rpc.CreateSubscribeProcessMsg(colonyName, processID, executorType, state, timeout)
-	jsonString, err := msg.ToJSON()
-	if err != nil {
-		return nil, err
+	createMsg := func() (string, error) {
+		return rpc.CreateSubscribeProcessMsg(colonyName, processID, executorType, state, timeout).ToJSON()
 	}
 
-	rpcMsg, err := rpc.CreateRPCMsg(rpc.SubscribeProcessPayloadType, jsonString, prvKey)
+	wsConn, address, err := client.subscribe(rpc.SubscribeProcessPayloadType, createMsg, prvKey)
 	if err != nil {
 		return nil, err
 	}
 
-	jsonString, err = rpcMsg.ToJSON()
+	subscription := createProcessSubscription(wsConn)
+	go func(subscription *ProcessSubscription) {
+		failovers := 0
+		for {
+			_, jsonBytes, err := subscription.conn.get().ReadMessage()
+			if err != nil {
+				if IsServerDraining(err) && client.resubscribe(subscription.conn, &address, &failovers, rpc.SubscribeProcessPayloadType, createMsg, prvKey) {
+					continue
+				}
+				subscription.ErrChan <- err
+				continue
+			}
+			failovers = 0
+
+			rpcReplyMsg, err := rpc.CreateRPCReplyMsgFromJSON(string(jsonBytes))
+			if err != nil {
+				subscription.ErrChan <- err
+				continue
+			}
+
+			if rpcReplyMsg.Error {
+				failureMsg, err := core.ConvertJSONToFailure(rpcReplyMsg.DecodePayload())
+				if err != nil {
+					subscription.ErrChan <- err
+				}
+				subscription.ErrChan <- errors.New(failureMsg.Message)
+			}
+
+			process, err := core.ConvertJSONToProcess(rpcReplyMsg.DecodePayload())
+			if err != nil {
+				subscription.ErrChan <- err
+				continue
+			}
+			subscription.ProcessChan <- process
+		}
+	}(subscription)
+
+	return subscription, nil
+}
+
+// SubscribeEvents subscribes to events in a colony. Types may contain event types, e.g. process.failed, or event
+// kinds, e.g. process. An empty types slice or label matches all events. To resume a subscription without missing
+// events, set fromSeq to the sequence number of the last received event plus one, a fromSeq of 0 only receives new events.
+// If the server starts draining, the subscription is resumed on a failover server, see AddFailoverServer.
+func (client *ColoniesClient) SubscribeEvents(colonyName string, types []string, label string, fromSeq int64, timeout int, prvKey string) (*EventSubscription, error) {
+	nextSeq := fromSeq
+	createMsg := func() (string, error) {
+		return rpc.CreateSubscribeEventsMsg(colonyName, types, label, nextSeq, timeout).ToJSON()
+	}
+
+	wsConn, address, err := client.subscribe(rpc.SubscribeEventsPayloadType, createMsg, prvKey)
 	if err != nil {
 		return nil, err
 	}
 
-	wsConn, err := client.establishWebSocketConn(jsonString)
+	subscription := createEventSubscription(wsConn)
+	go func(subscription *EventSubscription) {
+		failovers := 0
+		for {
+			_, jsonBytes, err := subscription.conn.get().ReadMessage()
+			if err != nil {
+				if IsServerDraining(err) && client.resubscribe(subscription.conn, &address, &failovers, rpc.SubscribeEventsPayloadType, createMsg, prvKey) {
+					continue
+				}
+				subscription.ErrChan <- err
+				return // The connection is closed, either by the server (timeout) or by calling Close()
+			}
+			failovers = 0
+
+			rpcReplyMsg, err := rpc.CreateRPCReplyMsgFromJSON(string(jsonBytes))
+			if err != nil {
+				subscription.ErrChan <- err
+				continue
+			}
+
+			if rpcReplyMsg.Error {
+				failureMsg, err := core.ConvertJSONToFailure(rpcReplyMsg.DecodePayload())
+				if err != nil {
+					subscription.ErrChan <- err
+					continue
+				}
+				subscription.ErrChan <- errors.New(failureMsg.Message)
+				continue
+			}
+
+			event, err := core.ConvertJSONToEvent(rpcReplyMsg.DecodePayload())
+			if err != nil {
+				subscription.ErrChan <- err
+				continue
+			}
+			if event.Seq > 0 {
+				nextSeq = event.Seq + 1 // Resumed from here after failing over
+			}
+			subscription.EventChan <- event
+		}
+	}(subscription)
+
+	return subscription, nil
+}
+
+// SubscribeLogs streams logs added to a process, or by an executor if processID is empty, as they are added. Stored logs
+// added after since (unix nano) are sent first. If the server starts draining, the subscription is resumed on a
+// failover server, see AddFailoverServer.
+func (client *ColoniesClient) SubscribeLogs(colonyName string, processID string, executorName string, level string, fields map[string]string, since int64, timeout int, prvKey string) (*LogSubscription, error) {
+	createMsg := func() (string, error) {
+		return rpc.CreateSubscribeLogsMsg(colonyName, processID, executorName, level, fields, since, timeout).ToJSON()
+	}
+
+	wsConn, address, err := client.subscribe(rpc.SubscribeLogsPayloadType, createMsg, prvKey)
 	if err != nil {
 		return nil, err
 	}
 
-	subscription := createProcessSubscription(wsConn)
-	go func(subscription *ProcessSubscription) {
+	subscription := createLogSubscription(wsConn)
+	go func(subscription *LogSubscription) {
+		failovers := 0
 		for {
-			_, jsonBytes, err := subscription.wsConn.ReadMessage()
+			_, jsonBytes, err := subscription.conn.get().ReadMessage()
 			if err != nil {
+				if IsServerDraining(err) && client.resubscribe(subscription.conn, &address, &failovers, rpc.SubscribeLogsPayloadType, createMsg, prvKey) {
+					continue
+				}
 				subscription.ErrChan <- err
-				continue
+				return // The connection is closed, either by the server (timeout) or by calling Close()
 			}
+			failovers = 0
+
 			rpcReplyMsg, err := rpc.CreateRPCReplyMsgFromJSON(string(jsonBytes))
 			if err != nil {
 				subscription.ErrChan <- err
@@ -227,16 +443,19 @@ func (client *ColoniesClient) SubscribeProcess(colonyName string, processID stri
 				failureMsg, err := core.ConvertJSONToFailure(rpcReplyMsg.DecodePayload())
 				if err != nil {
 					subscription.ErrChan <- err
+					continue
 				}
 				subscription.ErrChan <- errors.New(failureMsg.Message)
+				continue
 			}
 
-			process, err := core.ConvertJSONToProcess(rpcReplyMsg.DecodePayload())
+			log, err := core.ConvertJSONToLog(rpcReplyMsg.DecodePayload())
 			if err != nil {
 				subscription.ErrChan <- err
 				continue
 			}
-			subscription.ProcessChan <- process
+			since = log.Timestamp // Resumed from here after failing over
+			subscription.LogChan <- log
 		}
 	}(subscription)
 
@@ -489,13 +708,19 @@ func (client *ColoniesClient) RemoveExecutor(colonyName string, executorName str
 }
 
 func (client *ColoniesClient) Submit(funcSpec *core.FunctionSpec, prvKey string) (*core.Process, error) {
+	return client.SubmitWithContext(funcSpec, prvKey, context.TODO())
+}
+
+// SubmitWithContext submits a function spec as part of the trace in ctx, e.g. when an executor submits a process while
+// executing another process, see tracing.StartProcessSpan
+func (client *ColoniesClient) SubmitWithContext(funcSpec *core.FunctionSpec, prvKey string, ctx context.Context) (*core.Process, error) {
 	msg := rpc.CreateSubmitFunctionSpecMsg(funcSpec)
 	jsonString, err := msg.ToJSON()
 	if err != nil {
 		return nil, err
 	}
 
-	respBodyString, err := client.sendMessage(rpc.SubmitFunctionSpecPayloadType, jsonString, prvKey, false, context.TODO())
+	respBodyString, err := client.sendMessage(rpc.SubmitFunctionSpecPayloadType, jsonString, prvKey, false, ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -616,6 +841,36 @@ func (client *ColoniesClient) ColonyStatistics(colonyName string, prvKey string)
 	return core.ConvertJSONToStatistics(respBodyString)
 }
 
+func (client *ColoniesClient) ColonyStatisticsHistory(colonyName string, from time.Time, to time.Time, prvKey string) ([]*core.StatisticsSnapshot, error) {
+	msg := rpc.CreateGetColonyStatisticsHistoryMsg(colonyName, from, to)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetColonyStatisticsHistoryPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToStatisticsSnapshotArray(respBodyString)
+}
+
+func (client *ColoniesClient) GetColonyUsage(colonyName string, from time.Time, to time.Time, groupBy string, prvKey string) (*core.ColonyUsage, error) {
+	msg := rpc.CreateGetColonyUsageMsg(colonyName, from, to, groupBy)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetColonyUsagePayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToColonyUsage(respBodyString)
+}
+
 func (client *ColoniesClient) Statistics(prvKey string) (*core.Statistics, error) {
 	msg := rpc.CreateGetStatisticsMsg()
 	jsonString, err := msg.ToJSON()
@@ -694,21 +949,15 @@ func (client *ColoniesClient) RemoveAllProcessesWithState(colonyName string, sta
 }
 
 func (client *ColoniesClient) Close(processID string, prvKey string) error {
-	msg := rpc.CreateCloseSuccessfulMsg(processID)
-	jsonString, err := msg.ToJSON()
-	if err != nil {
-		return err
-	}
-
-	_, err = client.sendMessage(rpc.CloseSuccessfulPayloadType, jsonString, prvKey, false, context.TODO())
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return client.CloseWithContext(processID, nil, prvKey, context.TODO())
 }
 
 func (client *ColoniesClient) CloseWithOutput(processID string, output []interface{}, prvKey string) error {
+	return client.CloseWithContext(processID, output, prvKey, context.TODO())
+}
+
+// CloseWithContext closes a process as successful as part of the trace in ctx, see tracing.StartProcessSpan
+func (client *ColoniesClient) CloseWithContext(processID string, output []interface{}, prvKey string, ctx context.Context) error {
 	msg := rpc.CreateCloseSuccessfulMsg(processID)
 	msg.Output = output
 	jsonString, err := msg.ToJSON()
@@ -716,7 +965,7 @@ func (client *ColoniesClient) CloseWithOutput(processID string, output []interfa
 		return err
 	}
 
-	_, err = client.sendMessage(rpc.CloseSuccessfulPayloadType, jsonString, prvKey, false, context.TODO())
+	_, err = client.sendMessage(rpc.CloseSuccessfulPayloadType, jsonString, prvKey, false, ctx)
 	if err != nil {
 		return err
 	}
@@ -725,13 +974,18 @@ func (client *ColoniesClient) CloseWithOutput(processID string, output []interfa
 }
 
 func (client *ColoniesClient) Fail(processID string, errs []string, prvKey string) error {
+	return client.FailWithContext(processID, errs, prvKey, context.TODO())
+}
+
+// FailWithContext closes a process as failed as part of the trace in ctx, see tracing.StartProcessSpan
+func (client *ColoniesClient) FailWithContext(processID string, errs []string, prvKey string, ctx context.Context) error {
 	msg := rpc.CreateCloseFailedMsg(processID, errs)
 	jsonString, err := msg.ToJSON()
 	if err != nil {
 		return err
 	}
 
-	_, err = client.sendMessage(rpc.CloseFailedPayloadType, jsonString, prvKey, false, context.TODO())
+	_, err = client.sendMessage(rpc.CloseFailedPayloadType, jsonString, prvKey, false, ctx)
 	if err != nil {
 		return err
 	}
@@ -785,13 +1039,19 @@ func (client *ColoniesClient) GetAttribute(attributeID string, prvKey string) (c
 }
 
 func (client *ColoniesClient) SubmitWorkflowSpec(workflowSpec *core.WorkflowSpec, prvKey string) (*core.ProcessGraph, error) {
+	return client.SubmitWorkflowSpecWithContext(workflowSpec, prvKey, context.TODO())
+}
+
+// SubmitWorkflowSpecWithContext submits a workflow as part of the trace in ctx, all processes in the workflow continue
+// the trace
+func (client *ColoniesClient) SubmitWorkflowSpecWithContext(workflowSpec *core.WorkflowSpec, prvKey string, ctx context.Context) (*core.ProcessGraph, error) {
 	msg := rpc.CreateSubmitWorkflowSpecMsg(workflowSpec)
 	jsonString, err := msg.ToJSON()
 	if err != nil {
 		return nil, err
 	}
 
-	respBodyString, err := client.sendMessage(rpc.SubmitWorkflowSpecPayloadType, jsonString, prvKey, false, context.TODO())
+	respBodyString, err := client.sendMessage(rpc.SubmitWorkflowSpecPayloadType, jsonString, prvKey, false, ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -800,13 +1060,18 @@ func (client *ColoniesClient) SubmitWorkflowSpec(workflowSpec *core.WorkflowSpec
 }
 
 func (client *ColoniesClient) AddChild(processGraphID string, parentProcessID string, childProcessID string, funcSpec *core.FunctionSpec, insert bool, prvKey string) (*core.Process, error) {
+	return client.AddChildWithContext(processGraphID, parentProcessID, childProcessID, funcSpec, insert, prvKey, context.TODO())
+}
+
+// AddChildWithContext adds a child process as part of the trace in ctx, see tracing.StartProcessSpan
+func (client *ColoniesClient) AddChildWithContext(processGraphID string, parentProcessID string, childProcessID string, funcSpec *core.FunctionSpec, insert bool, prvKey string, ctx context.Context) (*core.Process, error) {
 	msg := rpc.CreateAddChildMsg(processGraphID, parentProcessID, childProcessID, funcSpec, insert)
 	jsonString, err := msg.ToJSON()
 	if err != nil {
 		return nil, err
 	}
 
-	respBodyString, err := client.sendMessage(rpc.AddChildPayloadType, jsonString, prvKey, false, context.TODO())
+	respBodyString, err := client.sendMessage(rpc.AddChildPayloadType, jsonString, prvKey, false, ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1132,6 +1397,23 @@ func (client *ColoniesClient) RemoveFunction(functionID string, prvKey string) e
 	return nil
 }
 
+// GetFunctionStats returns statistics of function runs during the last seconds, grouped by function name and
+// executor type and divided into bucketSeconds long buckets. An empty funcName or executorType matches all.
+func (client *ColoniesClient) GetFunctionStats(colonyName string, funcName string, executorType string, seconds int, bucketSeconds int, prvKey string) ([]*core.FunctionStats, error) {
+	msg := rpc.CreateGetFunctionStatsMsg(colonyName, funcName, executorType, seconds, bucketSeconds)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetFunctionStatsPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToFunctionStatsArray(respBodyString)
+}
+
 func (client *ColoniesClient) Version() (string, string, error) {
 	msg := rpc.CreateVersionMsg("", "")
 	jsonString, err := msg.ToJSON()
@@ -1158,11 +1440,36 @@ func (client *ColoniesClient) CheckHealth() error {
 		protocol = "http"
 	}
 	_, err := client.restyClient.R().
-		Get(protocol + "://" + client.host + ":" + strconv.Itoa(client.port) + "/health")
+		Get(protocol + "://" + client.serverAddress() + "/health")
 
 	return err
 }
 
+// Liveness returns the report of the liveness probe of the server, check IsOK() to see if the server is alive
+func (client *ColoniesClient) Liveness() (*core.HealthReport, error) {
+	return client.getHealthReport("/healthz")
+}
+
+// Readiness returns the report of the readiness probe of the server, check IsOK() to see if the server and all its
+// dependencies are working
+func (client *ColoniesClient) Readiness() (*core.HealthReport, error) {
+	return client.getHealthReport("/readyz")
+}
+
+func (client *ColoniesClient) getHealthReport(path string) (*core.HealthReport, error) {
+	protocol := "https"
+	if client.insecure {
+		protocol = "http"
+	}
+	resp, err := client.restyClient.R().
+		Get(protocol + "://" + client.serverAddress() + path)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToHealthReport(string(resp.Body()))
+}
+
 func (client *ColoniesClient) GetClusterInfo(prvKey string) (*cluster.Config, error) {
 	msg := rpc.CreateGetClusterMsg()
 	jsonString, err := msg.ToJSON()
@@ -1178,8 +1485,70 @@ func (client *ColoniesClient) GetClusterInfo(prvKey string) (*cluster.Config, er
 	return cluster.ConvertJSONToConfig(respBodyString)
 }
 
+func (client *ColoniesClient) AddClusterNode(node cluster.Node, prvKey string) (*cluster.Config, error) {
+	msg := rpc.CreateAddClusterNodeMsg(node)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.AddClusterNodePayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster.ConvertJSONToConfig(respBodyString)
+}
+
+func (client *ColoniesClient) RemoveClusterNode(name string, prvKey string) error {
+	msg := rpc.CreateRemoveClusterNodeMsg(name)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.RemoveClusterNodePayloadType, jsonString, prvKey, false, context.TODO())
+
+	return err
+}
+
+// DrainServer makes the server stop accepting long-polls and subscriptions, complete the requests it is handling,
+// hand off the jobs it leads to other servers in the cluster, and then exit. The server gives up waiting for
+// requests to complete after timeout seconds, 0 means the server default.
+func (client *ColoniesClient) DrainServer(timeout int, prvKey string) error {
+	msg := rpc.CreateDrainServerMsg(timeout)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.DrainServerPayloadType, jsonString, prvKey, false, context.TODO())
+
+	return err
+}
+
+// IsServerDraining returns true if a request failed, or a subscription was closed, because the server is draining,
+// the request should then be retried on another server
+func IsServerDraining(err error) bool {
+	var coloniesErr *core.ColoniesError
+	if errors.As(err, &coloniesErr) {
+		return coloniesErr.Status == http.StatusServiceUnavailable && coloniesErr.Message == core.ServerDrainingMessage
+	}
+
+	return websocket.IsCloseError(err, websocket.CloseServiceRestart)
+}
+
 func (client *ColoniesClient) AddLog(processID string, logmsg string, prvKey string) error {
+	return client.AddStructuredLog(processID, core.LogLevelInfo, "", logmsg, nil, prvKey)
+}
+
+// AddStructuredLog adds a log with a level, e.g. core.LogLevelWarn, the stream the log was written to, e.g.
+// core.LogStreamStderr, and key/values that can be used to filter logs
+func (client *ColoniesClient) AddStructuredLog(processID string, level string, stream string, logmsg string, fields map[string]string, prvKey string) error {
 	msg := rpc.CreateAddLogMsg(processID, logmsg)
+	msg.Level = level
+	msg.Stream = stream
+	msg.Fields = fields
 	jsonString, err := msg.ToJSON()
 	if err != nil {
 		return err
@@ -1258,11 +1627,15 @@ func (client *ColoniesClient) GetLogsByExecutorSince(colonyName, executorName st
 }
 
 func (client *ColoniesClient) SearchLogs(colonyName, text string, days int, count int, prvKey string) ([]*core.Log, error) {
+	return client.SearchLogsWithFilter(colonyName, text, "", nil, days, count, prvKey)
+}
+
+// SearchLogsWithFilter searches logs with at least the given level having all the given fields, an empty level matches
+// all levels
+func (client *ColoniesClient) SearchLogsWithFilter(colonyName, text string, level string, fields map[string]string, days int, count int, prvKey string) ([]*core.Log, error) {
 	msg := rpc.CreateSearchLogsMsg(colonyName, text, days, count)
-	msg.ColonyName = colonyName
-	msg.Text = text
-	msg.Days = days
-	msg.Count = count
+	msg.Level = level
+	msg.Fields = fields
 	jsonString, err := msg.ToJSON()
 	if err != nil {
 		return []*core.Log{}, err
@@ -1336,6 +1709,21 @@ func (client *ColoniesClient) GetFileByName(colonyName string, label string, nam
 	return core.ConvertJSONToFileArray(respBodyString)
 }
 
+func (client *ColoniesClient) GetFileByNameAsOf(colonyName string, label string, name string, asOf time.Time, prvKey string) ([]*core.File, error) {
+	msg := rpc.CreateGetFileAsOfMsg(colonyName, label, name, asOf.UnixNano())
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetFilePayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToFileArray(respBodyString)
+}
+
 func (client *ColoniesClient) GetFileData(colonyName string, label string, prvKey string) ([]*core.FileData, error) {
 	msg := rpc.CreateGetFilesMsg(colonyName, label)
 	jsonString, err := msg.ToJSON()
@@ -1351,6 +1739,21 @@ func (client *ColoniesClient) GetFileData(colonyName string, label string, prvKe
 	return core.ConvertJSONToFileDataArray(respBodyString)
 }
 
+func (client *ColoniesClient) GetFileDataAsOf(colonyName string, label string, asOf time.Time, prvKey string) ([]*core.FileData, error) {
+	msg := rpc.CreateGetFilesAsOfMsg(colonyName, label, asOf.UnixNano())
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetFilesPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToFileDataArray(respBodyString)
+}
+
 func (client *ColoniesClient) GetFileLabels(colonyName string, prvKey string) ([]*core.Label, error) {
 	msg := rpc.CreateGetAllFileLabelsMsg(colonyName)
 	jsonString, err := msg.ToJSON()
@@ -1546,6 +1949,247 @@ func (client *ColoniesClient) RemoveAllSnapshots(colonyName string, prvKey strin
 	return err
 }
 
+func (client *ColoniesClient) SetRetentionPolicy(policy *core.RetentionPolicy, prvKey string) (*core.RetentionPolicy, error) {
+	msg := rpc.CreateSetRetentionPolicyMsg(policy)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.SetRetentionPolicyPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToRetentionPolicy(respBodyString)
+}
+
+func (client *ColoniesClient) GetRetentionPolicy(colonyName string, prvKey string) (*core.RetentionPolicy, error) {
+	msg := rpc.CreateGetRetentionPolicyMsg(colonyName)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetRetentionPolicyPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToRetentionPolicy(respBodyString)
+}
+
+func (client *ColoniesClient) RemoveRetentionPolicy(colonyName string, prvKey string) error {
+	msg := rpc.CreateRemoveRetentionPolicyMsg(colonyName)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.RemoveRetentionPolicyPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (client *ColoniesClient) AddWebhook(webhook *core.Webhook, prvKey string) (*core.Webhook, error) {
+	msg := rpc.CreateAddWebhookMsg(webhook)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.AddWebhookPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToWebhook(respBodyString)
+}
+
+func (client *ColoniesClient) GetWebhooks(colonyName string, prvKey string) ([]*core.Webhook, error) {
+	msg := rpc.CreateGetWebhooksMsg(colonyName)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetWebhooksPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToWebhookArray(respBodyString)
+}
+
+func (client *ColoniesClient) RemoveWebhook(webhookID string, prvKey string) error {
+	msg := rpc.CreateRemoveWebhookMsg(webhookID)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.RemoveWebhookPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (client *ColoniesClient) GetWebhookDeliveries(webhookID string, count int, prvKey string) ([]*core.WebhookDelivery, error) {
+	msg := rpc.CreateGetWebhookDeliveriesMsg(webhookID, count)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetWebhookDeliveriesPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToWebhookDeliveryArray(respBodyString)
+}
+
+func (client *ColoniesClient) AddAlertRule(rule *core.AlertRule, prvKey string) (*core.AlertRule, error) {
+	msg := rpc.CreateAddAlertRuleMsg(rule)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.AddAlertRulePayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToAlertRule(respBodyString)
+}
+
+func (client *ColoniesClient) GetAlertRules(colonyName string, prvKey string) ([]*core.AlertRule, error) {
+	msg := rpc.CreateGetAlertRulesMsg(colonyName)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetAlertRulesPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToAlertRuleArray(respBodyString)
+}
+
+func (client *ColoniesClient) RemoveAlertRule(colonyName string, name string, prvKey string) error {
+	msg := rpc.CreateRemoveAlertRuleMsg(colonyName, name)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.RemoveAlertRulePayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (client *ColoniesClient) AddFederationPeer(peer *core.FederationPeer, prvKey string) (*core.FederationPeer, error) {
+	msg := rpc.CreateAddFederationPeerMsg(peer)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.AddFederationPeerPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToFederationPeer(respBodyString)
+}
+
+func (client *ColoniesClient) GetFederationPeers(colonyName string, prvKey string) ([]*core.FederationPeer, error) {
+	msg := rpc.CreateGetFederationPeersMsg(colonyName)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetFederationPeersPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToFederationPeerArray(respBodyString)
+}
+
+func (client *ColoniesClient) RemoveFederationPeer(colonyName string, name string, prvKey string) error {
+	msg := rpc.CreateRemoveFederationPeerMsg(colonyName, name)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.RemoveFederationPeerPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SubmitFederatedProcess is called by a federated server to forward a process, prvKey must be the private key of the
+// calling server
+func (client *ColoniesClient) SubmitFederatedProcess(funcSpec *core.FunctionSpec, prvKey string, ctx context.Context) (*core.Process, error) {
+	msg := rpc.CreateSubmitFederatedProcessMsg(funcSpec)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.SubmitFederatedProcessPayloadType, jsonString, prvKey, false, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToProcess(respBodyString)
+}
+
+// GetFederatedProcess is called by a federated server to get the state of a process it has forwarded
+func (client *ColoniesClient) GetFederatedProcess(processID string, prvKey string, ctx context.Context) (*core.Process, error) {
+	msg := rpc.CreateGetFederatedProcessMsg(processID)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetFederatedProcessPayloadType, jsonString, prvKey, false, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToProcess(respBodyString)
+}
+
+// RemoveFederatedProcess is called by a federated server to withdraw a process it has forwarded
+func (client *ColoniesClient) RemoveFederatedProcess(processID string, prvKey string, ctx context.Context) error {
+	msg := rpc.CreateRemoveFederatedProcessMsg(processID)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.RemoveFederatedProcessPayloadType, jsonString, prvKey, false, ctx)
+
+	return err
+}
+
 func (client *ColoniesClient) ChangeUserID(colonyName, userID string, prvKey string) error {
 	msg := rpc.CreateChangeUserIDMsg(colonyName, userID)
 	jsonString, err := msg.ToJSON()
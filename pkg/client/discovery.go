@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/p2p/dht"
+	log "github.com/sirupsen/logrus"
+)
+
+// DiscoveryMaxAge is how old a published endpoint may be before the server is considered gone, servers republish
+// their endpoints once a minute
+const DiscoveryMaxAge = 5 * time.Minute
+
+// ResolveColony looks up the endpoints of the servers serving a colony in a DHT. Endpoints are only accepted if they
+// are signed by the server ID, so nodes in the DHT cannot redirect clients to other servers. The most recently
+// published endpoint is returned first.
+func ResolveColony(d dht.DHT, serverID string, colonyName string, ctx context.Context) ([]*core.ServerEndpoint, error) {
+	kvs, err := d.Get(serverID, core.DiscoveryColonyKey(colonyName), dht.RegistrationReplicationFactor, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replicas may store endpoints published at different times, only the latest endpoint of every server is kept
+	latest := make(map[string]*core.ServerEndpoint)
+	for _, kv := range kvs {
+		if kv.ID != serverID {
+			continue
+		}
+
+		valid, err := dht.ValidateKV(&kv)
+		if err != nil || !valid {
+			log.WithFields(log.Fields{"Error": err, "Key": kv.Key}).Warn("Ignoring invalid server endpoint")
+			continue
+		}
+
+		endpoint, err := core.ConvertJSONToServerEndpoint(kv.Value)
+		if err != nil || endpoint == nil {
+			log.WithFields(log.Fields{"Error": err, "Key": kv.Key}).Warn("Ignoring invalid server endpoint")
+			continue
+		}
+
+		if time.Since(endpoint.Published) > DiscoveryMaxAge {
+			continue
+		}
+
+		if existing, ok := latest[endpoint.Name]; !ok || endpoint.Published.After(existing.Published) {
+			latest[endpoint.Name] = endpoint
+		}
+	}
+
+	if len(latest) == 0 {
+		return nil, errors.New("No servers found for colony <" + colonyName + ">")
+	}
+
+	var endpoints []*core.ServerEndpoint
+	for _, endpoint := range latest {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Published.After(endpoints[j].Published)
+	})
+
+	return endpoints, nil
+}
+
+// CreateColoniesClientFromEndpoint creates a client connected to a server endpoint resolved with ResolveColony
+func CreateColoniesClientFromEndpoint(endpoint *core.ServerEndpoint, skipTLSVerify bool) *ColoniesClient {
+	return CreateColoniesClient(endpoint.Host, endpoint.Port, !endpoint.TLS, skipTLSVerify)
+}
+
+// CreateColoniesClientFromEndpoints creates a client connected to the first server endpoint resolved with
+// ResolveColony, the other endpoints are used as failover servers when a server is draining
+func CreateColoniesClientFromEndpoints(endpoints []*core.ServerEndpoint, skipTLSVerify bool) (*ColoniesClient, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("No server endpoints")
+	}
+
+	client := CreateColoniesClientFromEndpoint(endpoints[0], skipTLSVerify)
+	for _, endpoint := range endpoints[1:] {
+		if endpoint.TLS != endpoints[0].TLS {
+			continue // The TLS settings of the client are shared by all servers
+		}
+		client.AddFailoverServer(endpoint.Host, endpoint.Port)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/security/crypto"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestClient(t *testing.T, servers ...*httptest.Server) *ColoniesClient {
+	var client *ColoniesClient
+	for i, server := range servers {
+		u, err := url.Parse(server.URL)
+		assert.Nil(t, err)
+		port, err := strconv.Atoi(u.Port())
+		assert.Nil(t, err)
+
+		if i == 0 {
+			client = CreateColoniesClient(u.Hostname(), port, true, false)
+		} else {
+			client.AddFailoverServer(u.Hostname(), port)
+		}
+	}
+
+	return client
+}
+
+func writeTestReply(t *testing.T, w http.ResponseWriter, replyMsg *rpc.RPCReplyMsg) {
+	jsonString, err := replyMsg.ToJSON()
+	assert.Nil(t, err)
+	w.Write([]byte(jsonString))
+}
+
+func TestAssignFailover(t *testing.T) {
+	drainingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failureJSON, err := core.CreateFailure(http.StatusServiceUnavailable, core.ServerDrainingMessage).ToJSON()
+		assert.Nil(t, err)
+		replyMsg, err := rpc.CreateRPCErrorReplyMsg(rpc.ErrorPayloadType, failureJSON)
+		assert.Nil(t, err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		writeTestReply(t, w, replyMsg)
+	}))
+	defer drainingServer.Close()
+
+	process := core.CreateProcess(core.CreateEmptyFunctionSpec())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		processJSON, err := process.ToJSON()
+		assert.Nil(t, err)
+		replyMsg, err := rpc.CreateRPCReplyMsg(rpc.AssignProcessPayloadType, processJSON)
+		assert.Nil(t, err)
+		writeTestReply(t, w, replyMsg)
+	}))
+	defer server.Close()
+
+	prvKey, err := crypto.CreateCrypto().GeneratePrivateKey()
+	assert.Nil(t, err)
+
+	// Without failover servers the draining error is returned
+	client := createTestClient(t, drainingServer)
+	_, err = client.Assign("test_colony", 10, "", "", prvKey)
+	assert.True(t, IsServerDraining(err))
+
+	// The assign is sent again to the failover server, which is then used for subsequent requests
+	client = createTestClient(t, drainingServer, server)
+	assignedProcess, err := client.Assign("test_colony", 10, "", "", prvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, process.ID, assignedProcess.ID)
+	assert.Equal(t, server.Listener.Addr().String(), client.serverAddress())
+}
+
+func TestSubscribeEventsFailover(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	fromSeqs := make(chan int64, 2)
+
+	// Both servers record the sequence number the subscription starts from, and then send one event
+	createServer := func(seq int64, drain bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wsConn, err := upgrader.Upgrade(w, r, nil)
+			assert.Nil(t, err)
+			defer wsConn.Close()
+
+			_, jsonBytes, err := wsConn.ReadMessage()
+			assert.Nil(t, err)
+			rpcMsg, err := rpc.CreateRPCMsgFromJSON(string(jsonBytes))
+			assert.Nil(t, err)
+			msg, err := rpc.CreateSubscribeEventsMsgFromJSON(rpcMsg.DecodePayload())
+			assert.Nil(t, err)
+			fromSeqs <- msg.FromSeq
+
+			file := &core.File{ID: core.GenerateRandomID(), ColonyName: "test_colony"}
+			event := core.CreateFileEvent(core.FileAddedEvent, file)
+			event.Seq = seq
+			eventJSON, err := event.ToJSON()
+			assert.Nil(t, err)
+			replyMsg, err := rpc.CreateRPCReplyMsg(rpc.SubscribeEventsPayloadType, eventJSON)
+			assert.Nil(t, err)
+			replyJSON, err := replyMsg.ToJSON()
+			assert.Nil(t, err)
+			err = wsConn.WriteMessage(websocket.TextMessage, []byte(replyJSON))
+			assert.Nil(t, err)
+
+			if drain {
+				closeMsg := websocket.FormatCloseMessage(websocket.CloseServiceRestart, core.ServerDrainingMessage)
+				wsConn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+				return
+			}
+
+			// Keep the subscription open until the client closes it
+			wsConn.ReadMessage()
+		}))
+	}
+
+	drainingServer := createServer(5, true)
+	defer drainingServer.Close()
+	server := createServer(6, false)
+	defer server.Close()
+
+	prvKey, err := crypto.CreateCrypto().GeneratePrivateKey()
+	assert.Nil(t, err)
+
+	client := createTestClient(t, drainingServer, server)
+	subscription, err := client.SubscribeEvents("test_colony", []string{}, "", 0, 100, prvKey)
+	assert.Nil(t, err)
+
+	// The subscription is resumed on the failover server after the last received event
+	for _, expectedSeq := range []int64{5, 6} {
+		select {
+		case event := <-subscription.EventChan:
+			assert.Equal(t, expectedSeq, event.Seq)
+		case err := <-subscription.ErrChan:
+			assert.Fail(t, err.Error())
+		case <-time.After(5 * time.Second):
+			assert.Fail(t, "Timeout waiting for event")
+		}
+	}
+
+	assert.Equal(t, int64(0), <-fromSeqs)
+	assert.Equal(t, int64(6), <-fromSeqs)
+
+	subscription.Close()
+}
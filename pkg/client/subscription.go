@@ -1,25 +1,102 @@
 package client
 
 import (
+	"sync"
+
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/gorilla/websocket"
 )
 
+// subscriptionConn is the websocket connection of a subscription, which is replaced when the subscription fails over
+// from a draining server to another server
+type subscriptionConn struct {
+	wsConn *websocket.Conn
+	closed bool
+	mutex  sync.Mutex
+}
+
+func (conn *subscriptionConn) get() *websocket.Conn {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+
+	return conn.wsConn
+}
+
+// replace returns false, and closes wsConn, if the subscription has been closed
+func (conn *subscriptionConn) replace(wsConn *websocket.Conn) bool {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+
+	if conn.closed {
+		wsConn.Close()
+		return false
+	}
+
+	conn.wsConn = wsConn
+	return true
+}
+
+func (conn *subscriptionConn) close() error {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+
+	conn.closed = true
+	return conn.wsConn.Close()
+}
+
 type ProcessSubscription struct {
 	ProcessChan chan *core.Process
 	ErrChan     chan error
-	wsConn      *websocket.Conn
+	conn        *subscriptionConn
 }
 
 func createProcessSubscription(wsConn *websocket.Conn) *ProcessSubscription {
 	subscription := &ProcessSubscription{}
 	subscription.ProcessChan = make(chan *core.Process)
 	subscription.ErrChan = make(chan error)
-	subscription.wsConn = wsConn
+	subscription.conn = &subscriptionConn{wsConn: wsConn}
 
 	return subscription
 }
 
 func (subscription *ProcessSubscription) Close() error {
-	return subscription.wsConn.Close()
+	return subscription.conn.close()
+}
+
+type EventSubscription struct {
+	EventChan chan *core.Event
+	ErrChan   chan error
+	conn      *subscriptionConn
+}
+
+func createEventSubscription(wsConn *websocket.Conn) *EventSubscription {
+	subscription := &EventSubscription{}
+	subscription.EventChan = make(chan *core.Event)
+	subscription.ErrChan = make(chan error)
+	subscription.conn = &subscriptionConn{wsConn: wsConn}
+
+	return subscription
+}
+
+func (subscription *EventSubscription) Close() error {
+	return subscription.conn.close()
+}
+
+type LogSubscription struct {
+	LogChan chan *core.Log
+	ErrChan chan error
+	conn    *subscriptionConn
+}
+
+func createLogSubscription(wsConn *websocket.Conn) *LogSubscription {
+	subscription := &LogSubscription{}
+	subscription.LogChan = make(chan *core.Log)
+	subscription.ErrChan = make(chan error)
+	subscription.conn = &subscriptionConn{wsConn: wsConn}
+
+	return subscription
+}
+
+func (subscription *LogSubscription) Close() error {
+	return subscription.conn.close()
 }
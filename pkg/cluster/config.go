@@ -39,12 +39,23 @@ func (node *Node) Equals(node2 *Node) bool {
 type Config struct {
 	Nodes  []Node `json:"nodes"`
 	Leader Node   `json:"leader"`
+	Join   bool   `json:"join,omitempty"` // Join an existing cluster, the node must first have been added as a member
 }
 
 func (config *Config) AddNode(node Node) {
 	config.Nodes = append(config.Nodes, node)
 }
 
+func (config *Config) RemoveNode(name string) {
+	var nodes []Node
+	for _, node := range config.Nodes {
+		if node.Name != name {
+			nodes = append(nodes, node)
+		}
+	}
+	config.Nodes = nodes
+}
+
 func ConvertJSONToConfig(jsonString string) (*Config, error) {
 	var config *Config
 	err := json.Unmarshal([]byte(jsonString), &config)
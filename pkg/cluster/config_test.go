@@ -27,3 +27,19 @@ func TestCreateCluster(t *testing.T) {
 	config.Leader = node1
 	assert.False(t, config.Equals(config2))
 }
+
+func TestRemoveNode(t *testing.T) {
+	node1 := Node{Name: "etcd1", Host: "localhost", EtcdClientPort: 24100, EtcdPeerPort: 23100, RelayPort: 25100, APIPort: 26100}
+	node2 := Node{Name: "etcd2", Host: "localhost", EtcdClientPort: 24200, EtcdPeerPort: 23200, RelayPort: 25200, APIPort: 26200}
+
+	config := Config{}
+	config.AddNode(node1)
+	config.AddNode(node2)
+
+	config.RemoveNode("etcd1")
+	assert.Len(t, config.Nodes, 1)
+	assert.Equal(t, "etcd2", config.Nodes[0].Name)
+
+	config.RemoveNode("does_not_exist")
+	assert.Len(t, config.Nodes, 1)
+}
@@ -0,0 +1,208 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+const electionsPrefix = "/colonies/elections/"
+
+// JobElection elects a single node to run a background job. The leader holds a key attached to a lease, if the
+// leader crashes or is partitioned from the cluster, the lease expires and another node takes over the job. The
+// create revision of the leader key is used as fencing token, it is unique and increases every time a new leader is
+// elected.
+type JobElection struct {
+	job        string
+	etcdServer *EtcdServer
+	ttl        int
+	delay      func() time.Duration
+	mutex      sync.Mutex
+	leader     bool
+	key        string
+	token      int64
+	ctx        context.Context
+	cancel     context.CancelFunc
+	stopped    chan struct{}
+}
+
+// CreateJobElection creates an election for a job. The ttl is the lease time in seconds, i.e. how long it takes
+// before another node takes over if the leader dies. The delay function is called before each campaign, and is used
+// to let nodes that already lead other jobs wait, so that jobs are spread across nodes.
+func (server *EtcdServer) CreateJobElection(job string, ttl int, delay func() time.Duration) *JobElection {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobElection{job: job,
+		etcdServer: server,
+		ttl:        ttl,
+		delay:      delay,
+		ctx:        ctx,
+		cancel:     cancel,
+		stopped:    make(chan struct{})}
+}
+
+func (election *JobElection) Job() string {
+	return election.job
+}
+
+func (election *JobElection) Start() {
+	go election.campaignForever()
+}
+
+// Stop resigns leadership, so that another node can take over the job without waiting for the lease to expire. If the
+// cluster has lost its quorum, resigning never completes, so Stop gives up once the lease would have expired anyway.
+func (election *JobElection) Stop() {
+	election.cancel()
+
+	select {
+	case <-election.stopped:
+	case <-time.After(time.Duration(election.ttl) * time.Second):
+		log.WithFields(log.Fields{"Job": election.job}).Warn("Timed out resigning job leadership")
+	}
+}
+
+func (election *JobElection) setLeader(leader bool, key string, token int64) {
+	election.mutex.Lock()
+	defer election.mutex.Unlock()
+
+	election.leader = leader
+	election.key = key
+	election.token = token
+}
+
+// IsLeader returns true if this node is believed to lead the job, use Verify to make sure before running the job
+func (election *JobElection) IsLeader() bool {
+	election.mutex.Lock()
+	defer election.mutex.Unlock()
+
+	return election.leader
+}
+
+// FencingToken returns the fencing token of this node, or 0 if it does not lead the job
+func (election *JobElection) FencingToken() int64 {
+	election.mutex.Lock()
+	defer election.mutex.Unlock()
+
+	if !election.leader {
+		return 0
+	}
+
+	return election.token
+}
+
+// Verify makes a linearizable read to check that this node still leads the job, and returns its fencing token. A
+// leader that has lost its lease, or cannot reach a quorum of etcd members, fails the check.
+func (election *JobElection) Verify(ctx context.Context) (int64, error) {
+	election.mutex.Lock()
+	leader := election.leader
+	key := election.key
+	token := election.token
+	election.mutex.Unlock()
+
+	if !leader {
+		return 0, errors.New("Not leader of job <" + election.job + ">")
+	}
+
+	resp, err := election.etcdServer.etcdClient.Get(ctx, electionsPrefix+election.job+"/", clientv3.WithFirstCreate()...)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(resp.Kvs) == 0 || string(resp.Kvs[0].Key) != key || resp.Kvs[0].CreateRevision != token {
+		election.setLeader(false, "", 0)
+		return 0, errors.New("Lost leadership of job <" + election.job + ">")
+	}
+
+	return token, nil
+}
+
+func (election *JobElection) campaignForever() {
+	defer close(election.stopped)
+
+	name := election.etcdServer.thisNode.Name
+	for {
+		// The session context is cancelled when the election is stopped, so that creating a session does not block
+		// forever if the cluster has lost its quorum
+		session, err := concurrency.NewSession(election.etcdServer.etcdClient, concurrency.WithTTL(election.ttl), concurrency.WithContext(election.ctx))
+		if err != nil {
+			if election.ctx.Err() != nil {
+				return
+			}
+			log.WithFields(log.Fields{"Error": err, "Job": election.job}).Warn("Failed to create election session")
+			select {
+			case <-election.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		select {
+		case <-election.ctx.Done():
+			session.Close()
+			return
+		case <-time.After(election.delay()):
+		}
+
+		ctx, cancel := context.WithCancel(election.ctx)
+		go func() {
+			select {
+			case <-session.Done():
+			case <-ctx.Done():
+			}
+			cancel()
+		}()
+
+		e := concurrency.NewElection(session, electionsPrefix+election.job)
+		err = e.Campaign(ctx, name)
+		if err != nil {
+			cancel()
+			session.Close()
+			continue
+		}
+
+		election.setLeader(true, e.Key(), e.Rev())
+		log.WithFields(log.Fields{"Job": election.job, "Node": name, "FencingToken": e.Rev()}).Info("Became job leader")
+
+		// The session also ends when the election is stopped, since it uses the same context
+		select {
+		case <-election.ctx.Done():
+		case <-session.Done():
+		}
+		election.setLeader(false, "", 0)
+		cancel()
+
+		if election.ctx.Err() != nil {
+			resignCtx, resignCancel := context.WithTimeout(context.Background(), time.Second)
+			e.Resign(resignCtx)
+			resignCancel()
+			session.Close()
+			return
+		}
+
+		log.WithFields(log.Fields{"Job": election.job, "Node": name}).Warn("Lost job leadership, lease expired")
+		session.Close()
+	}
+}
+
+// JobLeader returns the name of the node leading a job and its fencing token, or an empty name if no node leads it
+func (server *EtcdServer) JobLeader(ctx context.Context, job string) (string, int64, error) {
+	if server.etcdClient == nil {
+		return "", 0, errors.New("etcd client is not initialized")
+	}
+
+	resp, err := server.etcdClient.Get(ctx, electionsPrefix+job+"/", clientv3.WithFirstCreate()...)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if len(resp.Kvs) == 0 {
+		return "", 0, nil
+	}
+
+	return string(resp.Kvs[0].Value), resp.Kvs[0].CreateRevision, nil
+}
@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func waitForJobLeader(election *JobElection) bool {
+	for i := 0; i < 100; i++ {
+		if election.IsLeader() {
+			return true
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return false
+}
+
+func TestJobElection(t *testing.T) {
+	node := Node{Name: "etcd1", Host: "localhost", EtcdClientPort: 24960, EtcdPeerPort: 23960, RelayPort: 25960, APIPort: 26960}
+	config := Config{}
+	config.AddNode(node)
+
+	server := CreateEtcdServer(node, config, ".")
+	server.Start()
+	server.WaitToStart()
+
+	noDelay := func() time.Duration { return 0 }
+
+	election1 := server.CreateJobElection("test_job", 5, noDelay)
+	election1.Start()
+	assert.True(t, waitForJobLeader(election1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	token1, err := election1.Verify(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, token1, election1.FencingToken())
+	assert.True(t, token1 > 0)
+
+	name, token, err := server.JobLeader(ctx, "test_job")
+	assert.Nil(t, err)
+	assert.Equal(t, "etcd1", name)
+	assert.Equal(t, token1, token)
+
+	// A second candidate must wait until the first leader resigns
+	election2 := server.CreateJobElection("test_job", 5, noDelay)
+	election2.Start()
+	time.Sleep(500 * time.Millisecond)
+	assert.False(t, election2.IsLeader())
+	_, err = election2.Verify(ctx)
+	assert.NotNil(t, err)
+
+	// Other jobs are elected independently
+	election3 := server.CreateJobElection("another_job", 5, noDelay)
+	election3.Start()
+	assert.True(t, waitForJobLeader(election3))
+
+	election1.Stop()
+	assert.False(t, election1.IsLeader())
+	assert.Equal(t, int64(0), election1.FencingToken())
+	assert.True(t, waitForJobLeader(election2))
+
+	token2, err := election2.Verify(ctx)
+	assert.Nil(t, err)
+	assert.True(t, token2 > token1)
+
+	election2.Stop()
+	election3.Stop()
+
+	name, _, err = server.JobLeader(ctx, "test_job")
+	assert.Nil(t, err)
+	assert.Equal(t, "", name)
+
+	server.Stop()
+	server.WaitToStop()
+	os.RemoveAll(server.StorageDir())
+}
@@ -2,10 +2,13 @@ package cluster
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -23,15 +26,24 @@ type EtcdServer struct {
 	etcd       *embed.Etcd
 	cfg        *embed.Config
 	etcdClient *clientv3.Client
+	nodes      map[string]Node
+	nodesMutex sync.Mutex
 }
 
+const nodesPrefix = "/colonies/nodes/"
+
 func CreateEtcdServer(thisNode Node, config Config, dataPath string) *EtcdServer {
 	server := &EtcdServer{thisNode: thisNode,
 		config:   config,
 		ready:    make(chan bool, 1),
 		stop:     make(chan bool, 1),
 		stopped:  make(chan bool, 1),
-		dataPath: dataPath}
+		dataPath: dataPath,
+		nodes:    make(map[string]Node)}
+
+	for _, node := range config.Nodes {
+		server.nodes[node.Name] = node
+	}
 
 	cfg := embed.NewConfig()
 	cfg.LogLevel = "fatal"
@@ -60,6 +72,9 @@ func CreateEtcdServer(thisNode Node, config Config, dataPath string) *EtcdServer
 	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
 	cfg.InitialCluster = server.buildInitialClusterStr()
 	cfg.InitialClusterToken = "etcd-cluster-1"
+	if config.Join {
+		cfg.ClusterState = embed.ClusterStateFlagExisting
+	}
 
 	server.cfg = cfg
 
@@ -69,7 +84,7 @@ func CreateEtcdServer(thisNode Node, config Config, dataPath string) *EtcdServer
 func (server *EtcdServer) buildInitialClusterStr() string {
 	var str string
 	for _, node := range server.config.Nodes {
-		str += node.Name + "=" + "http://" + node.Host + ":" + strconv.Itoa(node.EtcdPeerPort) + ","
+		str += node.Name + "=" + peerURL(node) + ","
 	}
 
 	if len(str) > 1 {
@@ -79,6 +94,10 @@ func (server *EtcdServer) buildInitialClusterStr() string {
 	return ""
 }
 
+func peerURL(node Node) string {
+	return "http://" + node.Host + ":" + strconv.Itoa(node.EtcdPeerPort)
+}
+
 func (server *EtcdServer) StorageDir() string {
 	return server.cfg.Dir
 }
@@ -89,7 +108,6 @@ func (server *EtcdServer) Start() {
 		if err != nil {
 			log.Fatal(err)
 		}
-		defer etcd.Close()
 		server.etcd = etcd
 		select {
 		case <-etcd.Server.ReadyNotify():
@@ -104,6 +122,8 @@ func (server *EtcdServer) Start() {
 				log.Fatal(err)
 			}
 			server.etcdClient = client
+			server.registerNode()
+			go server.watchNodes(client)
 
 			log.WithFields(log.Fields{
 				"Name":           server.thisNode.Name,
@@ -116,7 +136,7 @@ func (server *EtcdServer) Start() {
 			if server.etcdClient != nil {
 				server.etcdClient.Close()
 			}
-			etcd.Server.Stop()
+			etcd.Close() // Closes the listeners before signalling stopped, so that the ports can be reused
 			log.WithFields(log.Fields{
 				"Name":           server.thisNode.Name,
 				"Host":           server.thisNode.Host,
@@ -149,7 +169,23 @@ func (server *EtcdServer) WaitToStop() {
 	<-server.stopped
 }
 
+// hasStopped returns true if the embedded etcd server has stopped, e.g. after this node was removed from the cluster
+func (server *EtcdServer) hasStopped() bool {
+	select {
+	case <-server.etcd.Server.StopNotify():
+		return true
+	default:
+		return false
+	}
+}
+
+// Leader returns the name of the etcd leader, or an empty string if there is no leader or this node is no longer
+// part of the cluster
 func (server *EtcdServer) Leader() string {
+	if !server.IsMember() {
+		return ""
+	}
+
 	leader := server.etcd.Server.Leader()
 	for _, member := range server.etcd.Server.Cluster().Members() {
 		if member.ID == leader {
@@ -159,20 +195,284 @@ func (server *EtcdServer) Leader() string {
 	return ""
 }
 
+// IsMember returns true if this node is a member of the etcd cluster
+func (server *EtcdServer) IsMember() bool {
+	if server.hasStopped() {
+		return false
+	}
+
+	for _, member := range server.etcd.Server.Cluster().Members() {
+		if member.Name == server.thisNode.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckQuorum makes a linearizable read, which only succeeds if the etcd cluster has a leader and this node can
+// reach a quorum of members
+func (server *EtcdServer) CheckQuorum(ctx context.Context) error {
+	if server.etcdClient == nil {
+		return errors.New("etcd client is not initialized")
+	}
+
+	_, err := server.etcdClient.Get(ctx, "/colonies/health")
+	return err
+}
+
+// Members returns the nodes of the current etcd members. Members that have been added but not yet started have no
+// name, and are matched by their peer URL instead.
 func (server *EtcdServer) Members() []Node {
+	server.nodesMutex.Lock()
+	defer server.nodesMutex.Unlock()
+
 	var nodes []Node
 	for _, member := range server.etcd.Server.Cluster().Members() {
-		for _, node := range server.config.Nodes {
-			if node.Name == member.Name {
+		for _, node := range server.nodes {
+			if node.Name == member.Name || (member.Name == "" && containsURL(member.PeerURLs, peerURL(node))) {
 				nodes = append(nodes, node)
+				break
 			}
-
 		}
 	}
 
 	return nodes
 }
 
+func containsURL(urls []string, url string) bool {
+	for _, u := range urls {
+		if u == url {
+			return true
+		}
+	}
+
+	return false
+}
+
+// registerNode stores this node under /colonies/nodes/ so that nodes that joined later become known by all members
+func (server *EtcdServer) registerNode() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := server.putNode(ctx, server.thisNode)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "Name": server.thisNode.Name}).Error("Failed to register node in etcd")
+	}
+}
+
+func (server *EtcdServer) putNode(ctx context.Context, node Node) error {
+	jsonBytes, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+
+	_, err = server.etcdClient.Put(ctx, nodesPrefix+node.Name, string(jsonBytes))
+	return err
+}
+
+func (server *EtcdServer) setNode(jsonBytes []byte) {
+	var node Node
+	err := json.Unmarshal(jsonBytes, &node)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to parse node stored in etcd")
+		return
+	}
+
+	server.nodesMutex.Lock()
+	server.nodes[node.Name] = node
+	server.nodesMutex.Unlock()
+}
+
+// watchNodes keeps the node registry in sync with /colonies/nodes/, it returns when the client is closed
+func (server *EtcdServer) watchNodes(client *clientv3.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	resp, err := client.Get(ctx, nodesPrefix, clientv3.WithPrefix())
+	cancel()
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to get nodes from etcd")
+		return
+	}
+
+	for _, kv := range resp.Kvs {
+		server.setNode(kv.Value)
+	}
+
+	watchChan := client.Watch(context.Background(), nodesPrefix, clientv3.WithPrefix(), clientv3.WithRev(resp.Header.Revision+1))
+	for watchResp := range watchChan {
+		for _, event := range watchResp.Events {
+			switch event.Type {
+			case clientv3.EventTypePut:
+				server.setNode(event.Kv.Value)
+			case clientv3.EventTypeDelete:
+				server.nodesMutex.Lock()
+				delete(server.nodes, strings.TrimPrefix(string(event.Kv.Key), nodesPrefix))
+				server.nodesMutex.Unlock()
+			}
+		}
+	}
+}
+
+// AddMember adds a node as a new etcd member. The node must then be started with Config.Join set to join the cluster.
+// The node is registered before it is added, since adding a member to a small cluster may cause quorum loss until the
+// new member has started.
+func (server *EtcdServer) AddMember(ctx context.Context, node Node) error {
+	if server.etcdClient == nil {
+		return errors.New("etcd client is not initialized")
+	}
+
+	if node.Name == "" {
+		return errors.New("Node name is empty")
+	}
+
+	resp, err := server.etcdClient.MemberList(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range resp.Members {
+		if member.Name == node.Name {
+			return errors.New("Node with name <" + node.Name + "> is already a member")
+		}
+		if containsURL(member.PeerURLs, peerURL(node)) {
+			return errors.New("Node with peer URL <" + peerURL(node) + "> is already a member")
+		}
+	}
+
+	err = server.putNode(ctx, node)
+	if err != nil {
+		return err
+	}
+
+	_, err = server.etcdClient.MemberAdd(ctx, []string{peerURL(node)})
+	if err != nil {
+		if _, delErr := server.etcdClient.Delete(ctx, nodesPrefix+node.Name); delErr != nil {
+			log.WithFields(log.Fields{"Error": delErr, "Name": node.Name}).Error("Failed to unregister node in etcd")
+		}
+		return err
+	}
+
+	server.nodesMutex.Lock()
+	server.nodes[node.Name] = node
+	server.nodesMutex.Unlock()
+
+	log.WithFields(log.Fields{"Name": node.Name, "Host": node.Host, "EtcdPeerPort": node.EtcdPeerPort}).Info("Added etcd member")
+
+	return nil
+}
+
+// RemoveMember removes a node from the etcd cluster. If the node is the current leader, leadership is first
+// transferred to another started member so that the cluster does not have to wait for an election timeout.
+func (server *EtcdServer) RemoveMember(ctx context.Context, name string) error {
+	if server.etcdClient == nil {
+		return errors.New("etcd client is not initialized")
+	}
+
+	resp, err := server.etcdClient.MemberList(ctx)
+	if err != nil {
+		return err
+	}
+
+	server.nodesMutex.Lock()
+	node, registered := server.nodes[name]
+	server.nodesMutex.Unlock()
+
+	leaving := -1
+	for i, member := range resp.Members {
+		if member.Name == name || (member.Name == "" && registered && containsURL(member.PeerURLs, peerURL(node))) {
+			leaving = i
+			break
+		}
+	}
+
+	if leaving == -1 {
+		return errors.New("Node with name <" + name + "> is not a member")
+	}
+
+	if len(resp.Members) == 1 {
+		return errors.New("Cannot remove <" + name + ">, it is the last member of the cluster")
+	}
+
+	leavingID := resp.Members[leaving].ID
+	if uint64(server.etcd.Server.Leader()) == leavingID {
+		err = moveLeader(ctx, resp, leaving)
+		if err != nil {
+			return errors.New("Cannot remove leader <" + name + ">, " + err.Error())
+		}
+	}
+
+	_, err = server.etcdClient.MemberRemove(ctx, leavingID)
+	if err != nil {
+		return err
+	}
+
+	_, err = server.etcdClient.Delete(ctx, nodesPrefix+name)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "Name": name}).Warn("Failed to unregister node in etcd")
+	}
+
+	log.WithFields(log.Fields{"Name": name}).Info("Removed etcd member")
+
+	return nil
+}
+
+// TransferLeadership transfers leadership to another started member if this node is the leader, so that the cluster
+// does not have to wait for an election timeout when this node is shut down
+func (server *EtcdServer) TransferLeadership(ctx context.Context) error {
+	if server.etcdClient == nil {
+		return errors.New("etcd client is not initialized")
+	}
+
+	resp, err := server.etcdClient.MemberList(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, member := range resp.Members {
+		if member.Name == server.thisNode.Name {
+			if uint64(server.etcd.Server.Leader()) != member.ID {
+				return nil
+			}
+			return moveLeader(ctx, resp, i)
+		}
+	}
+
+	return errors.New("Node with name <" + server.thisNode.Name + "> is not a member")
+}
+
+// moveLeader transfers leadership from the leader, which must be the member at index leader, to the first other
+// started member
+func moveLeader(ctx context.Context, resp *clientv3.MemberListResponse, leader int) error {
+	members := resp.Members
+	transferee := -1
+	for i, member := range members {
+		if i != leader && member.Name != "" {
+			transferee = i
+			break
+		}
+	}
+	if transferee == -1 {
+		return errors.New("no other member has started")
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   members[leader].ClientURLs,
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = client.MoveLeader(ctx, members[transferee].ID)
+	client.Close()
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{"From": members[leader].Name, "To": members[transferee].Name}).Info("Transferred etcd leadership")
+
+	return nil
+}
+
 func (server *EtcdServer) CurrentCluster() Config {
 	nodes := server.Members()
 	leader := server.Leader()
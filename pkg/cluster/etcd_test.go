@@ -1,8 +1,10 @@
 package cluster
 
 import (
+	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -53,6 +55,11 @@ func TestCreateEtcdCluster(t *testing.T) {
 	currentCluster := server4.CurrentCluster()
 	assert.Len(t, currentCluster.Nodes, 4)
 
+	assert.True(t, server1.IsMember())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	assert.Nil(t, server1.CheckQuorum(ctx))
+	cancel()
+
 	server1.Stop()
 	server2.Stop()
 	server3.Stop()
@@ -206,3 +213,63 @@ func TestEtcdAssignmentsPauseResumeMultiNode(t *testing.T) {
 	os.RemoveAll(server1.StorageDir())
 	os.RemoveAll(server2.StorageDir())
 }
+
+func TestEtcdAddRemoveMember(t *testing.T) {
+	node1 := Node{Name: "etcd1", Host: "localhost", EtcdClientPort: 24900, EtcdPeerPort: 23900, RelayPort: 25900, APIPort: 26900}
+	node2 := Node{Name: "etcd2", Host: "localhost", EtcdClientPort: 24950, EtcdPeerPort: 23950, RelayPort: 25950, APIPort: 26950}
+
+	config := Config{}
+	config.AddNode(node1)
+
+	server1 := CreateEtcdServer(node1, config, ".")
+	server1.Start()
+	server1.WaitToStart()
+	assert.Equal(t, "etcd1", server1.Leader())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	assert.Nil(t, server1.AddMember(ctx, node2))
+	assert.NotNil(t, server1.AddMember(ctx, node2)) // Already a member
+	assert.Len(t, server1.Members(), 2)             // Matched by peer URL until etcd2 has started
+
+	joinConfig := Config{Join: true}
+	joinConfig.AddNode(node1)
+	joinConfig.AddNode(node2)
+	server2 := CreateEtcdServer(node2, joinConfig, ".")
+	server2.Start()
+	server2.WaitToStart()
+
+	assert.Len(t, server1.Members(), 2)
+	assert.Len(t, server2.Members(), 2)
+	assert.Equal(t, server1.Leader(), server2.Leader())
+
+	// etcd refuses to remove members until the remaining members have been connected for its health interval (5s)
+	time.Sleep(6 * time.Second)
+
+	// Remove the leader, leadership must be transferred to etcd2
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	assert.NotNil(t, server2.RemoveMember(ctx, "does_not_exist"))
+	assert.Nil(t, server2.RemoveMember(ctx, "etcd1"))
+
+	assert.Equal(t, "etcd2", server2.Leader())
+	assert.Len(t, server2.Members(), 1)
+	assert.True(t, server2.IsMember())
+
+	for i := 0; i < 50 && server1.IsMember(); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.False(t, server1.IsMember())
+	assert.Equal(t, "", server1.Leader())
+
+	assert.NotNil(t, server2.RemoveMember(ctx, "etcd2")) // Last member
+
+	server1.Stop()
+	server2.Stop()
+	server1.WaitToStop()
+	server2.WaitToStop()
+
+	os.RemoveAll(server1.StorageDir())
+	os.RemoveAll(server2.StorageDir())
+}
@@ -0,0 +1,151 @@
+package cluster
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	relayBufferSize = 10000 // Max unacknowledged messages per node, the oldest messages are dropped when it is full
+	relayBatchSize  = 100   // Max messages sent in one request
+	relayMinBackoff = 100 * time.Millisecond
+	relayMaxBackoff = 5 * time.Second
+	relayTimeout    = 10 * time.Second
+)
+
+// relayMessage is sequenced per receiving node, so that the receiver can drop duplicates when a batch is resent
+type relayMessage struct {
+	Seq     uint64    `json:"seq"`
+	Payload []byte    `json:"payload"`
+	queued  time.Time // When the message was broadcasted, used to measure relay lag
+}
+
+// relayBatch is posted to /relay. The epoch identifies a RelayServer instance, sequence numbers start over when a
+// server is restarted.
+type relayBatch struct {
+	From     string          `json:"from"`
+	Epoch    string          `json:"epoch"`
+	Messages []*relayMessage `json:"messages"`
+}
+
+// relayAck is the reply to a relayBatch, all messages up to and including Seq have been delivered
+type relayAck struct {
+	Seq uint64 `json:"seq"`
+}
+
+// RelayStats describes the delivery of messages to another node
+type RelayStats struct {
+	Node      string
+	Pending   int           // Messages not yet acknowledged
+	Lag       time.Duration // Age of the oldest pending message
+	Delivered uint64        // Messages acknowledged by the node
+	Failures  uint64        // Failed delivery attempts
+	Dropped   uint64        // Messages dropped since the buffer was full
+	LastError string
+}
+
+// relayPeer buffers messages to another node until they have been acknowledged. Messages are resent with backoff,
+// so a node that is briefly down catches up on the messages it missed when it comes back.
+type relayPeer struct {
+	node      Node
+	mutex     sync.Mutex
+	queue     []*relayMessage
+	nextSeq   uint64
+	delivered uint64
+	failures  uint64
+	dropped   uint64
+	lastError string
+	notify    chan struct{}
+	stop      chan struct{}
+}
+
+func createRelayPeer(node Node) *relayPeer {
+	return &relayPeer{node: node,
+		nextSeq: 1,
+		notify:  make(chan struct{}, 1),
+		stop:    make(chan struct{})}
+}
+
+func (peer *relayPeer) getNode() Node {
+	peer.mutex.Lock()
+	defer peer.mutex.Unlock()
+
+	return peer.node
+}
+
+func (peer *relayPeer) setNode(node Node) {
+	peer.mutex.Lock()
+	defer peer.mutex.Unlock()
+
+	peer.node = node
+}
+
+func (peer *relayPeer) enqueue(payload []byte) {
+	peer.mutex.Lock()
+	if len(peer.queue) >= relayBufferSize {
+		peer.queue = peer.queue[1:]
+		peer.dropped++
+	}
+	peer.queue = append(peer.queue, &relayMessage{Seq: peer.nextSeq, Payload: payload, queued: time.Now()})
+	peer.nextSeq++
+	peer.mutex.Unlock()
+
+	select {
+	case peer.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pending returns the oldest unacknowledged messages
+func (peer *relayPeer) pending() []*relayMessage {
+	peer.mutex.Lock()
+	defer peer.mutex.Unlock()
+
+	n := len(peer.queue)
+	if n > relayBatchSize {
+		n = relayBatchSize
+	}
+	batch := make([]*relayMessage, n)
+	copy(batch, peer.queue[:n])
+
+	return batch
+}
+
+// acknowledge removes all messages up to and including seq
+func (peer *relayPeer) acknowledge(seq uint64) {
+	peer.mutex.Lock()
+	defer peer.mutex.Unlock()
+
+	i := 0
+	for i < len(peer.queue) && peer.queue[i].Seq <= seq {
+		i++
+	}
+	peer.delivered += uint64(i)
+	peer.queue = peer.queue[i:]
+	peer.lastError = ""
+}
+
+func (peer *relayPeer) failed(err error) {
+	peer.mutex.Lock()
+	defer peer.mutex.Unlock()
+
+	peer.failures++
+	peer.lastError = err.Error()
+}
+
+func (peer *relayPeer) stats() RelayStats {
+	peer.mutex.Lock()
+	defer peer.mutex.Unlock()
+
+	stats := RelayStats{Node: peer.node.Name,
+		Pending:   len(peer.queue),
+		Delivered: peer.delivered,
+		Failures:  peer.failures,
+		Dropped:   peer.dropped,
+		LastError: peer.lastError}
+	if len(peer.queue) > 0 {
+		stats.Lag = time.Since(peer.queue[0].queued)
+	}
+
+	return stats
+}
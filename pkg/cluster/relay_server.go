@@ -2,10 +2,15 @@ package cluster
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -19,8 +24,27 @@ type RelayServer struct {
 	httpServer    *http.Server
 	restyClient   *resty.Client
 	clusterConfig Config
+	nodesMutex    sync.Mutex
+	peers         map[string]*relayPeer
 	thisNode      Node
+	epoch         string
 	incoming      chan []byte
+	received      map[string]*relayReceived
+	receivedMutex sync.Mutex
+	serveErr      error
+	serveErrMutex sync.Mutex
+}
+
+// relayReceived keeps track of the last message delivered from another node
+type relayReceived struct {
+	epoch string
+	seq   uint64
+}
+
+func generateEpoch() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
 }
 
 func CreateRelayServer(thisNode Node, clusterConfig Config) *RelayServer {
@@ -28,9 +52,13 @@ func CreateRelayServer(thisNode Node, clusterConfig Config) *RelayServer {
 	server.ginHandler = gin.Default()
 	server.ginHandler.Use(cors.Default())
 	server.restyClient = resty.New()
-	server.clusterConfig = clusterConfig
+	server.restyClient.SetTimeout(relayTimeout)
 	server.thisNode = thisNode
+	server.epoch = generateEpoch()
 	server.incoming = make(chan []byte)
+	server.received = make(map[string]*relayReceived)
+	server.peers = make(map[string]*relayPeer)
+	server.SetNodes(clusterConfig.Nodes)
 
 	httpServer := &http.Server{
 		Addr:    ":" + strconv.Itoa(thisNode.RelayPort),
@@ -45,13 +73,25 @@ func CreateRelayServer(thisNode Node, clusterConfig Config) *RelayServer {
 }
 
 func (server *RelayServer) serveForever() error {
-	if err := server.httpServer.ListenAndServe(); err != nil && errors.Is(err, http.ErrServerClosed) {
+	if err := server.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.WithFields(log.Fields{"Error": err, "RelayPort": server.thisNode.RelayPort}).Error("RelayServer failed")
+		server.serveErrMutex.Lock()
+		server.serveErr = err
+		server.serveErrMutex.Unlock()
 		return err
 	}
 
 	return nil
 }
 
+// Err returns the error that made the RelayServer stop serving, or nil if it is serving
+func (server *RelayServer) Err() error {
+	server.serveErrMutex.Lock()
+	defer server.serveErrMutex.Unlock()
+
+	return server.serveErr
+}
+
 func (server *RelayServer) setupRoutes() {
 	server.ginHandler.POST("/relay", server.handleRelayRequest)
 }
@@ -62,27 +102,170 @@ func (server *RelayServer) handleRelayRequest(c *gin.Context) {
 		errMsg := "Bad relay request"
 		log.WithFields(log.Fields{"Error": err}).Error(errMsg)
 		c.String(http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var batch relayBatch
+	if err := json.Unmarshal(jsonBytes, &batch); err != nil || batch.From == "" {
+		// Unsequenced message from a server that does not batch messages
+		server.incoming <- jsonBytes
+		c.String(http.StatusOK, "")
+		return
+	}
+
+	server.receivedMutex.Lock()
+	received, ok := server.received[batch.From]
+	if !ok || received.epoch != batch.Epoch {
+		received = &relayReceived{epoch: batch.Epoch}
+		server.received[batch.From] = received
+	}
+	server.receivedMutex.Unlock()
+
+	// Only one request at a time is sent from each node, so messages from a node are delivered in order
+	for _, msg := range batch.Messages {
+		server.receivedMutex.Lock()
+		seq := received.seq
+		server.receivedMutex.Unlock()
+		if msg.Seq <= seq {
+			continue // Already delivered, the sender did not get the acknowledgement
+		}
+
+		select {
+		case server.incoming <- msg.Payload:
+		case <-c.Request.Context().Done():
+			return
+		}
+
+		server.receivedMutex.Lock()
+		received.seq = msg.Seq
+		server.receivedMutex.Unlock()
+	}
+
+	server.receivedMutex.Lock()
+	ack := relayAck{Seq: received.seq}
+	server.receivedMutex.Unlock()
+
+	c.JSON(http.StatusOK, ack)
+}
+
+// SetNodes replaces the nodes messages are broadcasted to, e.g. when nodes join or leave the cluster. Messages that
+// have not been delivered to a node that left are dropped.
+func (server *RelayServer) SetNodes(nodes []Node) {
+	server.nodesMutex.Lock()
+	defer server.nodesMutex.Unlock()
+
+	server.clusterConfig.Nodes = nodes
+
+	names := make(map[string]bool)
+	for _, node := range nodes {
+		if node.Name == server.thisNode.Name {
+			continue
+		}
+		names[node.Name] = true
+		if peer, ok := server.peers[node.Name]; ok {
+			peer.setNode(node)
+			continue
+		}
+		peer := createRelayPeer(node)
+		server.peers[node.Name] = peer
+		go server.deliverForever(peer)
+	}
+
+	for name, peer := range server.peers {
+		if !names[name] {
+			close(peer.stop)
+			delete(server.peers, name)
+		}
 	}
+}
 
-	server.incoming <- jsonBytes
+func (server *RelayServer) Nodes() []Node {
+	server.nodesMutex.Lock()
+	defer server.nodesMutex.Unlock()
 
-	c.String(http.StatusOK, "")
+	nodes := make([]Node, len(server.clusterConfig.Nodes))
+	copy(nodes, server.clusterConfig.Nodes)
+
+	return nodes
 }
 
-// Send a message to all ReplayServers in the Cluster
+// Broadcast queues a message to all other RelayServers in the cluster. Messages are delivered in order and resent
+// until they have been acknowledged.
 func (server *RelayServer) Broadcast(msg []byte) error {
-	for _, node := range server.clusterConfig.Nodes {
-		if node.Name != server.thisNode.Name {
-			_, err := server.restyClient.R().
-				SetBody(msg).
-				Post("http://" + node.Host + ":" + strconv.Itoa(node.RelayPort) + "/relay")
-			if err != nil {
-				return err
+	server.nodesMutex.Lock()
+	defer server.nodesMutex.Unlock()
+
+	for _, peer := range server.peers {
+		peer.enqueue(msg)
+	}
+
+	return nil
+}
+
+func (server *RelayServer) deliverForever(peer *relayPeer) {
+	backoff := relayMinBackoff
+	for {
+		batch := peer.pending()
+		if len(batch) == 0 {
+			select {
+			case <-peer.notify:
+				continue
+			case <-peer.stop:
+				return
 			}
 		}
+
+		node := peer.getNode()
+		ack, err := server.send(node, batch)
+		if err != nil {
+			peer.failed(err)
+			log.WithFields(log.Fields{"Error": err, "Node": node.Name, "Pending": peer.stats().Pending}).Debug("Failed to relay messages, retrying")
+			select {
+			case <-peer.stop:
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > relayMaxBackoff {
+				backoff = relayMaxBackoff
+			}
+			continue
+		}
+
+		backoff = relayMinBackoff
+		peer.acknowledge(ack.Seq)
 	}
+}
 
-	return nil
+func (server *RelayServer) send(node Node, messages []*relayMessage) (*relayAck, error) {
+	batch := &relayBatch{From: server.thisNode.Name, Epoch: server.epoch, Messages: messages}
+	resp, err := server.restyClient.R().
+		SetBody(batch).
+		SetResult(&relayAck{}).
+		Post("http://" + node.Host + ":" + strconv.Itoa(node.RelayPort) + "/relay")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, errors.New("Relay to <" + node.Name + "> failed with status code " + strconv.Itoa(resp.StatusCode()))
+	}
+
+	return resp.Result().(*relayAck), nil
+}
+
+// Stats returns delivery statistics for every other node in the cluster
+func (server *RelayServer) Stats() []RelayStats {
+	server.nodesMutex.Lock()
+	var stats []RelayStats
+	for _, peer := range server.peers {
+		stats = append(stats, peer.stats())
+	}
+	server.nodesMutex.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Node < stats[j].Node })
+
+	return stats
 }
 
 func (server *RelayServer) Receive() chan []byte {
@@ -90,6 +273,13 @@ func (server *RelayServer) Receive() chan []byte {
 }
 
 func (server *RelayServer) Shutdown() { // TODO: unittest
+	server.nodesMutex.Lock()
+	for name, peer := range server.peers {
+		close(peer.stop)
+		delete(server.peers, name)
+	}
+	server.nodesMutex.Unlock()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
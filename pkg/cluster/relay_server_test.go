@@ -2,7 +2,9 @@ package cluster
 
 import (
 	"io/ioutil"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -124,3 +126,151 @@ func TestRelayServer(t *testing.T) {
 	assert.Equal(t, relayServer3Received["relayserver2"], 1)
 	assert.Len(t, relayServer2Received, 2)
 }
+
+func TestRelayServerErr(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	gin.DefaultWriter = ioutil.Discard
+
+	node := Node{Name: "etcd1", Host: "localhost", EtcdClientPort: 24100, EtcdPeerPort: 23100, RelayPort: 25100, APIPort: 26100}
+	config := Config{}
+	config.AddNode(node)
+
+	relayServer1 := CreateRelayServer(node, config)
+	defer relayServer1.Shutdown()
+	time.Sleep(500 * time.Millisecond)
+	assert.Nil(t, relayServer1.Err())
+
+	relayServer2 := CreateRelayServer(node, config) // Port already in use
+	defer relayServer2.Shutdown()
+	time.Sleep(500 * time.Millisecond)
+	assert.NotNil(t, relayServer2.Err())
+}
+
+func TestRelayServerSetNodes(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	gin.DefaultWriter = ioutil.Discard
+
+	node1 := Node{Name: "etcd1", Host: "localhost", EtcdClientPort: 24910, EtcdPeerPort: 23910, RelayPort: 25910, APIPort: 26910}
+	node2 := Node{Name: "etcd2", Host: "localhost", EtcdClientPort: 24920, EtcdPeerPort: 23920, RelayPort: 25920, APIPort: 26920}
+
+	config := Config{}
+	config.AddNode(node1)
+
+	relayServer := CreateRelayServer(node1, config)
+	defer relayServer.Shutdown()
+
+	assert.Len(t, relayServer.Nodes(), 1)
+
+	relayServer.SetNodes([]Node{node1, node2})
+	nodes := relayServer.Nodes()
+	assert.Len(t, nodes, 2)
+	assert.True(t, nodes[1].Equals(&node2))
+
+	relayServer.SetNodes([]Node{node1})
+	assert.Len(t, relayServer.Nodes(), 1)
+}
+
+func TestRelayServerCatchUp(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	gin.DefaultWriter = ioutil.Discard
+
+	node1 := Node{Name: "etcd1", Host: "localhost", EtcdClientPort: 24930, EtcdPeerPort: 23930, RelayPort: 25930, APIPort: 26930}
+	node2 := Node{Name: "etcd2", Host: "localhost", EtcdClientPort: 24940, EtcdPeerPort: 23940, RelayPort: 25940, APIPort: 26940}
+
+	config := Config{}
+	config.AddNode(node1)
+	config.AddNode(node2)
+
+	relayServer1 := CreateRelayServer(node1, config)
+	defer relayServer1.Shutdown()
+
+	// etcd2 is down, messages are buffered until it comes back
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, relayServer1.Broadcast([]byte(strconv.Itoa(i))))
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	stats := relayServer1.Stats()
+	assert.Len(t, stats, 1)
+	assert.Equal(t, "etcd2", stats[0].Node)
+	assert.Equal(t, 10, stats[0].Pending)
+	assert.Greater(t, stats[0].Failures, uint64(0))
+	assert.Greater(t, stats[0].Lag, time.Duration(0))
+	assert.NotEqual(t, "", stats[0].LastError)
+
+	relayServer2 := CreateRelayServer(node2, config)
+	defer relayServer2.Shutdown()
+
+	incoming := relayServer2.Receive()
+	for i := 0; i < 10; i++ {
+		select {
+		case msg := <-incoming:
+			assert.Equal(t, strconv.Itoa(i), string(msg)) // Delivered in order
+		case <-time.After(10 * time.Second):
+			assert.Fail(t, "Timeout waiting for relayed message")
+			return
+		}
+	}
+
+	for i := 0; i < 50 && relayServer1.Stats()[0].Pending > 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	stats = relayServer1.Stats()
+	assert.Equal(t, 0, stats[0].Pending)
+	assert.Equal(t, uint64(10), stats[0].Delivered)
+	assert.Equal(t, time.Duration(0), stats[0].Lag)
+	assert.Equal(t, "", stats[0].LastError)
+}
+
+func TestRelayServerDuplicates(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	gin.DefaultWriter = ioutil.Discard
+
+	node1 := Node{Name: "etcd1", Host: "localhost", EtcdClientPort: 24970, EtcdPeerPort: 23970, RelayPort: 25970, APIPort: 26970}
+	node2 := Node{Name: "etcd2", Host: "localhost", EtcdClientPort: 24980, EtcdPeerPort: 23980, RelayPort: 25980, APIPort: 26980}
+
+	relayServer2 := CreateRelayServer(node2, Config{Nodes: []Node{node2}})
+	defer relayServer2.Shutdown()
+
+	var received []string
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case msg := <-relayServer2.Receive():
+				received = append(received, string(msg))
+			case <-done:
+				close(finished)
+				return
+			}
+		}
+	}()
+
+	// A sender that is not part of the cluster, so that batches can be sent explicitly
+	sender := CreateRelayServer(node1, Config{Nodes: []Node{node1}})
+	defer sender.Shutdown()
+	time.Sleep(200 * time.Millisecond)
+
+	batch := []*relayMessage{{Seq: 1, Payload: []byte("a")}, {Seq: 2, Payload: []byte("b")}}
+	ack, err := sender.send(node2, batch)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), ack.Seq)
+
+	// The acknowledgement was lost, the batch is resent together with a new message
+	batch = append(batch, &relayMessage{Seq: 3, Payload: []byte("c")})
+	ack, err = sender.send(node2, batch)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), ack.Seq)
+
+	// The sender restarts, sequence numbers start over
+	sender.epoch = generateEpoch()
+	ack, err = sender.send(node2, []*relayMessage{{Seq: 1, Payload: []byte("d")}})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), ack.Seq)
+
+	time.Sleep(100 * time.Millisecond)
+	close(done)
+	<-finished
+	assert.Equal(t, []string{"a", "b", "c", "d"}, received)
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WaitingProcessesAlert = "waitingprocesses" // Fires when more than Threshold processes are waiting
+	NoExecutorsAlert      = "noexecutors"      // Fires when at most Threshold executors are alive
+	FailureRateAlert      = "failurerate"      // Fires when the failure rate of function runs is above Threshold
+)
+
+const (
+	AlertOK      = "ok"
+	AlertPending = "pending"
+	AlertFiring  = "firing"
+)
+
+// AlertRule is evaluated periodically by the leader. When the rule condition has held for For seconds, the alert
+// fires and an alert.firing event is published. When the condition no longer holds, the alert is resolved and an
+// alert.resolved event is published. ExecutorType and FuncName restrict the rule to processes, executors or function
+// runs of that executor type and function, an empty value matches all.
+type AlertRule struct {
+	ID           string    `json:"alertruleid"`
+	ColonyName   string    `json:"colonyname"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	ExecutorType string    `json:"executortype"`
+	FuncName     string    `json:"funcname"`
+	Threshold    float64   `json:"threshold"`
+	For          int64     `json:"for"`    // Seconds the condition must hold before the alert fires
+	Window       int64     `json:"window"` // Seconds of function runs the failure rate is calculated from
+	State        string    `json:"state"`
+	Value        float64   `json:"value"`
+	PendingSince time.Time `json:"pendingsince"`
+	FiredTime    time.Time `json:"firedtime"`
+	ResolvedTime time.Time `json:"resolvedtime"`
+	Evaluated    time.Time `json:"evaluated"`
+	Added        time.Time `json:"added"`
+}
+
+func IsValidAlertRuleType(ruleType string) bool {
+	switch ruleType {
+	case WaitingProcessesAlert, NoExecutorsAlert, FailureRateAlert:
+		return true
+	}
+
+	return false
+}
+
+func CreateAlertRule(colonyName string, name string, ruleType string, executorType string, funcName string, threshold float64, forSeconds int64, window int64) *AlertRule {
+	return &AlertRule{
+		ColonyName:   colonyName,
+		Name:         name,
+		Type:         ruleType,
+		ExecutorType: executorType,
+		FuncName:     funcName,
+		Threshold:    threshold,
+		For:          forSeconds,
+		Window:       window,
+		State:        AlertOK,
+	}
+}
+
+func ConvertJSONToAlertRule(jsonString string) (*AlertRule, error) {
+	var rule *AlertRule
+	err := json.Unmarshal([]byte(jsonString), &rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func ConvertJSONToAlertRuleArray(jsonString string) ([]*AlertRule, error) {
+	var rules []*AlertRule
+	err := json.Unmarshal([]byte(jsonString), &rules)
+	if err != nil {
+		return rules, err
+	}
+
+	return rules, nil
+}
+
+func ConvertAlertRuleArrayToJSON(rules []*AlertRule) (string, error) {
+	jsonBytes, err := json.Marshal(rules)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+// Breached returns true if the rule condition holds for the measured value
+func (rule *AlertRule) Breached(value float64) bool {
+	switch rule.Type {
+	case NoExecutorsAlert:
+		return value <= rule.Threshold
+	case WaitingProcessesAlert, FailureRateAlert:
+		return value > rule.Threshold
+	}
+
+	return false
+}
+
+// Evaluate updates the state of the rule with a value measured at now, and returns alert.firing or alert.resolved if
+// the alert fired or was resolved, otherwise an empty string
+func (rule *AlertRule) Evaluate(value float64, now time.Time) string {
+	rule.Value = value
+	rule.Evaluated = now
+
+	if !rule.Breached(value) {
+		rule.PendingSince = time.Time{}
+		if rule.State == AlertFiring {
+			rule.State = AlertOK
+			rule.ResolvedTime = now
+			return AlertResolvedEvent
+		}
+		rule.State = AlertOK
+		return ""
+	}
+
+	if rule.PendingSince.IsZero() {
+		rule.PendingSince = now
+	}
+
+	if rule.State == AlertFiring {
+		return ""
+	}
+
+	if now.Sub(rule.PendingSince) >= time.Duration(rule.For)*time.Second {
+		rule.State = AlertFiring
+		rule.FiredTime = now
+		return AlertFiringEvent
+	}
+
+	rule.State = AlertPending
+
+	return ""
+}
+
+func (rule *AlertRule) Equals(rule2 *AlertRule) bool {
+	if rule2 == nil {
+		return false
+	}
+
+	same := true
+	if rule.ID != rule2.ID ||
+		rule.ColonyName != rule2.ColonyName ||
+		rule.Name != rule2.Name ||
+		rule.Type != rule2.Type ||
+		rule.ExecutorType != rule2.ExecutorType ||
+		rule.FuncName != rule2.FuncName ||
+		rule.Threshold != rule2.Threshold ||
+		rule.For != rule2.For ||
+		rule.Window != rule2.Window ||
+		rule.State != rule2.State ||
+		rule.Value != rule2.Value {
+		same = false
+	}
+
+	return same
+}
+
+func (rule *AlertRule) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(rule)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidAlertRuleType(t *testing.T) {
+	assert.True(t, IsValidAlertRuleType(WaitingProcessesAlert))
+	assert.True(t, IsValidAlertRuleType(NoExecutorsAlert))
+	assert.True(t, IsValidAlertRuleType(FailureRateAlert))
+	assert.False(t, IsValidAlertRuleType("invalid"))
+}
+
+func TestAlertRuleEquals(t *testing.T) {
+	rule1 := CreateAlertRule("test_colony", "test_rule", WaitingProcessesAlert, "test_executor_type", "", 10, 60, 0)
+	rule2 := CreateAlertRule("test_colony", "test_rule", WaitingProcessesAlert, "test_executor_type", "", 10, 60, 0)
+
+	assert.True(t, rule1.Equals(rule2))
+	assert.False(t, rule1.Equals(nil))
+
+	rule2.Threshold = 20
+	assert.False(t, rule1.Equals(rule2))
+}
+
+func TestAlertRuleToJSON(t *testing.T) {
+	rule1 := CreateAlertRule("test_colony", "test_rule", FailureRateAlert, "", "test_func", 0.5, 60, 3600)
+	rule1.ID = GenerateRandomID()
+	rule1.Added = time.Now()
+
+	jsonStr, err := rule1.ToJSON()
+	assert.Nil(t, err)
+
+	rule2, err := ConvertJSONToAlertRule(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, rule1.Equals(rule2))
+
+	_, err = ConvertJSONToAlertRule("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestAlertRuleArrayToJSON(t *testing.T) {
+	rules := []*AlertRule{
+		CreateAlertRule("test_colony", "test_rule1", WaitingProcessesAlert, "", "", 10, 0, 0),
+		CreateAlertRule("test_colony", "test_rule2", NoExecutorsAlert, "test_executor_type", "", 0, 60, 0),
+	}
+
+	jsonStr, err := ConvertAlertRuleArrayToJSON(rules)
+	assert.Nil(t, err)
+
+	rules2, err := ConvertJSONToAlertRuleArray(jsonStr)
+	assert.Nil(t, err)
+	assert.Len(t, rules2, 2)
+	assert.True(t, rules[0].Equals(rules2[0]))
+	assert.True(t, rules[1].Equals(rules2[1]))
+}
+
+func TestAlertRuleBreached(t *testing.T) {
+	rule := CreateAlertRule("test_colony", "test_rule", WaitingProcessesAlert, "", "", 10, 0, 0)
+	assert.False(t, rule.Breached(10))
+	assert.True(t, rule.Breached(11))
+
+	rule = CreateAlertRule("test_colony", "test_rule", NoExecutorsAlert, "", "", 0, 0, 0)
+	assert.True(t, rule.Breached(0))
+	assert.False(t, rule.Breached(1))
+
+	rule = CreateAlertRule("test_colony", "test_rule", FailureRateAlert, "", "", 0.1, 0, 3600)
+	assert.False(t, rule.Breached(0.1))
+	assert.True(t, rule.Breached(0.2))
+}
+
+func TestAlertRuleEvaluate(t *testing.T) {
+	rule := CreateAlertRule("test_colony", "test_rule", WaitingProcessesAlert, "", "", 10, 60, 0)
+	now := time.Now()
+
+	assert.Equal(t, "", rule.Evaluate(5, now))
+	assert.Equal(t, AlertOK, rule.State)
+
+	// The condition must hold for 60 seconds before the alert fires
+	assert.Equal(t, "", rule.Evaluate(20, now))
+	assert.Equal(t, AlertPending, rule.State)
+	assert.Equal(t, "", rule.Evaluate(20, now.Add(30*time.Second)))
+	assert.Equal(t, AlertPending, rule.State)
+	assert.Equal(t, AlertFiringEvent, rule.Evaluate(20, now.Add(60*time.Second)))
+	assert.Equal(t, AlertFiring, rule.State)
+	assert.Equal(t, float64(20), rule.Value)
+	assert.True(t, rule.FiredTime.Equal(now.Add(60*time.Second)))
+
+	// A firing alert only fires once
+	assert.Equal(t, "", rule.Evaluate(30, now.Add(90*time.Second)))
+	assert.Equal(t, AlertFiring, rule.State)
+
+	assert.Equal(t, AlertResolvedEvent, rule.Evaluate(5, now.Add(120*time.Second)))
+	assert.Equal(t, AlertOK, rule.State)
+	assert.True(t, rule.ResolvedTime.Equal(now.Add(120*time.Second)))
+	assert.True(t, rule.PendingSince.IsZero())
+
+	// A pending alert returns to ok without firing if the condition stops holding
+	assert.Equal(t, "", rule.Evaluate(20, now.Add(150*time.Second)))
+	assert.Equal(t, AlertPending, rule.State)
+	assert.Equal(t, "", rule.Evaluate(5, now.Add(160*time.Second)))
+	assert.Equal(t, AlertOK, rule.State)
+	assert.Equal(t, "", rule.Evaluate(20, now.Add(200*time.Second)))
+	assert.Equal(t, AlertPending, rule.State)
+}
+
+func TestAlertRuleEvaluateNoFor(t *testing.T) {
+	rule := CreateAlertRule("test_colony", "test_rule", NoExecutorsAlert, "test_executor_type", "", 0, 0, 0)
+	assert.Equal(t, AlertFiringEvent, rule.Evaluate(0, time.Now()))
+	assert.Equal(t, AlertFiring, rule.State)
+}
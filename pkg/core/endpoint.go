@@ -0,0 +1,88 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/security/crypto"
+)
+
+// ServerEndpoint is published in the DHT by every server, so that clients can find the servers of a colony without
+// knowing their addresses. Endpoints are signed with the server private key and stored under the server ID, see
+// DiscoveryColonyKey and DiscoveryServerKey.
+type ServerEndpoint struct {
+	Name      string    `json:"name"`
+	Host      string    `json:"host"`
+	Port      int       `json:"port"`
+	TLS       bool      `json:"tls"`
+	Published time.Time `json:"published"`
+}
+
+func CreateServerEndpoint(name string, host string, port int, tls bool) *ServerEndpoint {
+	return &ServerEndpoint{Name: name, Host: host, Port: port, TLS: tls}
+}
+
+// DiscoveryColonyKey returns the DHT key under which servers publish their endpoints for a colony. DHT keys may
+// only contain alphanumeric characters, so names are hashed.
+func DiscoveryColonyKey(colonyName string) string {
+	return "/colonies/" + crypto.CreateCrypto().GenerateHash(colonyName)
+}
+
+// DiscoveryServerKey returns the DHT key under which a server publishes its endpoint
+func DiscoveryServerKey(name string) string {
+	return "/servers/" + crypto.CreateCrypto().GenerateHash(name)
+}
+
+func ConvertJSONToServerEndpoint(jsonString string) (*ServerEndpoint, error) {
+	var endpoint *ServerEndpoint
+	err := json.Unmarshal([]byte(jsonString), &endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+func ConvertJSONToServerEndpointArray(jsonString string) ([]*ServerEndpoint, error) {
+	var endpoints []*ServerEndpoint
+	err := json.Unmarshal([]byte(jsonString), &endpoints)
+	if err != nil {
+		return endpoints, err
+	}
+
+	return endpoints, nil
+}
+
+func ConvertServerEndpointArrayToJSON(endpoints []*ServerEndpoint) (string, error) {
+	jsonBytes, err := json.Marshal(endpoints)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (endpoint *ServerEndpoint) Equals(endpoint2 *ServerEndpoint) bool {
+	if endpoint2 == nil {
+		return false
+	}
+
+	same := true
+	if endpoint.Name != endpoint2.Name ||
+		endpoint.Host != endpoint2.Host ||
+		endpoint.Port != endpoint2.Port ||
+		endpoint.TLS != endpoint2.TLS {
+		same = false
+	}
+
+	return same
+}
+
+func (endpoint *ServerEndpoint) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerEndpointEquals(t *testing.T) {
+	endpoint1 := CreateServerEndpoint("server1", "localhost", 50080, true)
+	endpoint2 := CreateServerEndpoint("server1", "localhost", 50080, true)
+
+	assert.True(t, endpoint1.Equals(endpoint2))
+	assert.False(t, endpoint1.Equals(nil))
+
+	endpoint2.Port = 50081
+	assert.False(t, endpoint1.Equals(endpoint2))
+}
+
+func TestServerEndpointToJSON(t *testing.T) {
+	endpoint1 := CreateServerEndpoint("server1", "localhost", 50080, false)
+	endpoint1.Published = time.Now()
+
+	jsonStr, err := endpoint1.ToJSON()
+	assert.Nil(t, err)
+
+	endpoint2, err := ConvertJSONToServerEndpoint(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, endpoint1.Equals(endpoint2))
+
+	_, err = ConvertJSONToServerEndpoint("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestServerEndpointArrayToJSON(t *testing.T) {
+	endpoints := []*ServerEndpoint{
+		CreateServerEndpoint("server1", "localhost", 50080, false),
+		CreateServerEndpoint("server2", "localhost", 50081, false),
+	}
+
+	jsonStr, err := ConvertServerEndpointArrayToJSON(endpoints)
+	assert.Nil(t, err)
+
+	endpoints2, err := ConvertJSONToServerEndpointArray(jsonStr)
+	assert.Nil(t, err)
+	assert.Len(t, endpoints2, 2)
+	assert.True(t, endpoints[0].Equals(endpoints2[0]))
+	assert.True(t, endpoints[1].Equals(endpoints2[1]))
+}
+
+func TestDiscoveryKeys(t *testing.T) {
+	key := DiscoveryColonyKey("test-colony")
+	assert.Len(t, key, len("/colonies/")+64)
+	assert.Equal(t, key, DiscoveryColonyKey("test-colony"))
+	assert.NotEqual(t, key, DiscoveryColonyKey("test-colony2"))
+
+	key = DiscoveryServerKey("server-1")
+	assert.Len(t, key, len("/servers/")+64)
+}
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	ProcessEventKind      = "process"
+	ProcessGraphEventKind = "processgraph"
+	ExecutorEventKind     = "executor"
+	CronEventKind         = "cron"
+	GeneratorEventKind    = "generator"
+	FileEventKind         = "file"
+	SnapshotEventKind     = "snapshot"
+	FunctionEventKind     = "function"
+	AlertEventKind        = "alert"
+)
+
+const (
+	ProcessWaitingEvent         = "process.waiting"
+	ProcessRunningEvent         = "process.running"
+	ProcessSuccessfulEvent      = "process.successful"
+	ProcessFailedEvent          = "process.failed"
+	ProcessGraphWaitingEvent    = "processgraph.waiting"
+	ProcessGraphRunningEvent    = "processgraph.running"
+	ProcessGraphSuccessfulEvent = "processgraph.successful"
+	ProcessGraphFailedEvent     = "processgraph.failed"
+	ExecutorRegisteredEvent     = "executor.registered"
+	ExecutorApprovedEvent       = "executor.approved"
+	ExecutorRejectedEvent       = "executor.rejected"
+	ExecutorRemovedEvent        = "executor.removed"
+	CronAddedEvent              = "cron.added"
+	CronTriggeredEvent          = "cron.triggered"
+	CronRemovedEvent            = "cron.removed"
+	GeneratorAddedEvent         = "generator.added"
+	GeneratorTriggeredEvent     = "generator.triggered"
+	GeneratorRemovedEvent       = "generator.removed"
+	FileAddedEvent              = "file.added"
+	FileRemovedEvent            = "file.removed"
+	SnapshotCreatedEvent        = "snapshot.created"
+	SnapshotRemovedEvent        = "snapshot.removed"
+	FunctionAnomalyEvent        = "function.anomaly"
+	AlertFiringEvent            = "alert.firing"
+	AlertResolvedEvent          = "alert.resolved"
+)
+
+// Event describes a lifecycle change of an object in a colony. Only the field matching the kind of the event is set.
+// Seq is assigned when the event is stored in the event log, it increases monotonically within a colony.
+type Event struct {
+	Seq          int64         `json:"seq"`
+	Type         string        `json:"type"`
+	ColonyName   string        `json:"colonyname"`
+	Label        string        `json:"label"`
+	ObjectID     string        `json:"objectid"`
+	Time         time.Time     `json:"time"`
+	Process      *Process      `json:"process,omitempty"`
+	ProcessGraph *ProcessGraph `json:"processgraph,omitempty"`
+	Executor     *Executor     `json:"executor,omitempty"`
+	Cron         *Cron         `json:"cron,omitempty"`
+	Generator    *Generator    `json:"generator,omitempty"`
+	File         *File         `json:"file,omitempty"`
+	Snapshot     *Snapshot     `json:"snapshot,omitempty"`
+	FunctionRun  *FunctionRun  `json:"functionrun,omitempty"`
+	AlertRule    *AlertRule    `json:"alertrule,omitempty"`
+}
+
+// EventFilter selects events in a colony. Types may contain event types, e.g. process.failed, or event kinds,
+// e.g. process, which match all event types of that kind. An empty Types or Label matches all events.
+type EventFilter struct {
+	ColonyName string   `json:"colonyname"`
+	Types      []string `json:"types"`
+	Label      string   `json:"label"`
+}
+
+func stateEventType(kind string, state int) string {
+	switch state {
+	case WAITING:
+		return kind + ".waiting"
+	case RUNNING:
+		return kind + ".running"
+	case SUCCESS:
+		return kind + ".successful"
+	case FAILED:
+		return kind + ".failed"
+	}
+
+	return kind + ".unknown"
+}
+
+func createEvent(eventType string, colonyName string, label string, objectID string) *Event {
+	return &Event{Type: eventType, ColonyName: colonyName, Label: label, ObjectID: objectID, Time: time.Now()}
+}
+
+func CreateProcessEvent(process *Process) *Event {
+	event := createEvent(stateEventType(ProcessEventKind, process.State), process.FunctionSpec.Conditions.ColonyName, process.FunctionSpec.Label, process.ID)
+	event.Process = process
+
+	return event
+}
+
+func CreateProcessGraphEvent(graph *ProcessGraph) *Event {
+	event := createEvent(stateEventType(ProcessGraphEventKind, graph.State), graph.ColonyName, "", graph.ID)
+	event.ProcessGraph = graph
+
+	return event
+}
+
+func CreateExecutorEvent(eventType string, executor *Executor) *Event {
+	event := createEvent(eventType, executor.ColonyName, "", executor.ID)
+	event.Executor = executor
+
+	return event
+}
+
+func CreateCronEvent(eventType string, cron *Cron) *Event {
+	event := createEvent(eventType, cron.ColonyName, "", cron.ID)
+	event.Cron = cron
+
+	return event
+}
+
+func CreateGeneratorEvent(eventType string, generator *Generator) *Event {
+	event := createEvent(eventType, generator.ColonyName, "", generator.ID)
+	event.Generator = generator
+
+	return event
+}
+
+func CreateFileEvent(eventType string, file *File) *Event {
+	event := createEvent(eventType, file.ColonyName, file.Label, file.ID)
+	event.File = file
+
+	return event
+}
+
+func CreateSnapshotEvent(eventType string, snapshot *Snapshot) *Event {
+	event := createEvent(eventType, snapshot.ColonyName, snapshot.Label, snapshot.ID)
+	event.Snapshot = snapshot
+
+	return event
+}
+
+// CreateFunctionAnomalyEvent creates an event for a function run that took abnormally long to execute
+func CreateFunctionAnomalyEvent(run *FunctionRun, label string) *Event {
+	event := createEvent(FunctionAnomalyEvent, run.ColonyName, label, run.ProcessID)
+	event.FunctionRun = run
+
+	return event
+}
+
+// CreateAlertEvent creates an event for an alert rule that fired or was resolved
+func CreateAlertEvent(eventType string, rule *AlertRule) *Event {
+	event := createEvent(eventType, rule.ColonyName, "", rule.ID)
+	event.AlertRule = rule
+
+	return event
+}
+
+func ConvertJSONToEvent(jsonString string) (*Event, error) {
+	var event *Event
+	err := json.Unmarshal([]byte(jsonString), &event)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func ConvertJSONToEventArray(jsonString string) ([]*Event, error) {
+	var events []*Event
+	err := json.Unmarshal([]byte(jsonString), &events)
+	if err != nil {
+		return events, err
+	}
+
+	return events, nil
+}
+
+func ConvertEventArrayToJSON(events []*Event) (string, error) {
+	jsonBytes, err := json.Marshal(events)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+// Kind returns the kind of the event, e.g. process for a process.failed event
+func (event *Event) Kind() string {
+	return strings.SplitN(event.Type, ".", 2)[0]
+}
+
+func (event *Event) Equals(event2 *Event) bool {
+	if event2 == nil {
+		return false
+	}
+
+	same := true
+	if event.Seq != event2.Seq ||
+		event.Type != event2.Type ||
+		event.ColonyName != event2.ColonyName ||
+		event.Label != event2.Label ||
+		event.ObjectID != event2.ObjectID ||
+		!event.Time.Equal(event2.Time) {
+		same = false
+	}
+
+	return same
+}
+
+// Compact returns a copy of the event without the inputs, outputs, arguments and environment of a process, or the
+// nodes and edges of a process graph, which is what is stored in the event log. The state transition can still be
+// followed, and the full object can be fetched by its ID.
+func (event *Event) Compact() *Event {
+	eventCopy := *event
+	if event.Process != nil {
+		process := event.Process.Clone()
+		process.Input = nil
+		process.Output = nil
+		process.Attributes = nil
+		process.FunctionSpec.Args = nil
+		process.FunctionSpec.KwArgs = nil
+		process.FunctionSpec.Env = nil
+		eventCopy.Process = process
+	}
+	if event.ProcessGraph != nil {
+		graph := &ProcessGraph{ID: event.ProcessGraph.ID,
+			InitiatorID:    event.ProcessGraph.InitiatorID,
+			InitiatorName:  event.ProcessGraph.InitiatorName,
+			ColonyName:     event.ProcessGraph.ColonyName,
+			Roots:          event.ProcessGraph.Roots,
+			State:          event.ProcessGraph.State,
+			SubmissionTime: event.ProcessGraph.SubmissionTime,
+			StartTime:      event.ProcessGraph.StartTime,
+			EndTime:        event.ProcessGraph.EndTime,
+		}
+		eventCopy.ProcessGraph = graph
+	}
+
+	return &eventCopy
+}
+
+func (event *Event) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (filter *EventFilter) Match(event *Event) bool {
+	if filter.ColonyName != event.ColonyName {
+		return false
+	}
+
+	if filter.Label != "" && filter.Label != event.Label {
+		return false
+	}
+
+	if len(filter.Types) == 0 {
+		return true
+	}
+
+	kind := event.Kind()
+	for _, t := range filter.Types {
+		if t == event.Type || t == kind {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateProcessEvent(t *testing.T) {
+	funcSpec := CreateEmptyFunctionSpec()
+	funcSpec.Conditions.ColonyName = "test_colony"
+	funcSpec.Label = "test_label"
+	process := CreateProcess(funcSpec)
+
+	event := CreateProcessEvent(process)
+	assert.Equal(t, ProcessWaitingEvent, event.Type)
+	assert.Equal(t, ProcessEventKind, event.Kind())
+	assert.Equal(t, "test_colony", event.ColonyName)
+	assert.Equal(t, "test_label", event.Label)
+	assert.Equal(t, process.ID, event.ObjectID)
+	assert.Equal(t, process, event.Process)
+
+	process.State = FAILED
+	assert.Equal(t, ProcessFailedEvent, CreateProcessEvent(process).Type)
+}
+
+func TestCompactEvent(t *testing.T) {
+	funcSpec := CreateEmptyFunctionSpec()
+	funcSpec.Conditions.ColonyName = "test_colony"
+	funcSpec.Args = []interface{}{"test_arg"}
+	funcSpec.Env["test_key"] = "test_value"
+	process := CreateProcess(funcSpec)
+	process.Output = []interface{}{"test_output"}
+
+	event := CreateProcessEvent(process)
+	event.Seq = 42
+	compacted := event.Compact()
+	assert.True(t, event.Equals(compacted))
+	assert.Equal(t, process.ID, compacted.Process.ID)
+	assert.Equal(t, process.State, compacted.Process.State)
+	assert.Nil(t, compacted.Process.FunctionSpec.Args)
+	assert.Nil(t, compacted.Process.FunctionSpec.Env)
+	assert.Nil(t, compacted.Process.Output)
+
+	// The original event is left untouched
+	assert.Len(t, event.Process.FunctionSpec.Args, 1)
+	assert.Len(t, event.Process.FunctionSpec.Env, 1)
+	assert.Len(t, event.Process.Output, 1)
+
+	graph, err := CreateProcessGraph("test_colony")
+	assert.Nil(t, err)
+	graph.ProcessIDs = []string{"test_processid"}
+
+	compacted = CreateProcessGraphEvent(graph).Compact()
+	assert.Equal(t, graph.ID, compacted.ProcessGraph.ID)
+	assert.Nil(t, compacted.ProcessGraph.ProcessIDs)
+	assert.Len(t, graph.ProcessIDs, 1)
+}
+
+func TestCreateProcessGraphEvent(t *testing.T) {
+	graph, err := CreateProcessGraph("test_colony")
+	assert.Nil(t, err)
+
+	graph.State = SUCCESS
+	event := CreateProcessGraphEvent(graph)
+	assert.Equal(t, ProcessGraphSuccessfulEvent, event.Type)
+	assert.Equal(t, ProcessGraphEventKind, event.Kind())
+	assert.Equal(t, graph.ID, event.ObjectID)
+}
+
+func TestCreateFunctionAnomalyEvent(t *testing.T) {
+	run := &FunctionRun{ProcessID: "test_processid", ColonyName: "test_colony", FuncName: "test_func", ExecutorType: "test_executor_type", ExecTime: 10, Successful: true, Anomaly: true, EndTime: time.Now()}
+
+	event := CreateFunctionAnomalyEvent(run, "test_label")
+	assert.Equal(t, FunctionAnomalyEvent, event.Type)
+	assert.Equal(t, FunctionEventKind, event.Kind())
+	assert.Equal(t, "test_colony", event.ColonyName)
+	assert.Equal(t, "test_label", event.Label)
+	assert.Equal(t, "test_processid", event.ObjectID)
+
+	jsonStr, err := event.ToJSON()
+	assert.Nil(t, err)
+
+	event2, err := ConvertJSONToEvent(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, run.Equals(event2.FunctionRun))
+}
+
+func TestEventToJSON(t *testing.T) {
+	snapshot := &Snapshot{ID: "test_snapshot_id", ColonyName: "test_colony", Label: "test_label", Name: "test_snapshotname", Added: time.Now()}
+	event1 := CreateSnapshotEvent(SnapshotCreatedEvent, snapshot)
+	event1.Seq = 42
+
+	jsonStr, err := event1.ToJSON()
+	assert.Nil(t, err)
+
+	event2, err := ConvertJSONToEvent(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, event1.Equals(event2))
+	assert.True(t, snapshot.Equals(event2.Snapshot))
+	assert.Nil(t, event2.Process)
+
+	assert.Equal(t, int64(42), event2.Seq)
+
+	event2.Seq = 43
+	assert.False(t, event1.Equals(event2))
+
+	event2.Seq = 42
+	event2.Type = SnapshotRemovedEvent
+	assert.False(t, event1.Equals(event2))
+	assert.False(t, event1.Equals(nil))
+
+	_, err = ConvertJSONToEvent("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestEventArrayToJSON(t *testing.T) {
+	file := &File{ID: "test_file_id", ColonyName: "test_colony", Label: "/test_label", Name: "test_file"}
+	events := []*Event{CreateFileEvent(FileAddedEvent, file), CreateFileEvent(FileRemovedEvent, file)}
+
+	jsonStr, err := ConvertEventArrayToJSON(events)
+	assert.Nil(t, err)
+
+	events2, err := ConvertJSONToEventArray(jsonStr)
+	assert.Nil(t, err)
+	assert.Len(t, events2, 2)
+	assert.True(t, events[0].Equals(events2[0]))
+	assert.True(t, events[1].Equals(events2[1]))
+}
+
+func TestEventFilter(t *testing.T) {
+	file := &File{ID: "test_file_id", ColonyName: "test_colony", Label: "/test_label", Name: "test_file"}
+	event := CreateFileEvent(FileAddedEvent, file)
+
+	filter := &EventFilter{ColonyName: "test_colony"}
+	assert.True(t, filter.Match(event))
+
+	filter = &EventFilter{ColonyName: "another_colony"}
+	assert.False(t, filter.Match(event))
+
+	filter = &EventFilter{ColonyName: "test_colony", Types: []string{FileEventKind}}
+	assert.True(t, filter.Match(event))
+
+	filter = &EventFilter{ColonyName: "test_colony", Types: []string{ProcessEventKind, FileAddedEvent}}
+	assert.True(t, filter.Match(event))
+
+	filter = &EventFilter{ColonyName: "test_colony", Types: []string{FileRemovedEvent}}
+	assert.False(t, filter.Match(event))
+
+	filter = &EventFilter{ColonyName: "test_colony", Label: "/test_label"}
+	assert.True(t, filter.Match(event))
+
+	filter = &EventFilter{ColonyName: "test_colony", Label: "/another_label"}
+	assert.False(t, filter.Match(event))
+}
+
+func TestCreateAlertEvent(t *testing.T) {
+	rule := CreateAlertRule("test_colony", "test_rule", WaitingProcessesAlert, "test_executor_type", "", 10, 60, 0)
+	rule.ID = GenerateRandomID()
+
+	event := CreateAlertEvent(AlertFiringEvent, rule)
+	assert.Equal(t, AlertFiringEvent, event.Type)
+	assert.Equal(t, AlertEventKind, event.Kind())
+	assert.Equal(t, "test_colony", event.ColonyName)
+	assert.Equal(t, rule.ID, event.ObjectID)
+
+	jsonStr, err := event.ToJSON()
+	assert.Nil(t, err)
+
+	event2, err := ConvertJSONToEvent(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, rule.Equals(event2.AlertRule))
+}
@@ -4,6 +4,10 @@ import (
 	"encoding/json"
 )
 
+// ServerDrainingMessage is the error message of requests rejected by a server that is draining, clients should
+// retry the request on another server
+const ServerDrainingMessage = "Server is draining, reconnect to another server"
+
 type ColoniesError struct {
 	Status  int
 	Message string
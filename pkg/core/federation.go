@@ -0,0 +1,165 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// FederationPathEnv is the env variable of a forwarded function spec listing the IDs of the servers the process has
+// been forwarded from, it is used to prevent processes from being forwarded in loops
+const FederationPathEnv = "COLONIES_FEDERATION_PATH"
+
+// FederationPeer is another Colonies server a colony is federated with. Waiting processes matching ExecutorTypes
+// and Labels are forwarded to RemoteColonyName on the peer, and the peer may forward processes to the colony. RPCs
+// between the servers are signed with the server private keys, ServerID is the ID of the peer server.
+type FederationPeer struct {
+	ID               string    `json:"federationpeerid"`
+	ColonyName       string    `json:"colonyname"`
+	Name             string    `json:"name"`
+	ServerID         string    `json:"serverid"`
+	Host             string    `json:"host"`
+	Port             int       `json:"port"`
+	TLS              bool      `json:"tls"`
+	RemoteColonyName string    `json:"remotecolonyname"`
+	ExecutorTypes    []string  `json:"executortypes"` // No processes are forwarded to the peer if empty
+	Labels           []string  `json:"labels"`        // Processes with any label are forwarded if empty
+	Added            time.Time `json:"added"`
+}
+
+// FederatedProcess maps a local process to the process it was forwarded as on a peer
+type FederatedProcess struct {
+	ProcessID       string    `json:"processid"`
+	ColonyName      string    `json:"colonyname"`
+	PeerID          string    `json:"peerid"`
+	RemoteProcessID string    `json:"remoteprocessid"`
+	Forwarded       time.Time `json:"forwarded"`
+}
+
+func CreateFederationPeer(colonyName string, name string, serverID string, host string, port int, tls bool, remoteColonyName string, executorTypes []string, labels []string) *FederationPeer {
+	return &FederationPeer{ColonyName: colonyName, Name: name, ServerID: serverID, Host: host, Port: port, TLS: tls, RemoteColonyName: remoteColonyName, ExecutorTypes: executorTypes, Labels: labels}
+}
+
+func CreateFederatedProcess(processID string, colonyName string, peerID string, remoteProcessID string) *FederatedProcess {
+	return &FederatedProcess{ProcessID: processID, ColonyName: colonyName, PeerID: peerID, RemoteProcessID: remoteProcessID}
+}
+
+func ConvertJSONToFederationPeer(jsonString string) (*FederationPeer, error) {
+	var peer *FederationPeer
+	err := json.Unmarshal([]byte(jsonString), &peer)
+	if err != nil {
+		return nil, err
+	}
+
+	return peer, nil
+}
+
+func ConvertJSONToFederationPeerArray(jsonString string) ([]*FederationPeer, error) {
+	var peers []*FederationPeer
+	err := json.Unmarshal([]byte(jsonString), &peers)
+	if err != nil {
+		return peers, err
+	}
+
+	return peers, nil
+}
+
+func ConvertFederationPeerArrayToJSON(peers []*FederationPeer) (string, error) {
+	jsonBytes, err := json.Marshal(peers)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+// GetFederationPath returns the IDs of the servers a process has been forwarded from, the origin server first
+func GetFederationPath(funcSpec *FunctionSpec) []string {
+	if funcSpec.Env == nil || funcSpec.Env[FederationPathEnv] == "" {
+		return []string{}
+	}
+
+	return strings.Split(funcSpec.Env[FederationPathEnv], ",")
+}
+
+func SetFederationPath(funcSpec *FunctionSpec, path []string) {
+	if funcSpec.Env == nil {
+		funcSpec.Env = make(map[string]string)
+	}
+	funcSpec.Env[FederationPathEnv] = strings.Join(path, ",")
+}
+
+// Forwards returns true if a waiting process should be forwarded to the peer. Processes pinned to named executors,
+// processes part of a workflow and processes already forwarded by the peer are never forwarded.
+func (peer *FederationPeer) Forwards(process *Process) bool {
+	if process.ProcessGraphID != "" || len(process.FunctionSpec.Conditions.ExecutorNames) > 0 {
+		return false
+	}
+
+	if !containsString(peer.ExecutorTypes, process.FunctionSpec.Conditions.ExecutorType) {
+		return false
+	}
+
+	if len(peer.Labels) > 0 && !containsString(peer.Labels, process.FunctionSpec.Label) {
+		return false
+	}
+
+	return !containsString(GetFederationPath(&process.FunctionSpec), peer.ServerID)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isStringArrayEqual(values1 []string, values2 []string) bool {
+	if len(values1) != len(values2) {
+		return false
+	}
+
+	for i := range values1 {
+		if values1[i] != values2[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (peer *FederationPeer) Equals(peer2 *FederationPeer) bool {
+	if peer2 == nil {
+		return false
+	}
+
+	if !isStringArrayEqual(peer.ExecutorTypes, peer2.ExecutorTypes) || !isStringArrayEqual(peer.Labels, peer2.Labels) {
+		return false
+	}
+
+	same := true
+	if peer.ID != peer2.ID ||
+		peer.ColonyName != peer2.ColonyName ||
+		peer.Name != peer2.Name ||
+		peer.ServerID != peer2.ServerID ||
+		peer.Host != peer2.Host ||
+		peer.Port != peer2.Port ||
+		peer.TLS != peer2.TLS ||
+		peer.RemoteColonyName != peer2.RemoteColonyName {
+		same = false
+	}
+
+	return same
+}
+
+func (peer *FederationPeer) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(peer)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestFederationPeer() *FederationPeer {
+	return CreateFederationPeer("test_colony", "site2", "test_server_id", "localhost", 50080, true, "test_remote_colony", []string{"test_executor_type"}, []string{})
+}
+
+func TestFederationPeerEquals(t *testing.T) {
+	peer1 := createTestFederationPeer()
+	peer2 := createTestFederationPeer()
+
+	assert.True(t, peer1.Equals(peer2))
+	assert.False(t, peer1.Equals(nil))
+
+	peer2.ExecutorTypes = []string{"another_executor_type"}
+	assert.False(t, peer1.Equals(peer2))
+
+	peer2 = createTestFederationPeer()
+	peer2.ServerID = "another_server_id"
+	assert.False(t, peer1.Equals(peer2))
+}
+
+func TestFederationPeerToJSON(t *testing.T) {
+	peer1 := createTestFederationPeer()
+	peer1.ID = GenerateRandomID()
+
+	jsonStr, err := peer1.ToJSON()
+	assert.Nil(t, err)
+
+	peer2, err := ConvertJSONToFederationPeer(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, peer1.Equals(peer2))
+
+	_, err = ConvertJSONToFederationPeer("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestFederationPeerArrayToJSON(t *testing.T) {
+	peers := []*FederationPeer{createTestFederationPeer(), createTestFederationPeer()}
+	peers[1].Name = "site3"
+
+	jsonStr, err := ConvertFederationPeerArrayToJSON(peers)
+	assert.Nil(t, err)
+
+	peers2, err := ConvertJSONToFederationPeerArray(jsonStr)
+	assert.Nil(t, err)
+	assert.Len(t, peers2, 2)
+	assert.True(t, peers[0].Equals(peers2[0]))
+	assert.True(t, peers[1].Equals(peers2[1]))
+}
+
+func TestFederationPath(t *testing.T) {
+	funcSpec := CreateEmptyFunctionSpec()
+	assert.Len(t, GetFederationPath(funcSpec), 0)
+
+	SetFederationPath(funcSpec, []string{"server1", "server2"})
+	assert.Equal(t, []string{"server1", "server2"}, GetFederationPath(funcSpec))
+
+	funcSpec.Env = nil
+	assert.Len(t, GetFederationPath(funcSpec), 0)
+	SetFederationPath(funcSpec, []string{"server1"})
+	assert.Equal(t, []string{"server1"}, GetFederationPath(funcSpec))
+}
+
+func TestFederationPeerForwards(t *testing.T) {
+	peer := createTestFederationPeer()
+
+	funcSpec := CreateEmptyFunctionSpec()
+	funcSpec.Conditions.ColonyName = "test_colony"
+	funcSpec.Conditions.ExecutorType = "test_executor_type"
+	process := CreateProcess(funcSpec)
+	assert.True(t, peer.Forwards(process))
+
+	process.FunctionSpec.Conditions.ExecutorType = "another_executor_type"
+	assert.False(t, peer.Forwards(process))
+	process.FunctionSpec.Conditions.ExecutorType = "test_executor_type"
+
+	peer.Labels = []string{"gpu"}
+	assert.False(t, peer.Forwards(process))
+	process.FunctionSpec.Label = "gpu"
+	assert.True(t, peer.Forwards(process))
+
+	// Never forward a process back to a server it was forwarded from
+	SetFederationPath(&process.FunctionSpec, []string{"test_server_id"})
+	assert.False(t, peer.Forwards(process))
+	SetFederationPath(&process.FunctionSpec, []string{"another_server_id"})
+	assert.True(t, peer.Forwards(process))
+
+	process.FunctionSpec.Conditions.ExecutorNames = []string{"test_executor"}
+	assert.False(t, peer.Forwards(process))
+	process.FunctionSpec.Conditions.ExecutorNames = []string{}
+
+	process.ProcessGraphID = GenerateRandomID()
+	assert.False(t, peer.Forwards(process))
+}
@@ -1,12 +1,16 @@
 package core
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type FileData struct {
-	Name       string `json:"name"`
-	Checksum   string `json:"checksum"`
-	Size       int64  `json:"size"`
-	S3Filename string `json:"s3filename"`
+	Name       string    `json:"name"`
+	Checksum   string    `json:"checksum"`
+	Size       int64     `json:"size"`
+	S3Filename string    `json:"s3filename"`
+	Added      time.Time `json:"added"`
 }
 
 func ConvertJSONToFileData(jsonString string) (*FileData, error) {
@@ -78,6 +82,10 @@ func (fileData *FileData) Equals(fileData2 *FileData) bool {
 		return false
 	}
 
+	if fileData.Added.Unix() != fileData2.Added.Unix() {
+		return false
+	}
+
 	return true
 }
 
@@ -18,12 +18,22 @@ type SnapshotMount struct {
 	KeepSnaphot bool   `json:"keepsnapshot"`
 }
 
+const (
+	ConflictStrategyKeepLocal  = "keeplocal"
+	ConflictStrategyKeepRemote = "keepremote"
+	ConflictStrategyKeepBoth   = "keepboth"
+	ConflictStrategyNewestWins = "newestwins"
+	ConflictStrategyFail       = "fail"
+)
+
 type OnStart struct {
-	KeepLocal bool `json:"keeplocal"`
+	KeepLocal bool   `json:"keeplocal"`
+	Strategy  string `json:"strategy,omitempty"`
 }
 
 type OnClose struct {
-	KeepLocal bool `json:"keeplocal"`
+	KeepLocal bool   `json:"keeplocal"`
+	Strategy  string `json:"strategy,omitempty"`
 }
 
 type ConflictResolution struct {
@@ -31,6 +41,28 @@ type ConflictResolution struct {
 	OnClose OnClose `json:"onclose"`
 }
 
+// ResolveConflictStrategy returns the conflict strategy to use, strategy takes precedence over the older keepLocal flag
+func ResolveConflictStrategy(strategy string, keepLocal bool) string {
+	if strategy != "" {
+		return strategy
+	}
+
+	if keepLocal {
+		return ConflictStrategyKeepLocal
+	}
+
+	return ConflictStrategyKeepRemote
+}
+
+func IsValidConflictStrategy(strategy string) bool {
+	switch strategy {
+	case ConflictStrategyKeepLocal, ConflictStrategyKeepRemote, ConflictStrategyKeepBoth, ConflictStrategyNewestWins, ConflictStrategyFail:
+		return true
+	}
+
+	return false
+}
+
 type SyncDirMount struct {
 	Label              string             `json:"label"`
 	Dir                string             `json:"dir"`
@@ -272,6 +304,9 @@ func (funcSpec *FunctionSpec) Equals(funcSpec2 *FunctionSpec) bool {
 		if funcSpec.Filesystem.SyncDirMounts[i].KeepFiles != funcSpec2.Filesystem.SyncDirMounts[i].KeepFiles {
 			same = false
 		}
+		if funcSpec.Filesystem.SyncDirMounts[i].ConflictResolution != funcSpec2.Filesystem.SyncDirMounts[i].ConflictResolution {
+			same = false
+		}
 	}
 
 	for i := range funcSpec.Filesystem.SnapshotMounts {
@@ -112,3 +112,35 @@ func TestFunctionSpecEquals(t *testing.T) {
 	assert.False(t, funcSpec1.Equals(nil))
 	assert.False(t, funcSpec1.Equals(functionSpec2))
 }
+
+func TestFunctionSpecConflictResolution(t *testing.T) {
+	syncdir := SyncDirMount{Label: "test_label1", Dir: "test_dir1", KeepFiles: false}
+	syncdir.ConflictResolution.OnStart.Strategy = ConflictStrategyKeepBoth
+	syncdir.ConflictResolution.OnClose.Strategy = ConflictStrategyNewestWins
+
+	funcSpec1 := CreateEmptyFunctionSpec()
+	funcSpec1.MaxWaitTime = -1
+	funcSpec1.Filesystem = Filesystem{SyncDirMounts: []SyncDirMount{syncdir}}
+
+	jsonString, err := funcSpec1.ToJSON()
+	assert.Nil(t, err)
+
+	funcSpec2, err := ConvertJSONToFunctionSpec(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, funcSpec1.Equals(funcSpec2))
+	assert.Equal(t, funcSpec2.Filesystem.SyncDirMounts[0].ConflictResolution.OnStart.Strategy, ConflictStrategyKeepBoth)
+
+	funcSpec2.Filesystem.SyncDirMounts[0].ConflictResolution.OnClose.Strategy = ConflictStrategyFail
+	assert.False(t, funcSpec1.Equals(funcSpec2))
+}
+
+func TestResolveConflictStrategy(t *testing.T) {
+	assert.Equal(t, ResolveConflictStrategy("", true), ConflictStrategyKeepLocal)
+	assert.Equal(t, ResolveConflictStrategy("", false), ConflictStrategyKeepRemote)
+	assert.Equal(t, ResolveConflictStrategy(ConflictStrategyKeepBoth, true), ConflictStrategyKeepBoth)
+
+	assert.True(t, IsValidConflictStrategy(ConflictStrategyNewestWins))
+	assert.True(t, IsValidConflictStrategy(ConflictStrategyFail))
+	assert.False(t, IsValidConflictStrategy("invalid"))
+	assert.False(t, IsValidConflictStrategy(""))
+}
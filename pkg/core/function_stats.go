@@ -0,0 +1,280 @@
+package core
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+)
+
+// Minimum number of historical runs needed before a run can be flagged as an anomaly
+const MinAnomalySamples = 10
+
+// FunctionRun records the outcome of a process running a function, function statistics are calculated from runs
+type FunctionRun struct {
+	ProcessID     string    `json:"processid"`
+	ColonyName    string    `json:"colonyname"`
+	FuncName      string    `json:"funcname"`
+	ExecutorType  string    `json:"executortype"`
+	ExecutorName  string    `json:"executorname"`
+	WaitTime      float64   `json:"waittime"` // Seconds
+	ExecTime      float64   `json:"exectime"` // Seconds
+	Successful    bool      `json:"successful"`
+	Anomaly       bool      `json:"anomaly"`
+	EndTime       time.Time `json:"endtime"`
+	InitiatorName string    `json:"initiatorname"`
+	Label         string    `json:"label"`
+	CPU           int64     `json:"cpu"`  // Requested millicores on all nodes
+	GPUs          int       `json:"gpus"` // Requested GPUs on all nodes
+}
+
+// FunctionStats summarizes the runs of a function on an executor type between Start and End. Throughput is
+// measured in runs per minute and FailureRate is the fraction of failed runs. Buckets are only set on the summary
+// of the whole period and only contain buckets with runs.
+type FunctionStats struct {
+	ColonyName   string           `json:"colonyname"`
+	FuncName     string           `json:"funcname"`
+	ExecutorType string           `json:"executortype"`
+	Start        time.Time        `json:"start"`
+	End          time.Time        `json:"end"`
+	Runs         int              `json:"runs"`
+	Failed       int              `json:"failed"`
+	Anomalies    int              `json:"anomalies"`
+	FailureRate  float64          `json:"failurerate"`
+	Throughput   float64          `json:"throughput"`
+	WaitTimeP50  float64          `json:"waittimep50"`
+	WaitTimeP95  float64          `json:"waittimep95"`
+	WaitTimeP99  float64          `json:"waittimep99"`
+	ExecTimeP50  float64          `json:"exectimep50"`
+	ExecTimeP95  float64          `json:"exectimep95"`
+	ExecTimeP99  float64          `json:"exectimep99"`
+	Buckets      []*FunctionStats `json:"buckets,omitempty"`
+}
+
+// Percentile returns the p:th (0-100) percentile of values using the nearest rank method, or 0 if values is empty
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+
+	return sorted[rank-1]
+}
+
+// IsAnomaly returns true if execTime exceeds factor times the p95 exec time of the historical runs. Nothing is
+// flagged until there are at least MinAnomalySamples historical runs, or if factor is not positive.
+func IsAnomaly(execTime float64, history []*FunctionRun, factor float64) bool {
+	if factor <= 0 || len(history) < MinAnomalySamples {
+		return false
+	}
+
+	execTimes := make([]float64, len(history))
+	for i, run := range history {
+		execTimes[i] = run.ExecTime
+	}
+
+	return execTime > factor*Percentile(execTimes, 95)
+}
+
+func calcFunctionStats(colonyName string, funcName string, executorType string, runs []*FunctionRun, start time.Time, end time.Time) *FunctionStats {
+	stats := &FunctionStats{ColonyName: colonyName, FuncName: funcName, ExecutorType: executorType, Start: start, End: end}
+
+	waitTimes := make([]float64, len(runs))
+	execTimes := make([]float64, len(runs))
+	for i, run := range runs {
+		waitTimes[i] = run.WaitTime
+		execTimes[i] = run.ExecTime
+		if !run.Successful {
+			stats.Failed++
+		}
+		if run.Anomaly {
+			stats.Anomalies++
+		}
+	}
+
+	stats.Runs = len(runs)
+	if stats.Runs > 0 {
+		stats.FailureRate = float64(stats.Failed) / float64(stats.Runs)
+	}
+	if minutes := end.Sub(start).Minutes(); minutes > 0 {
+		stats.Throughput = float64(stats.Runs) / minutes
+	}
+	stats.WaitTimeP50 = Percentile(waitTimes, 50)
+	stats.WaitTimeP95 = Percentile(waitTimes, 95)
+	stats.WaitTimeP99 = Percentile(waitTimes, 99)
+	stats.ExecTimeP50 = Percentile(execTimes, 50)
+	stats.ExecTimeP95 = Percentile(execTimes, 95)
+	stats.ExecTimeP99 = Percentile(execTimes, 99)
+
+	return stats
+}
+
+// CalcFunctionStats groups runs ended between start and end by function name and executor type, and summarizes
+// each group. If bucketSize is positive, each summary also contains summaries of bucketSize long periods from start.
+func CalcFunctionStats(runs []*FunctionRun, start time.Time, end time.Time, bucketSize time.Duration) []*FunctionStats {
+	type groupKey struct {
+		funcName     string
+		executorType string
+	}
+
+	groups := make(map[groupKey][]*FunctionRun)
+	var keys []groupKey
+	colonyNames := make(map[groupKey]string)
+	for _, run := range runs {
+		if run.EndTime.Before(start) || run.EndTime.After(end) {
+			continue
+		}
+		key := groupKey{funcName: run.FuncName, executorType: run.ExecutorType}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+			colonyNames[key] = run.ColonyName
+		}
+		groups[key] = append(groups[key], run)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].funcName != keys[j].funcName {
+			return keys[i].funcName < keys[j].funcName
+		}
+		return keys[i].executorType < keys[j].executorType
+	})
+
+	allStats := make([]*FunctionStats, 0, len(keys))
+	for _, key := range keys {
+		groupRuns := groups[key]
+		stats := calcFunctionStats(colonyNames[key], key.funcName, key.executorType, groupRuns, start, end)
+
+		if bucketSize > 0 {
+			buckets := make(map[int64][]*FunctionRun)
+			var bucketIndexes []int64
+			for _, run := range groupRuns {
+				index := int64(run.EndTime.Sub(start) / bucketSize)
+				if _, ok := buckets[index]; !ok {
+					bucketIndexes = append(bucketIndexes, index)
+				}
+				buckets[index] = append(buckets[index], run)
+			}
+
+			sort.Slice(bucketIndexes, func(i, j int) bool { return bucketIndexes[i] < bucketIndexes[j] })
+			for _, index := range bucketIndexes {
+				bucketStart := start.Add(time.Duration(index) * bucketSize)
+				bucketEnd := bucketStart.Add(bucketSize)
+				if bucketEnd.After(end) {
+					bucketEnd = end
+				}
+				stats.Buckets = append(stats.Buckets, calcFunctionStats(colonyNames[key], key.funcName, key.executorType, buckets[index], bucketStart, bucketEnd))
+			}
+		}
+
+		allStats = append(allStats, stats)
+	}
+
+	return allStats
+}
+
+func ConvertJSONToFunctionStatsArray(jsonString string) ([]*FunctionStats, error) {
+	var stats []*FunctionStats
+	err := json.Unmarshal([]byte(jsonString), &stats)
+	if err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+func ConvertFunctionStatsArrayToJSON(stats []*FunctionStats) (string, error) {
+	jsonBytes, err := json.Marshal(stats)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (stats *FunctionStats) Equals(stats2 *FunctionStats) bool {
+	if stats2 == nil {
+		return false
+	}
+
+	if stats.ColonyName != stats2.ColonyName ||
+		stats.FuncName != stats2.FuncName ||
+		stats.ExecutorType != stats2.ExecutorType ||
+		!stats.Start.Equal(stats2.Start) ||
+		!stats.End.Equal(stats2.End) ||
+		stats.Runs != stats2.Runs ||
+		stats.Failed != stats2.Failed ||
+		stats.Anomalies != stats2.Anomalies ||
+		stats.FailureRate != stats2.FailureRate ||
+		stats.Throughput != stats2.Throughput ||
+		stats.WaitTimeP50 != stats2.WaitTimeP50 ||
+		stats.WaitTimeP95 != stats2.WaitTimeP95 ||
+		stats.WaitTimeP99 != stats2.WaitTimeP99 ||
+		stats.ExecTimeP50 != stats2.ExecTimeP50 ||
+		stats.ExecTimeP95 != stats2.ExecTimeP95 ||
+		stats.ExecTimeP99 != stats2.ExecTimeP99 ||
+		len(stats.Buckets) != len(stats2.Buckets) {
+		return false
+	}
+
+	for i := range stats.Buckets {
+		if !stats.Buckets[i].Equals(stats2.Buckets[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (stats *FunctionStats) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(stats)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (run *FunctionRun) Equals(run2 *FunctionRun) bool {
+	if run2 == nil {
+		return false
+	}
+
+	if run.ProcessID != run2.ProcessID ||
+		run.ColonyName != run2.ColonyName ||
+		run.FuncName != run2.FuncName ||
+		run.ExecutorType != run2.ExecutorType ||
+		run.ExecutorName != run2.ExecutorName ||
+		run.WaitTime != run2.WaitTime ||
+		run.ExecTime != run2.ExecTime ||
+		run.Successful != run2.Successful ||
+		run.Anomaly != run2.Anomaly ||
+		!run.EndTime.Equal(run2.EndTime) ||
+		run.InitiatorName != run2.InitiatorName ||
+		run.Label != run2.Label ||
+		run.CPU != run2.CPU ||
+		run.GPUs != run2.GPUs {
+		return false
+	}
+
+	return true
+}
+
+func (run *FunctionRun) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(run)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	assert.Equal(t, 0.0, Percentile([]float64{}, 50))
+
+	values := []float64{10, 1, 9, 2, 8, 3, 7, 4, 6, 5}
+	assert.Equal(t, 5.0, Percentile(values, 50))
+	assert.Equal(t, 10.0, Percentile(values, 95))
+	assert.Equal(t, 10.0, Percentile(values, 99))
+	assert.Equal(t, 1.0, Percentile(values, 0))
+	assert.Equal(t, 10.0, values[0]) // Values are not sorted in place
+
+	values = make([]float64, 100)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	assert.Equal(t, 50.0, Percentile(values, 50))
+	assert.Equal(t, 95.0, Percentile(values, 95))
+	assert.Equal(t, 99.0, Percentile(values, 99))
+}
+
+func TestIsAnomaly(t *testing.T) {
+	var history []*FunctionRun
+	for i := 0; i < MinAnomalySamples-1; i++ {
+		history = append(history, &FunctionRun{ExecTime: 1})
+	}
+
+	assert.False(t, IsAnomaly(100, history, 3)) // Too few samples
+
+	history = append(history, &FunctionRun{ExecTime: 1})
+	assert.True(t, IsAnomaly(100, history, 3))
+	assert.False(t, IsAnomaly(3, history, 3))
+	assert.False(t, IsAnomaly(100, history, 0)) // Disabled
+}
+
+func TestCalcFunctionStats(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	var runs []*FunctionRun
+	for i := 0; i < 10; i++ {
+		runs = append(runs, &FunctionRun{ColonyName: "test_colony", FuncName: "func1", ExecutorType: "type1", WaitTime: 1, ExecTime: float64(i + 1), Successful: i != 0, EndTime: start.Add(time.Duration(i) * time.Minute)})
+	}
+	runs = append(runs, &FunctionRun{ColonyName: "test_colony", FuncName: "func1", ExecutorType: "type1", WaitTime: 2, ExecTime: 100, Successful: true, Anomaly: true, EndTime: start.Add(90 * time.Minute)})
+	runs = append(runs, &FunctionRun{ColonyName: "test_colony", FuncName: "func1", ExecutorType: "type2", WaitTime: 1, ExecTime: 1, Successful: true, EndTime: start.Add(time.Minute)})
+	runs = append(runs, &FunctionRun{ColonyName: "test_colony", FuncName: "func0", ExecutorType: "type1", WaitTime: 1, ExecTime: 1, Successful: false, EndTime: start.Add(time.Minute)})
+	runs = append(runs, &FunctionRun{ColonyName: "test_colony", FuncName: "func0", ExecutorType: "type1", WaitTime: 1, ExecTime: 1, Successful: true, EndTime: start.Add(-time.Minute)}) // Before start
+
+	stats := CalcFunctionStats(runs, start, end, time.Hour)
+	assert.Len(t, stats, 3)
+
+	assert.Equal(t, "func0", stats[0].FuncName)
+	assert.Equal(t, 1, stats[0].Runs)
+	assert.Equal(t, 1.0, stats[0].FailureRate)
+
+	assert.Equal(t, "func1", stats[1].FuncName)
+	assert.Equal(t, "type1", stats[1].ExecutorType)
+	assert.Equal(t, "test_colony", stats[1].ColonyName)
+	assert.Equal(t, 11, stats[1].Runs)
+	assert.Equal(t, 1, stats[1].Failed)
+	assert.Equal(t, 1, stats[1].Anomalies)
+	assert.InDelta(t, 1.0/11.0, stats[1].FailureRate, 0.0001)
+	assert.InDelta(t, 11.0/120.0, stats[1].Throughput, 0.0001)
+	assert.Equal(t, 6.0, stats[1].ExecTimeP50)
+	assert.Equal(t, 100.0, stats[1].ExecTimeP95)
+	assert.Equal(t, 1.0, stats[1].WaitTimeP50)
+	assert.Equal(t, 2.0, stats[1].WaitTimeP99)
+
+	assert.Len(t, stats[1].Buckets, 2)
+	assert.Equal(t, start, stats[1].Buckets[0].Start)
+	assert.Equal(t, start.Add(time.Hour), stats[1].Buckets[0].End)
+	assert.Equal(t, 10, stats[1].Buckets[0].Runs)
+	assert.Equal(t, 10.0, stats[1].Buckets[0].ExecTimeP95)
+	assert.Equal(t, start.Add(time.Hour), stats[1].Buckets[1].Start)
+	assert.Equal(t, 1, stats[1].Buckets[1].Runs)
+	assert.Equal(t, 1, stats[1].Buckets[1].Anomalies)
+
+	assert.Equal(t, "func1", stats[2].FuncName)
+	assert.Equal(t, "type2", stats[2].ExecutorType)
+
+	stats = CalcFunctionStats(runs, start, end, 0)
+	assert.Len(t, stats, 3)
+	assert.Nil(t, stats[1].Buckets)
+}
+
+func TestFunctionStatsToJSON(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	runs := []*FunctionRun{{ColonyName: "test_colony", FuncName: "func1", ExecutorType: "type1", WaitTime: 1, ExecTime: 2, Successful: true, EndTime: start.Add(time.Minute)}}
+	stats1 := CalcFunctionStats(runs, start, start.Add(time.Hour), time.Minute)
+
+	jsonStr, err := ConvertFunctionStatsArrayToJSON(stats1)
+	assert.Nil(t, err)
+
+	stats2, err := ConvertJSONToFunctionStatsArray(jsonStr)
+	assert.Nil(t, err)
+	assert.Len(t, stats2, 1)
+	assert.True(t, stats1[0].Equals(stats2[0]))
+
+	stats2[0].Buckets[0].Runs = 2
+	assert.False(t, stats1[0].Equals(stats2[0]))
+	assert.False(t, stats1[0].Equals(nil))
+}
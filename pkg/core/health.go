@@ -0,0 +1,102 @@
+package core
+
+import (
+	"encoding/json"
+)
+
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+// HealthCheck is the outcome of checking a dependency of a server, Duration is the time the check took in seconds
+type HealthCheck struct {
+	Name     string            `json:"name"`
+	Status   string            `json:"status"`
+	Message  string            `json:"message,omitempty"`
+	Duration float64           `json:"duration"`
+	Details  map[string]string `json:"details,omitempty"`
+}
+
+// HealthReport is the outcome of all checks of a liveness or readiness probe, Status is only ok if all checks are ok
+type HealthReport struct {
+	Status string         `json:"status"`
+	Node   string         `json:"node"`
+	Checks []*HealthCheck `json:"checks"`
+}
+
+func CreateHealthReport(node string, checks []*HealthCheck) *HealthReport {
+	report := &HealthReport{Status: HealthStatusOK, Node: node, Checks: checks}
+	for _, check := range checks {
+		if check.Status != HealthStatusOK {
+			report.Status = HealthStatusFail
+		}
+	}
+
+	return report
+}
+
+func ConvertJSONToHealthReport(jsonString string) (*HealthReport, error) {
+	var report *HealthReport
+	err := json.Unmarshal([]byte(jsonString), &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (report *HealthReport) IsOK() bool {
+	return report.Status == HealthStatusOK
+}
+
+func (report *HealthReport) Equals(report2 *HealthReport) bool {
+	if report2 == nil {
+		return false
+	}
+
+	if report.Status != report2.Status ||
+		report.Node != report2.Node ||
+		len(report.Checks) != len(report2.Checks) {
+		return false
+	}
+
+	for i := range report.Checks {
+		if !report.Checks[i].Equals(report2.Checks[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (report *HealthReport) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (check *HealthCheck) Equals(check2 *HealthCheck) bool {
+	if check2 == nil {
+		return false
+	}
+
+	if check.Name != check2.Name ||
+		check.Status != check2.Status ||
+		check.Message != check2.Message ||
+		check.Duration != check2.Duration ||
+		len(check.Details) != len(check2.Details) {
+		return false
+	}
+
+	for key, value := range check.Details {
+		if check2.Details[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
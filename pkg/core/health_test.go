@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateHealthReport(t *testing.T) {
+	report := CreateHealthReport("node1", []*HealthCheck{
+		{Name: "database", Status: HealthStatusOK, Duration: 0.001},
+		{Name: "etcd", Status: HealthStatusOK, Details: map[string]string{"leader": "node1"}},
+	})
+	assert.True(t, report.IsOK())
+	assert.Equal(t, "node1", report.Node)
+
+	report = CreateHealthReport("node1", []*HealthCheck{
+		{Name: "database", Status: HealthStatusOK},
+		{Name: "etcd", Status: HealthStatusFail, Message: "no quorum"},
+	})
+	assert.False(t, report.IsOK())
+	assert.Equal(t, HealthStatusFail, report.Status)
+
+	report = CreateHealthReport("node1", []*HealthCheck{})
+	assert.True(t, report.IsOK())
+}
+
+func TestHealthReportToJSON(t *testing.T) {
+	report := CreateHealthReport("node1", []*HealthCheck{
+		{Name: "database", Status: HealthStatusOK, Duration: 0.001},
+		{Name: "etcd", Status: HealthStatusFail, Message: "no quorum", Details: map[string]string{"leader": ""}},
+	})
+
+	jsonString, err := report.ToJSON()
+	assert.Nil(t, err)
+
+	_, err = ConvertJSONToHealthReport(jsonString + "error")
+	assert.NotNil(t, err)
+
+	report2, err := ConvertJSONToHealthReport(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, report.Equals(report2))
+}
+
+func TestHealthReportEquals(t *testing.T) {
+	report1 := CreateHealthReport("node1", []*HealthCheck{{Name: "database", Status: HealthStatusOK}})
+	report2 := CreateHealthReport("node1", []*HealthCheck{{Name: "database", Status: HealthStatusFail}})
+	report3 := CreateHealthReport("node1", []*HealthCheck{{Name: "database", Status: HealthStatusOK, Details: map[string]string{"key": "value"}}})
+
+	assert.True(t, report1.Equals(report1))
+	assert.False(t, report1.Equals(report2))
+	assert.False(t, report1.Equals(report3))
+	assert.False(t, report1.Equals(nil))
+}
@@ -4,12 +4,65 @@ import (
 	"encoding/json"
 )
 
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+const (
+	LogStreamStdout = "stdout"
+	LogStreamStderr = "stderr"
+)
+
+// Log levels ordered by severity
+var logLevels = []string{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError}
+
 type Log struct {
-	ProcessID    string `json:"processid"`
-	ColonyName   string `json:"colonyname"`
-	ExecutorName string `json:"executorname"`
-	Message      string `json:"message"`
-	Timestamp    int64  `json:"timestamp"` // UTC Unix time
+	ProcessID    string            `json:"processid"`
+	ColonyName   string            `json:"colonyname"`
+	ExecutorName string            `json:"executorname"`
+	Message      string            `json:"message"`
+	Timestamp    int64             `json:"timestamp"` // UTC Unix time
+	Level        string            `json:"level"`
+	Stream       string            `json:"stream"`
+	Fields       map[string]string `json:"fields"`
+}
+
+// LogFilter selects logs in a colony. An empty ProcessID or ExecutorName matches all processes or executors, Level
+// matches logs with at least that severity, and Fields matches logs having all the given key/values.
+type LogFilter struct {
+	ColonyName   string            `json:"colonyname"`
+	ProcessID    string            `json:"processid"`
+	ExecutorName string            `json:"executorname"`
+	Level        string            `json:"level"`
+	Fields       map[string]string `json:"fields"`
+}
+
+func IsValidLogLevel(level string) bool {
+	for _, l := range logLevels {
+		if l == level {
+			return true
+		}
+	}
+
+	return false
+}
+
+func IsValidLogStream(stream string) bool {
+	return stream == "" || stream == LogStreamStdout || stream == LogStreamStderr
+}
+
+// LogLevelsFrom returns the log levels with at least the severity of level, all levels are returned if level is empty
+func LogLevelsFrom(level string) []string {
+	for i, l := range logLevels {
+		if l == level {
+			return logLevels[i:]
+		}
+	}
+
+	return logLevels
 }
 
 func ConvertJSONToLog(jsonString string) (*Log, error) {
@@ -47,13 +100,30 @@ func (log *Log) Equals(log2 *Log) bool {
 		log.ColonyName != log2.ColonyName ||
 		log.ExecutorName != log2.ExecutorName ||
 		log.Message != log2.Message ||
-		log.Timestamp != log2.Timestamp {
+		log.Timestamp != log2.Timestamp ||
+		log.Level != log2.Level ||
+		log.Stream != log2.Stream ||
+		!isLogFieldsEqual(log.Fields, log2.Fields) {
 		same = false
 	}
 
 	return same
 }
 
+func isLogFieldsEqual(fields1 map[string]string, fields2 map[string]string) bool {
+	if len(fields1) != len(fields2) {
+		return false
+	}
+
+	for key, value := range fields1 {
+		if value2, ok := fields2[key]; !ok || value != value2 {
+			return false
+		}
+	}
+
+	return true
+}
+
 func IsLogArraysEqual(logs1 []*Log, logs2 []*Log) bool {
 	if logs1 == nil || logs2 == nil {
 		return false
@@ -85,3 +155,37 @@ func (log *Log) ToJSON() (string, error) {
 
 	return string(jsonBytes), nil
 }
+
+func (filter *LogFilter) Match(log *Log) bool {
+	if filter.ColonyName != log.ColonyName {
+		return false
+	}
+
+	if filter.ProcessID != "" && filter.ProcessID != log.ProcessID {
+		return false
+	}
+
+	if filter.ExecutorName != "" && filter.ExecutorName != log.ExecutorName {
+		return false
+	}
+
+	if filter.Level != "" {
+		match := false
+		for _, level := range LogLevelsFrom(filter.Level) {
+			if level == log.Level {
+				match = true
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+
+	for key, value := range filter.Fields {
+		if logValue, ok := log.Fields[key]; !ok || logValue != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -15,6 +15,14 @@ func TestIsLogEquals(t *testing.T) {
 	assert.True(t, log1.Equals(log2))
 	log1.Message = "changed_msg"
 	assert.False(t, log1.Equals(log2))
+
+	log1.Message = log2.Message
+	log1.Fields = map[string]string{"key": "value"}
+	assert.False(t, log1.Equals(log2))
+	log2.Fields = map[string]string{"key": "value"}
+	assert.True(t, log1.Equals(log2))
+	log2.Level = LogLevelError
+	assert.False(t, log1.Equals(log2))
 }
 
 func TestIsLogArraysEquals(t *testing.T) {
@@ -33,7 +41,7 @@ func TestIsLogArraysEquals(t *testing.T) {
 
 func TestLogToJSON(t *testing.T) {
 	now := time.Now()
-	log1 := &Log{ProcessID: "test_process_id", ColonyName: "test_colony", ExecutorName: "test_executor_name", Message: "test_msg", Timestamp: now.Unix()}
+	log1 := &Log{ProcessID: "test_process_id", ColonyName: "test_colony", ExecutorName: "test_executor_name", Message: "test_msg", Timestamp: now.Unix(), Level: LogLevelWarn, Stream: LogStreamStderr, Fields: map[string]string{"key": "value"}}
 	jsonStr, err := log1.ToJSON()
 	assert.Nil(t, err)
 
@@ -56,3 +64,33 @@ func TestLogArrayToJSON(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, IsLogArraysEqual(logs1, logs2))
 }
+
+func TestLogLevels(t *testing.T) {
+	assert.True(t, IsValidLogLevel(LogLevelDebug))
+	assert.True(t, IsValidLogLevel(LogLevelError))
+	assert.False(t, IsValidLogLevel("invalid"))
+	assert.False(t, IsValidLogLevel(""))
+
+	assert.True(t, IsValidLogStream(""))
+	assert.True(t, IsValidLogStream(LogStreamStderr))
+	assert.False(t, IsValidLogStream("invalid"))
+
+	assert.Equal(t, []string{LogLevelWarn, LogLevelError}, LogLevelsFrom(LogLevelWarn))
+	assert.Len(t, LogLevelsFrom(""), 4)
+}
+
+func TestLogFilterMatch(t *testing.T) {
+	log := &Log{ProcessID: "test_process_id", ColonyName: "test_colony", ExecutorName: "test_executor_name", Message: "test_msg", Level: LogLevelWarn, Fields: map[string]string{"key1": "value1", "key2": "value2"}}
+
+	assert.True(t, (&LogFilter{ColonyName: "test_colony"}).Match(log))
+	assert.False(t, (&LogFilter{ColonyName: "other_colony"}).Match(log))
+	assert.True(t, (&LogFilter{ColonyName: "test_colony", ProcessID: "test_process_id"}).Match(log))
+	assert.False(t, (&LogFilter{ColonyName: "test_colony", ProcessID: "other_process_id"}).Match(log))
+	assert.False(t, (&LogFilter{ColonyName: "test_colony", ExecutorName: "other_executor_name"}).Match(log))
+	assert.True(t, (&LogFilter{ColonyName: "test_colony", Level: LogLevelInfo}).Match(log))
+	assert.True(t, (&LogFilter{ColonyName: "test_colony", Level: LogLevelWarn}).Match(log))
+	assert.False(t, (&LogFilter{ColonyName: "test_colony", Level: LogLevelError}).Match(log))
+	assert.True(t, (&LogFilter{ColonyName: "test_colony", Fields: map[string]string{"key1": "value1"}}).Match(log))
+	assert.False(t, (&LogFilter{ColonyName: "test_colony", Fields: map[string]string{"key1": "value2"}}).Match(log))
+	assert.False(t, (&LogFilter{ColonyName: "test_colony", Fields: map[string]string{"key3": "value3"}}).Match(log))
+}
@@ -41,6 +41,8 @@ type Process struct {
 	Input              []interface{} `json:"in"`
 	Output             []interface{} `json:"out"`
 	Errors             []string      `json:"errors"`
+	TraceParent        string        `json:"traceparent"` // W3C trace context of the trace the process was submitted in
+	TraceState         string        `json:"tracestate"`
 }
 
 func CreateProcess(funcSpec *FunctionSpec) *Process {
@@ -160,7 +162,9 @@ func (process *Process) Equals(process2 *Process) bool {
 		process.ExecDeadline.Unix() != process2.ExecDeadline.Unix() ||
 		process.Retries != process2.Retries ||
 		process.WaitForParents != process2.WaitForParents ||
-		process.ProcessGraphID != process2.ProcessGraphID {
+		process.ProcessGraphID != process2.ProcessGraphID ||
+		process.TraceParent != process2.TraceParent ||
+		process.TraceState != process2.TraceState {
 		same = false
 	}
 
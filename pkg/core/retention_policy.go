@@ -0,0 +1,60 @@
+package core
+
+import (
+	"encoding/json"
+)
+
+// RetentionPolicy overrides the server wide retention period for a colony. All periods are in seconds. A period of 0
+// falls back to the server wide retention period, which only covers successful processes, process graphs and logs,
+// and only if retention is enabled, otherwise the objects are kept forever.
+type RetentionPolicy struct {
+	ColonyName          string `json:"colonyname"`
+	SuccessfulProcesses int64  `json:"successfulprocesses"`
+	FailedProcesses     int64  `json:"failedprocesses"`
+	ProcessGraphs       int64  `json:"processgraphs"`
+	Logs                int64  `json:"logs"`
+	Files               int64  `json:"files"`   // Only old revisions of files are removed, the latest revision is always kept
+	Archive             bool   `json:"archive"` // Store expired processes, process graphs and logs in ColoniesFS before removing them
+}
+
+func CreateRetentionPolicy(colonyName string) *RetentionPolicy {
+	return &RetentionPolicy{ColonyName: colonyName}
+}
+
+func ConvertJSONToRetentionPolicy(jsonString string) (*RetentionPolicy, error) {
+	var policy *RetentionPolicy
+	err := json.Unmarshal([]byte(jsonString), &policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (policy *RetentionPolicy) Equals(policy2 *RetentionPolicy) bool {
+	if policy2 == nil {
+		return false
+	}
+
+	same := true
+	if policy.ColonyName != policy2.ColonyName ||
+		policy.SuccessfulProcesses != policy2.SuccessfulProcesses ||
+		policy.FailedProcesses != policy2.FailedProcesses ||
+		policy.ProcessGraphs != policy2.ProcessGraphs ||
+		policy.Logs != policy2.Logs ||
+		policy.Files != policy2.Files ||
+		policy.Archive != policy2.Archive {
+		same = false
+	}
+
+	return same
+}
+
+func (policy *RetentionPolicy) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionPolicyEquals(t *testing.T) {
+	policy1 := CreateRetentionPolicy("test_colony")
+	policy1.SuccessfulProcesses = 60
+	policy1.FailedProcesses = 3600
+	policy1.Archive = true
+
+	policy2 := CreateRetentionPolicy("test_colony")
+	policy2.SuccessfulProcesses = 60
+	policy2.FailedProcesses = 3600
+	policy2.Archive = true
+
+	assert.True(t, policy1.Equals(policy2))
+	policy2.Logs = 10
+	assert.False(t, policy1.Equals(policy2))
+	assert.False(t, policy1.Equals(nil))
+}
+
+func TestRetentionPolicyToJSON(t *testing.T) {
+	policy1 := CreateRetentionPolicy("test_colony")
+	policy1.SuccessfulProcesses = 60
+	policy1.FailedProcesses = 3600
+	policy1.ProcessGraphs = 120
+	policy1.Logs = 10
+	policy1.Files = 86400
+	policy1.Archive = true
+
+	jsonStr, err := policy1.ToJSON()
+	assert.Nil(t, err)
+
+	policy2, err := ConvertJSONToRetentionPolicy(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, policy1.Equals(policy2))
+
+	_, err = ConvertJSONToRetentionPolicy("invalid json")
+	assert.NotNil(t, err)
+}
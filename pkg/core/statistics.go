@@ -2,6 +2,7 @@ package core
 
 import (
 	"encoding/json"
+	"time"
 )
 
 type Statistics struct {
@@ -81,3 +82,68 @@ func (stat *Statistics) ToJSON() (string, error) {
 
 	return string(jsonBytes), nil
 }
+
+// StatisticsSnapshot is the statistics of a colony at a point in time, snapshots are stored periodically to build a
+// time series
+type StatisticsSnapshot struct {
+	ColonyName string      `json:"colonyname"`
+	Time       time.Time   `json:"time"`
+	Statistics *Statistics `json:"statistics"`
+}
+
+func CreateStatisticsSnapshot(colonyName string, time time.Time, stat *Statistics) *StatisticsSnapshot {
+	return &StatisticsSnapshot{ColonyName: colonyName, Time: time, Statistics: stat}
+}
+
+func ConvertJSONToStatisticsSnapshotArray(jsonString string) ([]*StatisticsSnapshot, error) {
+	var snapshots []*StatisticsSnapshot
+	err := json.Unmarshal([]byte(jsonString), &snapshots)
+	if err != nil {
+		return snapshots, err
+	}
+
+	return snapshots, nil
+}
+
+func ConvertStatisticsSnapshotArrayToJSON(snapshots []*StatisticsSnapshot) (string, error) {
+	jsonBytes, err := json.Marshal(snapshots)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func IsStatisticsSnapshotArraysEqual(snapshots1 []*StatisticsSnapshot, snapshots2 []*StatisticsSnapshot) bool {
+	if snapshots1 == nil || snapshots2 == nil {
+		return false
+	}
+
+	if len(snapshots1) != len(snapshots2) {
+		return false
+	}
+
+	for i := range snapshots1 {
+		if !snapshots1[i].Equals(snapshots2[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (snapshot *StatisticsSnapshot) Equals(snapshot2 *StatisticsSnapshot) bool {
+	if snapshot2 == nil {
+		return false
+	}
+
+	if snapshot.ColonyName != snapshot2.ColonyName || !snapshot.Time.Equal(snapshot2.Time) {
+		return false
+	}
+
+	if snapshot.Statistics == nil || snapshot2.Statistics == nil {
+		return snapshot.Statistics == snapshot2.Statistics
+	}
+
+	return snapshot.Statistics.Equals(snapshot2.Statistics)
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -36,3 +37,26 @@ func TestStatisticsEquals(t *testing.T) {
 	assert.True(t, stat.Equals(stat))
 	assert.False(t, stat.Equals(nil))
 }
+
+func TestStatisticsSnapshot(t *testing.T) {
+	now := time.Now()
+	snapshot1 := CreateStatisticsSnapshot("test_colony", now, CreateStatistics(1, 2, 3, 4, 5, 6, 7, 8, 9, 10))
+	snapshot2 := CreateStatisticsSnapshot("test_colony", now.Add(time.Minute), CreateStatistics(1, 2, 0, 4, 5, 6, 7, 8, 9, 10))
+	snapshots := []*StatisticsSnapshot{snapshot1, snapshot2}
+
+	jsonString, err := ConvertStatisticsSnapshotArrayToJSON(snapshots)
+	assert.Nil(t, err)
+
+	_, err = ConvertJSONToStatisticsSnapshotArray(jsonString + "error")
+	assert.NotNil(t, err)
+
+	snapshots2, err := ConvertJSONToStatisticsSnapshotArray(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, IsStatisticsSnapshotArraysEqual(snapshots, snapshots2))
+
+	assert.True(t, snapshot1.Equals(snapshot1))
+	assert.False(t, snapshot1.Equals(snapshot2))
+	assert.False(t, snapshot1.Equals(nil))
+	assert.False(t, IsStatisticsSnapshotArraysEqual(snapshots, nil))
+	assert.False(t, IsStatisticsSnapshotArraysEqual(snapshots, []*StatisticsSnapshot{snapshot1}))
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+const (
+	UsageByUser     = "user"
+	UsageByExecutor = "executor"
+	UsageByLabel    = "label"
+)
+
+func IsValidUsageGroup(groupBy string) bool {
+	switch groupBy {
+	case UsageByUser, UsageByExecutor, UsageByLabel:
+		return true
+	}
+
+	return false
+}
+
+// Usage is the resources consumed by processes sharing a user, executor or label. CPU and GPU seconds are calculated
+// from the requested CPU and GPUs of a process multiplied by its exec time.
+type Usage struct {
+	Key          string  `json:"key"`
+	Processes    int     `json:"processes"`
+	Successful   int     `json:"successful"`
+	Failed       int     `json:"failed"`
+	CPUSeconds   float64 `json:"cpuseconds"`
+	GPUSeconds   float64 `json:"gpuseconds"`
+	StorageBytes int64   `json:"storagebytes"`
+}
+
+// ColonyUsage is the resource usage of a colony between From and To grouped by user, executor or label. Storage is
+// only known per label, so storage bytes are only attributed to groups when grouping by label but are always part of
+// the total.
+type ColonyUsage struct {
+	ColonyName string    `json:"colonyname"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	GroupBy    string    `json:"groupby"`
+	Usage      []*Usage  `json:"usage"`
+	Total      *Usage    `json:"total"`
+}
+
+func (usage *Usage) add(run *FunctionRun) {
+	usage.Processes++
+	if run.Successful {
+		usage.Successful++
+	} else {
+		usage.Failed++
+	}
+	usage.CPUSeconds += float64(run.CPU) / 1000 * run.ExecTime
+	usage.GPUSeconds += float64(run.GPUs) * run.ExecTime
+}
+
+// CalcColonyUsage sums the usage of runs ended between from and to, grouped by groupBy. storageByLabel maps file
+// labels to the number of bytes stored under the label.
+func CalcColonyUsage(colonyName string, groupBy string, from time.Time, to time.Time, runs []*FunctionRun, storageByLabel map[string]int64) *ColonyUsage {
+	colonyUsage := &ColonyUsage{ColonyName: colonyName, From: from, To: to, GroupBy: groupBy, Usage: []*Usage{}, Total: &Usage{}}
+
+	groups := make(map[string]*Usage)
+	getGroup := func(key string) *Usage {
+		usage, ok := groups[key]
+		if !ok {
+			usage = &Usage{Key: key}
+			groups[key] = usage
+		}
+		return usage
+	}
+
+	for _, run := range runs {
+		if run.EndTime.Before(from) || run.EndTime.After(to) {
+			continue
+		}
+
+		var key string
+		switch groupBy {
+		case UsageByUser:
+			key = run.InitiatorName
+		case UsageByExecutor:
+			key = run.ExecutorName
+		case UsageByLabel:
+			key = run.Label
+		}
+
+		getGroup(key).add(run)
+		colonyUsage.Total.add(run)
+	}
+
+	for label, bytes := range storageByLabel {
+		if groupBy == UsageByLabel {
+			getGroup(label).StorageBytes += bytes
+		}
+		colonyUsage.Total.StorageBytes += bytes
+	}
+
+	for _, usage := range groups {
+		colonyUsage.Usage = append(colonyUsage.Usage, usage)
+	}
+	sort.Slice(colonyUsage.Usage, func(i, j int) bool { return colonyUsage.Usage[i].Key < colonyUsage.Usage[j].Key })
+
+	return colonyUsage
+}
+
+func ConvertJSONToColonyUsage(jsonString string) (*ColonyUsage, error) {
+	var usage *ColonyUsage
+	err := json.Unmarshal([]byte(jsonString), &usage)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+func (usage *Usage) Equals(usage2 *Usage) bool {
+	if usage2 == nil {
+		return false
+	}
+
+	if usage.Key != usage2.Key ||
+		usage.Processes != usage2.Processes ||
+		usage.Successful != usage2.Successful ||
+		usage.Failed != usage2.Failed ||
+		usage.CPUSeconds != usage2.CPUSeconds ||
+		usage.GPUSeconds != usage2.GPUSeconds ||
+		usage.StorageBytes != usage2.StorageBytes {
+		return false
+	}
+
+	return true
+}
+
+func (usage *ColonyUsage) Equals(usage2 *ColonyUsage) bool {
+	if usage2 == nil {
+		return false
+	}
+
+	if usage.ColonyName != usage2.ColonyName ||
+		!usage.From.Equal(usage2.From) ||
+		!usage.To.Equal(usage2.To) ||
+		usage.GroupBy != usage2.GroupBy ||
+		len(usage.Usage) != len(usage2.Usage) {
+		return false
+	}
+
+	if usage.Total == nil || usage2.Total == nil {
+		if usage.Total != usage2.Total {
+			return false
+		}
+	} else if !usage.Total.Equals(usage2.Total) {
+		return false
+	}
+
+	for i := range usage.Usage {
+		if !usage.Usage[i].Equals(usage2.Usage[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (usage *ColonyUsage) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(usage)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidUsageGroup(t *testing.T) {
+	assert.True(t, IsValidUsageGroup(UsageByUser))
+	assert.True(t, IsValidUsageGroup(UsageByExecutor))
+	assert.True(t, IsValidUsageGroup(UsageByLabel))
+	assert.False(t, IsValidUsageGroup("error"))
+	assert.False(t, IsValidUsageGroup(""))
+}
+
+func TestCalcColonyUsage(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	runs := []*FunctionRun{
+		{ColonyName: "test_colony", InitiatorName: "user1", ExecutorName: "executor1", Label: "label1", CPU: 2000, GPUs: 1, ExecTime: 10, Successful: true, EndTime: from.Add(time.Hour)},
+		{ColonyName: "test_colony", InitiatorName: "user1", ExecutorName: "executor2", Label: "label2", CPU: 500, ExecTime: 4, Successful: false, EndTime: from.Add(2 * time.Hour)},
+		{ColonyName: "test_colony", InitiatorName: "user2", ExecutorName: "executor1", Label: "label1", CPU: 1000, GPUs: 2, ExecTime: 5, Successful: true, EndTime: from.Add(3 * time.Hour)},
+		{ColonyName: "test_colony", InitiatorName: "user2", ExecutorName: "executor1", Label: "label1", CPU: 1000, ExecTime: 100, Successful: true, EndTime: to.Add(time.Hour)}, // After to
+	}
+	storage := map[string]int64{"label1": 100, "label3": 50}
+
+	usage := CalcColonyUsage("test_colony", UsageByUser, from, to, runs, storage)
+	assert.Len(t, usage.Usage, 2)
+	assert.Equal(t, "user1", usage.Usage[0].Key)
+	assert.Equal(t, 2, usage.Usage[0].Processes)
+	assert.Equal(t, 1, usage.Usage[0].Successful)
+	assert.Equal(t, 1, usage.Usage[0].Failed)
+	assert.Equal(t, 22.0, usage.Usage[0].CPUSeconds)
+	assert.Equal(t, 10.0, usage.Usage[0].GPUSeconds)
+	assert.Equal(t, int64(0), usage.Usage[0].StorageBytes)
+	assert.Equal(t, "user2", usage.Usage[1].Key)
+	assert.Equal(t, 5.0, usage.Usage[1].CPUSeconds)
+	assert.Equal(t, 10.0, usage.Usage[1].GPUSeconds)
+	assert.Equal(t, 3, usage.Total.Processes)
+	assert.Equal(t, 27.0, usage.Total.CPUSeconds)
+	assert.Equal(t, 20.0, usage.Total.GPUSeconds)
+	assert.Equal(t, int64(150), usage.Total.StorageBytes)
+
+	usage = CalcColonyUsage("test_colony", UsageByExecutor, from, to, runs, storage)
+	assert.Len(t, usage.Usage, 2)
+	assert.Equal(t, "executor1", usage.Usage[0].Key)
+	assert.Equal(t, 2, usage.Usage[0].Processes)
+	assert.Equal(t, "executor2", usage.Usage[1].Key)
+	assert.Equal(t, 1, usage.Usage[1].Processes)
+
+	usage = CalcColonyUsage("test_colony", UsageByLabel, from, to, runs, storage)
+	assert.Len(t, usage.Usage, 3)
+	assert.Equal(t, "label1", usage.Usage[0].Key)
+	assert.Equal(t, 2, usage.Usage[0].Processes)
+	assert.Equal(t, int64(100), usage.Usage[0].StorageBytes)
+	assert.Equal(t, "label2", usage.Usage[1].Key)
+	assert.Equal(t, int64(0), usage.Usage[1].StorageBytes)
+	assert.Equal(t, "label3", usage.Usage[2].Key)
+	assert.Equal(t, 0, usage.Usage[2].Processes)
+	assert.Equal(t, int64(50), usage.Usage[2].StorageBytes)
+	assert.Equal(t, int64(150), usage.Total.StorageBytes)
+
+	usage = CalcColonyUsage("test_colony", UsageByUser, from, to, nil, nil)
+	assert.Len(t, usage.Usage, 0)
+	assert.Equal(t, 0, usage.Total.Processes)
+}
+
+func TestColonyUsageToJSON(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	runs := []*FunctionRun{{InitiatorName: "user1", CPU: 1000, ExecTime: 1, Successful: true, EndTime: from.Add(time.Hour)}}
+	usage := CalcColonyUsage("test_colony", UsageByUser, from, from.Add(24*time.Hour), runs, map[string]int64{"label1": 10})
+
+	jsonString, err := usage.ToJSON()
+	assert.Nil(t, err)
+
+	_, err = ConvertJSONToColonyUsage(jsonString + "error")
+	assert.NotNil(t, err)
+
+	usage2, err := ConvertJSONToColonyUsage(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, usage.Equals(usage2))
+}
+
+func TestColonyUsageEquals(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	runs := []*FunctionRun{{InitiatorName: "user1", CPU: 1000, ExecTime: 1, Successful: true, EndTime: from.Add(time.Hour)}}
+	usage := CalcColonyUsage("test_colony", UsageByUser, from, from.Add(24*time.Hour), runs, nil)
+	usage2 := CalcColonyUsage("test_colony", UsageByExecutor, from, from.Add(24*time.Hour), runs, nil)
+
+	assert.True(t, usage.Equals(usage))
+	assert.False(t, usage.Equals(usage2))
+	assert.False(t, usage.Equals(nil))
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+const WebhookSignaturePrefix = "sha256="
+
+// Webhook is called with a JSON encoded Event for every event in the colony matching Types and Label, see EventFilter
+type Webhook struct {
+	ID         string    `json:"webhookid"`
+	ColonyName string    `json:"colonyname"`
+	Name       string    `json:"name"`
+	URL        string    `json:"url"`
+	Types      []string  `json:"types"`
+	Label      string    `json:"label"`
+	Secret     string    `json:"secret"` // Used to sign payloads, never returned by the server
+	Added      time.Time `json:"added"`
+}
+
+// WebhookDelivery records the outcome of delivering an event to a webhook
+type WebhookDelivery struct {
+	ID         string    `json:"deliveryid"`
+	WebhookID  string    `json:"webhookid"`
+	ColonyName string    `json:"colonyname"`
+	EventType  string    `json:"eventtype"`
+	ObjectID   string    `json:"objectid"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"statuscode"`
+	Error      string    `json:"error"`
+	Delivered  bool      `json:"delivered"`
+	Time       time.Time `json:"time"`
+}
+
+func CreateWebhook(colonyName string, name string, url string, types []string, label string, secret string) *Webhook {
+	return &Webhook{ColonyName: colonyName, Name: name, URL: url, Types: types, Label: label, Secret: secret}
+}
+
+func ConvertJSONToWebhook(jsonString string) (*Webhook, error) {
+	var webhook *Webhook
+	err := json.Unmarshal([]byte(jsonString), &webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func ConvertJSONToWebhookArray(jsonString string) ([]*Webhook, error) {
+	var webhooks []*Webhook
+	err := json.Unmarshal([]byte(jsonString), &webhooks)
+	if err != nil {
+		return webhooks, err
+	}
+
+	return webhooks, nil
+}
+
+func ConvertWebhookArrayToJSON(webhooks []*Webhook) (string, error) {
+	jsonBytes, err := json.Marshal(webhooks)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (webhook *Webhook) Filter() *EventFilter {
+	return &EventFilter{ColonyName: webhook.ColonyName, Types: webhook.Types, Label: webhook.Label}
+}
+
+func (webhook *Webhook) Equals(webhook2 *Webhook) bool {
+	if webhook2 == nil {
+		return false
+	}
+
+	if len(webhook.Types) != len(webhook2.Types) {
+		return false
+	}
+
+	for i := range webhook.Types {
+		if webhook.Types[i] != webhook2.Types[i] {
+			return false
+		}
+	}
+
+	same := true
+	if webhook.ID != webhook2.ID ||
+		webhook.ColonyName != webhook2.ColonyName ||
+		webhook.Name != webhook2.Name ||
+		webhook.URL != webhook2.URL ||
+		webhook.Label != webhook2.Label ||
+		webhook.Secret != webhook2.Secret {
+		same = false
+	}
+
+	return same
+}
+
+func (webhook *Webhook) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(webhook)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func ConvertJSONToWebhookDeliveryArray(jsonString string) ([]*WebhookDelivery, error) {
+	var deliveries []*WebhookDelivery
+	err := json.Unmarshal([]byte(jsonString), &deliveries)
+	if err != nil {
+		return deliveries, err
+	}
+
+	return deliveries, nil
+}
+
+func ConvertWebhookDeliveryArrayToJSON(deliveries []*WebhookDelivery) (string, error) {
+	jsonBytes, err := json.Marshal(deliveries)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (delivery *WebhookDelivery) Equals(delivery2 *WebhookDelivery) bool {
+	if delivery2 == nil {
+		return false
+	}
+
+	same := true
+	if delivery.ID != delivery2.ID ||
+		delivery.WebhookID != delivery2.WebhookID ||
+		delivery.ColonyName != delivery2.ColonyName ||
+		delivery.EventType != delivery2.EventType ||
+		delivery.ObjectID != delivery2.ObjectID ||
+		delivery.Attempts != delivery2.Attempts ||
+		delivery.StatusCode != delivery2.StatusCode ||
+		delivery.Error != delivery2.Error ||
+		delivery.Delivered != delivery2.Delivered {
+		same = false
+	}
+
+	return same
+}
+
+// SignWebhookPayload returns the signature sent in the X-Colonies-Signature header, a hex encoded HMAC-SHA256 of the
+// payload prefixed with sha256=
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return WebhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature can be used by webhook receivers to verify that a payload was sent by a Colonies server
+func VerifyWebhookSignature(secret string, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, payload)), []byte(signature))
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookEquals(t *testing.T) {
+	webhook1 := CreateWebhook("test_colony", "test_webhook", "http://localhost/hook", []string{"processgraph"}, "", "test_secret")
+	webhook2 := CreateWebhook("test_colony", "test_webhook", "http://localhost/hook", []string{"processgraph"}, "", "test_secret")
+
+	assert.True(t, webhook1.Equals(webhook2))
+	assert.False(t, webhook1.Equals(nil))
+
+	webhook2.Types = []string{"process"}
+	assert.False(t, webhook1.Equals(webhook2))
+
+	webhook2.Types = []string{"processgraph"}
+	webhook2.URL = "http://localhost/another_hook"
+	assert.False(t, webhook1.Equals(webhook2))
+}
+
+func TestWebhookToJSON(t *testing.T) {
+	webhook1 := CreateWebhook("test_colony", "test_webhook", "http://localhost/hook", []string{"processgraph"}, "/test_label", "test_secret")
+	webhook1.ID = GenerateRandomID()
+	webhook1.Added = time.Now()
+
+	jsonStr, err := webhook1.ToJSON()
+	assert.Nil(t, err)
+
+	webhook2, err := ConvertJSONToWebhook(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, webhook1.Equals(webhook2))
+
+	_, err = ConvertJSONToWebhook("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestWebhookArrayToJSON(t *testing.T) {
+	webhooks := []*Webhook{
+		CreateWebhook("test_colony", "test_webhook1", "http://localhost/hook1", []string{}, "", ""),
+		CreateWebhook("test_colony", "test_webhook2", "http://localhost/hook2", []string{"file"}, "", ""),
+	}
+
+	jsonStr, err := ConvertWebhookArrayToJSON(webhooks)
+	assert.Nil(t, err)
+
+	webhooks2, err := ConvertJSONToWebhookArray(jsonStr)
+	assert.Nil(t, err)
+	assert.Len(t, webhooks2, 2)
+	assert.True(t, webhooks[0].Equals(webhooks2[0]))
+	assert.True(t, webhooks[1].Equals(webhooks2[1]))
+}
+
+func TestWebhookFilter(t *testing.T) {
+	webhook := CreateWebhook("test_colony", "test_webhook", "http://localhost/hook", []string{ProcessGraphFailedEvent}, "", "")
+
+	graph, err := CreateProcessGraph("test_colony")
+	assert.Nil(t, err)
+
+	graph.State = FAILED
+	assert.True(t, webhook.Filter().Match(CreateProcessGraphEvent(graph)))
+
+	graph.State = SUCCESS
+	assert.False(t, webhook.Filter().Match(CreateProcessGraphEvent(graph)))
+}
+
+func TestWebhookDeliveryArrayToJSON(t *testing.T) {
+	deliveries := []*WebhookDelivery{
+		{ID: "test_delivery_id1", WebhookID: "test_webhook_id", ColonyName: "test_colony", EventType: ProcessFailedEvent, Attempts: 1, StatusCode: 200, Delivered: true, Time: time.Now()},
+		{ID: "test_delivery_id2", WebhookID: "test_webhook_id", ColonyName: "test_colony", EventType: ProcessFailedEvent, Attempts: 5, StatusCode: 500, Error: "500 Internal Server Error", Time: time.Now()},
+	}
+
+	jsonStr, err := ConvertWebhookDeliveryArrayToJSON(deliveries)
+	assert.Nil(t, err)
+
+	deliveries2, err := ConvertJSONToWebhookDeliveryArray(jsonStr)
+	assert.Nil(t, err)
+	assert.Len(t, deliveries2, 2)
+	assert.True(t, deliveries[0].Equals(deliveries2[0]))
+	assert.True(t, deliveries[1].Equals(deliveries2[1]))
+	assert.False(t, deliveries[0].Equals(deliveries2[1]))
+	assert.False(t, deliveries[0].Equals(nil))
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	payload := []byte(`{"type":"process.failed"}`)
+	signature := SignWebhookPayload("test_secret", payload)
+
+	assert.Contains(t, signature, WebhookSignaturePrefix)
+	assert.True(t, VerifyWebhookSignature("test_secret", payload, signature))
+	assert.False(t, VerifyWebhookSignature("another_secret", payload, signature))
+	assert.False(t, VerifyWebhookSignature("test_secret", []byte(`{"type":"process.running"}`), signature))
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
@@ -9,9 +10,16 @@ import (
 type Database interface {
 	// General
 	Close()
+	Ping(ctx context.Context) error
 	Initialize() error
 	Drop() error
 
+	// Schema migrations, a schema version of 0 means that the database has not been initialized
+	SchemaVersion() (int, error)
+	LatestSchemaVersion() int
+	Migrations() []Migration
+	Migrate() error
+
 	// Users functions ...
 	AddUser(user *core.User) error
 	GetUsersByColonyName(colonyName string) ([]*core.User, error)
@@ -58,6 +66,19 @@ type Database interface {
 	RemoveFunctionsByColonyName(colonyName string) error
 	RemoveFunctions() error
 
+	// Function run history
+	AddFunctionRun(run *core.FunctionRun) error
+	GetFunctionRuns(colonyName string, funcName string, executorType string, since time.Time, count int) ([]*core.FunctionRun, error)
+	RemoveFunctionRunsByColonyName(colonyName string) error
+	GetFunctionRunsBetween(colonyName string, from time.Time, to time.Time, count int) ([]*core.FunctionRun, error)
+	RemoveExpiredFunctionRuns(colonyName string, before time.Time) error
+
+	// Statistics snapshots
+	AddStatisticsSnapshot(snapshot *core.StatisticsSnapshot) error
+	GetStatisticsSnapshots(colonyName string, from time.Time, to time.Time, count int) ([]*core.StatisticsSnapshot, error)
+	RemoveStatisticsSnapshotsByColonyName(colonyName string) error
+	RemoveExpiredStatisticsSnapshots(colonyName string, before time.Time) error
+
 	// Process functions ...
 	AddProcess(process *core.Process) error
 	GetProcesses() ([]*core.Process, error)
@@ -65,6 +86,7 @@ type Database interface {
 	FindProcessesByColonyName(colonyName string, seconds int, state int) ([]*core.Process, error)
 	FindProcessesByExecutorID(colonyName string, executorID string, seconds int, state int) ([]*core.Process, error)
 	FindWaitingProcesses(colonyName string, executorType string, label string, initiator string, count int) ([]*core.Process, error)
+	FindWaitingProcessesAfter(colonyName string, executorType string, priorityTime int64, processID string, count int) ([]*core.Process, error)
 	FindRunningProcesses(colonyName string, executorType string, label string, initiator string, count int) ([]*core.Process, error)
 	FindSuccessfulProcesses(colonyName string, executorType string, label string, initiator string, count int) ([]*core.Process, error)
 	FindFailedProcesses(colonyName string, executorType string, label string, initiator string, count int) ([]*core.Process, error)
@@ -102,6 +124,7 @@ type Database interface {
 	CountRunningProcessesByColonyName(colonyName string) (int, error)
 	CountSuccessfulProcessesByColonyName(colonyName string) (int, error)
 	CountFailedProcessesByColonyName(colonyName string) (int, error)
+	CountWaitingProcessesByExecutorType() ([]QueueDepth, error)
 
 	// Attribute functions
 	AddAttribute(attribute core.Attribute) error
@@ -180,9 +203,62 @@ type Database interface {
 
 	// Retention management
 	ApplyRetentionPolicy(retentionPeriod int64) error
+	SetRetentionPolicy(policy *core.RetentionPolicy) error
+	GetRetentionPolicy(colonyName string) (*core.RetentionPolicy, error)
+	GetRetentionPolicies() ([]*core.RetentionPolicy, error)
+	RemoveRetentionPolicy(colonyName string) error
+	FindExpiredProcesses(colonyName string, state int, before time.Time, count int) ([]*core.Process, error)
+	FindExpiredProcessGraphs(colonyName string, before time.Time, count int) ([]*core.ProcessGraph, error)
+	FindExpiredLogs(colonyName string, before time.Time, count int) ([]*core.Log, error)
+	RemoveExpiredLogs(colonyName string, before time.Time, count int) error
+	FindExpiredFiles(colonyName string, before time.Time, count int) ([]*core.File, error)
+
+	// Webhook management
+	AddWebhook(webhook *core.Webhook) error
+	GetWebhookByID(webhookID string) (*core.Webhook, error)
+	GetWebhooksByColonyName(colonyName string) ([]*core.Webhook, error)
+	RemoveWebhookByID(webhookID string) error
+	RemoveWebhooksByColonyName(colonyName string) error
+	AddWebhookDelivery(delivery *core.WebhookDelivery) error
+	GetWebhookDeliveries(webhookID string, count int) ([]*core.WebhookDelivery, error)
+	RemoveExpiredWebhookDeliveries(before time.Time) error
+	RemoveExcessWebhookDeliveries(count int) error
+
+	// Event log
+	AddEvent(event *core.Event) error
+	AddEvents(events []*core.Event) error
+	GetEvents(colonyName string, fromSeq int64, count int) ([]*core.Event, error)
+	RemoveEventsByColonyName(colonyName string) error
+	RemoveExpiredEvents(colonyName string, before time.Time) error
+	RemoveAllExpiredEvents(before time.Time) error
+	RemoveExcessEvents(count int) error
+
+	// Alert rule management
+	AddAlertRule(rule *core.AlertRule) error
+	GetAlertRuleByName(colonyName string, name string) (*core.AlertRule, error)
+	GetAlertRulesByColonyName(colonyName string) ([]*core.AlertRule, error)
+	UpdateAlertRuleState(rule *core.AlertRule) error
+	RemoveAlertRuleByName(colonyName string, name string) error
+	RemoveAlertRulesByColonyName(colonyName string) error
+
+	// Federation management
+	AddFederationPeer(peer *core.FederationPeer) error
+	GetFederationPeerByID(peerID string) (*core.FederationPeer, error)
+	GetFederationPeerByName(colonyName string, name string) (*core.FederationPeer, error)
+	GetFederationPeersByColonyName(colonyName string) ([]*core.FederationPeer, error)
+	RemoveFederationPeerByName(colonyName string, name string) error
+	RemoveFederationPeersByColonyName(colonyName string) error
+	AddFederatedProcess(federatedProcess *core.FederatedProcess) error
+	GetFederatedProcesses(count int) ([]*core.FederatedProcess, error)
+	RemoveFederatedProcess(processID string) error
+
+	// Job fencing
+	FenceJob(job string, token int64, ttl time.Duration) error
+	RunFencedJob(job string, token int64, run func() error) error
 
 	// Logging
 	AddLog(processID string, colonyName string, executorName string, timestamp int64, msg string) error
+	AddStructuredLog(log *core.Log) error
 	GetLogsByProcessID(processID string, limit int) ([]*core.Log, error)
 	GetLogsByProcessIDSince(processID string, limit int, since int64) ([]*core.Log, error)
 	GetLogsByExecutor(executorName string, limit int) ([]*core.Log, error)
@@ -190,20 +266,24 @@ type Database interface {
 	RemoveLogsByColonyName(colonyName string) error
 	CountLogs(colonyName string) (int, error)
 	SearchLogs(colonyName string, text string, days int, count int) ([]*core.Log, error)
+	SearchStructuredLogs(colonyName string, text string, level string, fields map[string]string, days int, count int) ([]*core.Log, error)
 
 	// File management
 	AddFile(file *core.File) error
 	GetFileByID(colonyName string, fileID string) (*core.File, error)
 	GetLatestFileByName(colonyName string, label string, name string) ([]*core.File, error)
 	GetFileByName(colonyName string, label string, name string) ([]*core.File, error)
+	GetFileByNameAsOf(colonyName string, label string, name string, asOf time.Time) ([]*core.File, error)
 	GetFilenamesByLabel(colonyName string, label string) ([]string, error)
 	GetFileDataByLabel(colonyName string, label string) ([]*core.FileData, error)
+	GetFileDataByLabelAsOf(colonyName string, label string, asOf time.Time) ([]*core.FileData, error)
 	RemoveFileByID(colonyName string, fileID string) error
 	RemoveFileByName(colonyName string, label string, name string) error
 	GetFileLabels(colonyName string) ([]*core.Label, error)
 	GetFileLabelsByName(colonyName string, name string, exact bool) ([]*core.Label, error)
 	CountFilesWithLabel(colonyName string, label string) (int, error)
 	CountFiles(colonyName string) (int, error)
+	GetStorageUsageByLabel(colonyName string, before time.Time) (map[string]int64, error)
 
 	// Snapshots
 	CreateSnapshot(colonyName string, label string, name string) (*core.Snapshot, error)
@@ -0,0 +1,146 @@
+package dbtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAlertRuleClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	db.Close()
+
+	rule := core.CreateAlertRule("test_colony", "test_rule", core.WaitingProcessesAlert, "", "", 10, 60, 0)
+	rule.ID = core.GenerateRandomID()
+
+	err = db.AddAlertRule(rule)
+	assert.NotNil(t, err)
+
+	_, err = db.GetAlertRuleByName("test_colony", "test_rule")
+	assert.NotNil(t, err)
+
+	_, err = db.GetAlertRulesByColonyName("test_colony")
+	assert.NotNil(t, err)
+
+	err = db.UpdateAlertRuleState(rule)
+	assert.NotNil(t, err)
+
+	err = db.RemoveAlertRuleByName("test_colony", "test_rule")
+	assert.NotNil(t, err)
+
+	err = db.RemoveAlertRulesByColonyName("test_colony")
+	assert.NotNil(t, err)
+}
+
+func testAddAlertRule(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	rule1 := core.CreateAlertRule("test_colony1", "test_rule1", core.WaitingProcessesAlert, "test_executor_type", "", 10, 60, 0)
+	rule1.ID = core.GenerateRandomID()
+	err = db.AddAlertRule(rule1)
+	assert.Nil(t, err)
+
+	rule2 := core.CreateAlertRule("test_colony1", "test_rule2", core.FailureRateAlert, "", "test_func", 0.5, 0, 3600)
+	rule2.ID = core.GenerateRandomID()
+	err = db.AddAlertRule(rule2)
+	assert.Nil(t, err)
+
+	rule3 := core.CreateAlertRule("test_colony2", "test_rule1", core.NoExecutorsAlert, "test_executor_type", "", 0, 60, 0)
+	rule3.ID = core.GenerateRandomID()
+	err = db.AddAlertRule(rule3)
+	assert.Nil(t, err)
+
+	ruleFromDB, err := db.GetAlertRuleByName("test_colony1", "test_rule1")
+	assert.Nil(t, err)
+	assert.True(t, rule1.Equals(ruleFromDB))
+
+	ruleFromDB, err = db.GetAlertRuleByName("test_colony2", "test_rule1")
+	assert.Nil(t, err)
+	assert.True(t, rule3.Equals(ruleFromDB))
+
+	ruleFromDB, err = db.GetAlertRuleByName("test_colony1", "test_rule3")
+	assert.Nil(t, err)
+	assert.Nil(t, ruleFromDB)
+
+	rules, err := db.GetAlertRulesByColonyName("test_colony1")
+	assert.Nil(t, err)
+	assert.Len(t, rules, 2)
+
+	rules, err = db.GetAlertRulesByColonyName("test_colony3")
+	assert.Nil(t, err)
+	assert.Len(t, rules, 0)
+}
+
+func testUpdateAlertRuleState(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	rule := core.CreateAlertRule("test_colony", "test_rule", core.WaitingProcessesAlert, "", "", 10, 0, 0)
+	rule.ID = core.GenerateRandomID()
+	err = db.AddAlertRule(rule)
+	assert.Nil(t, err)
+
+	now := time.Now()
+	assert.Equal(t, core.AlertFiringEvent, rule.Evaluate(20, now))
+	err = db.UpdateAlertRuleState(rule)
+	assert.Nil(t, err)
+
+	ruleFromDB, err := db.GetAlertRuleByName("test_colony", "test_rule")
+	assert.Nil(t, err)
+	assert.True(t, rule.Equals(ruleFromDB))
+	assert.Equal(t, core.AlertFiring, ruleFromDB.State)
+	assert.Equal(t, float64(20), ruleFromDB.Value)
+	assert.Equal(t, now.Unix(), ruleFromDB.FiredTime.Unix())
+	assert.Equal(t, now.Unix(), ruleFromDB.PendingSince.Unix())
+	assert.True(t, ruleFromDB.ResolvedTime.IsZero())
+}
+
+func testRemoveAlertRules(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	rule1 := core.CreateAlertRule("test_colony1", "test_rule1", core.WaitingProcessesAlert, "", "", 10, 0, 0)
+	rule1.ID = core.GenerateRandomID()
+	err = db.AddAlertRule(rule1)
+	assert.Nil(t, err)
+
+	rule2 := core.CreateAlertRule("test_colony1", "test_rule2", core.WaitingProcessesAlert, "", "", 10, 0, 0)
+	rule2.ID = core.GenerateRandomID()
+	err = db.AddAlertRule(rule2)
+	assert.Nil(t, err)
+
+	rule3 := core.CreateAlertRule("test_colony2", "test_rule1", core.WaitingProcessesAlert, "", "", 10, 0, 0)
+	rule3.ID = core.GenerateRandomID()
+	err = db.AddAlertRule(rule3)
+	assert.Nil(t, err)
+
+	err = db.RemoveAlertRuleByName("test_colony1", "test_rule1")
+	assert.Nil(t, err)
+
+	rules, err := db.GetAlertRulesByColonyName("test_colony1")
+	assert.Nil(t, err)
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "test_rule2", rules[0].Name)
+
+	err = db.RemoveAlertRulesByColonyName("test_colony1")
+	assert.Nil(t, err)
+
+	rules, err = db.GetAlertRulesByColonyName("test_colony1")
+	assert.Nil(t, err)
+	assert.Len(t, rules, 0)
+
+	rules, err = db.GetAlertRulesByColonyName("test_colony2")
+	assert.Nil(t, err)
+	assert.Len(t, rules, 1)
+}
@@ -1,4 +1,4 @@
-package postgresql
+package dbtest
 
 import (
 	"testing"
@@ -8,8 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestAttributeClosedDB(t *testing.T) {
-	db, err := PrepareTests()
+func testAttributeClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	db.Close()
@@ -70,8 +70,8 @@ func TestAttributeClosedDB(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestAddAttribute(t *testing.T) {
-	db, err := PrepareTests()
+func testAddAttribute(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -88,8 +88,8 @@ func TestAddAttribute(t *testing.T) {
 	assert.True(t, attribute.Equals(attributeFromDB))
 }
 
-func TestAddAttributes(t *testing.T) {
-	db, err := PrepareTests()
+func testAddAttributes(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -118,8 +118,8 @@ func TestAddAttributes(t *testing.T) {
 	assert.Len(t, attributesFromDB, 2)
 }
 
-func TestGetAttributes(t *testing.T) {
-	db, err := PrepareTests()
+func testGetAttributes(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -174,8 +174,8 @@ func TestGetAttributes(t *testing.T) {
 	assert.True(t, core.IsAttributeArraysEqual(allAttributes, attributesFromDB))
 }
 
-func TestGetAttributesByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetAttributesByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -214,8 +214,8 @@ func TestGetAttributesByColonyName(t *testing.T) {
 	assert.Len(t, attributesFromDB, 1)
 }
 
-func TestUpdateAttribute(t *testing.T) {
-	db, err := PrepareTests()
+func testUpdateAttribute(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -246,8 +246,8 @@ func TestUpdateAttribute(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestSetAttributeState(t *testing.T) {
-	db, err := PrepareTests()
+func testSetAttributeState(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -296,8 +296,8 @@ func TestSetAttributeState(t *testing.T) {
 	assert.Equal(t, attributeFromDB.State, 0)
 }
 
-func TestRemoveAttributes(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAttributes(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -399,8 +399,8 @@ func TestRemoveAttributes(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestRemoveAttributesByColonyNameWithState(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAttributesByColonyNameWithState(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -503,8 +503,8 @@ func TestRemoveAttributesByColonyNameWithState(t *testing.T) {
 	defer db.Close()
 }
 
-func TestRemoveAttributesInProcessGraphByColonyNameWithState(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAttributesInProcessGraphByColonyNameWithState(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -611,8 +611,8 @@ func TestRemoveAttributesInProcessGraphByColonyNameWithState(t *testing.T) {
 	defer db.Close()
 }
 
-func TestRemoveAllAttributesByProcessGraphID(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAllAttributesByProcessGraphID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -655,8 +655,8 @@ func TestRemoveAllAttributesByProcessGraphID(t *testing.T) {
 	assert.Len(t, attributesFromDB, 1)
 }
 
-func TestRemoveAllAttributesInProcesssGraphByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAllAttributesInProcesssGraphByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -1,4 +1,4 @@
-package postgresql
+package dbtest
 
 import (
 	"testing"
@@ -9,8 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestColonyClosedDB(t *testing.T) {
-	db, err := PrepareTests()
+func testColonyClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	db.Close()
@@ -36,8 +36,8 @@ func TestColonyClosedDB(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestAddColony(t *testing.T) {
-	db, err := PrepareTests()
+func testAddColony(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -64,8 +64,8 @@ func TestAddColony(t *testing.T) {
 	assert.True(t, colony.Equals(colonyFromDB))
 }
 
-func TestRenameColony(t *testing.T) {
-	db, err := PrepareTests()
+func testRenameColony(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -87,8 +87,8 @@ func TestRenameColony(t *testing.T) {
 	assert.Equal(t, colonyFromDB.Name, "test_colony_new_name")
 }
 
-func TestAddTwoColonies(t *testing.T) {
-	db, err := PrepareTests()
+func testAddTwoColonies(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -110,8 +110,8 @@ func TestAddTwoColonies(t *testing.T) {
 	assert.True(t, core.IsColonyArraysEqual(colonies, coloniesFromDB))
 }
 
-func TestGetColonyByID(t *testing.T) {
-	db, err := PrepareTests()
+func testGetColonyByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -134,8 +134,8 @@ func TestGetColonyByID(t *testing.T) {
 	assert.Nil(t, err)
 }
 
-func TestGetColonyByName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetColonyByName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -155,8 +155,8 @@ func TestGetColonyByName(t *testing.T) {
 	assert.Equal(t, colony1.ID, colonyFromDB.ID)
 }
 
-func TestRemoveColonies(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveColonies(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -327,8 +327,8 @@ func TestRemoveColonies(t *testing.T) {
 	assert.Len(t, snapshots, 1)
 }
 
-func TestCountColonies(t *testing.T) {
-	db, err := PrepareTests()
+func testCountColonies(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -354,8 +354,8 @@ func TestCountColonies(t *testing.T) {
 	assert.True(t, coloniesCount == 2)
 }
 
-func TestChangeColonyID(t *testing.T) {
-	db, err := PrepareTests()
+func testChangeColonyID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
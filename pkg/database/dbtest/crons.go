@@ -1,4 +1,4 @@
-package postgresql
+package dbtest
 
 import (
 	"testing"
@@ -8,8 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestCronClosedDB(t *testing.T) {
-	db, err := PrepareTests()
+func testCronClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	db.Close()
@@ -39,8 +39,8 @@ func TestCronClosedDB(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestAddCron(t *testing.T) {
-	db, err := PrepareTests()
+func testAddCron(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -57,8 +57,8 @@ func TestAddCron(t *testing.T) {
 	assert.True(t, cron.Equals(cronFromDB))
 }
 
-func TestUpdateCron(t *testing.T) {
-	db, err := PrepareTests()
+func testUpdateCron(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -97,8 +97,8 @@ func TestUpdateCron(t *testing.T) {
 	assert.Greater(t, cronFromDB.LastRun.Unix(), time.Time{}.Unix())
 }
 
-func TestFindCronsByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testFindCronsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -133,8 +133,8 @@ func TestFindCronsByColonyName(t *testing.T) {
 	assert.Len(t, crons, 1)
 }
 
-func TestFindAllCrons(t *testing.T) {
-	db, err := PrepareTests()
+func testFindAllCrons(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -161,8 +161,8 @@ func TestFindAllCrons(t *testing.T) {
 	assert.Len(t, crons, 3)
 }
 
-func TestRemoveCronByID(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveCronByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -184,8 +184,8 @@ func TestRemoveCronByID(t *testing.T) {
 	assert.Nil(t, cronFromDB)
 }
 
-func TestRemoveAllCronsByID(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAllCronsByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -0,0 +1,242 @@
+// Package dbtest contains the test suite that every database backend must pass, each backend runs it against its own
+// database with Run.
+package dbtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+
+	"github.com/colonyos/colonies/pkg/database"
+)
+
+// Database is the database interface plus the helpers that both backends export for their own use
+type Database interface {
+	database.Database
+	SetAttributeState(processID string, state int) error
+	RemoveFilesByColonyName(colonyName string) error
+	GetFileLabelByName(colonyName string, name string) (*core.Label, error)
+	RemoveAllProcessesInProcessGraphsByColonyNameWithState(colonyName string, state int) error
+	SetExecDeadline(process *core.Process, execDeadline time.Time) error
+	SetWaitDeadline(process *core.Process, waitDeadline time.Time) error
+}
+
+// Prepare returns a new empty database to run a test against
+type Prepare func() (Database, error)
+
+var tests = []struct {
+	name string
+	test func(t *testing.T, prepare Prepare)
+}{
+	{"AlertRuleClosedDB", testAlertRuleClosedDB},
+	{"AddAlertRule", testAddAlertRule},
+	{"UpdateAlertRuleState", testUpdateAlertRuleState},
+	{"RemoveAlertRules", testRemoveAlertRules},
+	{"AttributeClosedDB", testAttributeClosedDB},
+	{"AddAttribute", testAddAttribute},
+	{"AddAttributes", testAddAttributes},
+	{"GetAttributes", testGetAttributes},
+	{"GetAttributesByColonyName", testGetAttributesByColonyName},
+	{"UpdateAttribute", testUpdateAttribute},
+	{"SetAttributeState", testSetAttributeState},
+	{"RemoveAttributes", testRemoveAttributes},
+	{"RemoveAttributesByColonyNameWithState", testRemoveAttributesByColonyNameWithState},
+	{"RemoveAttributesInProcessGraphByColonyNameWithState", testRemoveAttributesInProcessGraphByColonyNameWithState},
+	{"RemoveAllAttributesByProcessGraphID", testRemoveAllAttributesByProcessGraphID},
+	{"RemoveAllAttributesInProcesssGraphByColonyName", testRemoveAllAttributesInProcesssGraphByColonyName},
+	{"ColonyClosedDB", testColonyClosedDB},
+	{"AddColony", testAddColony},
+	{"RenameColony", testRenameColony},
+	{"AddTwoColonies", testAddTwoColonies},
+	{"GetColonyByID", testGetColonyByID},
+	{"GetColonyByName", testGetColonyByName},
+	{"RemoveColonies", testRemoveColonies},
+	{"CountColonies", testCountColonies},
+	{"ChangeColonyID", testChangeColonyID},
+	{"CronClosedDB", testCronClosedDB},
+	{"AddCron", testAddCron},
+	{"UpdateCron", testUpdateCron},
+	{"FindCronsByColonyName", testFindCronsByColonyName},
+	{"FindAllCrons", testFindAllCrons},
+	{"RemoveCronByID", testRemoveCronByID},
+	{"RemoveAllCronsByID", testRemoveAllCronsByID},
+	{"EventsClosedDB", testEventsClosedDB},
+	{"AddEvent", testAddEvent},
+	{"AddEvents", testAddEvents},
+	{"RemoveExpiredEvents", testRemoveExpiredEvents},
+	{"RemoveAllExpiredEvents", testRemoveAllExpiredEvents},
+	{"RemoveExcessEvents", testRemoveExcessEvents},
+	{"RemoveEventsByColonyName", testRemoveEventsByColonyName},
+	{"RenameColonyEvents", testRenameColonyEvents},
+	{"ExecutorClosedDB", testExecutorClosedDB},
+	{"AddExecutor", testAddExecutor},
+	{"AddExecutorWithAllocations", testAddExecutorWithAllocations},
+	{"SetAllocations", testSetAllocations},
+	{"AddExecutors", testAddExecutors},
+	{"GetExecutorByID", testGetExecutorByID},
+	{"GetExecutorByColonyName", testGetExecutorByColonyName},
+	{"GetExecutorByName", testGetExecutorByName},
+	{"MarkAlive", testMarkAlive},
+	{"ApproveExecutor", testApproveExecutor},
+	{"RemoveExecutorMoveBackToQueue", testRemoveExecutorMoveBackToQueue},
+	{"RemoveExecutorsMoveBackToQueue", testRemoveExecutorsMoveBackToQueue},
+	{"RemoveExecutors", testRemoveExecutors},
+	{"CountExecutors", testCountExecutors},
+	{"CountExectorsByColonyName", testCountExectorsByColonyName},
+	{"ChangeExecutorID", testChangeExecutorID},
+	{"FederationClosedDB", testFederationClosedDB},
+	{"AddFederationPeer", testAddFederationPeer},
+	{"RemoveFederationPeers", testRemoveFederationPeers},
+	{"FederatedProcesses", testFederatedProcesses},
+	{"FenceJob", testFenceJob},
+	{"RunFencedJob", testRunFencedJob},
+	{"AddGetFile", testAddGetFile},
+	{"GetFileByName", testGetFileByName},
+	{"GetFileNamesByLabel", testGetFileNamesByLabel},
+	{"GetFileDataByLabel", testGetFileDataByLabel},
+	{"GetFileDataByLabelMultipleRevisions", testGetFileDataByLabelMultipleRevisions},
+	{"GetFileByNameAsOf", testGetFileByNameAsOf},
+	{"GetFileDataByLabelAsOf", testGetFileDataByLabelAsOf},
+	{"RemoveFileByID", testRemoveFileByID},
+	{"RemoveFilesByColonyName", testRemoveFilesByColonyName},
+	{"RemoveFileByName", testRemoveFileByName},
+	{"GetFileLabels", testGetFileLabels},
+	{"GetFileLabelsByName", testGetFileLabelsByName},
+	{"GetFileLabelByName", testGetFileLabelByName},
+	{"GetFileLabelsByNameOverlappingName", testGetFileLabelsByNameOverlappingName},
+	{"CountLabelFiles", testCountLabelFiles},
+	{"GetStorageUsageByLabel", testGetStorageUsageByLabel},
+	{"FunctionRunsClosedDB", testFunctionRunsClosedDB},
+	{"AddFunctionRun", testAddFunctionRun},
+	{"GetFunctionRunsBetween", testGetFunctionRunsBetween},
+	{"RemoveExpiredFunctionRuns", testRemoveExpiredFunctionRuns},
+	{"RemoveFunctionRunsByColonyName", testRemoveFunctionRunsByColonyName},
+	{"RenameColonyFunctionRuns", testRenameColonyFunctionRuns},
+	{"FunctionClosedDB", testFunctionClosedDB},
+	{"AddFunction", testAddFunction},
+	{"GetFunctionByExecutorIDAndName", testGetFunctionByExecutorIDAndName},
+	{"GetFunctionByID", testGetFunctionByID},
+	{"GetFunctionByColonyName", testGetFunctionByColonyName},
+	{"UpdateFunctionStats", testUpdateFunctionStats},
+	{"RemoveFunctionByExecutorID", testRemoveFunctionByExecutorID},
+	{"RemoveFunctionByID", testRemoveFunctionByID},
+	{"RemoveFunctionByName", testRemoveFunctionByName},
+	{"RemoveFunctionByColonyName", testRemoveFunctionByColonyName},
+	{"RemoveFunctions", testRemoveFunctions},
+	{"GeneratorArgClosedDB", testGeneratorArgClosedDB},
+	{"GeneratorArg", testGeneratorArg},
+	{"RemoveGeneratorArgByID", testRemoveGeneratorArgByID},
+	{"RemoveGeneratorArgByGeneratorID", testRemoveGeneratorArgByGeneratorID},
+	{"RemoveGeneratorArgByColonyName", testRemoveGeneratorArgByColonyName},
+	{"GeneratorClosedDB", testGeneratorClosedDB},
+	{"AddGenerator", testAddGenerator},
+	{"GetGeneratorByID", testGetGeneratorByID},
+	{"GetGeneratorByName", testGetGeneratorByName},
+	{"SetGeneratorLastRun", testSetGeneratorLastRun},
+	{"SetGeneratorFirstPack", testSetGeneratorFirstPack},
+	{"FindGeneratorsByColonyName", testFindGeneratorsByColonyName},
+	{"FindAllGenerators", testFindAllGenerators},
+	{"RemoveGeneratorByID", testRemoveGeneratorByID},
+	{"RemoveAllGeneratorsByColonyName", testRemoveAllGeneratorsByColonyName},
+	{"AddGetLogsByProcessID", testAddGetLogsByProcessID},
+	{"AddGetLogsByExecutor", testAddGetLogsByExecutor},
+	{"AddGetLogsByProcessIDSince", testAddGetLogsByProcessIDSince},
+	{"AddGetLogsByExecutorSince", testAddGetLogsByExecutorSince},
+	{"RemoveLogsByColonyName", testRemoveLogsByColonyName},
+	{"AddStructuredLog", testAddStructuredLog},
+	{"SearchLogsByLevelAndFields", testSearchLogsByLevelAndFields},
+	{"ProcessClosedDB", testProcessClosedDB},
+	{"AddProcess", testAddProcess},
+	{"AddProcessConditions", testAddProcessConditions},
+	{"AddProcessWithEnv", testAddProcessWithEnv},
+	{"RemoveProcesses", testRemoveProcesses},
+	{"RemoveAllProcessesByColony", testRemoveAllProcessesByColony},
+	{"RemoveAllProcessesByColonyWithState", testRemoveAllProcessesByColonyWithState},
+	{"RemoveAllProcessesByProcessGraphID", testRemoveAllProcessesByProcessGraphID},
+	{"RemoveAllProcessesInProcessGraphsByColonyName", testRemoveAllProcessesInProcessGraphsByColonyName},
+	{"RemoveAllProcessesInProcessGraphsByColonyNameWithState", testRemoveAllProcessesInProcessGraphsByColonyNameWithState},
+	{"RemoveAllProcessesAndAttributes", testRemoveAllProcessesAndAttributes},
+	{"RemoveProcessesAndAttributes", testRemoveProcessesAndAttributes},
+	{"Assign", testAssign},
+	{"MarkSuccessful", testMarkSuccessful},
+	{"MarkFailed", testMarkFailed},
+	{"ResetProcess", testResetProcess},
+	{"SetWaitingForParents", testSetWaitingForParents},
+	{"SetParents", testSetParents},
+	{"SetChildren", testSetChildren},
+	{"SetProcessState", testSetProcessState},
+	{"SetWaitDeadline", testSetWaitDeadline},
+	{"SetInput", testSetInput},
+	{"SetInput2", testSetInput2},
+	{"SetOutput", testSetOutput},
+	{"SetExecDeadline", testSetExecDeadline},
+	{"SetErrorMsg", testSetErrorMsg},
+	{"FindCandidates1", testFindCandidates1},
+	{"FindCandidates2", testFindCandidates2},
+	{"FindCandidates3", testFindCandidates3},
+	{"FindCandidates4", testFindCandidates4},
+	{"FindCandidatesOldest", testFindCandidatesOldest},
+	{"FindCandidatesByName", testFindCandidatesByName},
+	{"FindProcessAssigned", testFindProcessAssigned},
+	{"FindProcesses", testFindProcesses},
+	{"FindProcessesByFilter", testFindProcessesByFilter},
+	{"FindAllProcesses", testFindAllProcesses},
+	{"FindProcessesByExecutorID", testFindProcessesByExecutorID},
+	{"FindProcessesByColonyName", testFindProcessesByColonyName},
+	{"CountWaitingProcessesByExecutorType", testCountWaitingProcessesByExecutorType},
+	{"FindWaitingProcessesAfter", testFindWaitingProcessesAfter},
+	{"AddProcessWithTraceContext", testAddProcessWithTraceContext},
+	{"ProcessGraphClosedDB", testProcessGraphClosedDB},
+	{"AddProcessGraph", testAddProcessGraph},
+	{"RemoveProcessGraphByID", testRemoveProcessGraphByID},
+	{"RemoveAllProcessGraphsByColonyName", testRemoveAllProcessGraphsByColonyName},
+	{"RemoveAllWaitingProcessGraphsByColonyName", testRemoveAllWaitingProcessGraphsByColonyName},
+	{"RemoveAllRunningProcessGraphsByColonyName", testRemoveAllRunningProcessGraphsByColonyName},
+	{"RemoveAllSuccessfulProcessGraphsByColonyName", testRemoveAllSuccessfulProcessGraphsByColonyName},
+	{"RemoveAllFailedProcessGraphsByColonyName", testRemoveAllFailedProcessGraphsByColonyName},
+	{"SetProcessGraphState", testSetProcessGraphState},
+	{"FindProcessGraphs", testFindProcessGraphs},
+	{"RetentionClosedDB", testRetentionClosedDB},
+	{"ApplyRetentionPolicy", testApplyRetentionPolicy},
+	{"RetentionPolicies", testRetentionPolicies},
+	{"ApplyRetentionPolicySkipColoniesWithPolicy", testApplyRetentionPolicySkipColoniesWithPolicy},
+	{"ApplyRetentionPolicyFallbackToServerPeriod", testApplyRetentionPolicyFallbackToServerPeriod},
+	{"ApplyRetentionPolicyRunningProcessGraph", testApplyRetentionPolicyRunningProcessGraph},
+	{"FindExpiredLogsBatch", testFindExpiredLogsBatch},
+	{"FindExpired", testFindExpired},
+	{"FindExpiredFiles", testFindExpiredFiles},
+	{"ServerID", testServerID},
+	{"CreateSnapshot", testCreateSnapshot},
+	{"GetSnapshotByID", testGetSnapshotByID},
+	{"GetSnapshotByName", testGetSnapshotByName},
+	{"GetSnapshotsByColonyName", testGetSnapshotsByColonyName},
+	{"RemoveSnapshotByID", testRemoveSnapshotByID},
+	{"RemoveSnapshotByName", testRemoveSnapshotByName},
+	{"RemoveSnapshotsByColonyName", testRemoveSnapshotsByColonyName},
+	{"StatisticsSnapshotsClosedDB", testStatisticsSnapshotsClosedDB},
+	{"AddStatisticsSnapshot", testAddStatisticsSnapshot},
+	{"RemoveExpiredStatisticsSnapshots", testRemoveExpiredStatisticsSnapshots},
+	{"RemoveStatisticsSnapshotsByColonyName", testRemoveStatisticsSnapshotsByColonyName},
+	{"RenameColonyStatisticsSnapshots", testRenameColonyStatisticsSnapshots},
+	{"AddUser", testAddUser},
+	{"GetUsers", testGetUsers},
+	{"RemoveUser", testRemoveUser},
+	{"RemoveUsersByColonyName", testRemoveUsersByColonyName},
+	{"ChangeUserID", testChangeUserID},
+	{"WebhookClosedDB", testWebhookClosedDB},
+	{"AddWebhook", testAddWebhook},
+	{"RemoveWebhook", testRemoveWebhook},
+	{"WebhookDeliveries", testWebhookDeliveries},
+	{"RemoveWebhookDeliveries", testRemoveWebhookDeliveries},
+	{"RenameColonyWebhooks", testRenameColonyWebhooks},
+}
+
+// Run runs the test suite, each test as a subtest with a new database
+func Run(t *testing.T, prepare Prepare) {
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.test(t, prepare)
+		})
+	}
+}
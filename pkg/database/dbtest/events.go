@@ -0,0 +1,258 @@
+package dbtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestEvent(colonyName string) *core.Event {
+	file := &core.File{ID: core.GenerateRandomID(), ColonyName: colonyName, Label: "/test_label", Name: "test_file"}
+	return core.CreateFileEvent(core.FileAddedEvent, file)
+}
+
+func testEventsClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	db.Close()
+
+	err = db.AddEvent(createTestEvent("test_colony"))
+	assert.NotNil(t, err)
+
+	_, err = db.GetEvents("test_colony", 1, 10)
+	assert.NotNil(t, err)
+
+	err = db.RemoveEventsByColonyName("test_colony")
+	assert.NotNil(t, err)
+
+	err = db.RemoveExpiredEvents("test_colony", time.Now())
+	assert.NotNil(t, err)
+
+	err = db.AddEvents([]*core.Event{createTestEvent("test_colony")})
+	assert.NotNil(t, err)
+
+	err = db.RemoveAllExpiredEvents(time.Now())
+	assert.NotNil(t, err)
+
+	err = db.RemoveExcessEvents(10)
+	assert.NotNil(t, err)
+}
+
+func testAddEvent(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	var events []*core.Event
+	for i := 0; i < 5; i++ {
+		event := createTestEvent("test_colony1")
+		err = db.AddEvent(event)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(i+1), event.Seq)
+		events = append(events, event)
+	}
+
+	// Sequence numbers are per colony
+	event := createTestEvent("test_colony2")
+	err = db.AddEvent(event)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), event.Seq)
+
+	eventsFromDB, err := db.GetEvents("test_colony1", 1, 100)
+	assert.Nil(t, err)
+	assert.Len(t, eventsFromDB, 5)
+	for i := range events {
+		assert.True(t, events[i].Equals(eventsFromDB[i]))
+		assert.NotNil(t, eventsFromDB[i].File)
+	}
+
+	eventsFromDB, err = db.GetEvents("test_colony1", 3, 100)
+	assert.Nil(t, err)
+	assert.Len(t, eventsFromDB, 3)
+	assert.Equal(t, int64(3), eventsFromDB[0].Seq)
+
+	eventsFromDB, err = db.GetEvents("test_colony1", 2, 2)
+	assert.Nil(t, err)
+	assert.Len(t, eventsFromDB, 2)
+	assert.Equal(t, int64(2), eventsFromDB[0].Seq)
+	assert.Equal(t, int64(3), eventsFromDB[1].Seq)
+
+	eventsFromDB, err = db.GetEvents("test_colony1", 6, 100)
+	assert.Nil(t, err)
+	assert.Len(t, eventsFromDB, 0)
+}
+
+func testAddEvents(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	err = db.AddEvent(createTestEvent("test_colony1"))
+	assert.Nil(t, err)
+
+	events := []*core.Event{createTestEvent("test_colony1"), createTestEvent("test_colony2"), createTestEvent("test_colony1")}
+	err = db.AddEvents(events)
+	assert.Nil(t, err)
+
+	// Events of the same colony get consecutive sequence numbers in the given order
+	assert.Equal(t, int64(2), events[0].Seq)
+	assert.Equal(t, int64(1), events[1].Seq)
+	assert.Equal(t, int64(3), events[2].Seq)
+
+	eventsFromDB, err := db.GetEvents("test_colony1", 1, 100)
+	assert.Nil(t, err)
+	assert.Len(t, eventsFromDB, 3)
+	assert.True(t, events[0].Equals(eventsFromDB[1]))
+	assert.True(t, events[2].Equals(eventsFromDB[2]))
+
+	eventsFromDB, err = db.GetEvents("test_colony2", 1, 100)
+	assert.Nil(t, err)
+	assert.Len(t, eventsFromDB, 1)
+	assert.True(t, events[1].Equals(eventsFromDB[0]))
+
+	err = db.AddEvents(nil)
+	assert.Nil(t, err)
+}
+
+func testRemoveExpiredEvents(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	oldEvent := createTestEvent("test_colony")
+	oldEvent.Time = time.Now().Add(-time.Hour)
+	err = db.AddEvent(oldEvent)
+	assert.Nil(t, err)
+
+	err = db.AddEvent(createTestEvent("test_colony"))
+	assert.Nil(t, err)
+
+	err = db.RemoveExpiredEvents("test_colony", time.Now().Add(-time.Minute))
+	assert.Nil(t, err)
+
+	events, err := db.GetEvents("test_colony", 1, 100)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, int64(2), events[0].Seq)
+
+	// Sequence numbers keep increasing after events have been removed
+	err = db.RemoveExpiredEvents("test_colony", time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+
+	event := createTestEvent("test_colony")
+	err = db.AddEvent(event)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), event.Seq)
+}
+
+func testRemoveAllExpiredEvents(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	for _, colonyName := range []string{"test_colony1", "test_colony2"} {
+		oldEvent := createTestEvent(colonyName)
+		oldEvent.Time = time.Now().Add(-time.Hour)
+		err = db.AddEvent(oldEvent)
+		assert.Nil(t, err)
+
+		err = db.AddEvent(createTestEvent(colonyName))
+		assert.Nil(t, err)
+	}
+
+	err = db.RemoveAllExpiredEvents(time.Now().Add(-time.Minute))
+	assert.Nil(t, err)
+
+	for _, colonyName := range []string{"test_colony1", "test_colony2"} {
+		events, err := db.GetEvents(colonyName, 1, 100)
+		assert.Nil(t, err)
+		assert.Len(t, events, 1)
+		assert.Equal(t, int64(2), events[0].Seq)
+	}
+}
+
+func testRemoveExcessEvents(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	for i := 0; i < 5; i++ {
+		err = db.AddEvent(createTestEvent("test_colony1"))
+		assert.Nil(t, err)
+	}
+
+	err = db.AddEvent(createTestEvent("test_colony2"))
+	assert.Nil(t, err)
+
+	err = db.RemoveExcessEvents(2)
+	assert.Nil(t, err)
+
+	events, err := db.GetEvents("test_colony1", 1, 100)
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(4), events[0].Seq)
+	assert.Equal(t, int64(5), events[1].Seq)
+
+	events, err = db.GetEvents("test_colony2", 1, 100)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+}
+
+func testRemoveEventsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	err = db.AddEvent(createTestEvent("test_colony1"))
+	assert.Nil(t, err)
+
+	err = db.AddEvent(createTestEvent("test_colony2"))
+	assert.Nil(t, err)
+
+	err = db.RemoveEventsByColonyName("test_colony1")
+	assert.Nil(t, err)
+
+	events, err := db.GetEvents("test_colony1", 1, 100)
+	assert.Nil(t, err)
+	assert.Len(t, events, 0)
+
+	events, err = db.GetEvents("test_colony2", 1, 100)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+}
+
+func testRenameColonyEvents(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colony := core.CreateColony(core.GenerateRandomID(), "test_colony")
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	err = db.AddEvent(createTestEvent("test_colony"))
+	assert.Nil(t, err)
+
+	err = db.RenameColony("test_colony", "test_colony_renamed")
+	assert.Nil(t, err)
+
+	events, err := db.GetEvents("test_colony_renamed", 1, 100)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "test_colony_renamed", events[0].ColonyName)
+
+	event := createTestEvent("test_colony_renamed")
+	err = db.AddEvent(event)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), event.Seq)
+}
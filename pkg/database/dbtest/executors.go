@@ -1,4 +1,4 @@
-package postgresql
+package dbtest
 
 import (
 	"testing"
@@ -9,8 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestExecutorClosedDB(t *testing.T) {
-	db, err := PrepareTests()
+func testExecutorClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	db.Close()
@@ -53,8 +53,8 @@ func TestExecutorClosedDB(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestAddExecutor(t *testing.T) {
-	db, err := PrepareTests()
+func testAddExecutor(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -106,8 +106,8 @@ func TestAddExecutor(t *testing.T) {
 	assert.Equal(t, executor.Capabilities.Hardware.GPU.Memory, "10G")
 }
 
-func TestAddExecutorWithAllocations(t *testing.T) {
-	db, err := PrepareTests()
+func testAddExecutorWithAllocations(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -137,8 +137,8 @@ func TestAddExecutorWithAllocations(t *testing.T) {
 	assert.Equal(t, testProj.UsedStorage, int64(6))
 }
 
-func TestSetAllocations(t *testing.T) {
-	db, err := PrepareTests()
+func testSetAllocations(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -187,8 +187,8 @@ func TestSetAllocations(t *testing.T) {
 	assert.Equal(t, testProj.UsedStorage, int64(12))
 }
 
-func TestAddExecutors(t *testing.T) {
-	db, err := PrepareTests()
+func testAddExecutors(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -232,8 +232,8 @@ func TestAddExecutors(t *testing.T) {
 	assert.True(t, core.IsExecutorArraysEqual(executors, executorsFromDB))
 }
 
-func TestGetExecutorByID(t *testing.T) {
-	db, err := PrepareTests()
+func testGetExecutorByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -260,8 +260,8 @@ func TestGetExecutorByID(t *testing.T) {
 	assert.True(t, executor1.Equals(executorFromDB))
 }
 
-func TestGetExecutorByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetExecutorByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -301,8 +301,8 @@ func TestGetExecutorByColonyName(t *testing.T) {
 	assert.True(t, core.IsExecutorArraysEqual(executorsColony1, executorsColony1FromDB))
 }
 
-func TestGetExecutorByName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetExecutorByName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -339,8 +339,8 @@ func TestGetExecutorByName(t *testing.T) {
 	assert.True(t, executor1.Equals(executorFromDB))
 }
 
-func TestMarkAlive(t *testing.T) {
-	db, err := PrepareTests()
+func testMarkAlive(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -365,8 +365,8 @@ func TestMarkAlive(t *testing.T) {
 	assert.True(t, (executorFromDB.LastHeardFromTime.Unix()-executor.LastHeardFromTime.Unix()) > 1)
 }
 
-func TestApproveExecutor(t *testing.T) {
-	db, err := PrepareTests()
+func testApproveExecutor(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -402,8 +402,8 @@ func TestApproveExecutor(t *testing.T) {
 	assert.True(t, executor.IsRejected())
 }
 
-func TestRemoveExecutorMoveBackToQueue(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveExecutorMoveBackToQueue(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -529,8 +529,8 @@ func TestRemoveExecutorMoveBackToQueue(t *testing.T) {
 	assert.True(t, count == 0)
 }
 
-func TestRemoveExecutorsMoveBackToQueue(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveExecutorsMoveBackToQueue(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -634,8 +634,8 @@ func TestRemoveExecutorsMoveBackToQueue(t *testing.T) {
 	assert.True(t, count == 1)
 }
 
-func TestRemoveExecutors(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveExecutors(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -717,8 +717,8 @@ func TestRemoveExecutors(t *testing.T) {
 	assert.Len(t, functions, 1)
 }
 
-func TestCountExecutors(t *testing.T) {
-	db, err := PrepareTests()
+func testCountExecutors(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -740,8 +740,8 @@ func TestCountExecutors(t *testing.T) {
 	assert.True(t, executorCount == 1)
 }
 
-func TestCountExectorsByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testCountExectorsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -780,8 +780,8 @@ func TestCountExectorsByColonyName(t *testing.T) {
 
 }
 
-func TestChangeExecutorID(t *testing.T) {
-	db, err := PrepareTests()
+func testChangeExecutorID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	colonyName := core.GenerateRandomID()
@@ -0,0 +1,179 @@
+package dbtest
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func testFederationClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	db.Close()
+
+	peer := core.CreateFederationPeer("test_colony", "test_peer", "test_server_id", "localhost", 50080, true, "test_remote_colony", []string{"test_executor_type"}, []string{})
+	peer.ID = core.GenerateRandomID()
+
+	err = db.AddFederationPeer(peer)
+	assert.NotNil(t, err)
+
+	_, err = db.GetFederationPeerByID(peer.ID)
+	assert.NotNil(t, err)
+
+	_, err = db.GetFederationPeerByName("test_colony", "test_peer")
+	assert.NotNil(t, err)
+
+	_, err = db.GetFederationPeersByColonyName("test_colony")
+	assert.NotNil(t, err)
+
+	err = db.RemoveFederationPeerByName("test_colony", "test_peer")
+	assert.NotNil(t, err)
+
+	err = db.RemoveFederationPeersByColonyName("test_colony")
+	assert.NotNil(t, err)
+
+	err = db.AddFederatedProcess(core.CreateFederatedProcess(core.GenerateRandomID(), "test_colony", peer.ID, core.GenerateRandomID()))
+	assert.NotNil(t, err)
+
+	_, err = db.GetFederatedProcesses(100)
+	assert.NotNil(t, err)
+
+	err = db.RemoveFederatedProcess(core.GenerateRandomID())
+	assert.NotNil(t, err)
+}
+
+func testAddFederationPeer(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	peer1 := core.CreateFederationPeer("test_colony1", "test_peer1", "test_server_id1", "localhost", 50080, true, "test_remote_colony", []string{"test_executor_type"}, []string{"gpu"})
+	peer1.ID = core.GenerateRandomID()
+	err = db.AddFederationPeer(peer1)
+	assert.Nil(t, err)
+
+	peer2 := core.CreateFederationPeer("test_colony1", "test_peer2", "test_server_id2", "localhost", 50081, false, "test_remote_colony", []string{}, []string{})
+	peer2.ID = core.GenerateRandomID()
+	err = db.AddFederationPeer(peer2)
+	assert.Nil(t, err)
+
+	peer3 := core.CreateFederationPeer("test_colony2", "test_peer1", "test_server_id1", "localhost", 50080, true, "test_remote_colony", []string{"test_executor_type"}, []string{})
+	peer3.ID = core.GenerateRandomID()
+	err = db.AddFederationPeer(peer3)
+	assert.Nil(t, err)
+
+	peerFromDB, err := db.GetFederationPeerByID(peer1.ID)
+	assert.Nil(t, err)
+	assert.True(t, peer1.Equals(peerFromDB))
+
+	peerFromDB, err = db.GetFederationPeerByName("test_colony1", "test_peer2")
+	assert.Nil(t, err)
+	assert.True(t, peer2.Equals(peerFromDB))
+
+	peerFromDB, err = db.GetFederationPeerByName("test_colony1", "test_peer3")
+	assert.Nil(t, err)
+	assert.Nil(t, peerFromDB)
+
+	peers, err := db.GetFederationPeersByColonyName("test_colony1")
+	assert.Nil(t, err)
+	assert.Len(t, peers, 2)
+
+	peers, err = db.GetFederationPeersByColonyName("test_colony3")
+	assert.Nil(t, err)
+	assert.Len(t, peers, 0)
+}
+
+func testRemoveFederationPeers(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	peer1 := core.CreateFederationPeer("test_colony1", "test_peer1", "test_server_id1", "localhost", 50080, true, "test_remote_colony", []string{"test_executor_type"}, []string{})
+	peer1.ID = core.GenerateRandomID()
+	err = db.AddFederationPeer(peer1)
+	assert.Nil(t, err)
+
+	peer2 := core.CreateFederationPeer("test_colony1", "test_peer2", "test_server_id2", "localhost", 50081, true, "test_remote_colony", []string{"test_executor_type"}, []string{})
+	peer2.ID = core.GenerateRandomID()
+	err = db.AddFederationPeer(peer2)
+	assert.Nil(t, err)
+
+	peer3 := core.CreateFederationPeer("test_colony2", "test_peer1", "test_server_id1", "localhost", 50080, true, "test_remote_colony", []string{"test_executor_type"}, []string{})
+	peer3.ID = core.GenerateRandomID()
+	err = db.AddFederationPeer(peer3)
+	assert.Nil(t, err)
+
+	err = db.AddFederatedProcess(core.CreateFederatedProcess(core.GenerateRandomID(), "test_colony1", peer1.ID, core.GenerateRandomID()))
+	assert.Nil(t, err)
+	err = db.AddFederatedProcess(core.CreateFederatedProcess(core.GenerateRandomID(), "test_colony1", peer2.ID, core.GenerateRandomID()))
+	assert.Nil(t, err)
+	err = db.AddFederatedProcess(core.CreateFederatedProcess(core.GenerateRandomID(), "test_colony2", peer3.ID, core.GenerateRandomID()))
+	assert.Nil(t, err)
+
+	// Processes forwarded to a removed peer are still tracked until they have been reset
+	err = db.RemoveFederationPeerByName("test_colony1", "test_peer1")
+	assert.Nil(t, err)
+
+	peers, err := db.GetFederationPeersByColonyName("test_colony1")
+	assert.Nil(t, err)
+	assert.Len(t, peers, 1)
+	assert.Equal(t, "test_peer2", peers[0].Name)
+
+	federatedProcesses, err := db.GetFederatedProcesses(100)
+	assert.Nil(t, err)
+	assert.Len(t, federatedProcesses, 3)
+
+	err = db.RemoveFederationPeersByColonyName("test_colony1")
+	assert.Nil(t, err)
+
+	peers, err = db.GetFederationPeersByColonyName("test_colony1")
+	assert.Nil(t, err)
+	assert.Len(t, peers, 0)
+
+	peers, err = db.GetFederationPeersByColonyName("test_colony2")
+	assert.Nil(t, err)
+	assert.Len(t, peers, 1)
+
+	federatedProcesses, err = db.GetFederatedProcesses(100)
+	assert.Nil(t, err)
+	assert.Len(t, federatedProcesses, 1)
+	assert.Equal(t, peer3.ID, federatedProcesses[0].PeerID)
+}
+
+func testFederatedProcesses(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	federatedProcess1 := core.CreateFederatedProcess(core.GenerateRandomID(), "test_colony", core.GenerateRandomID(), core.GenerateRandomID())
+	err = db.AddFederatedProcess(federatedProcess1)
+	assert.Nil(t, err)
+
+	federatedProcess2 := core.CreateFederatedProcess(core.GenerateRandomID(), "test_colony", core.GenerateRandomID(), core.GenerateRandomID())
+	err = db.AddFederatedProcess(federatedProcess2)
+	assert.Nil(t, err)
+
+	federatedProcesses, err := db.GetFederatedProcesses(100)
+	assert.Nil(t, err)
+	assert.Len(t, federatedProcesses, 2)
+	assert.Equal(t, federatedProcess1.ProcessID, federatedProcesses[0].ProcessID)
+	assert.Equal(t, federatedProcess1.RemoteProcessID, federatedProcesses[0].RemoteProcessID)
+	assert.Equal(t, federatedProcess1.PeerID, federatedProcesses[0].PeerID)
+
+	federatedProcesses, err = db.GetFederatedProcesses(1)
+	assert.Nil(t, err)
+	assert.Len(t, federatedProcesses, 1)
+
+	err = db.RemoveFederatedProcess(federatedProcess1.ProcessID)
+	assert.Nil(t, err)
+
+	federatedProcesses, err = db.GetFederatedProcesses(100)
+	assert.Nil(t, err)
+	assert.Len(t, federatedProcesses, 1)
+	assert.Equal(t, federatedProcess2.ProcessID, federatedProcesses[0].ProcessID)
+}
@@ -1,4 +1,4 @@
-package postgresql
+package dbtest
 
 import (
 	"testing"
@@ -9,8 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestAddGetFile(t *testing.T) {
-	db, err := PrepareTests()
+func testAddGetFile(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -32,8 +32,8 @@ func TestAddGetFile(t *testing.T) {
 	assert.True(t, file.Equals(fileFromDB))
 }
 
-func TestGetFileByName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFileByName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -64,8 +64,8 @@ func TestGetFileByName(t *testing.T) {
 	assert.Len(t, filesFromDB, 2)
 }
 
-func TestGetFileNamesByLabel(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFileNamesByLabel(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -112,8 +112,8 @@ func TestGetFileNamesByLabel(t *testing.T) {
 	assert.Len(t, filesnames, 1)
 }
 
-func TestGetFileDataByLabel(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFileDataByLabel(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -160,8 +160,8 @@ func TestGetFileDataByLabel(t *testing.T) {
 	assert.Len(t, fileDataArr, 1)
 }
 
-func TestGetFileDataByLabelMultipleRevisions(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFileDataByLabelMultipleRevisions(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -204,8 +204,101 @@ func TestGetFileDataByLabelMultipleRevisions(t *testing.T) {
 	assert.Len(t, fileDataArr, 1)
 }
 
-func TestRemoveFileByID(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFileByNameAsOf(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	beforeFirst := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	file1 := utils.CreateTestFileWithID("test_id", "test_colonyid", time.Now())
+	file1.ID = core.GenerateRandomID()
+	file1.Label = "/testpath"
+	file1.Name = "test_file.txt"
+	file1.Size = 1
+	err = db.AddFile(file1)
+	assert.Nil(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	afterFirst := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	file2 := utils.CreateTestFileWithID("test_id", "test_colonyid", time.Now())
+	file2.ID = core.GenerateRandomID()
+	file2.Label = "/testpath"
+	file2.Name = "test_file.txt"
+	file2.Size = 2
+	err = db.AddFile(file2)
+	assert.Nil(t, err)
+
+	filesFromDB, err := db.GetFileByNameAsOf("test_colonyid", "/testpath", "test_file.txt", beforeFirst)
+	assert.Nil(t, err)
+	assert.Len(t, filesFromDB, 0)
+
+	filesFromDB, err = db.GetFileByNameAsOf("test_colonyid", "/testpath", "test_file.txt", afterFirst)
+	assert.Nil(t, err)
+	assert.Len(t, filesFromDB, 1)
+	assert.Equal(t, filesFromDB[0].ID, file1.ID)
+
+	filesFromDB, err = db.GetFileByNameAsOf("test_colonyid", "/testpath", "test_file.txt", time.Now())
+	assert.Nil(t, err)
+	assert.Len(t, filesFromDB, 1)
+	assert.Equal(t, filesFromDB[0].ID, file2.ID)
+}
+
+func testGetFileDataByLabelAsOf(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	file1 := utils.CreateTestFileWithID("test_id", "test_colonyid", time.Now())
+	file1.ID = core.GenerateRandomID()
+	file1.Label = "/samedir"
+	file1.Name = "test_file.txt"
+	file1.Size = 1
+	err = db.AddFile(file1)
+	assert.Nil(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	afterFirst := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	file2 := utils.CreateTestFileWithID("test_id", "test_colonyid", time.Now())
+	file2.ID = core.GenerateRandomID()
+	file2.Label = "/samedir"
+	file2.Name = "test_file.txt"
+	file2.Size = 2
+	err = db.AddFile(file2)
+	assert.Nil(t, err)
+
+	file3 := utils.CreateTestFileWithID("test_id", "test_colonyid", time.Now())
+	file3.ID = core.GenerateRandomID()
+	file3.Label = "/samedir"
+	file3.Name = "test_file2.txt"
+	file3.Size = 1
+	err = db.AddFile(file3)
+	assert.Nil(t, err)
+
+	fileDataArr, err := db.GetFileDataByLabelAsOf("test_colonyid", "/samedir", afterFirst)
+	assert.Nil(t, err)
+	assert.Len(t, fileDataArr, 1)
+	assert.Equal(t, fileDataArr[0].Size, int64(1))
+
+	fileDataArr, err = db.GetFileDataByLabelAsOf("test_colonyid", "/samedir", time.Now())
+	assert.Nil(t, err)
+	assert.Len(t, fileDataArr, 2)
+	for _, fileData := range fileDataArr {
+		if fileData.Name == "test_file.txt" {
+			assert.Equal(t, fileData.Size, int64(2))
+		}
+	}
+}
+
+func testRemoveFileByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -247,8 +340,8 @@ func TestRemoveFileByID(t *testing.T) {
 	assert.Nil(t, file1FromDB)
 }
 
-func TestRemoveFilesByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveFilesByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -298,8 +391,8 @@ func TestRemoveFilesByColonyName(t *testing.T) {
 	assert.Equal(t, files, 0)
 }
 
-func TestRemoveFileByName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveFileByName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -364,8 +457,8 @@ func TestRemoveFileByName(t *testing.T) {
 	assert.NotNil(t, fileFromDB)
 }
 
-func TestGetFileLabels(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFileLabels(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -414,8 +507,8 @@ func TestGetFileLabels(t *testing.T) {
 	assert.Equal(t, files, 4)
 }
 
-func TestGetFileLabelsByName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFileLabelsByName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -484,8 +577,8 @@ func TestGetFileLabelsByName(t *testing.T) {
 	assert.Equal(t, counter, 1)
 }
 
-func TestGetFileLabelByName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFileLabelByName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -508,8 +601,8 @@ func TestGetFileLabelByName(t *testing.T) {
 	assert.NotNil(t, label)
 }
 
-func TestGetFileLabelsByNameOverlappingName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFileLabelsByNameOverlappingName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -545,8 +638,8 @@ func TestGetFileLabelsByNameOverlappingName(t *testing.T) {
 	assert.Len(t, labels, 2)
 }
 
-func TestCountLabelFiles(t *testing.T) {
-	db, err := PrepareTests()
+func testCountLabelFiles(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -600,3 +693,48 @@ func TestCountLabelFiles(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, count, 0)
 }
+
+func testGetStorageUsageByLabel(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	file := utils.CreateTestFile("test_colony")
+	file.ID = core.GenerateRandomID()
+	file.Label = "/testlabel1"
+	file.Size = 100
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	file = utils.CreateTestFile("test_colony")
+	file.ID = core.GenerateRandomID()
+	file.Label = "/testlabel1"
+	file.Size = 50
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	file = utils.CreateTestFile("test_colony")
+	file.ID = core.GenerateRandomID()
+	file.Label = "/testlabel2"
+	file.Size = 10
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	file = utils.CreateTestFile("test_colony2")
+	file.ID = core.GenerateRandomID()
+	file.Label = "/testlabel1"
+	file.Size = 1000
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	storage, err := db.GetStorageUsageByLabel("test_colony", time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Len(t, storage, 2)
+	assert.Equal(t, int64(150), storage["/testlabel1"])
+	assert.Equal(t, int64(10), storage["/testlabel2"])
+
+	storage, err = db.GetStorageUsageByLabel("test_colony", time.Now().Add(-time.Hour))
+	assert.Nil(t, err)
+	assert.Len(t, storage, 0)
+}
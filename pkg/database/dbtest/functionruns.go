@@ -0,0 +1,199 @@
+package dbtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestFunctionRun(colonyName string, funcName string, executorType string, endTime time.Time) *core.FunctionRun {
+	return &core.FunctionRun{
+		ProcessID:     core.GenerateRandomID(),
+		ColonyName:    colonyName,
+		FuncName:      funcName,
+		ExecutorType:  executorType,
+		ExecutorName:  "test_executor",
+		WaitTime:      1.5,
+		ExecTime:      2.5,
+		Successful:    true,
+		EndTime:       endTime,
+		InitiatorName: "test_user",
+		Label:         "test_label",
+		CPU:           2000,
+		GPUs:          1,
+	}
+}
+
+func testFunctionRunsClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	db.Close()
+
+	err = db.AddFunctionRun(createTestFunctionRun("test_colony", "test_func", "test_executor_type", time.Now()))
+	assert.NotNil(t, err)
+
+	_, err = db.GetFunctionRuns("test_colony", "", "", time.Time{}, 10)
+	assert.NotNil(t, err)
+
+	_, err = db.GetFunctionRunsBetween("test_colony", time.Time{}, time.Now(), 10)
+	assert.NotNil(t, err)
+
+	err = db.RemoveFunctionRunsByColonyName("test_colony")
+	assert.NotNil(t, err)
+
+	err = db.RemoveExpiredFunctionRuns("test_colony", time.Now())
+	assert.NotNil(t, err)
+}
+
+func testAddFunctionRun(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	now := time.Now()
+	run1 := createTestFunctionRun("test_colony", "test_func1", "test_executor_type1", now.Add(-time.Hour))
+	run1.Successful = false
+	run1.Anomaly = true
+	err = db.AddFunctionRun(run1)
+	assert.Nil(t, err)
+
+	run2 := createTestFunctionRun("test_colony", "test_func1", "test_executor_type2", now.Add(-time.Minute))
+	err = db.AddFunctionRun(run2)
+	assert.Nil(t, err)
+
+	run3 := createTestFunctionRun("test_colony", "test_func2", "test_executor_type1", now)
+	err = db.AddFunctionRun(run3)
+	assert.Nil(t, err)
+
+	err = db.AddFunctionRun(createTestFunctionRun("test_colony2", "test_func1", "test_executor_type1", now))
+	assert.Nil(t, err)
+
+	runs, err := db.GetFunctionRuns("test_colony", "", "", time.Time{}, 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 3)
+	assert.True(t, run3.Equals(runs[0])) // Newest first
+	assert.True(t, run2.Equals(runs[1]))
+	assert.True(t, run1.Equals(runs[2]))
+
+	runs, err = db.GetFunctionRuns("test_colony", "test_func1", "", time.Time{}, 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 2)
+
+	runs, err = db.GetFunctionRuns("test_colony", "test_func1", "test_executor_type1", time.Time{}, 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 1)
+	assert.True(t, run1.Equals(runs[0]))
+
+	runs, err = db.GetFunctionRuns("test_colony", "", "", now.Add(-10*time.Minute), 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 2)
+
+	runs, err = db.GetFunctionRuns("test_colony", "", "", time.Time{}, 1)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 1)
+	assert.True(t, run3.Equals(runs[0]))
+}
+
+func testGetFunctionRunsBetween(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	now := time.Now()
+	run1 := createTestFunctionRun("test_colony", "test_func1", "test_executor_type1", now.Add(-2*time.Hour))
+	err = db.AddFunctionRun(run1)
+	assert.Nil(t, err)
+
+	run2 := createTestFunctionRun("test_colony", "test_func2", "test_executor_type2", now.Add(-time.Hour))
+	err = db.AddFunctionRun(run2)
+	assert.Nil(t, err)
+
+	run3 := createTestFunctionRun("test_colony", "test_func1", "test_executor_type1", now)
+	err = db.AddFunctionRun(run3)
+	assert.Nil(t, err)
+
+	err = db.AddFunctionRun(createTestFunctionRun("test_colony2", "test_func1", "test_executor_type1", now.Add(-time.Hour)))
+	assert.Nil(t, err)
+
+	runs, err := db.GetFunctionRunsBetween("test_colony", now.Add(-3*time.Hour), now.Add(-30*time.Minute), 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 2)
+	assert.True(t, run1.Equals(runs[0])) // Oldest first
+	assert.True(t, run2.Equals(runs[1]))
+
+	runs, err = db.GetFunctionRunsBetween("test_colony", now.Add(-3*time.Hour), now.Add(time.Minute), 1)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 1)
+	assert.True(t, run1.Equals(runs[0]))
+}
+
+func testRemoveExpiredFunctionRuns(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	err = db.AddFunctionRun(createTestFunctionRun("test_colony", "test_func", "test_executor_type", time.Now().Add(-time.Hour)))
+	assert.Nil(t, err)
+
+	err = db.AddFunctionRun(createTestFunctionRun("test_colony", "test_func", "test_executor_type", time.Now()))
+	assert.Nil(t, err)
+
+	err = db.RemoveExpiredFunctionRuns("test_colony", time.Now().Add(-time.Minute))
+	assert.Nil(t, err)
+
+	runs, err := db.GetFunctionRuns("test_colony", "", "", time.Time{}, 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 1)
+}
+
+func testRemoveFunctionRunsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	err = db.AddFunctionRun(createTestFunctionRun("test_colony1", "test_func", "test_executor_type", time.Now()))
+	assert.Nil(t, err)
+
+	err = db.AddFunctionRun(createTestFunctionRun("test_colony2", "test_func", "test_executor_type", time.Now()))
+	assert.Nil(t, err)
+
+	err = db.RemoveFunctionRunsByColonyName("test_colony1")
+	assert.Nil(t, err)
+
+	runs, err := db.GetFunctionRuns("test_colony1", "", "", time.Time{}, 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 0)
+
+	runs, err = db.GetFunctionRuns("test_colony2", "", "", time.Time{}, 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 1)
+}
+
+func testRenameColonyFunctionRuns(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colony := core.CreateColony(core.GenerateRandomID(), "test_colony")
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	err = db.AddFunctionRun(createTestFunctionRun("test_colony", "test_func", "test_executor_type", time.Now()))
+	assert.Nil(t, err)
+
+	err = db.RenameColony("test_colony", "test_colony_renamed")
+	assert.Nil(t, err)
+
+	runs, err := db.GetFunctionRuns("test_colony_renamed", "", "", time.Time{}, 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 1)
+	assert.Equal(t, "test_colony_renamed", runs[0].ColonyName)
+}
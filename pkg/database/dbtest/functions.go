@@ -1,4 +1,4 @@
-package postgresql
+package dbtest
 
 import (
 	"testing"
@@ -7,8 +7,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestFunctionClosedDB(t *testing.T) {
-	db, err := PrepareTests()
+func testFunctionClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	db.Close()
@@ -62,8 +62,8 @@ func TestFunctionClosedDB(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestAddFunction(t *testing.T) {
-	db, err := PrepareTests()
+func testAddFunction(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -91,8 +91,8 @@ func TestAddFunction(t *testing.T) {
 	assert.True(t, function1.Equals(functions[0]))
 }
 
-func TestGetFunctionByExecutorIDAndName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFunctionByExecutorIDAndName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -122,8 +122,8 @@ func TestGetFunctionByExecutorIDAndName(t *testing.T) {
 	assert.Nil(t, functionFromDB)
 }
 
-func TestGetFunctionByID(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFunctionByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -141,8 +141,8 @@ func TestGetFunctionByID(t *testing.T) {
 	assert.True(t, function1.Equals(function2))
 }
 
-func TestGetFunctionByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetFunctionByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -165,8 +165,8 @@ func TestGetFunctionByColonyName(t *testing.T) {
 	assert.Len(t, functions, 2)
 }
 
-func TestUpdateFunctionStats(t *testing.T) {
-	db, err := PrepareTests()
+func testUpdateFunctionStats(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -198,8 +198,8 @@ func TestUpdateFunctionStats(t *testing.T) {
 	assert.Equal(t, functions[0].AvgExecTime, 2.1)
 }
 
-func TestRemoveFunctionByExecutorID(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveFunctionByExecutorID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -226,8 +226,8 @@ func TestRemoveFunctionByExecutorID(t *testing.T) {
 	assert.Len(t, functions, 1)
 }
 
-func TestRemoveFunctionByID(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveFunctionByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -256,8 +256,8 @@ func TestRemoveFunctionByID(t *testing.T) {
 	assert.True(t, functions[0].Equals(function2))
 }
 
-func TestRemoveFunctionByName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveFunctionByName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -286,8 +286,8 @@ func TestRemoveFunctionByName(t *testing.T) {
 	assert.True(t, functions[0].Equals(function2))
 }
 
-func TestRemoveFunctionByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveFunctionByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -326,8 +326,8 @@ func TestRemoveFunctionByColonyName(t *testing.T) {
 	assert.Len(t, functions, 1)
 }
 
-func TestRemoveFunctions(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveFunctions(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -1,4 +1,4 @@
-package postgresql
+package dbtest
 
 import (
 	"testing"
@@ -7,8 +7,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestGeneratorArgClosedDB(t *testing.T) {
-	db, err := PrepareTests()
+func testGeneratorArgClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	db.Close()
@@ -33,8 +33,8 @@ func TestGeneratorArgClosedDB(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestGeneratorArg(t *testing.T) {
-	db, err := PrepareTests()
+func testGeneratorArg(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -58,8 +58,8 @@ func TestGeneratorArg(t *testing.T) {
 	assert.Equal(t, count, 2)
 }
 
-func TestRemoveGeneratorArgByID(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveGeneratorArgByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -83,8 +83,8 @@ func TestRemoveGeneratorArgByID(t *testing.T) {
 	assert.Equal(t, count, 0)
 }
 
-func TestRemoveGeneratorArgByGeneratorID(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveGeneratorArgByGeneratorID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -112,8 +112,8 @@ func TestRemoveGeneratorArgByGeneratorID(t *testing.T) {
 	assert.Equal(t, count, 1)
 }
 
-func TestRemoveGeneratorArgByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveGeneratorArgByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -1,4 +1,4 @@
-package postgresql
+package dbtest
 
 import (
 	"testing"
@@ -8,8 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestGeneratorClosedDB(t *testing.T) {
-	db, err := PrepareTests()
+func testGeneratorClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	db.Close()
@@ -44,8 +44,8 @@ func TestGeneratorClosedDB(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestAddGenerator(t *testing.T) {
-	db, err := PrepareTests()
+func testAddGenerator(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -56,8 +56,8 @@ func TestAddGenerator(t *testing.T) {
 	assert.Nil(t, err)
 }
 
-func TestGetGeneratorByID(t *testing.T) {
-	db, err := PrepareTests()
+func testGetGeneratorByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -76,8 +76,8 @@ func TestGetGeneratorByID(t *testing.T) {
 	assert.True(t, generator.Equals(generatorFromDB))
 }
 
-func TestGetGeneratorByName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetGeneratorByName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -97,8 +97,8 @@ func TestGetGeneratorByName(t *testing.T) {
 	assert.True(t, generator.Equals(generatorFromDB))
 }
 
-func TestSetGeneratorLastRun(t *testing.T) {
-	db, err := PrepareTests()
+func testSetGeneratorLastRun(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -123,8 +123,8 @@ func TestSetGeneratorLastRun(t *testing.T) {
 	assert.Greater(t, generatorFromDB.LastRun.Unix(), lastRun)
 }
 
-func TestSetGeneratorFirstPack(t *testing.T) {
-	db, err := PrepareTests()
+func testSetGeneratorFirstPack(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -147,8 +147,8 @@ func TestSetGeneratorFirstPack(t *testing.T) {
 	assert.True(t, generatorFromDB.FirstPack.Unix() > 0)
 }
 
-func TestFindGeneratorsByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testFindGeneratorsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -180,8 +180,8 @@ func TestFindGeneratorsByColonyName(t *testing.T) {
 	assert.True(t, count == 2)
 }
 
-func TestFindAllGenerators(t *testing.T) {
-	db, err := PrepareTests()
+func testFindAllGenerators(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -203,8 +203,8 @@ func TestFindAllGenerators(t *testing.T) {
 	assert.Len(t, generatorsFromDB, 2)
 }
 
-func TestRemoveGeneratorByID(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveGeneratorByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -248,8 +248,8 @@ func TestRemoveGeneratorByID(t *testing.T) {
 	assert.Equal(t, count, 0)
 }
 
-func TestRemoveAllGeneratorsByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAllGeneratorsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
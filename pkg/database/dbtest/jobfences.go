@@ -0,0 +1,72 @@
+package dbtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testFenceJob(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	err = db.FenceJob("test_job", 10, time.Minute)
+	assert.Nil(t, err)
+
+	err = db.FenceJob("test_job", 10, time.Minute) // Same leader
+	assert.Nil(t, err)
+
+	err = db.FenceJob("test_job", 11, time.Minute) // Newer leader
+	assert.Nil(t, err)
+
+	err = db.FenceJob("test_job", 10, time.Minute) // Stale leader
+	assert.NotNil(t, err)
+
+	err = db.FenceJob("test_job2", 1, time.Minute) // Jobs are fenced separately
+	assert.Nil(t, err)
+
+	// Old tokens do not block other leaders, e.g. when the etcd cluster has been recreated
+	time.Sleep(100 * time.Millisecond)
+	err = db.FenceJob("test_job", 1, 50*time.Millisecond)
+	assert.Nil(t, err)
+}
+
+func testRunFencedJob(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	ran := false
+	run := func() error {
+		ran = true
+		return nil
+	}
+
+	err = db.RunFencedJob("test_job", 10, run) // No token recorded yet
+	assert.NotNil(t, err)
+	assert.False(t, ran)
+
+	err = db.FenceJob("test_job", 10, time.Minute)
+	assert.Nil(t, err)
+
+	err = db.RunFencedJob("test_job", 10, run)
+	assert.Nil(t, err)
+	assert.True(t, ran)
+
+	err = db.FenceJob("test_job", 11, time.Minute) // Newer leader
+	assert.Nil(t, err)
+
+	ran = false
+	err = db.RunFencedJob("test_job", 10, run) // Stale leader
+	assert.NotNil(t, err)
+	assert.False(t, ran)
+
+	// Errors of the job are returned
+	err = db.RunFencedJob("test_job", 11, func() error { return errors.New("test error") })
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,173 @@
+package dbtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAddGetLogsByProcessID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	err = db.AddLog("test_processid", "test_colony", "test_executor_name", time.Now().UTC().UnixNano(), "1")
+	assert.Nil(t, err)
+	err = db.AddLog("test_processid", "test_colony", "test_executor_name", time.Now().UTC().UnixNano(), "2")
+	assert.Nil(t, err)
+
+	logs, err := db.GetLogsByProcessID("test_processid", 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, logs[0].ProcessID, "test_processid")
+	assert.Equal(t, logs[0].ColonyName, "test_colony")
+	assert.Equal(t, logs[0].ExecutorName, "test_executor_name")
+}
+
+func testAddGetLogsByExecutor(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	err = db.AddLog("test_processid", "test_colony", "test_executor_name1", time.Now().UTC().UnixNano(), "1")
+	assert.Nil(t, err)
+	err = db.AddLog("test_processid", "test_colony", "test_executor_name2", time.Now().UTC().UnixNano(), "2")
+	assert.Nil(t, err)
+
+	logs, err := db.GetLogsByExecutor("test_executor_name2", 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, logs[0].ExecutorName, "test_executor_name2")
+}
+
+func testAddGetLogsByProcessIDSince(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	timestamp1 := time.Now().UTC().UnixNano()
+	time.Sleep(2 * time.Second)
+	timestamp2 := time.Now().UTC().UnixNano()
+
+	err = db.AddLog("test_processid", "test_colony", "test_executor_name", timestamp1, "1")
+	assert.Nil(t, err)
+	err = db.AddLog("test_processid", "test_colony", "test_executor_name", timestamp2, "2")
+	assert.Nil(t, err)
+
+	logs, err := db.GetLogsByProcessIDSince("test_processid", 100, timestamp1)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+}
+
+func testAddGetLogsByExecutorSince(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	timestamp1 := time.Now().UTC().UnixNano()
+	time.Sleep(2 * time.Second)
+	timestamp2 := time.Now().UTC().UnixNano()
+
+	err = db.AddLog("test_processid", "test_colony", "test_executor_name", timestamp1, "1")
+	assert.Nil(t, err)
+	err = db.AddLog("test_processid", "test_colony", "test_executor_name", timestamp2, "2")
+	assert.Nil(t, err)
+
+	logs, err := db.GetLogsByExecutorSince("test_executor_name", 100, timestamp1)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+}
+
+func testRemoveLogsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	err = db.AddLog("test_processid1", "test_colony1", "test_executor_name", time.Now().UTC().UnixNano(), "1")
+	assert.Nil(t, err)
+	err = db.AddLog("test_processid2", "test_colony2", "test_executor_name", time.Now().UTC().UnixNano(), "2")
+	assert.Nil(t, err)
+
+	logs, err := db.GetLogsByProcessID("test_processid1", 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+
+	err = db.RemoveLogsByColonyName("test_colony1")
+	assert.Nil(t, err)
+
+	logs, err = db.GetLogsByProcessID("test_processid1", 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 0)
+
+	logs, err = db.GetLogsByProcessID("test_processid2", 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+}
+
+func testAddStructuredLog(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	log := &core.Log{ProcessID: "test_processid", ColonyName: "test_colony", ExecutorName: "test_executor_name", Timestamp: time.Now().UTC().UnixNano(), Message: "1", Level: core.LogLevelError, Stream: core.LogStreamStderr, Fields: map[string]string{"key": "value"}}
+	err = db.AddStructuredLog(log)
+	assert.Nil(t, err)
+	err = db.AddLog("test_processid", "test_colony", "test_executor_name", time.Now().UTC().UnixNano(), "2")
+	assert.Nil(t, err)
+
+	logs, err := db.GetLogsByProcessID("test_processid", 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+	assert.True(t, log.Equals(logs[0]))
+	assert.Equal(t, core.LogLevelInfo, logs[1].Level) // Default level
+	assert.Equal(t, "", logs[1].Stream)
+	assert.Len(t, logs[1].Fields, 0)
+}
+
+func testSearchLogsByLevelAndFields(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	err = db.AddStructuredLog(&core.Log{ProcessID: "test_processid1", ColonyName: "test_colony", ExecutorName: "test_executor_name", Timestamp: time.Now().UTC().UnixNano(), Message: "test_msg", Level: core.LogLevelDebug, Fields: map[string]string{"request": "1"}})
+	assert.Nil(t, err)
+	err = db.AddStructuredLog(&core.Log{ProcessID: "test_processid1", ColonyName: "test_colony", ExecutorName: "test_executor_name", Timestamp: time.Now().UTC().UnixNano(), Message: "test_msg", Level: core.LogLevelWarn, Fields: map[string]string{"request": "1", "user": "alice"}})
+	assert.Nil(t, err)
+	err = db.AddStructuredLog(&core.Log{ProcessID: "test_processid2", ColonyName: "test_colony", ExecutorName: "test_executor_name", Timestamp: time.Now().UTC().UnixNano(), Message: "test_msg", Level: core.LogLevelError, Fields: map[string]string{"request": "2"}})
+	assert.Nil(t, err)
+
+	logs, err := db.SearchLogs("test_colony", "test_msg", 1, 10)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 3)
+
+	logs, err = db.SearchStructuredLogs("test_colony", "test_msg", core.LogLevelWarn, nil, 1, 10)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+
+	logs, err = db.SearchStructuredLogs("test_colony", "", core.LogLevelError, nil, 1, 10)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "test_processid2", logs[0].ProcessID)
+
+	logs, err = db.SearchStructuredLogs("test_colony", "", "", map[string]string{"request": "1"}, 1, 10)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+
+	logs, err = db.SearchStructuredLogs("test_colony", "", "", map[string]string{"request": "1", "user": "alice"}, 1, 10)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, core.LogLevelWarn, logs[0].Level)
+
+	logs, err = db.SearchStructuredLogs("test_colony", "", core.LogLevelError, map[string]string{"request": "1"}, 1, 10)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 0)
+}
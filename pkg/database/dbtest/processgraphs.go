@@ -1,4 +1,4 @@
-package postgresql
+package dbtest
 
 import (
 	"testing"
@@ -8,7 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func generateProcessGraph(t *testing.T, db *PQDatabase, colonyName string) *core.ProcessGraph {
+func generateProcessGraph(t *testing.T, db Database, colonyName string) *core.ProcessGraph {
 	process1 := utils.CreateTestProcess(colonyName)
 	process2 := utils.CreateTestProcess(colonyName)
 	process3 := utils.CreateTestProcess(colonyName)
@@ -46,7 +46,7 @@ func generateProcessGraph(t *testing.T, db *PQDatabase, colonyName string) *core
 	return graph
 }
 
-func generateProcessGraph2(t *testing.T, db *PQDatabase, colonyName string) (*core.Process, *core.ProcessGraph) {
+func generateProcessGraph2(t *testing.T, db Database, colonyName string) (*core.Process, *core.ProcessGraph) {
 	graph, err := core.CreateProcessGraph(colonyName)
 	assert.Nil(t, err)
 
@@ -60,8 +60,8 @@ func generateProcessGraph2(t *testing.T, db *PQDatabase, colonyName string) (*co
 	return process, graph
 }
 
-func TestProcessGraphClosedDB(t *testing.T) {
-	db, err := PrepareTests()
+func testProcessGraphClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	graph := generateProcessGraph(t, db, "invalid_id")
@@ -132,8 +132,8 @@ func TestProcessGraphClosedDB(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestAddProcessGraph(t *testing.T) {
-	db, err := PrepareTests()
+func testAddProcessGraph(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -149,8 +149,8 @@ func TestAddProcessGraph(t *testing.T) {
 	assert.True(t, graph.Equals(graphFromDB))
 }
 
-func TestRemoveProcessGraphByID(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveProcessGraphByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 	defer db.Close()
 
@@ -184,8 +184,8 @@ func TestRemoveProcessGraphByID(t *testing.T) {
 	assert.True(t, graphFromDB.Equals(graph2))
 }
 
-func TestRemoveAllProcessGraphsByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAllProcessGraphsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 	defer db.Close()
 
@@ -219,8 +219,8 @@ func TestRemoveAllProcessGraphsByColonyName(t *testing.T) {
 	assert.Nil(t, graphFromDB)
 }
 
-func TestRemoveAllWaitingProcessGraphsByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAllWaitingProcessGraphsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 	defer db.Close()
 
@@ -264,8 +264,8 @@ func TestRemoveAllWaitingProcessGraphsByColonyName(t *testing.T) {
 	assert.Equal(t, waitingProcesses, 0)
 }
 
-func TestRemoveAllRunningProcessGraphsByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAllRunningProcessGraphsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 	defer db.Close()
 
@@ -309,8 +309,8 @@ func TestRemoveAllRunningProcessGraphsByColonyName(t *testing.T) {
 	assert.Equal(t, runningGraphs, 0)
 }
 
-func TestRemoveAllSuccessfulProcessGraphsByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAllSuccessfulProcessGraphsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 	defer db.Close()
 
@@ -354,8 +354,8 @@ func TestRemoveAllSuccessfulProcessGraphsByColonyName(t *testing.T) {
 	assert.Equal(t, successfulGraphs, 0)
 }
 
-func TestRemoveAllFailedProcessGraphsByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveAllFailedProcessGraphsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 	defer db.Close()
 
@@ -399,8 +399,8 @@ func TestRemoveAllFailedProcessGraphsByColonyName(t *testing.T) {
 	assert.Equal(t, failedGraphs, 0)
 }
 
-func TestSetProcessGraphState(t *testing.T) {
-	db, err := PrepareTests()
+func testSetProcessGraphState(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 	defer db.Close()
 
@@ -423,8 +423,8 @@ func TestSetProcessGraphState(t *testing.T) {
 	assert.True(t, graph2.State == core.FAILED)
 }
 
-func TestFindProcessGraphs(t *testing.T) {
-	db, err := PrepareTests()
+func testFindProcessGraphs(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 	defer db.Close()
 
@@ -0,0 +1,399 @@
+package dbtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetentionClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	db.Close()
+
+	err = db.ApplyRetentionPolicy(1000)
+	assert.NotNil(t, err)
+}
+
+func testApplyRetentionPolicy(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	err = db.AddLog("test_processid", "test_colonyid", "test_executorid", time.Now().UTC().UnixNano(), "test_msg")
+	assert.Nil(t, err)
+
+	colonyName := core.GenerateRandomID()
+
+	process := utils.CreateTestProcess(colonyName)
+	err = db.AddProcess(process)
+	assert.Nil(t, err)
+
+	attribute := core.CreateAttribute(process.ID, colonyName, "", core.IN, "test_key2", "test_value2")
+	err = db.AddAttribute(attribute)
+	assert.Nil(t, err)
+
+	graph, err := core.CreateProcessGraph(colonyName)
+	assert.Nil(t, err)
+	graph.AddRoot(process.ID)
+	err = db.AddProcessGraph(graph)
+	assert.Nil(t, err)
+
+	err = db.SetProcessState(process.ID, core.SUCCESS)
+	assert.Nil(t, err)
+	err = db.SetProcessGraphState(graph.ID, core.SUCCESS)
+	assert.Nil(t, err)
+
+	count, err := db.CountSuccessfulProcessGraphs()
+	assert.Nil(t, err)
+	assert.Equal(t, count, 1)
+
+	logs, err := db.GetLogsByProcessID("test_processid", 100)
+	assert.Len(t, logs, 1)
+
+	err = db.ApplyRetentionPolicy(1) // has no effect, it has not passed 1 second yet
+	assert.Nil(t, err)
+
+	count, err = db.CountSuccessfulProcessGraphs()
+	assert.Nil(t, err)
+	assert.Equal(t, count, 1)
+
+	count, err = db.CountSuccessfulProcesses()
+	assert.Nil(t, err)
+	assert.Equal(t, count, 1)
+
+	_, err = db.GetAttributeByID(attribute.ID)
+	assert.Nil(t, err)
+
+	time.Sleep(2 * time.Second)
+
+	err = db.ApplyRetentionPolicy(1)
+	assert.Nil(t, err)
+
+	count, err = db.CountSuccessfulProcessGraphs()
+	assert.Nil(t, err)
+	assert.Equal(t, count, 0)
+
+	count, err = db.CountSuccessfulProcesses()
+	assert.Nil(t, err)
+	assert.Equal(t, count, 0)
+
+	_, err = db.GetAttributeByID(attribute.ID)
+	assert.NotNil(t, err)
+
+	logs, err = db.GetLogsByProcessID("test_processid", 100)
+	assert.Len(t, logs, 0)
+
+	defer db.Close()
+}
+
+func testRetentionPolicies(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	policy, err := db.GetRetentionPolicy("test_colony1")
+	assert.Nil(t, err)
+	assert.Nil(t, policy)
+
+	policy1 := core.CreateRetentionPolicy("test_colony1")
+	policy1.SuccessfulProcesses = 60
+	policy1.FailedProcesses = 3600
+	err = db.SetRetentionPolicy(policy1)
+	assert.Nil(t, err)
+
+	policy2 := core.CreateRetentionPolicy("test_colony2")
+	policy2.Logs = 10
+	policy2.Archive = true
+	err = db.SetRetentionPolicy(policy2)
+	assert.Nil(t, err)
+
+	policy, err = db.GetRetentionPolicy("test_colony1")
+	assert.Nil(t, err)
+	assert.True(t, policy.Equals(policy1))
+
+	// Update existing policy
+	policy1.FailedProcesses = 7200
+	err = db.SetRetentionPolicy(policy1)
+	assert.Nil(t, err)
+
+	policy, err = db.GetRetentionPolicy("test_colony1")
+	assert.Nil(t, err)
+	assert.True(t, policy.Equals(policy1))
+
+	policies, err := db.GetRetentionPolicies()
+	assert.Nil(t, err)
+	assert.Len(t, policies, 2)
+
+	err = db.RemoveRetentionPolicy("test_colony1")
+	assert.Nil(t, err)
+
+	policy, err = db.GetRetentionPolicy("test_colony1")
+	assert.Nil(t, err)
+	assert.Nil(t, policy)
+
+	policies, err = db.GetRetentionPolicies()
+	assert.Nil(t, err)
+	assert.Len(t, policies, 1)
+}
+
+func testApplyRetentionPolicySkipColoniesWithPolicy(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := core.GenerateRandomID()
+
+	policy := core.CreateRetentionPolicy(colonyName)
+	policy.SuccessfulProcesses = 1000
+	policy.Logs = 1000
+	err = db.SetRetentionPolicy(policy)
+	assert.Nil(t, err)
+
+	process := utils.CreateTestProcess(colonyName)
+	err = db.AddProcess(process)
+	assert.Nil(t, err)
+	err = db.SetProcessState(process.ID, core.SUCCESS)
+	assert.Nil(t, err)
+
+	err = db.AddLog(process.ID, colonyName, "test_executorid", time.Now().UTC().UnixNano(), "test_msg")
+	assert.Nil(t, err)
+
+	time.Sleep(2 * time.Second)
+
+	err = db.ApplyRetentionPolicy(1)
+	assert.Nil(t, err)
+
+	count, err := db.CountSuccessfulProcesses()
+	assert.Nil(t, err)
+	assert.Equal(t, count, 1)
+
+	logs, err := db.GetLogsByProcessID(process.ID, 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+}
+
+func testApplyRetentionPolicyFallbackToServerPeriod(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := core.GenerateRandomID()
+
+	// The policy only sets a period for failed processes, the server period applies to everything else
+	policy := core.CreateRetentionPolicy(colonyName)
+	policy.FailedProcesses = 1000
+	err = db.SetRetentionPolicy(policy)
+	assert.Nil(t, err)
+
+	process := utils.CreateTestProcess(colonyName)
+	err = db.AddProcess(process)
+	assert.Nil(t, err)
+	err = db.SetProcessState(process.ID, core.SUCCESS)
+	assert.Nil(t, err)
+
+	err = db.AddLog(process.ID, colonyName, "test_executorid", time.Now().UTC().UnixNano(), "test_msg")
+	assert.Nil(t, err)
+
+	time.Sleep(2 * time.Second)
+
+	err = db.ApplyRetentionPolicy(1)
+	assert.Nil(t, err)
+
+	count, err := db.CountSuccessfulProcesses()
+	assert.Nil(t, err)
+	assert.Equal(t, count, 0)
+
+	logs, err := db.GetLogsByProcessID(process.ID, 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 0)
+}
+
+func testApplyRetentionPolicyRunningProcessGraph(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := core.GenerateRandomID()
+
+	process := utils.CreateTestProcess(colonyName)
+	graph, err := core.CreateProcessGraph(colonyName)
+	assert.Nil(t, err)
+	process.ProcessGraphID = graph.ID
+	graph.AddRoot(process.ID)
+	err = db.AddProcessGraph(graph)
+	assert.Nil(t, err)
+	err = db.AddProcess(process)
+	assert.Nil(t, err)
+	err = db.SetProcessState(process.ID, core.SUCCESS)
+	assert.Nil(t, err)
+	err = db.SetProcessGraphState(graph.ID, core.RUNNING)
+	assert.Nil(t, err)
+
+	time.Sleep(2 * time.Second)
+
+	processes, err := db.FindExpiredProcesses(colonyName, core.SUCCESS, time.Now(), 100)
+	assert.Nil(t, err)
+	assert.Len(t, processes, 0) // The process graph still needs the process
+
+	err = db.ApplyRetentionPolicy(1)
+	assert.Nil(t, err)
+
+	count, err := db.CountSuccessfulProcesses()
+	assert.Nil(t, err)
+	assert.Equal(t, count, 1)
+
+	err = db.SetProcessGraphState(graph.ID, core.SUCCESS)
+	assert.Nil(t, err)
+
+	processes, err = db.FindExpiredProcesses(colonyName, core.SUCCESS, time.Now(), 100)
+	assert.Nil(t, err)
+	assert.Len(t, processes, 1)
+}
+
+func testFindExpiredLogsBatch(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := core.GenerateRandomID()
+
+	for i := 0; i < 5; i++ {
+		err = db.AddLog("test_processid", colonyName, "test_executorid", time.Now().UTC().UnixNano(), "test_msg")
+		assert.Nil(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	before := time.Now()
+
+	logs, err := db.FindExpiredLogs(colonyName, before, 2)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+
+	err = db.RemoveExpiredLogs(colonyName, before, 2)
+	assert.Nil(t, err)
+
+	logs, err = db.FindExpiredLogs(colonyName, before, 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 3)
+
+	err = db.RemoveExpiredLogs(colonyName, before, 0)
+	assert.Nil(t, err)
+
+	logs, err = db.FindExpiredLogs(colonyName, before, 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 0)
+}
+
+func testFindExpired(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := core.GenerateRandomID()
+
+	process1 := utils.CreateTestProcess(colonyName)
+	err = db.AddProcess(process1)
+	assert.Nil(t, err)
+	err = db.SetProcessState(process1.ID, core.FAILED)
+	assert.Nil(t, err)
+
+	process2 := utils.CreateTestProcess(colonyName)
+	err = db.AddProcess(process2)
+	assert.Nil(t, err)
+
+	graph, err := core.CreateProcessGraph(colonyName)
+	assert.Nil(t, err)
+	graph.AddRoot(process1.ID)
+	err = db.AddProcessGraph(graph)
+	assert.Nil(t, err)
+	err = db.SetProcessGraphState(graph.ID, core.FAILED)
+	assert.Nil(t, err)
+
+	err = db.AddLog(process1.ID, colonyName, "test_executorid", time.Now().UTC().UnixNano(), "test_msg")
+	assert.Nil(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	before := time.Now()
+
+	processes, err := db.FindExpiredProcesses(colonyName, core.FAILED, before, 100)
+	assert.Nil(t, err)
+	assert.Len(t, processes, 1)
+	assert.Equal(t, process1.ID, processes[0].ID)
+
+	processes, err = db.FindExpiredProcesses(colonyName, core.FAILED, before.Add(-time.Hour), 100)
+	assert.Nil(t, err)
+	assert.Len(t, processes, 0)
+
+	graphs, err := db.FindExpiredProcessGraphs(colonyName, before, 100)
+	assert.Nil(t, err)
+	assert.Len(t, graphs, 1)
+	assert.Equal(t, graph.ID, graphs[0].ID)
+
+	logs, err := db.FindExpiredLogs(colonyName, before, 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+
+	err = db.RemoveExpiredLogs(colonyName, before, 100)
+	assert.Nil(t, err)
+
+	logs, err = db.FindExpiredLogs(colonyName, time.Now(), 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 0)
+}
+
+func testFindExpiredFiles(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	var files []*core.File
+	for i := 0; i < 3; i++ {
+		file := utils.CreateTestFileWithID("test_id", "test_colony", time.Now())
+		file.ID = core.GenerateRandomID()
+		file.Label = "/testpath"
+		file.Name = "test_file.txt"
+		err = db.AddFile(file)
+		assert.Nil(t, err)
+		files = append(files, file)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	before := time.Now()
+
+	// The latest revision never expires
+	expiredFiles, err := db.FindExpiredFiles("test_colony", before, 100)
+	assert.Nil(t, err)
+	assert.Len(t, expiredFiles, 2)
+	assert.Equal(t, files[0].ID, expiredFiles[0].ID)
+	assert.Equal(t, files[1].ID, expiredFiles[1].ID)
+
+	// Revisions in a snapshot never expire
+	_, err = db.CreateSnapshot("test_colony", "/testpath", "test_snapshot")
+	assert.Nil(t, err)
+
+	file := utils.CreateTestFileWithID("test_id", "test_colony", time.Now())
+	file.ID = core.GenerateRandomID()
+	file.Label = "/testpath"
+	file.Name = "test_file.txt"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	expiredFiles, err = db.FindExpiredFiles("test_colony", time.Now(), 100)
+	assert.Nil(t, err)
+	assert.Len(t, expiredFiles, 2)
+
+	expiredFiles, err = db.FindExpiredFiles("test_colony", before.Add(-time.Hour), 100)
+	assert.Nil(t, err)
+	assert.Len(t, expiredFiles, 0)
+}
@@ -1,4 +1,4 @@
-package postgresql
+package dbtest
 
 import (
 	"testing"
@@ -8,8 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestCreateSnapshot(t *testing.T) {
-	db, err := PrepareTests()
+func testCreateSnapshot(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -63,8 +63,8 @@ func TestCreateSnapshot(t *testing.T) {
 	assert.NotNil(t, err) // name must be unique
 }
 
-func TestGetSnapshotByID(t *testing.T) {
-	db, err := PrepareTests()
+func testGetSnapshotByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -91,8 +91,8 @@ func TestGetSnapshotByID(t *testing.T) {
 	assert.True(t, snapshotFromDB.Equals(snapshot))
 }
 
-func TestGetSnapshotByName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetSnapshotByName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -119,8 +119,8 @@ func TestGetSnapshotByName(t *testing.T) {
 	assert.True(t, snapshotFromDB.Equals(snapshot))
 }
 
-func TestGetSnapshotsByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testGetSnapshotsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -150,8 +150,8 @@ func TestGetSnapshotsByColonyName(t *testing.T) {
 	assert.Len(t, snapshotsFromDB, 2)
 }
 
-func TestRemoveSnapshotByID(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveSnapshotByID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -188,8 +188,8 @@ func TestRemoveSnapshotByID(t *testing.T) {
 	assert.Len(t, snapshotsFromDB, 1)
 }
 
-func TestRemoveSnapshotByName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveSnapshotByName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
@@ -226,8 +226,8 @@ func TestRemoveSnapshotByName(t *testing.T) {
 	assert.Len(t, snapshotsFromDB, 1)
 }
 
-func TestRemoveSnapshotsByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveSnapshotsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	defer db.Close()
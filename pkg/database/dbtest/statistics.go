@@ -0,0 +1,133 @@
+package dbtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStatisticsSnapshotsClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	db.Close()
+
+	err = db.AddStatisticsSnapshot(core.CreateStatisticsSnapshot("test_colony", time.Now(), core.CreateStatistics(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)))
+	assert.NotNil(t, err)
+
+	_, err = db.GetStatisticsSnapshots("test_colony", time.Time{}, time.Now(), 10)
+	assert.NotNil(t, err)
+
+	err = db.RemoveStatisticsSnapshotsByColonyName("test_colony")
+	assert.NotNil(t, err)
+
+	err = db.RemoveExpiredStatisticsSnapshots("test_colony", time.Now())
+	assert.NotNil(t, err)
+}
+
+func testAddStatisticsSnapshot(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	now := time.Now()
+	snapshot1 := core.CreateStatisticsSnapshot("test_colony", now.Add(-2*time.Hour), core.CreateStatistics(1, 2, 3, 4, 5, 6, 7, 8, 9, 10))
+	err = db.AddStatisticsSnapshot(snapshot1)
+	assert.Nil(t, err)
+
+	snapshot2 := core.CreateStatisticsSnapshot("test_colony", now.Add(-time.Hour), core.CreateStatistics(1, 2, 0, 4, 5, 6, 7, 8, 9, 10))
+	err = db.AddStatisticsSnapshot(snapshot2)
+	assert.Nil(t, err)
+
+	snapshot3 := core.CreateStatisticsSnapshot("test_colony", now, core.CreateStatistics(1, 2, 0, 0, 5, 6, 7, 8, 9, 10))
+	err = db.AddStatisticsSnapshot(snapshot3)
+	assert.Nil(t, err)
+
+	err = db.AddStatisticsSnapshot(core.CreateStatisticsSnapshot("test_colony2", now, core.CreateStatistics(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)))
+	assert.Nil(t, err)
+
+	snapshots, err := db.GetStatisticsSnapshots("test_colony", now.Add(-3*time.Hour), now.Add(time.Minute), 100)
+	assert.Nil(t, err)
+	assert.True(t, core.IsStatisticsSnapshotArraysEqual([]*core.StatisticsSnapshot{snapshot1, snapshot2, snapshot3}, snapshots)) // Oldest first
+
+	snapshots, err = db.GetStatisticsSnapshots("test_colony", now.Add(-90*time.Minute), now.Add(time.Minute), 100)
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 2)
+
+	snapshots, err = db.GetStatisticsSnapshots("test_colony", now.Add(-3*time.Hour), now.Add(time.Minute), 1)
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 1)
+	assert.True(t, snapshot1.Equals(snapshots[0]))
+}
+
+func testRemoveExpiredStatisticsSnapshots(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	now := time.Now()
+	err = db.AddStatisticsSnapshot(core.CreateStatisticsSnapshot("test_colony", now.Add(-time.Hour), core.CreateStatistics(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)))
+	assert.Nil(t, err)
+
+	err = db.AddStatisticsSnapshot(core.CreateStatisticsSnapshot("test_colony", now, core.CreateStatistics(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)))
+	assert.Nil(t, err)
+
+	err = db.RemoveExpiredStatisticsSnapshots("test_colony", now.Add(-time.Minute))
+	assert.Nil(t, err)
+
+	snapshots, err := db.GetStatisticsSnapshots("test_colony", time.Time{}, now.Add(time.Minute), 100)
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 1)
+}
+
+func testRemoveStatisticsSnapshotsByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	now := time.Now()
+	err = db.AddStatisticsSnapshot(core.CreateStatisticsSnapshot("test_colony1", now, core.CreateStatistics(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)))
+	assert.Nil(t, err)
+
+	err = db.AddStatisticsSnapshot(core.CreateStatisticsSnapshot("test_colony2", now, core.CreateStatistics(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)))
+	assert.Nil(t, err)
+
+	err = db.RemoveStatisticsSnapshotsByColonyName("test_colony1")
+	assert.Nil(t, err)
+
+	snapshots, err := db.GetStatisticsSnapshots("test_colony1", time.Time{}, now.Add(time.Minute), 100)
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 0)
+
+	snapshots, err = db.GetStatisticsSnapshots("test_colony2", time.Time{}, now.Add(time.Minute), 100)
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 1)
+}
+
+func testRenameColonyStatisticsSnapshots(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colony := core.CreateColony(core.GenerateRandomID(), "test_colony")
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	now := time.Now()
+	err = db.AddStatisticsSnapshot(core.CreateStatisticsSnapshot("test_colony", now, core.CreateStatistics(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)))
+	assert.Nil(t, err)
+
+	err = db.RenameColony("test_colony", "test_colony_renamed")
+	assert.Nil(t, err)
+
+	snapshots, err := db.GetStatisticsSnapshots("test_colony_renamed", time.Time{}, now.Add(time.Minute), 100)
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 1)
+	assert.Equal(t, "test_colony_renamed", snapshots[0].ColonyName)
+}
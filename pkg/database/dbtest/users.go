@@ -1,4 +1,4 @@
-package postgresql
+package dbtest
 
 import (
 	"testing"
@@ -8,8 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestAddUser(t *testing.T) {
-	db, err := PrepareTests()
+func testAddUser(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	colonyName := core.GenerateRandomID()
@@ -43,8 +43,8 @@ func TestAddUser(t *testing.T) {
 	defer db.Close()
 }
 
-func TestGetUsers(t *testing.T) {
-	db, err := PrepareTests()
+func testGetUsers(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	colonyName := core.GenerateRandomID()
@@ -64,8 +64,8 @@ func TestGetUsers(t *testing.T) {
 	defer db.Close()
 }
 
-func TestRemoveUser(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveUser(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	colonyName := core.GenerateRandomID()
@@ -119,8 +119,8 @@ func TestRemoveUser(t *testing.T) {
 	defer db.Close()
 }
 
-func TestRemoveUsersByColonyName(t *testing.T) {
-	db, err := PrepareTests()
+func testRemoveUsersByColonyName(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	colonyName1 := core.GenerateRandomID()
@@ -156,8 +156,8 @@ func TestRemoveUsersByColonyName(t *testing.T) {
 	defer db.Close()
 }
 
-func TestChangeUserID(t *testing.T) {
-	db, err := PrepareTests()
+func testChangeUserID(t *testing.T, prepare Prepare) {
+	db, err := prepare()
 	assert.Nil(t, err)
 
 	colonyName := core.GenerateRandomID()
@@ -0,0 +1,245 @@
+package dbtest
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func testWebhookClosedDB(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	db.Close()
+
+	webhook := core.CreateWebhook("test_colony", "test_webhook", "http://localhost/hook", []string{}, "", "")
+	webhook.ID = core.GenerateRandomID()
+
+	err = db.AddWebhook(webhook)
+	assert.NotNil(t, err)
+
+	_, err = db.GetWebhookByID(webhook.ID)
+	assert.NotNil(t, err)
+
+	_, err = db.GetWebhooksByColonyName("test_colony")
+	assert.NotNil(t, err)
+
+	err = db.RemoveWebhookByID(webhook.ID)
+	assert.NotNil(t, err)
+
+	err = db.RemoveWebhooksByColonyName("test_colony")
+	assert.NotNil(t, err)
+
+	err = db.AddWebhookDelivery(&core.WebhookDelivery{ID: core.GenerateRandomID(), WebhookID: webhook.ID})
+	assert.NotNil(t, err)
+
+	_, err = db.GetWebhookDeliveries(webhook.ID, 10)
+	assert.NotNil(t, err)
+}
+
+func testAddWebhook(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	webhook1 := core.CreateWebhook("test_colony1", "test_webhook1", "http://localhost/hook1", []string{core.ProcessFailedEvent, core.ProcessGraphEventKind}, "/test_label", "test_secret")
+	webhook1.ID = core.GenerateRandomID()
+	err = db.AddWebhook(webhook1)
+	assert.Nil(t, err)
+
+	webhook2 := core.CreateWebhook("test_colony1", "test_webhook2", "http://localhost/hook2", []string{}, "", "")
+	webhook2.ID = core.GenerateRandomID()
+	err = db.AddWebhook(webhook2)
+	assert.Nil(t, err)
+
+	webhook3 := core.CreateWebhook("test_colony2", "test_webhook3", "http://localhost/hook3", []string{}, "", "")
+	webhook3.ID = core.GenerateRandomID()
+	err = db.AddWebhook(webhook3)
+	assert.Nil(t, err)
+
+	webhookFromDB, err := db.GetWebhookByID(webhook1.ID)
+	assert.Nil(t, err)
+	assert.True(t, webhook1.Equals(webhookFromDB))
+
+	webhookFromDB, err = db.GetWebhookByID(webhook2.ID)
+	assert.Nil(t, err)
+	assert.True(t, webhook2.Equals(webhookFromDB))
+
+	webhookFromDB, err = db.GetWebhookByID(core.GenerateRandomID())
+	assert.Nil(t, err)
+	assert.Nil(t, webhookFromDB)
+
+	webhooks, err := db.GetWebhooksByColonyName("test_colony1")
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 2)
+
+	webhooks, err = db.GetWebhooksByColonyName("test_colony2")
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 1)
+}
+
+func testRemoveWebhook(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	webhook1 := core.CreateWebhook("test_colony1", "test_webhook1", "http://localhost/hook1", []string{}, "", "")
+	webhook1.ID = core.GenerateRandomID()
+	err = db.AddWebhook(webhook1)
+	assert.Nil(t, err)
+
+	webhook2 := core.CreateWebhook("test_colony1", "test_webhook2", "http://localhost/hook2", []string{}, "", "")
+	webhook2.ID = core.GenerateRandomID()
+	err = db.AddWebhook(webhook2)
+	assert.Nil(t, err)
+
+	webhook3 := core.CreateWebhook("test_colony2", "test_webhook3", "http://localhost/hook3", []string{}, "", "")
+	webhook3.ID = core.GenerateRandomID()
+	err = db.AddWebhook(webhook3)
+	assert.Nil(t, err)
+
+	err = db.AddWebhookDelivery(&core.WebhookDelivery{ID: core.GenerateRandomID(), WebhookID: webhook1.ID, ColonyName: "test_colony1", Time: time.Now()})
+	assert.Nil(t, err)
+
+	err = db.RemoveWebhookByID(webhook1.ID)
+	assert.Nil(t, err)
+
+	webhookFromDB, err := db.GetWebhookByID(webhook1.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, webhookFromDB)
+
+	deliveries, err := db.GetWebhookDeliveries(webhook1.ID, 10)
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 0)
+
+	err = db.RemoveWebhooksByColonyName("test_colony1")
+	assert.Nil(t, err)
+
+	webhooks, err := db.GetWebhooksByColonyName("test_colony1")
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 0)
+
+	webhooks, err = db.GetWebhooksByColonyName("test_colony2")
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 1)
+}
+
+func testWebhookDeliveries(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	webhook := core.CreateWebhook("test_colony", "test_webhook", "http://localhost/hook", []string{}, "", "")
+	webhook.ID = core.GenerateRandomID()
+	err = db.AddWebhook(webhook)
+	assert.Nil(t, err)
+
+	now := time.Now()
+	var deliveries []*core.WebhookDelivery
+	for i := 0; i < 5; i++ {
+		delivery := &core.WebhookDelivery{
+			ID:         core.GenerateRandomID(),
+			WebhookID:  webhook.ID,
+			ColonyName: "test_colony",
+			EventType:  core.ProcessFailedEvent,
+			ObjectID:   core.GenerateRandomID(),
+			Attempts:   i + 1,
+			StatusCode: 500,
+			Error:      "500 Internal Server Error",
+			Time:       now.Add(time.Duration(i) * time.Second),
+		}
+		err = db.AddWebhookDelivery(delivery)
+		assert.Nil(t, err)
+		deliveries = append(deliveries, delivery)
+	}
+
+	deliveriesFromDB, err := db.GetWebhookDeliveries(webhook.ID, 3)
+	assert.Nil(t, err)
+	assert.Len(t, deliveriesFromDB, 3)
+	assert.True(t, deliveries[4].Equals(deliveriesFromDB[0]))
+	assert.True(t, deliveries[3].Equals(deliveriesFromDB[1]))
+	assert.True(t, deliveries[2].Equals(deliveriesFromDB[2]))
+}
+
+func testRemoveWebhookDeliveries(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	now := time.Now()
+	var webhookIDs []string
+	for i := 0; i < 2; i++ {
+		webhook := core.CreateWebhook("test_colony", "test_webhook"+strconv.Itoa(i), "http://localhost/hook", []string{}, "", "")
+		webhook.ID = core.GenerateRandomID()
+		err = db.AddWebhook(webhook)
+		assert.Nil(t, err)
+		webhookIDs = append(webhookIDs, webhook.ID)
+
+		for j := 0; j < 5; j++ {
+			delivery := &core.WebhookDelivery{
+				ID:         core.GenerateRandomID(),
+				WebhookID:  webhook.ID,
+				ColonyName: "test_colony",
+				EventType:  core.ProcessFailedEvent,
+				Time:       now.Add(time.Duration(j-4) * time.Hour),
+			}
+			err = db.AddWebhookDelivery(delivery)
+			assert.Nil(t, err)
+		}
+	}
+
+	err = db.RemoveExcessWebhookDeliveries(4)
+	assert.Nil(t, err)
+
+	for _, webhookID := range webhookIDs {
+		deliveries, err := db.GetWebhookDeliveries(webhookID, 100)
+		assert.Nil(t, err)
+		assert.Len(t, deliveries, 4)
+	}
+
+	err = db.RemoveExpiredWebhookDeliveries(now.Add(-90 * time.Minute))
+	assert.Nil(t, err)
+
+	for _, webhookID := range webhookIDs {
+		deliveries, err := db.GetWebhookDeliveries(webhookID, 100)
+		assert.Nil(t, err)
+		assert.Len(t, deliveries, 2)
+	}
+}
+
+func testRenameColonyWebhooks(t *testing.T, prepare Prepare) {
+	db, err := prepare()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colony := core.CreateColony(core.GenerateRandomID(), "test_colony")
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	webhook := core.CreateWebhook("test_colony", "test_webhook", "http://localhost/hook", []string{}, "", "")
+	webhook.ID = core.GenerateRandomID()
+	err = db.AddWebhook(webhook)
+	assert.Nil(t, err)
+
+	err = db.RenameColony("test_colony", "test_colony_renamed")
+	assert.Nil(t, err)
+
+	webhooks, err := db.GetWebhooksByColonyName("test_colony_renamed")
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 1)
+
+	err = db.RemoveColonyByName("test_colony_renamed")
+	assert.Nil(t, err)
+
+	webhooks, err = db.GetWebhooksByColonyName("test_colony_renamed")
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 0)
+}
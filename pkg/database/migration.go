@@ -0,0 +1,7 @@
+package database
+
+// Migration describes a schema migration that upgrades a database to Version
+type Migration struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,96 @@
+package postgresql
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+func (db *PQDatabase) AddAlertRule(rule *core.AlertRule) error {
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `ALERTRULES (ALERT_RULE_ID, COLONY_NAME, NAME, TYPE, EXECUTOR_TYPE, FUNCNAME, THRESHOLD, FOR_SECONDS, WINDOW_SECONDS, STATE, VALUE, PENDING_SINCE, FIRED_TIME, RESOLVED_TIME, EVALUATED, ADDED) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+	_, err := db.postgresql.Exec(sqlStatement, rule.ID, rule.ColonyName, rule.Name, rule.Type, rule.ExecutorType, rule.FuncName, rule.Threshold, rule.For, rule.Window, rule.State, rule.Value, rule.PendingSince, rule.FiredTime, rule.ResolvedTime, rule.Evaluated, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) parseAlertRules(rows *sql.Rows) ([]*core.AlertRule, error) {
+	var rules []*core.AlertRule
+
+	for rows.Next() {
+		rule := &core.AlertRule{}
+		if err := rows.Scan(&rule.ID, &rule.ColonyName, &rule.Name, &rule.Type, &rule.ExecutorType, &rule.FuncName, &rule.Threshold, &rule.For, &rule.Window, &rule.State, &rule.Value, &rule.PendingSince, &rule.FiredTime, &rule.ResolvedTime, &rule.Evaluated, &rule.Added); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (db *PQDatabase) GetAlertRuleByName(colonyName string, name string) (*core.AlertRule, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `ALERTRULES WHERE COLONY_NAME=$1 AND NAME=$2`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	rules, err := db.parseAlertRules(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	return rules[0], nil
+}
+
+func (db *PQDatabase) GetAlertRulesByColonyName(colonyName string) ([]*core.AlertRule, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `ALERTRULES WHERE COLONY_NAME=$1 ORDER BY ADDED ASC`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseAlertRules(rows)
+}
+
+// UpdateAlertRuleState stores the result of the latest evaluation of an alert rule
+func (db *PQDatabase) UpdateAlertRuleState(rule *core.AlertRule) error {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `ALERTRULES SET STATE=$1, VALUE=$2, PENDING_SINCE=$3, FIRED_TIME=$4, RESOLVED_TIME=$5, EVALUATED=$6 WHERE ALERT_RULE_ID=$7`
+	_, err := db.postgresql.Exec(sqlStatement, rule.State, rule.Value, rule.PendingSince, rule.FiredTime, rule.ResolvedTime, rule.Evaluated, rule.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) RemoveAlertRuleByName(colonyName string, name string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `ALERTRULES WHERE COLONY_NAME=$1 AND NAME=$2`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName, name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) RemoveAlertRulesByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `ALERTRULES WHERE COLONY_NAME=$1`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -119,6 +119,66 @@ func (db *PQDatabase) RenameColony(colonyName string, newName string) error {
 		return err
 	}
 
+	sqlStatement = `UPDATE ` + db.dbPrefix + `RETENTIONPOLICIES SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.postgresql.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `WEBHOOKS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.postgresql.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `WEBHOOKDELIVERIES SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.postgresql.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `EVENTS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.postgresql.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `EVENTSEQS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.postgresql.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `FUNCTIONRUNS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.postgresql.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `COLONYSTATS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.postgresql.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `ALERTRULES SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.postgresql.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `FEDERATIONPEERS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.postgresql.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `FEDERATEDPROCESSES SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.postgresql.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -188,6 +248,41 @@ func (db *PQDatabase) RemoveColonyByName(colonyName string) error {
 		return err
 	}
 
+	err = db.RemoveRetentionPolicy(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveWebhooksByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveEventsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveFunctionRunsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveStatisticsSnapshotsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAlertRulesByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveFederationPeersByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -46,6 +47,17 @@ type PQDatabase struct {
 	dbName      string
 	dbPrefix    string
 	timescaleDB bool
+	lockConn    *sql.Conn
+	lockMutex   sync.Mutex
+
+	// Read-only queries are routed to read replicas, see replicas.go
+	replicas      []*readReplica
+	maxReplicaLag time.Duration
+	replicaMutex  sync.RWMutex
+	nextReplica   uint64
+	replicaCtx    context.Context
+	replicaCancel context.CancelFunc
+	replicaDone   chan struct{}
 }
 
 func CreatePQDatabase(dbHost string, dbPort int, dbUser string, dbPassword string, dbName string, dbPrefix string, timescaleDB bool) *PQDatabase {
@@ -71,13 +83,26 @@ func (db *PQDatabase) Connect() error {
 		return err
 	}
 
-	return nil
+	return db.connectReplicas(tz)
 }
 
 func (db *PQDatabase) Close() {
+	db.lockMutex.Lock()
+	if db.lockConn != nil {
+		db.lockConn.Close()
+		db.lockConn = nil
+	}
+	db.lockMutex.Unlock()
+
+	db.closeReplicas()
 	db.postgresql.Close()
 }
 
+// Ping checks that the database can be reached
+func (db *PQDatabase) Ping(ctx context.Context) error {
+	return db.postgresql.PingContext(ctx)
+}
+
 func (db *PQDatabase) dropUsersTable() error {
 	sqlStatement := `DROP TABLE ` + db.dbPrefix + `USERS`
 	_, err := db.postgresql.Exec(sqlStatement)
@@ -214,6 +239,116 @@ func (db *PQDatabase) dropCronsTable() error {
 	return nil
 }
 
+func (db *PQDatabase) dropRetentionPoliciesTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `RETENTIONPOLICIES`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropWebhooksTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `WEBHOOKS`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropWebhookDeliveriesTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `WEBHOOKDELIVERIES`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropEventsTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `EVENTS`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropEventSeqsTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `EVENTSEQS`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropStatisticsSnapshotsTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `COLONYSTATS`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropAlertRulesTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `ALERTRULES`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropFederationPeersTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `FEDERATIONPEERS`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropFederatedProcessesTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `FEDERATEDPROCESSES`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropJobFencesTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `JOBFENCES`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropFunctionRunsTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `FUNCTIONRUNS`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (db *PQDatabase) dropServerTable() error {
 	sqlStatement := `DROP TABLE ` + db.dbPrefix + `SERVER`
 	_, err := db.postgresql.Exec(sqlStatement)
@@ -290,11 +425,71 @@ func (db *PQDatabase) Drop() error {
 		return err
 	}
 
+	err = db.dropRetentionPoliciesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropWebhooksTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropWebhookDeliveriesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropEventsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropEventSeqsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropFunctionRunsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropStatisticsSnapshotsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropAlertRulesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropFederationPeersTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropFederatedProcessesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropJobFencesTable()
+	if err != nil {
+		return err
+	}
+
 	err = db.dropServerTable()
 	if err != nil {
 		return err
 	}
 
+	err = db.dropSchemaTable()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -360,7 +555,7 @@ func (db *PQDatabase) createFunctionsTable() error {
 }
 
 func (db *PQDatabase) createProcessesTable() error {
-	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `PROCESSES (PROCESS_ID TEXT PRIMARY KEY NOT NULL, TARGET_COLONY_NAME TEXT NOT NULL, TARGET_EXECUTOR_NAMES TEXT[], ASSIGNED_EXECUTOR_ID TEXT, STATE INTEGER, IS_ASSIGNED BOOLEAN, EXECUTOR_TYPE TEXT, SUBMISSION_TIME TIMESTAMPTZ, START_TIME TIMESTAMPTZ, END_TIME TIMESTAMPTZ, WAIT_DEADLINE TIMESTAMPTZ, EXEC_DEADLINE TIMESTAMPTZ, ERRORS TEXT[], NODENAME TEXT, FUNCNAME TEXT, ARGS TEXT, KWARGS TEXT, MAX_WAIT_TIME INTEGER, MAX_EXEC_TIME INTEGER, RETRIES INTEGER, MAX_RETRIES INTEGER, DEPENDENCIES TEXT[], PRIORITY INTEGER, PRIORITYTIME BIGINT, WAIT_FOR_PARENTS BOOLEAN, PARENTS TEXT[], CHILDREN TEXT[], PROCESSGRAPH_ID TEXT, INPUT TEXT, OUTPUT TEXT, LABEL TEXT, FS TEXT, NODES INTEGER, CPU BIGINT, PROCESSES INTEGER, PROCESSES_PER_NODE INTEGER, MEMORY BIGINT, STORAGE BIGINT, GPUNAME TEXT, GPUCOUNT TEXT, GPUMEM BIGINT, WALLTIME BIGINT, INITIATOR_ID TEXT NOT NULL, INITIATOR_NAME TEXT NOT NULL)`
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `PROCESSES (PROCESS_ID TEXT PRIMARY KEY NOT NULL, TARGET_COLONY_NAME TEXT NOT NULL, TARGET_EXECUTOR_NAMES TEXT[], ASSIGNED_EXECUTOR_ID TEXT, STATE INTEGER, IS_ASSIGNED BOOLEAN, EXECUTOR_TYPE TEXT, SUBMISSION_TIME TIMESTAMPTZ, START_TIME TIMESTAMPTZ, END_TIME TIMESTAMPTZ, WAIT_DEADLINE TIMESTAMPTZ, EXEC_DEADLINE TIMESTAMPTZ, ERRORS TEXT[], NODENAME TEXT, FUNCNAME TEXT, ARGS TEXT, KWARGS TEXT, MAX_WAIT_TIME INTEGER, MAX_EXEC_TIME INTEGER, RETRIES INTEGER, MAX_RETRIES INTEGER, DEPENDENCIES TEXT[], PRIORITY INTEGER, PRIORITYTIME BIGINT, WAIT_FOR_PARENTS BOOLEAN, PARENTS TEXT[], CHILDREN TEXT[], PROCESSGRAPH_ID TEXT, INPUT TEXT, OUTPUT TEXT, LABEL TEXT, FS TEXT, NODES INTEGER, CPU BIGINT, PROCESSES INTEGER, PROCESSES_PER_NODE INTEGER, MEMORY BIGINT, STORAGE BIGINT, GPUNAME TEXT, GPUCOUNT TEXT, GPUMEM BIGINT, WALLTIME BIGINT, INITIATOR_ID TEXT NOT NULL, INITIATOR_NAME TEXT NOT NULL, TRACE_PARENT TEXT NOT NULL DEFAULT '', TRACE_STATE TEXT NOT NULL DEFAULT '')`
 	_, err := db.postgresql.Exec(sqlStatement)
 	if err != nil {
 		return err
@@ -370,7 +565,7 @@ func (db *PQDatabase) createProcessesTable() error {
 }
 
 func (db *PQDatabase) createLogTable() error {
-	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `LOGS (PROCESS_ID TEXT, COLONY_NAME TEXT NOT NULL, EXECUTOR_NAME TEXT NOT NULL, TS BIGINT, MSG TEXT NOT NULL, ADDED TIMESTAMPTZ)`
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `LOGS (PROCESS_ID TEXT, COLONY_NAME TEXT NOT NULL, EXECUTOR_NAME TEXT NOT NULL, TS BIGINT, MSG TEXT NOT NULL, ADDED TIMESTAMPTZ, LEVEL TEXT NOT NULL DEFAULT 'info', STREAM TEXT NOT NULL DEFAULT '', FIELDS TEXT NOT NULL DEFAULT '{}')`
 	_, err := db.postgresql.Exec(sqlStatement)
 	if err != nil {
 		return err
@@ -455,6 +650,116 @@ func (db *PQDatabase) createCronsTable() error {
 	return nil
 }
 
+func (db *PQDatabase) createRetentionPoliciesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `RETENTIONPOLICIES (COLONY_NAME TEXT PRIMARY KEY NOT NULL, SUCCESSFUL_PROCESSES BIGINT, FAILED_PROCESSES BIGINT, PROCESSGRAPHS BIGINT, LOGS BIGINT, FILES BIGINT, ARCHIVE BOOLEAN)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createWebhooksTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `WEBHOOKS (WEBHOOK_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, URL TEXT NOT NULL, TYPES TEXT[], LABEL TEXT, SECRET TEXT, ADDED TIMESTAMPTZ)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createWebhookDeliveriesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `WEBHOOKDELIVERIES (DELIVERY_ID TEXT PRIMARY KEY NOT NULL, WEBHOOK_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, EVENT_TYPE TEXT, OBJECT_ID TEXT, ATTEMPTS INTEGER, STATUS_CODE INTEGER, ERROR TEXT, DELIVERED BOOLEAN, TIME TIMESTAMPTZ)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createEventsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `EVENTS (COLONY_NAME TEXT NOT NULL, SEQ BIGINT NOT NULL, TYPE TEXT NOT NULL, LABEL TEXT, OBJECT_ID TEXT, TIME TIMESTAMPTZ, EVENT TEXT NOT NULL, PRIMARY KEY (COLONY_NAME, SEQ))`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createEventSeqsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `EVENTSEQS (COLONY_NAME TEXT PRIMARY KEY NOT NULL, SEQ BIGINT NOT NULL)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createFunctionRunsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `FUNCTIONRUNS (PROCESS_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, FUNCNAME TEXT NOT NULL, EXECUTOR_TYPE TEXT NOT NULL, EXECUTOR_NAME TEXT, WAIT_TIME FLOAT, EXEC_TIME FLOAT, SUCCESSFUL BOOLEAN, ANOMALY BOOLEAN, END_TIME TIMESTAMPTZ, INITIATOR_NAME TEXT NOT NULL DEFAULT '', LABEL TEXT NOT NULL DEFAULT '', CPU BIGINT NOT NULL DEFAULT 0, GPUS INTEGER NOT NULL DEFAULT 0)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createStatisticsSnapshotsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `COLONYSTATS (COLONY_NAME TEXT NOT NULL, TIME TIMESTAMPTZ, STATISTICS TEXT NOT NULL)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createAlertRulesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `ALERTRULES (ALERT_RULE_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, TYPE TEXT NOT NULL, EXECUTOR_TYPE TEXT, FUNCNAME TEXT, THRESHOLD FLOAT, FOR_SECONDS BIGINT, WINDOW_SECONDS BIGINT, STATE TEXT, VALUE FLOAT, PENDING_SINCE TIMESTAMPTZ, FIRED_TIME TIMESTAMPTZ, RESOLVED_TIME TIMESTAMPTZ, EVALUATED TIMESTAMPTZ, ADDED TIMESTAMPTZ)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createFederationPeersTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `FEDERATIONPEERS (FEDERATION_PEER_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, SERVER_ID TEXT NOT NULL, HOST TEXT, PORT INTEGER, TLS BOOLEAN, REMOTE_COLONY_NAME TEXT, EXECUTOR_TYPES TEXT[], LABELS TEXT[], ADDED TIMESTAMPTZ)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createFederatedProcessesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `FEDERATEDPROCESSES (PROCESS_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, PEER_ID TEXT NOT NULL, REMOTE_PROCESS_ID TEXT, FORWARDED TIMESTAMPTZ)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createJobFencesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `JOBFENCES (JOB TEXT PRIMARY KEY NOT NULL, TOKEN BIGINT NOT NULL, FENCED TIMESTAMPTZ)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (db *PQDatabase) createProcessesIndex1() error {
 	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX1 ON ` + db.dbPrefix + `PROCESSES (TARGET_COLONY_NAME, STATE, SUBMISSION_TIME)`
 	_, err := db.postgresql.Exec(sqlStatement)
@@ -657,6 +962,46 @@ func (db *PQDatabase) createFileIndex3() error {
 	return nil
 }
 
+func (db *PQDatabase) createWebhookDeliveriesIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `WEBHOOKDELIVERIES_INDEX1 ON ` + db.dbPrefix + `WEBHOOKDELIVERIES (WEBHOOK_ID, TIME)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createEventsIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `EVENTS_INDEX1 ON ` + db.dbPrefix + `EVENTS (TIME)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createFunctionRunsIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `FUNCTIONRUNS_INDEX1 ON ` + db.dbPrefix + `FUNCTIONRUNS (COLONY_NAME, FUNCNAME, EXECUTOR_TYPE, END_TIME)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createStatisticsSnapshotsIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `COLONYSTATS_INDEX1 ON ` + db.dbPrefix + `COLONYSTATS (COLONY_NAME, TIME)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (db *PQDatabase) Initialize() error {
 	err := db.createUsersTable()
 	if err != nil {
@@ -728,6 +1073,61 @@ func (db *PQDatabase) Initialize() error {
 		return err
 	}
 
+	err = db.createRetentionPoliciesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createWebhooksTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createWebhookDeliveriesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createEventsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createEventSeqsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFunctionRunsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createStatisticsSnapshotsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createAlertRulesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFederationPeersTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFederatedProcessesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createJobFencesTable()
+	if err != nil {
+		return err
+	}
+
 	err = db.createProcessesIndex1()
 	if err != nil {
 		return err
@@ -828,6 +1228,26 @@ func (db *PQDatabase) Initialize() error {
 		return err
 	}
 
+	err = db.createWebhookDeliveriesIndex1()
+	if err != nil {
+		return err
+	}
+
+	err = db.createEventsIndex1()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFunctionRunsIndex1()
+	if err != nil {
+		return err
+	}
+
+	err = db.createStatisticsSnapshotsIndex1()
+	if err != nil {
+		return err
+	}
+
 	if db.timescaleDB {
 		log.Info("Creating TimescaleDB hypertables")
 		err := db.createHypertables()
@@ -842,5 +1262,5 @@ func (db *PQDatabase) Initialize() error {
 		}
 	}
 
-	return nil
+	return db.createSchemaTable(db.LatestSchemaVersion())
 }
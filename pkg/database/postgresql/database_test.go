@@ -588,3 +588,14 @@ func TestCreateRetentionIndex3(t *testing.T) {
 	err = db.createRetentionIndex3()
 	assert.Nil(t, err)
 }
+
+func TestPing(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	assert.Nil(t, db.Ping(context.Background()))
+
+	db.Close()
+
+	assert.NotNil(t, db.Ping(context.Background()))
+}
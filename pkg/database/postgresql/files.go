@@ -154,6 +154,27 @@ func (db *PQDatabase) GetFileByName(colonyName string, label string, name string
 	return files, nil
 }
 
+func (db *PQDatabase) GetFileByNameAsOf(colonyName string, label string, name string, asOf time.Time) ([]*core.File, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FILES WHERE COLONY_NAME=$1 AND LABEL=$2 AND NAME=$3 AND ADDED<=$4 ORDER BY SEQNR DESC LIMIT 1`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, label, name, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	files, err := db.parseFiles(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 1 {
+		return files, nil
+	}
+
+	return nil, nil
+}
+
 func (db *PQDatabase) GetFilenamesByLabel(colonyName string, label string) ([]string, error) {
 	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FILES WHERE COLONY_NAME=$1 AND LABEL=$2`
 	rows, err := db.postgresql.Query(sqlStatement, colonyName, label)
@@ -210,7 +231,42 @@ func (db *PQDatabase) GetFileDataByLabel(colonyName string, label string) ([]*co
 
 	fileDataArr := []*core.FileData{}
 	for _, file := range filemap {
-		fileData := &core.FileData{Name: file.Name, Checksum: file.Checksum, Size: file.Size, S3Filename: file.Reference.S3Object.Object}
+		fileData := &core.FileData{Name: file.Name, Checksum: file.Checksum, Size: file.Size, S3Filename: file.Reference.S3Object.Object, Added: file.Added}
+		fileDataArr = append(fileDataArr, fileData)
+	}
+
+	return fileDataArr, nil
+}
+
+func (db *PQDatabase) GetFileDataByLabelAsOf(colonyName string, label string, asOf time.Time) ([]*core.FileData, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FILES WHERE COLONY_NAME=$1 AND LABEL=$2 AND ADDED<=$3`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, label, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	files, err := db.parseFiles(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the revision with the highest sequence number that existed at asOf
+	filemap := make(map[string]*core.File)
+	for _, file := range files {
+		if _, ok := filemap[file.Name]; !ok {
+			filemap[file.Name] = file
+		} else {
+			if filemap[file.Name].SequenceNumber < file.SequenceNumber {
+				filemap[file.Name] = file
+			}
+		}
+	}
+
+	fileDataArr := []*core.FileData{}
+	for _, file := range filemap {
+		fileData := &core.FileData{Name: file.Name, Checksum: file.Checksum, Size: file.Size, S3Filename: file.Reference.S3Object.Object, Added: file.Added}
 		fileDataArr = append(fileDataArr, fileData)
 	}
 
@@ -394,3 +450,26 @@ func (db *PQDatabase) CountFilesWithLabel(colonyName string, label string) (int,
 
 	return count, nil
 }
+
+// Returns the number of bytes stored under each label of a colony, counting files added before the given time
+func (db *PQDatabase) GetStorageUsageByLabel(colonyName string, before time.Time) (map[string]int64, error) {
+	sqlStatement := `SELECT LABEL, COALESCE(SUM(SIZE), 0) FROM ` + db.dbPrefix + `FILES WHERE COLONY_NAME=$1 AND ADDED<=$2 GROUP BY LABEL`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, before)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	storage := make(map[string]int64)
+	for rows.Next() {
+		var label string
+		var bytes int64
+		if err := rows.Scan(&label, &bytes); err != nil {
+			return nil, err
+		}
+		storage[label] = bytes
+	}
+
+	return storage, nil
+}
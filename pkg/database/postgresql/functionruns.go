@@ -0,0 +1,80 @@
+package postgresql
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+func (db *PQDatabase) AddFunctionRun(run *core.FunctionRun) error {
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `FUNCTIONRUNS (PROCESS_ID, COLONY_NAME, FUNCNAME, EXECUTOR_TYPE, EXECUTOR_NAME, WAIT_TIME, EXEC_TIME, SUCCESSFUL, ANOMALY, END_TIME, INITIATOR_NAME, LABEL, CPU, GPUS) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+	_, err := db.postgresql.Exec(sqlStatement, run.ProcessID, run.ColonyName, run.FuncName, run.ExecutorType, run.ExecutorName, run.WaitTime, run.ExecTime, run.Successful, run.Anomaly, run.EndTime, run.InitiatorName, run.Label, run.CPU, run.GPUs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) parseFunctionRuns(rows *sql.Rows) ([]*core.FunctionRun, error) {
+	var runs []*core.FunctionRun
+
+	for rows.Next() {
+		run := &core.FunctionRun{}
+		if err := rows.Scan(&run.ProcessID, &run.ColonyName, &run.FuncName, &run.ExecutorType, &run.ExecutorName, &run.WaitTime, &run.ExecTime, &run.Successful, &run.Anomaly, &run.EndTime, &run.InitiatorName, &run.Label, &run.CPU, &run.GPUs); err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+// Returns at most count runs ended after since, newest first. An empty funcName or executorType matches all
+// functions or executor types.
+func (db *PQDatabase) GetFunctionRuns(colonyName string, funcName string, executorType string, since time.Time, count int) ([]*core.FunctionRun, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FUNCTIONRUNS WHERE COLONY_NAME=$1 AND END_TIME>$2 AND ($3='' OR FUNCNAME=$3) AND ($4='' OR EXECUTOR_TYPE=$4) ORDER BY END_TIME DESC LIMIT $5`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, since, funcName, executorType, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseFunctionRuns(rows)
+}
+
+// Returns at most count runs of all functions ended between from and to, oldest first
+func (db *PQDatabase) GetFunctionRunsBetween(colonyName string, from time.Time, to time.Time, count int) ([]*core.FunctionRun, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FUNCTIONRUNS WHERE COLONY_NAME=$1 AND END_TIME>=$2 AND END_TIME<=$3 ORDER BY END_TIME ASC LIMIT $4`
+	rows, err := db.reader().Query(sqlStatement, colonyName, from, to, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseFunctionRuns(rows)
+}
+
+func (db *PQDatabase) RemoveFunctionRunsByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FUNCTIONRUNS WHERE COLONY_NAME=$1`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) RemoveExpiredFunctionRuns(colonyName string, before time.Time) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FUNCTIONRUNS WHERE COLONY_NAME=$1 AND END_TIME<$2`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName, before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
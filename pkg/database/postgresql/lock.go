@@ -1,32 +1,57 @@
 package postgresql
 
 import (
+	"context"
 	"errors"
 	"time"
 )
 
+// Lock acquires a session level advisory lock. The lock is held by a dedicated connection since the connection pool
+// may otherwise run Unlock in another session than Lock.
 func (db *PQDatabase) Lock(timeout int) error {
-	errChan := make(chan error, 1)
-	go func() {
-		sqlStatement := `SELECT pg_advisory_lock(1)`
-		_, err := db.postgresql.Exec(sqlStatement)
-		if err != nil {
-			errChan <- err
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Millisecond)
+	defer cancel()
+
+	conn, err := db.postgresql.Conn(ctx)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.New("lock request timed out")
 		}
-		errChan <- nil
-	}()
+		return err
+	}
 
-	select {
-	case err := <-errChan:
+	sqlStatement := `SELECT pg_advisory_lock(1)`
+	_, err = conn.ExecContext(ctx, sqlStatement)
+	if err != nil {
+		conn.Close()
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.New("lock request timed out")
+		}
 		return err
-	case <-time.After(time.Duration(timeout) * time.Millisecond):
-		return errors.New("lock request timed out")
 	}
+
+	db.lockMutex.Lock()
+	db.lockConn = conn
+	db.lockMutex.Unlock()
+
+	return nil
 }
 
 func (db *PQDatabase) Unlock() error {
+	db.lockMutex.Lock()
+	conn := db.lockConn
+	db.lockConn = nil
+	db.lockMutex.Unlock()
+
 	sqlStatement := `SELECT pg_advisory_unlock(1)`
-	_, err := db.postgresql.Exec(sqlStatement)
+	if conn == nil {
+		_, err := db.postgresql.Exec(sqlStatement)
+		return err
+	}
+
+	defer conn.Close()
+
+	_, err := conn.ExecContext(context.Background(), sqlStatement)
 	if err != nil {
 		return err
 	}
@@ -2,25 +2,42 @@ package postgresql
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 func (db *PQDatabase) AddLog(processID string, colonyName string, executorName string, timestamp int64, msg string) error {
-	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `LOGS (PROCESS_ID, COLONY_NAME, EXECUTOR_NAME, TS, MSG, ADDED) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := db.postgresql.Exec(sqlStatement, processID, colonyName, executorName, timestamp, msg, time.Now())
-	if err != nil {
-		return err
-	}
+	return db.AddStructuredLog(&core.Log{ProcessID: processID, ColonyName: colonyName, ExecutorName: executorName, Timestamp: timestamp, Message: msg})
+}
 
-	return nil
+func (db *PQDatabase) AddStructuredLog(log *core.Log) error {
+	return db.addStructuredLog(log, time.Now())
 }
 
 func (db *PQDatabase) addHistoricalLog(processID string, colonyName string, executorName string, timestamp int64, msg string, t time.Time) error {
-	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `LOGS (PROCESS_ID, COLONY_NAME, EXECUTOR_NAME, TS, MSG, ADDED) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := db.postgresql.Exec(sqlStatement, processID, colonyName, executorName, timestamp, msg, t)
+	return db.addStructuredLog(&core.Log{ProcessID: processID, ColonyName: colonyName, ExecutorName: executorName, Timestamp: timestamp, Message: msg}, t)
+}
+
+func (db *PQDatabase) addStructuredLog(log *core.Log, t time.Time) error {
+	level := log.Level
+	if level == "" {
+		level = core.LogLevelInfo
+	}
+
+	fields := log.Fields
+	if fields == nil {
+		fields = make(map[string]string)
+	}
+	fieldsJSON, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `LOGS (PROCESS_ID, COLONY_NAME, EXECUTOR_NAME, TS, MSG, ADDED, LEVEL, STREAM, FIELDS) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = db.postgresql.Exec(sqlStatement, log.ProcessID, log.ColonyName, log.ExecutorName, log.Timestamp, log.Message, t, level, log.Stream, string(fieldsJSON))
 	if err != nil {
 		return err
 	}
@@ -38,10 +55,19 @@ func (db *PQDatabase) parseLogs(rows *sql.Rows) ([]*core.Log, error) {
 		var ts int64
 		var msg string
 		var added time.Time
-		if err := rows.Scan(&processID, &colonyName, &executorName, &ts, &msg, &added); err != nil {
+		var level string
+		var stream string
+		var fieldsJSON string
+		if err := rows.Scan(&processID, &colonyName, &executorName, &ts, &msg, &added, &level, &stream, &fieldsJSON); err != nil {
+			return nil, err
+		}
+
+		var fields map[string]string
+		if err := json.Unmarshal([]byte(fieldsJSON), &fields); err != nil {
 			return nil, err
 		}
-		log := &core.Log{ProcessID: processID, ColonyName: colonyName, ExecutorName: executorName, Timestamp: ts, Message: msg}
+
+		log := &core.Log{ProcessID: processID, ColonyName: colonyName, ExecutorName: executorName, Timestamp: ts, Message: msg, Level: level, Stream: stream, Fields: fields}
 		logs = append(logs, log)
 	}
 
@@ -50,7 +76,7 @@ func (db *PQDatabase) parseLogs(rows *sql.Rows) ([]*core.Log, error) {
 
 func (db *PQDatabase) GetLogsByProcessID(processID string, limit int) ([]*core.Log, error) {
 	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOGS WHERE PROCESS_ID=$1 ORDER BY TS ASC LIMIT $2`
-	rows, err := db.postgresql.Query(sqlStatement, processID, limit)
+	rows, err := db.reader().Query(sqlStatement, processID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +93,7 @@ func (db *PQDatabase) GetLogsByProcessID(processID string, limit int) ([]*core.L
 
 func (db *PQDatabase) GetLogsByExecutor(executorName string, limit int) ([]*core.Log, error) {
 	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOGS WHERE EXECUTOR_NAME=$1 ORDER BY TS ASC LIMIT $2`
-	rows, err := db.postgresql.Query(sqlStatement, executorName, limit)
+	rows, err := db.reader().Query(sqlStatement, executorName, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +108,8 @@ func (db *PQDatabase) GetLogsByExecutor(executorName string, limit int) ([]*core
 	return logs, nil
 }
 
+// GetLogsByProcessIDSince reads from the primary, since log streaming replays the logs a subscriber missed from it and
+// a lagging replica would leave gaps
 func (db *PQDatabase) GetLogsByProcessIDSince(processID string, limit int, since int64) ([]*core.Log, error) {
 	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOGS WHERE PROCESS_ID=$1 AND TS>$2 ORDER BY TS ASC LIMIT $3`
 	rows, err := db.postgresql.Query(sqlStatement, processID, since, limit)
@@ -99,6 +127,7 @@ func (db *PQDatabase) GetLogsByProcessIDSince(processID string, limit int, since
 	return logs, nil
 }
 
+// GetLogsByExecutorSince reads from the primary, see GetLogsByProcessIDSince
 func (db *PQDatabase) GetLogsByExecutorSince(executorName string, limit int, since int64) ([]*core.Log, error) {
 	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOGS WHERE EXECUTOR_NAME=$1 AND TS>$2 ORDER BY TS ASC LIMIT $3`
 	rows, err := db.postgresql.Query(sqlStatement, executorName, since, limit)
@@ -128,7 +157,7 @@ func (db *PQDatabase) RemoveLogsByColonyName(colonyName string) error {
 
 func (db *PQDatabase) CountLogs(colonyName string) (int, error) {
 	sqlStatement := `SELECT COUNT(*) FROM ` + db.dbPrefix + `LOGS WHERE COLONY_NAME=$1`
-	rows, err := db.postgresql.Query(sqlStatement, colonyName)
+	rows, err := db.reader().Query(sqlStatement, colonyName)
 	if err != nil {
 		return -1, err
 	}
@@ -146,31 +175,29 @@ func (db *PQDatabase) CountLogs(colonyName string) (int, error) {
 }
 
 func (db *PQDatabase) SearchLogs(colonyName string, text string, days int, count int) ([]*core.Log, error) {
+	return db.SearchStructuredLogs(colonyName, text, "", nil, days, count)
+}
+
+func (db *PQDatabase) SearchStructuredLogs(colonyName string, text string, level string, fields map[string]string, days int, count int) ([]*core.Log, error) {
+	if fields == nil {
+		fields = make(map[string]string)
+	}
+	fieldsJSON, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
 	sqlStatement := `SELECT *
                      FROM ` + db.dbPrefix + `LOGS
                      WHERE MSG LIKE '%' || $1 || '%' AND COLONY_NAME = $2 
-                     AND ADDED > NOW() - make_interval(days => $3) LIMIT $4`
+                     AND ADDED > NOW() - make_interval(days => $3) AND LEVEL = ANY($4) AND FIELDS::jsonb @> $5::jsonb LIMIT $6`
 
-	rows, err := db.postgresql.Query(sqlStatement, text, colonyName, days, count)
+	rows, err := db.reader().Query(sqlStatement, text, colonyName, days, pq.Array(core.LogLevelsFrom(level)), string(fieldsJSON), count)
 	if err != nil {
 		return nil, err
 	}
 
 	defer rows.Close()
 
-	var results []*core.Log
-	for rows.Next() {
-		var processID string
-		var colonyName string
-		var executorName string
-		var timestamp int64
-		var message string
-		var added time.Time
-		if err := rows.Scan(&processID, &colonyName, &executorName, &timestamp, &message, &added); err != nil {
-			return nil, err
-		}
-		results = append(results, &core.Log{ProcessID: processID, ColonyName: colonyName, ExecutorName: executorName, Message: message, Timestamp: timestamp})
-	}
-
-	return results, nil
+	return db.parseLogs(rows)
 }
@@ -0,0 +1,254 @@
+package postgresql
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/colonyos/colonies/pkg/database"
+	log "github.com/sirupsen/logrus"
+)
+
+type migration struct {
+	description string
+	migrate     func(db *PQDatabase) error
+}
+
+// migrations[i] upgrades a database from schema version i+1 to version i+2. Version 1 is the schema created before
+// schema versioning was introduced. Initialize always creates the latest schema, so when the schema changes, update
+// the create statements and append a migration that upgrades existing databases. Migrations must be idempotent since
+// a migration may be re-run if the server is stopped before the schema version has been updated.
+var migrations = []migration{
+	{description: "Add retention policies table", migrate: func(db *PQDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `RETENTIONPOLICIES (COLONY_NAME TEXT PRIMARY KEY NOT NULL, SUCCESSFUL_PROCESSES BIGINT, FAILED_PROCESSES BIGINT, PROCESSGRAPHS BIGINT, LOGS BIGINT, FILES BIGINT, ARCHIVE BOOLEAN)`
+		_, err := db.postgresql.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add webhooks and webhook deliveries tables", migrate: func(db *PQDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `WEBHOOKS (WEBHOOK_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, URL TEXT NOT NULL, TYPES TEXT[], LABEL TEXT, SECRET TEXT, ADDED TIMESTAMPTZ)`
+		_, err := db.postgresql.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `WEBHOOKDELIVERIES (DELIVERY_ID TEXT PRIMARY KEY NOT NULL, WEBHOOK_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, EVENT_TYPE TEXT, OBJECT_ID TEXT, ATTEMPTS INTEGER, STATUS_CODE INTEGER, ERROR TEXT, DELIVERED BOOLEAN, TIME TIMESTAMPTZ)`
+		_, err = db.postgresql.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `WEBHOOKDELIVERIES_INDEX1 ON ` + db.dbPrefix + `WEBHOOKDELIVERIES (WEBHOOK_ID, TIME)`
+		_, err = db.postgresql.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add event log tables", migrate: func(db *PQDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `EVENTS (COLONY_NAME TEXT NOT NULL, SEQ BIGINT NOT NULL, TYPE TEXT NOT NULL, LABEL TEXT, OBJECT_ID TEXT, TIME TIMESTAMPTZ, EVENT TEXT NOT NULL, PRIMARY KEY (COLONY_NAME, SEQ))`
+		_, err := db.postgresql.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `EVENTSEQS (COLONY_NAME TEXT PRIMARY KEY NOT NULL, SEQ BIGINT NOT NULL)`
+		_, err = db.postgresql.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `EVENTS_INDEX1 ON ` + db.dbPrefix + `EVENTS (TIME)`
+		_, err = db.postgresql.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add trace context to processes", migrate: func(db *PQDatabase) error {
+		sqlStatement := `ALTER TABLE ` + db.dbPrefix + `PROCESSES ADD COLUMN IF NOT EXISTS TRACE_PARENT TEXT NOT NULL DEFAULT '', ADD COLUMN IF NOT EXISTS TRACE_STATE TEXT NOT NULL DEFAULT ''`
+		_, err := db.postgresql.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add level, stream and fields to logs", migrate: func(db *PQDatabase) error {
+		sqlStatement := `ALTER TABLE ` + db.dbPrefix + `LOGS ADD COLUMN IF NOT EXISTS LEVEL TEXT NOT NULL DEFAULT 'info', ADD COLUMN IF NOT EXISTS STREAM TEXT NOT NULL DEFAULT '', ADD COLUMN IF NOT EXISTS FIELDS TEXT NOT NULL DEFAULT '{}'`
+		_, err := db.postgresql.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add function runs table", migrate: func(db *PQDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `FUNCTIONRUNS (PROCESS_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, FUNCNAME TEXT NOT NULL, EXECUTOR_TYPE TEXT NOT NULL, EXECUTOR_NAME TEXT, WAIT_TIME FLOAT, EXEC_TIME FLOAT, SUCCESSFUL BOOLEAN, ANOMALY BOOLEAN, END_TIME TIMESTAMPTZ)`
+		_, err := db.postgresql.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `FUNCTIONRUNS_INDEX1 ON ` + db.dbPrefix + `FUNCTIONRUNS (COLONY_NAME, FUNCNAME, EXECUTOR_TYPE, END_TIME)`
+		_, err = db.postgresql.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add usage to function runs and colony statistics table", migrate: func(db *PQDatabase) error {
+		sqlStatement := `ALTER TABLE ` + db.dbPrefix + `FUNCTIONRUNS ADD COLUMN IF NOT EXISTS INITIATOR_NAME TEXT NOT NULL DEFAULT '', ADD COLUMN IF NOT EXISTS LABEL TEXT NOT NULL DEFAULT '', ADD COLUMN IF NOT EXISTS CPU BIGINT NOT NULL DEFAULT 0, ADD COLUMN IF NOT EXISTS GPUS INTEGER NOT NULL DEFAULT 0`
+		_, err := db.postgresql.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `COLONYSTATS (COLONY_NAME TEXT NOT NULL, TIME TIMESTAMPTZ, STATISTICS TEXT NOT NULL)`
+		_, err = db.postgresql.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `COLONYSTATS_INDEX1 ON ` + db.dbPrefix + `COLONYSTATS (COLONY_NAME, TIME)`
+		_, err = db.postgresql.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add alert rules table", migrate: func(db *PQDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `ALERTRULES (ALERT_RULE_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, TYPE TEXT NOT NULL, EXECUTOR_TYPE TEXT, FUNCNAME TEXT, THRESHOLD FLOAT, FOR_SECONDS BIGINT, WINDOW_SECONDS BIGINT, STATE TEXT, VALUE FLOAT, PENDING_SINCE TIMESTAMPTZ, FIRED_TIME TIMESTAMPTZ, RESOLVED_TIME TIMESTAMPTZ, EVALUATED TIMESTAMPTZ, ADDED TIMESTAMPTZ)`
+		_, err := db.postgresql.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add federation peers and federated processes tables", migrate: func(db *PQDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `FEDERATIONPEERS (FEDERATION_PEER_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, SERVER_ID TEXT NOT NULL, HOST TEXT, PORT INTEGER, TLS BOOLEAN, REMOTE_COLONY_NAME TEXT, EXECUTOR_TYPES TEXT[], LABELS TEXT[], ADDED TIMESTAMPTZ)`
+		_, err := db.postgresql.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `FEDERATEDPROCESSES (PROCESS_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, PEER_ID TEXT NOT NULL, REMOTE_PROCESS_ID TEXT, FORWARDED TIMESTAMPTZ)`
+		_, err = db.postgresql.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add job fences table", migrate: func(db *PQDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `JOBFENCES (JOB TEXT PRIMARY KEY NOT NULL, TOKEN BIGINT NOT NULL, FENCED TIMESTAMPTZ)`
+		_, err := db.postgresql.Exec(sqlStatement)
+		return err
+	}},
+}
+
+func (db *PQDatabase) createSchemaTable(version int) error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `SCHEMA (VERSION INTEGER NOT NULL)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `INSERT INTO ` + db.dbPrefix + `SCHEMA (VERSION) VALUES ($1)`
+	_, err = db.postgresql.Exec(sqlStatement, version)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropSchemaTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `SCHEMA`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) tableExists(table string) (bool, error) {
+	sqlStatement := `SELECT to_regclass($1) IS NOT NULL`
+	var exists bool
+	err := db.postgresql.QueryRow(sqlStatement, db.dbPrefix+table).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (db *PQDatabase) SchemaVersion() (int, error) {
+	exists, err := db.tableExists("SCHEMA")
+	if err != nil {
+		return -1, err
+	}
+
+	if !exists {
+		// Databases created before schema versioning was introduced have no schema table
+		initialized, err := db.tableExists("SERVER")
+		if err != nil {
+			return -1, err
+		}
+		if initialized {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	sqlStatement := `SELECT VERSION FROM ` + db.dbPrefix + `SCHEMA`
+	rows, err := db.postgresql.Query(sqlStatement)
+	if err != nil {
+		return -1, err
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return -1, errors.New("Schema version missing")
+	}
+
+	var version int
+	err = rows.Scan(&version)
+	if err != nil {
+		return -1, err
+	}
+
+	return version, nil
+}
+
+func (db *PQDatabase) LatestSchemaVersion() int {
+	return len(migrations) + 1
+}
+
+func (db *PQDatabase) Migrations() []database.Migration {
+	var m []database.Migration
+	for i, migration := range migrations {
+		m = append(m, database.Migration{Version: i + 2, Description: migration.description})
+	}
+
+	return m
+}
+
+// Migrate upgrades an initialized database to the latest schema version. Concurrent servers must hold the database
+// lock while migrating.
+func (db *PQDatabase) Migrate() error {
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	if version == 0 {
+		return errors.New("Database is not initialized")
+	}
+
+	latestVersion := db.LatestSchemaVersion()
+	if version > latestVersion {
+		return errors.New("Database schema version " + strconv.Itoa(version) + " is newer than supported version " + strconv.Itoa(latestVersion))
+	}
+
+	exists, err := db.tableExists("SCHEMA")
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		err = db.createSchemaTable(version)
+		if err != nil {
+			return err
+		}
+	}
+
+	for ; version < latestVersion; version++ {
+		log.WithFields(log.Fields{"From": version, "To": version + 1, "Description": migrations[version-1].description}).Info("Migrating PostgreSQL database schema")
+		err = migrations[version-1].migrate(db)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement := `UPDATE ` + db.dbPrefix + `SCHEMA SET VERSION=$1`
+		_, err = db.postgresql.Exec(sqlStatement, version+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	version, err := db.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, db.LatestSchemaVersion(), version)
+
+	err = db.Migrate()
+	assert.Nil(t, err)
+
+	version, err = db.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, db.LatestSchemaVersion(), version)
+}
+
+func TestMigrateUnversionedDatabase(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	// Turn the database into a database created before schema versioning was introduced
+	err = db.dropSchemaTable()
+	assert.Nil(t, err)
+	err = db.dropRetentionPoliciesTable()
+	assert.Nil(t, err)
+
+	version, err := db.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, version)
+
+	err = db.Migrate()
+	assert.Nil(t, err)
+
+	version, err = db.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, db.LatestSchemaVersion(), version)
+
+	policies, err := db.GetRetentionPolicies()
+	assert.Nil(t, err)
+	assert.Len(t, policies, 0)
+}
+
+func TestMigrateNewerSchema(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	_, err = db.postgresql.Exec(`UPDATE `+db.dbPrefix+`SCHEMA SET VERSION=$1`, db.LatestSchemaVersion()+1)
+	assert.Nil(t, err)
+
+	err = db.Migrate()
+	assert.NotNil(t, err)
+}
+
+func TestMigrations(t *testing.T) {
+	db := CreatePQDatabase("localhost", 5432, "postgres", "", "postgres", "TEST_", false)
+
+	m := db.Migrations()
+	assert.Len(t, m, db.LatestSchemaVersion()-1)
+	for i, migration := range m {
+		assert.Equal(t, i+2, migration.Version)
+		assert.NotEmpty(t, migration.Description)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
 	"github.com/colonyos/colonies/pkg/parsers"
 	"github.com/lib/pq"
 	_ "github.com/lib/pq"
@@ -29,7 +30,7 @@ func (db *PQDatabase) AddProcess(process *core.Process) error {
 		return nil
 	}
 
-	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `PROCESSES (PROCESS_ID, TARGET_COLONY_NAME, TARGET_EXECUTOR_NAMES, ASSIGNED_EXECUTOR_ID, STATE, IS_ASSIGNED, EXECUTOR_TYPE, SUBMISSION_TIME, START_TIME, END_TIME, WAIT_DEADLINE, EXEC_DEADLINE, ERRORS, RETRIES, NODENAME, FUNCNAME, ARGS, KWARGS, MAX_WAIT_TIME, MAX_EXEC_TIME, MAX_RETRIES, DEPENDENCIES, PRIORITY, PRIORITYTIME, WAIT_FOR_PARENTS, PARENTS, CHILDREN, PROCESSGRAPH_ID, INPUT, OUTPUT, LABEL, FS, NODES, CPU, PROCESSES, PROCESSES_PER_NODE, MEMORY, STORAGE, GPUNAME, GPUCOUNT, GPUMEM, WALLTIME, INITIATOR_ID, INITIATOR_NAME) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43, $44)`
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `PROCESSES (PROCESS_ID, TARGET_COLONY_NAME, TARGET_EXECUTOR_NAMES, ASSIGNED_EXECUTOR_ID, STATE, IS_ASSIGNED, EXECUTOR_TYPE, SUBMISSION_TIME, START_TIME, END_TIME, WAIT_DEADLINE, EXEC_DEADLINE, ERRORS, RETRIES, NODENAME, FUNCNAME, ARGS, KWARGS, MAX_WAIT_TIME, MAX_EXEC_TIME, MAX_RETRIES, DEPENDENCIES, PRIORITY, PRIORITYTIME, WAIT_FOR_PARENTS, PARENTS, CHILDREN, PROCESSGRAPH_ID, INPUT, OUTPUT, LABEL, FS, NODES, CPU, PROCESSES, PROCESSES_PER_NODE, MEMORY, STORAGE, GPUNAME, GPUCOUNT, GPUMEM, WALLTIME, INITIATOR_ID, INITIATOR_NAME, TRACE_PARENT, TRACE_STATE) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43, $44, $45, $46)`
 
 	argsJSON, err := json.Marshal(process.FunctionSpec.Args)
 	if err != nil {
@@ -77,7 +78,7 @@ func (db *PQDatabase) AddProcess(process *core.Process) error {
 		return err
 	}
 
-	_, err = db.postgresql.Exec(sqlStatement, process.ID, process.FunctionSpec.Conditions.ColonyName, pq.Array(targetExecutorNames), process.AssignedExecutorID, process.State, process.IsAssigned, process.FunctionSpec.Conditions.ExecutorType, submissionTime, time.Time{}, time.Time{}, deadline, process.ExecDeadline, pq.Array(process.Errors), 0, process.FunctionSpec.NodeName, process.FunctionSpec.FuncName, argsJSONStr, kwargsJSONStr, process.FunctionSpec.MaxWaitTime, process.FunctionSpec.MaxExecTime, process.FunctionSpec.MaxRetries, pq.Array(process.FunctionSpec.Conditions.Dependencies), process.FunctionSpec.Priority, process.PriorityTime, process.WaitForParents, pq.Array(process.Parents), pq.Array(process.Children), process.ProcessGraphID, inJSONStr, outJSONStr, process.FunctionSpec.Label, fsJSONStr, process.FunctionSpec.Conditions.Nodes, cpu, process.FunctionSpec.Conditions.Processes, process.FunctionSpec.Conditions.ProcessesPerNode, memory, storage, process.FunctionSpec.Conditions.GPU.Name, process.FunctionSpec.Conditions.GPU.Count, gpuMem, process.FunctionSpec.Conditions.WallTime, process.InitiatorID, process.InitiatorName)
+	_, err = db.postgresql.Exec(sqlStatement, process.ID, process.FunctionSpec.Conditions.ColonyName, pq.Array(targetExecutorNames), process.AssignedExecutorID, process.State, process.IsAssigned, process.FunctionSpec.Conditions.ExecutorType, submissionTime, time.Time{}, time.Time{}, deadline, process.ExecDeadline, pq.Array(process.Errors), 0, process.FunctionSpec.NodeName, process.FunctionSpec.FuncName, argsJSONStr, kwargsJSONStr, process.FunctionSpec.MaxWaitTime, process.FunctionSpec.MaxExecTime, process.FunctionSpec.MaxRetries, pq.Array(process.FunctionSpec.Conditions.Dependencies), process.FunctionSpec.Priority, process.PriorityTime, process.WaitForParents, pq.Array(process.Parents), pq.Array(process.Children), process.ProcessGraphID, inJSONStr, outJSONStr, process.FunctionSpec.Label, fsJSONStr, process.FunctionSpec.Conditions.Nodes, cpu, process.FunctionSpec.Conditions.Processes, process.FunctionSpec.Conditions.ProcessesPerNode, memory, storage, process.FunctionSpec.Conditions.GPU.Name, process.FunctionSpec.Conditions.GPU.Count, gpuMem, process.FunctionSpec.Conditions.WallTime, process.InitiatorID, process.InitiatorName, process.TraceParent, process.TraceState)
 	if err != nil {
 		return err
 	}
@@ -143,8 +144,10 @@ func (db *PQDatabase) parseProcesses(rows *sql.Rows) ([]*core.Process, error) {
 		var walltime int64
 		var initiatorID string
 		var initiatorName string
+		var traceParent string
+		var traceState string
 
-		if err := rows.Scan(&processID, &targetColonyName, pq.Array(&targetExecutorNames), &assignedExecutorID, &state, &isAssigned, &executorType, &submissionTime, &startTime, &endTime, &waitDeadline, &execDeadline, pq.Array(&errs), &nodeName, &funcName, &argsJSONStr, &kwargsJSONStr, &maxWaitTime, &maxExecTime, &retries, &maxRetries, pq.Array(&dependencies), &priority, &priorityTime, &waitForParent, pq.Array(&parents), pq.Array(&children), &processGraphID, &inputJSONStr, &outputJSONStr, &label, &fsJSONStr, &nodes, &cpu, &processesCount, &processesPerNode, &memory, &storage, &gpuName, &gpuCount, &gpuMemory, &walltime, &initiatorID, &initiatorName); err != nil {
+		if err := rows.Scan(&processID, &targetColonyName, pq.Array(&targetExecutorNames), &assignedExecutorID, &state, &isAssigned, &executorType, &submissionTime, &startTime, &endTime, &waitDeadline, &execDeadline, pq.Array(&errs), &nodeName, &funcName, &argsJSONStr, &kwargsJSONStr, &maxWaitTime, &maxExecTime, &retries, &maxRetries, pq.Array(&dependencies), &priority, &priorityTime, &waitForParent, pq.Array(&parents), pq.Array(&children), &processGraphID, &inputJSONStr, &outputJSONStr, &label, &fsJSONStr, &nodes, &cpu, &processesCount, &processesPerNode, &memory, &storage, &gpuName, &gpuCount, &gpuMemory, &walltime, &initiatorID, &initiatorName, &traceParent, &traceState); err != nil {
 			return nil, err
 		}
 
@@ -240,6 +243,8 @@ func (db *PQDatabase) parseProcesses(rows *sql.Rows) ([]*core.Process, error) {
 		process.ProcessGraphID = processGraphID
 		process.InitiatorID = initiatorID
 		process.InitiatorName = initiatorName
+		process.TraceParent = traceParent
+		process.TraceState = traceState
 	}
 
 	return processes, nil
@@ -288,7 +293,7 @@ func (db *PQDatabase) selectCandidate(candidates []*core.Process) *core.Process
 
 func (db *PQDatabase) FindProcessesByColonyName(colonyName string, seconds int, state int) ([]*core.Process, error) {
 	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 AND SUBMISSION_TIME BETWEEN NOW() - INTERVAL '1 seconds' * $3 AND NOW() ORDER BY SUBMISSION_TIME ASC`
-	rows, err := db.postgresql.Query(sqlStatement, colonyName, state, strconv.Itoa(seconds))
+	rows, err := db.reader().Query(sqlStatement, colonyName, state, strconv.Itoa(seconds))
 	if err != nil {
 		return nil, err
 	}
@@ -304,7 +309,7 @@ func (db *PQDatabase) FindProcessesByColonyName(colonyName string, seconds int,
 
 func (db *PQDatabase) FindProcessesByExecutorID(colonyName string, executorID string, seconds int, state int) ([]*core.Process, error) {
 	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND ASSIGNED_EXECUTOR_ID=$2 AND STATE=$3 AND SUBMISSION_TIME BETWEEN NOW() - INTERVAL '1 seconds' * $4 AND NOW() ORDER BY SUBMISSION_TIME ASC`
-	rows, err := db.postgresql.Query(sqlStatement, colonyName, executorID, state, strconv.Itoa(seconds))
+	rows, err := db.reader().Query(sqlStatement, colonyName, executorID, state, strconv.Itoa(seconds))
 	if err != nil {
 		return nil, err
 	}
@@ -325,28 +330,28 @@ func (db *PQDatabase) FindWaitingProcesses(colonyName string, executorType strin
 
 	if executorType != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND EXECUTOR_TYPE=$2 AND STATE=$3 ORDER BY PRIORITYTIME LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, executorType, core.WAITING, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, executorType, core.WAITING, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else if initiator != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND INITIATOR_NAME=$2 AND STATE=$3 ORDER BY PRIORITYTIME LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, initiator, core.WAITING, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, initiator, core.WAITING, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else if label != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND LABEL=$2 AND STATE=$3 ORDER BY PRIORITYTIME LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, label, core.WAITING, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, label, core.WAITING, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 ORDER BY PRIORITYTIME LIMIT $3`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, core.WAITING, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, core.WAITING, count)
 		if err != nil {
 			return nil, err
 		}
@@ -361,6 +366,19 @@ func (db *PQDatabase) FindWaitingProcesses(colonyName string, executorType strin
 	return matches, nil
 }
 
+// FindWaitingProcessesAfter pages through the waiting processes of an executor type in priority order, returning the
+// processes after the process with the given priority time and ID. Pass math.MinInt64 and "" to get the first page.
+func (db *PQDatabase) FindWaitingProcessesAfter(colonyName string, executorType string, priorityTime int64, processID string, count int) ([]*core.Process, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND EXECUTOR_TYPE=$2 AND STATE=$3 AND (PRIORITYTIME>$4 OR (PRIORITYTIME=$4 AND PROCESS_ID>$5)) ORDER BY PRIORITYTIME, PROCESS_ID LIMIT $6`
+	rows, err := db.reader().Query(sqlStatement, colonyName, executorType, core.WAITING, priorityTime, processID, count)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return db.parseProcesses(rows)
+}
+
 func (db *PQDatabase) FindRunningProcesses(colonyName string, executorType string, label string, initiator string, count int) ([]*core.Process, error) {
 	var sqlStatement string
 	var rows *sql.Rows
@@ -368,28 +386,28 @@ func (db *PQDatabase) FindRunningProcesses(colonyName string, executorType strin
 
 	if executorType != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND EXECUTOR_TYPE=$2 AND STATE=$3 ORDER BY START_TIME ASC LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, executorType, core.RUNNING, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, executorType, core.RUNNING, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else if initiator != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND INITIATOR_NAME=$2 AND STATE=$3 ORDER BY START_TIME ASC LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, initiator, core.RUNNING, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, initiator, core.RUNNING, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else if label != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND LABEL=$2 AND STATE=$3 ORDER BY START_TIME ASC LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, label, core.RUNNING, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, label, core.RUNNING, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 ORDER BY START_TIME ASC LIMIT $3`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, core.RUNNING, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, core.RUNNING, count)
 		if err != nil {
 			return nil, err
 		}
@@ -411,28 +429,28 @@ func (db *PQDatabase) FindSuccessfulProcesses(colonyName string, executorType st
 
 	if executorType != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND EXECUTOR_TYPE=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, executorType, core.SUCCESS, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, executorType, core.SUCCESS, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else if initiator != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND INITIATOR_NAME=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, initiator, core.SUCCESS, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, initiator, core.SUCCESS, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else if label != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND LABEL=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, label, core.SUCCESS, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, label, core.SUCCESS, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 ORDER BY END_TIME DESC LIMIT $3`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, core.SUCCESS, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, core.SUCCESS, count)
 		if err != nil {
 			return nil, err
 		}
@@ -454,28 +472,28 @@ func (db *PQDatabase) FindFailedProcesses(colonyName string, executorType string
 
 	if executorType != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND EXECUTOR_TYPE=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, executorType, core.FAILED, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, executorType, core.FAILED, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else if initiator != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND INITIATOR_NAME=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, initiator, core.FAILED, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, initiator, core.FAILED, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else if label != "" {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND LABEL=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, label, core.FAILED, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, label, core.FAILED, count)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 	} else {
 		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 ORDER BY END_TIME DESC LIMIT $3`
-		rows, err = db.postgresql.Query(sqlStatement, colonyName, core.FAILED, count)
+		rows, err = db.reader().Query(sqlStatement, colonyName, core.FAILED, count)
 		if err != nil {
 			return nil, err
 		}
@@ -990,7 +1008,7 @@ func (db *PQDatabase) MarkFailed(processID string, errs []string) error {
 
 func (db *PQDatabase) CountProcesses() (int, error) {
 	sqlStatement := `SELECT COUNT(*) FROM ` + db.dbPrefix + `PROCESSES`
-	rows, err := db.postgresql.Query(sqlStatement)
+	rows, err := db.reader().Query(sqlStatement)
 	if err != nil {
 		return -1, err
 	}
@@ -1009,7 +1027,7 @@ func (db *PQDatabase) CountProcesses() (int, error) {
 
 func (db *PQDatabase) countProcesses(state int) (int, error) {
 	sqlStatement := `SELECT COUNT(*) FROM ` + db.dbPrefix + `PROCESSES WHERE STATE=$1`
-	rows, err := db.postgresql.Query(sqlStatement, state)
+	rows, err := db.reader().Query(sqlStatement, state)
 	if err != nil {
 		return -1, err
 	}
@@ -1038,7 +1056,7 @@ func (db *PQDatabase) countProcesses(state int) (int, error) {
 
 func (db *PQDatabase) countProcessesByColonyName(state int, colonyName string) (int, error) {
 	sqlStatement := `SELECT COUNT(*) FROM ` + db.dbPrefix + `PROCESSES WHERE STATE=$1 AND TARGET_COLONY_NAME=$2`
-	rows, err := db.postgresql.Query(sqlStatement, state, colonyName)
+	rows, err := db.reader().Query(sqlStatement, state, colonyName)
 	if err != nil {
 		return -1, err
 	}
@@ -1086,3 +1104,24 @@ func (db *PQDatabase) CountSuccessfulProcessesByColonyName(colonyName string) (i
 func (db *PQDatabase) CountFailedProcessesByColonyName(colonyName string) (int, error) {
 	return db.countProcessesByColonyName(core.FAILED, colonyName)
 }
+
+func (db *PQDatabase) CountWaitingProcessesByExecutorType() ([]database.QueueDepth, error) {
+	sqlStatement := `SELECT TARGET_COLONY_NAME, COALESCE(EXECUTOR_TYPE, ''), COUNT(*) FROM ` + db.dbPrefix + `PROCESSES WHERE STATE=$1 GROUP BY TARGET_COLONY_NAME, EXECUTOR_TYPE ORDER BY TARGET_COLONY_NAME, EXECUTOR_TYPE`
+	rows, err := db.reader().Query(sqlStatement, core.WAITING)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var queueDepths []database.QueueDepth
+	for rows.Next() {
+		var queueDepth database.QueueDepth
+		if err := rows.Scan(&queueDepth.ColonyName, &queueDepth.ExecutorType, &queueDepth.Count); err != nil {
+			return nil, err
+		}
+		queueDepths = append(queueDepths, queueDepth)
+	}
+
+	return queueDepths, nil
+}
@@ -113,7 +113,7 @@ func (db *PQDatabase) SetProcessGraphState(processGraphID string, state int) err
 
 func (db *PQDatabase) findProcessGraphsByState(colonyName string, state int, count int) ([]*core.ProcessGraph, error) {
 	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSGRAPHS WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 ORDER BY SUBMISSION_TIME DESC LIMIT $3`
-	rows, err := db.postgresql.Query(sqlStatement, colonyName, state, count)
+	rows, err := db.reader().Query(sqlStatement, colonyName, state, count)
 	if err != nil {
 		return nil, err
 	}
@@ -145,7 +145,7 @@ func (db *PQDatabase) FindFailedProcessGraphs(colonyName string, count int) ([]*
 
 func (db *PQDatabase) countProcessGraphsByColonyName(state int, colonyName string) (int, error) {
 	sqlStatement := `SELECT COUNT(*) FROM ` + db.dbPrefix + `PROCESSGRAPHS WHERE STATE=$1 AND TARGET_COLONY_NAME=$2`
-	rows, err := db.postgresql.Query(sqlStatement, state, colonyName)
+	rows, err := db.reader().Query(sqlStatement, state, colonyName)
 	if err != nil {
 		return -1, err
 	}
@@ -179,7 +179,7 @@ func (db *PQDatabase) CountFailedProcessGraphsByColonyName(colonyName string) (i
 
 func (db *PQDatabase) countProcessGraphs(state int) (int, error) {
 	sqlStatement := `SELECT COUNT(*) FROM ` + db.dbPrefix + `PROCESSGRAPHS WHERE STATE=$1`
-	rows, err := db.postgresql.Query(sqlStatement, state)
+	rows, err := db.reader().Query(sqlStatement, state)
 	if err != nil {
 		return -1, err
 	}
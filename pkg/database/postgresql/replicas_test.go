@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicaDSN(t *testing.T) {
+	assert.Equal(t, "postgres://user:pw@replica1:5432/postgres?TimeZone=UTC", replicaDSN("postgres://user:pw@replica1:5432/postgres", "UTC"))
+	assert.Equal(t, "postgres://replica1/postgres?sslmode=disable&TimeZone=UTC", replicaDSN("postgres://replica1/postgres?sslmode=disable", "UTC"))
+	assert.Equal(t, "host=replica1 port=5432 TimeZone=UTC", replicaDSN("host=replica1 port=5432", "UTC"))
+	assert.Equal(t, "host=replica1 TimeZone=Europe/Stockholm", replicaDSN("host=replica1 TimeZone=Europe/Stockholm", "UTC"))
+}
+
+func TestReader(t *testing.T) {
+	primary := &DBMock{}
+	db := &PQDatabase{postgresql: primary}
+	assert.Same(t, primary, db.reader()) // No replicas
+
+	db.SetReadReplicas([]string{"host=replica1", "host=replica2"}, 5*time.Second)
+	replica1 := &DBMock{}
+	replica2 := &DBMock{}
+	db.replicas[0].postgresql = replica1
+	db.replicas[1].postgresql = replica2
+	assert.Same(t, primary, db.reader()) // Replicas have not been checked yet
+
+	db.updateReplica(db.replicas[0], time.Second, nil)
+	db.updateReplica(db.replicas[1], time.Second, nil)
+
+	// Reads are spread over the replicas
+	readers := make(map[Postgresql]int)
+	for i := 0; i < 10; i++ {
+		readers[db.reader()]++
+	}
+	assert.Equal(t, 5, readers[replica1])
+	assert.Equal(t, 5, readers[replica2])
+
+	// Replicas that lag too far behind are not used
+	db.updateReplica(db.replicas[0], 10*time.Second, nil)
+	for i := 0; i < 10; i++ {
+		assert.Same(t, replica2, db.reader())
+	}
+
+	// Replicas with unknown lag, e.g. disconnected from the primary, are not used
+	db.updateReplica(db.replicas[0], replicaLagUnknown, nil)
+	for i := 0; i < 10; i++ {
+		assert.Same(t, replica2, db.reader())
+	}
+
+	// Unreachable replicas are not used
+	db.updateReplica(db.replicas[1], 0, errors.New("error"))
+	assert.Same(t, primary, db.reader())
+
+	db.updateReplica(db.replicas[0], 0, nil)
+	assert.Same(t, replica1, db.reader())
+}
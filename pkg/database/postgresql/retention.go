@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
@@ -14,33 +15,206 @@ func (db *PQDatabase) calcTimestamp(retentionPeriod int64) (time.Time, time.Time
 	return now, timestamp
 }
 
-// retentionPeriod in seconds
+// retentionPeriod in seconds. A colony with a retention policy of its own is only left out for the objects its policy
+// sets a period for, a period of 0 in the policy falls back to the server wide retention period.
 func (db *PQDatabase) ApplyRetentionPolicy(retentionPeriod int64) error {
 	_, timestamp := db.calcTimestamp(retentionPeriod)
 
-	sqlStatement := `DELETE FROM ` + db.dbPrefix + `ATTRIBUTES WHERE ADDED<$1 AND STATE=$2`
+	withoutPolicy := func(period string) string {
+		return `NOT IN (SELECT COLONY_NAME FROM ` + db.dbPrefix + `RETENTIONPOLICIES WHERE ` + period + `>0)`
+	}
+
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `ATTRIBUTES WHERE ADDED<$1 AND STATE=$2 AND TARGET_COLONY_NAME ` + withoutPolicy("SUCCESSFUL_PROCESSES")
 	_, err := db.postgresql.Exec(sqlStatement, timestamp, core.SUCCESS)
 	if err != nil {
 		return err
 	}
 
-	sqlStatement = `DELETE FROM ` + db.dbPrefix + `LOGS WHERE ADDED<$1`
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `LOGS WHERE ADDED<$1 AND COLONY_NAME ` + withoutPolicy("LOGS")
 	_, err = db.postgresql.Exec(sqlStatement, timestamp)
 	if err != nil {
 		return err
 	}
 
-	sqlStatement = `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE SUBMISSION_TIME<$1 AND STATE=$2`
-	_, err = db.postgresql.Exec(sqlStatement, timestamp, core.SUCCESS)
+	// Processes that are part of a process graph that has not finished are kept, since the graph still needs them
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `PROCESSES AS P WHERE SUBMISSION_TIME<$1 AND STATE=$2 AND TARGET_COLONY_NAME ` + withoutPolicy("SUCCESSFUL_PROCESSES") + `
+		AND NOT EXISTS (SELECT 1 FROM ` + db.dbPrefix + `PROCESSGRAPHS G WHERE G.PROCESSGRAPH_ID=P.PROCESSGRAPH_ID AND G.STATE<>$2 AND G.STATE<>$3)`
+	_, err = db.postgresql.Exec(sqlStatement, timestamp, core.SUCCESS, core.FAILED)
 	if err != nil {
 		return err
 	}
 
-	sqlStatement = `DELETE FROM ` + db.dbPrefix + `PROCESSGRAPHS WHERE SUBMISSION_TIME<$1 AND STATE=$2`
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `PROCESSGRAPHS WHERE SUBMISSION_TIME<$1 AND STATE=$2 AND TARGET_COLONY_NAME ` + withoutPolicy("PROCESSGRAPHS")
 	_, err = db.postgresql.Exec(sqlStatement, timestamp, core.SUCCESS)
 	if err != nil {
 		return err
 	}
 
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `EVENTS WHERE TIME<$1 AND COLONY_NAME ` + withoutPolicy("LOGS")
+	_, err = db.postgresql.Exec(sqlStatement, timestamp)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `FUNCTIONRUNS WHERE END_TIME<$1 AND COLONY_NAME ` + withoutPolicy("LOGS")
+	_, err = db.postgresql.Exec(sqlStatement, timestamp)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `COLONYSTATS WHERE TIME<$1 AND COLONY_NAME ` + withoutPolicy("LOGS")
+	_, err = db.postgresql.Exec(sqlStatement, timestamp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) SetRetentionPolicy(policy *core.RetentionPolicy) error {
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `RETENTIONPOLICIES (COLONY_NAME, SUCCESSFUL_PROCESSES, FAILED_PROCESSES, PROCESSGRAPHS, LOGS, FILES, ARCHIVE) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (COLONY_NAME) DO UPDATE SET SUCCESSFUL_PROCESSES=$2, FAILED_PROCESSES=$3, PROCESSGRAPHS=$4, LOGS=$5, FILES=$6, ARCHIVE=$7`
+	_, err := db.postgresql.Exec(sqlStatement, policy.ColonyName, policy.SuccessfulProcesses, policy.FailedProcesses, policy.ProcessGraphs, policy.Logs, policy.Files, policy.Archive)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) parseRetentionPolicies(rows *sql.Rows) ([]*core.RetentionPolicy, error) {
+	var policies []*core.RetentionPolicy
+
+	for rows.Next() {
+		policy := &core.RetentionPolicy{}
+		if err := rows.Scan(&policy.ColonyName, &policy.SuccessfulProcesses, &policy.FailedProcesses, &policy.ProcessGraphs, &policy.Logs, &policy.Files, &policy.Archive); err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+func (db *PQDatabase) GetRetentionPolicy(colonyName string) (*core.RetentionPolicy, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `RETENTIONPOLICIES WHERE COLONY_NAME=$1`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	policies, err := db.parseRetentionPolicies(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	return policies[0], nil
+}
+
+func (db *PQDatabase) GetRetentionPolicies() ([]*core.RetentionPolicy, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `RETENTIONPOLICIES`
+	rows, err := db.postgresql.Query(sqlStatement)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseRetentionPolicies(rows)
+}
+
+func (db *PQDatabase) RemoveRetentionPolicy(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `RETENTIONPOLICIES WHERE COLONY_NAME=$1`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Processes that are part of a process graph that has not finished do not expire, since the graph still needs them
+func (db *PQDatabase) FindExpiredProcesses(colonyName string, state int, before time.Time, count int) ([]*core.Process, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSES P WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 AND SUBMISSION_TIME<$3
+		AND NOT EXISTS (SELECT 1 FROM ` + db.dbPrefix + `PROCESSGRAPHS G WHERE G.PROCESSGRAPH_ID=P.PROCESSGRAPH_ID AND G.STATE<>$4 AND G.STATE<>$5)
+		ORDER BY SUBMISSION_TIME ASC LIMIT $6`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, state, before, core.SUCCESS, core.FAILED, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseProcesses(rows)
+}
+
+// Only process graphs that have finished, successfully or not, can expire
+func (db *PQDatabase) FindExpiredProcessGraphs(colonyName string, before time.Time, count int) ([]*core.ProcessGraph, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSGRAPHS WHERE TARGET_COLONY_NAME=$1 AND (STATE=$2 OR STATE=$3) AND SUBMISSION_TIME<$4 ORDER BY SUBMISSION_TIME ASC LIMIT $5`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, core.SUCCESS, core.FAILED, before, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseProcessGraphs(rows)
+}
+
+// expiredLogBatch selects the count oldest logs added before the given time, and the logs added at the same time as
+// the last of them, so that a batch never splits logs that cannot be told apart by the time they were added
+func (db *PQDatabase) expiredLogBatch() string {
+	return `COLONY_NAME=$1 AND ADDED<$2 AND ADDED<=(SELECT MAX(ADDED) FROM (SELECT ADDED FROM ` + db.dbPrefix + `LOGS WHERE COLONY_NAME=$1 AND ADDED<$2 ORDER BY ADDED ASC LIMIT $3) AS BATCH)`
+}
+
+// Finds the oldest logs added before the given time, about count logs at a time, see RemoveExpiredLogs
+func (db *PQDatabase) FindExpiredLogs(colonyName string, before time.Time, count int) ([]*core.Log, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOGS WHERE ` + db.expiredLogBatch() + ` ORDER BY ADDED ASC`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, before, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseLogs(rows)
+}
+
+// Removes the logs FindExpiredLogs finds with the same arguments, or all logs added before the given time if count is 0
+func (db *PQDatabase) RemoveExpiredLogs(colonyName string, before time.Time, count int) error {
+	var err error
+	if count > 0 {
+		sqlStatement := `DELETE FROM ` + db.dbPrefix + `LOGS WHERE ` + db.expiredLogBatch()
+		_, err = db.postgresql.Exec(sqlStatement, colonyName, before, count)
+	} else {
+		sqlStatement := `DELETE FROM ` + db.dbPrefix + `LOGS WHERE COLONY_NAME=$1 AND ADDED<$2`
+		_, err = db.postgresql.Exec(sqlStatement, colonyName, before)
+	}
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// Finds file revisions added before the given time, the latest revision of each file and revisions that
+// are part of a snapshot never expire
+func (db *PQDatabase) FindExpiredFiles(colonyName string, before time.Time, count int) ([]*core.File, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FILES F WHERE COLONY_NAME=$1 AND ADDED<$2
+		AND SEQNR < (SELECT MAX(SEQNR) FROM ` + db.dbPrefix + `FILES L WHERE L.COLONY_NAME=F.COLONY_NAME AND L.LABEL=F.LABEL AND L.NAME=F.NAME)
+		AND NOT EXISTS (SELECT 1 FROM ` + db.dbPrefix + `SNAPSHOTS S WHERE S.COLONY_NAME=F.COLONY_NAME AND F.FILE_ID=ANY(S.FILE_IDS))
+		ORDER BY SEQNR ASC LIMIT $3`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, before, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseFiles(rows)
+}
@@ -0,0 +1,78 @@
+package postgresql
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+func (db *PQDatabase) AddStatisticsSnapshot(snapshot *core.StatisticsSnapshot) error {
+	jsonString, err := snapshot.Statistics.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `COLONYSTATS (COLONY_NAME, TIME, STATISTICS) VALUES ($1, $2, $3)`
+	_, err = db.postgresql.Exec(sqlStatement, snapshot.ColonyName, snapshot.Time, jsonString)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) parseStatisticsSnapshots(rows *sql.Rows) ([]*core.StatisticsSnapshot, error) {
+	var snapshots []*core.StatisticsSnapshot
+
+	for rows.Next() {
+		var colonyName string
+		var t time.Time
+		var jsonString string
+		if err := rows.Scan(&colonyName, &t, &jsonString); err != nil {
+			return nil, err
+		}
+
+		stat, err := core.ConvertJSONToStatistics(jsonString)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, core.CreateStatisticsSnapshot(colonyName, t, stat))
+	}
+
+	return snapshots, nil
+}
+
+// Returns at most count statistics snapshots taken between from and to, oldest first
+func (db *PQDatabase) GetStatisticsSnapshots(colonyName string, from time.Time, to time.Time, count int) ([]*core.StatisticsSnapshot, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `COLONYSTATS WHERE COLONY_NAME=$1 AND TIME>=$2 AND TIME<=$3 ORDER BY TIME ASC LIMIT $4`
+	rows, err := db.reader().Query(sqlStatement, colonyName, from, to, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseStatisticsSnapshots(rows)
+}
+
+func (db *PQDatabase) RemoveStatisticsSnapshotsByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `COLONYSTATS WHERE COLONY_NAME=$1`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) RemoveExpiredStatisticsSnapshots(colonyName string, before time.Time) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `COLONYSTATS WHERE COLONY_NAME=$1 AND TIME<$2`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName, before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
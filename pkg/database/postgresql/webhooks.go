@@ -0,0 +1,157 @@
+package postgresql
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/lib/pq"
+)
+
+func (db *PQDatabase) AddWebhook(webhook *core.Webhook) error {
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `WEBHOOKS (WEBHOOK_ID, COLONY_NAME, NAME, URL, TYPES, LABEL, SECRET, ADDED) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := db.postgresql.Exec(sqlStatement, webhook.ID, webhook.ColonyName, webhook.Name, webhook.URL, pq.Array(webhook.Types), webhook.Label, webhook.Secret, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) parseWebhooks(rows *sql.Rows) ([]*core.Webhook, error) {
+	var webhooks []*core.Webhook
+
+	for rows.Next() {
+		webhook := &core.Webhook{}
+		var types []string
+		if err := rows.Scan(&webhook.ID, &webhook.ColonyName, &webhook.Name, &webhook.URL, pq.Array(&types), &webhook.Label, &webhook.Secret, &webhook.Added); err != nil {
+			return nil, err
+		}
+		webhook.Types = types
+		if webhook.Types == nil {
+			webhook.Types = []string{}
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+func (db *PQDatabase) GetWebhookByID(webhookID string) (*core.Webhook, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `WEBHOOKS WHERE WEBHOOK_ID=$1`
+	rows, err := db.postgresql.Query(sqlStatement, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	webhooks, err := db.parseWebhooks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+
+	return webhooks[0], nil
+}
+
+func (db *PQDatabase) GetWebhooksByColonyName(colonyName string) ([]*core.Webhook, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `WEBHOOKS WHERE COLONY_NAME=$1 ORDER BY ADDED ASC`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseWebhooks(rows)
+}
+
+func (db *PQDatabase) RemoveWebhookByID(webhookID string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `WEBHOOKDELIVERIES WHERE WEBHOOK_ID=$1`
+	_, err := db.postgresql.Exec(sqlStatement, webhookID)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `WEBHOOKS WHERE WEBHOOK_ID=$1`
+	_, err = db.postgresql.Exec(sqlStatement, webhookID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) RemoveWebhooksByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `WEBHOOKDELIVERIES WHERE COLONY_NAME=$1`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `WEBHOOKS WHERE COLONY_NAME=$1`
+	_, err = db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) AddWebhookDelivery(delivery *core.WebhookDelivery) error {
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `WEBHOOKDELIVERIES (DELIVERY_ID, WEBHOOK_ID, COLONY_NAME, EVENT_TYPE, OBJECT_ID, ATTEMPTS, STATUS_CODE, ERROR, DELIVERED, TIME) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := db.postgresql.Exec(sqlStatement, delivery.ID, delivery.WebhookID, delivery.ColonyName, delivery.EventType, delivery.ObjectID, delivery.Attempts, delivery.StatusCode, delivery.Error, delivery.Delivered, delivery.Time)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Removes the deliveries of all webhooks made before the given time
+func (db *PQDatabase) RemoveExpiredWebhookDeliveries(before time.Time) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `WEBHOOKDELIVERIES WHERE TIME<$1`
+	_, err := db.postgresql.Exec(sqlStatement, before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Removes all but the latest count deliveries of every webhook
+func (db *PQDatabase) RemoveExcessWebhookDeliveries(count int) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `WEBHOOKDELIVERIES WHERE DELIVERY_ID IN (SELECT DELIVERY_ID FROM (SELECT DELIVERY_ID, ROW_NUMBER() OVER (PARTITION BY WEBHOOK_ID ORDER BY TIME DESC) AS N FROM ` + db.dbPrefix + `WEBHOOKDELIVERIES) AS D WHERE D.N>$1)`
+	_, err := db.postgresql.Exec(sqlStatement, count)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Returns the latest deliveries of a webhook, newest first
+func (db *PQDatabase) GetWebhookDeliveries(webhookID string, count int) ([]*core.WebhookDelivery, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `WEBHOOKDELIVERIES WHERE WEBHOOK_ID=$1 ORDER BY TIME DESC LIMIT $2`
+	rows, err := db.postgresql.Query(sqlStatement, webhookID, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var deliveries []*core.WebhookDelivery
+	for rows.Next() {
+		delivery := &core.WebhookDelivery{}
+		if err := rows.Scan(&delivery.ID, &delivery.WebhookID, &delivery.ColonyName, &delivery.EventType, &delivery.ObjectID, &delivery.Attempts, &delivery.StatusCode, &delivery.Error, &delivery.Delivered, &delivery.Time); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
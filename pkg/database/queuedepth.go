@@ -0,0 +1,8 @@
+package database
+
+// QueueDepth is the number of waiting processes in a colony targeting an executor type
+type QueueDepth struct {
+	ColonyName   string `json:"colonyname"`
+	ExecutorType string `json:"executortype"`
+	Count        int    `json:"count"`
+}
@@ -0,0 +1,295 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+func (db *SQLiteDatabase) AddColony(colony *core.Colony) error {
+	if colony == nil {
+		return errors.New("Colony is nil")
+	}
+
+	exitingColony, err := db.GetColonyByName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	if exitingColony != nil {
+		return errors.New("Colony with name <" + colony.Name + "> already exists")
+	}
+
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `COLONIES (COLONY_ID, NAME) VALUES ($1, $2)`
+	_, err = db.sqlite.Exec(sqlStatement, colony.ID, colony.Name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) parseColonies(rows *sql.Rows) ([]*core.Colony, error) {
+	var colonies []*core.Colony
+
+	for rows.Next() {
+		var name string
+		var colonyID string
+		if err := rows.Scan(&name, &colonyID); err != nil {
+			return nil, err
+		}
+
+		colony := core.CreateColony(colonyID, name)
+		colonies = append(colonies, colony)
+	}
+
+	return colonies, nil
+}
+
+func (db *SQLiteDatabase) GetColonies() ([]*core.Colony, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `COLONIES`
+	rows, err := db.sqlite.Query(sqlStatement)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseColonies(rows)
+}
+
+func (db *SQLiteDatabase) GetColonyByID(id string) (*core.Colony, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `COLONIES WHERE COLONY_ID=$1`
+	rows, err := db.sqlite.Query(sqlStatement, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	colonies, err := db.parseColonies(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(colonies) == 0 {
+		return nil, nil
+	}
+
+	return colonies[0], nil
+}
+
+func (db *SQLiteDatabase) GetColonyByName(name string) (*core.Colony, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `COLONIES WHERE NAME=$1`
+	rows, err := db.sqlite.Query(sqlStatement, name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	colonies, err := db.parseColonies(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(colonies) == 0 {
+		return nil, nil
+	}
+
+	return colonies[0], nil
+}
+
+func (db *SQLiteDatabase) ChangeColonyID(colonyName string, oldColonyID, newColonyID string) error {
+	sqlStatement := `UPDATE  ` + db.dbPrefix + `COLONIES SET COLONY_ID=$1 WHERE NAME=$2 AND COLONY_ID=$3`
+	_, err := db.sqlite.Exec(sqlStatement, newColonyID, colonyName, oldColonyID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RenameColony(colonyName string, newName string) error {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `COLONIES SET NAME=$1 WHERE NAME=$2`
+	_, err := db.sqlite.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `RETENTIONPOLICIES SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.sqlite.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `WEBHOOKS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.sqlite.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `WEBHOOKDELIVERIES SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.sqlite.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `EVENTS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.sqlite.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `EVENTSEQS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.sqlite.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `FUNCTIONRUNS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.sqlite.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `COLONYSTATS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.sqlite.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `ALERTRULES SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.sqlite.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `FEDERATIONPEERS SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.sqlite.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `UPDATE ` + db.dbPrefix + `FEDERATEDPROCESSES SET COLONY_NAME=$1 WHERE COLONY_NAME=$2`
+	_, err = db.sqlite.Exec(sqlStatement, newName, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveColonyByName(colonyName string) error {
+	colony, err := db.GetColonyByName(colonyName)
+	if err != nil {
+		return err
+	}
+
+	if colony == nil {
+		return errors.New("Colony does not exists")
+	}
+
+	err = db.RemoveUsersByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveExecutorsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `COLONIES WHERE NAME=$1`
+	_, err = db.sqlite.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllProcessesByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllProcessGraphsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllGeneratorsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllCronsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveFunctionsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveLogsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveFilesByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveSnapshotsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveRetentionPolicy(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveWebhooksByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveEventsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveFunctionRunsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveStatisticsSnapshotsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAlertRulesByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveFederationPeersByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) CountColonies() (int, error) {
+	colonies, err := db.GetColonies()
+	if err != nil {
+		return -1, err
+	}
+
+	return len(colonies), nil
+}
@@ -0,0 +1,1237 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+type SQLite interface {
+	Begin() (*sql.Tx, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	Close() error
+	Conn(ctx context.Context) (*sql.Conn, error)
+	Driver() driver.Driver
+	Exec(query string, args ...any) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	Ping() error
+	PingContext(ctx context.Context) error
+	Prepare(query string) (*sql.Stmt, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	SetConnMaxIdleTime(d time.Duration)
+	SetConnMaxLifetime(d time.Duration)
+	SetMaxIdleConns(n int)
+	SetMaxOpenConns(n int)
+	Stats() sql.DBStats
+}
+
+type SQLiteDatabase struct {
+	sqlite    SQLite
+	dbPath    string
+	dbPrefix  string
+	lock      chan struct{}
+	lockMutex sync.Mutex
+	locked    bool
+}
+
+func CreateSQLiteDatabase(dbPath string, dbPrefix string) *SQLiteDatabase {
+	return &SQLiteDatabase{dbPath: dbPath, dbPrefix: dbPrefix, lock: getLock(dbPath)}
+}
+
+func (db *SQLiteDatabase) Connect() error {
+	// LIKE is made case sensitive to behave the same way as in PostgreSQL
+	dsn := "file:" + db.dbPath + "?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_pragma=case_sensitive_like(1)"
+	if db.dbPath == ":memory:" {
+		dsn = "file::memory:?_pragma=case_sensitive_like(1)"
+	}
+
+	sqlDB, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return err
+	}
+
+	// SQLite only allows one writer at a time, serializing all access through a single connection avoids
+	// SQLITE_BUSY errors and is also required for in-memory databases
+	sqlDB.SetMaxOpenConns(1)
+	db.sqlite = &sqliteDB{DB: sqlDB}
+
+	err = db.sqlite.Ping()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) Close() {
+	db.releaseLock()
+	db.sqlite.Close()
+}
+
+// Ping checks that the database can be reached
+func (db *SQLiteDatabase) Ping(ctx context.Context) error {
+	return db.sqlite.PingContext(ctx)
+}
+
+func (db *SQLiteDatabase) dropUsersTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `USERS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropColoniesTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `COLONIES`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropExecutorsTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `EXECUTORS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropFunctionsTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `FUNCTIONS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropProcessesTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `PROCESSES`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropLogTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `LOGS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropFileTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `FILE_SEQ`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `DROP TABLE IF EXISTS ` + db.dbPrefix + `FILES`
+	_, err = db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropSnapshotTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `SNAPSHOTS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropAttributesTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `ATTRIBUTES`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropProcessGraphsTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `PROCESSGRAPHS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropGeneratorsTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `GENERATORS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropGeneratorArgsTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `GENERATORARGS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropCronsTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `CRONS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropRetentionPoliciesTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `RETENTIONPOLICIES`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropWebhooksTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `WEBHOOKS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropWebhookDeliveriesTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `WEBHOOKDELIVERIES`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropEventsTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `EVENTS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropEventSeqsTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `EVENTSEQS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropStatisticsSnapshotsTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `COLONYSTATS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropAlertRulesTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `ALERTRULES`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropFederationPeersTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `FEDERATIONPEERS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropFederatedProcessesTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `FEDERATEDPROCESSES`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropJobFencesTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `JOBFENCES`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropFunctionRunsTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `FUNCTIONRUNS`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropServerTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `SERVER`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) Drop() error {
+	err := db.dropUsersTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropColoniesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropExecutorsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropFunctionsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropProcessesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropLogTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropFileTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropSnapshotTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropAttributesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropProcessGraphsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropGeneratorsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropGeneratorArgsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropCronsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropRetentionPoliciesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropWebhooksTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropWebhookDeliveriesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropEventsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropEventSeqsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropFunctionRunsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropStatisticsSnapshotsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropAlertRulesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropFederationPeersTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropFederatedProcessesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropJobFencesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropServerTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropSchemaTable()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createServerTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `SERVER (SERVER_ID TEXT PRIMARY KEY NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createColoniesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `COLONIES (NAME TEXT PRIMARY KEY NOT NULL, COLONY_ID TEXT NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createUsersTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `USERS (NAME TEXT PRIMARY KEY NOT NULL, USER_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, EMAIL TEXT NOT NULL, PHONE TEXT NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createExecutorsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `EXECUTORS (NAME TEXT PRIMARY KEY NOT NULL, EXECUTOR_TYPE TEXT NOT NULL, EXECUTOR_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, STATE INTEGER, REQUIRE_FUNC_REG BOOLEAN, COMMISSIONTIME TIMESTAMP, LASTHEARDFROM TIMESTAMP, LONG DOUBLE PRECISION, LAT DOUBLE PRECISION, LOCDESC TEXT, HWMODEL TEXT, HWNODES INT, HWCPU TEXT, HWMEM TEXT, HWSTORAGE TEXT, HWGPUNAME TEXT, HWGPUCOUNT TEXT, HWGPUNODECOUNT INTEGER, HWGPUMEM TEXT, SWNAME TEXT, SWTYPE TEXT, SWVERSION TEXT, ALLOCATIONS TEXT NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (db *SQLiteDatabase) createFunctionsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `FUNCTIONS (FUNCTION_ID TEXT PRIMARY KEY NOT NULL, EXECUTOR_NAME TEXT NOT NULL, EXECUTOR_TYPE TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, FUNCNAME TEXT NOT NULL, COUNTER INTEGER, MINWAITTIME FLOAT, MAXWAITTIME FLOAT, MINEXECTIME FLOAT, MAXEXECTIME FLOAT, AVGWAITTIME FLOAT, AVGEXECTIME FLOAT)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `PROCESSES (PROCESS_ID TEXT PRIMARY KEY NOT NULL, TARGET_COLONY_NAME TEXT NOT NULL, TARGET_EXECUTOR_NAMES TEXT, ASSIGNED_EXECUTOR_ID TEXT, STATE INTEGER, IS_ASSIGNED BOOLEAN, EXECUTOR_TYPE TEXT, SUBMISSION_TIME TIMESTAMP, START_TIME TIMESTAMP, END_TIME TIMESTAMP, WAIT_DEADLINE TIMESTAMP, EXEC_DEADLINE TIMESTAMP, ERRORS TEXT, NODENAME TEXT, FUNCNAME TEXT, ARGS TEXT, KWARGS TEXT, MAX_WAIT_TIME INTEGER, MAX_EXEC_TIME INTEGER, RETRIES INTEGER, MAX_RETRIES INTEGER, DEPENDENCIES TEXT, PRIORITY INTEGER, PRIORITYTIME BIGINT, WAIT_FOR_PARENTS BOOLEAN, PARENTS TEXT, CHILDREN TEXT, PROCESSGRAPH_ID TEXT, INPUT TEXT, OUTPUT TEXT, LABEL TEXT, FS TEXT, NODES INTEGER, CPU BIGINT, PROCESSES INTEGER, PROCESSES_PER_NODE INTEGER, MEMORY BIGINT, STORAGE BIGINT, GPUNAME TEXT, GPUCOUNT TEXT, GPUMEM BIGINT, WALLTIME BIGINT, INITIATOR_ID TEXT NOT NULL, INITIATOR_NAME TEXT NOT NULL, TRACE_PARENT TEXT NOT NULL DEFAULT '', TRACE_STATE TEXT NOT NULL DEFAULT '')`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createLogTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `LOGS (PROCESS_ID TEXT, COLONY_NAME TEXT NOT NULL, EXECUTOR_NAME TEXT NOT NULL, TS BIGINT, MSG TEXT NOT NULL, ADDED TIMESTAMP, LEVEL TEXT NOT NULL DEFAULT 'info', STREAM TEXT NOT NULL DEFAULT '', FIELDS TEXT NOT NULL DEFAULT '{}')`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createFileTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `FILE_SEQ (SEQNR INTEGER NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `INSERT INTO ` + db.dbPrefix + `FILE_SEQ (SEQNR) VALUES (0)`
+	_, err = db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `CREATE TABLE ` + db.dbPrefix + `FILES (FILE_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, LABEL TEXT NOT NULL, NAME TEXT NOT NULL, SIZE BIGINT, SEQNR BIGINT, CHECKSUM TEXT, CHECKSUM_ALG TEXT, ADDED TIMESTAMP, PROTOCOL TEXT, S3_SERVER TEXT, S3_PORT INTEGER, S3_TLS BOOLEAN, S3_ACCESSKEY TEXT, S3_SECRETKEY TEXT, S3_REGION TEXT, S3_ENCKEY TEXT, S3_ENCALG TEXT, S3_OBJ TEXT, S3_BUCKET TEXT)`
+	_, err = db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createSnapshotTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `SNAPSHOTS (SNAPSHOT_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, LABEL TEXT NOT NULL, NAME TEXT NOT NULL UNIQUE, FILE_IDS TEXT, ADDED TIMESTAMP)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createAttributesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `ATTRIBUTES (ATTRIBUTE_ID TEXT PRIMARY KEY NOT NULL, KEY TEXT NOT NULL, VALUE TEXT NOT NULL, ATTRIBUTE_TYPE INTEGER, TARGET_ID TEXT NOT NULL, TARGET_COLONY_NAME TEXT NOT NULL, PROCESSGRAPH_ID TEXT NOT NULL, ADDED TIMESTAMP, STATE INTEGER)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessGraphsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `PROCESSGRAPHS (PROCESSGRAPH_ID TEXT PRIMARY KEY NOT NULL, TARGET_COLONY_NAME TEXT NOT NULL, ROOTS TEXT, STATE INTEGER, SUBMISSION_TIME TIMESTAMP, START_TIME TIMESTAMP, END_TIME TIMESTAMP, INITIATOR_ID TEXT NOT NULL, INITIATOR_NAME TEXT NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createGeneratorsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `GENERATORS (GENERATOR_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, WORKFLOW_SPEC TEXT NOT NULL, TRIGGER INTEGER, TIMEOUT INTEGER, LASTRUN TIMESTAMP, FIRSTPACK TIMESTAMP, INITIATOR_ID TEXT NOT NULL, INITIATOR_NAME TEXT NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createGeneratorArgsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `GENERATORARGS (GENERATORARG_ID TEXT PRIMARY KEY NOT NULL, GENERATOR_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, ARG TEXT NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createCronsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `CRONS (CRON_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL UNIQUE, CRON_EXPR TEXT NOT NULL, INTERVAL INT, RANDOM BOOLEAN, NEXT_RUN TIMESTAMP, LAST_RUN TIMESTAMP, WORKFLOW_SPEC TEXT NOT NULL, PREV_PROCESSGRAPH_ID TEXT NOT NULL, WAIT_FOR_PREV_PROCESSGRAPH BOOLEAN, INITIATOR_ID TEXT NOT NULL, INITIATOR_NAME TEXT NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createRetentionPoliciesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `RETENTIONPOLICIES (COLONY_NAME TEXT PRIMARY KEY NOT NULL, SUCCESSFUL_PROCESSES BIGINT, FAILED_PROCESSES BIGINT, PROCESSGRAPHS BIGINT, LOGS BIGINT, FILES BIGINT, ARCHIVE BOOLEAN)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createWebhooksTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `WEBHOOKS (WEBHOOK_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, URL TEXT NOT NULL, TYPES TEXT, LABEL TEXT, SECRET TEXT, ADDED TIMESTAMP)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createWebhookDeliveriesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `WEBHOOKDELIVERIES (DELIVERY_ID TEXT PRIMARY KEY NOT NULL, WEBHOOK_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, EVENT_TYPE TEXT, OBJECT_ID TEXT, ATTEMPTS INTEGER, STATUS_CODE INTEGER, ERROR TEXT, DELIVERED BOOLEAN, TIME TIMESTAMP)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createEventsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `EVENTS (COLONY_NAME TEXT NOT NULL, SEQ BIGINT NOT NULL, TYPE TEXT NOT NULL, LABEL TEXT, OBJECT_ID TEXT, TIME TIMESTAMP, EVENT TEXT NOT NULL, PRIMARY KEY (COLONY_NAME, SEQ))`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createEventSeqsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `EVENTSEQS (COLONY_NAME TEXT PRIMARY KEY NOT NULL, SEQ BIGINT NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createFunctionRunsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `FUNCTIONRUNS (PROCESS_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, FUNCNAME TEXT NOT NULL, EXECUTOR_TYPE TEXT NOT NULL, EXECUTOR_NAME TEXT, WAIT_TIME FLOAT, EXEC_TIME FLOAT, SUCCESSFUL BOOLEAN, ANOMALY BOOLEAN, END_TIME TIMESTAMP, INITIATOR_NAME TEXT NOT NULL DEFAULT '', LABEL TEXT NOT NULL DEFAULT '', CPU BIGINT NOT NULL DEFAULT 0, GPUS INTEGER NOT NULL DEFAULT 0)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createStatisticsSnapshotsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `COLONYSTATS (COLONY_NAME TEXT NOT NULL, TIME TIMESTAMP, STATISTICS TEXT NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createAlertRulesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `ALERTRULES (ALERT_RULE_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, TYPE TEXT NOT NULL, EXECUTOR_TYPE TEXT, FUNCNAME TEXT, THRESHOLD FLOAT, FOR_SECONDS BIGINT, WINDOW_SECONDS BIGINT, STATE TEXT, VALUE FLOAT, PENDING_SINCE TIMESTAMP, FIRED_TIME TIMESTAMP, RESOLVED_TIME TIMESTAMP, EVALUATED TIMESTAMP, ADDED TIMESTAMP)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createFederationPeersTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `FEDERATIONPEERS (FEDERATION_PEER_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, SERVER_ID TEXT NOT NULL, HOST TEXT, PORT INTEGER, TLS BOOLEAN, REMOTE_COLONY_NAME TEXT, EXECUTOR_TYPES TEXT, LABELS TEXT, ADDED TIMESTAMP)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createFederatedProcessesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `FEDERATEDPROCESSES (PROCESS_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, PEER_ID TEXT NOT NULL, REMOTE_PROCESS_ID TEXT, FORWARDED TIMESTAMP)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createJobFencesTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `JOBFENCES (JOB TEXT PRIMARY KEY NOT NULL, TOKEN BIGINT NOT NULL, FENCED TIMESTAMP)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX1 ON ` + db.dbPrefix + `PROCESSES (TARGET_COLONY_NAME, STATE, SUBMISSION_TIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesIndex2() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX2 ON ` + db.dbPrefix + `PROCESSES (TARGET_COLONY_NAME, STATE, START_TIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesIndex3() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX3 ON ` + db.dbPrefix + `PROCESSES (TARGET_COLONY_NAME, STATE, END_TIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesIndex4() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX4 ON ` + db.dbPrefix + `PROCESSES (IS_ASSIGNED, START_TIME, ASSIGNED_EXECUTOR_ID, STATE, PROCESS_ID)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesIndex5() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX5 ON ` + db.dbPrefix + `PROCESSES (IS_ASSIGNED, START_TIME, ASSIGNED_EXECUTOR_ID, STATE, PROCESS_ID)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesIndex6() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX6 ON ` + db.dbPrefix + `PROCESSES (STATE, EXECUTOR_TYPE, IS_ASSIGNED, WAIT_FOR_PARENTS, TARGET_COLONY_NAME, PRIORITYTIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesIndex7() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX7 ON ` + db.dbPrefix + `PROCESSES (TARGET_COLONY_NAME, STATE, PRIORITYTIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesIndex8() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX8 ON ` + db.dbPrefix + `PROCESSES (TARGET_COLONY_NAME, STATE, EXECUTOR_TYPE, PRIORITYTIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesIndex9() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX9 ON ` + db.dbPrefix + `PROCESSES (TARGET_COLONY_NAME, STATE, INITIATOR_NAME, PRIORITYTIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesIndex10() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX10 ON ` + db.dbPrefix + `PROCESSES (TARGET_COLONY_NAME, STATE, LABEL, PRIORITYTIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createProcessesIndex11() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `PROCESSES_INDEX11 ON ` + db.dbPrefix + `PROCESSES (STATE, EXECUTOR_TYPE, IS_ASSIGNED, WAIT_FOR_PARENTS, TARGET_COLONY_NAME, EXECUTOR_TYPE, IS_ASSIGNED, TARGET_EXECUTOR_NAMES, CPU, MEMORY, GPUNAME, GPUMEM, GPUCOUNT, STORAGE, NODES, PROCESSES, PROCESSES_PER_NODE, PRIORITYTIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createAttributesIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `ATTRIBUTES_INDEX1 ON ` + db.dbPrefix + `ATTRIBUTES (TARGET_ID, ATTRIBUTE_TYPE)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createAttributesIndex2() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `ATTRIBUTES_INDEX2 ON ` + db.dbPrefix + `ATTRIBUTES (TARGET_ID)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createRetentionIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `RETENTION_INDEX1 ON ` + db.dbPrefix + `ATTRIBUTES (ADDED, STATE)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createRetentionIndex2() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `RETENTION_INDEX2 ON ` + db.dbPrefix + `PROCESSES (SUBMISSION_TIME, STATE)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createRetentionIndex3() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `RETENTION_INDEX3 ON ` + db.dbPrefix + `PROCESSGRAPHS (SUBMISSION_TIME, STATE)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createRetentionIndex4() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `RETENTION_INDEX4 ON ` + db.dbPrefix + `FILES (ADDED)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createFileIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `FILE_INDEX1 ON ` + db.dbPrefix + `FILES (COLONY_NAME, LABEL, NAME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createFileIndex2() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `FILE_INDEX2 ON ` + db.dbPrefix + `FILES (COLONY_NAME, FILE_ID)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createFileIndex3() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `FILE_INDEX3 ON ` + db.dbPrefix + `FILES (COLONY_NAME, LABEL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createLogsIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `LOGS_INDEX1 ON ` + db.dbPrefix + `LOGS (PROCESS_ID)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createWebhookDeliveriesIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `WEBHOOKDELIVERIES_INDEX1 ON ` + db.dbPrefix + `WEBHOOKDELIVERIES (WEBHOOK_ID, TIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createEventsIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `EVENTS_INDEX1 ON ` + db.dbPrefix + `EVENTS (TIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createFunctionRunsIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `FUNCTIONRUNS_INDEX1 ON ` + db.dbPrefix + `FUNCTIONRUNS (COLONY_NAME, FUNCNAME, EXECUTOR_TYPE, END_TIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) createStatisticsSnapshotsIndex1() error {
+	sqlStatement := `CREATE INDEX ` + db.dbPrefix + `COLONYSTATS_INDEX1 ON ` + db.dbPrefix + `COLONYSTATS (COLONY_NAME, TIME)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) Initialize() error {
+	err := db.createUsersTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createServerTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createColoniesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createExecutorsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFunctionsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createLogTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFileTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createSnapshotTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createAttributesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessGraphsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createGeneratorsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createGeneratorArgsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createCronsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createRetentionPoliciesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createWebhooksTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createWebhookDeliveriesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createEventsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createEventSeqsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFunctionRunsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createStatisticsSnapshotsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createAlertRulesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFederationPeersTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFederatedProcessesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createJobFencesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesIndex1()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesIndex2()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesIndex3()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesIndex4()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesIndex5()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesIndex6()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesIndex7()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesIndex8()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesIndex9()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesIndex10()
+	if err != nil {
+		return err
+	}
+
+	err = db.createProcessesIndex11()
+	if err != nil {
+		return err
+	}
+
+	err = db.createAttributesIndex1()
+	if err != nil {
+		return err
+	}
+
+	err = db.createAttributesIndex2()
+	if err != nil {
+		return err
+	}
+
+	err = db.createRetentionIndex1()
+	if err != nil {
+		return err
+	}
+
+	err = db.createRetentionIndex2()
+	if err != nil {
+		return err
+	}
+
+	err = db.createRetentionIndex3()
+	if err != nil {
+		return err
+	}
+
+	err = db.createRetentionIndex4()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFileIndex1()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFileIndex2()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFileIndex3()
+	if err != nil {
+		return err
+	}
+
+	err = db.createWebhookDeliveriesIndex1()
+	if err != nil {
+		return err
+	}
+
+	err = db.createEventsIndex1()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFunctionRunsIndex1()
+	if err != nil {
+		return err
+	}
+
+	err = db.createStatisticsSnapshotsIndex1()
+	if err != nil {
+		return err
+	}
+
+	err = db.createLogsIndex1()
+	if err != nil {
+		return err
+	}
+
+	return db.createSchemaTable()
+}
@@ -0,0 +1,171 @@
+package sqlite
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+// AddEvent stores an event in the event log and assigns it the next sequence number of its colony. Sequence numbers
+// are taken from a per colony counter, so that they keep increasing even when old events have been removed.
+func (db *SQLiteDatabase) AddEvent(event *core.Event) error {
+	return db.AddEvents([]*core.Event{event})
+}
+
+// AddEvents stores events in the event log in one transaction, events of the same colony are assigned consecutive
+// sequence numbers in the order they are given. The counter of a colony is locked until the transaction commits, so
+// an event is never visible before events of the same colony with lower sequence numbers.
+func (db *SQLiteDatabase) AddEvents(events []*core.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int64)
+	for _, event := range events {
+		counts[event.ColonyName]++
+	}
+
+	// Counters are locked in the same order by all servers to avoid deadlocks
+	colonyNames := make([]string, 0, len(counts))
+	for colonyName := range counts {
+		colonyNames = append(colonyNames, colonyName)
+	}
+	sort.Strings(colonyNames)
+
+	tx, err := db.sqlite.Begin()
+	if err != nil {
+		return err
+	}
+
+	nextSeqs := make(map[string]int64)
+	for _, colonyName := range colonyNames {
+		var lastSeq int64
+		sqlStatement := `INSERT INTO ` + db.dbPrefix + `EVENTSEQS (COLONY_NAME, SEQ) VALUES ($1, $2) ON CONFLICT (COLONY_NAME) DO UPDATE SET SEQ=` + db.dbPrefix + `EVENTSEQS.SEQ+$2 RETURNING SEQ`
+		err = tx.QueryRow(sqlStatement, convertArgs([]any{colonyName, counts[colonyName]})...).Scan(&lastSeq)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		nextSeqs[colonyName] = lastSeq - counts[colonyName] + 1
+	}
+
+	resetSeqs := func() {
+		for _, event := range events {
+			event.Seq = 0
+		}
+	}
+
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `EVENTS (COLONY_NAME, SEQ, TYPE, LABEL, OBJECT_ID, TIME, EVENT) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, event := range events {
+		event.Seq = nextSeqs[event.ColonyName]
+		nextSeqs[event.ColonyName]++
+
+		jsonString, err := event.ToJSON()
+		if err != nil {
+			resetSeqs()
+			tx.Rollback()
+			return err
+		}
+
+		_, err = tx.Exec(sqlStatement, convertArgs([]any{event.ColonyName, event.Seq, event.Type, event.Label, event.ObjectID, event.Time, jsonString})...)
+		if err != nil {
+			resetSeqs()
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		resetSeqs()
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) parseEvents(rows *sql.Rows) ([]*core.Event, error) {
+	var events []*core.Event
+
+	for rows.Next() {
+		var colonyName string
+		var seq int64
+		var jsonString string
+		if err := rows.Scan(&colonyName, &seq, &jsonString); err != nil {
+			return nil, err
+		}
+
+		event, err := core.ConvertJSONToEvent(jsonString)
+		if err != nil {
+			return nil, err
+		}
+		event.ColonyName = colonyName // The colony may have been renamed since the event was stored
+		event.Seq = seq
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// Returns events with a sequence number greater than or equal to fromSeq, oldest first
+func (db *SQLiteDatabase) GetEvents(colonyName string, fromSeq int64, count int) ([]*core.Event, error) {
+	sqlStatement := `SELECT COLONY_NAME, SEQ, EVENT FROM ` + db.dbPrefix + `EVENTS WHERE COLONY_NAME=$1 AND SEQ>=$2 ORDER BY SEQ ASC LIMIT $3`
+	rows, err := db.sqlite.Query(sqlStatement, colonyName, fromSeq, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseEvents(rows)
+}
+
+func (db *SQLiteDatabase) RemoveEventsByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `EVENTS WHERE COLONY_NAME=$1`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `EVENTSEQS WHERE COLONY_NAME=$1`
+	_, err = db.sqlite.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveExpiredEvents(colonyName string, before time.Time) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `EVENTS WHERE COLONY_NAME=$1 AND TIME<$2`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName, before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Removes events older than before in all colonies
+func (db *SQLiteDatabase) RemoveAllExpiredEvents(before time.Time) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `EVENTS WHERE TIME<$1`
+	_, err := db.sqlite.Exec(sqlStatement, before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Removes all but the latest count events of every colony
+func (db *SQLiteDatabase) RemoveExcessEvents(count int) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `EVENTS WHERE SEQ<=(SELECT S.SEQ FROM ` + db.dbPrefix + `EVENTSEQS S WHERE S.COLONY_NAME=` + db.dbPrefix + `EVENTS.COLONY_NAME)-$1`
+	_, err := db.sqlite.Exec(sqlStatement, count)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+func (db *SQLiteDatabase) AddFederationPeer(peer *core.FederationPeer) error {
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `FEDERATIONPEERS (FEDERATION_PEER_ID, COLONY_NAME, NAME, SERVER_ID, HOST, PORT, TLS, REMOTE_COLONY_NAME, EXECUTOR_TYPES, LABELS, ADDED) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := db.sqlite.Exec(sqlStatement, peer.ID, peer.ColonyName, peer.Name, peer.ServerID, peer.Host, peer.Port, peer.TLS, peer.RemoteColonyName, array(&peer.ExecutorTypes), array(&peer.Labels), time.Now())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) parseFederationPeers(rows *sql.Rows) ([]*core.FederationPeer, error) {
+	var peers []*core.FederationPeer
+
+	for rows.Next() {
+		peer := &core.FederationPeer{}
+		var executorTypes []string
+		var labels []string
+		if err := rows.Scan(&peer.ID, &peer.ColonyName, &peer.Name, &peer.ServerID, &peer.Host, &peer.Port, &peer.TLS, &peer.RemoteColonyName, array(&executorTypes), array(&labels), &peer.Added); err != nil {
+			return nil, err
+		}
+		peer.ExecutorTypes = executorTypes
+		if peer.ExecutorTypes == nil {
+			peer.ExecutorTypes = []string{}
+		}
+		peer.Labels = labels
+		if peer.Labels == nil {
+			peer.Labels = []string{}
+		}
+		peers = append(peers, peer)
+	}
+
+	return peers, nil
+}
+
+func (db *SQLiteDatabase) GetFederationPeerByID(peerID string) (*core.FederationPeer, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FEDERATIONPEERS WHERE FEDERATION_PEER_ID=$1`
+	rows, err := db.sqlite.Query(sqlStatement, peerID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	peers, err := db.parseFederationPeers(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(peers) == 0 {
+		return nil, nil
+	}
+
+	return peers[0], nil
+}
+
+func (db *SQLiteDatabase) GetFederationPeerByName(colonyName string, name string) (*core.FederationPeer, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FEDERATIONPEERS WHERE COLONY_NAME=$1 AND NAME=$2`
+	rows, err := db.sqlite.Query(sqlStatement, colonyName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	peers, err := db.parseFederationPeers(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(peers) == 0 {
+		return nil, nil
+	}
+
+	return peers[0], nil
+}
+
+func (db *SQLiteDatabase) GetFederationPeersByColonyName(colonyName string) ([]*core.FederationPeer, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FEDERATIONPEERS WHERE COLONY_NAME=$1 ORDER BY ADDED ASC`
+	rows, err := db.sqlite.Query(sqlStatement, colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseFederationPeers(rows)
+}
+
+// Processes forwarded to the peer are kept, so that they can be reset to waiting, see syncFederatedProcesses
+func (db *SQLiteDatabase) RemoveFederationPeerByName(colonyName string, name string) error {
+	peer, err := db.GetFederationPeerByName(colonyName, name)
+	if err != nil {
+		return err
+	}
+
+	if peer == nil {
+		return nil
+	}
+
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FEDERATIONPEERS WHERE FEDERATION_PEER_ID=$1`
+	_, err = db.sqlite.Exec(sqlStatement, peer.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveFederationPeersByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FEDERATEDPROCESSES WHERE COLONY_NAME=$1`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `FEDERATIONPEERS WHERE COLONY_NAME=$1`
+	_, err = db.sqlite.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) AddFederatedProcess(federatedProcess *core.FederatedProcess) error {
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `FEDERATEDPROCESSES (PROCESS_ID, COLONY_NAME, PEER_ID, REMOTE_PROCESS_ID, FORWARDED) VALUES ($1, $2, $3, $4, $5)`
+	_, err := db.sqlite.Exec(sqlStatement, federatedProcess.ProcessID, federatedProcess.ColonyName, federatedProcess.PeerID, federatedProcess.RemoteProcessID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Returns processes forwarded to peers, the longest forwarded first
+func (db *SQLiteDatabase) GetFederatedProcesses(count int) ([]*core.FederatedProcess, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FEDERATEDPROCESSES ORDER BY FORWARDED ASC LIMIT $1`
+	rows, err := db.sqlite.Query(sqlStatement, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var federatedProcesses []*core.FederatedProcess
+	for rows.Next() {
+		federatedProcess := &core.FederatedProcess{}
+		if err := rows.Scan(&federatedProcess.ProcessID, &federatedProcess.ColonyName, &federatedProcess.PeerID, &federatedProcess.RemoteProcessID, &federatedProcess.Forwarded); err != nil {
+			return nil, err
+		}
+		federatedProcesses = append(federatedProcesses, federatedProcess)
+	}
+
+	return federatedProcesses, nil
+}
+
+func (db *SQLiteDatabase) RemoveFederatedProcess(processID string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FEDERATEDPROCESSES WHERE PROCESS_ID=$1`
+	_, err := db.sqlite.Exec(sqlStatement, processID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+)
+
+func staleFencingTokenError(job string, token int64) error {
+	return errors.New("Job <" + job + "> has been run by a newer leader, fencing token " + strconv.FormatInt(token, 10) + " is stale")
+}
+
+// FenceJob records the fencing token of the node running a background job. It fails if a node with a newer token,
+// i.e. a later leader of the job, has run the job within the last ttl, so that a node that has lost the leadership
+// of a job without noticing stops running it. Tokens older than ttl are ignored, since the tokens start over if the
+// etcd cluster is recreated. The age of a token is measured by the database clock, so that a node with a skewed clock
+// cannot make a newer token look expired.
+func (db *SQLiteDatabase) FenceJob(job string, token int64, ttl time.Duration) error {
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `JOBFENCES (JOB, TOKEN, FENCED) VALUES ($1, $2, STRFTIME('%Y-%m-%d %H:%M:%f+00:00', 'now')) ON CONFLICT (JOB) DO UPDATE SET TOKEN=EXCLUDED.TOKEN, FENCED=EXCLUDED.FENCED WHERE ` + db.dbPrefix + `JOBFENCES.TOKEN<=EXCLUDED.TOKEN OR ` + db.dbPrefix + `JOBFENCES.FENCED<STRFTIME('%Y-%m-%d %H:%M:%f+00:00', 'now', '-' || $3 || ' seconds')`
+	result, err := db.sqlite.Exec(sqlStatement, job, token, ttl.Seconds())
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return staleFencingTokenError(job, token)
+	}
+
+	return nil
+}
+
+// RunFencedJob runs a background job if token is still the fencing token recorded for the job. SQLite only allows one
+// connection, so the fence row cannot be locked while the job runs, unlike in PostgreSQL. Servers sharing a database
+// in a cluster must use PostgreSQL.
+func (db *SQLiteDatabase) RunFencedJob(job string, token int64, run func() error) error {
+	var fencedToken int64
+	sqlStatement := `SELECT TOKEN FROM ` + db.dbPrefix + `JOBFENCES WHERE JOB=$1`
+	err := db.sqlite.QueryRow(sqlStatement, job).Scan(&fencedToken)
+	if err == sql.ErrNoRows {
+		return staleFencingTokenError(job, token)
+	}
+	if err != nil {
+		return err
+	}
+
+	if fencedToken != token {
+		return staleFencingTokenError(job, token)
+	}
+
+	return run()
+}
@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// SQLite is embedded and only accessed by a single server process, a process local lock per database file is
+// therefore enough to replace the advisory lock used by the PostgreSQL backend
+var (
+	locksMutex sync.Mutex
+	locks      = make(map[string]chan struct{})
+)
+
+func getLock(dbPath string) chan struct{} {
+	if dbPath == ":memory:" {
+		return make(chan struct{}, 1)
+	}
+
+	locksMutex.Lock()
+	defer locksMutex.Unlock()
+
+	lock, ok := locks[dbPath]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		locks[dbPath] = lock
+	}
+
+	return lock
+}
+
+func (db *SQLiteDatabase) Lock(timeout int) error {
+	err := db.sqlite.Ping()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case db.lock <- struct{}{}:
+		db.lockMutex.Lock()
+		db.locked = true
+		db.lockMutex.Unlock()
+		return nil
+	case <-time.After(time.Duration(timeout) * time.Millisecond):
+		return errors.New("lock request timed out")
+	}
+}
+
+func (db *SQLiteDatabase) Unlock() error {
+	err := db.sqlite.Ping()
+	if err != nil {
+		return err
+	}
+
+	return db.releaseLock()
+}
+
+func (db *SQLiteDatabase) releaseLock() error {
+	db.lockMutex.Lock()
+	defer db.lockMutex.Unlock()
+
+	if !db.locked {
+		return errors.New("lock is not held")
+	}
+
+	<-db.lock
+	db.locked = false
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+func (db *SQLiteDatabase) AddLog(processID string, colonyName string, executorName string, timestamp int64, msg string) error {
+	return db.AddStructuredLog(&core.Log{ProcessID: processID, ColonyName: colonyName, ExecutorName: executorName, Timestamp: timestamp, Message: msg})
+}
+
+func (db *SQLiteDatabase) AddStructuredLog(log *core.Log) error {
+	return db.addStructuredLog(log, time.Now())
+}
+
+func (db *SQLiteDatabase) addHistoricalLog(processID string, colonyName string, executorName string, timestamp int64, msg string, t time.Time) error {
+	return db.addStructuredLog(&core.Log{ProcessID: processID, ColonyName: colonyName, ExecutorName: executorName, Timestamp: timestamp, Message: msg}, t)
+}
+
+func (db *SQLiteDatabase) addStructuredLog(log *core.Log, t time.Time) error {
+	level := log.Level
+	if level == "" {
+		level = core.LogLevelInfo
+	}
+
+	fields := log.Fields
+	if fields == nil {
+		fields = make(map[string]string)
+	}
+	fieldsJSON, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `LOGS (PROCESS_ID, COLONY_NAME, EXECUTOR_NAME, TS, MSG, ADDED, LEVEL, STREAM, FIELDS) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = db.sqlite.Exec(sqlStatement, log.ProcessID, log.ColonyName, log.ExecutorName, log.Timestamp, log.Message, t, level, log.Stream, string(fieldsJSON))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) parseLogs(rows *sql.Rows) ([]*core.Log, error) {
+	var logs []*core.Log
+
+	for rows.Next() {
+		var processID string
+		var colonyName string
+		var executorName string
+		var ts int64
+		var msg string
+		var added time.Time
+		var level string
+		var stream string
+		var fieldsJSON string
+		if err := rows.Scan(&processID, &colonyName, &executorName, &ts, &msg, &added, &level, &stream, &fieldsJSON); err != nil {
+			return nil, err
+		}
+
+		var fields map[string]string
+		if err := json.Unmarshal([]byte(fieldsJSON), &fields); err != nil {
+			return nil, err
+		}
+
+		log := &core.Log{ProcessID: processID, ColonyName: colonyName, ExecutorName: executorName, Timestamp: ts, Message: msg, Level: level, Stream: stream, Fields: fields}
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
+
+func (db *SQLiteDatabase) GetLogsByProcessID(processID string, limit int) ([]*core.Log, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOGS WHERE PROCESS_ID=$1 ORDER BY TS ASC LIMIT $2`
+	rows, err := db.sqlite.Query(sqlStatement, processID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	logs, err := db.parseLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (db *SQLiteDatabase) GetLogsByExecutor(executorName string, limit int) ([]*core.Log, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOGS WHERE EXECUTOR_NAME=$1 ORDER BY TS ASC LIMIT $2`
+	rows, err := db.sqlite.Query(sqlStatement, executorName, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	logs, err := db.parseLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (db *SQLiteDatabase) GetLogsByProcessIDSince(processID string, limit int, since int64) ([]*core.Log, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOGS WHERE PROCESS_ID=$1 AND TS>$2 ORDER BY TS ASC LIMIT $3`
+	rows, err := db.sqlite.Query(sqlStatement, processID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	logs, err := db.parseLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (db *SQLiteDatabase) GetLogsByExecutorSince(executorName string, limit int, since int64) ([]*core.Log, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOGS WHERE EXECUTOR_NAME=$1 AND TS>$2 ORDER BY TS ASC LIMIT $3`
+	rows, err := db.sqlite.Query(sqlStatement, executorName, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	logs, err := db.parseLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (db *SQLiteDatabase) RemoveLogsByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `LOGS WHERE COLONY_NAME=$1`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) CountLogs(colonyName string) (int, error) {
+	sqlStatement := `SELECT COUNT(*) FROM ` + db.dbPrefix + `LOGS WHERE COLONY_NAME=$1`
+	rows, err := db.sqlite.Query(sqlStatement, colonyName)
+	if err != nil {
+		return -1, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var count int
+	err = rows.Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
+
+func (db *SQLiteDatabase) SearchLogs(colonyName string, text string, days int, count int) ([]*core.Log, error) {
+	return db.SearchStructuredLogs(colonyName, text, "", nil, days, count)
+}
+
+func (db *SQLiteDatabase) SearchStructuredLogs(colonyName string, text string, level string, fields map[string]string, days int, count int) ([]*core.Log, error) {
+	levels := core.LogLevelsFrom(level)
+	since := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	args := []interface{}{text, colonyName, since}
+
+	var placeholders []string
+	for _, l := range levels {
+		args = append(args, l)
+		placeholders = append(placeholders, "$"+strconv.Itoa(len(args)))
+	}
+
+	sqlStatement := `SELECT *
+                     FROM ` + db.dbPrefix + `LOGS
+                     WHERE INSTR(MSG, $1) > 0 AND COLONY_NAME = $2
+                     AND ADDED > $3 AND LEVEL IN (` + strings.Join(placeholders, ", ") + `)`
+
+	for key, value := range fields {
+		args = append(args, `$."`+key+`"`, value)
+		sqlStatement += ` AND json_extract(FIELDS, $` + strconv.Itoa(len(args)-1) + `) = $` + strconv.Itoa(len(args))
+	}
+
+	args = append(args, count)
+	sqlStatement += ` LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := db.sqlite.Query(sqlStatement, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseLogs(rows)
+}
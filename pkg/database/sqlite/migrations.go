@@ -0,0 +1,279 @@
+package sqlite
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/colonyos/colonies/pkg/database"
+	log "github.com/sirupsen/logrus"
+)
+
+type migration struct {
+	description string
+	migrate     func(db *SQLiteDatabase) error
+}
+
+// migrations[i] upgrades a database from schema version i+1 to version i+2. Initialize always creates the latest
+// schema, so when the schema changes, update the create statements and append a migration that upgrades existing
+// databases.
+var migrations = []migration{
+	{description: "Add webhooks and webhook deliveries tables", migrate: func(db *SQLiteDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `WEBHOOKS (WEBHOOK_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, URL TEXT NOT NULL, TYPES TEXT, LABEL TEXT, SECRET TEXT, ADDED TIMESTAMP)`
+		_, err := db.sqlite.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `WEBHOOKDELIVERIES (DELIVERY_ID TEXT PRIMARY KEY NOT NULL, WEBHOOK_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, EVENT_TYPE TEXT, OBJECT_ID TEXT, ATTEMPTS INTEGER, STATUS_CODE INTEGER, ERROR TEXT, DELIVERED BOOLEAN, TIME TIMESTAMP)`
+		_, err = db.sqlite.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `WEBHOOKDELIVERIES_INDEX1 ON ` + db.dbPrefix + `WEBHOOKDELIVERIES (WEBHOOK_ID, TIME)`
+		_, err = db.sqlite.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add event log tables", migrate: func(db *SQLiteDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `EVENTS (COLONY_NAME TEXT NOT NULL, SEQ BIGINT NOT NULL, TYPE TEXT NOT NULL, LABEL TEXT, OBJECT_ID TEXT, TIME TIMESTAMP, EVENT TEXT NOT NULL, PRIMARY KEY (COLONY_NAME, SEQ))`
+		_, err := db.sqlite.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `EVENTSEQS (COLONY_NAME TEXT PRIMARY KEY NOT NULL, SEQ BIGINT NOT NULL)`
+		_, err = db.sqlite.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `EVENTS_INDEX1 ON ` + db.dbPrefix + `EVENTS (TIME)`
+		_, err = db.sqlite.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add trace context to processes", migrate: func(db *SQLiteDatabase) error {
+		// SQLite does not support ADD COLUMN IF NOT EXISTS
+		for _, column := range []string{"TRACE_PARENT", "TRACE_STATE"} {
+			exists, err := db.columnExists("PROCESSES", column)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+
+			sqlStatement := `ALTER TABLE ` + db.dbPrefix + `PROCESSES ADD COLUMN ` + column + ` TEXT NOT NULL DEFAULT ''`
+			_, err = db.sqlite.Exec(sqlStatement)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}},
+	{description: "Add level, stream and fields to logs", migrate: func(db *SQLiteDatabase) error {
+		columns := []struct{ name, definition string }{
+			{"LEVEL", `TEXT NOT NULL DEFAULT 'info'`},
+			{"STREAM", `TEXT NOT NULL DEFAULT ''`},
+			{"FIELDS", `TEXT NOT NULL DEFAULT '{}'`},
+		}
+		for _, column := range columns {
+			exists, err := db.columnExists("LOGS", column.name)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+
+			sqlStatement := `ALTER TABLE ` + db.dbPrefix + `LOGS ADD COLUMN ` + column.name + ` ` + column.definition
+			_, err = db.sqlite.Exec(sqlStatement)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}},
+	{description: "Add function runs table", migrate: func(db *SQLiteDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `FUNCTIONRUNS (PROCESS_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, FUNCNAME TEXT NOT NULL, EXECUTOR_TYPE TEXT NOT NULL, EXECUTOR_NAME TEXT, WAIT_TIME FLOAT, EXEC_TIME FLOAT, SUCCESSFUL BOOLEAN, ANOMALY BOOLEAN, END_TIME TIMESTAMP)`
+		_, err := db.sqlite.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `FUNCTIONRUNS_INDEX1 ON ` + db.dbPrefix + `FUNCTIONRUNS (COLONY_NAME, FUNCNAME, EXECUTOR_TYPE, END_TIME)`
+		_, err = db.sqlite.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add usage to function runs and colony statistics table", migrate: func(db *SQLiteDatabase) error {
+		columns := []struct{ name, definition string }{
+			{"INITIATOR_NAME", `TEXT NOT NULL DEFAULT ''`},
+			{"LABEL", `TEXT NOT NULL DEFAULT ''`},
+			{"CPU", `BIGINT NOT NULL DEFAULT 0`},
+			{"GPUS", `INTEGER NOT NULL DEFAULT 0`},
+		}
+		for _, column := range columns {
+			exists, err := db.columnExists("FUNCTIONRUNS", column.name)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+
+			sqlStatement := `ALTER TABLE ` + db.dbPrefix + `FUNCTIONRUNS ADD COLUMN ` + column.name + ` ` + column.definition
+			_, err = db.sqlite.Exec(sqlStatement)
+			if err != nil {
+				return err
+			}
+		}
+
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `COLONYSTATS (COLONY_NAME TEXT NOT NULL, TIME TIMESTAMP, STATISTICS TEXT NOT NULL)`
+		_, err := db.sqlite.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `COLONYSTATS_INDEX1 ON ` + db.dbPrefix + `COLONYSTATS (COLONY_NAME, TIME)`
+		_, err = db.sqlite.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add alert rules table", migrate: func(db *SQLiteDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `ALERTRULES (ALERT_RULE_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, TYPE TEXT NOT NULL, EXECUTOR_TYPE TEXT, FUNCNAME TEXT, THRESHOLD FLOAT, FOR_SECONDS BIGINT, WINDOW_SECONDS BIGINT, STATE TEXT, VALUE FLOAT, PENDING_SINCE TIMESTAMP, FIRED_TIME TIMESTAMP, RESOLVED_TIME TIMESTAMP, EVALUATED TIMESTAMP, ADDED TIMESTAMP)`
+		_, err := db.sqlite.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add federation peers and federated processes tables", migrate: func(db *SQLiteDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `FEDERATIONPEERS (FEDERATION_PEER_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, SERVER_ID TEXT NOT NULL, HOST TEXT, PORT INTEGER, TLS BOOLEAN, REMOTE_COLONY_NAME TEXT, EXECUTOR_TYPES TEXT, LABELS TEXT, ADDED TIMESTAMP)`
+		_, err := db.sqlite.Exec(sqlStatement)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement = `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `FEDERATEDPROCESSES (PROCESS_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, PEER_ID TEXT NOT NULL, REMOTE_PROCESS_ID TEXT, FORWARDED TIMESTAMP)`
+		_, err = db.sqlite.Exec(sqlStatement)
+		return err
+	}},
+	{description: "Add job fences table", migrate: func(db *SQLiteDatabase) error {
+		sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `JOBFENCES (JOB TEXT PRIMARY KEY NOT NULL, TOKEN BIGINT NOT NULL, FENCED TIMESTAMP)`
+		_, err := db.sqlite.Exec(sqlStatement)
+		return err
+	}},
+}
+
+func (db *SQLiteDatabase) createSchemaTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `SCHEMA (VERSION INTEGER NOT NULL)`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `INSERT INTO ` + db.dbPrefix + `SCHEMA (VERSION) VALUES ($1)`
+	_, err = db.sqlite.Exec(sqlStatement, db.LatestSchemaVersion())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) dropSchemaTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `SCHEMA`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) columnExists(table string, column string) (bool, error) {
+	sqlStatement := `SELECT COUNT(*) FROM pragma_table_info($1) WHERE UPPER(name)=$2`
+	var count int
+	err := db.sqlite.QueryRow(sqlStatement, db.dbPrefix+table, column).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (db *SQLiteDatabase) SchemaVersion() (int, error) {
+	sqlStatement := `SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=$1`
+	var count int
+	err := db.sqlite.QueryRow(sqlStatement, db.dbPrefix+"SCHEMA").Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	sqlStatement = `SELECT VERSION FROM ` + db.dbPrefix + `SCHEMA`
+	rows, err := db.sqlite.Query(sqlStatement)
+	if err != nil {
+		return -1, err
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return -1, errors.New("Schema version missing")
+	}
+
+	var version int
+	err = rows.Scan(&version)
+	if err != nil {
+		return -1, err
+	}
+
+	return version, nil
+}
+
+func (db *SQLiteDatabase) LatestSchemaVersion() int {
+	return len(migrations) + 1
+}
+
+func (db *SQLiteDatabase) Migrations() []database.Migration {
+	var m []database.Migration
+	for i, migration := range migrations {
+		m = append(m, database.Migration{Version: i + 2, Description: migration.description})
+	}
+
+	return m
+}
+
+// Migrate upgrades an initialized database to the latest schema version
+func (db *SQLiteDatabase) Migrate() error {
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	if version == 0 {
+		return errors.New("Database is not initialized")
+	}
+
+	latestVersion := db.LatestSchemaVersion()
+	if version > latestVersion {
+		return errors.New("Database schema version " + strconv.Itoa(version) + " is newer than supported version " + strconv.Itoa(latestVersion))
+	}
+
+	for ; version < latestVersion; version++ {
+		log.WithFields(log.Fields{"From": version, "To": version + 1, "Description": migrations[version-1].description}).Info("Migrating SQLite database schema")
+		err = migrations[version-1].migrate(db)
+		if err != nil {
+			return err
+		}
+
+		sqlStatement := `UPDATE ` + db.dbPrefix + `SCHEMA SET VERSION=$1`
+		_, err = db.sqlite.Exec(sqlStatement, version+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	version, err := db.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, db.LatestSchemaVersion(), version)
+
+	err = db.Migrate()
+	assert.Nil(t, err)
+
+	version, err = db.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, db.LatestSchemaVersion(), version)
+}
+
+func TestMigrate(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	// Add a migration, the database now has an older schema version than the latest one
+	migrated := false
+	savedMigrations := migrations
+	migrations = append(migrations, migration{description: "Test migration", migrate: func(db *SQLiteDatabase) error {
+		migrated = true
+		return nil
+	}})
+	defer func() { migrations = savedMigrations }()
+
+	version, err := db.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, db.LatestSchemaVersion()-1, version)
+
+	m := db.Migrations()
+	assert.Len(t, m, len(migrations))
+	assert.Equal(t, db.LatestSchemaVersion(), m[len(m)-1].Version)
+	assert.Equal(t, "Test migration", m[len(m)-1].Description)
+
+	err = db.Migrate()
+	assert.Nil(t, err)
+	assert.True(t, migrated)
+
+	version, err = db.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, db.LatestSchemaVersion(), version)
+}
+
+func TestMigrateNewerSchema(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	_, err = db.sqlite.Exec(`UPDATE `+db.dbPrefix+`SCHEMA SET VERSION=$1`, db.LatestSchemaVersion()+1)
+	assert.Nil(t, err)
+
+	err = db.Migrate()
+	assert.NotNil(t, err)
+}
+
+func TestMigrateUninitialized(t *testing.T) {
+	db := CreateSQLiteDatabase(":memory:", "TEST_")
+	err := db.Connect()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	version, err := db.SchemaVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, version)
+
+	err = db.Migrate()
+	assert.NotNil(t, err)
+}
+
+func TestMigrateProcessTraceContext(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	exists, err := db.columnExists("PROCESSES", "TRACE_PARENT")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	_, err = db.sqlite.Exec(`ALTER TABLE ` + db.dbPrefix + `PROCESSES DROP COLUMN TRACE_STATE`)
+	assert.Nil(t, err)
+
+	exists, err = db.columnExists("PROCESSES", "TRACE_STATE")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	for _, m := range migrations {
+		if m.description == "Add trace context to processes" {
+			assert.Nil(t, m.migrate(db))
+			assert.Nil(t, m.migrate(db)) // Migrations must be idempotent
+		}
+	}
+
+	exists, err = db.columnExists("PROCESSES", "TRACE_STATE")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
+
+func TestMigrateLogFields(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	for _, column := range []string{"LEVEL", "STREAM", "FIELDS"} {
+		_, err = db.sqlite.Exec(`ALTER TABLE ` + db.dbPrefix + `LOGS DROP COLUMN ` + column)
+		assert.Nil(t, err)
+	}
+
+	_, err = db.sqlite.Exec(`INSERT INTO `+db.dbPrefix+`LOGS (PROCESS_ID, COLONY_NAME, EXECUTOR_NAME, TS, MSG, ADDED) VALUES ('test_processid', 'test_colony', 'test_executor_name', 1, 'test_msg', $1)`, time.Now())
+	assert.Nil(t, err)
+
+	for _, m := range migrations {
+		if m.description == "Add level, stream and fields to logs" {
+			assert.Nil(t, m.migrate(db))
+			assert.Nil(t, m.migrate(db)) // Migrations must be idempotent
+		}
+	}
+
+	logs, err := db.GetLogsByProcessID("test_processid", 10)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, core.LogLevelInfo, logs[0].Level)
+	assert.Len(t, logs[0].Fields, 0)
+}
+
+func TestMigrateFunctionRunUsage(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	for _, column := range []string{"INITIATOR_NAME", "LABEL", "CPU", "GPUS"} {
+		_, err = db.sqlite.Exec(`ALTER TABLE ` + db.dbPrefix + `FUNCTIONRUNS DROP COLUMN ` + column)
+		assert.Nil(t, err)
+	}
+
+	_, err = db.sqlite.Exec(`DROP TABLE ` + db.dbPrefix + `COLONYSTATS`)
+	assert.Nil(t, err)
+
+	_, err = db.sqlite.Exec(`INSERT INTO `+db.dbPrefix+`FUNCTIONRUNS (PROCESS_ID, COLONY_NAME, FUNCNAME, EXECUTOR_TYPE, EXECUTOR_NAME, WAIT_TIME, EXEC_TIME, SUCCESSFUL, ANOMALY, END_TIME) VALUES ('test_processid', 'test_colony', 'test_func', 'test_executor_type', 'test_executor', 1, 2, true, false, $1)`, time.Now())
+	assert.Nil(t, err)
+
+	for _, m := range migrations {
+		if m.description == "Add usage to function runs and colony statistics table" {
+			assert.Nil(t, m.migrate(db))
+			assert.Nil(t, m.migrate(db)) // Migrations must be idempotent
+		}
+	}
+
+	runs, err := db.GetFunctionRuns("test_colony", "", "", time.Time{}, 10)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 1)
+	assert.Equal(t, "", runs[0].InitiatorName)
+	assert.Equal(t, int64(0), runs[0].CPU)
+
+	err = db.AddStatisticsSnapshot(core.CreateStatisticsSnapshot("test_colony", time.Now(), core.CreateStatistics(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,1117 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/parsers"
+)
+
+func (db *SQLiteDatabase) AddProcess(process *core.Process) error {
+	targetExecutorNames := process.FunctionSpec.Conditions.ExecutorNames
+
+	submissionTime := time.Now()
+
+	maxWaitTime := process.FunctionSpec.MaxWaitTime
+	var deadline time.Time
+	if maxWaitTime > 0 {
+		deadline = time.Now().Add(time.Duration(maxWaitTime) * time.Second)
+	}
+
+	fsJSONStr, err := json.Marshal(process.FunctionSpec.Filesystem)
+	if err != nil {
+		return nil
+	}
+
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `PROCESSES (PROCESS_ID, TARGET_COLONY_NAME, TARGET_EXECUTOR_NAMES, ASSIGNED_EXECUTOR_ID, STATE, IS_ASSIGNED, EXECUTOR_TYPE, SUBMISSION_TIME, START_TIME, END_TIME, WAIT_DEADLINE, EXEC_DEADLINE, ERRORS, RETRIES, NODENAME, FUNCNAME, ARGS, KWARGS, MAX_WAIT_TIME, MAX_EXEC_TIME, MAX_RETRIES, DEPENDENCIES, PRIORITY, PRIORITYTIME, WAIT_FOR_PARENTS, PARENTS, CHILDREN, PROCESSGRAPH_ID, INPUT, OUTPUT, LABEL, FS, NODES, CPU, PROCESSES, PROCESSES_PER_NODE, MEMORY, STORAGE, GPUNAME, GPUCOUNT, GPUMEM, WALLTIME, INITIATOR_ID, INITIATOR_NAME, TRACE_PARENT, TRACE_STATE) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43, $44, $45, $46)`
+
+	argsJSON, err := json.Marshal(process.FunctionSpec.Args)
+	if err != nil {
+		return err
+	}
+	argsJSONStr := string(argsJSON)
+
+	kwargsJSON, err := json.Marshal(process.FunctionSpec.KwArgs)
+	if err != nil {
+		return err
+	}
+	kwargsJSONStr := string(kwargsJSON)
+
+	inJSON, err := json.Marshal(process.Input)
+	if err != nil {
+		return err
+	}
+	inJSONStr := string(inJSON)
+
+	outJSON, err := json.Marshal(process.Output)
+	if err != nil {
+		return err
+	}
+	outJSONStr := string(outJSON)
+
+	process.SetSubmissionTime(submissionTime)
+
+	cpu, err := parsers.ConvertCPUToInt(process.FunctionSpec.Conditions.CPU)
+	if err != nil {
+		return err
+	}
+
+	memory, err := parsers.ConvertMemoryToBytes(process.FunctionSpec.Conditions.Memory)
+	if err != nil {
+		return err
+	}
+
+	storage, err := parsers.ConvertMemoryToBytes(process.FunctionSpec.Conditions.Storage)
+	if err != nil {
+		return err
+	}
+
+	gpuMem, err := parsers.ConvertMemoryToBytes(process.FunctionSpec.Conditions.GPU.Memory)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.sqlite.Exec(sqlStatement, process.ID, process.FunctionSpec.Conditions.ColonyName, array(&targetExecutorNames), process.AssignedExecutorID, process.State, process.IsAssigned, process.FunctionSpec.Conditions.ExecutorType, submissionTime, time.Time{}, time.Time{}, deadline, process.ExecDeadline, array(&process.Errors), 0, process.FunctionSpec.NodeName, process.FunctionSpec.FuncName, argsJSONStr, kwargsJSONStr, process.FunctionSpec.MaxWaitTime, process.FunctionSpec.MaxExecTime, process.FunctionSpec.MaxRetries, array(&process.FunctionSpec.Conditions.Dependencies), process.FunctionSpec.Priority, process.PriorityTime, process.WaitForParents, array(&process.Parents), array(&process.Children), process.ProcessGraphID, inJSONStr, outJSONStr, process.FunctionSpec.Label, fsJSONStr, process.FunctionSpec.Conditions.Nodes, cpu, process.FunctionSpec.Conditions.Processes, process.FunctionSpec.Conditions.ProcessesPerNode, memory, storage, process.FunctionSpec.Conditions.GPU.Name, process.FunctionSpec.Conditions.GPU.Count, gpuMem, process.FunctionSpec.Conditions.WallTime, process.InitiatorID, process.InitiatorName, process.TraceParent, process.TraceState)
+	if err != nil {
+		return err
+	}
+
+	// Convert Envs to Attributes
+	for key, value := range process.FunctionSpec.Env {
+		process.Attributes = append(process.Attributes, core.CreateAttribute(process.ID, process.FunctionSpec.Conditions.ColonyName, process.ProcessGraphID, core.ENV, key, value))
+	}
+
+	err = db.AddAttributes(process.Attributes)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) parseProcesses(rows *sql.Rows) ([]*core.Process, error) {
+	var processes []*core.Process
+
+	for rows.Next() {
+		var processID string
+		var targetColonyName string
+		var targetExecutorNames []string
+		var assignedExecutorID string
+		var state int
+		var isAssigned bool
+		var executorType string
+		var submissionTime time.Time
+		var startTime time.Time
+		var endTime time.Time
+		var waitDeadline time.Time
+		var execDeadline time.Time
+		var errs []string
+		var nodeName string
+		var funcName string
+		var argsJSONStr string
+		var kwargsJSONStr string
+		var maxWaitTime int
+		var maxExecTime int
+		var retries int
+		var maxRetries int
+		var dependencies []string
+		var priority int
+		var priorityTime int64
+		var waitForParent bool
+		var parents []string
+		var children []string
+		var processGraphID string
+		var inputJSONStr string
+		var outputJSONStr string
+		var label string
+		var fsJSONStr string
+		var nodes int
+		var cpu int64
+		var processesCount int
+		var processesPerNode int
+		var memory int64
+		var storage int64
+		var gpuName string
+		var gpuCount int
+		var gpuMemory int64
+		var walltime int64
+		var initiatorID string
+		var initiatorName string
+		var traceParent string
+		var traceState string
+
+		if err := rows.Scan(&processID, &targetColonyName, array(&targetExecutorNames), &assignedExecutorID, &state, &isAssigned, &executorType, &submissionTime, &startTime, &endTime, &waitDeadline, &execDeadline, array(&errs), &nodeName, &funcName, &argsJSONStr, &kwargsJSONStr, &maxWaitTime, &maxExecTime, &retries, &maxRetries, array(&dependencies), &priority, &priorityTime, &waitForParent, array(&parents), array(&children), &processGraphID, &inputJSONStr, &outputJSONStr, &label, &fsJSONStr, &nodes, &cpu, &processesCount, &processesPerNode, &memory, &storage, &gpuName, &gpuCount, &gpuMemory, &walltime, &initiatorID, &initiatorName, &traceParent, &traceState); err != nil {
+			return nil, err
+		}
+
+		if len(targetExecutorNames) == 1 && targetExecutorNames[0] == "*" {
+			targetExecutorNames = []string{}
+		}
+
+		if len(dependencies) == 0 {
+			dependencies = make([]string, 0)
+		}
+
+		var argsif []interface{}
+		err := json.Unmarshal([]byte(argsJSONStr), &argsif)
+		if err != nil {
+			return nil, err
+		}
+
+		var kwargsif map[string]interface{}
+		err = json.Unmarshal([]byte(kwargsJSONStr), &kwargsif)
+		if err != nil {
+			return nil, err
+		}
+
+		var inputif []interface{}
+		err = json.Unmarshal([]byte(inputJSONStr), &inputif)
+		if err != nil {
+			return nil, err
+		}
+
+		var outputif []interface{}
+		err = json.Unmarshal([]byte(outputJSONStr), &outputif)
+		if err != nil {
+			return nil, err
+		}
+
+		functionSpec := core.CreateFunctionSpec(nodeName, funcName, argsif, kwargsif, targetColonyName, targetExecutorNames, executorType, maxWaitTime, maxExecTime, maxRetries, make(map[string]string), dependencies, priority, label)
+
+		functionSpec.Conditions.Nodes = nodes
+		functionSpec.Conditions.CPU = parsers.ConvertCPUToString(cpu)
+		functionSpec.Conditions.Processes = processesCount
+		functionSpec.Conditions.ProcessesPerNode = processesPerNode
+		functionSpec.Conditions.Memory = parsers.ConvertMemoryToString(memory)
+		functionSpec.Conditions.Storage = parsers.ConvertMemoryToString(storage)
+		functionSpec.Conditions.GPU.Name = gpuName
+		functionSpec.Conditions.GPU.Count = gpuCount
+		functionSpec.Conditions.GPU.Memory = parsers.ConvertMemoryToString(gpuMemory)
+		functionSpec.Conditions.WallTime = walltime
+
+		fs := core.Filesystem{}
+		err = json.Unmarshal([]byte(fsJSONStr), &fs)
+		if err != nil {
+			return nil, err
+		}
+		functionSpec.Filesystem = fs
+
+		process := core.CreateProcessFromDB(functionSpec, processID, assignedExecutorID, isAssigned, state, priorityTime, submissionTime, startTime, endTime, waitDeadline, execDeadline, errs, retries, make([]core.Attribute, 0))
+
+		process.Input = inputif
+		process.Output = outputif
+		processes = append(processes, process)
+
+		process.WaitForParents = waitForParent
+		if len(parents) == 0 {
+			process.Parents = make([]string, 0)
+		} else {
+			process.Parents = parents
+		}
+		if len(children) == 0 {
+			process.Children = make([]string, 0)
+		} else {
+			process.Children = children
+		}
+		process.ProcessGraphID = processGraphID
+		process.InitiatorID = initiatorID
+		process.InitiatorName = initiatorName
+		process.TraceParent = traceParent
+		process.TraceState = traceState
+	}
+
+	// Attributes are fetched once all rows have been read since the database only has a single connection
+	for _, process := range processes {
+		attributes, err := db.GetAttributes(process.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(attributes) > 0 {
+			process.Attributes = attributes
+		}
+
+		// Restore env map
+		envAttributes, err := db.GetAttributesByType(process.ID, core.ENV)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, attribute := range envAttributes {
+			process.FunctionSpec.Env[attribute.Key] = attribute.Value
+		}
+	}
+
+	return processes, nil
+}
+
+func (db *SQLiteDatabase) GetProcesses() ([]*core.Process, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSES ORDER BY SUBMISSION_TIME DESC`
+	rows, err := db.sqlite.Query(sqlStatement)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseProcesses(rows)
+}
+
+func (db *SQLiteDatabase) GetProcessByID(processID string) (*core.Process, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE PROCESS_ID=$1`
+	rows, err := db.sqlite.Query(sqlStatement, processID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	processes, err := db.parseProcesses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(processes) == 0 {
+		return nil, nil
+	}
+
+	return processes[0], nil
+}
+
+func (db *SQLiteDatabase) selectCandidate(candidates []*core.Process) *core.Process {
+	if len(candidates) > 0 {
+		return candidates[0]
+	} else {
+		return nil
+	}
+}
+
+func (db *SQLiteDatabase) FindProcessesByColonyName(colonyName string, seconds int, state int) ([]*core.Process, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 AND SUBMISSION_TIME BETWEEN $3 AND $4 ORDER BY SUBMISSION_TIME ASC`
+	now := time.Now()
+	rows, err := db.sqlite.Query(sqlStatement, colonyName, state, now.Add(-time.Duration(seconds)*time.Second), now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches, err := db.parseProcesses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (db *SQLiteDatabase) FindProcessesByExecutorID(colonyName string, executorID string, seconds int, state int) ([]*core.Process, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND ASSIGNED_EXECUTOR_ID=$2 AND STATE=$3 AND SUBMISSION_TIME BETWEEN $4 AND $5 ORDER BY SUBMISSION_TIME ASC`
+	now := time.Now()
+	rows, err := db.sqlite.Query(sqlStatement, colonyName, executorID, state, now.Add(-time.Duration(seconds)*time.Second), now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches, err := db.parseProcesses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (db *SQLiteDatabase) FindWaitingProcesses(colonyName string, executorType string, label string, initiator string, count int) ([]*core.Process, error) {
+	var sqlStatement string
+	var rows *sql.Rows
+	var err error
+
+	if executorType != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND EXECUTOR_TYPE=$2 AND STATE=$3 ORDER BY PRIORITYTIME LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, executorType, core.WAITING, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else if initiator != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND INITIATOR_NAME=$2 AND STATE=$3 ORDER BY PRIORITYTIME LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, initiator, core.WAITING, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else if label != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND LABEL=$2 AND STATE=$3 ORDER BY PRIORITYTIME LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, label, core.WAITING, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 ORDER BY PRIORITYTIME LIMIT $3`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, core.WAITING, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	}
+
+	matches, err := db.parseProcesses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// FindWaitingProcessesAfter pages through the waiting processes of an executor type in priority order, returning the
+// processes after the process with the given priority time and ID. Pass math.MinInt64 and "" to get the first page.
+func (db *SQLiteDatabase) FindWaitingProcessesAfter(colonyName string, executorType string, priorityTime int64, processID string, count int) ([]*core.Process, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND EXECUTOR_TYPE=$2 AND STATE=$3 AND (PRIORITYTIME>$4 OR (PRIORITYTIME=$4 AND PROCESS_ID>$5)) ORDER BY PRIORITYTIME, PROCESS_ID LIMIT $6`
+	rows, err := db.sqlite.Query(sqlStatement, colonyName, executorType, core.WAITING, priorityTime, processID, count)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return db.parseProcesses(rows)
+}
+
+func (db *SQLiteDatabase) FindRunningProcesses(colonyName string, executorType string, label string, initiator string, count int) ([]*core.Process, error) {
+	var sqlStatement string
+	var rows *sql.Rows
+	var err error
+
+	if executorType != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND EXECUTOR_TYPE=$2 AND STATE=$3 ORDER BY START_TIME ASC LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, executorType, core.RUNNING, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else if initiator != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND INITIATOR_NAME=$2 AND STATE=$3 ORDER BY START_TIME ASC LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, initiator, core.RUNNING, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else if label != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND LABEL=$2 AND STATE=$3 ORDER BY START_TIME ASC LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, label, core.RUNNING, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 ORDER BY START_TIME ASC LIMIT $3`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, core.RUNNING, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	}
+
+	matches, err := db.parseProcesses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (db *SQLiteDatabase) FindSuccessfulProcesses(colonyName string, executorType string, label string, initiator string, count int) ([]*core.Process, error) {
+	var sqlStatement string
+	var rows *sql.Rows
+	var err error
+
+	if executorType != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND EXECUTOR_TYPE=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, executorType, core.SUCCESS, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else if initiator != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND INITIATOR_NAME=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, initiator, core.SUCCESS, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else if label != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND LABEL=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, label, core.SUCCESS, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 ORDER BY END_TIME DESC LIMIT $3`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, core.SUCCESS, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	}
+
+	matches, err := db.parseProcesses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (db *SQLiteDatabase) FindFailedProcesses(colonyName string, executorType string, label string, initiator string, count int) ([]*core.Process, error) {
+	var sqlStatement string
+	var rows *sql.Rows
+	var err error
+
+	if executorType != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND EXECUTOR_TYPE=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, executorType, core.FAILED, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else if initiator != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND INITIATOR_NAME=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, initiator, core.FAILED, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else if label != "" {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND LABEL=$2 AND STATE=$3 ORDER BY END_TIME DESC LIMIT $4`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, label, core.FAILED, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	} else {
+		sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND STATE=$2 ORDER BY END_TIME DESC LIMIT $3`
+		rows, err = db.sqlite.Query(sqlStatement, colonyName, core.FAILED, count)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+	}
+
+	matches, err := db.parseProcesses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (db *SQLiteDatabase) FindAllRunningProcesses() ([]*core.Process, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE STATE=$1 ORDER BY START_TIME ASC`
+	rows, err := db.sqlite.Query(sqlStatement, core.RUNNING)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches, err := db.parseProcesses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (db *SQLiteDatabase) FindAllWaitingProcesses() ([]*core.Process, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE STATE=$1 ORDER BY PRIORITYTIME LIMIT 1000`
+	rows, err := db.sqlite.Query(sqlStatement, core.WAITING)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches, err := db.parseProcesses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (db *SQLiteDatabase) FindCandidates(colonyName string, executorType string, cpu int64, memory int64, storage int64, nodes int, processes int, processesPerNode int, count int) ([]*core.Process, error) {
+	var sqlStatement string
+
+	sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE STATE=$1 AND EXECUTOR_TYPE=$2 AND IS_ASSIGNED=FALSE AND WAIT_FOR_PARENTS=FALSE AND TARGET_COLONY_NAME=$3 AND IFNULL(json_array_length(TARGET_EXECUTOR_NAMES), 0)=0 AND CPU<=$4 AND MEMORY<=$5 AND STORAGE<=$6 AND NODES<=$7 AND PROCESSES<=$8 AND PROCESSES_PER_NODE<=$9 ORDER BY PRIORITYTIME LIMIT $10`
+	rows, err := db.sqlite.Query(sqlStatement, core.WAITING, executorType, colonyName, cpu, memory, storage, nodes, processes, processesPerNode, count)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches, err := db.parseProcesses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (db *SQLiteDatabase) FindCandidatesByName(colonyName string, executorName string, executorType string, cpu int64, memory int64, storage int64, nodes int, processes int, processesPerNode int, count int) ([]*core.Process, error) {
+	var sqlStatement string
+
+	sqlStatement = `SELECT * FROM ` + db.dbPrefix + `PROCESSES WHERE STATE=$1 AND EXISTS (SELECT 1 FROM json_each(TARGET_EXECUTOR_NAMES) WHERE value=$2) AND EXECUTOR_TYPE=$3 AND IS_ASSIGNED=FALSE AND WAIT_FOR_PARENTS=FALSE AND TARGET_COLONY_NAME=$4 AND CPU<=$5 AND MEMORY<=$6 AND STORAGE<=$7 AND NODES<=$8 AND PROCESSES<=$9 AND PROCESSES_PER_NODE<=$10 ORDER BY PRIORITYTIME LIMIT $11`
+	rows, err := db.sqlite.Query(sqlStatement, core.WAITING, executorName, executorType, colonyName, cpu, memory, storage, nodes, processes, processesPerNode, count)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches, err := db.parseProcesses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (db *SQLiteDatabase) RemoveProcessByID(processID string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE PROCESS_ID=$1`
+	_, err := db.sqlite.Exec(sqlStatement, processID)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllAttributesByTargetID(processID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveAllProcesses() error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES`
+	_, err := db.sqlite.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllAttributes()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveAllWaitingProcessesByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName, "", core.WAITING)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllAttributesByColonyNameWithState(colonyName, core.WAITING)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveAllRunningProcessesByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName, "", core.RUNNING)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllAttributesByColonyNameWithState(colonyName, core.RUNNING)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveAllSuccessfulProcessesByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName, "", core.SUCCESS)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllAttributesByColonyNameWithState(colonyName, core.SUCCESS)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveAllFailedProcessesByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName, "", core.FAILED)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllAttributesByColonyNameWithState(colonyName, core.FAILED)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveAllProcessesByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName, "")
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllAttributesByColonyName(colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveAllProcessesByProcessGraphID(processGraphID string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE PROCESSGRAPH_ID=$1`
+	_, err := db.sqlite.Exec(sqlStatement, processGraphID)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveAllAttributesByProcessGraphID(processGraphID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveAllProcessesInProcessGraphsByColonyName(colonyName string) error {
+	err := db.RemoveAllAttributesInProcessGraphsByColonyName(colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID!=$2`
+	_, err = db.sqlite.Exec(sqlStatement, colonyName, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveAllProcessesInProcessGraphsByColonyNameWithState(colonyName string, state int) error {
+	err := db.RemoveAllAttributesInProcessGraphsByColonyNameWithState(colonyName, state)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID!=$2 AND STATE=$3`
+	_, err = db.sqlite.Exec(sqlStatement, colonyName, "", state)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) ResetProcess(process *core.Process) error {
+	submissionTime := time.Now()
+
+	maxWaitTime := process.FunctionSpec.MaxWaitTime
+	if maxWaitTime > 0 {
+		deadline := time.Now().Add(time.Duration(maxWaitTime) * time.Second)
+		sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET IS_ASSIGNED=FALSE, SUBMISSION_TIME=$1, START_TIME=$2, END_TIME=$3, ASSIGNED_EXECUTOR_ID=$4, STATE=$5, WAIT_DEADLINE=$6 WHERE PROCESS_ID=$7`
+		_, err := db.sqlite.Exec(sqlStatement, submissionTime, time.Time{}, time.Time{}, "", core.WAITING, deadline, process.ID)
+		if err != nil {
+			return err
+		}
+	} else {
+		sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET IS_ASSIGNED=FALSE, SUBMISSION_TIME=$1, START_TIME=$2, END_TIME=$3, ASSIGNED_EXECUTOR_ID=$4, STATE=$5 WHERE PROCESS_ID=$6`
+		_, err := db.sqlite.Exec(sqlStatement, submissionTime, time.Time{}, time.Time{}, "", core.WAITING, process.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	process.SetSubmissionTime(submissionTime)
+	process.SetStartTime(time.Time{})
+	process.SetEndTime(time.Time{})
+	process.SetAssignedExecutorID("")
+	process.SetState(core.WAITING)
+
+	return nil
+}
+
+func (db *SQLiteDatabase) SetWaitForParents(processID string, waitForParent bool) error {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET WAIT_FOR_PARENTS=$1 WHERE PROCESS_ID=$2`
+	_, err := db.sqlite.Exec(sqlStatement, waitForParent, processID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) SetParents(processID string, parents []string) error {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET PARENTS=$1 WHERE PROCESS_ID=$2`
+	_, err := db.sqlite.Exec(sqlStatement, array(&parents), processID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) SetChildren(processID string, children []string) error {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET CHILDREN=$1 WHERE PROCESS_ID=$2`
+	_, err := db.sqlite.Exec(sqlStatement, array(&children), processID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) SetProcessState(processID string, state int) error {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET STATE=$1 WHERE PROCESS_ID=$2`
+	_, err := db.sqlite.Exec(sqlStatement, state, processID)
+	if err != nil {
+		return err
+	}
+
+	err = db.SetAttributeState(processID, state)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) SetInput(processID string, input []interface{}) error {
+	inJSON, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	inJSONStr := string(inJSON)
+
+	sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET INPUT=$1 WHERE PROCESS_ID=$2`
+	_, err = db.sqlite.Exec(sqlStatement, inJSONStr, processID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) SetOutput(processID string, output []interface{}) error {
+	outJSON, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	outJSONStr := string(outJSON)
+
+	sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET OUTPUT=$1 WHERE PROCESS_ID=$2`
+	_, err = db.sqlite.Exec(sqlStatement, outJSONStr, processID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) SetErrors(processID string, errs []string) error {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET ERRORS=$1 WHERE PROCESS_ID=$2`
+	_, err := db.sqlite.Exec(sqlStatement, array(&errs), processID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) SetExecDeadline(process *core.Process, execDeadline time.Time) error {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET EXEC_DEADLINE=$1 WHERE PROCESS_ID=$2`
+	_, err := db.sqlite.Exec(sqlStatement, execDeadline, process.ID)
+	if err != nil {
+		return err
+	}
+
+	process.ExecDeadline = execDeadline
+
+	return nil
+}
+
+func (db *SQLiteDatabase) SetWaitDeadline(process *core.Process, waitDeadline time.Time) error {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET WAIT_DEADLINE=$1 WHERE PROCESS_ID=$2`
+	_, err := db.sqlite.Exec(sqlStatement, waitDeadline, process.ID)
+	if err != nil {
+		return err
+	}
+
+	process.ExecDeadline = waitDeadline
+
+	return nil
+}
+
+func (db *SQLiteDatabase) Assign(executorID string, process *core.Process) error {
+	processFromDB, err := db.GetProcessByID(process.ID)
+	if err != nil {
+		return err
+	}
+
+	if processFromDB.IsAssigned {
+		return errors.New("Process already assigned")
+	}
+
+	startTime := time.Now()
+	if process.FunctionSpec.MaxExecTime > 0 {
+		deadline := time.Now().Add(time.Duration(process.FunctionSpec.MaxExecTime) * time.Second)
+		sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET IS_ASSIGNED=TRUE, START_TIME=$1, ASSIGNED_EXECUTOR_ID=$2, STATE=$3, EXEC_DEADLINE=$4 WHERE PROCESS_ID=$5`
+		_, err = db.sqlite.Exec(sqlStatement, startTime, executorID, core.RUNNING, deadline, process.ID)
+		if err != nil {
+			return err
+		}
+	} else {
+		sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET IS_ASSIGNED=TRUE, START_TIME=$1, ASSIGNED_EXECUTOR_ID=$2, STATE=$3 WHERE PROCESS_ID=$4`
+		_, err = db.sqlite.Exec(sqlStatement, startTime, executorID, core.RUNNING, process.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = db.SetAttributeState(process.ID, core.RUNNING)
+	if err != nil {
+		return err
+	}
+
+	process.SetStartTime(startTime)
+	process.Assign()
+	process.SetAssignedExecutorID(executorID)
+	process.SetState(core.RUNNING)
+
+	return nil
+}
+
+func (db *SQLiteDatabase) Unassign(process *core.Process) error {
+	endTime := time.Now()
+
+	maxWaitTime := process.FunctionSpec.MaxWaitTime
+	if maxWaitTime > 0 {
+		deadline := time.Now().Add(time.Duration(maxWaitTime) * time.Second)
+
+		sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET IS_ASSIGNED=FALSE, END_TIME=$1, STATE=$2, RETRIES=$3, ASSIGNED_EXECUTOR_ID=$4, WAIT_DEADLINE=$5 WHERE PROCESS_ID=$6`
+		_, err := db.sqlite.Exec(sqlStatement, endTime, core.WAITING, process.Retries+1, "", deadline, process.ID)
+		if err != nil {
+			return err
+		}
+	} else {
+		sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET IS_ASSIGNED=FALSE, END_TIME=$1, STATE=$2, RETRIES=$3, ASSIGNED_EXECUTOR_ID=$4 WHERE PROCESS_ID=$5`
+		_, err := db.sqlite.Exec(sqlStatement, endTime, core.WAITING, process.Retries+1, "", process.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := db.SetAttributeState(process.ID, core.PENDING)
+	if err != nil {
+		return err
+	}
+
+	process.SetEndTime(endTime)
+	process.Unassign()
+	process.SetState(core.WAITING)
+
+	return nil
+}
+
+func (db *SQLiteDatabase) MarkSuccessful(processID string) (float64, float64, error) {
+	process, err := db.GetProcessByID(processID)
+	if err != nil {
+		return 0.0, 0.0, err
+	}
+
+	if process.State == core.FAILED {
+		return 0.0, 0.0, errors.New("Tried to set failed process as successful")
+	}
+
+	if process.State == core.WAITING {
+		return 0.0, 0.0, errors.New("Tried to set waiting process as successful without being running")
+	}
+
+	endTime := time.Now()
+
+	sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET END_TIME=$1, STATE=$2 WHERE PROCESS_ID=$3`
+	_, err = db.sqlite.Exec(sqlStatement, endTime, core.SUCCESS, process.ID)
+	if err != nil {
+		return 0.0, 0.0, err
+	}
+
+	err = db.SetAttributeState(process.ID, core.SUCCESS)
+	if err != nil {
+		return 0.0, 0.0, err
+	}
+
+	process.SetEndTime(endTime)
+	process.SetState(core.SUCCESS)
+
+	return process.WaitingTime().Seconds(), process.ProcessingTime().Seconds(), nil
+}
+
+func (db *SQLiteDatabase) MarkFailed(processID string, errs []string) error {
+	endTime := time.Now()
+	process, err := db.GetProcessByID(processID)
+	if err != nil {
+		return err
+	}
+
+	if process == nil {
+		return errors.New("Process with Id <" + processID + "> not found")
+	}
+
+	if process.State == core.SUCCESS {
+		return errors.New("Tried to set successful process as failed")
+	}
+
+	if process.State == core.FAILED {
+		return errors.New("Tried to set failed process as failed")
+	}
+
+	sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET END_TIME=$1, STATE=$2 WHERE PROCESS_ID=$3`
+	_, err = db.sqlite.Exec(sqlStatement, endTime, core.FAILED, process.ID)
+	if err != nil {
+		return err
+	}
+
+	err = db.SetAttributeState(process.ID, core.FAILED)
+	if err != nil {
+		return err
+	}
+
+	process.SetEndTime(endTime)
+	process.SetState(core.FAILED)
+
+	return db.SetErrors(process.ID, errs)
+}
+
+func (db *SQLiteDatabase) CountProcesses() (int, error) {
+	sqlStatement := `SELECT COUNT(*) FROM ` + db.dbPrefix + `PROCESSES`
+	rows, err := db.sqlite.Query(sqlStatement)
+	if err != nil {
+		return -1, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var count int
+	err = rows.Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
+
+func (db *SQLiteDatabase) countProcesses(state int) (int, error) {
+	sqlStatement := `SELECT COUNT(*) FROM ` + db.dbPrefix + `PROCESSES WHERE STATE=$1`
+	rows, err := db.sqlite.Query(sqlStatement, state)
+	if err != nil {
+		return -1, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var count int
+	err = rows.Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
+
+func (db *SQLiteDatabase) countProcessesByColonyName(state int, colonyName string) (int, error) {
+	sqlStatement := `SELECT COUNT(*) FROM ` + db.dbPrefix + `PROCESSES WHERE STATE=$1 AND TARGET_COLONY_NAME=$2`
+	rows, err := db.sqlite.Query(sqlStatement, state, colonyName)
+	if err != nil {
+		return -1, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var count int
+	err = rows.Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
+
+func (db *SQLiteDatabase) CountWaitingProcesses() (int, error) {
+	return db.countProcesses(core.WAITING)
+}
+
+func (db *SQLiteDatabase) CountRunningProcesses() (int, error) {
+	return db.countProcesses(core.RUNNING)
+}
+
+func (db *SQLiteDatabase) CountSuccessfulProcesses() (int, error) {
+	return db.countProcesses(core.SUCCESS)
+}
+
+func (db *SQLiteDatabase) CountFailedProcesses() (int, error) {
+	return db.countProcesses(core.FAILED)
+}
+
+func (db *SQLiteDatabase) CountWaitingProcessesByColonyName(colonyName string) (int, error) {
+	return db.countProcessesByColonyName(core.WAITING, colonyName)
+}
+
+func (db *SQLiteDatabase) CountRunningProcessesByColonyName(colonyName string) (int, error) {
+	return db.countProcessesByColonyName(core.RUNNING, colonyName)
+}
+
+func (db *SQLiteDatabase) CountSuccessfulProcessesByColonyName(colonyName string) (int, error) {
+	return db.countProcessesByColonyName(core.SUCCESS, colonyName)
+}
+
+func (db *SQLiteDatabase) CountFailedProcessesByColonyName(colonyName string) (int, error) {
+	return db.countProcessesByColonyName(core.FAILED, colonyName)
+}
+
+func (db *SQLiteDatabase) CountWaitingProcessesByExecutorType() ([]database.QueueDepth, error) {
+	sqlStatement := `SELECT TARGET_COLONY_NAME, COALESCE(EXECUTOR_TYPE, ''), COUNT(*) FROM ` + db.dbPrefix + `PROCESSES WHERE STATE=$1 GROUP BY TARGET_COLONY_NAME, EXECUTOR_TYPE ORDER BY TARGET_COLONY_NAME, EXECUTOR_TYPE`
+	rows, err := db.sqlite.Query(sqlStatement, core.WAITING)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var queueDepths []database.QueueDepth
+	for rows.Next() {
+		var queueDepth database.QueueDepth
+		if err := rows.Scan(&queueDepth.ColonyName, &queueDepth.ExecutorType, &queueDepth.Count); err != nil {
+			return nil, err
+		}
+		queueDepths = append(queueDepths, queueDepth)
+	}
+
+	return queueDepths, nil
+}
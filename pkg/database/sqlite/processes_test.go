@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectCandiate(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	var processes []*core.Process
+
+	selectedProcess := db.selectCandidate(processes)
+	assert.Nil(t, selectedProcess)
+
+	process1 := utils.CreateTestProcess(core.GenerateRandomID())
+	process2 := utils.CreateTestProcess(core.GenerateRandomID())
+	processes = append(processes, process1)
+	processes = append(processes, process2)
+
+	selectedProcess = db.selectCandidate(processes)
+	assert.NotNil(t, selectedProcess)
+	assert.Equal(t, selectedProcess.ID, process1.ID)
+}
@@ -0,0 +1,19 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcTimestamp(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	period := 60 * 60 * 24
+	now, timestamp := db.calcTimestamp(int64(period))
+
+	assert.Equal(t, now.Unix()-timestamp.Unix(), int64(period))
+}
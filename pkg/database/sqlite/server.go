@@ -0,0 +1,32 @@
+package sqlite
+
+func (db *SQLiteDatabase) SetServerID(oldServerID, newServerID string) error {
+	if oldServerID == "" {
+		sqlStatement := `INSERT INTO  ` + db.dbPrefix + `SERVER (SERVER_ID) VALUES ($1)`
+		_, err := db.sqlite.Exec(sqlStatement, newServerID)
+		if err != nil {
+			return err
+		}
+	} else {
+		sqlStatement := `UPDATE ` + db.dbPrefix + `SERVER SET SERVER_ID = $1 WHERE SERVER_ID = $2`
+		_, err := db.sqlite.Exec(sqlStatement, newServerID, oldServerID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) GetServerID() (string, error) {
+	sqlStatement := `SELECT SERVER_ID FROM ` + db.dbPrefix + `SERVER`
+	row := db.sqlite.QueryRow(sqlStatement)
+	var serverID string
+
+	err := row.Scan(&serverID)
+	if err != nil {
+		return "", err
+	}
+
+	return serverID, nil
+}
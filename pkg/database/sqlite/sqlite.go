@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SQLite has no timestamp type, timestamps are therefore stored as UTC text with a fixed number of fractional
+// digits so that they can be compared and sorted as strings
+const timeLayout = "2006-01-02 15:04:05.000000000-07:00"
+
+// sqliteDB wraps sql.DB and converts query arguments to the representation used by the SQLite schema
+type sqliteDB struct {
+	*sql.DB
+}
+
+func convertArgs(args []any) []any {
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			converted[i] = v.UTC().Format(timeLayout)
+		default:
+			converted[i] = arg
+		}
+	}
+
+	return converted
+}
+
+func (db *sqliteDB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.DB.Exec(query, convertArgs(args)...)
+}
+
+func (db *sqliteDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return db.DB.ExecContext(ctx, query, convertArgs(args)...)
+}
+
+func (db *sqliteDB) Query(query string, args ...any) (*sql.Rows, error) {
+	return db.DB.Query(query, convertArgs(args)...)
+}
+
+func (db *sqliteDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return db.DB.QueryContext(ctx, query, convertArgs(args)...)
+}
+
+func (db *sqliteDB) QueryRow(query string, args ...any) *sql.Row {
+	return db.DB.QueryRow(query, convertArgs(args)...)
+}
+
+func (db *sqliteDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return db.DB.QueryRowContext(ctx, query, convertArgs(args)...)
+}
+
+// stringArray stores a string slice as a JSON array, it replaces pq.Array since SQLite lacks array columns
+type stringArray struct {
+	a *[]string
+}
+
+func array(a *[]string) *stringArray {
+	return &stringArray{a: a}
+}
+
+func (s *stringArray) Value() (driver.Value, error) {
+	if *s.a == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(*s.a)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (s *stringArray) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*s.a = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), s.a)
+	case []byte:
+		return json.Unmarshal(v, s.a)
+	default:
+		return fmt.Errorf("Cannot convert %T to string array", src)
+	}
+}
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringArray(t *testing.T) {
+	values := []string{"a", "b"}
+	value, err := array(&values).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, `["a","b"]`, value)
+
+	var nilValues []string
+	value, err = array(&nilValues).Value()
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+
+	var scanned []string
+	assert.Nil(t, array(&scanned).Scan(`["a","b"]`))
+	assert.Equal(t, values, scanned)
+
+	assert.Nil(t, array(&scanned).Scan(nil))
+	assert.Nil(t, scanned)
+
+	assert.Nil(t, array(&scanned).Scan([]byte(`[]`)))
+	assert.Len(t, scanned, 0)
+
+	assert.NotNil(t, array(&scanned).Scan(1))
+}
+
+func TestConvertArgsTimeOrder(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	assert.Nil(t, err)
+
+	// Timestamps in different time zones must still compare correctly as strings
+	t1 := time.Date(2024, 1, 1, 12, 0, 0, 500, time.UTC)
+	t2 := time.Date(2024, 1, 1, 12, 30, 0, 0, stockholm)
+	args := convertArgs([]any{t1, t2, "text"})
+
+	assert.Equal(t, "text", args[2])
+	assert.True(t, args[0].(string) > args[1].(string))
+}
+
+func TestTimeRoundTrip(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	_, err = db.sqlite.Exec(`CREATE TABLE ` + db.dbPrefix + `TIMES (ADDED TIMESTAMP)`)
+	assert.Nil(t, err)
+
+	now := time.Now()
+	_, err = db.sqlite.Exec(`INSERT INTO `+db.dbPrefix+`TIMES (ADDED) VALUES ($1)`, now)
+	assert.Nil(t, err)
+	_, err = db.sqlite.Exec(`INSERT INTO `+db.dbPrefix+`TIMES (ADDED) VALUES ($1)`, time.Time{})
+	assert.Nil(t, err)
+
+	rows, err := db.sqlite.Query(`SELECT ADDED FROM ` + db.dbPrefix + `TIMES ORDER BY ADDED DESC`)
+	assert.Nil(t, err)
+	defer rows.Close()
+
+	var times []time.Time
+	for rows.Next() {
+		var added time.Time
+		assert.Nil(t, rows.Scan(&added))
+		times = append(times, added)
+	}
+
+	assert.Len(t, times, 2)
+	assert.True(t, now.Equal(times[0]))
+	assert.True(t, times[1].IsZero())
+}
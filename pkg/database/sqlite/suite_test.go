@@ -0,0 +1,11 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/database/dbtest"
+)
+
+func TestDatabase(t *testing.T) {
+	dbtest.Run(t, func() (dbtest.Database, error) { return PrepareTests() })
+}
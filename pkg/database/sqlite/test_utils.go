@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"time"
+)
+
+func PrepareTests() (*SQLiteDatabase, error) {
+	return PrepareTestsWithPrefix("TEST_")
+}
+
+func PrepareTestsWithPrefix(prefix string) (*SQLiteDatabase, error) {
+	log.SetOutput(ioutil.Discard)
+
+	rand.Seed(time.Now().UTC().UnixNano())
+
+	db := CreateSQLiteDatabase(":memory:", prefix)
+
+	err := db.Connect()
+	if err != nil {
+		return nil, err
+	}
+
+	db.Drop()
+	err = db.Initialize()
+
+	return db, err
+}
@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+func (db *SQLiteDatabase) AddUser(user *core.User) error {
+	if user == nil {
+		return errors.New("User is nil")
+	}
+
+	existingUser, err := db.GetUserByName(user.ColonyName, user.Name)
+	if err != nil {
+		return err
+	}
+
+	if existingUser != nil {
+		return errors.New("User with name <" + user.Name + "> already exists in Colony with name <" + user.ColonyName + ">")
+	}
+
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `USERS (NAME, USER_ID, COLONY_NAME, EMAIL, PHONE) VALUES ($1, $2, $3, $4, $5)`
+	_, err = db.sqlite.Exec(sqlStatement, user.ColonyName+":"+user.Name, user.ID, user.ColonyName, user.Email, user.Phone)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) parseUsers(rows *sql.Rows) ([]*core.User, error) {
+	var users []*core.User
+
+	for rows.Next() {
+		var name string
+		var userID string
+		var colonyName string
+		var email string
+		var phone string
+		if err := rows.Scan(&name, &userID, &colonyName, &email, &phone); err != nil {
+			return nil, err
+		}
+
+		s := strings.Split(name, ":")
+		if len(s) != 2 {
+			return nil, errors.New("Failed to parse User name")
+		}
+		name = s[1]
+
+		user := core.CreateUser(colonyName, userID, name, email, phone)
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (db *SQLiteDatabase) GetUsersByColonyName(colonyName string) ([]*core.User, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `USERS WHERE COLONY_NAME=$1`
+	rows, err := db.sqlite.Query(sqlStatement, colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseUsers(rows)
+}
+
+func (db *SQLiteDatabase) GetUserByID(colonyName string, userID string) (*core.User, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `USERS WHERE COLONY_NAME=$1 AND USER_ID=$2`
+	rows, err := db.sqlite.Query(sqlStatement, colonyName, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	users, err := db.parseUsers(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	return users[0], nil
+}
+
+func (db *SQLiteDatabase) GetUserByName(colonyName string, name string) (*core.User, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `USERS WHERE NAME=$1 AND COLONY_NAME=$2`
+	rows, err := db.sqlite.Query(sqlStatement, colonyName+":"+name, colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	users, err := db.parseUsers(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	return users[0], nil
+}
+
+func (db *SQLiteDatabase) ChangeUserID(colonyName string, oldUserID, newUserID string) error {
+	sqlStatement := `UPDATE  ` + db.dbPrefix + `USERS SET USER_ID=$1 WHERE COLONY_NAME=$2 AND USER_ID=$3`
+	_, err := db.sqlite.Exec(sqlStatement, newUserID, colonyName, oldUserID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveUserByID(colonyName string, userID string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `USERS WHERE COLONY_NAME=$1 AND USER_ID=$2`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveUserByName(colonyName string, name string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `USERS WHERE NAME=$1 AND COLONY_NAME=$2`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName+":"+name, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *SQLiteDatabase) RemoveUsersByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `USERS WHERE COLONY_NAME=$1`
+	_, err := db.sqlite.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -9,7 +9,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/client"
 	"github.com/colonyos/colonies/pkg/core"
@@ -19,28 +21,40 @@ import (
 )
 
 type FSClient struct {
-	coloniesClient *client.ColoniesClient
-	colonyName     string
-	executorPrvKey string
-	s3Client       *S3Client
-	Quiet          bool
+	coloniesClient   *client.ColoniesClient
+	colonyName       string
+	executorPrvKey   string
+	s3Client         *S3Client
+	Quiet            bool
+	AsOf             time.Time // If set, sync and download revisions as they were at this point in time
+	MaxVersions      int       // If > 0, only keep this many revisions of each file when syncing
+	ConflictStrategy string    // One of core.ConflictStrategy*, if empty the keepLocal flag decides
 }
 
 type FileInfo struct {
-	Name       string
-	Checksum   string
-	Size       int64
-	S3Filename string
-	Dir        bool
+	Name          string
+	Checksum      string
+	Size          int64
+	S3Filename    string
+	Dir           bool
+	Resolution    string // How a conflict is resolved, core.ConflictStrategyKeepLocal, KeepRemote or KeepBoth
+	LocalChecksum string // Only set for keepboth conflicts, the local file is kept as ConflictName
+	LocalSize     int64
+	ConflictName  string
+	Remove        bool // Only set for conflicts where the file was removed on the side that is kept, it is then removed on the other side
 }
 
 type SyncPlan struct {
 	Dir           string
 	LocalMissing  []*FileInfo
 	RemoteMissing []*FileInfo
+	LocalRemove   []*FileInfo // Removed remotely, and unchanged locally, since last sync
+	RemoteRemove  []*FileInfo // Removed locally, and unchanged remotely, since last sync
 	Conflicts     []*FileInfo
 	KeepLocal     bool
+	Strategy      string
 	Label         string
+	syncState     map[string]string // Checksums of all files once the plan has been applied
 }
 
 type CleanPlan struct {
@@ -50,7 +64,60 @@ type CleanPlan struct {
 }
 
 type CFSFile struct {
-	Label string `json:"label"`
+	Label       string `json:"label"`
+	MaxVersions int    `json:"maxversions,omitempty"`
+}
+
+const cfsFilename = ".cfs"
+const syncStateFilename = ".cfsstate"
+
+// SyncState is stored in every synced directory and contains the checksum each file had the last
+// time it was synced. It is used as common ancestor to tell one-sided changes from true conflicts.
+type SyncState struct {
+	Label     string            `json:"label"`
+	Checksums map[string]string `json:"checksums"`
+}
+
+func isMetadataFile(filename string) bool {
+	return filename == cfsFilename || filename == syncStateFilename
+}
+
+func loadSyncState(dir string, label string) map[string]string {
+	checksums := make(map[string]string)
+
+	stateBytes, err := os.ReadFile(dir + "/" + syncStateFilename)
+	if err != nil {
+		return checksums
+	}
+
+	var syncState SyncState
+	err = json.Unmarshal(stateBytes, &syncState)
+	if err != nil {
+		log.WithFields(log.Fields{"Dir": dir, "Error": err}).Warn("Ignoring corrupt sync state file")
+		return checksums
+	}
+
+	// The directory has been synced with another label, the state tells nothing about this label
+	if syncState.Label != label || syncState.Checksums == nil {
+		return checksums
+	}
+
+	return syncState.Checksums
+}
+
+func saveSyncState(dir string, label string, checksums map[string]string) error {
+	stateBytes, err := json.Marshal(SyncState{Label: label, Checksums: checksums})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(dir+"/"+syncStateFilename, stateBytes, 0644)
+}
+
+func conflictFilename(filename string, now time.Time) string {
+	ext := filepath.Ext(filename)
+	stem := strings.TrimSuffix(filename, ext)
+	return stem + ".conflict-" + now.Format("20060102150405") + ext
 }
 
 func CreateFSClient(coloniesClient *client.ColoniesClient, colonyName string, executorPrvKey string) (*FSClient, error) {
@@ -126,8 +193,70 @@ func (fsClient *FSClient) uploadFile(syncPlan *SyncPlan, fileInfo *FileInfo, tra
 	return nil
 }
 
+func (fsClient *FSClient) downloadFile(syncPlan *SyncPlan, fileInfo *FileInfo, tracker *progress.Tracker, quite bool) error {
+	if fileInfo.Size > 0 {
+		return fsClient.s3Client.Download(fileInfo.Name, fileInfo.S3Filename, syncPlan.Dir, tracker, quite)
+	}
+
+	file, err := os.Create(syncPlan.Dir + "/" + fileInfo.Name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return nil
+}
+
+func (fsClient *FSClient) removeLocalFile(syncPlan *SyncPlan, fileInfo *FileInfo) error {
+	err := os.Remove(syncPlan.Dir + "/" + fileInfo.Name)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (fsClient *FSClient) removeRemoteFile(syncPlan *SyncPlan, fileInfo *FileInfo) error {
+	return fsClient.RemoveFileByName(fsClient.colonyName, syncPlan.Label, fileInfo.Name)
+}
+
+func (fsClient *FSClient) resolveConflict(syncPlan *SyncPlan, fileInfo *FileInfo, tracker *progress.Tracker, quite bool) error {
+	resolution := fileInfo.Resolution
+	if resolution == "" {
+		resolution = core.ResolveConflictStrategy("", syncPlan.KeepLocal)
+	}
+
+	switch resolution {
+	case core.ConflictStrategyKeepLocal:
+		if fileInfo.Remove {
+			return fsClient.removeRemoteFile(syncPlan, fileInfo)
+		}
+		return fsClient.uploadFile(syncPlan, fileInfo, tracker, quite)
+	case core.ConflictStrategyKeepRemote:
+		if fileInfo.Remove {
+			return fsClient.removeLocalFile(syncPlan, fileInfo)
+		}
+		return fsClient.downloadFile(syncPlan, fileInfo, tracker, quite)
+	case core.ConflictStrategyKeepBoth:
+		err := os.Rename(syncPlan.Dir+"/"+fileInfo.Name, syncPlan.Dir+"/"+fileInfo.ConflictName)
+		if err != nil {
+			return err
+		}
+
+		localCopy := &FileInfo{Name: fileInfo.ConflictName, Checksum: fileInfo.LocalChecksum, Size: fileInfo.LocalSize}
+		err = fsClient.uploadFile(syncPlan, localCopy, tracker, quite)
+		if err != nil {
+			return err
+		}
+
+		return fsClient.downloadFile(syncPlan, fileInfo, tracker, quite)
+	}
+
+	return errors.New("Invalid conflict resolution <" + resolution + "> for file " + fileInfo.Name)
+}
+
 func (fsClient *FSClient) ApplySyncPlan(syncPlan *SyncPlan) error {
-	totalCalls := len(syncPlan.RemoteMissing) + len(syncPlan.LocalMissing) + len(syncPlan.Conflicts)
+	totalCalls := len(syncPlan.RemoteMissing) + len(syncPlan.LocalMissing) + len(syncPlan.LocalRemove) + len(syncPlan.RemoteRemove) + len(syncPlan.Conflicts)
 	if totalCalls == 0 {
 		return nil
 	}
@@ -142,12 +271,12 @@ func (fsClient *FSClient) ApplySyncPlan(syncPlan *SyncPlan) error {
 	}
 
 	// Create a .cfs file based on the CFSFile struct
-	cfsFile := CFSFile{Label: syncPlan.Label}
+	cfsFile := CFSFile{Label: syncPlan.Label, MaxVersions: fsClient.MaxVersions}
 	cfsFileBytes, err := json.Marshal(cfsFile)
 	if err != nil {
 		return err
 	}
-	err = os.WriteFile(syncPlan.Dir+"/.cfs", cfsFileBytes, 0644)
+	err = os.WriteFile(syncPlan.Dir+"/"+cfsFilename, cfsFileBytes, 0644)
 	if err != nil {
 		return err
 	}
@@ -198,12 +327,8 @@ func (fsClient *FSClient) ApplySyncPlan(syncPlan *SyncPlan) error {
 			downloadTracker.Start()
 		}
 
-		var messageConflictTracker string
-		if syncPlan.KeepLocal {
-			messageConflictTracker = fmt.Sprintf("Conflict (keeplocal) %s", syncPlan.Label)
-		} else {
-			messageConflictTracker = fmt.Sprintf("Conflict (keepremote) %s", syncPlan.Label)
-		}
+		strategy := core.ResolveConflictStrategy(syncPlan.Strategy, syncPlan.KeepLocal)
+		messageConflictTracker := fmt.Sprintf("Conflict (%s) %s", strategy, syncPlan.Label)
 		conflictTracker = progress.Tracker{Message: messageConflictTracker, Total: totalConflictSize, Units: progress.UnitsBytes}
 		if len(syncPlan.Conflicts) > 0 && totalConflictSize > 0 {
 			pw.AppendTracker(&conflictTracker)
@@ -228,16 +353,7 @@ func (fsClient *FSClient) ApplySyncPlan(syncPlan *SyncPlan) error {
 	for _, fileInfo := range syncPlan.LocalMissing {
 		errChan := pool.Call(func(arg interface{}) error {
 			f := arg.(*FileInfo)
-			if f.Size > 0 {
-				return fsClient.s3Client.Download(f.Name, f.S3Filename, syncPlan.Dir, &downloadTracker, fsClient.Quiet)
-			} else {
-				file, err := os.Create(syncPlan.Dir + "/" + f.Name)
-				if err != nil {
-					return err
-				}
-				defer file.Close()
-				return nil
-			}
+			return fsClient.downloadFile(syncPlan, f, &downloadTracker, fsClient.Quiet)
 		}, fileInfo)
 		go func() {
 			err := <-errChan
@@ -245,30 +361,39 @@ func (fsClient *FSClient) ApplySyncPlan(syncPlan *SyncPlan) error {
 		}()
 	}
 
-	// 3. Handle conflicts
-	// If keepLocalFiles then upload conflicting files to server else download conflicting files to local filesystem
-	if syncPlan.KeepLocal {
-		for _, fileInfo := range syncPlan.Conflicts {
-			errChan := pool.Call(func(arg interface{}) error {
-				f := arg.(*FileInfo)
-				return fsClient.uploadFile(syncPlan, f, &conflictTracker, fsClient.Quiet)
-			}, fileInfo)
-			go func() {
-				err := <-errChan
-				aggErrChan <- err
-			}()
-		}
-	} else {
-		for _, fileInfo := range syncPlan.Conflicts {
-			errChan := pool.Call(func(arg interface{}) error {
-				f := arg.(*FileInfo)
-				return fsClient.s3Client.Download(f.Name, f.S3Filename, syncPlan.Dir, &conflictTracker, fsClient.Quiet)
-			}, fileInfo)
-			go func() {
-				err := <-errChan
-				aggErrChan <- err
-			}()
-		}
+	// 3. Remove files that have been removed on the other side since last sync
+	for _, fileInfo := range syncPlan.LocalRemove {
+		errChan := pool.Call(func(arg interface{}) error {
+			return fsClient.removeLocalFile(syncPlan, arg.(*FileInfo))
+		}, fileInfo)
+		go func() {
+			err := <-errChan
+			aggErrChan <- err
+		}()
+	}
+
+	for _, fileInfo := range syncPlan.RemoteRemove {
+		errChan := pool.Call(func(arg interface{}) error {
+			return fsClient.removeRemoteFile(syncPlan, arg.(*FileInfo))
+		}, fileInfo)
+		go func() {
+			err := <-errChan
+			aggErrChan <- err
+		}()
+	}
+
+	// 4. Handle conflicts
+	// Each conflict is either uploaded (keeplocal), downloaded (keepremote), or the local file is renamed
+	// and uploaded before the remote file is downloaded (keepboth)
+	for _, fileInfo := range syncPlan.Conflicts {
+		errChan := pool.Call(func(arg interface{}) error {
+			f := arg.(*FileInfo)
+			return fsClient.resolveConflict(syncPlan, f, &conflictTracker, fsClient.Quiet)
+		}, fileInfo)
+		go func() {
+			err := <-errChan
+			aggErrChan <- err
+		}()
 	}
 
 	expectedErrs := totalCalls
@@ -302,6 +427,20 @@ O:
 		pw.Stop()
 	}
 
+	if syncPlan.syncState != nil {
+		err := saveSyncState(syncPlan.Dir, syncPlan.Label, syncPlan.syncState)
+		if err != nil {
+			return err
+		}
+	}
+
+	if fsClient.MaxVersions > 0 && (len(syncPlan.RemoteMissing) > 0 || len(syncPlan.Conflicts) > 0) {
+		_, err := fsClient.PruneRevisions(syncPlan.Label, fsClient.MaxVersions)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -404,24 +543,46 @@ func (fsClient *FSClient) CalcSyncPlan(dir string, label string, keepLocal bool)
 	}
 
 	log.WithFields(log.Fields{"Label": label, "Dir:": dir}).Debug("Getting remoteFilenames")
-	remoteFileDataArr, err := fsClient.coloniesClient.GetFileData(fsClient.colonyName, label, fsClient.executorPrvKey)
-	if err != nil {
-		return nil, err
+	var remoteFileDataArr []*core.FileData
+	if fsClient.AsOf.IsZero() {
+		remoteFileDataArr, err = fsClient.coloniesClient.GetFileData(fsClient.colonyName, label, fsClient.executorPrvKey)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		remoteFileDataArr, err = fsClient.coloniesClient.GetFileDataAsOf(fsClient.colonyName, label, fsClient.AsOf, fsClient.executorPrvKey)
+		if err != nil {
+			return nil, err
+		}
+
+		// Restoring a past state must never modify the server, so local changes are always replaced
+		keepLocal = false
 	}
 	log.WithFields(log.Fields{"Label": label, "Dir:": dir, "RemoteFileData": len(remoteFileDataArr)}).Debug("Done getting remoteFileData")
 
+	strategy := core.ResolveConflictStrategy(fsClient.ConflictStrategy, keepLocal)
+	if !fsClient.AsOf.IsZero() {
+		strategy = core.ConflictStrategyKeepRemote
+	}
+	if !core.IsValidConflictStrategy(strategy) {
+		return nil, errors.New("Invalid conflict strategy <" + strategy + ">")
+	}
+
 	var remoteFileMap = make(map[string]string)
 	var remoteS3FilenameMap = make(map[string]string)
 	var remoteFileSizeMap = make(map[string]int64)
+	var remoteAddedMap = make(map[string]time.Time)
 
 	for _, remoteFileData := range remoteFileDataArr {
 		remoteFileMap[remoteFileData.Name] = remoteFileData.Checksum
 		remoteFileSizeMap[remoteFileData.Name] = remoteFileData.Size
 		remoteS3FilenameMap[remoteFileData.Name] = remoteFileData.S3Filename
+		remoteAddedMap[remoteFileData.Name] = remoteFileData.Added
 	}
 
 	var localFileMap = make(map[string]string)
 	var localFileSizeMap = make(map[string]int64)
+	var localModTimeMap = make(map[string]time.Time)
 	for _, file := range files {
 		// Strange, file.IsDir() says that a file is a not a directory when it is
 		// The workaround seems to obtain a new fileinfo struct
@@ -429,7 +590,7 @@ func (fsClient *FSClient) CalcSyncPlan(dir string, label string, keepLocal bool)
 		if err != nil {
 			return nil, err
 		}
-		if !fileInfo.IsDir() && file.Name() != ".cfs" { // Ignore .cfs file
+		if !fileInfo.IsDir() && !isMetadataFile(file.Name()) { // Ignore .cfs and .cfsstate files
 			checksum, err := checksum(dir + "/" + file.Name())
 			if err != nil {
 				return nil, err
@@ -441,6 +602,7 @@ func (fsClient *FSClient) CalcSyncPlan(dir string, label string, keepLocal bool)
 				return nil, err
 			}
 			localFileSizeMap[file.Name()] = fi.Size()
+			localModTimeMap[file.Name()] = fi.ModTime()
 		}
 
 		if !fsClient.Quiet {
@@ -448,47 +610,155 @@ func (fsClient *FSClient) CalcSyncPlan(dir string, label string, keepLocal bool)
 		}
 	}
 
+	// The checksums from the last sync is the common ancestor, if only one side has changed since then
+	// it is not a conflict and the change is simply propagated to the other side
+	lastSynced := loadSyncState(dir, label)
+	syncState := make(map[string]string)
+	for filename, checksum := range localFileMap {
+		syncState[filename] = checksum
+	}
+	for filename, checksum := range remoteFileMap {
+		syncState[filename] = checksum
+	}
+
+	now := time.Now()
+	var conflicts []*FileInfo
+	var failedConflicts []string
+
 	// Find out which files are missing at the server
 	var remoteMissing []*FileInfo
+	var localRemove []*FileInfo
 	for filename, checksum := range localFileMap {
 		_, ok := remoteFileMap[filename]
-		if !ok {
-			// File missing on server
-			size := localFileSizeMap[filename]
-			remoteMissing = append(remoteMissing, &FileInfo{Name: filename, Checksum: checksum, Size: size, S3Filename: ""})
+		if !ok && fsClient.AsOf.IsZero() {
+			localInfo := &FileInfo{Name: filename, Checksum: checksum, Size: localFileSizeMap[filename], S3Filename: ""}
+
+			lastSyncedChecksum, synced := lastSynced[filename]
+			if !synced {
+				// File missing on server
+				remoteMissing = append(remoteMissing, localInfo)
+				continue
+			}
+
+			// File removed on server since last sync
+			if lastSyncedChecksum == checksum { // Unchanged locally
+				localRemove = append(localRemove, localInfo)
+				delete(syncState, filename)
+				continue
+			}
+
+			// Changed locally, the change is kept unless the remote removal wins the conflict. Keeping both is
+			// the same as keeping the local file, and the removal time is unknown so the local change is newest.
+			switch strategy {
+			case core.ConflictStrategyKeepRemote:
+				localInfo.Resolution = strategy
+				localInfo.Remove = true
+				conflicts = append(conflicts, localInfo)
+				delete(syncState, filename)
+			case core.ConflictStrategyFail:
+				failedConflicts = append(failedConflicts, filename)
+			default:
+				localInfo.Resolution = core.ConflictStrategyKeepLocal
+				conflicts = append(conflicts, localInfo)
+			}
 		}
 	}
 
 	// Find out which files are missing locally
 	var localMissing []*FileInfo
+	var remoteRemove []*FileInfo
 	for filename, checksum := range remoteFileMap {
 		_, ok := localFileMap[filename]
 		if !ok {
-			// File missing locally
-			size := remoteFileSizeMap[filename]
-			s3Filename := remoteS3FilenameMap[filename]
-			localMissing = append(localMissing, &FileInfo{Name: filename, Checksum: checksum, Size: size, S3Filename: s3Filename})
+			remoteInfo := &FileInfo{Name: filename, Checksum: checksum, Size: remoteFileSizeMap[filename], S3Filename: remoteS3FilenameMap[filename]}
+
+			lastSyncedChecksum, synced := lastSynced[filename]
+			if !synced || !fsClient.AsOf.IsZero() {
+				// File missing locally
+				localMissing = append(localMissing, remoteInfo)
+				continue
+			}
+
+			// File removed locally since last sync
+			if lastSyncedChecksum == checksum { // Unchanged remotely
+				remoteRemove = append(remoteRemove, remoteInfo)
+				delete(syncState, filename)
+				continue
+			}
+
+			// Changed remotely, the change is kept unless the local removal wins the conflict. Keeping both is
+			// the same as keeping the remote file, and the removal time is unknown so the remote change is newest.
+			switch strategy {
+			case core.ConflictStrategyKeepLocal:
+				remoteInfo.Resolution = strategy
+				remoteInfo.Remove = true
+				conflicts = append(conflicts, remoteInfo)
+				delete(syncState, filename)
+			case core.ConflictStrategyFail:
+				failedConflicts = append(failedConflicts, filename)
+			default:
+				remoteInfo.Resolution = core.ConflictStrategyKeepRemote
+				conflicts = append(conflicts, remoteInfo)
+			}
 		}
 	}
 
 	// Calculate conflicts
-	var conflicts []*FileInfo
 	for filename, checksum := range remoteFileMap {
 		// File exists locally, but does not match file on server
-		_, ok := localFileMap[filename]
-		if ok {
-			if localFileMap[filename] != checksum {
-				if keepLocal {
-					localChecksum := localFileMap[filename]
-					size := localFileSizeMap[filename]
-					conflicts = append(conflicts, &FileInfo{Name: filename, Checksum: localChecksum, Size: size, S3Filename: ""})
-				} else {
-					size := remoteFileSizeMap[filename]
-					s3Filename := remoteS3FilenameMap[filename]
-					conflicts = append(conflicts, &FileInfo{Name: filename, Checksum: checksum, Size: size, S3Filename: s3Filename})
-				}
+		localChecksum, ok := localFileMap[filename]
+		if !ok || localChecksum == checksum {
+			continue
+		}
+
+		localInfo := &FileInfo{Name: filename, Checksum: localChecksum, Size: localFileSizeMap[filename], S3Filename: ""}
+		remoteInfo := &FileInfo{Name: filename, Checksum: checksum, Size: remoteFileSizeMap[filename], S3Filename: remoteS3FilenameMap[filename]}
+
+		lastSyncedChecksum, synced := lastSynced[filename]
+		if synced && fsClient.AsOf.IsZero() {
+			if lastSyncedChecksum == checksum { // Only changed locally
+				remoteMissing = append(remoteMissing, localInfo)
+				syncState[filename] = localChecksum
+				continue
+			}
+			if lastSyncedChecksum == localChecksum { // Only changed remotely
+				localMissing = append(localMissing, remoteInfo)
+				continue
 			}
 		}
+
+		resolution := strategy
+		if resolution == core.ConflictStrategyNewestWins {
+			if localModTimeMap[filename].After(remoteAddedMap[filename]) {
+				resolution = core.ConflictStrategyKeepLocal
+			} else {
+				resolution = core.ConflictStrategyKeepRemote
+			}
+		}
+
+		switch resolution {
+		case core.ConflictStrategyKeepLocal:
+			localInfo.Resolution = resolution
+			conflicts = append(conflicts, localInfo)
+			syncState[filename] = localChecksum
+		case core.ConflictStrategyKeepRemote:
+			remoteInfo.Resolution = resolution
+			conflicts = append(conflicts, remoteInfo)
+		case core.ConflictStrategyKeepBoth:
+			remoteInfo.Resolution = resolution
+			remoteInfo.LocalChecksum = localChecksum
+			remoteInfo.LocalSize = localFileSizeMap[filename]
+			remoteInfo.ConflictName = conflictFilename(filename, now)
+			conflicts = append(conflicts, remoteInfo)
+			syncState[remoteInfo.ConflictName] = localChecksum
+		case core.ConflictStrategyFail:
+			failedConflicts = append(failedConflicts, filename)
+		}
+	}
+
+	// Restoring a past state says nothing about what has been synced, so keep the previous state
+	if !fsClient.AsOf.IsZero() {
+		syncState = nil
 	}
 
 	if !fsClient.Quiet {
@@ -503,13 +773,22 @@ func (fsClient *FSClient) CalcSyncPlan(dir string, label string, keepLocal bool)
 		}
 	}
 
+	if len(failedConflicts) > 0 {
+		sort.Strings(failedConflicts)
+		return nil, errors.New("Conflicting changes to " + strings.Join(failedConflicts, ", ") + " in " + label + ", files have changed both locally and remotely since last sync")
+	}
+
 	return &SyncPlan{
 		LocalMissing:  localMissing,
 		RemoteMissing: remoteMissing,
+		LocalRemove:   localRemove,
+		RemoteRemove:  remoteRemove,
 		Conflicts:     conflicts,
 		Dir:           dir,
 		Label:         label,
-		KeepLocal:     keepLocal}, nil
+		KeepLocal:     keepLocal,
+		Strategy:      strategy,
+		syncState:     syncState}, nil
 }
 
 func (fsClient *FSClient) CalcCleanPlan(dir string, label string) (*CleanPlan, error) {
@@ -563,7 +842,7 @@ func (fsClient *FSClient) CalcCleanPlan(dir string, label string) (*CleanPlan, e
 				filesToRemove = append(filesToRemove, &FileInfo{Name: dir + "/" + localFile.Name(), Dir: true})
 			}
 		} else {
-			if localFile.Name() != ".cfs" {
+			if !isMetadataFile(localFile.Name()) {
 				_, ok := remoteFiles[localFile.Name()]
 				if !ok {
 					filesToRemove = append(filesToRemove, &FileInfo{Name: dir + "/" + localFile.Name(), Dir: false})
@@ -935,3 +1214,75 @@ func (fsClient *FSClient) DownloadSnapshot(snapshotID string, downloadDir string
 
 	return nil
 }
+
+func (fsClient *FSClient) GetRevisions(label string, name string) ([]*core.File, error) {
+	return fsClient.coloniesClient.GetFileByName(fsClient.colonyName, label, name, fsClient.executorPrvKey)
+}
+
+func (fsClient *FSClient) PruneRevisions(label string, maxVersions int) ([]*core.File, error) {
+	if maxVersions < 1 {
+		return nil, errors.New("maxVersions must be at least 1")
+	}
+
+	if !strings.HasPrefix(label, "/") {
+		label = "/" + label
+	}
+
+	// Revisions referenced by a snapshot must be kept, otherwise the snapshot can no longer be downloaded
+	snapshots, err := fsClient.coloniesClient.GetSnapshotsByColonyName(fsClient.colonyName, fsClient.executorPrvKey)
+	if err != nil {
+		return nil, err
+	}
+	snapshotFileIDs := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		for _, fileID := range snapshot.FileIDs {
+			snapshotFileIDs[fileID] = true
+		}
+	}
+
+	fileDataArr, err := fsClient.coloniesClient.GetFileData(fsClient.colonyName, label, fsClient.executorPrvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []*core.File
+	for _, fileData := range fileDataArr {
+		revisions, err := fsClient.GetRevisions(label, fileData.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		// Revisions are sorted by sequence number, newest first
+		sort.Slice(revisions, func(i, j int) bool {
+			return revisions[i].SequenceNumber > revisions[j].SequenceNumber
+		})
+
+		if len(revisions) <= maxVersions {
+			continue
+		}
+
+		for _, revision := range revisions[maxVersions:] {
+			if snapshotFileIDs[revision.ID] {
+				log.WithFields(log.Fields{"Label": label, "Name": revision.Name, "FileID": revision.ID}).Debug("Keeping revision, part of a snapshot")
+				continue
+			}
+
+			if revision.Size > 0 {
+				err = fsClient.s3Client.Remove(revision.Reference.S3Object.Object)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			err = fsClient.coloniesClient.RemoveFileByID(fsClient.colonyName, revision.ID, fsClient.executorPrvKey)
+			if err != nil {
+				return nil, err
+			}
+
+			log.WithFields(log.Fields{"Label": label, "Name": revision.Name, "FileID": revision.ID, "SequenceNumber": revision.SequenceNumber}).Debug("Pruned file revision")
+			pruned = append(pruned, revision)
+		}
+	}
+
+	return pruned, nil
+}
@@ -7,8 +7,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -428,6 +430,10 @@ func TestApplySyncPlan4(t *testing.T) {
 	replacedChecksum, err := checksum(tmpFile1.Name())
 	assert.Nil(t, err)
 
+	// Forget the last sync, without a common ancestor every difference is a conflict
+	err = os.Remove(syncDir + "/" + syncStateFilename)
+	assert.Nil(t, err)
+
 	// Make another sync
 	keepLocal := true
 	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, keepLocal)
@@ -511,6 +517,10 @@ func TestApplySyncPlan5(t *testing.T) {
 	replacedChecksum, err := checksum(tmpFile1.Name())
 	assert.Nil(t, err)
 
+	// Forget the last sync, without a common ancestor every difference is a conflict
+	err = os.Remove(syncDir + "/" + syncStateFilename)
+	assert.Nil(t, err)
+
 	// Make another sync
 	keepLocal := false // keep remote
 	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, keepLocal)
@@ -1366,3 +1376,543 @@ func TestCleanFilesRecursively(t *testing.T) {
 	coloniesServer.Shutdown()
 	<-done
 }
+
+func writeTestFile(t *testing.T, f *os.File, data string) {
+	err := f.Truncate(0)
+	assert.Nil(t, err)
+	_, err = f.Seek(0, 0)
+	assert.Nil(t, err)
+	_, err = f.Write([]byte(data))
+	assert.Nil(t, err)
+}
+
+func TestPruneRevisions(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	label := "/test_label"
+
+	syncDir, err := os.MkdirTemp("/tmp/", "sync")
+	assert.Nil(t, err)
+	tmpFile1, err := os.CreateTemp(syncDir, "test")
+	assert.Nil(t, err)
+	tmpFile1Filename := filepath.Base(tmpFile1.Name())
+
+	fsClient, err := CreateFSClient(coloniesClient, env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+
+	// Create three revisions of the same file
+	for i := 0; i < 3; i++ {
+		writeTestFile(t, tmpFile1, fmt.Sprintf("testdata%d", i))
+		syncPlan, err := fsClient.CalcSyncPlan(syncDir, label, true)
+		assert.Nil(t, err)
+		err = fsClient.ApplySyncPlan(syncPlan)
+		assert.Nil(t, err)
+	}
+
+	revisions, err := fsClient.GetRevisions(label, tmpFile1Filename)
+	assert.Nil(t, err)
+	assert.Len(t, revisions, 3)
+
+	pruned, err := fsClient.PruneRevisions(label, 1)
+	assert.Nil(t, err)
+	assert.Len(t, pruned, 2)
+
+	revisions, err = fsClient.GetRevisions(label, tmpFile1Filename)
+	assert.Nil(t, err)
+	assert.Len(t, revisions, 1)
+	latestChecksum, err := checksum(tmpFile1.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, revisions[0].Checksum, latestChecksum)
+
+	_, err = fsClient.PruneRevisions(label, 0)
+	assert.NotNil(t, err)
+
+	// Clean up
+	tmpFile1.Close()
+	err = os.RemoveAll(syncDir)
+	assert.Nil(t, err)
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func TestPruneRevisionsKeepSnapshot(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	label := "/test_label"
+
+	syncDir, err := os.MkdirTemp("/tmp/", "sync")
+	assert.Nil(t, err)
+	tmpFile1, err := os.CreateTemp(syncDir, "test")
+	assert.Nil(t, err)
+	tmpFile1Filename := filepath.Base(tmpFile1.Name())
+
+	fsClient, err := CreateFSClient(coloniesClient, env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+
+	writeTestFile(t, tmpFile1, "testdata1")
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	_, err = coloniesClient.CreateSnapshot(env.colonyName, label, "test_snapshot", env.executorPrvKey)
+	assert.Nil(t, err)
+
+	writeTestFile(t, tmpFile1, "testdata2")
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	// The first revision is part of a snapshot and must not be removed
+	pruned, err := fsClient.PruneRevisions(label, 1)
+	assert.Nil(t, err)
+	assert.Len(t, pruned, 0)
+
+	revisions, err := fsClient.GetRevisions(label, tmpFile1Filename)
+	assert.Nil(t, err)
+	assert.Len(t, revisions, 2)
+
+	// Clean up
+	tmpFile1.Close()
+	err = os.RemoveAll(syncDir)
+	assert.Nil(t, err)
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func TestSyncAsOf(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	label := "/test_label"
+
+	syncDir, err := os.MkdirTemp("/tmp/", "sync")
+	assert.Nil(t, err)
+	tmpFile1, err := os.CreateTemp(syncDir, "test")
+	assert.Nil(t, err)
+
+	fsClient, err := CreateFSClient(coloniesClient, env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+
+	writeTestFile(t, tmpFile1, "testdata1")
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+	checksum1, err := checksum(tmpFile1.Name())
+	assert.Nil(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	asOf := time.Now()
+	time.Sleep(100 * time.Millisecond)
+
+	writeTestFile(t, tmpFile1, "testdata2")
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	// A file added after asOf must not be uploaded when restoring
+	tmpFile2, err := os.CreateTemp(syncDir, "test")
+	assert.Nil(t, err)
+	writeTestFile(t, tmpFile2, "testdata3")
+
+	fsClient.AsOf = asOf
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.RemoteMissing, 0)
+	assert.Len(t, syncPlan.Conflicts, 1)
+	assert.False(t, syncPlan.KeepLocal)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	restoredChecksum, err := checksum(tmpFile1.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, restoredChecksum, checksum1)
+
+	// Clean up
+	tmpFile1.Close()
+	tmpFile2.Close()
+	err = os.RemoveAll(syncDir)
+	assert.Nil(t, err)
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func setupConflictTest(t *testing.T, fsClient *FSClient, label string) (string, string, *os.File) {
+	// syncDir and syncDir2 are both synced with the same label
+	syncDir, err := os.MkdirTemp("/tmp/", "sync")
+	assert.Nil(t, err)
+	tmpFile1, err := os.CreateTemp(syncDir, "test")
+	assert.Nil(t, err)
+	writeTestFile(t, tmpFile1, "base")
+
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	syncDir2, err := os.MkdirTemp("/tmp/", "sync")
+	assert.Nil(t, err)
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir2, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.LocalMissing, 1)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	return syncDir, syncDir2, tmpFile1
+}
+
+func TestSyncLocalChange(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	label := "/test_label"
+
+	fsClient, err := CreateFSClient(coloniesClient, env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+	fsClient.ConflictStrategy = core.ConflictStrategyFail
+
+	syncDir, syncDir2, tmpFile1 := setupConflictTest(t, fsClient, label)
+	tmpFile1Filename := filepath.Base(tmpFile1.Name())
+
+	// Only changed locally, should be uploaded even if the remote side would win a conflict
+	writeTestFile(t, tmpFile1, "local")
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir, label, false)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.RemoteMissing, 1)
+	assert.Len(t, syncPlan.Conflicts, 0)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	// Only changed remotely from the perspective of syncDir2, should be downloaded
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir2, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.LocalMissing, 1)
+	assert.Len(t, syncPlan.RemoteMissing, 0)
+	assert.Len(t, syncPlan.Conflicts, 0)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	fileContent, err := os.ReadFile(syncDir2 + "/" + tmpFile1Filename)
+	assert.Nil(t, err)
+	assert.Equal(t, "local", string(fileContent))
+
+	// Everything is now in sync
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.LocalMissing, 0)
+	assert.Len(t, syncPlan.RemoteMissing, 0)
+	assert.Len(t, syncPlan.Conflicts, 0)
+
+	// Clean up
+	tmpFile1.Close()
+	err = os.RemoveAll(syncDir)
+	assert.Nil(t, err)
+	err = os.RemoveAll(syncDir2)
+	assert.Nil(t, err)
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func TestSyncConflictFail(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	label := "/test_label"
+
+	fsClient, err := CreateFSClient(coloniesClient, env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+
+	syncDir, syncDir2, tmpFile1 := setupConflictTest(t, fsClient, label)
+	tmpFile1Filename := filepath.Base(tmpFile1.Name())
+
+	writeTestFile(t, tmpFile1, "local")
+	err = os.WriteFile(syncDir2+"/"+tmpFile1Filename, []byte("remote"), 0644)
+	assert.Nil(t, err)
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir2, label, true)
+	assert.Nil(t, err)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	fsClient.ConflictStrategy = core.ConflictStrategyFail
+	_, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.NotNil(t, err)
+
+	// Nothing should have been touched
+	fileContent, err := os.ReadFile(tmpFile1.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, "local", string(fileContent))
+
+	fsClient.ConflictStrategy = "invalid"
+	_, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.NotNil(t, err)
+
+	// Clean up
+	tmpFile1.Close()
+	err = os.RemoveAll(syncDir)
+	assert.Nil(t, err)
+	err = os.RemoveAll(syncDir2)
+	assert.Nil(t, err)
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func TestSyncConflictKeepBoth(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	label := "/test_label"
+
+	fsClient, err := CreateFSClient(coloniesClient, env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+
+	syncDir, syncDir2, tmpFile1 := setupConflictTest(t, fsClient, label)
+	tmpFile1Filename := filepath.Base(tmpFile1.Name())
+
+	writeTestFile(t, tmpFile1, "local")
+	err = os.WriteFile(syncDir2+"/"+tmpFile1Filename, []byte("remote"), 0644)
+	assert.Nil(t, err)
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir2, label, true)
+	assert.Nil(t, err)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	fsClient.ConflictStrategy = core.ConflictStrategyKeepBoth
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.Conflicts, 1)
+	conflictName := syncPlan.Conflicts[0].ConflictName
+	assert.True(t, strings.HasPrefix(conflictName, tmpFile1Filename+".conflict-"))
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	fileContent, err := os.ReadFile(syncDir + "/" + tmpFile1Filename)
+	assert.Nil(t, err)
+	assert.Equal(t, "remote", string(fileContent))
+	fileContent, err = os.ReadFile(syncDir + "/" + conflictName)
+	assert.Nil(t, err)
+	assert.Equal(t, "local", string(fileContent))
+
+	// The renamed local copy has been uploaded as well
+	fileData, err := coloniesClient.GetFileData(env.colonyName, label, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, fileData, 2)
+
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.LocalMissing, 0)
+	assert.Len(t, syncPlan.RemoteMissing, 0)
+	assert.Len(t, syncPlan.Conflicts, 0)
+
+	// Clean up
+	tmpFile1.Close()
+	err = os.RemoveAll(syncDir)
+	assert.Nil(t, err)
+	err = os.RemoveAll(syncDir2)
+	assert.Nil(t, err)
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func TestSyncConflictNewestWins(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	label := "/test_label"
+
+	fsClient, err := CreateFSClient(coloniesClient, env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+
+	syncDir, syncDir2, tmpFile1 := setupConflictTest(t, fsClient, label)
+	tmpFile1Filename := filepath.Base(tmpFile1.Name())
+
+	// The local change is older than the remote change
+	writeTestFile(t, tmpFile1, "local")
+	old := time.Now().Add(-time.Hour)
+	err = os.Chtimes(tmpFile1.Name(), old, old)
+	assert.Nil(t, err)
+	err = os.WriteFile(syncDir2+"/"+tmpFile1Filename, []byte("remote"), 0644)
+	assert.Nil(t, err)
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir2, label, true)
+	assert.Nil(t, err)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	fsClient.ConflictStrategy = core.ConflictStrategyNewestWins
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.Conflicts, 1)
+	assert.Equal(t, core.ConflictStrategyKeepRemote, syncPlan.Conflicts[0].Resolution)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	fileContent, err := os.ReadFile(syncDir + "/" + tmpFile1Filename)
+	assert.Nil(t, err)
+	assert.Equal(t, "remote", string(fileContent))
+
+	// Clean up
+	tmpFile1.Close()
+	err = os.RemoveAll(syncDir)
+	assert.Nil(t, err)
+	err = os.RemoveAll(syncDir2)
+	assert.Nil(t, err)
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func TestSyncRemove(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	label := "/test_label"
+
+	fsClient, err := CreateFSClient(coloniesClient, env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+
+	syncDir, syncDir2, tmpFile1 := setupConflictTest(t, fsClient, label)
+	tmpFile1Filename := filepath.Base(tmpFile1.Name())
+
+	// Removed locally in syncDir2 and unchanged remotely, should be removed remotely rather than downloaded again
+	err = os.Remove(syncDir2 + "/" + tmpFile1Filename)
+	assert.Nil(t, err)
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir2, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.LocalMissing, 0)
+	assert.Len(t, syncPlan.RemoteRemove, 1)
+	assert.Len(t, syncPlan.Conflicts, 0)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	fileData, err := coloniesClient.GetFileData(env.colonyName, label, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, fileData, 0)
+
+	// Removed remotely and unchanged locally in syncDir, should be removed locally rather than uploaded again
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.RemoteMissing, 0)
+	assert.Len(t, syncPlan.LocalRemove, 1)
+	assert.Len(t, syncPlan.Conflicts, 0)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	_, err = os.Stat(syncDir + "/" + tmpFile1Filename)
+	assert.True(t, os.IsNotExist(err))
+
+	// Everything is now in sync
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.LocalMissing, 0)
+	assert.Len(t, syncPlan.RemoteMissing, 0)
+	assert.Len(t, syncPlan.LocalRemove, 0)
+	assert.Len(t, syncPlan.RemoteRemove, 0)
+	assert.Len(t, syncPlan.Conflicts, 0)
+
+	// Clean up
+	tmpFile1.Close()
+	err = os.RemoveAll(syncDir)
+	assert.Nil(t, err)
+	err = os.RemoveAll(syncDir2)
+	assert.Nil(t, err)
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func TestSyncRemoveConflict(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	label := "/test_label"
+
+	fsClient, err := CreateFSClient(coloniesClient, env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+
+	syncDir, syncDir2, tmpFile1 := setupConflictTest(t, fsClient, label)
+	tmpFile1Filename := filepath.Base(tmpFile1.Name())
+
+	// Changed remotely and removed locally in syncDir
+	err = os.WriteFile(syncDir2+"/"+tmpFile1Filename, []byte("remote"), 0644)
+	assert.Nil(t, err)
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir2, label, true)
+	assert.Nil(t, err)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+	err = os.Remove(tmpFile1.Name())
+	assert.Nil(t, err)
+
+	fsClient.ConflictStrategy = core.ConflictStrategyFail
+	_, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.NotNil(t, err)
+
+	fsClient.ConflictStrategy = core.ConflictStrategyKeepLocal
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.RemoteRemove, 0)
+	assert.Len(t, syncPlan.Conflicts, 1)
+	assert.True(t, syncPlan.Conflicts[0].Remove)
+
+	// The remote change wins, and is downloaded
+	fsClient.ConflictStrategy = core.ConflictStrategyKeepRemote
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.Conflicts, 1)
+	assert.False(t, syncPlan.Conflicts[0].Remove)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	fileContent, err := os.ReadFile(syncDir + "/" + tmpFile1Filename)
+	assert.Nil(t, err)
+	assert.Equal(t, "remote", string(fileContent))
+
+	// Removed remotely from syncDir2, and changed locally in syncDir
+	err = os.Remove(syncDir2 + "/" + tmpFile1Filename)
+	assert.Nil(t, err)
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir2, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.RemoteRemove, 1)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+	err = os.WriteFile(syncDir+"/"+tmpFile1Filename, []byte("local"), 0644)
+	assert.Nil(t, err)
+
+	fsClient.ConflictStrategy = core.ConflictStrategyKeepLocal
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.LocalRemove, 0)
+	assert.Len(t, syncPlan.Conflicts, 1)
+	assert.False(t, syncPlan.Conflicts[0].Remove)
+
+	// The remote removal wins, and the local file is removed
+	fsClient.ConflictStrategy = core.ConflictStrategyKeepRemote
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir, label, true)
+	assert.Nil(t, err)
+	assert.Len(t, syncPlan.Conflicts, 1)
+	assert.True(t, syncPlan.Conflicts[0].Remove)
+	err = fsClient.ApplySyncPlan(syncPlan)
+	assert.Nil(t, err)
+
+	_, err = os.Stat(syncDir + "/" + tmpFile1Filename)
+	assert.True(t, os.IsNotExist(err))
+
+	// Clean up
+	tmpFile1.Close()
+	err = os.RemoveAll(syncDir)
+	assert.Nil(t, err)
+	err = os.RemoveAll(syncDir2)
+	assert.Nil(t, err)
+
+	coloniesServer.Shutdown()
+	<-done
+}
@@ -0,0 +1,88 @@
+package fs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	log "github.com/sirupsen/logrus"
+)
+
+// RetentionStore is used by the server to archive rows removed by a retention policy and to remove expired
+// file revisions. Archives are gzip compressed and added to ColoniesFS, so they can be downloaded using
+// the regular fs commands.
+type RetentionStore struct {
+	s3Client *S3Client
+	db       database.Database
+}
+
+func CreateRetentionStore(db database.Database) (*RetentionStore, error) {
+	s3Client, err := CreateS3Client()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RetentionStore{s3Client: s3Client, db: db}, nil
+}
+
+func (store *RetentionStore) Archive(colonyName string, label string, name string, data []byte) error {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(data)
+	if err != nil {
+		return err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	compressed := buf.Bytes()
+	hash := sha256.Sum256(compressed)
+
+	s3Object := core.S3Object{
+		Server:        store.s3Client.Endpoint,
+		Port:          -1,
+		TLS:           store.s3Client.TLS,
+		AccessKey:     store.s3Client.AccessKey,
+		SecretKey:     store.s3Client.SecretKey,
+		Region:        store.s3Client.Region,
+		EncryptionKey: "",
+		EncryptionAlg: "",
+		Object:        core.GenerateRandomID(),
+		Bucket:        store.s3Client.BucketName,
+	}
+	file := &core.File{
+		ID:          core.GenerateRandomID(),
+		ColonyName:  colonyName,
+		Label:       label,
+		Name:        name,
+		Size:        int64(len(compressed)),
+		Checksum:    hex.EncodeToString(hash[:]),
+		ChecksumAlg: "SHA256",
+		Reference:   core.Reference{Protocol: "s3", S3Object: s3Object}}
+
+	err = store.s3Client.UploadData(compressed, s3Object.Object)
+	if err != nil {
+		return err
+	}
+
+	return store.db.AddFile(file)
+}
+
+func (store *RetentionStore) Remove(file *core.File) error {
+	if file.Size == 0 || file.Reference.Protocol != "s3" {
+		return nil
+	}
+
+	if file.Reference.S3Object.Bucket != store.s3Client.BucketName {
+		log.WithFields(log.Fields{"FileID": file.ID, "Bucket": file.Reference.S3Object.Bucket}).Warn("File revision is stored in another bucket, only removing metadata")
+		return nil
+	}
+
+	return store.s3Client.Remove(file.Reference.S3Object.Object)
+}
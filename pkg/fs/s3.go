@@ -2,6 +2,7 @@ package fs
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/tls"
 	"io"
@@ -120,6 +121,16 @@ func (s3Client *S3Client) Upload(dir string, filename string, s3Filename string,
 	return nil
 }
 
+func (s3Client *S3Client) UploadData(data []byte, s3Filename string) error {
+	_, err := s3Client.mc.PutObject(context.Background(), s3Client.BucketName, s3Filename, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/octet-stream"})
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	return nil
+}
+
 func (s3Client *S3Client) Download(filename string, s3Filename string, downloadDir string, tracker *progress.Tracker, quiet bool) error {
 	file, err := s3Client.mc.GetObject(context.Background(), s3Client.BucketName, s3Filename, minio.GetObjectOptions{})
 	if err != nil {
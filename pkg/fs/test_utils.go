@@ -74,7 +74,7 @@ func areDirsSame(dir1, dir2 string) (bool, error) {
 
 		cfsFile := filepath.Base(path2)
 
-		if cfsFile != ".cfs" {
+		if !isMetadataFile(cfsFile) {
 			info2, err2 := os.Stat(path2)
 			if err2 != nil {
 				if os.IsNotExist(err2) {
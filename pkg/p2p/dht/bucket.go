@@ -65,6 +65,14 @@ func (bucket *bucket) getContactAndCalcDistance(target KademliaID) []Contact {
 	return contacts
 }
 
+func (bucket *bucket) getContacts() []Contact {
+	var contacts []Contact
+	for elt := bucket.list.Front(); elt != nil; elt = elt.Next() {
+		contacts = append(contacts, elt.Value.(Contact))
+	}
+	return contacts
+}
+
 func (bucket *bucket) len() int {
 	return bucket.list.Len()
 }
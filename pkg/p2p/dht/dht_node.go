@@ -6,6 +6,21 @@ import (
 )
 
 func CreateDHT(port int, name string) (DHT, error) {
+	return CreateDHTWithStorage(port, name, "")
+}
+
+// CreateDHTWithStorage creates a DHT node that persists the values it stores in a file at storagePath, so that the
+// values survive restarts. Values are only kept in memory if storagePath is empty.
+func CreateDHTWithStorage(port int, name string, storagePath string) (DHT, error) {
+	var storage Storage
+	if storagePath != "" {
+		fileStorage, err := CreateFileStorage(storagePath)
+		if err != nil {
+			return nil, err
+		}
+		storage = fileStorage
+	}
+
 	m, err := libp2p.CreateMessenger(port, name)
 	if err != nil {
 		return nil, err
@@ -23,8 +38,11 @@ func CreateDHT(port int, name string) (DHT, error) {
 		return nil, err
 	}
 
-	k, err := CreateKademlia(m, contact)
+	k, err := CreateKademliaWithStorage(m, contact, storage)
 	if err != nil {
+		if storage != nil {
+			storage.Close()
+		}
 		return nil, err
 	}
 
@@ -96,7 +96,8 @@ func (dispatcher *dispatcher) send(msg p2p.Message) (chan p2p.Message, error) {
 
 	log.WithFields(log.Fields{"msgID": msg.ID, "From": msg.From.String(), "To": msg.To.String(), "Type": msg.Type}).Info("Sending message")
 
-	replyChan := make(chan p2p.Message)
+	// The reply channel is buffered, so that a reply arriving after the sender has timed out does not block the dispatcher
+	replyChan := make(chan p2p.Message, 1)
 
 	dispatcher.mutex.Lock()
 	dispatcher.replyHandler[msg.ID] = replyChan
@@ -0,0 +1,205 @@
+package dht
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	storagePut    = "put"
+	storageRemove = "remove"
+)
+
+type storageEntry struct {
+	Op     string  `json:"op"`
+	Key    string  `json:"key,omitempty"`
+	Record *Record `json:"record,omitempty"`
+}
+
+// FileStorage is an append-only log of put and remove entries, one JSON entry per line. The log is replayed when
+// the node starts and rewritten with only the current records when it is compacted.
+type FileStorage struct {
+	path  string
+	file  *os.File
+	size  int
+	mutex sync.Mutex
+}
+
+func CreateFileStorage(path string) (*FileStorage, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileStorage{path: path, file: file}, nil
+}
+
+func (storage *FileStorage) Load() ([]Record, error) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	file, err := os.Open(storage.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records := make(map[string]Record)
+	var keys []string
+	size := 0
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry storageEntry
+		err := json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			// The last entry is incomplete if the node crashed while writing it
+			log.WithFields(log.Fields{"Error": err, "Path": storage.path}).Warn("Skipping corrupt DHT storage entry")
+			continue
+		}
+
+		size++
+		switch entry.Op {
+		case storagePut:
+			if entry.Record == nil {
+				continue
+			}
+			if _, ok := records[entry.Record.KV.Key]; !ok {
+				keys = append(keys, entry.Record.KV.Key)
+			}
+			records[entry.Record.KV.Key] = *entry.Record
+		case storageRemove:
+			delete(records, entry.Key)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	storage.size = size
+
+	var result []Record
+	for _, key := range keys {
+		if record, ok := records[key]; ok {
+			result = append(result, record)
+		}
+	}
+
+	return result, nil
+}
+
+func (storage *FileStorage) append(entry storageEntry) error {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	if storage.file == nil {
+		return errors.New("DHT storage is closed")
+	}
+
+	jsonBytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = storage.file.Write(append(jsonBytes, '\n'))
+	if err != nil {
+		return err
+	}
+
+	storage.size++
+
+	return nil
+}
+
+func (storage *FileStorage) Put(record Record) error {
+	return storage.append(storageEntry{Op: storagePut, Record: &record})
+}
+
+func (storage *FileStorage) Remove(key string) error {
+	return storage.append(storageEntry{Op: storageRemove, Key: key})
+}
+
+// Compact writes the records to a new file which then replaces the log, so that the log is intact if the node
+// crashes while compacting
+func (storage *FileStorage) Compact(records []Record) error {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	if storage.file == nil {
+		return errors.New("DHT storage is closed")
+	}
+
+	tmpPath := storage.path + ".compact"
+	tmpFile, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(tmpFile)
+	for i := range records {
+		jsonBytes, err := json.Marshal(storageEntry{Op: storagePut, Record: &records[i]})
+		if err != nil {
+			tmpFile.Close()
+			return err
+		}
+		writer.Write(append(jsonBytes, '\n'))
+	}
+
+	err = writer.Flush()
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpPath, storage.path)
+	if err != nil {
+		return err
+	}
+
+	storage.file.Close()
+	storage.file, err = os.OpenFile(storage.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	storage.size = len(records)
+
+	return nil
+}
+
+func (storage *FileStorage) Size() int {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	return storage.size
+}
+
+func (storage *FileStorage) Close() error {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	if storage.file == nil {
+		return nil
+	}
+
+	err := storage.file.Close()
+	storage.file = nil
+
+	return err
+}
@@ -0,0 +1,170 @@
+package dht
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestRecord(key string, value string) Record {
+	return Record{KV: KV{ID: "myid", Key: key, Value: value, Sig: core.GenerateRandomID(), TTL: 3600}, Expires: time.Now().Add(time.Hour).UTC()}
+}
+
+func TestFileStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dht", "kv.log")
+
+	storage, err := CreateFileStorage(path)
+	assert.Nil(t, err)
+
+	records, err := storage.Load()
+	assert.Nil(t, err)
+	assert.Len(t, records, 0)
+
+	record1 := createTestRecord("/a/b", "value1")
+	record2 := createTestRecord("/a/c", "value2")
+	record3 := createTestRecord("/a/b", "value3")
+
+	assert.Nil(t, storage.Put(record1))
+	assert.Nil(t, storage.Put(record2))
+	assert.Nil(t, storage.Put(record3))
+	assert.Nil(t, storage.Remove("/a/c"))
+	assert.Equal(t, 4, storage.Size())
+	assert.Nil(t, storage.Close())
+
+	err = storage.Put(record1)
+	assert.NotNil(t, err) // Closed
+
+	storage, err = CreateFileStorage(path)
+	assert.Nil(t, err)
+
+	records, err = storage.Load()
+	assert.Nil(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "value3", records[0].KV.Value)
+	assert.True(t, record3.Expires.Equal(records[0].Expires))
+	assert.Equal(t, 4, storage.Size())
+
+	assert.Nil(t, storage.Compact(records))
+	assert.Equal(t, 1, storage.Size())
+
+	assert.Nil(t, storage.Put(record2))
+	assert.Nil(t, storage.Close())
+
+	storage, err = CreateFileStorage(path)
+	assert.Nil(t, err)
+
+	records, err = storage.Load()
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, 2, storage.Size())
+	assert.Nil(t, storage.Close())
+}
+
+func TestFileStorageCorruptEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kv.log")
+
+	storage, err := CreateFileStorage(path)
+	assert.Nil(t, err)
+	assert.Nil(t, storage.Put(createTestRecord("/a/b", "value1")))
+	assert.Nil(t, storage.Close())
+
+	// A node crashing while writing an entry leaves an incomplete entry
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	assert.Nil(t, err)
+	_, err = file.WriteString(`{"op":"put","record":{"kv":{"id":"myid","key":"/a/c"`)
+	assert.Nil(t, err)
+	file.Close()
+
+	storage, err = CreateFileStorage(path)
+	assert.Nil(t, err)
+
+	records, err := storage.Load()
+	assert.Nil(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "/a/b", records[0].KV.Key)
+	assert.Nil(t, storage.Close())
+}
+
+func TestKVStoreWithStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kv.log")
+
+	storage, err := CreateFileStorage(path)
+	assert.Nil(t, err)
+
+	kvs, err := createKVStoreWithStorage(storage)
+	assert.Nil(t, err)
+
+	sig := core.GenerateRandomID()
+	now := time.Now()
+	assert.Nil(t, kvs.putWithExpiry("myid", "/a/b", "value1", sig, 3600, now.Add(time.Hour)))
+	assert.Nil(t, kvs.putWithExpiry("myid", "/a/c", "value2", sig, 60, now.Add(time.Minute)))
+	assert.Nil(t, kvs.put("myid", "/a/d", "value3", sig))
+	assert.Nil(t, kvs.put("myid", "/a/e", "value4", sig))
+	assert.Nil(t, kvs.removeKey("/a/e"))
+	assert.Nil(t, kvs.close())
+
+	// Values are replayed when the store is recreated
+	storage, err = CreateFileStorage(path)
+	assert.Nil(t, err)
+	kvs, err = createKVStoreWithStorage(storage)
+	assert.Nil(t, err)
+
+	got, err := kvs.get("/a/b")
+	assert.Nil(t, err)
+	assert.Equal(t, "value1", got.Value)
+	assert.Equal(t, int64(3600), got.TTL)
+	_, err = kvs.get("/a/c")
+	assert.Nil(t, err)
+	_, err = kvs.get("/a/d")
+	assert.Nil(t, err)
+	_, err = kvs.get("/a/e")
+	assert.NotNil(t, err)
+
+	// The storage is compacted on start
+	assert.Equal(t, 3, storage.Size())
+
+	// Removed expired values are not replayed
+	assert.Equal(t, 1, kvs.removeExpired(now.Add(2*time.Minute)))
+	assert.Nil(t, kvs.close())
+
+	storage, err = CreateFileStorage(path)
+	assert.Nil(t, err)
+	kvs, err = createKVStoreWithStorage(storage)
+	assert.Nil(t, err)
+
+	_, err = kvs.get("/a/c")
+	assert.NotNil(t, err)
+	records := kvs.getRecords()
+	assert.Len(t, records, 2)
+	assert.Nil(t, kvs.close())
+}
+
+func TestKVStoreCompactIfNeeded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kv.log")
+
+	storage, err := CreateFileStorage(path)
+	assert.Nil(t, err)
+
+	kvs, err := createKVStoreWithStorage(storage)
+	assert.Nil(t, err)
+
+	sig := core.GenerateRandomID()
+	for i := 0; i < compactionThreshold; i++ {
+		assert.Nil(t, kvs.put("myid", "/a/b", "value", sig))
+	}
+	assert.Equal(t, compactionThreshold, storage.Size())
+
+	assert.Nil(t, kvs.compactIfNeeded())
+	assert.Equal(t, 1, storage.Size())
+
+	// Small storages are not compacted
+	assert.Nil(t, kvs.put("myid", "/a/b", "value", sig))
+	assert.Nil(t, kvs.compactIfNeeded())
+	assert.Equal(t, 2, storage.Size())
+
+	assert.Nil(t, kvs.close())
+}
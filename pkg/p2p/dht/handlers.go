@@ -131,7 +131,8 @@ func (k *Kademlia) handlePutReq(msg p2p.Message) error {
 
 	err = k.addContact(&req.Header.Sender)
 	if err == nil {
-		errChan := k.states.put(req.KV.ID, req.KV.Key, req.KV.Value, req.KV.Sig)
+		ttl := kvTTLFromReq(req.KV.TTL)
+		errChan := k.states.put(req.KV.ID, req.KV.Key, req.KV.Value, req.KV.Sig, int64(ttl.Seconds()), time.Now().Add(ttl))
 
 		select {
 		case <-time.After(1 * time.Second):
@@ -1,29 +1,61 @@
 package dht
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/colonyos/colonies/pkg/p2p"
 	log "github.com/sirupsen/logrus"
 )
 
 type Kademlia struct {
-	messenger  p2p.Messenger
-	Contact    Contact
-	states     *states
-	dispatcher *dispatcher
+	messenger         p2p.Messenger
+	Contact           Contact
+	states            *states
+	dispatcher        *dispatcher
+	kvTTL             time.Duration
+	refreshInterval   time.Duration
+	republishInterval time.Duration
+	published         map[string]publication
+	publishedMutex    sync.Mutex
+	ctx               context.Context
+	cancel            context.CancelFunc
 }
 
 func CreateKademlia(messenger p2p.Messenger, contact Contact) (*Kademlia, error) {
-	states := createStates(contact)
-	k := &Kademlia{messenger: messenger, Contact: contact, states: states}
+	return CreateKademliaWithStorage(messenger, contact, nil)
+}
+
+// CreateKademliaWithStorage creates a node that persists the values it stores, values in the storage are loaded
+// before the node starts serving requests
+func CreateKademliaWithStorage(messenger p2p.Messenger, contact Contact, storage Storage) (*Kademlia, error) {
+	states, err := createStates(contact, storage)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to load DHT storage")
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	k := &Kademlia{messenger: messenger,
+		Contact:           contact,
+		states:            states,
+		kvTTL:             DefaultKVTTL,
+		refreshInterval:   BucketRefreshInterval,
+		republishInterval: RepublishInterval,
+		published:         make(map[string]publication),
+		ctx:               ctx,
+		cancel:            cancel}
 	dispatcher, err := createDispatcher(k)
 	if err != nil {
 		log.WithFields(log.Fields{"Error": err}).Error("Failed to create dispatcher")
+		cancel()
 		return nil, err
 	}
 	k.dispatcher = dispatcher
 
 	go dispatcher.serveForever()
 	go states.serveForever()
+	go k.maintainForever()
 
 	return k, nil
 }
@@ -33,6 +65,7 @@ func (k *Kademlia) GetContact() Contact {
 }
 
 func (k *Kademlia) Shutdown() {
+	k.cancel()
 	k.states.shutdown()
 	k.dispatcher.shutdown()
 }
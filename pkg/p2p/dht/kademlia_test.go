@@ -247,3 +247,34 @@ func TestKademliaNodeRegAndLookup(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, lookupedNode.Equals(node))
 }
+
+func TestKademliaPutGetNoContacts(t *testing.T) {
+	n := mock.CreateFakeNetwork()
+
+	k := createKademliaNode(t, n, "localhost:8000")
+
+	crypto := crypto.CreateCrypto()
+	prvKey, err := crypto.GeneratePrivateKey()
+	assert.Nil(t, err)
+	id, err := crypto.GenerateID(prvKey)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	err = k.Put(id, prvKey, "/prefix/key1", "test1", 5, ctx)
+	cancel()
+	assert.Nil(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	kvs, err := k.Get(id, "/prefix", 5, ctx)
+	cancel()
+	assert.Nil(t, err)
+	assert.Len(t, kvs, 1)
+	assert.Equal(t, kvs[0].Value, "test1")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	_, err = k.Get(id, "/notfound", 5, ctx)
+	cancel()
+	assert.NotNil(t, err)
+
+	k.Shutdown()
+}
@@ -3,20 +3,25 @@ package dht
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 type node struct {
 	children map[string]*node
 	kv       KV
 	isValue  bool
+	expires  time.Time // Zero if the value never expires
 }
 
 func newNode() *node {
 	return &node{children: make(map[string]*node)}
 }
 
+const compactionThreshold = 1000
+
 type kvStore struct {
-	root *node
+	root    *node
+	storage Storage // Nil if values are only kept in memory
 }
 
 type KV struct {
@@ -24,6 +29,7 @@ type KV struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
 	Sig   string `json:"sig"`
+	TTL   int64  `json:"ttl,omitempty"` // Seconds the value is stored before it expires, unless it is republished
 }
 
 func (kv *KV) String() string {
@@ -34,7 +40,43 @@ func createKVStore() *kvStore {
 	return &kvStore{root: &node{children: make(map[string]*node)}}
 }
 
+// createKVStoreWithStorage replays the storage into a new store, expired records are skipped and the storage is
+// compacted, so that it only contains the replayed records
+func createKVStoreWithStorage(storage Storage) (*kvStore, error) {
+	records, err := storage.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	kvs := createKVStore()
+	now := time.Now()
+	for _, record := range records {
+		if !record.Expires.IsZero() && now.After(record.Expires) {
+			continue
+		}
+		err := kvs.putWithExpiry(record.KV.ID, record.KV.Key, record.KV.Value, record.KV.Sig, record.KV.TTL, record.Expires)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = storage.Compact(kvs.getRecords())
+	if err != nil {
+		return nil, err
+	}
+
+	kvs.storage = storage
+
+	return kvs, nil
+}
+
 func (kvStore *kvStore) put(id string, key string, value string, sig string) error {
+	return kvStore.putWithExpiry(id, key, value, sig, 0, time.Time{})
+}
+
+// putWithExpiry stores a value that is removed by removeExpired after the expiry time, putting the value again
+// extends the expiry time
+func (kvStore *kvStore) putWithExpiry(id string, key string, value string, sig string, ttl int64, expires time.Time) error {
 	if len(key) == 0 || key[0] != '/' {
 		return fmt.Errorf("Invalid key, must start with /")
 	}
@@ -43,6 +85,14 @@ func (kvStore *kvStore) put(id string, key string, value string, sig string) err
 		return fmt.Errorf("Invalid value, cannot be empty")
 	}
 
+	kv := KV{ID: id, Key: key, Value: value, Sig: sig, TTL: ttl}
+	if kvStore.storage != nil {
+		err := kvStore.storage.Put(Record{KV: kv, Expires: expires})
+		if err != nil {
+			return err
+		}
+	}
+
 	parts := strings.Split(key, "/")[1:]
 
 	current := kvStore.root
@@ -57,8 +107,9 @@ func (kvStore *kvStore) put(id string, key string, value string, sig string) err
 	if current.children[lastPart] == nil {
 		current.children[lastPart] = newNode()
 	}
-	current.children[lastPart].kv = KV{ID: id, Key: key, Value: value, Sig: sig}
+	current.children[lastPart].kv = kv
 	current.children[lastPart].isValue = true
+	current.children[lastPart].expires = expires
 
 	return nil
 }
@@ -74,7 +125,7 @@ func (kvStore *kvStore) get(key string) (KV, error) {
 		current = current.children[part]
 	}
 
-	if current.isValue {
+	if current.isValue && !current.isExpired(time.Now()) {
 		return current.kv, nil
 	} else {
 		return KV{}, fmt.Errorf("Key not found")
@@ -99,7 +150,7 @@ func (kvStore *kvStore) getAllValuesWithPrefix(prefix string) ([]KV, error) {
 }
 
 func (kvStore *kvStore) collectValues(node *node, coll *[]KV) {
-	if node.isValue {
+	if node.isValue && !node.isExpired(time.Now()) {
 		*coll = append(*coll, node.kv)
 	}
 	for _, child := range node.children {
@@ -107,6 +158,36 @@ func (kvStore *kvStore) collectValues(node *node, coll *[]KV) {
 	}
 }
 
+func (node *node) isExpired(now time.Time) bool {
+	return !node.expires.IsZero() && now.After(node.expires)
+}
+
+// removeExpired removes all values that have expired and returns the number of removed values
+func (kvStore *kvStore) removeExpired(now time.Time) int {
+	var expiredKeys []string
+	kvStore.collectExpiredKeys(kvStore.root, now, &expiredKeys)
+
+	for _, key := range expiredKeys {
+		err := kvStore.removeKey(key)
+		if err != nil {
+			continue
+		}
+		parts := strings.Split(key, "/")[1:]
+		kvStore.cleanupParents(kvStore.root, parts[:len(parts)-1])
+	}
+
+	return len(expiredKeys)
+}
+
+func (kvStore *kvStore) collectExpiredKeys(node *node, now time.Time, keys *[]string) {
+	if node.isValue && node.isExpired(now) {
+		*keys = append(*keys, node.kv.Key)
+	}
+	for _, child := range node.children {
+		kvStore.collectExpiredKeys(child, now, keys)
+	}
+}
+
 func (kvStore *kvStore) removeKey(key string) error {
 	if len(key) == 0 || key[0] != '/' {
 		return fmt.Errorf("Invalid key, must start with /")
@@ -123,6 +204,13 @@ func (kvStore *kvStore) removeKey(key string) error {
 			return fmt.Errorf("Key not found")
 		}
 		if i == len(parts)-1 {
+			if kvStore.storage != nil {
+				err := kvStore.storage.Remove(key)
+				if err != nil {
+					return err
+				}
+			}
+
 			if len(current.children[part].children) > 0 {
 				current.children[part].kv = KV{}
 				current.children[part].isValue = false
@@ -137,6 +225,43 @@ func (kvStore *kvStore) removeKey(key string) error {
 	return nil
 }
 
+func (kvStore *kvStore) getRecords() []Record {
+	var records []Record
+	kvStore.collectRecords(kvStore.root, &records)
+	return records
+}
+
+func (kvStore *kvStore) collectRecords(node *node, records *[]Record) {
+	if node.isValue {
+		*records = append(*records, Record{KV: node.kv, Expires: node.expires})
+	}
+	for _, child := range node.children {
+		kvStore.collectRecords(child, records)
+	}
+}
+
+// compactIfNeeded compacts the storage when most entries are overwritten or removed values
+func (kvStore *kvStore) compactIfNeeded() error {
+	if kvStore.storage == nil || kvStore.storage.Size() < compactionThreshold {
+		return nil
+	}
+
+	records := kvStore.getRecords()
+	if kvStore.storage.Size() < 2*len(records) {
+		return nil
+	}
+
+	return kvStore.storage.Compact(records)
+}
+
+func (kvStore *kvStore) close() error {
+	if kvStore.storage == nil {
+		return nil
+	}
+
+	return kvStore.storage.Close()
+}
+
 func (kvs *kvStore) cleanupParents(node *node, parts []string) {
 	if len(parts) == 0 || node == nil {
 		return
@@ -3,6 +3,7 @@ package dht
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/stretchr/testify/assert"
@@ -253,3 +254,56 @@ func TestRemoveKey(t *testing.T) {
 		t.Errorf("Expected error when attempting to remove the root, but got nil")
 	}
 }
+
+func TestRemoveExpired(t *testing.T) {
+	kvs := createKVStore()
+
+	sig := core.GenerateRandomID()
+	now := time.Now()
+
+	err := kvs.putWithExpiry("myid", "/a/b/c", "value1", sig, 60, now.Add(time.Minute))
+	assert.Nil(t, err)
+	err = kvs.putWithExpiry("myid", "/a/b/d", "value2", sig, 3600, now.Add(time.Hour))
+	assert.Nil(t, err)
+	err = kvs.putWithExpiry("myid", "/a/e/f", "value3", sig, 60, now.Add(time.Minute))
+	assert.Nil(t, err)
+	err = kvs.put("myid", "/a/g", "value4", sig)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 0, kvs.removeExpired(now))
+
+	assert.Equal(t, 2, kvs.removeExpired(now.Add(2*time.Minute)))
+
+	_, err = kvs.get("/a/b/c")
+	assert.NotNil(t, err)
+	_, err = kvs.getAllValuesWithPrefix("/a/e")
+	assert.NotNil(t, err) // Empty parents are removed
+
+	got, err := kvs.get("/a/b/d")
+	assert.Nil(t, err)
+	assert.Equal(t, "value2", got.Value)
+	assert.Equal(t, int64(3600), got.TTL)
+
+	// Values without an expiry time never expire
+	assert.Equal(t, 1, kvs.removeExpired(now.Add(24*time.Hour)))
+	got, err = kvs.get("/a/g")
+	assert.Nil(t, err)
+	assert.Equal(t, "value4", got.Value)
+}
+
+func TestPutWithExpiryExtends(t *testing.T) {
+	kvs := createKVStore()
+
+	sig := core.GenerateRandomID()
+	now := time.Now()
+
+	err := kvs.putWithExpiry("myid", "/a/b", "value1", sig, 60, now.Add(time.Minute))
+	assert.Nil(t, err)
+	err = kvs.putWithExpiry("myid", "/a/b", "value1", sig, 60, now.Add(time.Hour))
+	assert.Nil(t, err)
+
+	assert.Equal(t, 0, kvs.removeExpired(now.Add(2*time.Minute)))
+
+	_, err = kvs.get("/a/b")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,193 @@
+package dht
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const DefaultKVTTL = 1 * time.Hour
+const MaxKVTTL = 24 * time.Hour
+const RepublishInterval = 20 * time.Minute
+const BucketRefreshInterval = 10 * time.Minute
+const ExpireInterval = 1 * time.Minute
+const PingTimeout = 2 * time.Second
+
+// publication is a value put by this node, values are republished by their owner before they expire on other nodes
+type publication struct {
+	id                string
+	prvKey            string
+	key               string
+	value             string
+	replicationFactor int
+}
+
+// kvTTLFromReq returns the TTL of a value put by another node, values without a TTL get the default TTL and the TTL
+// is capped, since the TTL is not covered by the signature
+func kvTTLFromReq(ttl int64) time.Duration {
+	if ttl <= 0 {
+		return DefaultKVTTL
+	}
+
+	d := time.Duration(ttl) * time.Second
+	if d > MaxKVTTL {
+		return MaxKVTTL
+	}
+
+	return d
+}
+
+func (k *Kademlia) addPublication(id string, prvKey string, key string, value string, replicationFactor int) {
+	k.publishedMutex.Lock()
+	defer k.publishedMutex.Unlock()
+
+	k.published["/"+id+key] = publication{id: id, prvKey: prvKey, key: key, value: value, replicationFactor: replicationFactor}
+}
+
+func (k *Kademlia) getPublications() []publication {
+	k.publishedMutex.Lock()
+	defer k.publishedMutex.Unlock()
+
+	var publications []publication
+	for _, p := range k.published {
+		publications = append(publications, p)
+	}
+
+	return publications
+}
+
+// republish puts all values published by this node again, so that the values do not expire on the nodes storing them
+// and are stored on nodes that have joined the network since the values were published
+func (k *Kademlia) republish(ctx context.Context) {
+	for _, p := range k.getPublications() {
+		err := k.Put(p.id, p.prvKey, p.key, p.value, p.replicationFactor, ctx)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "Key": p.key}).Warn("Failed to republish value")
+		}
+	}
+}
+
+// expire removes all values that have not been republished by their owners before their TTL expired
+func (k *Kademlia) expire(now time.Time, ctx context.Context) error {
+	errChan := k.states.expire(now)
+	select {
+	case <-ctx.Done():
+		return errors.New("Expire timeout")
+	case err := <-errChan:
+		return err
+	}
+}
+
+func (k *Kademlia) getContacts(ctx context.Context) ([]Contact, error) {
+	contactsChan, errChan := k.states.getContacts()
+	select {
+	case <-ctx.Done():
+		return nil, errors.New("Get contacts timeout")
+	case contacts := <-contactsChan:
+		return contacts, nil
+	case err := <-errChan:
+		if err != nil {
+			return nil, err
+		}
+		return <-contactsChan, nil
+	}
+}
+
+func (k *Kademlia) removeContact(contact Contact, ctx context.Context) error {
+	errChan := k.states.removeContact(contact)
+	select {
+	case <-ctx.Done():
+		return errors.New("Remove contact timeout")
+	case err := <-errChan:
+		return err
+	}
+}
+
+// refreshBuckets pings all contacts in the routing table and removes the contacts that do not respond. If no contact
+// responds, this node is more likely to be disconnected than all contacts to have left the network, so the routing
+// table is kept intact. Finally, the buckets are refilled by looking up the node itself.
+func (k *Kademlia) refreshBuckets(ctx context.Context) error {
+	contacts, err := k.getContacts(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	type pingResult struct {
+		contact Contact
+		err     error
+	}
+
+	results := make(chan pingResult, len(contacts))
+	for _, contact := range contacts {
+		go func(contact Contact) {
+			pingCtx, cancel := context.WithTimeout(ctx, PingTimeout)
+			defer cancel()
+			results <- pingResult{contact: contact, err: k.ping(contact.Node, pingCtx)}
+		}(contact)
+	}
+
+	var failed []Contact
+	for range contacts {
+		result := <-results
+		if result.err != nil {
+			failed = append(failed, result.contact)
+		}
+	}
+
+	if len(failed) == len(contacts) {
+		log.WithFields(log.Fields{"Node": k.Contact.Node.String(), "Contacts": len(contacts)}).Warn("No contacts responded to ping, keeping routing table intact")
+		return errors.New("No contacts responded to ping")
+	}
+
+	for _, contact := range failed {
+		err := k.removeContact(contact, ctx)
+		if err != nil {
+			return err
+		}
+		log.WithFields(log.Fields{"Node": k.Contact.Node.String(), "Contact": contact.Node.String()}).Info("Removed contact not responding to ping")
+	}
+
+	_, err = k.FindContacts(k.Contact.ID.String(), bucketSize, ctx)
+	return err
+}
+
+// maintainForever removes expired values, refreshes the buckets and republishes values until the node is shut down
+func (k *Kademlia) maintainForever() {
+	expireTicker := time.NewTicker(ExpireInterval)
+	defer expireTicker.Stop()
+	refreshTicker := time.NewTicker(k.refreshInterval)
+	defer refreshTicker.Stop()
+	republishTicker := time.NewTicker(k.republishInterval)
+	defer republishTicker.Stop()
+
+	for {
+		select {
+		case <-k.ctx.Done():
+			return
+		case now := <-expireTicker.C:
+			ctx, cancel := context.WithTimeout(k.ctx, ExpireInterval)
+			err := k.expire(now, ctx)
+			cancel()
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err}).Warn("Failed to remove expired values")
+			}
+		case <-refreshTicker.C:
+			ctx, cancel := context.WithTimeout(k.ctx, k.refreshInterval)
+			err := k.refreshBuckets(ctx)
+			cancel()
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err}).Warn("Failed to refresh buckets")
+			}
+		case <-republishTicker.C:
+			ctx, cancel := context.WithTimeout(k.ctx, k.republishInterval)
+			k.republish(ctx)
+			cancel()
+		}
+	}
+}
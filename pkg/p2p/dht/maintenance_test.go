@@ -0,0 +1,212 @@
+package dht
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/p2p"
+	"github.com/colonyos/colonies/pkg/p2p/mock"
+	"github.com/colonyos/colonies/pkg/security/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func hasContact(contacts []Contact, contact Contact) bool {
+	for _, c := range contacts {
+		if c.ID.Equals(contact.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestKademliaRefreshBuckets(t *testing.T) {
+	n := mock.CreateFakeNetwork()
+
+	bootstrapNode := createKademliaNode(t, n, "localhost:8000")
+
+	var nodes []*Kademlia
+	for i := 1; i < 6; i++ {
+		k := createKademliaNode(t, n, "localhost:800"+fmt.Sprint(i))
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		k.RegisterNetwork(bootstrapNode.Contact.Node, k.Contact.ID.String(), ctx)
+		cancel()
+		nodes = append(nodes, k)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	contacts, err := bootstrapNode.getContacts(ctx)
+	cancel()
+	assert.Nil(t, err)
+	assert.Len(t, contacts, 5)
+
+	// Dead nodes do not respond to pings
+	nodes[1].Shutdown()
+	nodes[3].Shutdown()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	err = bootstrapNode.refreshBuckets(ctx)
+	cancel()
+	assert.Nil(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	contacts, err = bootstrapNode.getContacts(ctx)
+	cancel()
+	assert.Nil(t, err)
+	assert.Len(t, contacts, 3)
+	assert.False(t, hasContact(contacts, nodes[1].Contact))
+	assert.False(t, hasContact(contacts, nodes[3].Contact))
+	assert.True(t, hasContact(contacts, nodes[0].Contact))
+	assert.True(t, hasContact(contacts, nodes[2].Contact))
+	assert.True(t, hasContact(contacts, nodes[4].Contact))
+
+	bootstrapNode.Shutdown()
+	nodes[0].Shutdown()
+	nodes[2].Shutdown()
+	nodes[4].Shutdown()
+}
+
+func TestKademliaRefreshBucketsDisconnected(t *testing.T) {
+	n := mock.CreateFakeNetwork()
+
+	bootstrapNode := createKademliaNode(t, n, "localhost:8000")
+	k := createKademliaNode(t, n, "localhost:8001")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	err := k.RegisterNetwork(bootstrapNode.Contact.Node, k.Contact.ID.String(), ctx)
+	cancel()
+	assert.Nil(t, err)
+
+	// If no contact responds, the node is probably disconnected and the routing table is kept
+	bootstrapNode.Shutdown()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	err = k.refreshBuckets(ctx)
+	cancel()
+	assert.NotNil(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	contacts, err := k.getContacts(ctx)
+	cancel()
+	assert.Nil(t, err)
+	assert.Len(t, contacts, 1)
+	assert.True(t, hasContact(contacts, bootstrapNode.Contact))
+
+	k.Shutdown()
+}
+
+func TestKademliaExpireAndRepublish(t *testing.T) {
+	n := mock.CreateFakeNetwork()
+
+	k1 := createKademliaNode(t, n, "localhost:8000")
+	k2 := createKademliaNode(t, n, "localhost:8001")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	err := k2.RegisterNetwork(k1.Contact.Node, k2.Contact.ID.String(), ctx)
+	cancel()
+	assert.Nil(t, err)
+
+	crypto := crypto.CreateCrypto()
+	prvKey, err := crypto.GeneratePrivateKey()
+	assert.Nil(t, err)
+	id, err := crypto.GenerateID(prvKey)
+	assert.Nil(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	err = k1.Put(id, prvKey, "/prefix/key1", "test1", 5, ctx)
+	cancel()
+	assert.Nil(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	kvs, err := k2.getLocal(id, "/prefix/key1", ctx)
+	cancel()
+	assert.Nil(t, err)
+	assert.Len(t, kvs, 1)
+	assert.Equal(t, int64(DefaultKVTTL.Seconds()), kvs[0].TTL)
+
+	// Values are kept until the TTL has expired
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	err = k2.expire(time.Now().Add(DefaultKVTTL/2), ctx)
+	cancel()
+	assert.Nil(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	kvs, err = k2.getLocal(id, "/prefix/key1", ctx)
+	cancel()
+	assert.Nil(t, err)
+	assert.Len(t, kvs, 1)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	err = k2.expire(time.Now().Add(2*DefaultKVTTL), ctx)
+	cancel()
+	assert.Nil(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	_, err = k2.getLocal(id, "/prefix/key1", ctx)
+	cancel()
+	assert.NotNil(t, err)
+
+	// The owner republishes the value
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	k1.republish(ctx)
+	cancel()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	kvs, err = k2.getLocal(id, "/prefix/key1", ctx)
+	cancel()
+	assert.Nil(t, err)
+	assert.Len(t, kvs, 1)
+	assert.Equal(t, "test1", kvs[0].Value)
+
+	k1.Shutdown()
+	k2.Shutdown()
+}
+
+func TestKVTTLFromReq(t *testing.T) {
+	assert.Equal(t, DefaultKVTTL, kvTTLFromReq(0))
+	assert.Equal(t, 10*time.Second, kvTTLFromReq(10))
+	assert.Equal(t, MaxKVTTL, kvTTLFromReq(int64((2 * MaxKVTTL).Seconds())))
+}
+
+func TestKademliaStorageRestart(t *testing.T) {
+	n := mock.CreateFakeNetwork()
+	path := filepath.Join(t.TempDir(), "kv.log")
+
+	crypto := crypto.CreateCrypto()
+	nodePrvKey, err := crypto.GeneratePrivateKey()
+	assert.Nil(t, err)
+	node := p2p.Node{Addr: "localhost:8000"}
+	contact, err := CreateContact(node, nodePrvKey)
+	assert.Nil(t, err)
+
+	storage, err := CreateFileStorage(path)
+	assert.Nil(t, err)
+	k, err := CreateKademliaWithStorage(mock.CreateMessenger(n, node), contact, storage)
+	assert.Nil(t, err)
+
+	prvKey, err := crypto.GeneratePrivateKey()
+	assert.Nil(t, err)
+	id, err := crypto.GenerateID(prvKey)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	err = k.Put(id, prvKey, "/prefix/key1", "test1", 5, ctx)
+	cancel()
+	assert.Nil(t, err)
+
+	k.Shutdown()
+
+	storage, err = CreateFileStorage(path)
+	assert.Nil(t, err)
+	k, err = CreateKademliaWithStorage(mock.CreateMessenger(n, node), contact, storage)
+	assert.Nil(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	kvs, err := k.getLocal(id, "/prefix/key1", ctx)
+	cancel()
+	assert.Nil(t, err)
+	assert.Len(t, kvs, 1)
+	assert.Equal(t, "test1", kvs[0].Value)
+
+	k.Shutdown()
+}
@@ -3,6 +3,7 @@ package dht
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/p2p"
 	"github.com/colonyos/colonies/pkg/security/crypto"
@@ -35,6 +36,7 @@ func (k *Kademlia) ping(node p2p.Node, ctx context.Context) error {
 	select {
 	case <-ctx.Done():
 		log.WithFields(log.Fields{"Node": node.String()}).Warn("Ping request timeout")
+		return errors.New("Ping request timeout")
 	case msg := <-reply:
 		log.WithFields(log.Fields{"From": msg.From}).Info("Ping response received")
 		rpc, err := ConvertJSONToPingResp(string(msg.Payload))
@@ -153,11 +155,6 @@ func (k *Kademlia) RegisterNetworkWithAddr(bootstrapNodeAddr string, kademliaID
 }
 
 func (k *Kademlia) FindContacts(kademliaID string, count int, ctx context.Context) ([]Contact, error) {
-	foundContacts := make(map[string]Contact)
-	pendingContactChan := make(chan Contact, 10)
-
-	outgoingReq := make(chan struct{}, MaxPendingRequests)
-
 	contacts, err := k.findLocalContacts(kademliaID, count, ctx)
 	if err != nil {
 		log.WithFields(log.Fields{"Error": err}).Error("Failed to find local contacts")
@@ -169,62 +166,77 @@ func (k *Kademlia) FindContacts(kademliaID string, count int, ctx context.Contex
 		return []Contact{}, nil
 	}
 
-	for _, contact := range contacts {
-		pendingContactChan <- contact
-	}
-
-	var contact Contact
+	// Every contact found is queried once, the lookup ends when all queries have been answered or have timed out.
+	// The loop blocks on the results, so that pending lookups do not consume any CPU.
+	targetID := CreateKademliaID(kademliaID)
+	foundContacts := make(map[string]Contact)
+	results := make(chan []Contact)
+	queue := contacts
+	outgoing := 0
+lookup:
 	for {
-		if len(pendingContactChan) > 0 {
-			contact = <-pendingContactChan
-			if contact.ID == CreateKademliaID(kademliaID) && count == 1 {
+		for len(queue) > 0 {
+			contact := queue[0]
+			queue = queue[1:]
+			if contact.ID == targetID && count == 1 {
 				return []Contact{contact}, nil
 			}
-		} else if len(outgoingReq) == 0 && len(pendingContactChan) == 0 {
-			var candidates ContactCandidates
-			for _, contact := range foundContacts {
-				contact.CalcDistance(CreateKademliaID(kademliaID))
-				candidates.contacts = append(candidates.contacts, contact)
-			}
-			candidates.Sort()
 
-			if count > candidates.Len() {
-				count = candidates.Len()
+			if _, ok := foundContacts[contact.Node.String()]; ok {
+				continue
 			}
-
-			return candidates.GetContacts(count), nil
-		}
-
-		if _, ok := foundContacts[contact.Node.String()]; !ok {
 			foundContacts[contact.Node.String()] = contact
-			outgoingReq <- struct{}{}
-			go func(contact2 Contact, kademliaID2 string, count2 int, ctx2 context.Context) {
-				defer func() { <-outgoingReq }()
-				contacts, err := k.findRemoteContacts(contact2.Node, kademliaID2, count2, ctx2)
+
+			outgoing++
+			go func(contact Contact) {
+				contacts, err := k.findRemoteContacts(contact.Node, kademliaID, count, ctx)
 				if err != nil {
-					log.WithFields(log.Fields{"Error": err, "Node": contact2.Node.String()}).Error("Failed to find remote contacts")
-					return
+					log.WithFields(log.Fields{"Error": err, "Node": contact.Node.String()}).Error("Failed to find remote contacts")
 				}
-				for _, contact := range contacts {
-					pendingContactChan <- contact
+				select {
+				case results <- contacts:
+				case <-ctx.Done():
 				}
-			}(contact, kademliaID, count, ctx)
+			}(contact)
+		}
+
+		if outgoing == 0 {
+			break
 		}
+
+		select {
+		case contacts := <-results:
+			outgoing--
+			queue = append(queue, contacts...)
+		case <-ctx.Done():
+			break lookup
+		}
+	}
+
+	var candidates ContactCandidates
+	for _, contact := range foundContacts {
+		contact.CalcDistance(targetID)
+		candidates.contacts = append(candidates.contacts, contact)
 	}
+	candidates.Sort()
+
+	if count > candidates.Len() {
+		count = candidates.Len()
+	}
+
+	return candidates.GetContacts(count), nil
 }
 
 func (k *Kademlia) putRemote(node p2p.Node, id string, prvKey string, key string, value string, ctx context.Context) error {
 	log.WithFields(log.Fields{"To": node, "From": k.Contact.Node.String()}).Info("Sending put request")
 
-	crypto := crypto.CreateCrypto()
-	hash := crypto.GenerateHash(value)
-	sig, err := crypto.GenerateSignature(hash, prvKey)
+	sig, err := sign(value, prvKey)
 	if err != nil {
 		log.WithFields(log.Fields{"Error": err}).Error("Failed to generate signature")
 		return err
 	}
 
-	payload := PutReq{Header: RPCHeader{Sender: k.Contact}, KV: KV{ID: id, Key: "/" + id + key, Value: value, Sig: sig}}
+	payload := PutReq{Header: RPCHeader{Sender: k.Contact}, KV: KV{ID: id, Key: "/" + id + key, Value: value, Sig: sig, TTL: int64(k.kvTTL.Seconds())}}
 	json, err := payload.ToJSON()
 	if err != nil {
 		log.WithFields(log.Fields{"Error": err}).Error("Failed to convert to JSON")
@@ -285,7 +297,7 @@ func (k *Kademlia) getRemote(node p2p.Node, id string, key string, ctx context.C
 	select {
 	case <-ctx.Done():
 		log.WithFields(log.Fields{"Node": node.String()}).Warn("Get request timeout")
-		return nil, errors.New("Put request timeout")
+		return nil, errors.New("Get request timeout")
 	case msg := <-reply:
 		log.WithFields(log.Fields{"From": msg.From.String()}).Info("Get response received")
 		rpc, err := ConvertJSONToGetResp(string(msg.Payload))
@@ -302,7 +314,66 @@ func (k *Kademlia) getRemote(node p2p.Node, id string, key string, ctx context.C
 	}
 }
 
+func sign(value string, prvKey string) (string, error) {
+	crypto := crypto.CreateCrypto()
+	hash := crypto.GenerateHash(value)
+	return crypto.GenerateSignature(hash, prvKey)
+}
+
+// putLocal stores a value in the local key-value store, the node publishing a value is always one of the replicas,
+// so that the value can be found even if the node has no contacts yet
+func (k *Kademlia) putLocal(id string, prvKey string, key string, value string, ctx context.Context) error {
+	sig, err := sign(value, prvKey)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to generate signature")
+		return err
+	}
+
+	kv := KV{ID: id, Key: "/" + id + key, Value: value, Sig: sig, TTL: int64(k.kvTTL.Seconds())}
+	valid, err := ValidateKV(&kv)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("Failed to validate key-value")
+	}
+
+	errChan := k.states.put(kv.ID, kv.Key, kv.Value, kv.Sig, kv.TTL, time.Now().Add(k.kvTTL))
+	select {
+	case <-ctx.Done():
+		return errors.New("Put local timeout")
+	case err := <-errChan:
+		return err
+	}
+}
+
+func (k *Kademlia) getLocal(id string, key string, ctx context.Context) ([]KV, error) {
+	kvsChan, errChan := k.states.get("/" + id + key)
+	select {
+	case <-ctx.Done():
+		return nil, errors.New("Get local timeout")
+	case kvs := <-kvsChan:
+		return kvs, nil
+	case err := <-errChan:
+		// Both channels are closed when the job is done, so a closed error channel means the values are ready
+		if err != nil {
+			return nil, err
+		}
+		return <-kvsChan, nil
+	}
+}
+
+// Put stores a value on this node and on the closest contacts. Values expire after the TTL of the node, and are
+// republished by this node until it is shut down, see republish.
 func (k *Kademlia) Put(id string, prvKey string, key string, value string, replicationFactor int, ctx context.Context) error {
+	err := k.putLocal(id, prvKey, key, value, ctx)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to put value locally")
+		return err
+	}
+
+	k.addPublication(id, prvKey, key, value, replicationFactor)
+
 	crypto := crypto.CreateCrypto()
 	hash := crypto.GenerateHash(id)
 
@@ -313,19 +384,17 @@ func (k *Kademlia) Put(id string, prvKey string, key string, value string, repli
 	}
 
 	if len(contacts) == 0 {
-		log.Info("No contacts found")
-		return errors.New("No contacts found")
+		log.WithFields(log.Fields{"Key": key}).Info("No contacts found, value only stored locally")
+		return nil
 	}
 
+	// A contact that has left the network must not prevent the value from being stored on the other contacts
 	for _, contact := range contacts {
 		err := k.putRemote(contact.Node, id, prvKey, key, value, ctx)
 		if err != nil {
-			log.WithFields(log.Fields{"Error": err}).Error("Failed to put value")
-			return err
+			log.WithFields(log.Fields{"Error": err, "Contact": contact.Node.String()}).Warn("Failed to put value")
+			continue
 		}
-	}
-
-	for _, contact := range contacts {
 		log.WithFields(log.Fields{"Contact": contact.Node.String(), "Key": key}).Info("Contact has stored value")
 	}
 
@@ -333,6 +402,14 @@ func (k *Kademlia) Put(id string, prvKey string, key string, value string, repli
 }
 
 func (k *Kademlia) Get(id string, key string, replicationFactor int, ctx context.Context) ([]KV, error) {
+	kvsMap := make(map[string]KV)
+	kvs, err := k.getLocal(id, key, ctx)
+	if err == nil {
+		for _, kv := range kvs {
+			kvsMap[kv.String()] = kv
+		}
+	}
+
 	crypto := crypto.CreateCrypto()
 	hash := crypto.GenerateHash(id)
 
@@ -342,12 +419,11 @@ func (k *Kademlia) Get(id string, key string, replicationFactor int, ctx context
 		return nil, err
 	}
 
-	if len(contacts) == 0 {
+	if len(contacts) == 0 && len(kvsMap) == 0 {
 		log.Info("No contacts found")
 		return nil, errors.New("No contacts found")
 	}
 
-	kvsMap := make(map[string]KV)
 	for _, contact := range contacts {
 		kvs, err := k.getRemote(contact.Node, id, key, ctx)
 		if err != nil {
@@ -16,7 +16,12 @@ func createRoutingTable(me Contact) *routingTable {
 	return routingTable
 }
 
+// addContact ignores the node itself, since a node never needs to look up or ping itself
 func (routingTable *routingTable) addContact(contact Contact) {
+	if contact.ID.Equals(routingTable.me.ID) {
+		return
+	}
+
 	bucketIndex := routingTable.getBucketIndex(contact.ID)
 	bucket := routingTable.buckets[bucketIndex]
 	bucket.addContact(contact)
@@ -34,6 +39,14 @@ func (routingTable *routingTable) getContact(id KademliaID) Contact {
 	return bucket.getContact(id)
 }
 
+func (routingTable *routingTable) getContacts() []Contact {
+	var contacts []Contact
+	for _, bucket := range routingTable.buckets {
+		contacts = append(contacts, bucket.getContacts()...)
+	}
+	return contacts
+}
+
 func (routingTable *routingTable) findClosestContacts(target KademliaID, count int) []Contact {
 	var candidates ContactCandidates
 	bucketIndex := routingTable.getBucketIndex(target)
@@ -1,11 +1,20 @@
 package dht
 
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
 const (
-	ADD_CONTACT   int = 0
-	FIND_CONTACTS int = 1
-	PUT           int = 2
-	GET           int = 3
-	STOP          int = 100
+	ADD_CONTACT    int = 0
+	FIND_CONTACTS  int = 1
+	PUT            int = 2
+	GET            int = 3
+	REMOVE_CONTACT int = 4
+	GET_CONTACTS   int = 5
+	EXPIRE         int = 6
+	STOP           int = 100
 )
 
 type states struct {
@@ -24,16 +33,27 @@ type job struct {
 	key          string
 	value        string
 	sig          string
+	ttl          int64
+	expires      time.Time
 	kvChan       chan []KV
 	errChan      chan error
 }
 
-func createStates(contact Contact) *states {
+// createStates replays the storage if set, otherwise values are only kept in memory
+func createStates(contact Contact, storage Storage) (*states, error) {
 	s := &states{}
 	s.jobQueue = make(chan job, 1000)
 	s.rt = createRoutingTable(contact)
-	s.kvs = createKVStore()
-	return s
+	if storage == nil {
+		s.kvs = createKVStore()
+	} else {
+		kvs, err := createKVStoreWithStorage(storage)
+		if err != nil {
+			return nil, err
+		}
+		s.kvs = kvs
+	}
+	return s, nil
 }
 
 func (s *states) serveForever() {
@@ -48,8 +68,20 @@ func (s *states) serveForever() {
 			job.contactsChan <- s.rt.findClosestContacts(CreateKademliaID(job.kademliaID), job.count)
 			close(job.contactsChan)
 			close(job.errChan)
+		case REMOVE_CONTACT:
+			s.rt.removeContact(job.contact)
+			job.errChan <- nil
+			close(job.errChan)
+		case GET_CONTACTS:
+			job.contactsChan <- s.rt.getContacts()
+			close(job.contactsChan)
+			close(job.errChan)
 		case PUT:
-			job.errChan <- s.kvs.put(job.id, job.key, job.value, job.sig)
+			job.errChan <- s.kvs.putWithExpiry(job.id, job.key, job.value, job.sig, job.ttl, job.expires)
+			close(job.errChan)
+		case EXPIRE:
+			s.kvs.removeExpired(job.expires)
+			job.errChan <- s.kvs.compactIfNeeded()
 			close(job.errChan)
 		case GET:
 			kvs, err := s.kvs.getAllValuesWithPrefix(job.value)
@@ -61,6 +93,10 @@ func (s *states) serveForever() {
 			close(job.kvChan)
 			close(job.errChan)
 		case STOP:
+			err := s.kvs.close()
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err}).Error("Failed to close DHT storage")
+			}
 			return
 		}
 	}
@@ -79,9 +115,30 @@ func (s *states) findContacts(kademliaID string, count int) (chan []Contact, cha
 	return contactsChan, errChan
 }
 
-func (s *states) put(id string, key string, value string, sig string) chan error {
+func (s *states) removeContact(contact Contact) chan error {
+	errChan := make(chan error, 1)
+	s.jobQueue <- job{jobType: REMOVE_CONTACT, contact: contact, errChan: errChan}
+	return errChan
+}
+
+func (s *states) getContacts() (chan []Contact, chan error) {
+	contactsChan := make(chan []Contact, 1)
+	errChan := make(chan error, 1)
+	s.jobQueue <- job{jobType: GET_CONTACTS, contactsChan: contactsChan, errChan: errChan}
+	return contactsChan, errChan
+}
+
+// put stores a value until the expiry time, a zero expiry time means that the value never expires
+func (s *states) put(id string, key string, value string, sig string, ttl int64, expires time.Time) chan error {
+	errChan := make(chan error, 1)
+	s.jobQueue <- job{jobType: PUT, id: id, key: key, value: value, sig: sig, ttl: ttl, expires: expires, errChan: errChan}
+	return errChan
+}
+
+// expire removes all values that expired before now
+func (s *states) expire(now time.Time) chan error {
 	errChan := make(chan error, 1)
-	s.jobQueue <- job{jobType: PUT, id: id, key: key, value: value, sig: sig, errChan: errChan}
+	s.jobQueue <- job{jobType: EXPIRE, expires: now, errChan: errChan}
 	return errChan
 }
 
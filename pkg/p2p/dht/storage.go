@@ -0,0 +1,32 @@
+package dht
+
+import "time"
+
+// Record is a key-value pair stored by a node together with its expiry time, a zero expiry time means that the value
+// never expires
+type Record struct {
+	KV      KV        `json:"kv"`
+	Expires time.Time `json:"expires"`
+}
+
+// Storage persists the key-value pairs stored by a node, so that the values a node is responsible for survive
+// restarts. Only the states goroutine accesses the storage.
+type Storage interface {
+	// Load replays the storage and returns all records that have been put and not removed
+	Load() ([]Record, error)
+
+	// Put stores a record, replacing any record with the same key
+	Put(record Record) error
+
+	// Remove removes the record with the given key
+	Remove(key string) error
+
+	// Compact replaces the content of the storage with the given records
+	Compact(records []Record) error
+
+	// Size returns the number of entries in the storage, which is larger than the number of records until the
+	// storage has been compacted
+	Size() int
+
+	Close() error
+}
@@ -57,13 +57,9 @@ func CreateMessengerWithBindAddr(bindAddr string, port int, name string) (*Messe
 }
 
 func (m *Messenger) Send(msg p2p.Message, ctx context.Context) error {
-	host, err := libp2p.New(
-		libp2p.NATPortMap(),
-		libp2p.EnableRelay())
-	if err != nil {
-		return err
-	}
-
+	// The listening host is reused for outgoing streams, creating a new host for every message is slow and leaks
+	// connections
+	host := m.host
 	addrStr := msg.To.Addr
 
 	addr, err := ma.NewMultiaddr(addrStr)
@@ -85,6 +81,7 @@ func (m *Messenger) Send(msg p2p.Message, ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	defer stream.Close()
 
 	data, err := json.Marshal(msg)
 	if err != nil {
@@ -106,35 +103,40 @@ func (m *Messenger) Send(msg p2p.Message, ctx context.Context) error {
 }
 
 func (m *Messenger) ListenForever(msgChan chan p2p.Message, ctx context.Context) error {
-	for {
-		m.host.SetStreamHandler("/colonies/1.0.0", func(stream network.Stream) {
-			var msg p2p.Message
-			r := bufio.NewReader(stream)
+	m.host.SetStreamHandler("/colonies/1.0.0", func(stream network.Stream) {
+		defer stream.Close()
+
+		var msg p2p.Message
+		r := bufio.NewReader(stream)
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			lengthBytes := make([]byte, 4)
+			_, err := io.ReadFull(r, lengthBytes)
+			if err != nil {
+				return
+			}
+			length := binary.BigEndian.Uint32(lengthBytes)
 
-			select {
-			case <-ctx.Done():
+			data := make([]byte, length)
+			_, err = io.ReadFull(r, data)
+			if err != nil {
 				return
-			default:
-				lengthBytes := make([]byte, 4)
-				_, err := io.ReadFull(r, lengthBytes)
-				if err != nil {
-					return
-				}
-				length := binary.BigEndian.Uint32(lengthBytes)
-
-				data := make([]byte, length)
-				_, err = io.ReadFull(r, data)
-				if err != nil {
-					return
-				}
-
-				err = json.Unmarshal(data, &msg)
-				if err != nil {
-					return
-				}
-
-				msgChan <- msg
 			}
-		})
-	}
+
+			err = json.Unmarshal(data, &msg)
+			if err != nil {
+				return
+			}
+
+			msgChan <- msg
+		}
+	})
+
+	<-ctx.Done()
+	m.host.RemoveStreamHandler("/colonies/1.0.0")
+
+	return nil
 }
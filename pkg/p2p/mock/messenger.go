@@ -9,10 +9,15 @@ import (
 type MockMessenger struct {
 	network Network
 	node    p2p.Node
+	socket  Socket
+	err     error
 }
 
+// CreateMessenger starts listening directly, so that messages sent to the node before ListenForever is called are
+// not lost
 func CreateMessenger(network Network, node p2p.Node) *MockMessenger {
-	return &MockMessenger{network: network, node: node}
+	socket, err := network.Listen(node.String())
+	return &MockMessenger{network: network, node: node, socket: socket, err: err}
 }
 
 func (m *MockMessenger) Send(msg p2p.Message, ctx context.Context) error {
@@ -25,13 +30,12 @@ func (m *MockMessenger) Send(msg p2p.Message, ctx context.Context) error {
 }
 
 func (m *MockMessenger) ListenForever(msgChan chan p2p.Message, ctx context.Context) error {
-	socket, err := m.network.Listen(m.node.String())
-	if err != nil {
-		return err
+	if m.err != nil {
+		return m.err
 	}
 
 	for {
-		msg, _ := socket.Receive(ctx)
+		msg, _ := m.socket.Receive(ctx)
 		select {
 		case <-ctx.Done():
 			return nil
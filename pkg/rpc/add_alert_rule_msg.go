@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const AddAlertRulePayloadType = "addalertrulemsg"
+
+type AddAlertRuleMsg struct {
+	AlertRule *core.AlertRule `json:"alertrule"`
+	MsgType   string          `json:"msgtype"`
+}
+
+func CreateAddAlertRuleMsg(rule *core.AlertRule) *AddAlertRuleMsg {
+	msg := &AddAlertRuleMsg{}
+	msg.AlertRule = rule
+	msg.MsgType = AddAlertRulePayloadType
+
+	return msg
+}
+
+func (msg *AddAlertRuleMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *AddAlertRuleMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *AddAlertRuleMsg) Equals(msg2 *AddAlertRuleMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.AlertRule.Equals(msg2.AlertRule) {
+		return true
+	}
+
+	return false
+}
+
+func CreateAddAlertRuleMsgFromJSON(jsonString string) (*AddAlertRuleMsg, error) {
+	var msg *AddAlertRuleMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
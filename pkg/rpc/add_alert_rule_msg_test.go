@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestAlertRule() *core.AlertRule {
+	return core.CreateAlertRule("test_colony", "test_rule", core.WaitingProcessesAlert, "test_executor_type", "", 10, 60, 0)
+}
+
+func TestRPCAddAlertRuleMsg(t *testing.T) {
+	msg := CreateAddAlertRuleMsg(createTestAlertRule())
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddAlertRuleMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateAddAlertRuleMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCAddAlertRuleMsgIndent(t *testing.T) {
+	msg := CreateAddAlertRuleMsg(createTestAlertRule())
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddAlertRuleMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateAddAlertRuleMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCAddAlertRuleMsgEquals(t *testing.T) {
+	msg := CreateAddAlertRuleMsg(createTestAlertRule())
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+
+	rule := createTestAlertRule()
+	rule.Threshold = 20
+	assert.False(t, msg.Equals(CreateAddAlertRuleMsg(rule)))
+}
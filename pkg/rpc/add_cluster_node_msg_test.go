@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/cluster"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCAddClusterNodeMsg(t *testing.T) {
+	node := cluster.Node{Name: "etcd2", Host: "localhost", EtcdClientPort: 24200, EtcdPeerPort: 23200, RelayPort: 25200, APIPort: 26200}
+	msg := CreateAddClusterNodeMsg(node)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddClusterNodeMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateAddClusterNodeMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.True(t, node.Equals(&msg2.Node))
+}
+
+func TestRPCAddClusterNodeMsgIndent(t *testing.T) {
+	node := cluster.Node{Name: "etcd2", Host: "localhost", EtcdClientPort: 24200, EtcdPeerPort: 23200, RelayPort: 25200, APIPort: 26200}
+	msg := CreateAddClusterNodeMsg(node)
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddClusterNodeMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateAddClusterNodeMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCAddClusterNodeMsgEquals(t *testing.T) {
+	node := cluster.Node{Name: "etcd2", Host: "localhost", EtcdClientPort: 24200, EtcdPeerPort: 23200, RelayPort: 25200, APIPort: 26200}
+	node2 := cluster.Node{Name: "etcd3", Host: "localhost", EtcdClientPort: 24300, EtcdPeerPort: 23300, RelayPort: 25300, APIPort: 26300}
+	msg := CreateAddClusterNodeMsg(node)
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+	assert.False(t, msg.Equals(CreateAddClusterNodeMsg(node2)))
+}
@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const AddFederationPeerPayloadType = "addfederationpeermsg"
+
+type AddFederationPeerMsg struct {
+	FederationPeer *core.FederationPeer `json:"federationpeer"`
+	MsgType        string               `json:"msgtype"`
+}
+
+func CreateAddFederationPeerMsg(peer *core.FederationPeer) *AddFederationPeerMsg {
+	msg := &AddFederationPeerMsg{}
+	msg.FederationPeer = peer
+	msg.MsgType = AddFederationPeerPayloadType
+
+	return msg
+}
+
+func (msg *AddFederationPeerMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *AddFederationPeerMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *AddFederationPeerMsg) Equals(msg2 *AddFederationPeerMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.FederationPeer.Equals(msg2.FederationPeer) {
+		return true
+	}
+
+	return false
+}
+
+func CreateAddFederationPeerMsgFromJSON(jsonString string) (*AddFederationPeerMsg, error) {
+	var msg *AddFederationPeerMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
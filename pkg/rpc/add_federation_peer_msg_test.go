@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestFederationPeer() *core.FederationPeer {
+	return core.CreateFederationPeer("test_colony", "test_peer", core.GenerateRandomID(), "localhost", 50080, true, "test_remote_colony", []string{"test_executor_type"}, []string{})
+}
+
+func TestRPCAddFederationPeerMsg(t *testing.T) {
+	msg := CreateAddFederationPeerMsg(createTestFederationPeer())
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddFederationPeerMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateAddFederationPeerMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCAddFederationPeerMsgIndent(t *testing.T) {
+	msg := CreateAddFederationPeerMsg(createTestFederationPeer())
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddFederationPeerMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateAddFederationPeerMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCAddFederationPeerMsgEquals(t *testing.T) {
+	msg := CreateAddFederationPeerMsg(createTestFederationPeer())
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+	assert.False(t, msg.Equals(CreateAddFederationPeerMsg(createTestFederationPeer())))
+}
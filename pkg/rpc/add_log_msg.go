@@ -7,9 +7,12 @@ import (
 const AddLogPayloadType = "addlogmsg"
 
 type AddLogMsg struct {
-	ProcessID string `json:"processid"`
-	Message   string `json:"message"`
-	MsgType   string `json:"msgtype"`
+	ProcessID string            `json:"processid"`
+	Message   string            `json:"message"`
+	Level     string            `json:"level"`  // Defaults to info
+	Stream    string            `json:"stream"` // stdout, stderr or empty
+	Fields    map[string]string `json:"fields"`
+	MsgType   string            `json:"msgtype"`
 }
 
 func CreateAddLogMsg(processID string, logmsg string) *AddLogMsg {
@@ -35,7 +38,12 @@ func (msg *AddLogMsg) Equals(msg2 *AddLogMsg) bool {
 		return false
 	}
 
-	if msg.MsgType == msg2.MsgType && msg.ProcessID == msg2.ProcessID && msg.Message == msg2.Message {
+	if msg.MsgType == msg2.MsgType &&
+		msg.ProcessID == msg2.ProcessID &&
+		msg.Message == msg2.Message &&
+		msg.Level == msg2.Level &&
+		msg.Stream == msg2.Stream &&
+		isStringMapEqual(msg.Fields, msg2.Fields) {
 		return true
 	}
 
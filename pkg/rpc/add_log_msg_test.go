@@ -9,6 +9,9 @@ import (
 
 func TestRPCAddLogMsg(t *testing.T) {
 	msg := CreateAddLogMsg(core.GenerateRandomID(), "test_msg")
+	msg.Level = core.LogLevelWarn
+	msg.Stream = core.LogStreamStdout
+	msg.Fields = map[string]string{"key": "value"}
 	jsonString, err := msg.ToJSON()
 	assert.Nil(t, err)
 
@@ -39,4 +42,10 @@ func TestRPCAddLogMsgEquals(t *testing.T) {
 	msg := CreateAddLogMsg(core.GenerateRandomID(), "test_msg")
 	assert.True(t, msg.Equals(msg))
 	assert.False(t, msg.Equals(nil))
+
+	msg2 := CreateAddLogMsg(msg.ProcessID, "test_msg")
+	msg2.Level = core.LogLevelError
+	msg2.Stream = core.LogStreamStderr
+	msg2.Fields = map[string]string{"key": "value"}
+	assert.False(t, msg.Equals(msg2))
 }
@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestWebhook() *core.Webhook {
+	return core.CreateWebhook("test_colony", "test_webhook", "http://localhost/hook", []string{core.ProcessGraphEventKind}, "", "test_secret")
+}
+
+func TestRPCAddWebhookMsg(t *testing.T) {
+	msg := CreateAddWebhookMsg(createTestWebhook())
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddWebhookMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateAddWebhookMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCAddWebhookMsgIndent(t *testing.T) {
+	msg := CreateAddWebhookMsg(createTestWebhook())
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddWebhookMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateAddWebhookMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCAddWebhookMsgEquals(t *testing.T) {
+	msg := CreateAddWebhookMsg(createTestWebhook())
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+
+	webhook := createTestWebhook()
+	webhook.URL = "http://localhost/another_hook"
+	assert.False(t, msg.Equals(CreateAddWebhookMsg(webhook)))
+}
@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const DrainServerPayloadType = "drainservermsg"
+
+type DrainServerMsg struct {
+	MsgType string `json:"msgtype"`
+	Timeout int    `json:"timeout"`
+}
+
+func CreateDrainServerMsg(timeout int) *DrainServerMsg {
+	msg := &DrainServerMsg{}
+	msg.MsgType = DrainServerPayloadType
+	msg.Timeout = timeout
+
+	return msg
+}
+
+func (msg *DrainServerMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *DrainServerMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *DrainServerMsg) Equals(msg2 *DrainServerMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.Timeout == msg2.Timeout {
+		return true
+	}
+
+	return false
+}
+
+func CreateDrainServerMsgFromJSON(jsonString string) (*DrainServerMsg, error) {
+	var msg *DrainServerMsg
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
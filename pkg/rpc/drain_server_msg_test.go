@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCDrainServerMsg(t *testing.T) {
+	msg := CreateDrainServerMsg(60)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateDrainServerMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateDrainServerMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, 60, msg2.Timeout)
+}
+
+func TestRPCDrainServerMsgIndent(t *testing.T) {
+	msg := CreateDrainServerMsg(60)
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateDrainServerMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateDrainServerMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCDrainServerMsgEquals(t *testing.T) {
+	msg := CreateDrainServerMsg(60)
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+	assert.False(t, msg.Equals(CreateDrainServerMsg(30)))
+}
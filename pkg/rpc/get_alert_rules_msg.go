@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetAlertRulesPayloadType = "getalertrulesmsg"
+
+type GetAlertRulesMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+}
+
+func CreateGetAlertRulesMsg(colonyName string) *GetAlertRulesMsg {
+	msg := &GetAlertRulesMsg{}
+	msg.MsgType = GetAlertRulesPayloadType
+	msg.ColonyName = colonyName
+	return msg
+}
+
+func (msg *GetAlertRulesMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetAlertRulesMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetAlertRulesMsg) Equals(msg2 *GetAlertRulesMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ColonyName == msg2.ColonyName {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetAlertRulesMsgFromJSON(jsonString string) (*GetAlertRulesMsg, error) {
+	var msg *GetAlertRulesMsg
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetAlertRulesMsg(t *testing.T) {
+	msg := CreateGetAlertRulesMsg("test_colony")
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetAlertRulesMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetAlertRulesMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, "test_colony", msg2.ColonyName)
+}
+
+func TestRPCGetAlertRulesMsgIndent(t *testing.T) {
+	msg := CreateGetAlertRulesMsg("test_colony")
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetAlertRulesMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetAlertRulesMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetAlertRulesMsgEquals(t *testing.T) {
+	msg := CreateGetAlertRulesMsg("test_colony")
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+	assert.False(t, msg.Equals(CreateGetAlertRulesMsg("different_colony")))
+}
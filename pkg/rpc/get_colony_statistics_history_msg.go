@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const GetColonyStatisticsHistoryPayloadType = "getcolonystatshistorymsg"
+
+type GetColonyStatisticsHistoryMsg struct {
+	ColonyName string    `json:"colonyname"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	MsgType    string    `json:"msgtype"`
+}
+
+func CreateGetColonyStatisticsHistoryMsg(colonyName string, from time.Time, to time.Time) *GetColonyStatisticsHistoryMsg {
+	msg := &GetColonyStatisticsHistoryMsg{}
+	msg.ColonyName = colonyName
+	msg.From = from
+	msg.To = to
+	msg.MsgType = GetColonyStatisticsHistoryPayloadType
+
+	return msg
+}
+
+func (msg *GetColonyStatisticsHistoryMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetColonyStatisticsHistoryMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetColonyStatisticsHistoryMsg) Equals(msg2 *GetColonyStatisticsHistoryMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.From.Equal(msg2.From) &&
+		msg.To.Equal(msg2.To) {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetColonyStatisticsHistoryMsgFromJSON(jsonString string) (*GetColonyStatisticsHistoryMsg, error) {
+	var msg *GetColonyStatisticsHistoryMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
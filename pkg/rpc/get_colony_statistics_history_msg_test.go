@@ -0,0 +1,46 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetColonyStatisticsHistoryMsg(t *testing.T) {
+	now := time.Now()
+	msg := CreateGetColonyStatisticsHistoryMsg(core.GenerateRandomID(), now.Add(-time.Hour), now)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetColonyStatisticsHistoryMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetColonyStatisticsHistoryMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetColonyStatisticsHistoryMsgIndent(t *testing.T) {
+	now := time.Now()
+	msg := CreateGetColonyStatisticsHistoryMsg(core.GenerateRandomID(), now.Add(-time.Hour), now)
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetColonyStatisticsHistoryMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetColonyStatisticsHistoryMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetColonyStatisticsHistoryMsgEquals(t *testing.T) {
+	now := time.Now()
+	msg := CreateGetColonyStatisticsHistoryMsg(core.GenerateRandomID(), now.Add(-time.Hour), now)
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+}
@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const GetColonyUsagePayloadType = "getcolonyusagemsg"
+
+type GetColonyUsageMsg struct {
+	ColonyName string    `json:"colonyname"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	GroupBy    string    `json:"groupby"`
+	MsgType    string    `json:"msgtype"`
+}
+
+func CreateGetColonyUsageMsg(colonyName string, from time.Time, to time.Time, groupBy string) *GetColonyUsageMsg {
+	msg := &GetColonyUsageMsg{}
+	msg.ColonyName = colonyName
+	msg.From = from
+	msg.To = to
+	msg.GroupBy = groupBy
+	msg.MsgType = GetColonyUsagePayloadType
+
+	return msg
+}
+
+func (msg *GetColonyUsageMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetColonyUsageMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetColonyUsageMsg) Equals(msg2 *GetColonyUsageMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.From.Equal(msg2.From) &&
+		msg.To.Equal(msg2.To) &&
+		msg.GroupBy == msg2.GroupBy {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetColonyUsageMsgFromJSON(jsonString string) (*GetColonyUsageMsg, error) {
+	var msg *GetColonyUsageMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,46 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetColonyUsageMsg(t *testing.T) {
+	now := time.Now()
+	msg := CreateGetColonyUsageMsg(core.GenerateRandomID(), now.Add(-time.Hour), now, core.UsageByUser)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetColonyUsageMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetColonyUsageMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetColonyUsageMsgIndent(t *testing.T) {
+	now := time.Now()
+	msg := CreateGetColonyUsageMsg(core.GenerateRandomID(), now.Add(-time.Hour), now, core.UsageByUser)
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetColonyUsageMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetColonyUsageMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetColonyUsageMsgEquals(t *testing.T) {
+	now := time.Now()
+	msg := CreateGetColonyUsageMsg(core.GenerateRandomID(), now.Add(-time.Hour), now, core.UsageByUser)
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+}
@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetFederatedProcessPayloadType = "getfederatedprocessmsg"
+
+type GetFederatedProcessMsg struct {
+	ProcessID string `json:"processid"`
+	MsgType   string `json:"msgtype"`
+}
+
+func CreateGetFederatedProcessMsg(processID string) *GetFederatedProcessMsg {
+	msg := &GetFederatedProcessMsg{}
+	msg.ProcessID = processID
+	msg.MsgType = GetFederatedProcessPayloadType
+
+	return msg
+}
+
+func (msg *GetFederatedProcessMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetFederatedProcessMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetFederatedProcessMsg) Equals(msg2 *GetFederatedProcessMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ProcessID == msg2.ProcessID {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetFederatedProcessMsgFromJSON(jsonString string) (*GetFederatedProcessMsg, error) {
+	var msg *GetFederatedProcessMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
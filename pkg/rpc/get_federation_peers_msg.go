@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetFederationPeersPayloadType = "getfederationpeersmsg"
+
+type GetFederationPeersMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+}
+
+func CreateGetFederationPeersMsg(colonyName string) *GetFederationPeersMsg {
+	msg := &GetFederationPeersMsg{}
+	msg.MsgType = GetFederationPeersPayloadType
+	msg.ColonyName = colonyName
+	return msg
+}
+
+func (msg *GetFederationPeersMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetFederationPeersMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetFederationPeersMsg) Equals(msg2 *GetFederationPeersMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ColonyName == msg2.ColonyName {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetFederationPeersMsgFromJSON(jsonString string) (*GetFederationPeersMsg, error) {
+	var msg *GetFederationPeersMsg
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
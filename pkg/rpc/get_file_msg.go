@@ -12,6 +12,7 @@ type GetFileMsg struct {
 	Label      string `json:"label"`
 	Name       string `json:"name"`
 	Latest     bool   `json:"latest"`
+	AsOf       int64  `json:"asof"`
 	MsgType    string `json:"msgtype"`
 }
 
@@ -27,6 +28,17 @@ func CreateGetFileMsg(colonyName string, fileID string, label string, name strin
 	return msg
 }
 
+func CreateGetFileAsOfMsg(colonyName string, label string, name string, asOf int64) *GetFileMsg {
+	msg := &GetFileMsg{}
+	msg.ColonyName = colonyName
+	msg.Label = label
+	msg.Name = name
+	msg.AsOf = asOf
+	msg.MsgType = GetFilePayloadType
+
+	return msg
+}
+
 func (msg *GetFileMsg) ToJSON() (string, error) {
 	jsonBytes, err := json.Marshal(msg)
 	if err != nil {
@@ -46,7 +58,8 @@ func (msg *GetFileMsg) Equals(msg2 *GetFileMsg) bool {
 		msg.FileID == msg2.FileID &&
 		msg.Label == msg2.Label &&
 		msg.Name == msg2.Name &&
-		msg.Latest == msg2.Latest {
+		msg.Latest == msg2.Latest &&
+		msg.AsOf == msg2.AsOf {
 		return true
 	}
 
@@ -39,3 +39,16 @@ func TestRPCGetFileMsgEquals(t *testing.T) {
 	assert.True(t, msg.Equals(msg))
 	assert.False(t, msg.Equals(nil))
 }
+
+func TestRPCGetFileAsOfMsg(t *testing.T) {
+	msg := CreateGetFileAsOfMsg("test_colony", "test_prefix", "test_name", 1234)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetFileMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, msg2.AsOf, int64(1234))
+	assert.False(t, msg.Equals(CreateGetFileMsg("test_colony", "", "test_prefix", "test_name", false)))
+}
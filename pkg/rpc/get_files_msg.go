@@ -9,6 +9,7 @@ const GetFilesPayloadType = "getfilesmsg"
 type GetFilesMsg struct {
 	Label      string `json:"label"`
 	ColonyName string `json:"colonyname"`
+	AsOf       int64  `json:"asof"`
 	MsgType    string `json:"msgtype"`
 }
 
@@ -21,6 +22,16 @@ func CreateGetFilesMsg(colonyName string, label string) *GetFilesMsg {
 	return msg
 }
 
+func CreateGetFilesAsOfMsg(colonyName string, label string, asOf int64) *GetFilesMsg {
+	msg := &GetFilesMsg{}
+	msg.ColonyName = colonyName
+	msg.Label = label
+	msg.AsOf = asOf
+	msg.MsgType = GetFilesPayloadType
+
+	return msg
+}
+
 func (msg *GetFilesMsg) ToJSON() (string, error) {
 	jsonBytes, err := json.Marshal(msg)
 	if err != nil {
@@ -35,7 +46,7 @@ func (msg *GetFilesMsg) Equals(msg2 *GetFilesMsg) bool {
 		return false
 	}
 
-	if msg.MsgType == msg2.MsgType && msg.ColonyName == msg2.ColonyName && msg.Label == msg2.Label {
+	if msg.MsgType == msg2.MsgType && msg.ColonyName == msg2.ColonyName && msg.Label == msg2.Label && msg.AsOf == msg2.AsOf {
 		return true
 	}
 
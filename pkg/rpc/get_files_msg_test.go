@@ -39,3 +39,16 @@ func TestRPCGetFilesMsgEquals(t *testing.T) {
 	assert.True(t, msg.Equals(msg))
 	assert.False(t, msg.Equals(nil))
 }
+
+func TestRPCGetFilesAsOfMsg(t *testing.T) {
+	msg := CreateGetFilesAsOfMsg("test_colony", "test_prefix", 1234)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetFilesMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, msg2.AsOf, int64(1234))
+	assert.False(t, msg.Equals(CreateGetFilesMsg("test_colony", "test_prefix")))
+}
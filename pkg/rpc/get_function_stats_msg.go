@@ -0,0 +1,74 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetFunctionStatsPayloadType = "getfunctionstatsmsg"
+
+type GetFunctionStatsMsg struct {
+	ColonyName    string `json:"colonyname"`
+	FuncName      string `json:"funcname"`      // Empty matches all functions
+	ExecutorType  string `json:"executortype"`  // Empty matches all executor types
+	Seconds       int    `json:"seconds"`       // Period back in time to calculate statistics for
+	BucketSeconds int    `json:"bucketseconds"` // Length of each bucket, 0 returns no buckets
+	MsgType       string `json:"msgtype"`
+}
+
+func CreateGetFunctionStatsMsg(colonyName string, funcName string, executorType string, seconds int, bucketSeconds int) *GetFunctionStatsMsg {
+	msg := &GetFunctionStatsMsg{}
+	msg.ColonyName = colonyName
+	msg.FuncName = funcName
+	msg.ExecutorType = executorType
+	msg.Seconds = seconds
+	msg.BucketSeconds = bucketSeconds
+	msg.MsgType = GetFunctionStatsPayloadType
+
+	return msg
+}
+
+func (msg *GetFunctionStatsMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetFunctionStatsMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetFunctionStatsMsg) Equals(msg2 *GetFunctionStatsMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.FuncName == msg2.FuncName &&
+		msg.ExecutorType == msg2.ExecutorType &&
+		msg.Seconds == msg2.Seconds &&
+		msg.BucketSeconds == msg2.BucketSeconds {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetFunctionStatsMsgFromJSON(jsonString string) (*GetFunctionStatsMsg, error) {
+	var msg *GetFunctionStatsMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
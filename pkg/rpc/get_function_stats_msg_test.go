@@ -0,0 +1,45 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetFunctionStatsMsg(t *testing.T) {
+	msg := CreateGetFunctionStatsMsg(core.GenerateRandomID(), "test_func", "test_executor_type", 86400, 3600)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetFunctionStatsMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetFunctionStatsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetFunctionStatsMsgIndent(t *testing.T) {
+	msg := CreateGetFunctionStatsMsg(core.GenerateRandomID(), "test_func", "test_executor_type", 86400, 3600)
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetFunctionStatsMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetFunctionStatsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetFunctionStatsMsgEquals(t *testing.T) {
+	msg := CreateGetFunctionStatsMsg(core.GenerateRandomID(), "test_func", "test_executor_type", 86400, 3600)
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+
+	msg2 := CreateGetFunctionStatsMsg(msg.ColonyName, "test_func", "test_executor_type", 86400, 60)
+	assert.False(t, msg.Equals(msg2))
+}
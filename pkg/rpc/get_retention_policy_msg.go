@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetRetentionPolicyPayloadType = "getretentionpolicymsg"
+
+type GetRetentionPolicyMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+}
+
+func CreateGetRetentionPolicyMsg(colonyName string) *GetRetentionPolicyMsg {
+	msg := &GetRetentionPolicyMsg{}
+	msg.MsgType = GetRetentionPolicyPayloadType
+	msg.ColonyName = colonyName
+	return msg
+}
+
+func (msg *GetRetentionPolicyMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetRetentionPolicyMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetRetentionPolicyMsg) Equals(msg2 *GetRetentionPolicyMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ColonyName == msg2.ColonyName {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetRetentionPolicyMsgFromJSON(jsonString string) (*GetRetentionPolicyMsg, error) {
+	var msg *GetRetentionPolicyMsg
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetRetentionPolicyMsg(t *testing.T) {
+	colonyName := "test_colony"
+	msg := CreateGetRetentionPolicyMsg(colonyName)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetRetentionPolicyMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetRetentionPolicyMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, colonyName, msg2.ColonyName)
+}
+
+func TestRPCGetRetentionPolicyMsgIndent(t *testing.T) {
+	colonyName := "test_colony"
+	msg := CreateGetRetentionPolicyMsg(colonyName)
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetRetentionPolicyMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetRetentionPolicyMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, colonyName, msg2.ColonyName)
+}
+
+func TestRPCGetRetentionPolicyMsgEquals(t *testing.T) {
+	colonyName := "test_colony"
+	msg1 := CreateGetRetentionPolicyMsg(colonyName)
+	msg2 := CreateGetRetentionPolicyMsg(colonyName)
+
+	assert.True(t, msg1.Equals(msg2))
+	assert.False(t, msg1.Equals(nil))
+
+	// Test different message type
+	msg3 := &GetRetentionPolicyMsg{MsgType: "different", ColonyName: colonyName}
+	assert.False(t, msg1.Equals(msg3))
+
+	// Test different colony name
+	msg4 := CreateGetRetentionPolicyMsg("different_colony")
+	assert.False(t, msg1.Equals(msg4))
+}
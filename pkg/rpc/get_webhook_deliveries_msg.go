@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetWebhookDeliveriesPayloadType = "getwebhookdeliveriesmsg"
+
+type GetWebhookDeliveriesMsg struct {
+	MsgType   string `json:"msgtype"`
+	WebhookID string `json:"webhookid"`
+	Count     int    `json:"count"`
+}
+
+func CreateGetWebhookDeliveriesMsg(webhookID string, count int) *GetWebhookDeliveriesMsg {
+	msg := &GetWebhookDeliveriesMsg{}
+	msg.MsgType = GetWebhookDeliveriesPayloadType
+	msg.WebhookID = webhookID
+	msg.Count = count
+	return msg
+}
+
+func (msg *GetWebhookDeliveriesMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetWebhookDeliveriesMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetWebhookDeliveriesMsg) Equals(msg2 *GetWebhookDeliveriesMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.WebhookID == msg2.WebhookID && msg.Count == msg2.Count {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetWebhookDeliveriesMsgFromJSON(jsonString string) (*GetWebhookDeliveriesMsg, error) {
+	var msg *GetWebhookDeliveriesMsg
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
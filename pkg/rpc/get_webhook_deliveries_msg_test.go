@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetWebhookDeliveriesMsg(t *testing.T) {
+	webhookID := core.GenerateRandomID()
+	msg := CreateGetWebhookDeliveriesMsg(webhookID, 10)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetWebhookDeliveriesMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetWebhookDeliveriesMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, webhookID, msg2.WebhookID)
+	assert.Equal(t, 10, msg2.Count)
+}
+
+func TestRPCGetWebhookDeliveriesMsgIndent(t *testing.T) {
+	msg := CreateGetWebhookDeliveriesMsg(core.GenerateRandomID(), 10)
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetWebhookDeliveriesMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateGetWebhookDeliveriesMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetWebhookDeliveriesMsgEquals(t *testing.T) {
+	webhookID := core.GenerateRandomID()
+	msg := CreateGetWebhookDeliveriesMsg(webhookID, 10)
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+	assert.False(t, msg.Equals(CreateGetWebhookDeliveriesMsg(webhookID, 20)))
+	assert.False(t, msg.Equals(CreateGetWebhookDeliveriesMsg(core.GenerateRandomID(), 10)))
+}
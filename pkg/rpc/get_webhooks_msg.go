@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetWebhooksPayloadType = "getwebhooksmsg"
+
+type GetWebhooksMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+}
+
+func CreateGetWebhooksMsg(colonyName string) *GetWebhooksMsg {
+	msg := &GetWebhooksMsg{}
+	msg.MsgType = GetWebhooksPayloadType
+	msg.ColonyName = colonyName
+	return msg
+}
+
+func (msg *GetWebhooksMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetWebhooksMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetWebhooksMsg) Equals(msg2 *GetWebhooksMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ColonyName == msg2.ColonyName {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetWebhooksMsgFromJSON(jsonString string) (*GetWebhooksMsg, error) {
+	var msg *GetWebhooksMsg
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const RemoveAlertRulePayloadType = "removealertrulemsg"
+
+type RemoveAlertRuleMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+	Name       string `json:"name"`
+}
+
+func CreateRemoveAlertRuleMsg(colonyName string, name string) *RemoveAlertRuleMsg {
+	msg := &RemoveAlertRuleMsg{}
+	msg.MsgType = RemoveAlertRulePayloadType
+	msg.ColonyName = colonyName
+	msg.Name = name
+	return msg
+}
+
+func (msg *RemoveAlertRuleMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveAlertRuleMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveAlertRuleMsg) Equals(msg2 *RemoveAlertRuleMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ColonyName == msg2.ColonyName && msg.Name == msg2.Name {
+		return true
+	}
+
+	return false
+}
+
+func CreateRemoveAlertRuleMsgFromJSON(jsonString string) (*RemoveAlertRuleMsg, error) {
+	var msg *RemoveAlertRuleMsg
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCRemoveAlertRuleMsg(t *testing.T) {
+	msg := CreateRemoveAlertRuleMsg("test_colony", "test_rule")
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveAlertRuleMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateRemoveAlertRuleMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, "test_colony", msg2.ColonyName)
+	assert.Equal(t, "test_rule", msg2.Name)
+}
+
+func TestRPCRemoveAlertRuleMsgIndent(t *testing.T) {
+	msg := CreateRemoveAlertRuleMsg("test_colony", "test_rule")
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveAlertRuleMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateRemoveAlertRuleMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCRemoveAlertRuleMsgEquals(t *testing.T) {
+	msg := CreateRemoveAlertRuleMsg("test_colony", "test_rule")
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+	assert.False(t, msg.Equals(CreateRemoveAlertRuleMsg("test_colony", "another_rule")))
+}
@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCRemoveClusterNodeMsg(t *testing.T) {
+	msg := CreateRemoveClusterNodeMsg("etcd2")
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveClusterNodeMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateRemoveClusterNodeMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, "etcd2", msg2.Name)
+}
+
+func TestRPCRemoveClusterNodeMsgIndent(t *testing.T) {
+	msg := CreateRemoveClusterNodeMsg("etcd2")
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveClusterNodeMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateRemoveClusterNodeMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCRemoveClusterNodeMsgEquals(t *testing.T) {
+	msg := CreateRemoveClusterNodeMsg("etcd2")
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+	assert.False(t, msg.Equals(CreateRemoveClusterNodeMsg("etcd3")))
+}
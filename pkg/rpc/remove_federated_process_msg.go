@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const RemoveFederatedProcessPayloadType = "removefederatedprocessmsg"
+
+type RemoveFederatedProcessMsg struct {
+	ProcessID string `json:"processid"`
+	MsgType   string `json:"msgtype"`
+}
+
+func CreateRemoveFederatedProcessMsg(processID string) *RemoveFederatedProcessMsg {
+	msg := &RemoveFederatedProcessMsg{}
+	msg.ProcessID = processID
+	msg.MsgType = RemoveFederatedProcessPayloadType
+
+	return msg
+}
+
+func (msg *RemoveFederatedProcessMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveFederatedProcessMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveFederatedProcessMsg) Equals(msg2 *RemoveFederatedProcessMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ProcessID == msg2.ProcessID {
+		return true
+	}
+
+	return false
+}
+
+func CreateRemoveFederatedProcessMsgFromJSON(jsonString string) (*RemoveFederatedProcessMsg, error) {
+	var msg *RemoveFederatedProcessMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const RemoveFederationPeerPayloadType = "removefederationpeermsg"
+
+type RemoveFederationPeerMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+	Name       string `json:"name"`
+}
+
+func CreateRemoveFederationPeerMsg(colonyName string, name string) *RemoveFederationPeerMsg {
+	msg := &RemoveFederationPeerMsg{}
+	msg.MsgType = RemoveFederationPeerPayloadType
+	msg.ColonyName = colonyName
+	msg.Name = name
+	return msg
+}
+
+func (msg *RemoveFederationPeerMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveFederationPeerMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveFederationPeerMsg) Equals(msg2 *RemoveFederationPeerMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ColonyName == msg2.ColonyName && msg.Name == msg2.Name {
+		return true
+	}
+
+	return false
+}
+
+func CreateRemoveFederationPeerMsgFromJSON(jsonString string) (*RemoveFederationPeerMsg, error) {
+	var msg *RemoveFederationPeerMsg
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
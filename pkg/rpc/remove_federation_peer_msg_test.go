@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCRemoveFederationPeerMsg(t *testing.T) {
+	msg := CreateRemoveFederationPeerMsg("test_colony", "test_peer")
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveFederationPeerMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateRemoveFederationPeerMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, "test_colony", msg2.ColonyName)
+	assert.Equal(t, "test_peer", msg2.Name)
+}
+
+func TestRPCRemoveFederationPeerMsgIndent(t *testing.T) {
+	msg := CreateRemoveFederationPeerMsg("test_colony", "test_peer")
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveFederationPeerMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateRemoveFederationPeerMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCRemoveFederationPeerMsgEquals(t *testing.T) {
+	msg := CreateRemoveFederationPeerMsg("test_colony", "test_peer")
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+	assert.False(t, msg.Equals(CreateRemoveFederationPeerMsg("test_colony", "another_peer")))
+}
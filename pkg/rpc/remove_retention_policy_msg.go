@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const RemoveRetentionPolicyPayloadType = "removeretentionpolicymsg"
+
+type RemoveRetentionPolicyMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+}
+
+func CreateRemoveRetentionPolicyMsg(colonyName string) *RemoveRetentionPolicyMsg {
+	msg := &RemoveRetentionPolicyMsg{}
+	msg.MsgType = RemoveRetentionPolicyPayloadType
+	msg.ColonyName = colonyName
+	return msg
+}
+
+func (msg *RemoveRetentionPolicyMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveRetentionPolicyMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveRetentionPolicyMsg) Equals(msg2 *RemoveRetentionPolicyMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ColonyName == msg2.ColonyName {
+		return true
+	}
+
+	return false
+}
+
+func CreateRemoveRetentionPolicyMsgFromJSON(jsonString string) (*RemoveRetentionPolicyMsg, error) {
+	var msg *RemoveRetentionPolicyMsg
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
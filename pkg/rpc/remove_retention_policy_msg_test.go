@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCRemoveRetentionPolicyMsg(t *testing.T) {
+	colonyName := "test_colony"
+	msg := CreateRemoveRetentionPolicyMsg(colonyName)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveRetentionPolicyMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateRemoveRetentionPolicyMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, colonyName, msg2.ColonyName)
+}
+
+func TestRPCRemoveRetentionPolicyMsgIndent(t *testing.T) {
+	colonyName := "test_colony"
+	msg := CreateRemoveRetentionPolicyMsg(colonyName)
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveRetentionPolicyMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateRemoveRetentionPolicyMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, colonyName, msg2.ColonyName)
+}
+
+func TestRPCRemoveRetentionPolicyMsgEquals(t *testing.T) {
+	colonyName := "test_colony"
+	msg1 := CreateRemoveRetentionPolicyMsg(colonyName)
+	msg2 := CreateRemoveRetentionPolicyMsg(colonyName)
+
+	assert.True(t, msg1.Equals(msg2))
+	assert.False(t, msg1.Equals(nil))
+
+	// Test different message type
+	msg3 := &RemoveRetentionPolicyMsg{MsgType: "different", ColonyName: colonyName}
+	assert.False(t, msg1.Equals(msg3))
+
+	// Test different colony name
+	msg4 := CreateRemoveRetentionPolicyMsg("different_colony")
+	assert.False(t, msg1.Equals(msg4))
+}
@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const RemoveWebhookPayloadType = "removewebhookmsg"
+
+type RemoveWebhookMsg struct {
+	MsgType   string `json:"msgtype"`
+	WebhookID string `json:"webhookid"`
+}
+
+func CreateRemoveWebhookMsg(webhookID string) *RemoveWebhookMsg {
+	msg := &RemoveWebhookMsg{}
+	msg.MsgType = RemoveWebhookPayloadType
+	msg.WebhookID = webhookID
+	return msg
+}
+
+func (msg *RemoveWebhookMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveWebhookMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveWebhookMsg) Equals(msg2 *RemoveWebhookMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.WebhookID == msg2.WebhookID {
+		return true
+	}
+
+	return false
+}
+
+func CreateRemoveWebhookMsgFromJSON(jsonString string) (*RemoveWebhookMsg, error) {
+	var msg *RemoveWebhookMsg
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
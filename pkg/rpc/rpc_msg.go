@@ -12,6 +12,8 @@ type RPCMsg struct {
 	Signature   string `json:"signature"`
 	PayloadType string `json:"payloadtype"`
 	Payload     string `json:"payload"`
+	TraceParent string `json:"traceparent,omitempty"` // W3C trace context of the caller, not covered by the signature
+	TraceState  string `json:"tracestate,omitempty"`
 }
 
 func CreateRPCMsg(payloadType string, payload string, prvKey string) (*RPCMsg, error) {
@@ -79,7 +81,9 @@ func (msg *RPCMsg) Equals(msg2 *RPCMsg) bool {
 
 	if msg.Signature == msg2.Signature &&
 		msg.PayloadType == msg2.PayloadType &&
-		msg.Payload == msg2.Payload {
+		msg.Payload == msg2.Payload &&
+		msg.TraceParent == msg2.TraceParent &&
+		msg.TraceState == msg2.TraceState {
 		return true
 	}
 
@@ -75,3 +75,25 @@ func TestRPCMsgEquals(t *testing.T) {
 	assert.True(t, msg.Equals(msg))
 	assert.False(t, msg.Equals(nil))
 }
+
+func TestRPCMsgTraceContext(t *testing.T) {
+	msg, err := CreateInsecureRPCMsg("test_method", "test_payload")
+	assert.Nil(t, err)
+
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+	assert.NotContains(t, jsonString, "traceparent")
+
+	msg.TraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	msg.TraceState = "colonies=1"
+
+	jsonString, err = msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRPCMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+
+	msg2.TraceParent = ""
+	assert.False(t, msg.Equals(msg2))
+}
@@ -7,11 +7,13 @@ import (
 const SearchLogsPayloadType = "searchlogsmsg"
 
 type SearchLogsMsg struct {
-	ColonyName string `json:"colonyname"`
-	Text       string `json:"text"`
-	Days       int    `json:"days"`
-	Count      int    `json:"count"`
-	MsgType    string `json:"msgtype"`
+	ColonyName string            `json:"colonyname"`
+	Text       string            `json:"text"`
+	Level      string            `json:"level"`  // Minimum level, empty matches all levels
+	Fields     map[string]string `json:"fields"` // Logs must have all key/values
+	Days       int               `json:"days"`
+	Count      int               `json:"count"`
+	MsgType    string            `json:"msgtype"`
 }
 
 func CreateSearchLogsMsg(colonyName string, text string, days int, count int) *SearchLogsMsg {
@@ -39,7 +41,13 @@ func (msg *SearchLogsMsg) Equals(msg2 *SearchLogsMsg) bool {
 		return false
 	}
 
-	if msg.MsgType == msg2.MsgType && msg.ColonyName == msg2.ColonyName && msg.Text == msg2.Text && msg.Days == msg2.Days && msg.Count == msg2.Count {
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.Text == msg2.Text &&
+		msg.Level == msg2.Level &&
+		isStringMapEqual(msg.Fields, msg2.Fields) &&
+		msg.Days == msg2.Days &&
+		msg.Count == msg2.Count {
 		return true
 	}
 
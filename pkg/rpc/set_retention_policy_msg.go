@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const SetRetentionPolicyPayloadType = "setretentionpolicymsg"
+
+type SetRetentionPolicyMsg struct {
+	RetentionPolicy *core.RetentionPolicy `json:"retentionpolicy"`
+	MsgType         string                `json:"msgtype"`
+}
+
+func CreateSetRetentionPolicyMsg(policy *core.RetentionPolicy) *SetRetentionPolicyMsg {
+	msg := &SetRetentionPolicyMsg{}
+	msg.RetentionPolicy = policy
+	msg.MsgType = SetRetentionPolicyPayloadType
+
+	return msg
+}
+
+func (msg *SetRetentionPolicyMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SetRetentionPolicyMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SetRetentionPolicyMsg) Equals(msg2 *SetRetentionPolicyMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.RetentionPolicy.Equals(msg2.RetentionPolicy) {
+		return true
+	}
+
+	return false
+}
+
+func CreateSetRetentionPolicyMsgFromJSON(jsonString string) (*SetRetentionPolicyMsg, error) {
+	var msg *SetRetentionPolicyMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
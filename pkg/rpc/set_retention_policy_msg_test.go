@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestRetentionPolicy() *core.RetentionPolicy {
+	policy := core.CreateRetentionPolicy("test_colony")
+	policy.SuccessfulProcesses = 60
+	policy.FailedProcesses = 3600
+	policy.Archive = true
+	return policy
+}
+
+func TestRPCSetRetentionPolicyMsg(t *testing.T) {
+	msg := CreateSetRetentionPolicyMsg(createTestRetentionPolicy())
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateSetRetentionPolicyMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateSetRetentionPolicyMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCSetRetentionPolicyMsgIndent(t *testing.T) {
+	msg := CreateSetRetentionPolicyMsg(createTestRetentionPolicy())
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateSetRetentionPolicyMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateSetRetentionPolicyMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCSetRetentionPolicyMsgEquals(t *testing.T) {
+	msg := CreateSetRetentionPolicyMsg(createTestRetentionPolicy())
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+}
@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const SubmitFederatedProcessPayloadType = "submitfederatedprocessmsg"
+
+type SubmitFederatedProcessMsg struct {
+	FunctionSpec *core.FunctionSpec `json:"spec"`
+	MsgType      string             `json:"msgtype"`
+}
+
+func CreateSubmitFederatedProcessMsg(funcSpec *core.FunctionSpec) *SubmitFederatedProcessMsg {
+	msg := &SubmitFederatedProcessMsg{}
+	msg.FunctionSpec = funcSpec
+	msg.MsgType = SubmitFederatedProcessPayloadType
+
+	return msg
+}
+
+func (msg *SubmitFederatedProcessMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SubmitFederatedProcessMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SubmitFederatedProcessMsg) Equals(msg2 *SubmitFederatedProcessMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.FunctionSpec.Equals(msg2.FunctionSpec) {
+		return true
+	}
+
+	return false
+}
+
+func CreateSubmitFederatedProcessMsgFromJSON(jsonString string) (*SubmitFederatedProcessMsg, error) {
+	var msg *SubmitFederatedProcessMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
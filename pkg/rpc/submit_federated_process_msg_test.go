@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func createFederatedFunctionSpec() *core.FunctionSpec {
+	funcSpec := createFunctionSpec()
+	core.SetFederationPath(funcSpec, []string{core.GenerateRandomID()})
+
+	return funcSpec
+}
+
+func TestRPCSubmitFederatedProcessMsg(t *testing.T) {
+	msg := CreateSubmitFederatedProcessMsg(createFederatedFunctionSpec())
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateSubmitFederatedProcessMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateSubmitFederatedProcessMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Len(t, core.GetFederationPath(msg2.FunctionSpec), 1)
+}
+
+func TestRPCSubmitFederatedProcessMsgIndent(t *testing.T) {
+	msg := CreateSubmitFederatedProcessMsg(createFederatedFunctionSpec())
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateSubmitFederatedProcessMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateSubmitFederatedProcessMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCSubmitFederatedProcessMsgEquals(t *testing.T) {
+	msg := CreateSubmitFederatedProcessMsg(createFederatedFunctionSpec())
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+}
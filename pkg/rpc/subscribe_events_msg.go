@@ -0,0 +1,83 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const SubscribeEventsPayloadType = "subscribeeventsmsg"
+
+type SubscribeEventsMsg struct {
+	ColonyName string   `json:"colonyname"`
+	Types      []string `json:"types"`
+	Label      string   `json:"label"`
+	FromSeq    int64    `json:"fromseq"` // Replay stored events from this sequence number, 0 only sends new events
+	Timeout    int      `json:"timeout"`
+	MsgType    string   `json:"msgtype"`
+}
+
+func CreateSubscribeEventsMsg(colonyName string, types []string, label string, fromSeq int64, timeout int) *SubscribeEventsMsg {
+	msg := &SubscribeEventsMsg{}
+	msg.ColonyName = colonyName
+	msg.Types = types
+	msg.Label = label
+	msg.FromSeq = fromSeq
+	msg.Timeout = timeout
+	msg.MsgType = SubscribeEventsPayloadType
+
+	return msg
+}
+
+func (msg *SubscribeEventsMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SubscribeEventsMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SubscribeEventsMsg) Equals(msg2 *SubscribeEventsMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if len(msg.Types) != len(msg2.Types) {
+		return false
+	}
+
+	for i := range msg.Types {
+		if msg.Types[i] != msg2.Types[i] {
+			return false
+		}
+	}
+
+	if msg.ColonyName == msg2.ColonyName &&
+		msg.MsgType == msg2.MsgType &&
+		msg.Label == msg2.Label &&
+		msg.FromSeq == msg2.FromSeq &&
+		msg.Timeout == msg2.Timeout {
+		return true
+	}
+
+	return false
+}
+
+func CreateSubscribeEventsMsgFromJSON(jsonString string) (*SubscribeEventsMsg, error) {
+	var msg *SubscribeEventsMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
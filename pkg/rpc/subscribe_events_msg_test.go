@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCSubscribeEventsMsg(t *testing.T) {
+	msg := CreateSubscribeEventsMsg("test_colony_name", []string{"process", "file.added"}, "test_label", 10, 2)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateSubscribeEventsMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateSubscribeEventsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Equal(t, int64(10), msg2.FromSeq)
+}
+
+func TestRPCSubscribeEventsMsgIndent(t *testing.T) {
+	msg := CreateSubscribeEventsMsg("test_colony_name", []string{"process", "file.added"}, "test_label", 10, 2)
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateSubscribeEventsMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateSubscribeEventsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCSubscribeEventsMsgEquals(t *testing.T) {
+	msg := CreateSubscribeEventsMsg("test_colony_name", []string{"process"}, "", 0, 2)
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+
+	msg2 := CreateSubscribeEventsMsg("test_colony_name", []string{"file"}, "", 0, 2)
+	assert.False(t, msg.Equals(msg2))
+
+	msg3 := CreateSubscribeEventsMsg("test_colony_name", []string{"process"}, "", 10, 2)
+	assert.False(t, msg.Equals(msg3))
+}
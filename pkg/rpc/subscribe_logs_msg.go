@@ -0,0 +1,94 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const SubscribeLogsPayloadType = "subscribelogsmsg"
+
+type SubscribeLogsMsg struct {
+	ColonyName   string            `json:"colonyname"`
+	ProcessID    string            `json:"processid"`
+	ExecutorName string            `json:"executorname"`
+	Level        string            `json:"level"`  // Minimum level, empty matches all levels
+	Fields       map[string]string `json:"fields"` // Logs must have all key/values
+	Since        int64             `json:"since"`  // Replay stored logs added after this (unix nano) time, 0 only sends new logs
+	Timeout      int               `json:"timeout"`
+	MsgType      string            `json:"msgtype"`
+}
+
+func CreateSubscribeLogsMsg(colonyName string, processID string, executorName string, level string, fields map[string]string, since int64, timeout int) *SubscribeLogsMsg {
+	msg := &SubscribeLogsMsg{}
+	msg.ColonyName = colonyName
+	msg.ProcessID = processID
+	msg.ExecutorName = executorName
+	msg.Level = level
+	msg.Fields = fields
+	msg.Since = since
+	msg.Timeout = timeout
+	msg.MsgType = SubscribeLogsPayloadType
+
+	return msg
+}
+
+func (msg *SubscribeLogsMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SubscribeLogsMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SubscribeLogsMsg) Equals(msg2 *SubscribeLogsMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.ColonyName == msg2.ColonyName &&
+		msg.MsgType == msg2.MsgType &&
+		msg.ProcessID == msg2.ProcessID &&
+		msg.ExecutorName == msg2.ExecutorName &&
+		msg.Level == msg2.Level &&
+		isStringMapEqual(msg.Fields, msg2.Fields) &&
+		msg.Since == msg2.Since &&
+		msg.Timeout == msg2.Timeout {
+		return true
+	}
+
+	return false
+}
+
+func CreateSubscribeLogsMsgFromJSON(jsonString string) (*SubscribeLogsMsg, error) {
+	var msg *SubscribeLogsMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
+
+func isStringMapEqual(m1 map[string]string, m2 map[string]string) bool {
+	if len(m1) != len(m2) {
+		return false
+	}
+
+	for key, value := range m1 {
+		if value2, ok := m2[key]; !ok || value != value2 {
+			return false
+		}
+	}
+
+	return true
+}
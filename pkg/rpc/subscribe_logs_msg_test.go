@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCSubscribeLogsMsg(t *testing.T) {
+	msg := CreateSubscribeLogsMsg("test_colony_name", core.GenerateRandomID(), "test_executor_name", core.LogLevelWarn, map[string]string{"key": "value"}, 10, 2)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateSubscribeLogsMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateSubscribeLogsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCSubscribeLogsMsgIndent(t *testing.T) {
+	msg := CreateSubscribeLogsMsg("test_colony_name", core.GenerateRandomID(), "", "", nil, 0, 2)
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateSubscribeLogsMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateSubscribeLogsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCSubscribeLogsMsgEquals(t *testing.T) {
+	msg := CreateSubscribeLogsMsg("test_colony_name", "test_process_id", "", "", map[string]string{"key": "value"}, 0, 2)
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+
+	msg2 := CreateSubscribeLogsMsg("test_colony_name", "test_process_id", "", "", map[string]string{"key": "value2"}, 0, 2)
+	assert.False(t, msg.Equals(msg2))
+
+	msg3 := CreateSubscribeLogsMsg("test_colony_name", "test_process_id", "", core.LogLevelError, map[string]string{"key": "value"}, 0, 2)
+	assert.False(t, msg.Equals(msg3))
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+func (server *ColoniesServer) handleAddAlertRuleHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateAddAlertRuleMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to add alert rule, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to add alert rule, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.AlertRule == nil {
+		server.handleHTTPError(c, errors.New("Failed to add alert rule, alert rule is <nil>"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireColonyOwner(recoveredID, msg.AlertRule.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	rule := msg.AlertRule
+	if rule.Name == "" {
+		server.handleHTTPError(c, errors.New("Failed to add alert rule, name is empty"), http.StatusBadRequest)
+		return
+	}
+
+	if !core.IsValidAlertRuleType(rule.Type) {
+		server.handleHTTPError(c, errors.New("Failed to add alert rule, invalid type <"+rule.Type+">, must be "+core.WaitingProcessesAlert+", "+core.NoExecutorsAlert+" or "+core.FailureRateAlert), http.StatusBadRequest)
+		return
+	}
+
+	if rule.Threshold < 0 || rule.For < 0 || rule.Window < 0 {
+		server.handleHTTPError(c, errors.New("Failed to add alert rule, threshold, for and window must not be negative"), http.StatusBadRequest)
+		return
+	}
+
+	if rule.Type == core.FailureRateAlert && rule.Window == 0 {
+		server.handleHTTPError(c, errors.New("Failed to add alert rule, window must be set for failure rate rules"), http.StatusBadRequest)
+		return
+	}
+
+	existingRule, err := server.db.GetAlertRuleByName(rule.ColonyName, rule.Name)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if existingRule != nil {
+		server.handleHTTPError(c, errors.New("Failed to add alert rule, alert rule with name <"+rule.Name+"> already exists in colony <"+rule.ColonyName+">"), http.StatusBadRequest)
+		return
+	}
+
+	rule.ID = core.GenerateRandomID()
+	rule.State = core.AlertOK
+	rule.Value = 0
+	rule.PendingSince = time.Time{}
+	rule.FiredTime = time.Time{}
+	rule.ResolvedTime = time.Time{}
+	rule.Evaluated = time.Time{}
+	rule.Added = time.Now()
+
+	err = server.db.AddAlertRule(rule)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	jsonString, err = rule.ToJSON()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"AlertRuleID": rule.ID, "ColonyName": rule.ColonyName, "Name": rule.Name, "Type": rule.Type}).Debug("Adding alert rule")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+func (server *ColoniesServer) handleGetAlertRulesHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetAlertRulesMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to get alert rules, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to get alert rules, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireMembership(recoveredID, msg.ColonyName, true)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	rules, err := server.db.GetAlertRulesByColonyName(msg.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	jsonString, err = core.ConvertAlertRuleArrayToJSON(rules)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName}).Debug("Getting alert rules")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+func (server *ColoniesServer) handleRemoveAlertRuleHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRemoveAlertRuleMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to remove alert rule, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to remove alert rule, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireColonyOwner(recoveredID, msg.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	rule, err := server.db.GetAlertRuleByName(msg.ColonyName, msg.Name)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if rule == nil {
+		server.handleHTTPError(c, errors.New("Failed to remove alert rule, alert rule with name <"+msg.Name+"> not found in colony <"+msg.ColonyName+">"), http.StatusNotFound)
+		return
+	}
+
+	err = server.db.RemoveAlertRuleByName(msg.ColonyName, msg.Name)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"AlertRuleID": rule.ID, "ColonyName": rule.ColonyName, "Name": rule.Name}).Debug("Removing alert rule")
+
+	server.sendEmptyHTTPReply(c, payloadType)
+}
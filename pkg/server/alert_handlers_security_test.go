@@ -0,0 +1,71 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddAlertRuleSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	rule := core.CreateAlertRule(env.colony1Name, "test_rule", core.WaitingProcessesAlert, "", "", 10, 60, 0)
+
+	_, err := client.AddAlertRule(rule, env.executor1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.AddAlertRule(rule, env.colony2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.AddAlertRule(rule, env.colony1PrvKey)
+	assert.Nil(t, err) // Should work
+
+	server.Shutdown()
+	<-done
+}
+
+func TestGetAlertRulesSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	_, err := client.GetAlertRules(env.colony1Name, env.executor2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.GetAlertRules(env.colony1Name, env.executor1PrvKey)
+	assert.Nil(t, err) // Should work
+
+	server.Shutdown()
+	<-done
+}
+
+func TestRemoveAlertRuleSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	rule := core.CreateAlertRule(env.colony1Name, "test_rule", core.WaitingProcessesAlert, "", "", 10, 60, 0)
+	_, err := client.AddAlertRule(rule, env.colony1PrvKey)
+	assert.Nil(t, err)
+
+	err = client.RemoveAlertRule(env.colony1Name, "test_rule", env.executor1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	err = client.RemoveAlertRule(env.colony1Name, "test_rule", env.colony2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	err = client.RemoveAlertRule(env.colony1Name, "test_rule", env.colony1PrvKey)
+	assert.Nil(t, err) // Should work
+
+	server.Shutdown()
+	<-done
+}
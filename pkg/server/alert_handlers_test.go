@@ -0,0 +1,144 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddAlertRule(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	rule := core.CreateAlertRule(env.colonyName, "test_rule", core.WaitingProcessesAlert, "test_executor_type", "", 10, 60, 0)
+	addedRule, err := client.AddAlertRule(rule, env.colonyPrvKey)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, addedRule.ID)
+	assert.Equal(t, core.AlertOK, addedRule.State)
+
+	rules, err := client.GetAlertRules(env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, rules, 1)
+	assert.True(t, addedRule.Equals(rules[0]))
+
+	_, err = client.AddAlertRule(rule, env.colonyPrvKey)
+	assert.NotNil(t, err) // Name already exists
+
+	invalidRule := core.CreateAlertRule(env.colonyName, "invalid_rule", "invalid_type", "", "", 10, 60, 0)
+	_, err = client.AddAlertRule(invalidRule, env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	invalidRule = core.CreateAlertRule(env.colonyName, "", core.WaitingProcessesAlert, "", "", 10, 60, 0)
+	_, err = client.AddAlertRule(invalidRule, env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	invalidRule = core.CreateAlertRule(env.colonyName, "invalid_rule", core.WaitingProcessesAlert, "", "", -1, 60, 0)
+	_, err = client.AddAlertRule(invalidRule, env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	invalidRule = core.CreateAlertRule(env.colonyName, "invalid_rule", core.FailureRateAlert, "", "", 0.5, 60, 0)
+	_, err = client.AddAlertRule(invalidRule, env.colonyPrvKey)
+	assert.NotNil(t, err) // Window not set
+
+	err = client.RemoveAlertRule(env.colonyName, "test_rule", env.colonyPrvKey)
+	assert.Nil(t, err)
+
+	rules, err = client.GetAlertRules(env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, rules, 0)
+
+	err = client.RemoveAlertRule(env.colonyName, "test_rule", env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
+
+func waitForAlertEvent(t *testing.T, subscription *client.EventSubscription) *core.Event {
+	select {
+	case event := <-subscription.EventChan:
+		return event
+	case err := <-subscription.ErrChan:
+		assert.Fail(t, err.Error())
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "Timed out waiting for alert event")
+	}
+
+	return nil
+}
+
+func TestEvaluateAlertRules(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	_, err := client.AddAlertRule(core.CreateAlertRule(env.colonyName, "waiting", core.WaitingProcessesAlert, "test_executor_type", "", 0, 0, 0), env.colonyPrvKey)
+	assert.Nil(t, err)
+	_, err = client.AddAlertRule(core.CreateAlertRule(env.colonyName, "noexecutors", core.NoExecutorsAlert, "another_executor_type", "", 0, 0, 0), env.colonyPrvKey)
+	assert.Nil(t, err)
+	_, err = client.AddAlertRule(core.CreateAlertRule(env.colonyName, "failurerate", core.FailureRateAlert, "", "test_func", 0.5, 1, 3600), env.colonyPrvKey)
+	assert.Nil(t, err)
+
+	subscription, err := client.SubscribeEvents(env.colonyName, []string{core.AlertEventKind}, "", 0, 100, env.executorPrvKey)
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+
+	_, err = client.Submit(utils.CreateTestFunctionSpec(env.colonyName), env.executorPrvKey)
+	assert.Nil(t, err)
+
+	server.controller.evaluateAlertRules(time.Now())
+
+	fired := make(map[string]*core.Event)
+	for i := 0; i < 2; i++ {
+		event := waitForAlertEvent(t, subscription)
+		assert.Equal(t, core.AlertFiringEvent, event.Type)
+		fired[event.AlertRule.Name] = event
+	}
+	assert.Contains(t, fired, "waiting")
+	assert.Contains(t, fired, "noexecutors")
+	assert.Equal(t, float64(1), fired["waiting"].AlertRule.Value)
+
+	rules, err := client.GetAlertRules(env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	for _, rule := range rules {
+		if rule.Name == "failurerate" {
+			assert.Equal(t, core.AlertOK, rule.State) // No function runs yet
+		} else {
+			assert.Equal(t, core.AlertFiring, rule.State)
+		}
+	}
+
+	process, err := client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+	err = client.Fail(process.ID, []string{"error"}, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	server.controller.evaluateAlertRules(time.Now())
+
+	event := waitForAlertEvent(t, subscription)
+	assert.Equal(t, core.AlertResolvedEvent, event.Type)
+	assert.Equal(t, "waiting", event.AlertRule.Name)
+	assert.Equal(t, core.AlertOK, event.AlertRule.State)
+
+	rules, err = client.GetAlertRules(env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	for _, rule := range rules {
+		if rule.Name == "failurerate" {
+			assert.Equal(t, core.AlertPending, rule.State)
+			assert.Equal(t, float64(1), rule.Value)
+		}
+	}
+
+	// The failure rate alert fires when the condition has held for a second
+	server.controller.evaluateAlertRules(time.Now().Add(2 * time.Second))
+
+	event = waitForAlertEvent(t, subscription)
+	assert.Equal(t, core.AlertFiringEvent, event.Type)
+	assert.Equal(t, "failurerate", event.AlertRule.Name)
+
+	subscription.Close()
+
+	server.Shutdown()
+	<-done
+}
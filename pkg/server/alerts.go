@@ -0,0 +1,148 @@
+package server
+
+import (
+	"errors"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// measureAlertRule returns the current value of the metric an alert rule is evaluated against. Waiting process
+// counts are passed in since they are counted for all colonies at once.
+func (controller *coloniesController) measureAlertRule(rule *core.AlertRule, queueDepths map[string]map[string]int, now time.Time) (float64, error) {
+	switch rule.Type {
+	case core.WaitingProcessesAlert:
+		if rule.ExecutorType != "" {
+			return float64(queueDepths[rule.ColonyName][rule.ExecutorType]), nil
+		}
+		waiting := 0
+		for _, count := range queueDepths[rule.ColonyName] {
+			waiting += count
+		}
+		return float64(waiting), nil
+	case core.NoExecutorsAlert:
+		executors, err := controller.db.GetExecutorsByColonyName(rule.ColonyName)
+		if err != nil {
+			return 0, err
+		}
+		alive := 0
+		for _, executor := range executors {
+			if rule.ExecutorType != "" && executor.Type != rule.ExecutorType {
+				continue
+			}
+			if executor.State == core.APPROVED && now.Sub(executor.LastHeardFromTime) <= ALERT_EXECUTOR_ALIVE_PERIOD {
+				alive++
+			}
+		}
+		return float64(alive), nil
+	case core.FailureRateAlert:
+		runs, err := controller.db.GetFunctionRunsBetween(rule.ColonyName, now.Add(-time.Duration(rule.Window)*time.Second), now, MAX_FUNCTION_RUNS)
+		if err != nil {
+			return 0, err
+		}
+		total := 0
+		failed := 0
+		for _, run := range runs {
+			if rule.ExecutorType != "" && run.ExecutorType != rule.ExecutorType {
+				continue
+			}
+			if rule.FuncName != "" && run.FuncName != rule.FuncName {
+				continue
+			}
+			total++
+			if !run.Successful {
+				failed++
+			}
+		}
+		if total == 0 {
+			return 0, nil
+		}
+		return float64(failed) / float64(total), nil
+	}
+
+	return 0, errors.New("Invalid alert rule type <" + rule.Type + ">")
+}
+
+// evaluateAlertRules evaluates the alert rules of all colonies and publishes an event for every alert that fired or
+// was resolved. The state of the rules is stored, so that a new leader continues where the previous leader stopped.
+func (controller *coloniesController) evaluateAlertRules(now time.Time) {
+	colonies, err := controller.db.GetColonies()
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to get colonies, cannot evaluate alert rules")
+		return
+	}
+
+	depths, err := controller.db.CountWaitingProcessesByExecutorType()
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to count waiting processes, cannot evaluate alert rules")
+		return
+	}
+
+	queueDepths := make(map[string]map[string]int)
+	for _, depth := range depths {
+		if queueDepths[depth.ColonyName] == nil {
+			queueDepths[depth.ColonyName] = make(map[string]int)
+		}
+		queueDepths[depth.ColonyName][depth.ExecutorType] = depth.Count
+	}
+
+	for _, colony := range colonies {
+		rules, err := controller.db.GetAlertRulesByColonyName(colony.Name)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ColonyName": colony.Name}).Error("Failed to get alert rules")
+			continue
+		}
+
+		for _, rule := range rules {
+			value, err := controller.measureAlertRule(rule, queueDepths, now)
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err, "ColonyName": rule.ColonyName, "AlertRule": rule.Name}).Error("Failed to evaluate alert rule")
+				continue
+			}
+
+			eventType := rule.Evaluate(value, now)
+			err = controller.db.UpdateAlertRuleState(rule)
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err, "ColonyName": rule.ColonyName, "AlertRule": rule.Name}).Error("Failed to update alert rule state")
+				continue
+			}
+
+			if eventType == "" {
+				continue
+			}
+
+			fields := log.Fields{"ColonyName": rule.ColonyName, "AlertRule": rule.Name, "Type": rule.Type, "Value": rule.Value, "Threshold": rule.Threshold}
+			if eventType == core.AlertFiringEvent {
+				log.WithFields(fields).Warn("Alert firing")
+			} else {
+				log.WithFields(fields).Info("Alert resolved")
+			}
+			controller.eventHandler.publish(core.CreateAlertEvent(eventType, rule))
+		}
+	}
+}
+
+// alertWorker periodically evaluates all alert rules. Only the leader evaluates rules so that every alert fires once.
+func (controller *coloniesController) alertWorker() {
+	var lastEvaluation time.Time
+	for {
+		time.Sleep(time.Second)
+
+		controller.stopMutex.Lock()
+		stopped := controller.stopFlag
+		controller.stopMutex.Unlock()
+		if stopped {
+			return
+		}
+
+		if time.Since(lastEvaluation) < ALERT_EVALUATION_PERIOD {
+			continue
+		}
+
+		controller.runJob(ALERT_JOB, func() {
+			lastEvaluation = time.Now()
+			controller.evaluateAlertRules(lastEvaluation)
+		})
+	}
+}
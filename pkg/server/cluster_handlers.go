@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+func (server *ColoniesServer) handleAddClusterNodeHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateAddClusterNodeMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to add cluster node, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to add cluster node, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	serverID, err := server.getServerID()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	err = server.validator.RequireServerOwner(recoveredID, serverID)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	node := msg.Node
+	if node.Name == "" || node.Host == "" {
+		server.handleHTTPError(c, errors.New("Failed to add cluster node, name and host must be set"), http.StatusBadRequest)
+		return
+	}
+
+	if node.EtcdClientPort <= 0 || node.EtcdPeerPort <= 0 || node.RelayPort <= 0 || node.APIPort <= 0 {
+		server.handleHTTPError(c, errors.New("Failed to add cluster node, all ports must be set"), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), CLUSTER_MEMBERSHIP_TIMEOUT)
+	defer cancel()
+
+	etcdServer := server.controller.getEtcdServer()
+	err = etcdServer.AddMember(ctx, node)
+	if server.handleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	cluster := etcdServer.CurrentCluster()
+	jsonString, err = cluster.ToJSON()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"Name": node.Name, "Host": node.Host}).Info("Adding cluster node")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+func (server *ColoniesServer) handleRemoveClusterNodeHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRemoveClusterNodeMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to remove cluster node, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to remove cluster node, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	serverID, err := server.getServerID()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	err = server.validator.RequireServerOwner(recoveredID, serverID)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), CLUSTER_MEMBERSHIP_TIMEOUT)
+	defer cancel()
+
+	err = server.controller.getEtcdServer().RemoveMember(ctx, msg.Name)
+	if server.handleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	log.WithFields(log.Fields{"Name": msg.Name}).Info("Removing cluster node")
+
+	server.sendEmptyHTTPReply(c, payloadType)
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/cluster"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddClusterNodeSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	node := cluster.Node{Name: "etcd2", Host: "localhost", EtcdClientPort: 24250, EtcdPeerPort: 23250, RelayPort: 25250, APIPort: 26250}
+
+	_, err := client.AddClusterNode(node, env.executor1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.AddClusterNode(node, env.colony1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.AddClusterNode(node, env.colony2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	server.Shutdown()
+	<-done
+}
+
+func TestRemoveClusterNodeSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	thisNode := server.controller.getThisNode()
+
+	err := client.RemoveClusterNode(thisNode.Name, env.executor1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	err = client.RemoveClusterNode(thisNode.Name, env.colony1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	err = client.RemoveClusterNode(thisNode.Name, env.colony2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	server.Shutdown()
+	<-done
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/cluster"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddClusterNodeInvalid(t *testing.T) {
+	_, client, server, serverPrvKey, done := setupTestEnv2(t)
+
+	_, err := client.AddClusterNode(cluster.Node{Host: "localhost", EtcdClientPort: 24250, EtcdPeerPort: 23250, RelayPort: 25250, APIPort: 26250}, serverPrvKey)
+	assert.NotNil(t, err) // Name is missing
+
+	_, err = client.AddClusterNode(cluster.Node{Name: "etcd2", Host: "localhost"}, serverPrvKey)
+	assert.NotNil(t, err) // Ports are missing
+
+	thisNode := server.controller.getThisNode()
+	_, err = client.AddClusterNode(thisNode, serverPrvKey)
+	assert.NotNil(t, err) // Already a member
+
+	cluster, err := client.GetClusterInfo(serverPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, cluster.Nodes, 1)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestRemoveClusterNode(t *testing.T) {
+	_, client, server, serverPrvKey, done := setupTestEnv2(t)
+
+	err := client.RemoveClusterNode("does_not_exist", serverPrvKey)
+	assert.NotNil(t, err)
+
+	thisNode := server.controller.getThisNode()
+	err = client.RemoveClusterNode(thisNode.Name, serverPrvKey)
+	assert.NotNil(t, err) // Last member cannot leave
+
+	assert.True(t, server.controller.syncClusterMembership())
+
+	cluster, err := client.GetClusterInfo(serverPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, cluster.Nodes, 1)
+	assert.Equal(t, thisNode.Name, cluster.Leader.Name)
+
+	server.Shutdown()
+	<-done
+}
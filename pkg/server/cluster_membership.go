@@ -0,0 +1,72 @@
+package server
+
+import (
+	"time"
+
+	"github.com/colonyos/colonies/pkg/cluster"
+	log "github.com/sirupsen/logrus"
+)
+
+func sameNodes(nodes1 []cluster.Node, nodes2 []cluster.Node) bool {
+	if len(nodes1) != len(nodes2) {
+		return false
+	}
+
+	for _, node1 := range nodes1 {
+		found := false
+		for _, node2 := range nodes2 {
+			if node1.Equals(&node2) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// syncClusterMembership updates the nodes events are relayed to when nodes have joined or left the cluster. It
+// returns false if this node is no longer a member of the cluster.
+func (controller *coloniesController) syncClusterMembership() bool {
+	if !controller.etcdServer.IsMember() {
+		return false
+	}
+
+	nodes := controller.etcdServer.Members()
+	if len(nodes) == 0 || sameNodes(nodes, controller.relayServer.Nodes()) {
+		return true
+	}
+
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	log.WithFields(log.Fields{"Nodes": names}).Info("Cluster membership changed")
+	controller.relayServer.SetNodes(nodes)
+
+	return true
+}
+
+// clusterMembershipWorker runs on all nodes, since every node relays events to the other nodes
+func (controller *coloniesController) clusterMembershipWorker() {
+	member := true
+	for {
+		time.Sleep(CLUSTER_MEMBERSHIP_SYNC_PERIOD)
+
+		controller.stopMutex.Lock()
+		stopped := controller.stopFlag
+		controller.stopMutex.Unlock()
+		if stopped {
+			return
+		}
+
+		isMember := controller.syncClusterMembership()
+		if member && !isMember {
+			log.WithFields(log.Fields{"Name": controller.thisNode.Name}).Warn("This node has left the cluster, restart it with --join after adding it again")
+		}
+		member = isMember
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"math"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"github.com/colonyos/colonies/pkg/cluster"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/p2p/dht"
 	"github.com/colonyos/colonies/pkg/scheduler"
 	log "github.com/sirupsen/logrus"
 )
@@ -49,6 +51,8 @@ type command struct {
 	functionsReplyChan     chan []*core.Function
 	threaded               bool
 	handler                func(cmd *command)
+	ctx                    context.Context // Trace context of the request that issued the command, may be nil
+	name                   string
 }
 
 type coloniesController struct {
@@ -60,20 +64,48 @@ type coloniesController struct {
 	relayServer      *cluster.RelayServer
 	eventHandler     *eventHandler
 	stopFlag         bool
+	draining         bool
 	stopMutex        sync.Mutex
 	leaderMutex      sync.Mutex
 	thisNode         cluster.Node
 	clusterConfig    cluster.Config
 	etcdServer       *cluster.EtcdServer
+	jobElections     map[string]*cluster.JobElection
+	jobLeaderships   map[string]*jobLeadership // Verified leadership of jobs, see jobToken
+	jobLeadershipMux sync.Mutex
 	leader           bool
 	generatorPeriod  int
 	cronPeriod       int
 	retention        bool
 	retentionPolicy  int64
 	retentionPeriod  int
+	fileStore        FileStore
+	fileStoreMutex   sync.Mutex
+	webhookQueue     chan *webhookJob
+	webhookCtx       context.Context
+	webhookCancel    context.CancelFunc
+	webhookDone      chan struct{}
+	metrics          *serverMetrics
+	anomalyFactor    float64
+	anomalyFactorMux sync.Mutex
+	statsPeriod      time.Duration
+	statsPeriodMux   sync.Mutex
+	// DHT discovery, nil if the server does not publish its endpoint
+	discovery         dht.DHT
+	discoveryPrvKey   string
+	discoveryEndpoint *core.ServerEndpoint
+	discoveryMutex    sync.Mutex
+	// Federation, processes are only forwarded to peers if the server private key is set
+	federationPrvKey        string
+	federationSkipTLSVerify bool
+	federationMutex         sync.Mutex
 	// Pause channel management
 	pauseChannels    map[string][]chan bool // colony -> list of waiting channels
 	pauseChannelsMux sync.RWMutex
+	// Commands are scheduled fairly between colonies, see colony_queues.go
+	cmdScheduler         *colonyScheduler
+	blockingCmdScheduler *colonyScheduler
+	colonyLimits         *colonyLimits
 }
 
 func createColoniesController(db database.Database,
@@ -87,7 +119,8 @@ func createColoniesController(db database.Database,
 	retentionPeriod int) *coloniesController {
 
 	controller := &coloniesController{}
-	controller.db = db
+	controller.metrics = createServerMetrics()
+	controller.db = createInstrumentedDatabase(db, controller.metrics)
 	controller.thisNode = thisNode
 	controller.clusterConfig = clusterConfig
 	controller.etcdServer = cluster.CreateEtcdServer(controller.thisNode, controller.clusterConfig, etcdDataPath)
@@ -99,23 +132,43 @@ func createColoniesController(db database.Database,
 	controller.retention = retention
 	controller.retentionPolicy = retentionPolicy
 	controller.retentionPeriod = retentionPeriod
+	controller.anomalyFactor = DEFAULT_ANOMALY_FACTOR
+	controller.statsPeriod = STATISTICS_SNAPSHOT_PERIOD
 	controller.pauseChannels = make(map[string][]chan bool)
+	controller.webhookQueue = make(chan *webhookJob, webhookQueueSize)
+	controller.webhookCtx, controller.webhookCancel = context.WithCancel(context.Background())
+	controller.webhookDone = make(chan struct{})
 
 	controller.relayServer = cluster.CreateRelayServer(controller.thisNode, controller.clusterConfig)
-	controller.eventHandler = createEventHandler(controller.relayServer)
+	controller.eventHandler = createEventHandler(controller.relayServer, controller.db)
 	controller.wsSubCtrl = createWSSubscriptionController(controller.eventHandler)
 	controller.scheduler = scheduler.CreateScheduler(controller.db)
+	controller.metrics.registerClusterMetrics(controller.db, controller.tryBecomeLeader)
+	controller.metrics.registerRelayMetrics(controller.relayServer)
 
 	controller.cmdQueue = make(chan *command)
 	controller.blockingCmdQueue = make(chan *command)
+	controller.colonyLimits = createColonyLimits()
+	controller.cmdScheduler = createColonyScheduler(CONCURRENT_QUEUE, controller.colonyLimits, controller.metrics)
+	controller.blockingCmdScheduler = createColonyScheduler(SERIAL_QUEUE, controller.colonyLimits, controller.metrics)
+	controller.metrics.registerColonyQueueMetrics(controller.cmdScheduler, controller.blockingCmdScheduler)
 
 	controller.tryBecomeLeader()
+	controller.startJobElections()
 	go controller.blockingCmdQueueWorker()
 	go controller.cmdQueueWorker()
+	go controller.runScheduledCommands(controller.blockingCmdScheduler)
+	go controller.runScheduledCommands(controller.cmdScheduler)
 	go controller.timeoutLoop()
 	go controller.generatorTriggerLoop()
 	go controller.cronTriggerLoop()
 	go controller.retentionWorker()
+	go controller.webhookWorker()
+	go controller.statisticsWorker()
+	go controller.alertWorker()
+	go controller.clusterMembershipWorker()
+	go controller.discoveryWorker()
+	go controller.federationWorker()
 
 	return controller
 }
@@ -136,12 +189,16 @@ func (controller *coloniesController) getEventHandler() *eventHandler {
 	return controller.eventHandler
 }
 
+func (controller *coloniesController) getMetrics() *serverMetrics {
+	return controller.metrics
+}
+
 func (controller *coloniesController) getThisNode() cluster.Node {
 	return controller.thisNode
 }
 
-func (controller *coloniesController) subscribeProcesses(executorID string, subscription *subscription) error {
-	cmd := &command{threaded: false, errorChan: make(chan error, 1),
+func (controller *coloniesController) subscribeProcesses(executorID string, colonyName string, subscription *subscription) error {
+	cmd := &command{threaded: false, colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			controller.wsSubCtrl.addProcessesSubscriber(executorID, subscription)
 			cmd.errorChan <- nil
@@ -151,8 +208,8 @@ func (controller *coloniesController) subscribeProcesses(executorID string, subs
 	return <-cmd.errorChan
 }
 
-func (controller *coloniesController) subscribeProcess(executorID string, subscription *subscription) error {
-	cmd := &command{threaded: false, errorChan: make(chan error, 1),
+func (controller *coloniesController) subscribeProcess(executorID string, colonyName string, subscription *subscription) error {
+	cmd := &command{threaded: false, colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			process, err := controller.db.GetProcessByID(subscription.processID)
 			if err != nil {
@@ -172,6 +229,28 @@ func (controller *coloniesController) subscribeProcess(executorID string, subscr
 	return <-cmd.errorChan
 }
 
+func (controller *coloniesController) subscribeEvents(executorID string, subscription *subscription) error {
+	cmd := &command{threaded: false, colonyName: subscription.eventFilter.ColonyName, errorChan: make(chan error, 1),
+		handler: func(cmd *command) {
+			controller.wsSubCtrl.addEventsSubscriber(executorID, subscription)
+			cmd.errorChan <- nil
+		}}
+	controller.blockingCmdQueue <- cmd
+
+	return <-cmd.errorChan
+}
+
+func (controller *coloniesController) subscribeLogs(executorID string, subscription *subscription) error {
+	cmd := &command{threaded: false, colonyName: subscription.logFilter.ColonyName, errorChan: make(chan error, 1),
+		handler: func(cmd *command) {
+			controller.wsSubCtrl.addLogsSubscriber(executorID, subscription)
+			cmd.errorChan <- nil
+		}}
+	controller.blockingCmdQueue <- cmd
+
+	return <-cmd.errorChan
+}
+
 func (controller *coloniesController) getColonies() ([]*core.Colony, error) {
 	cmd := &command{threaded: true, coloniesReplyChan: make(chan []*core.Colony),
 		errorChan: make(chan error, 1),
@@ -195,7 +274,7 @@ func (controller *coloniesController) getColonies() ([]*core.Colony, error) {
 }
 
 func (controller *coloniesController) getColony(colonyName string) (*core.Colony, error) {
-	cmd := &command{threaded: true, colonyReplyChan: make(chan *core.Colony),
+	cmd := &command{threaded: true, colonyName: colonyName, colonyReplyChan: make(chan *core.Colony),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			colony, err := controller.db.GetColonyByName(colonyName)
@@ -247,7 +326,7 @@ func (controller *coloniesController) addColony(colony *core.Colony) (*core.Colo
 }
 
 func (controller *coloniesController) removeColony(colonyName string) error {
-	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+	cmd := &command{threaded: true, colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			err := controller.db.RemoveColonyByName(colonyName)
 			if err != nil {
@@ -262,7 +341,11 @@ func (controller *coloniesController) removeColony(colonyName string) error {
 }
 
 func (controller *coloniesController) addExecutor(executor *core.Executor, allowExecutorReregister bool) (*core.Executor, error) {
-	cmd := &command{threaded: true, executorReplyChan: make(chan *core.Executor, 1),
+	colonyName := ""
+	if executor != nil {
+		colonyName = executor.ColonyName
+	}
+	cmd := &command{threaded: true, colonyName: colonyName, executorReplyChan: make(chan *core.Executor, 1),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			if executor == nil {
@@ -333,7 +416,7 @@ func (controller *coloniesController) getExecutor(executorID string) (*core.Exec
 }
 
 func (controller *coloniesController) getExecutorByColonyName(colonyName string) ([]*core.Executor, error) {
-	cmd := &command{threaded: true, executorsReplyChan: make(chan []*core.Executor),
+	cmd := &command{threaded: true, colonyName: colonyName, executorsReplyChan: make(chan []*core.Executor),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			executors, err := controller.db.GetExecutorsByColonyName(colonyName)
@@ -372,10 +455,13 @@ func (controller *coloniesController) addProcessToDB(process *core.Process) (*co
 	return addedProcess, nil
 }
 
-func (controller *coloniesController) addProcess(process *core.Process) (*core.Process, error) {
-	cmd := &command{threaded: true, processReplyChan: make(chan *core.Process, 1),
+func (controller *coloniesController) addProcess(process *core.Process, ctx context.Context) (*core.Process, error) {
+	cmd := &command{threaded: true, colonyName: process.FunctionSpec.Conditions.ColonyName, processReplyChan: make(chan *core.Process, 1),
 		errorChan: make(chan error, 1),
+		ctx:       ctx,
+		name:      "addProcess",
 		handler: func(cmd *command) {
+			stampTraceContext(process, cmd.ctx)
 			addedProcess, err := controller.addProcessToDB(process)
 			if err != nil {
 				cmd.errorChan <- err
@@ -385,6 +471,7 @@ func (controller *coloniesController) addProcess(process *core.Process) (*core.P
 			if !addedProcess.WaitForParents {
 				controller.eventHandler.signal(addedProcess)
 			}
+			controller.eventHandler.publish(core.CreateProcessEvent(addedProcess))
 			cmd.processReplyChan <- addedProcess
 		}}
 
@@ -403,10 +490,14 @@ func (controller *coloniesController) addChild(
 	childProcessID string,
 	process *core.Process,
 	executorID string,
-	insert bool) (*core.Process, error) {
-	cmd := &command{threaded: false, processReplyChan: make(chan *core.Process, 1),
+	insert bool,
+	ctx context.Context) (*core.Process, error) {
+	cmd := &command{threaded: false, colonyName: process.FunctionSpec.Conditions.ColonyName, processReplyChan: make(chan *core.Process, 1),
 		errorChan: make(chan error, 1),
+		ctx:       ctx,
+		name:      "addChild",
 		handler: func(cmd *command) {
+			stampTraceContext(process, cmd.ctx)
 			process.WaitForParents = true
 			parentProcess, err := controller.db.GetProcessByID(parentProcessID)
 			if err != nil {
@@ -477,6 +568,7 @@ func (controller *coloniesController) addChild(
 			if !addedProcess.WaitForParents {
 				controller.eventHandler.signal(addedProcess)
 			}
+			controller.eventHandler.publish(core.CreateProcessEvent(addedProcess))
 
 			updatedProcess, err := controller.db.GetProcessByID(addedProcess.ID)
 			if err != nil {
@@ -518,7 +610,7 @@ func (controller *coloniesController) getProcess(processID string) (*core.Proces
 }
 
 func (controller *coloniesController) findProcessHistory(colonyName string, executorID string, seconds int, state int) ([]*core.Process, error) {
-	cmd := &command{threaded: true, processesReplyChan: make(chan []*core.Process),
+	cmd := &command{threaded: true, colonyName: colonyName, processesReplyChan: make(chan []*core.Process),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			var processes []*core.Process
@@ -554,7 +646,7 @@ func (controller *coloniesController) updateProcessGraph(graph *core.ProcessGrap
 	return graph.UpdateProcessIDs()
 }
 
-func (controller *coloniesController) createProcessGraph(workflowSpec *core.WorkflowSpec, args []interface{}, kwargs map[string]interface{}, rootInput []interface{}, recoveredID string) (*core.ProcessGraph, error) {
+func (controller *coloniesController) createProcessGraph(workflowSpec *core.WorkflowSpec, args []interface{}, kwargs map[string]interface{}, rootInput []interface{}, recoveredID string, ctx context.Context) (*core.ProcessGraph, error) {
 	processgraph, err := core.CreateProcessGraph(workflowSpec.ColonyName)
 	if err != nil {
 		log.WithFields(log.Fields{"Error": err}).Error("Failed to create processgraph")
@@ -579,6 +671,7 @@ func (controller *coloniesController) createProcessGraph(workflowSpec *core.Work
 			funcSpec.MaxExecTime = -1
 		}
 		process := core.CreateProcess(&funcSpec)
+		stampTraceContext(process, ctx)
 		log.WithFields(log.Fields{"ProcessId": process.ID, "MaxExecTime": process.FunctionSpec.MaxExecTime, "MaxRetries": process.FunctionSpec.MaxRetries}).Debug("Creating new process")
 		if len(funcSpec.Conditions.Dependencies) == 0 {
 			// The process is a root process, let it start immediately
@@ -671,16 +764,21 @@ func (controller *coloniesController) createProcessGraph(workflowSpec *core.Work
 		if !addedProcess.WaitForParents {
 			controller.eventHandler.signal(addedProcess)
 		}
+		controller.eventHandler.publish(core.CreateProcessEvent(addedProcess))
 	}
 
+	controller.eventHandler.publish(core.CreateProcessGraphEvent(processgraph))
+
 	return processgraph, nil
 }
 
-func (controller *coloniesController) submitWorkflowSpec(workflowSpec *core.WorkflowSpec, recoveredID string) (*core.ProcessGraph, error) {
-	cmd := &command{threaded: false, processGraphReplyChan: make(chan *core.ProcessGraph, 1),
+func (controller *coloniesController) submitWorkflowSpec(workflowSpec *core.WorkflowSpec, recoveredID string, ctx context.Context) (*core.ProcessGraph, error) {
+	cmd := &command{threaded: false, colonyName: workflowSpec.ColonyName, processGraphReplyChan: make(chan *core.ProcessGraph, 1),
 		errorChan: make(chan error, 1),
+		ctx:       ctx,
+		name:      "submitWorkflowSpec",
 		handler: func(cmd *command) {
-			addedProcessGraph, err := controller.createProcessGraph(workflowSpec, make([]interface{}, 0), make(map[string]interface{}), make([]interface{}, 0), recoveredID)
+			addedProcessGraph, err := controller.createProcessGraph(workflowSpec, make([]interface{}, 0), make(map[string]interface{}), make([]interface{}, 0), recoveredID, cmd.ctx)
 			if err != nil {
 				cmd.errorChan <- err
 				return
@@ -730,7 +828,7 @@ func (controller *coloniesController) getProcessGraphByID(processGraphID string)
 }
 
 func (controller *coloniesController) findWaitingProcessGraphs(colonyName string, count int) ([]*core.ProcessGraph, error) {
-	cmd := &command{threaded: true, processGraphsReplyChan: make(chan []*core.ProcessGraph),
+	cmd := &command{threaded: true, colonyName: colonyName, processGraphsReplyChan: make(chan []*core.ProcessGraph),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			if count > MAX_COUNT {
@@ -764,7 +862,7 @@ func (controller *coloniesController) findWaitingProcessGraphs(colonyName string
 }
 
 func (controller *coloniesController) findRunningProcessGraphs(colonyName string, count int) ([]*core.ProcessGraph, error) {
-	cmd := &command{threaded: true, processGraphsReplyChan: make(chan []*core.ProcessGraph),
+	cmd := &command{threaded: true, colonyName: colonyName, processGraphsReplyChan: make(chan []*core.ProcessGraph),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			if count > MAX_COUNT {
@@ -797,7 +895,7 @@ func (controller *coloniesController) findRunningProcessGraphs(colonyName string
 }
 
 func (controller *coloniesController) findSuccessfulProcessGraphs(colonyName string, count int) ([]*core.ProcessGraph, error) {
-	cmd := &command{threaded: true, processGraphsReplyChan: make(chan []*core.ProcessGraph),
+	cmd := &command{threaded: true, colonyName: colonyName, processGraphsReplyChan: make(chan []*core.ProcessGraph),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			if count > MAX_COUNT {
@@ -830,7 +928,7 @@ func (controller *coloniesController) findSuccessfulProcessGraphs(colonyName str
 }
 
 func (controller *coloniesController) findFailedProcessGraphs(colonyName string, count int) ([]*core.ProcessGraph, error) {
-	cmd := &command{threaded: true, processGraphsReplyChan: make(chan []*core.ProcessGraph),
+	cmd := &command{threaded: true, colonyName: colonyName, processGraphsReplyChan: make(chan []*core.ProcessGraph),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			if count > MAX_COUNT {
@@ -862,8 +960,8 @@ func (controller *coloniesController) findFailedProcessGraphs(colonyName string,
 	}
 }
 
-func (controller *coloniesController) removeProcess(processID string) error {
-	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+func (controller *coloniesController) removeProcess(processID string, colonyName string) error {
+	cmd := &command{threaded: true, colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			err := controller.db.RemoveProcessByID(processID)
 			cmd.errorChan <- err
@@ -874,7 +972,7 @@ func (controller *coloniesController) removeProcess(processID string) error {
 }
 
 func (controller *coloniesController) removeAllProcesses(colonyName string, state int) error {
-	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+	cmd := &command{threaded: true, colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			switch state {
 			case core.WAITING:
@@ -896,8 +994,8 @@ func (controller *coloniesController) removeAllProcesses(colonyName string, stat
 	return <-cmd.errorChan
 }
 
-func (controller *coloniesController) removeProcessGraph(processID string) error {
-	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+func (controller *coloniesController) removeProcessGraph(processID string, colonyName string) error {
+	cmd := &command{threaded: true, colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			err := controller.db.RemoveProcessGraphByID(processID)
 			cmd.errorChan <- err
@@ -908,7 +1006,7 @@ func (controller *coloniesController) removeProcessGraph(processID string) error
 }
 
 func (controller *coloniesController) removeAllProcessGraphs(colonyName string, state int) error {
-	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+	cmd := &command{threaded: true, colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			switch state {
 			case core.WAITING:
@@ -930,8 +1028,8 @@ func (controller *coloniesController) removeAllProcessGraphs(colonyName string,
 	return <-cmd.errorChan
 }
 
-func (controller *coloniesController) setOutput(processID string, output []interface{}) error {
-	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+func (controller *coloniesController) setOutput(processID string, colonyName string, output []interface{}) error {
+	cmd := &command{threaded: true, colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			if len(output) > 0 {
 				err := controller.db.SetOutput(processID, output)
@@ -947,8 +1045,10 @@ func (controller *coloniesController) setOutput(processID string, output []inter
 	return <-cmd.errorChan
 }
 
-func (controller *coloniesController) closeSuccessful(processID string, executorID string, output []interface{}) error {
-	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+func (controller *coloniesController) closeSuccessful(processID string, colonyName string, executorID string, output []interface{}, ctx context.Context) error {
+	cmd := &command{threaded: true, colonyName: colonyName, errorChan: make(chan error, 1),
+		ctx:  ctx,
+		name: "closeSuccessful",
 		handler: func(cmd *command) {
 			process, err := controller.db.GetProcessByID(processID)
 			if err != nil {
@@ -966,6 +1066,10 @@ func (controller *coloniesController) closeSuccessful(processID string, executor
 				return
 			}
 
+			controller.metrics.observeProcessExecTime(process, "successful", processingTime)
+			controller.recordFunctionRun(process, waitingTime, processingTime, true, time.Now())
+			traceProcessSpan(process, "colonies.process/exec", process.StartTime, time.Now(), cmd.ctx)
+
 			if process.ProcessGraphID != "" {
 				log.WithFields(log.Fields{"ProcessGraphId": process.ProcessGraphID}).Debug("Resolving processgraph (close successful)")
 				processGraph, err := controller.db.GetProcessGraphByID(process.ProcessGraphID)
@@ -974,6 +1078,7 @@ func (controller *coloniesController) closeSuccessful(processID string, executor
 					return
 				}
 				processGraph.SetStorage(controller.db)
+				graphState := processGraph.State
 				err = processGraph.Resolve()
 				if err != nil {
 					err2 := controller.handleDefunctProcessgraph(processGraph.ID, process.ID, err)
@@ -987,6 +1092,7 @@ func (controller *coloniesController) closeSuccessful(processID string, executor
 					cmd.errorChan <- err
 					return
 				}
+				controller.publishProcessGraphStateChange(processGraph, graphState)
 
 				// This is process is now closed. This means that children processes can now execute,
 				// assuming all their parents are closed successfully
@@ -1005,10 +1111,14 @@ func (controller *coloniesController) closeSuccessful(processID string, executor
 				return
 			}
 
-			function, err := controller.db.GetFunctionsByExecutorAndName(process.FunctionSpec.Conditions.ColonyName, executor.Name, process.FunctionSpec.FuncName)
-			if err != nil {
-				cmd.errorChan <- err
-				return
+			// Processes forwarded to federation peers are not assigned to a local executor
+			var function *core.Function
+			if executor != nil {
+				function, err = controller.db.GetFunctionsByExecutorAndName(process.FunctionSpec.Conditions.ColonyName, executor.Name, process.FunctionSpec.FuncName)
+				if err != nil {
+					cmd.errorChan <- err
+					return
+				}
 			}
 
 			if function != nil {
@@ -1068,6 +1178,7 @@ func (controller *coloniesController) closeSuccessful(processID string, executor
 			}
 
 			controller.eventHandler.signal(process)
+			controller.eventHandler.publish(core.CreateProcessEvent(process))
 			cmd.errorChan <- nil
 		}}
 
@@ -1102,8 +1213,10 @@ func (controller *coloniesController) notifyChildren(process *core.Process) erro
 	return nil
 }
 
-func (controller *coloniesController) closeFailed(processID string, errs []string) error {
-	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+func (controller *coloniesController) closeFailed(processID string, colonyName string, errs []string, ctx context.Context) error {
+	cmd := &command{threaded: true, colonyName: colonyName, errorChan: make(chan error, 1),
+		ctx:  ctx,
+		name: "closeFailed",
 		handler: func(cmd *command) {
 			err := controller.db.MarkFailed(processID, errs)
 			if err != nil {
@@ -1125,6 +1238,7 @@ func (controller *coloniesController) closeFailed(processID string, errs []strin
 					return
 				}
 				processGraph.SetStorage(controller.db)
+				graphState := processGraph.State
 				err = processGraph.Resolve()
 				if err != nil {
 					err2 := controller.handleDefunctProcessgraph(processGraph.ID, process.ID, err)
@@ -1138,12 +1252,20 @@ func (controller *coloniesController) closeFailed(processID string, errs []strin
 					cmd.errorChan <- err
 					return
 				}
+				controller.publishProcessGraphStateChange(processGraph, graphState)
 
 			}
 
 			process.State = core.FAILED
 
+			if !process.StartTime.IsZero() && !process.EndTime.IsZero() {
+				controller.metrics.observeProcessExecTime(process, "failed", process.EndTime.Sub(process.StartTime).Seconds())
+				controller.recordFunctionRun(process, process.StartTime.Sub(process.SubmissionTime).Seconds(), process.EndTime.Sub(process.StartTime).Seconds(), false, process.EndTime)
+				traceProcessSpan(process, "colonies.process/exec", process.StartTime, process.EndTime, cmd.ctx)
+			}
+
 			controller.eventHandler.signal(process)
+			controller.eventHandler.publish(core.CreateProcessEvent(process))
 			cmd.errorChan <- nil
 		}}
 
@@ -1151,6 +1273,12 @@ func (controller *coloniesController) closeFailed(processID string, errs []strin
 	return <-cmd.errorChan
 }
 
+func (controller *coloniesController) publishProcessGraphStateChange(processGraph *core.ProcessGraph, oldState int) {
+	if processGraph.State != oldState {
+		controller.eventHandler.publish(core.CreateProcessGraphEvent(processGraph))
+	}
+}
+
 func (controller *coloniesController) handleDefunctProcessgraph(processGraphID string, processID string, err error) error {
 	err2 := controller.db.MarkFailed(processID, []string{err.Error()})
 	if err2 != nil {
@@ -1164,9 +1292,11 @@ func (controller *coloniesController) handleDefunctProcessgraph(processGraphID s
 	return nil
 }
 
-func (controller *coloniesController) assign(executorID string, colonyName string, cpu int64, mem int64) (*AssignResult, error) {
-	cmd := &command{threaded: false, assignResultReplyChan: make(chan *AssignResult),
+func (controller *coloniesController) assign(executorID string, colonyName string, cpu int64, mem int64, ctx context.Context) (*AssignResult, error) {
+	cmd := &command{threaded: false, colonyName: colonyName, assignResultReplyChan: make(chan *AssignResult),
 		errorChan: make(chan error, 1),
+		ctx:       ctx,
+		name:      "assign",
 		handler: func(cmd *command) {
 			executor, err := controller.db.GetExecutorByID(executorID)
 			if err != nil {
@@ -1227,6 +1357,9 @@ func (controller *coloniesController) assign(executorID string, colonyName strin
 				return
 			}
 
+			controller.metrics.observeProcessWaitTime(selectedProcess, time.Since(selectedProcess.SubmissionTime).Seconds())
+			traceProcessSpan(selectedProcess, "colonies.process/wait", selectedProcess.SubmissionTime, time.Now(), cmd.ctx)
+
 			if selectedProcess.ProcessGraphID != "" {
 				log.WithFields(log.Fields{"ProcessGraphId": selectedProcess.ProcessGraphID}).Debug("Resolving processgraph (assigned)")
 				processGraph, err := controller.db.GetProcessGraphByID(selectedProcess.ProcessGraphID)
@@ -1241,6 +1374,7 @@ func (controller *coloniesController) assign(executorID string, colonyName strin
 					cmd.errorChan <- errors.New(errMsg)
 				}
 				processGraph.SetStorage(controller.db)
+				graphState := processGraph.State
 
 				// One Colonies server might have added a processgraph, and another colonies directly get an assign request
 				// This means that all processes part of the graph might not yet have been added, consequently the
@@ -1272,6 +1406,7 @@ func (controller *coloniesController) assign(executorID string, colonyName strin
 						break
 					}
 				}
+				controller.publishProcessGraphStateChange(processGraph, graphState)
 
 				// Now, we need to collect the output from the parents and use ut as our input
 				var output []interface{}
@@ -1290,6 +1425,8 @@ func (controller *coloniesController) assign(executorID string, colonyName strin
 				}
 			}
 
+			controller.eventHandler.publish(core.CreateProcessEvent(selectedProcess))
+
 			result := &AssignResult{
 				Process:       selectedProcess,
 				IsPaused:      false,
@@ -1307,8 +1444,8 @@ func (controller *coloniesController) assign(executorID string, colonyName strin
 	}
 }
 
-func (controller *coloniesController) unassignExecutor(processID string) error {
-	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+func (controller *coloniesController) unassignExecutor(processID string, colonyName string) error {
+	cmd := &command{threaded: true, colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			process, err := controller.db.GetProcessByID(processID)
 			if err != nil {
@@ -1316,16 +1453,23 @@ func (controller *coloniesController) unassignExecutor(processID string) error {
 				return
 			}
 
-			cmd.errorChan <- controller.db.Unassign(process)
+			err = controller.db.Unassign(process)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+
 			controller.eventHandler.signal(process)
+			controller.eventHandler.publish(core.CreateProcessEvent(process))
+			cmd.errorChan <- nil
 		}}
 
 	controller.cmdQueue <- cmd
 	return <-cmd.errorChan
 }
 
-func (controller *coloniesController) resetProcess(processID string) error {
-	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+func (controller *coloniesController) resetProcess(processID string, colonyName string) error {
+	cmd := &command{threaded: true, colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			process, err := controller.db.GetProcessByID(processID)
 			if err != nil {
@@ -1333,8 +1477,15 @@ func (controller *coloniesController) resetProcess(processID string) error {
 				return
 			}
 
-			cmd.errorChan <- controller.db.ResetProcess(process)
+			err = controller.db.ResetProcess(process)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+
 			controller.eventHandler.signal(process)
+			controller.eventHandler.publish(core.CreateProcessEvent(process))
+			cmd.errorChan <- nil
 		}}
 
 	controller.cmdQueue <- cmd
@@ -1342,7 +1493,7 @@ func (controller *coloniesController) resetProcess(processID string) error {
 }
 
 func (controller *coloniesController) getColonyStatistics(colonyName string) (*core.Statistics, error) {
-	cmd := &command{threaded: true, statisticsReplyChan: make(chan *core.Statistics),
+	cmd := &command{threaded: true, colonyName: colonyName, statisticsReplyChan: make(chan *core.Statistics),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			colonies := 1
@@ -1490,7 +1641,7 @@ func (controller *coloniesController) getStatistics() (*core.Statistics, error)
 }
 
 func (controller *coloniesController) addAttribute(attribute *core.Attribute) (*core.Attribute, error) {
-	cmd := &command{threaded: true, attributeReplyChan: make(chan *core.Attribute, 1),
+	cmd := &command{threaded: true, colonyName: attribute.TargetColonyName, attributeReplyChan: make(chan *core.Attribute, 1),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			err := controller.db.AddAttribute(*attribute)
@@ -1537,7 +1688,7 @@ func (controller *coloniesController) getAttribute(attributeID string) (*core.At
 }
 
 func (controller *coloniesController) addFunction(function *core.Function) (*core.Function, error) {
-	cmd := &command{threaded: true, functionReplyChan: make(chan *core.Function, 1),
+	cmd := &command{threaded: true, colonyName: function.ColonyName, functionReplyChan: make(chan *core.Function, 1),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			err := controller.db.AddFunction(function)
@@ -1563,7 +1714,7 @@ func (controller *coloniesController) addFunction(function *core.Function) (*cor
 }
 
 func (controller *coloniesController) getFunctionsByExecutorName(colonyName string, executorName string) ([]*core.Function, error) {
-	cmd := &command{threaded: true, functionsReplyChan: make(chan []*core.Function, 1),
+	cmd := &command{threaded: true, colonyName: colonyName, functionsReplyChan: make(chan []*core.Function, 1),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			functions, err := controller.db.GetFunctionsByExecutorName(colonyName, executorName)
@@ -1584,7 +1735,7 @@ func (controller *coloniesController) getFunctionsByExecutorName(colonyName stri
 }
 
 func (controller *coloniesController) getFunctionsByColonyName(colonyName string) ([]*core.Function, error) {
-	cmd := &command{threaded: true, functionsReplyChan: make(chan []*core.Function, 1),
+	cmd := &command{threaded: true, colonyName: colonyName, functionsReplyChan: make(chan []*core.Function, 1),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			functions, err := controller.db.GetFunctionsByColonyName(colonyName)
@@ -1625,8 +1776,8 @@ func (controller *coloniesController) getFunctionByID(functionID string) (*core.
 	}
 }
 
-func (controller *coloniesController) removeFunction(functionID string) error {
-	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+func (controller *coloniesController) removeFunction(functionID string, colonyName string) error {
+	cmd := &command{threaded: true, colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			err := controller.db.RemoveFunctionByID(functionID)
 			if err != nil {
@@ -1712,6 +1863,9 @@ func (controller *coloniesController) stop() {
 	controller.stopFlag = true
 	controller.stopMutex.Unlock()
 	controller.cmdQueue <- &command{stop: true}
+	controller.webhookCancel()
+	<-controller.webhookDone // The webhook worker must unsubscribe before the event handler is stopped
+	controller.stopJobElections()
 	controller.eventHandler.stop()
 	controller.relayServer.Shutdown()
 	controller.etcdServer.Stop()
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"testing"
 
 	"github.com/colonyos/colonies/pkg/core"
@@ -13,7 +14,7 @@ func TestColoniesControllerInvalidDB(t *testing.T) {
 	controller, dbMock := createFakeColoniesController()
 
 	dbMock.returnError = "GetProcessByID"
-	err := controller.subscribeProcess("invalid_id", &subscription{})
+	err := controller.subscribeProcess("invalid_id", "invalid_colony", &subscription{})
 	assert.NotNil(t, err)
 
 	dbMock.returnError = "GetColonies"
@@ -80,7 +81,7 @@ func TestColoniesControllerInvalidDB(t *testing.T) {
 	assert.NotNil(t, err)
 
 	dbMock.returnError = "AddProcess"
-	_, err = controller.addProcess(&core.Process{})
+	_, err = controller.addProcess(&core.Process{}, context.TODO())
 	assert.NotNil(t, err)
 
 	controller.stop()
@@ -136,7 +137,7 @@ func TestColoniesControllerAddProcess(t *testing.T) {
 	funcSpec := utils.CreateTestFunctionSpecWithEnv(colonyName, make(map[string]string))
 	process := core.CreateProcess(funcSpec)
 
-	addedProcess, err := controller.addProcess(process)
+	addedProcess, err := controller.addProcess(process, context.TODO())
 	assert.Nil(t, err)
 	assert.True(t, process.ID == addedProcess.ID)
 }
@@ -159,10 +160,10 @@ func TestColoniesControllerAssignExecutor(t *testing.T) {
 
 	funcSpec := utils.CreateTestFunctionSpecWithEnv(colonyName, make(map[string]string))
 	process := core.CreateProcess(funcSpec)
-	_, err = controller.addProcess(process)
+	_, err = controller.addProcess(process, context.TODO())
 	assert.Nil(t, err)
 
-	result, err := controller.assign(executor.ID, colonyName, 0, 0)
+	result, err := controller.assign(executor.ID, colonyName, 0, 0, context.TODO())
 	assert.Nil(t, err)
 	assert.False(t, result.IsPaused)
 	assert.NotNil(t, result.Process)
@@ -197,7 +198,7 @@ func TestColoniesControllerAssignExecutorConcurrency(t *testing.T) {
 	for i := 0; i < processCount; i++ {
 		funcSpec := utils.CreateTestFunctionSpecWithEnv(colonyName, make(map[string]string))
 		process := core.CreateProcess(funcSpec)
-		_, err = controller1.addProcess(process)
+		_, err = controller1.addProcess(process, context.TODO())
 		assert.Nil(t, err)
 	}
 
@@ -205,7 +206,7 @@ func TestColoniesControllerAssignExecutorConcurrency(t *testing.T) {
 
 	go func() {
 		for {
-			result, err := controller1.assign(executor1.ID, colonyName, 0, 0)
+			result, err := controller1.assign(executor1.ID, colonyName, 0, 0, context.TODO())
 			if err == nil && !result.IsPaused && result.Process != nil {
 				countChan <- 1
 			}
@@ -218,7 +219,7 @@ func TestColoniesControllerAssignExecutorConcurrency(t *testing.T) {
 
 	go func() {
 		for {
-			result, err := controller2.assign(executor2.ID, colonyName, 0, 0)
+			result, err := controller2.assign(executor2.ID, colonyName, 0, 0, context.TODO())
 			if err == nil && !result.IsPaused && result.Process != nil {
 				countChan <- 1
 			}
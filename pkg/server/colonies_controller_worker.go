@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -40,70 +41,75 @@ func (controller *coloniesController) timeoutLoop() {
 		}
 		controller.stopMutex.Unlock()
 
-		processes, err := controller.db.FindAllRunningProcesses()
-		if err != nil {
-			log.Error(err)
+		controller.runJob(TIMEOUT_JOB, controller.releaseTimedOutProcesses)
+	}
+}
+
+// releaseTimedOutProcesses unassigns, or closes as failed, running processes that have exceeded their max execution
+// time, and closes as failed waiting processes that have exceeded their max waiting time
+func (controller *coloniesController) releaseTimedOutProcesses() {
+	processes, err := controller.db.FindAllRunningProcesses()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, process := range processes {
+		if process.FunctionSpec.MaxExecTime == -1 {
 			continue
 		}
-		for _, process := range processes {
-			if process.FunctionSpec.MaxExecTime == -1 {
-				continue
-			}
-			if time.Now().Unix() > process.ExecDeadline.Unix() {
-				if process.Retries >= process.FunctionSpec.MaxRetries && process.FunctionSpec.MaxRetries > -1 {
-					err := controller.closeFailed(process.ID, []string{"Maximum execution time limit exceeded"})
-					if err != nil {
-						log.WithFields(log.Fields{"ProcessId": process.ID, "Error": err}).Debug("Max retries reached, but failed to close process")
-						continue
-					}
-					log.WithFields(log.Fields{"ProcessId": process.ID, "MaxExecTime": process.FunctionSpec.MaxExecTime, "MaxRetries": process.FunctionSpec.MaxRetries}).Debug("Process closed as failed as max retries reached")
+		if time.Now().Unix() > process.ExecDeadline.Unix() {
+			if process.Retries >= process.FunctionSpec.MaxRetries && process.FunctionSpec.MaxRetries > -1 {
+				err := controller.closeFailed(process.ID, process.FunctionSpec.Conditions.ColonyName, []string{"Maximum execution time limit exceeded"}, context.Background())
+				if err != nil {
+					log.WithFields(log.Fields{"ProcessId": process.ID, "Error": err}).Debug("Max retries reached, but failed to close process")
 					continue
 				}
+				log.WithFields(log.Fields{"ProcessId": process.ID, "MaxExecTime": process.FunctionSpec.MaxExecTime, "MaxRetries": process.FunctionSpec.MaxRetries}).Debug("Process closed as failed as max retries reached")
+				continue
+			}
 
-				err := controller.unassignExecutor(process.ID)
-				if err != nil {
-					log.WithFields(log.Fields{"ProcessId": process.ID, "Error": err}).Error("Failed to unassign process")
-				}
-				log.WithFields(log.Fields{"ProcessId": process.ID, "MaxExecTime": process.FunctionSpec.MaxExecTime, "MaxRetries": process.FunctionSpec.MaxRetries}).Debug("Process was unassigned as it did not complete in time")
+			err := controller.unassignExecutor(process.ID, process.FunctionSpec.Conditions.ColonyName)
+			if err != nil {
+				log.WithFields(log.Fields{"ProcessId": process.ID, "Error": err}).Error("Failed to unassign process")
 			}
+			log.WithFields(log.Fields{"ProcessId": process.ID, "MaxExecTime": process.FunctionSpec.MaxExecTime, "MaxRetries": process.FunctionSpec.MaxRetries}).Debug("Process was unassigned as it did not complete in time")
 		}
+	}
 
-		// TODO: FindAllWaitingProcesses will only return max 1000 processes, this is to avoid dumping the entire database
-		// However, the means that maxWaitTime may not work correctly if there are more than 1000 waiting processes.
-		processes, err = controller.db.FindAllWaitingProcesses()
-		if err != nil {
+	// TODO: FindAllWaitingProcesses will only return max 1000 processes, this is to avoid dumping the entire database
+	// However, the means that maxWaitTime may not work correctly if there are more than 1000 waiting processes.
+	processes, err = controller.db.FindAllWaitingProcesses()
+	if err != nil {
+		return
+	}
+	for _, process := range processes {
+		if process.FunctionSpec.MaxWaitTime == -1 || process.FunctionSpec.MaxWaitTime == 0 {
 			continue
 		}
-		for _, process := range processes {
-			if process.FunctionSpec.MaxWaitTime == -1 || process.FunctionSpec.MaxWaitTime == 0 {
-				continue
-			}
 
-			if time.Now().Unix() > process.WaitDeadline.Unix() {
-				err := controller.closeFailed(process.ID, []string{"Maximum waiting time limit exceeded"})
-				if err != nil {
-					log.WithFields(log.Fields{"ProcessId": process.ID, "Error": err}).Debug("Max waiting time reached, but failed to close process")
-					continue
-				}
-				log.WithFields(log.Fields{"ProcessId": process.ID, "MaxWaitTime": process.FunctionSpec.MaxWaitTime}).Debug("Process closed as failed as maximum waiting time limit exceeded")
+		if time.Now().Unix() > process.WaitDeadline.Unix() {
+			err := controller.closeFailed(process.ID, process.FunctionSpec.Conditions.ColonyName, []string{"Maximum waiting time limit exceeded"}, context.Background())
+			if err != nil {
+				log.WithFields(log.Fields{"ProcessId": process.ID, "Error": err}).Debug("Max waiting time reached, but failed to close process")
+				continue
 			}
+			log.WithFields(log.Fields{"ProcessId": process.ID, "MaxWaitTime": process.FunctionSpec.MaxWaitTime}).Debug("Process closed as failed as maximum waiting time limit exceeded")
 		}
 	}
 }
 
+// blockingCmdQueueWorker moves commands from the blocking command queue to the queue of their colony, the commands
+// are then run one at a time in weighted fair order between the colonies
 func (controller *coloniesController) blockingCmdQueueWorker() {
 	for {
 		select {
 		case cmd := <-controller.blockingCmdQueue:
 			if cmd.stop {
+				controller.blockingCmdScheduler.stop()
 				return
 			}
 			if cmd.handler != nil {
-				if cmd.threaded {
-					go cmd.handler(cmd)
-				} else {
-					cmd.handler(cmd)
-				}
+				controller.blockingCmdScheduler.push(cmd)
 			}
 		}
 	}
@@ -111,30 +117,27 @@ func (controller *coloniesController) blockingCmdQueueWorker() {
 
 func (controller *coloniesController) retentionWorker() {
 	for {
-		isLeader := controller.tryBecomeLeader()
-
-		if isLeader && controller.retention {
+		controller.runJob(RETENTION_JOB, func() {
 			log.Debug("Appling retention policy")
-			controller.db.ApplyRetentionPolicy(controller.retentionPolicy)
-		}
+			controller.applyRetentionPolicies()
+		})
 
 		time.Sleep(time.Duration(controller.retentionPeriod) * time.Millisecond)
 	}
 }
 
+// cmdQueueWorker moves commands from the command queue to the queue of their colony, the commands are then run in
+// weighted fair order between the colonies, limited by the database budget of every colony
 func (controller *coloniesController) cmdQueueWorker() {
 	for {
 		select {
 		case cmd := <-controller.cmdQueue:
 			if cmd.stop {
+				controller.cmdScheduler.stop()
 				return
 			}
 			if cmd.handler != nil {
-				if cmd.threaded {
-					go cmd.handler(cmd)
-				} else {
-					cmd.handler(cmd)
-				}
+				controller.cmdScheduler.push(cmd)
 			}
 		}
 	}
@@ -7,15 +7,21 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/colonyos/colonies/pkg/cluster"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/p2p/dht"
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/colonyos/colonies/pkg/security/crypto"
 	"github.com/colonyos/colonies/pkg/security/validator"
+	"github.com/colonyos/colonies/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -40,6 +46,12 @@ type ColoniesServer struct {
 	retentionPolicy         int64
 	retentionPeriod         int
 	unprivilegedExecutors   bool
+	metrics                 *serverMetrics
+	drainCtx                context.Context // Cancelled when the server starts draining
+	drainCancel             context.CancelFunc
+	drainOnce               sync.Once
+	drained                 chan struct{}
+	shutdownOnce            sync.Once
 }
 
 func CreateColoniesServer(db database.Database,
@@ -61,8 +73,11 @@ func CreateColoniesServer(db database.Database,
 	server := &ColoniesServer{}
 	server.ginHandler = gin.Default()
 	server.ginHandler.Use(cors.Default())
+	server.ginHandler.Use(server.closeConnectionsOnDrain)
 
 	server.db = db
+	server.drainCtx, server.drainCancel = context.WithCancel(context.Background())
+	server.drained = make(chan struct{})
 
 	httpServer := &http.Server{
 		Addr:    ":" + strconv.Itoa(port),
@@ -71,6 +86,7 @@ func CreateColoniesServer(db database.Database,
 
 	server.httpServer = httpServer
 	server.controller = createColoniesController(db, thisNode, clusterConfig, etcdDataPath, generatorPeriod, cronPeriod, retention, retentionPolicy, retentionPeriod)
+	server.metrics = server.controller.getMetrics()
 
 	server.tls = tls
 	server.port = port
@@ -108,6 +124,78 @@ func CreateColoniesServer(db database.Database,
 	return server
 }
 
+// SetFileStore makes it possible for the retention worker to archive expired rows and to remove expired file revisions
+func (server *ColoniesServer) SetFileStore(fileStore FileStore) {
+	server.controller.setFileStore(fileStore)
+}
+
+// SetAnomalyFactor sets how many times longer than the historical p95 exec time of a function a run must take to be
+// flagged as an anomaly, 0 disables anomaly detection
+func (server *ColoniesServer) SetAnomalyFactor(factor float64) {
+	server.controller.setAnomalyFactor(factor)
+}
+
+// SetStatisticsPeriod sets how often colony statistics are stored as snapshots, 0 disables snapshots
+func (server *ColoniesServer) SetStatisticsPeriod(period time.Duration) {
+	server.controller.setStatisticsPeriod(period)
+}
+
+// SetColonyWeight sets the share of the server a colony gets when colonies compete for it, relative to the weights of
+// the other colonies. Colonies have weight 1 by default, a weight less than 1 restores the default weight.
+func (server *ColoniesServer) SetColonyWeight(colonyName string, weight int) {
+	server.controller.setColonyWeight(colonyName, weight)
+}
+
+// SetColonyDBBudget sets the max number of requests of every colony that may use the database at the same time, so
+// that a single colony cannot use up the database connections, 0 means unlimited
+func (server *ColoniesServer) SetColonyDBBudget(budget int) {
+	server.controller.setColonyDBBudget(budget)
+}
+
+// SetDiscovery makes the server publish its endpoint, and the colonies it serves, in a DHT, so that clients can find
+// the server without knowing its address. The server private key must match the server ID.
+func (server *ColoniesServer) SetDiscovery(d dht.DHT, serverPrvKey string, endpoint *core.ServerEndpoint) error {
+	serverID, err := server.getServerID()
+	if err != nil {
+		return err
+	}
+
+	id, err := server.crypto.GenerateID(serverPrvKey)
+	if err != nil {
+		return err
+	}
+
+	if id != serverID {
+		return errors.New("Server private key does not match the server ID, cannot publish server endpoint")
+	}
+
+	server.controller.setDiscovery(d, serverPrvKey, endpoint)
+
+	return nil
+}
+
+// SetFederation makes the server forward processes to the federation peers of its colonies, and mirror the outcome
+// of forwarded processes back. RPCs to peers are signed with the server private key, which must match the server ID.
+func (server *ColoniesServer) SetFederation(serverPrvKey string, skipTLSVerify bool) error {
+	serverID, err := server.getServerID()
+	if err != nil {
+		return err
+	}
+
+	id, err := server.crypto.GenerateID(serverPrvKey)
+	if err != nil {
+		return err
+	}
+
+	if id != serverID {
+		return errors.New("Server private key does not match the server ID, cannot enable federation")
+	}
+
+	server.controller.setFederation(serverPrvKey, skipTLSVerify)
+
+	return nil
+}
+
 func (server *ColoniesServer) getServerID() (string, error) {
 	return server.db.GetServerID()
 }
@@ -115,6 +203,9 @@ func (server *ColoniesServer) getServerID() (string, error) {
 func (server *ColoniesServer) setupRoutes() {
 	server.ginHandler.POST("/api", server.handleAPIRequest)
 	server.ginHandler.GET("/health", server.handleHealthRequest)
+	server.ginHandler.GET("/healthz", server.handleLivenessRequest)
+	server.ginHandler.GET("/readyz", server.handleReadinessRequest)
+	server.ginHandler.GET("/metrics", server.metrics.handler())
 	server.ginHandler.GET("/pubsub", server.handleWSRequest)
 }
 
@@ -133,6 +224,7 @@ func (server *ColoniesServer) handleHealthRequest(c *gin.Context) {
 }
 
 func (server *ColoniesServer) handleAPIRequest(c *gin.Context) {
+	start := time.Now()
 	jsonBytes, err := ioutil.ReadAll(c.Request.Body)
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		log.WithFields(log.Fields{"Error": err}).Error("Bad request")
@@ -144,6 +236,24 @@ func (server *ColoniesServer) handleAPIRequest(c *gin.Context) {
 		return
 	}
 
+	// Continue the trace of the caller, handlers pass c.Request.Context() to the controller
+	ctx, span := tracing.Tracer().Start(tracing.Extract(c.Request.Context(), rpcMsg.TraceParent, rpcMsg.TraceState), "colonies.rpc", trace.WithSpanKind(trace.SpanKindServer))
+	c.Request = c.Request.WithContext(ctx)
+
+	// Payload types are sent by clients, so unknown payload types are counted together to bound the number of series
+	payloadType := rpcMsg.PayloadType
+	defer func() {
+		code := c.Writer.Status()
+		server.metrics.observeRPC(payloadType, code, start)
+
+		span.SetName("colonies.rpc/" + payloadType)
+		span.SetAttributes(attribute.Int("http.status_code", code))
+		if code >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, http.StatusText(code))
+		}
+		span.End()
+	}()
+
 	// Version does not require a valid private key
 	if rpcMsg.PayloadType == rpc.VersionPayloadType {
 		server.handleVersionHTTPRequest(c, rpcMsg.PayloadType, rpcMsg.DecodePayload())
@@ -152,6 +262,7 @@ func (server *ColoniesServer) handleAPIRequest(c *gin.Context) {
 
 	recoveredID, err := server.parseSignature(rpcMsg.Payload, rpcMsg.Signature)
 	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		payloadType = "invalid_signature"
 		return
 	}
 
@@ -200,6 +311,8 @@ func (server *ColoniesServer) handleAPIRequest(c *gin.Context) {
 		server.handleGetFunctionsHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
 	case rpc.RemoveFunctionPayloadType:
 		server.handleRemoveFunctionHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.GetFunctionStatsPayloadType:
+		server.handleGetFunctionStatsHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
 
 	// Process handlers
 	case rpc.SubmitFunctionSpecPayloadType:
@@ -230,6 +343,10 @@ func (server *ColoniesServer) handleAPIRequest(c *gin.Context) {
 		server.handleSetOutputHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
 	case rpc.GetColonyStatisticsPayloadType:
 		server.handleColonyStatisticsHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.GetColonyStatisticsHistoryPayloadType:
+		server.handleColonyStatisticsHistoryHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.GetColonyUsagePayloadType:
+		server.handleColonyUsageHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
 
 	// Attribute handlers
 	case rpc.AddAttributePayloadType:
@@ -282,6 +399,12 @@ func (server *ColoniesServer) handleAPIRequest(c *gin.Context) {
 		server.handleStatisticsHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
 	case rpc.GetClusterPayloadType:
 		server.handleGetClusterHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.AddClusterNodePayloadType:
+		server.handleAddClusterNodeHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.RemoveClusterNodePayloadType:
+		server.handleRemoveClusterNodeHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.DrainServerPayloadType:
+		server.handleDrainServerHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
 
 	// Log handlers
 	case rpc.AddLogPayloadType:
@@ -315,6 +438,46 @@ func (server *ColoniesServer) handleAPIRequest(c *gin.Context) {
 	case rpc.RemoveAllSnapshotsPayloadType:
 		server.handleRemoveAllSnapshotsHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
 
+		// Retention handlers
+	case rpc.SetRetentionPolicyPayloadType:
+		server.handleSetRetentionPolicyHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.GetRetentionPolicyPayloadType:
+		server.handleGetRetentionPolicyHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.RemoveRetentionPolicyPayloadType:
+		server.handleRemoveRetentionPolicyHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+
+		// Webhook handlers
+	case rpc.AddWebhookPayloadType:
+		server.handleAddWebhookHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.GetWebhooksPayloadType:
+		server.handleGetWebhooksHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.RemoveWebhookPayloadType:
+		server.handleRemoveWebhookHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.GetWebhookDeliveriesPayloadType:
+		server.handleGetWebhookDeliveriesHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+
+		// Alert handlers
+	case rpc.AddAlertRulePayloadType:
+		server.handleAddAlertRuleHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.GetAlertRulesPayloadType:
+		server.handleGetAlertRulesHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.RemoveAlertRulePayloadType:
+		server.handleRemoveAlertRuleHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+
+		// Federation handlers
+	case rpc.AddFederationPeerPayloadType:
+		server.handleAddFederationPeerHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.GetFederationPeersPayloadType:
+		server.handleGetFederationPeersHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.RemoveFederationPeerPayloadType:
+		server.handleRemoveFederationPeerHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.SubmitFederatedProcessPayloadType:
+		server.handleSubmitFederatedProcessHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.GetFederatedProcessPayloadType:
+		server.handleGetFederatedProcessHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+	case rpc.RemoveFederatedProcessPayloadType:
+		server.handleRemoveFederatedProcessHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
+
 		// Security handlers
 	case rpc.ChangeUserIDPayloadType:
 		server.handleChangeUserIDHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
@@ -326,6 +489,7 @@ func (server *ColoniesServer) handleAPIRequest(c *gin.Context) {
 		server.handleChangeServerIDHTTPRequest(c, recoveredID, rpcMsg.PayloadType, rpcMsg.DecodePayload())
 
 	default:
+		payloadType = "unknown"
 		errMsg := "invalid rpcMsg.PayloadType, " + rpcMsg.PayloadType
 		if server.handleHTTPError(c, errors.New(errMsg), http.StatusForbidden) {
 			log.Error(errMsg)
@@ -396,6 +560,10 @@ func (server *ColoniesServer) sendEmptyHTTPReply(c *gin.Context, payloadType str
 	c.String(http.StatusOK, rpcReplyMsgJSONString)
 }
 
+func (server *ColoniesServer) publishEvent(event *core.Event) {
+	server.controller.getEventHandler().publish(event)
+}
+
 func (server *ColoniesServer) ServeForever() error {
 	if server.tls {
 		if err := server.httpServer.ListenAndServeTLS(server.tlsCertPath, server.tlsPrivateKeyPath); err != nil && errors.Is(err, http.ErrServerClosed) {
@@ -411,12 +579,14 @@ func (server *ColoniesServer) ServeForever() error {
 }
 
 func (server *ColoniesServer) Shutdown() {
-	server.controller.stop()
+	server.shutdownOnce.Do(func() {
+		server.controller.stop()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
 
-	if err := server.httpServer.Shutdown(ctx); err != nil {
-		log.WithFields(log.Fields{"Error": err}).Warning("ColoniesServer forced to shutdown")
-	}
+		if err := server.httpServer.Shutdown(ctx); err != nil {
+			log.WithFields(log.Fields{"Error": err}).Warning("ColoniesServer forced to shutdown")
+		}
+	})
 }
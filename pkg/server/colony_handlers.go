@@ -3,6 +3,7 @@ package server
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/rpc"
@@ -237,3 +238,96 @@ func (server *ColoniesServer) handleColonyStatisticsHTTPRequest(c *gin.Context,
 
 	server.sendHTTPReply(c, payloadType, jsonString)
 }
+
+func (server *ColoniesServer) handleColonyStatisticsHistoryHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetColonyStatisticsHistoryMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to get colony statistics history, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to get colony statistics history, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireMembership(recoveredID, msg.ColonyName, true)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	if !msg.From.Before(msg.To) {
+		server.handleHTTPError(c, errors.New("Failed to get colony statistics history, from must be before to"), http.StatusBadRequest)
+		return
+	}
+
+	snapshots, err := server.db.GetStatisticsSnapshots(msg.ColonyName, msg.From, msg.To, MAX_STATISTICS_SNAPSHOTS)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	jsonString, err = core.ConvertStatisticsSnapshotArrayToJSON(snapshots)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "From": msg.From, "To": msg.To, "Snapshots": len(snapshots)}).Debug("Getting colony statistics history")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+func (server *ColoniesServer) handleColonyUsageHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetColonyUsageMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to get colony usage, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to get colony usage, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireMembership(recoveredID, msg.ColonyName, true)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	if !msg.From.Before(msg.To) {
+		server.handleHTTPError(c, errors.New("Failed to get colony usage, from must be before to"), http.StatusBadRequest)
+		return
+	}
+
+	if !core.IsValidUsageGroup(msg.GroupBy) {
+		server.handleHTTPError(c, errors.New("Failed to get colony usage, invalid group <"+msg.GroupBy+">, must be "+core.UsageByUser+", "+core.UsageByExecutor+" or "+core.UsageByLabel), http.StatusBadRequest)
+		return
+	}
+
+	runs, err := server.db.GetFunctionRunsBetween(msg.ColonyName, msg.From, msg.To, MAX_FUNCTION_RUNS+1)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	// A partial sum would silently undercharge, so refuse rather than truncate
+	if len(runs) > MAX_FUNCTION_RUNS {
+		server.handleHTTPError(c, errors.New("Failed to get colony usage, more than "+strconv.Itoa(MAX_FUNCTION_RUNS)+" processes in period, use a shorter period"), http.StatusBadRequest)
+		return
+	}
+
+	storage, err := server.db.GetStorageUsageByLabel(msg.ColonyName, msg.To)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	usage := core.CalcColonyUsage(msg.ColonyName, msg.GroupBy, msg.From, msg.To, runs, storage)
+	jsonString, err = usage.ToJSON()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "From": msg.From, "To": msg.To, "GroupBy": msg.GroupBy, "Runs": len(runs)}).Debug("Getting colony usage")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/security/crypto"
@@ -137,3 +138,49 @@ func TestGetColonyStatisticsSecurity(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+func TestGetColonyStatisticsHistorySecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	from := time.Now().Add(-time.Hour)
+	to := time.Now()
+
+	_, err := client.ColonyStatisticsHistory(env.colony1Name, from, to, env.executor2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.ColonyStatisticsHistory(env.colony1Name, from, to, env.colony1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.ColonyStatisticsHistory(env.colony1Name, from, to, env.executor1PrvKey)
+	assert.Nil(t, err) // Should work
+
+	server.Shutdown()
+	<-done
+}
+
+func TestGetColonyUsageSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	from := time.Now().Add(-time.Hour)
+	to := time.Now()
+
+	_, err := client.GetColonyUsage(env.colony1Name, from, to, core.UsageByUser, env.executor2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.GetColonyUsage(env.colony1Name, from, to, core.UsageByUser, env.colony1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.GetColonyUsage(env.colony1Name, from, to, core.UsageByUser, env.executor1PrvKey)
+	assert.Nil(t, err) // Should work
+
+	server.Shutdown()
+	<-done
+}
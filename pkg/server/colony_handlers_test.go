@@ -2,6 +2,7 @@ package server
 
 import (
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/utils"
@@ -149,3 +150,107 @@ func TestGetColonyStatistics(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+func TestGetColonyStatisticsHistory(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	server.SetStatisticsPeriod(time.Second)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	_, err := client.Submit(funcSpec, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	time.Sleep(3 * time.Second)
+
+	snapshots, err := client.ColonyStatisticsHistory(env.colonyName, time.Now().Add(-time.Hour), time.Now(), env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Greater(t, len(snapshots), 0)
+	for _, snapshot := range snapshots {
+		assert.Equal(t, env.colonyName, snapshot.ColonyName)
+	}
+	// A snapshot may have been taken before the process was submitted
+	assert.Equal(t, 1, snapshots[len(snapshots)-1].Statistics.WaitingProcesses)
+
+	server.SetStatisticsPeriod(0)
+	time.Sleep(1500 * time.Millisecond) // Let a snapshot in progress finish
+	snapshots, err = client.ColonyStatisticsHistory(env.colonyName, time.Now().Add(-time.Hour), time.Now(), env.executorPrvKey)
+	assert.Nil(t, err)
+	count := len(snapshots)
+
+	time.Sleep(2 * time.Second)
+	snapshots, err = client.ColonyStatisticsHistory(env.colonyName, time.Now().Add(-time.Hour), time.Now(), env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, count) // Snapshots are disabled
+
+	_, err = client.ColonyStatisticsHistory(env.colonyName, time.Now(), time.Now().Add(-time.Hour), env.executorPrvKey)
+	assert.NotNil(t, err) // From after to
+
+	server.Shutdown()
+	<-done
+}
+
+func TestGetColonyUsage(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	from := time.Now().Add(-time.Hour)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	funcSpec.Conditions.CPU = "500m"
+	funcSpec.Conditions.Nodes = 2
+	funcSpec.Conditions.GPU.Count = 1
+	_, err := client.Submit(funcSpec, env.executorPrvKey)
+	assert.Nil(t, err)
+	process, err := client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	err = client.Close(process.ID, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	_, err = client.Submit(utils.CreateTestFunctionSpec(env.colonyName), env.executorPrvKey)
+	assert.Nil(t, err)
+	process, err = client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+	err = client.Fail(process.ID, []string{"error"}, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	file := utils.CreateTestFile(env.colonyName)
+	file.Label = "/test_label"
+	file.Size = 100
+	_, err = client.AddFile(file, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	usage, err := client.GetColonyUsage(env.colonyName, from, time.Now(), core.UsageByUser, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, core.UsageByUser, usage.GroupBy)
+	assert.Len(t, usage.Usage, 1)
+	assert.Equal(t, env.executorName, usage.Usage[0].Key)
+	assert.Equal(t, 2, usage.Usage[0].Processes)
+	assert.Equal(t, 1, usage.Usage[0].Successful)
+	assert.Equal(t, 1, usage.Usage[0].Failed)
+	assert.Greater(t, usage.Usage[0].CPUSeconds, 0.0)
+	assert.Greater(t, usage.Usage[0].GPUSeconds, 0.0)
+	assert.Equal(t, usage.Usage[0].CPUSeconds, usage.Usage[0].GPUSeconds/2) // 1 core and 2 GPUs on 2 nodes
+	assert.Equal(t, 2, usage.Total.Processes)
+	assert.Equal(t, int64(100), usage.Total.StorageBytes)
+
+	usage, err = client.GetColonyUsage(env.colonyName, from, time.Now(), core.UsageByLabel, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, usage.Usage, 2)
+	assert.Equal(t, "/test_label", usage.Usage[0].Key)
+	assert.Equal(t, int64(100), usage.Usage[0].StorageBytes)
+	assert.Equal(t, "test_label", usage.Usage[1].Key)
+	assert.Equal(t, 2, usage.Usage[1].Processes)
+
+	usage, err = client.GetColonyUsage(env.colonyName, from, from.Add(time.Minute), core.UsageByExecutor, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, usage.Usage, 0)
+
+	_, err = client.GetColonyUsage(env.colonyName, from, time.Now(), "error", env.executorPrvKey)
+	assert.NotNil(t, err) // Invalid group
+
+	_, err = client.GetColonyUsage(env.colonyName, time.Now(), from, core.UsageByUser, env.executorPrvKey)
+	assert.NotNil(t, err) // From after to
+
+	server.Shutdown()
+	<-done
+}
@@ -0,0 +1,330 @@
+package server
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const SERIAL_QUEUE = "serial"         // Commands run one at a time, e.g. assign
+const CONCURRENT_QUEUE = "concurrent" // Commands run concurrently, e.g. submit
+
+// colonyLimits holds the weights and database budgets of the colonies, shared by the command schedulers. The
+// database budget is tracked here rather than in the schedulers, so that the commands of a colony count towards the
+// same budget in both schedulers.
+type colonyLimits struct {
+	mutex     sync.Mutex
+	weights   map[string]int
+	dbBudget  int
+	running   map[string]int // Commands of a colony using the database, in all schedulers
+	listeners []func()       // Called when a colony has released some of its budget
+}
+
+func createColonyLimits() *colonyLimits {
+	return &colonyLimits{weights: make(map[string]int), dbBudget: DEFAULT_COLONY_DB_BUDGET, running: make(map[string]int)}
+}
+
+func (limits *colonyLimits) addListener(listener func()) {
+	limits.mutex.Lock()
+	defer limits.mutex.Unlock()
+
+	limits.listeners = append(limits.listeners, listener)
+}
+
+func (limits *colonyLimits) setWeight(colonyName string, weight int) {
+	limits.mutex.Lock()
+	defer limits.mutex.Unlock()
+
+	if weight < 1 {
+		delete(limits.weights, colonyName)
+		return
+	}
+
+	limits.weights[colonyName] = weight
+}
+
+func (limits *colonyLimits) weight(colonyName string) int {
+	limits.mutex.Lock()
+	defer limits.mutex.Unlock()
+
+	if weight, ok := limits.weights[colonyName]; ok {
+		return weight
+	}
+
+	return DEFAULT_COLONY_WEIGHT
+}
+
+func (limits *colonyLimits) setDBBudget(budget int) {
+	limits.mutex.Lock()
+	defer limits.mutex.Unlock()
+
+	if budget < 0 {
+		budget = 0
+	}
+	limits.dbBudget = budget
+}
+
+// acquire reserves one unit of the database budget of a colony, returns false if the colony has used up its budget.
+// Commands not bound to a colony, e.g. health probes, are not limited.
+func (limits *colonyLimits) acquire(colonyName string) bool {
+	if colonyName == "" {
+		return true
+	}
+
+	limits.mutex.Lock()
+	defer limits.mutex.Unlock()
+
+	if limits.dbBudget > 0 && limits.running[colonyName] >= limits.dbBudget {
+		return false
+	}
+	limits.running[colonyName]++
+
+	return true
+}
+
+// release returns one unit of the database budget of a colony and wakes up the schedulers, a scheduler may be
+// waiting for budget released by a command of the other scheduler
+func (limits *colonyLimits) release(colonyName string) {
+	if colonyName == "" {
+		return
+	}
+
+	limits.mutex.Lock()
+	limits.running[colonyName]--
+	if limits.running[colonyName] <= 0 {
+		delete(limits.running, colonyName)
+	}
+	listeners := limits.listeners
+	limits.mutex.Unlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
+type queuedCommand struct {
+	cmd      *command
+	enqueued time.Time
+}
+
+type colonyQueue struct {
+	colonyName string
+	commands   []*queuedCommand
+	credits    int       // Commands left to pop from the queue before the next colony gets its turn
+	running    int       // Commands popped from the queue that have not finished yet
+	scheduled  int64     // Commands popped from the queue since the server started
+	idleSince  time.Time // When the queue became empty with no running commands, zero if it is in use
+}
+
+type colonyQueueStats struct {
+	ColonyName string
+	Queue      string
+	Depth      int
+	Running    int
+	Scheduled  int64
+}
+
+// colonyScheduler keeps a command queue per colony and pops commands in weighted round-robin order, a colony with
+// weight 2 gets twice as many turns as a colony with weight 1. A colony flooding the server with requests thus only
+// delays its own commands. The number of commands of a colony running at the same time is limited by the database
+// budget of the colony, so that a single colony cannot use up the database connection pool. Queues of colonies that
+// have been idle for COLONY_QUEUE_IDLE_TIMEOUT are removed.
+type colonyScheduler struct {
+	queue       string
+	mutex       sync.Mutex
+	cond        *sync.Cond
+	queues      map[string]*colonyQueue
+	ring        []*colonyQueue // Queues with waiting commands, in round-robin order
+	cursor      int
+	limits      *colonyLimits
+	metrics     *serverMetrics
+	stopped     bool
+	lastEvicted time.Time
+}
+
+func createColonyScheduler(queue string, limits *colonyLimits, metrics *serverMetrics) *colonyScheduler {
+	scheduler := &colonyScheduler{queue: queue, queues: make(map[string]*colonyQueue), limits: limits, metrics: metrics, lastEvicted: time.Now()}
+	scheduler.cond = sync.NewCond(&scheduler.mutex)
+	limits.addListener(scheduler.wake)
+
+	return scheduler
+}
+
+func (scheduler *colonyScheduler) wake() {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	scheduler.cond.Broadcast()
+}
+
+func (scheduler *colonyScheduler) push(cmd *command) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	scheduler.evictIdleQueues(time.Now())
+
+	queue, ok := scheduler.queues[cmd.colonyName]
+	if !ok {
+		queue = &colonyQueue{colonyName: cmd.colonyName}
+		scheduler.queues[cmd.colonyName] = queue
+	}
+	queue.idleSince = time.Time{}
+
+	if len(queue.commands) == 0 {
+		scheduler.ring = append(scheduler.ring, queue)
+	}
+	queue.commands = append(queue.commands, &queuedCommand{cmd: cmd, enqueued: time.Now()})
+
+	scheduler.cond.Broadcast()
+}
+
+// pop blocks until a command can be run, colonies that have used up their database budget are skipped. The command
+// must be marked as done when it has finished. Returns nil when the scheduler has been stopped.
+func (scheduler *colonyScheduler) pop() *command {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	for {
+		if scheduler.stopped {
+			return nil
+		}
+
+		for i := 0; i < len(scheduler.ring); i++ {
+			queue := scheduler.ring[scheduler.cursor]
+			if !scheduler.limits.acquire(queue.colonyName) {
+				queue.credits = 0
+				scheduler.cursor = (scheduler.cursor + 1) % len(scheduler.ring)
+				continue
+			}
+
+			if queue.credits == 0 {
+				queue.credits = scheduler.limits.weight(queue.colonyName)
+			}
+
+			queuedCmd := queue.commands[0]
+			queue.commands[0] = nil
+			queue.commands = queue.commands[1:]
+			queue.credits--
+			queue.running++
+			queue.scheduled++
+
+			if len(queue.commands) == 0 {
+				queue.credits = 0
+				scheduler.ring = append(scheduler.ring[:scheduler.cursor], scheduler.ring[scheduler.cursor+1:]...)
+				if scheduler.cursor >= len(scheduler.ring) {
+					scheduler.cursor = 0
+				}
+			} else if queue.credits == 0 {
+				scheduler.cursor = (scheduler.cursor + 1) % len(scheduler.ring)
+			}
+
+			scheduler.metrics.observeCommandWait(queue.colonyName, scheduler.queue, queuedCmd.enqueued)
+
+			return queuedCmd.cmd
+		}
+
+		scheduler.cond.Wait()
+	}
+}
+
+func (scheduler *colonyScheduler) done(cmd *command) {
+	scheduler.mutex.Lock()
+	if queue, ok := scheduler.queues[cmd.colonyName]; ok {
+		queue.running--
+		if queue.running == 0 && len(queue.commands) == 0 {
+			queue.idleSince = time.Now()
+		}
+	}
+	scheduler.mutex.Unlock()
+
+	// Wakes up both schedulers, must not be called while holding the mutex
+	scheduler.limits.release(cmd.colonyName)
+}
+
+// evictIdleQueues removes the queues of colonies that have been idle for COLONY_QUEUE_IDLE_TIMEOUT, so that the
+// queues do not grow forever as colonies come and go. Must be called while holding the mutex.
+func (scheduler *colonyScheduler) evictIdleQueues(now time.Time) {
+	if now.Sub(scheduler.lastEvicted) < COLONY_QUEUE_IDLE_TIMEOUT {
+		return
+	}
+	scheduler.lastEvicted = now
+
+	for colonyName, queue := range scheduler.queues {
+		if !queue.idleSince.IsZero() && now.Sub(queue.idleSince) >= COLONY_QUEUE_IDLE_TIMEOUT {
+			delete(scheduler.queues, colonyName)
+		}
+	}
+}
+
+func (scheduler *colonyScheduler) stop() {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	scheduler.stopped = true
+	scheduler.cond.Broadcast()
+}
+
+// idle returns true if no commands are queued or running
+func (scheduler *colonyScheduler) idle() bool {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	for _, queue := range scheduler.queues {
+		if len(queue.commands) > 0 || queue.running > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (scheduler *colonyScheduler) stats() []colonyQueueStats {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	var stats []colonyQueueStats
+	for _, queue := range scheduler.queues {
+		stats = append(stats, colonyQueueStats{ColonyName: queue.colonyName,
+			Queue:     scheduler.queue,
+			Depth:     len(queue.commands),
+			Running:   queue.running,
+			Scheduled: queue.scheduled})
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].ColonyName < stats[j].ColonyName })
+
+	return stats
+}
+
+// runScheduledCommands runs the commands popped from a scheduler until the scheduler is stopped. Threaded commands
+// run in their own goroutine, but count towards the database budget of the colony until they have finished.
+func (controller *coloniesController) runScheduledCommands(scheduler *colonyScheduler) {
+	for {
+		cmd := scheduler.pop()
+		if cmd == nil {
+			return
+		}
+
+		if cmd.threaded {
+			go func() {
+				defer scheduler.done(cmd)
+				controller.runCommand(cmd)
+			}()
+		} else {
+			controller.runCommand(cmd)
+			scheduler.done(cmd)
+		}
+	}
+}
+
+// setColonyWeight sets the share of the command queues a colony gets relative to other colonies, a weight less than
+// 1 restores the default weight
+func (controller *coloniesController) setColonyWeight(colonyName string, weight int) {
+	controller.colonyLimits.setWeight(colonyName, weight)
+}
+
+// setColonyDBBudget sets the max number of commands of a colony that may use the database at the same time, 0 means
+// unlimited
+func (controller *coloniesController) setColonyDBBudget(budget int) {
+	controller.colonyLimits.setDBBudget(budget)
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCommand(colonyName string, name string) *command {
+	return &command{colonyName: colonyName, name: name, handler: func(cmd *command) {}}
+}
+
+func popNames(scheduler *colonyScheduler, count int) []string {
+	var names []string
+	for i := 0; i < count; i++ {
+		cmd := scheduler.pop()
+		names = append(names, cmd.name)
+		scheduler.done(cmd)
+	}
+
+	return names
+}
+
+func TestColonySchedulerFairness(t *testing.T) {
+	scheduler := createColonyScheduler(CONCURRENT_QUEUE, createColonyLimits(), nil)
+
+	// Colony a floods the queue before colony b submits any commands
+	for i := 0; i < 4; i++ {
+		scheduler.push(createTestCommand("a", "a"))
+	}
+	scheduler.push(createTestCommand("b", "b"))
+	scheduler.push(createTestCommand("b", "b"))
+
+	assert.Equal(t, []string{"a", "b", "a", "b", "a", "a"}, popNames(scheduler, 6))
+}
+
+func TestColonySchedulerWeights(t *testing.T) {
+	limits := createColonyLimits()
+	limits.setWeight("a", 3)
+	scheduler := createColonyScheduler(CONCURRENT_QUEUE, limits, nil)
+
+	for i := 0; i < 6; i++ {
+		scheduler.push(createTestCommand("a", "a"))
+		scheduler.push(createTestCommand("b", "b"))
+	}
+
+	assert.Equal(t, []string{"a", "a", "a", "b", "a", "a", "a", "b", "b", "b", "b", "b"}, popNames(scheduler, 12))
+
+	limits.setWeight("a", 0) // Restores the default weight
+	assert.Equal(t, DEFAULT_COLONY_WEIGHT, limits.weight("a"))
+}
+
+func TestColonySchedulerDBBudget(t *testing.T) {
+	limits := createColonyLimits()
+	limits.setDBBudget(2)
+	scheduler := createColonyScheduler(CONCURRENT_QUEUE, limits, nil)
+
+	for i := 0; i < 3; i++ {
+		scheduler.push(createTestCommand("a", "a"))
+	}
+
+	cmd1 := scheduler.pop()
+	cmd2 := scheduler.pop()
+	assert.Equal(t, "a", cmd1.name)
+	assert.Equal(t, "a", cmd2.name)
+
+	// Colony a has used up its budget, but other colonies, and commands not bound to a colony, are not blocked
+	scheduler.push(createTestCommand("b", "b"))
+	scheduler.push(createTestCommand("", "system"))
+	assert.Equal(t, "b", scheduler.pop().name)
+	assert.Equal(t, "system", scheduler.pop().name)
+
+	popped := make(chan *command)
+	go func() {
+		popped <- scheduler.pop()
+	}()
+
+	select {
+	case <-popped:
+		assert.Fail(t, "Colony a exceeded its database budget")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	scheduler.done(cmd1)
+	cmd3 := <-popped
+	assert.Equal(t, "a", cmd3.name)
+
+	stats := scheduler.stats()
+	assert.Len(t, stats, 3)
+	assert.Equal(t, "a", stats[1].ColonyName)
+	assert.Equal(t, 0, stats[1].Depth)
+	assert.Equal(t, 2, stats[1].Running)
+	assert.Equal(t, int64(3), stats[1].Scheduled)
+}
+
+func TestColonySchedulerStop(t *testing.T) {
+	scheduler := createColonyScheduler(SERIAL_QUEUE, createColonyLimits(), nil)
+
+	popped := make(chan *command)
+	go func() {
+		popped <- scheduler.pop()
+	}()
+
+	scheduler.stop()
+	assert.Nil(t, <-popped)
+}
+
+func TestColonySchedulerSharedDBBudget(t *testing.T) {
+	limits := createColonyLimits()
+	limits.setDBBudget(1)
+	serialScheduler := createColonyScheduler(SERIAL_QUEUE, limits, nil)
+	concurrentScheduler := createColonyScheduler(CONCURRENT_QUEUE, limits, nil)
+
+	serialScheduler.push(createTestCommand("a", "serial"))
+	concurrentScheduler.push(createTestCommand("a", "concurrent"))
+
+	cmd := serialScheduler.pop()
+	assert.Equal(t, "serial", cmd.name)
+
+	popped := make(chan *command)
+	go func() {
+		popped <- concurrentScheduler.pop()
+	}()
+
+	// The budget of colony a is used up by the command of the other scheduler
+	select {
+	case <-popped:
+		assert.Fail(t, "Colony a exceeded its database budget")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	serialScheduler.done(cmd)
+	assert.Equal(t, "concurrent", (<-popped).name)
+}
+
+func TestColonySchedulerEvictIdleQueues(t *testing.T) {
+	scheduler := createColonyScheduler(CONCURRENT_QUEUE, createColonyLimits(), nil)
+
+	scheduler.push(createTestCommand("a", "a"))
+	scheduler.push(createTestCommand("b", "b"))
+	scheduler.push(createTestCommand("b", "b"))
+	cmd := scheduler.pop()
+	scheduler.done(cmd)
+	scheduler.pop() // Colony b still has a running command
+
+	scheduler.mutex.Lock()
+	scheduler.evictIdleQueues(time.Now().Add(COLONY_QUEUE_IDLE_TIMEOUT))
+	_, foundA := scheduler.queues["a"]
+	_, foundB := scheduler.queues["b"]
+	scheduler.mutex.Unlock()
+
+	assert.False(t, foundA)
+	assert.True(t, foundB)
+}
@@ -1,5 +1,7 @@
 package server
 
+import "time"
+
 const MAX_COUNT = 100
 const MAX_DAYS = 30
 const MAX_LOG_COUNT = 500
@@ -11,3 +13,56 @@ const GENERATOR_TRIGGER_PERIOD = 1000 // Period in milliseconds when generator i
 const CRON_TRIGGER_PERIOD = 1000      // Period in milliseconds when cron is run
 const MIN_PRIORITY = -50000
 const MAX_PRIORITY = 50000
+
+const DEFAULT_ANOMALY_FACTOR = 3.0                // Runs taking longer than this times the historical p95 exec time are flagged as anomalies
+const ANOMALY_HISTORY_PERIOD = 7 * 24 * time.Hour // Period of historical runs the p95 exec time is calculated from
+const MAX_ANOMALY_HISTORY = 1000                  // Max number of historical runs the p95 exec time is calculated from
+const MAX_FUNCTION_RUNS = 100000                  // Max number of runs function statistics are calculated from
+const MAX_FUNCTION_STATS_BUCKETS = 1000           // Max number of buckets in function statistics
+
+const STATISTICS_SNAPSHOT_PERIOD = time.Minute // Period when colony statistics are stored as snapshots
+const MAX_STATISTICS_SNAPSHOTS = 10000         // Max number of statistics snapshots returned
+
+const EVENT_TTL = 7 * 24 * time.Hour // Events older than this are removed from the event log, unless a retention policy removes them earlier
+const MAX_EVENTS = 100000            // Max number of events kept in the event log of a colony
+
+const HEALTH_CHECK_TIMEOUT = 2 * time.Second // Max time a health check may take before it fails
+
+const ALERT_EVALUATION_PERIOD = 10 * time.Second // Period when alert rules are evaluated
+const ALERT_EXECUTOR_ALIVE_PERIOD = time.Minute  // Executors not heard from within this period are not counted as alive
+
+const CLUSTER_MEMBERSHIP_SYNC_PERIOD = time.Second  // Period when relay targets are synced with the etcd members
+const CLUSTER_MEMBERSHIP_TIMEOUT = 30 * time.Second // Max time adding or removing a cluster node may take
+
+const DISCOVERY_PUBLISH_PERIOD = time.Minute       // Period when the server endpoint is republished in the DHT
+const DISCOVERY_PUBLISH_TIMEOUT = 30 * time.Second // Max time publishing the server endpoints may take
+
+const CRON_JOB = "cron"             // Background job triggering crons
+const GENERATOR_JOB = "generator"   // Background job triggering generators
+const RETENTION_JOB = "retention"   // Background job applying retention policies
+const TIMEOUT_JOB = "timeout"       // Background job handling max exec time and max wait time
+const WEBHOOK_JOB = "webhook"       // Background job delivering webhooks
+const ALERT_JOB = "alert"           // Background job evaluating alert rules
+const STATISTICS_JOB = "statistics" // Background job taking statistics snapshots
+const FEDERATION_JOB = "federation" // Background job forwarding processes to federation peers
+
+const JOB_ELECTION_TTL = 5                               // Lease time in seconds, after which another node takes over a job if the leader dies
+const JOB_ELECTION_SPREAD_DELAY = 200 * time.Millisecond // Campaign delay per job a node already leads, spreads jobs across nodes
+const JOB_LEADER_VERIFY_TIMEOUT = 2 * time.Second        // Max time verifying job leadership may take
+const JOB_FENCE_TTL = 60 * time.Second                   // Min age of a fencing token before leaders with older tokens are let through
+const JOB_FENCE_PERIODS = 2                              // Number of job periods added to JOB_FENCE_TTL, so that tokens outlive the runs of a job
+const JOB_LEADER_CACHE_PERIOD = 5 * time.Second          // Period when jobs verify their leadership against etcd and record their fencing token
+
+const FEDERATION_PERIOD = time.Second           // Period when processes are forwarded to and synced with federation peers
+const FEDERATION_MAX_HOPS = 8                   // Max number of servers a process may be forwarded through
+const FEDERATION_BATCH_SIZE = 100               // Max number of waiting processes of an executor type forwarded to a peer at a time
+const MAX_FEDERATED_PROCESSES = 10000           // Max number of forwarded processes synced with peers at a time
+const FEDERATION_RPC_TIMEOUT = 10 * time.Second // Max time an RPC to a federation peer may take
+
+const DEFAULT_COLONY_WEIGHT = 1                    // Share of the command queues a colony gets relative to other colonies, unless configured
+const DEFAULT_COLONY_DB_BUDGET = 16                // Max number of commands of a colony using the database at the same time, 0 means unlimited
+const COLONY_QUEUE_IDLE_TIMEOUT = 10 * time.Minute // Command queues of colonies idle this long are removed
+
+const DEFAULT_DRAIN_TIMEOUT = 60 * time.Second      // Max time draining a server may take before it is shut down anyway
+const DRAIN_POLL_PERIOD = 100 * time.Millisecond    // Period when a draining server checks if in-flight commands have finished
+const LEADERSHIP_TRANSFER_TIMEOUT = 5 * time.Second // Max time transferring etcd leadership may take when draining
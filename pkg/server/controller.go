@@ -1,20 +1,26 @@
 package server
 
 import (
+	"context"
 	"time"
 
 	"github.com/colonyos/colonies/pkg/cluster"
 	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/p2p/dht"
 )
 
 type controller interface {
 	getCronPeriod() int
 	getGeneratorPeriod() int
 	getEtcdServer() *cluster.EtcdServer
+	getRelayServer() *cluster.RelayServer
 	getEventHandler() *eventHandler
 	getThisNode() cluster.Node
-	subscribeProcesses(executorID string, subscription *subscription) error
-	subscribeProcess(executorID string, subscription *subscription) error
+	getMetrics() *serverMetrics
+	subscribeProcesses(executorID string, colonyName string, subscription *subscription) error
+	subscribeProcess(executorID string, colonyName string, subscription *subscription) error
+	subscribeEvents(executorID string, subscription *subscription) error
+	subscribeLogs(executorID string, subscription *subscription) error
 	getColonies() ([]*core.Colony, error)
 	getColony(colonyName string) (*core.Colony, error)
 	addColony(colony *core.Colony) (*core.Colony, error)
@@ -23,30 +29,30 @@ type controller interface {
 	getExecutor(executorID string) (*core.Executor, error)
 	getExecutorByColonyName(colonyName string) ([]*core.Executor, error)
 	addProcessToDB(process *core.Process) (*core.Process, error)
-	addProcess(process *core.Process) (*core.Process, error)
-	addChild(processGraphID string, parentProcessID string, childProcessID string, process *core.Process, executorID string, insert bool) (*core.Process, error)
+	addProcess(process *core.Process, ctx context.Context) (*core.Process, error)
+	addChild(processGraphID string, parentProcessID string, childProcessID string, process *core.Process, executorID string, insert bool, ctx context.Context) (*core.Process, error)
 	getProcess(processID string) (*core.Process, error)
 	findProcessHistory(colonyName string, executorID string, seconds int, state int) ([]*core.Process, error)
 	updateProcessGraph(graph *core.ProcessGraph) error
-	createProcessGraph(workflowSpec *core.WorkflowSpec, args []interface{}, kwargs map[string]interface{}, rootInput []interface{}, recoveredID string) (*core.ProcessGraph, error)
-	submitWorkflowSpec(workflowSpec *core.WorkflowSpec, recoveredID string) (*core.ProcessGraph, error)
+	createProcessGraph(workflowSpec *core.WorkflowSpec, args []interface{}, kwargs map[string]interface{}, rootInput []interface{}, recoveredID string, ctx context.Context) (*core.ProcessGraph, error)
+	submitWorkflowSpec(workflowSpec *core.WorkflowSpec, recoveredID string, ctx context.Context) (*core.ProcessGraph, error)
 	getProcessGraphByID(processGraphID string) (*core.ProcessGraph, error)
 	findWaitingProcessGraphs(colonyName string, count int) ([]*core.ProcessGraph, error)
 	findRunningProcessGraphs(colonyName string, count int) ([]*core.ProcessGraph, error)
 	findSuccessfulProcessGraphs(colonyName string, count int) ([]*core.ProcessGraph, error)
 	findFailedProcessGraphs(colonyName string, count int) ([]*core.ProcessGraph, error)
-	removeProcess(processID string) error
+	removeProcess(processID string, colonyName string) error
 	removeAllProcesses(colonyName string, state int) error
-	removeProcessGraph(processID string) error
+	removeProcessGraph(processID string, colonyName string) error
 	removeAllProcessGraphs(colonyName string, state int) error
-	setOutput(processID string, output []interface{}) error
-	closeSuccessful(processID string, executorID string, output []interface{}) error
+	setOutput(processID string, colonyName string, output []interface{}) error
+	closeSuccessful(processID string, colonyName string, executorID string, output []interface{}, ctx context.Context) error
 	notifyChildren(process *core.Process) error
-	closeFailed(processID string, errs []string) error
+	closeFailed(processID string, colonyName string, errs []string, ctx context.Context) error
 	handleDefunctProcessgraph(processGraphID string, processID string, err error) error
-	assign(executorID string, colonyName string, cpu int64, memory int64) (*AssignResult, error)
-	unassignExecutor(processID string) error
-	resetProcess(processID string) error
+	assign(executorID string, colonyName string, cpu int64, memory int64, ctx context.Context) (*AssignResult, error)
+	unassignExecutor(processID string, colonyName string) error
+	resetProcess(processID string, colonyName string) error
 	getColonyStatistics(colonyName string) (*core.Statistics, error)
 	getStatistics() (*core.Statistics, error)
 	addAttribute(attribute *core.Attribute) (*core.Attribute, error)
@@ -55,7 +61,7 @@ type controller interface {
 	getFunctionsByExecutorName(colonyName string, executorName string) ([]*core.Function, error)
 	getFunctionsByColonyName(colonyName string) ([]*core.Function, error)
 	getFunctionByID(functionID string) (*core.Function, error)
-	removeFunction(functionID string) error
+	removeFunction(functionID string, colonyName string) error
 	addGenerator(generator *core.Generator) (*core.Generator, error)
 	getGenerator(generatorID string) (*core.Generator, error)
 	resolveGenerator(colonyName string, generatorName string) (*core.Generator, error)
@@ -65,11 +71,11 @@ type controller interface {
 	triggerGenerators()
 	submitWorkflow(generator *core.Generator, counter int, recoveredID string) // TODO: change name, there is also a submitWorkflowSpec()
 	addCron(cron *core.Cron) (*core.Cron, error)
-	removeGenerator(generatorID string) error
+	removeGenerator(generatorID string, colonyName string) error
 	getCron(cronID string) (*core.Cron, error)
 	getCrons(colonyName string, count int) ([]*core.Cron, error)
-	runCron(cronID string) (*core.Cron, error)
-	removeCron(cronID string) error
+	runCron(cronID string, colonyName string) (*core.Cron, error)
+	removeCron(cronID string, colonyName string) error
 	calcNextRun(cron *core.Cron) time.Time
 	startCron(cron *core.Cron)
 	triggerCrons()
@@ -79,10 +85,34 @@ type controller interface {
 	resumeColonyAssignments(colonyName string) error
 	areColonyAssignmentsPaused(colonyName string) (bool, error)
 	stop()
+	drain()
+	isDraining() bool
+	waitForCommands(ctx context.Context) error
+	handOffLeadership()
 	isLeader() bool
 	tryBecomeLeader() bool
+	isJobLeader(job string) bool
 	timeoutLoop()
 	blockingCmdQueueWorker()
 	retentionWorker()
+	webhookWorker()
+	setFileStore(fileStore FileStore)
+	setAnomalyFactor(factor float64)
+	statisticsWorker()
+	setStatisticsPeriod(period time.Duration)
+	setColonyWeight(colonyName string, weight int)
+	setColonyDBBudget(budget int)
+	discoveryWorker()
+	setDiscovery(d dht.DHT, serverPrvKey string, endpoint *core.ServerEndpoint)
+	publishEndpoints(now time.Time) error
+	setFederation(serverPrvKey string, skipTLSVerify bool)
+	federationWorker()
+	forwardProcesses()
+	syncFederatedProcesses()
+	alertWorker()
+	evaluateAlertRules(now time.Time)
+	clusterMembershipWorker()
+	syncClusterMembership() bool
+	probeCmdQueue(timeout time.Duration) (time.Duration, error)
 	cmdQueueWorker()
 }
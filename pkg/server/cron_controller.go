@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
@@ -9,7 +10,7 @@ import (
 )
 
 func (controller *coloniesController) addCron(cron *core.Cron) (*core.Cron, error) {
-	cmd := &command{cronReplyChan: make(chan *core.Cron, 1),
+	cmd := &command{colonyName: cron.ColonyName, cronReplyChan: make(chan *core.Cron, 1),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			err := controller.db.AddCron(cron)
@@ -22,6 +23,7 @@ func (controller *coloniesController) addCron(cron *core.Cron) (*core.Cron, erro
 				cmd.errorChan <- err
 				return
 			}
+			controller.eventHandler.publish(core.CreateCronEvent(core.CronAddedEvent, addedCron))
 			cmd.cronReplyChan <- addedCron
 		}}
 
@@ -34,10 +36,25 @@ func (controller *coloniesController) addCron(cron *core.Cron) (*core.Cron, erro
 	}
 }
 
-func (controller *coloniesController) removeGenerator(generatorID string) error {
-	cmd := &command{errorChan: make(chan error, 1),
+func (controller *coloniesController) removeGenerator(generatorID string, colonyName string) error {
+	cmd := &command{colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
-			cmd.errorChan <- controller.db.RemoveGeneratorByID(generatorID)
+			generator, err := controller.db.GetGeneratorByID(generatorID)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+
+			err = controller.db.RemoveGeneratorByID(generatorID)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+
+			if generator != nil {
+				controller.eventHandler.publish(core.CreateGeneratorEvent(core.GeneratorRemovedEvent, generator))
+			}
+			cmd.errorChan <- nil
 		}}
 
 	controller.cmdQueue <- cmd
@@ -66,7 +83,7 @@ func (controller *coloniesController) getCron(cronID string) (*core.Cron, error)
 }
 
 func (controller *coloniesController) getCrons(colonyName string, count int) ([]*core.Cron, error) {
-	cmd := &command{cronsReplyChan: make(chan []*core.Cron, 1),
+	cmd := &command{colonyName: colonyName, cronsReplyChan: make(chan []*core.Cron, 1),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			crons, err := controller.db.FindCronsByColonyName(colonyName, count)
@@ -86,8 +103,8 @@ func (controller *coloniesController) getCrons(colonyName string, count int) ([]
 	}
 }
 
-func (controller *coloniesController) runCron(cronID string) (*core.Cron, error) {
-	cmd := &command{cronReplyChan: make(chan *core.Cron, 1),
+func (controller *coloniesController) runCron(cronID string, colonyName string) (*core.Cron, error) {
+	cmd := &command{colonyName: colonyName, cronReplyChan: make(chan *core.Cron, 1),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			cron, err := controller.db.GetCronByID(cronID)
@@ -108,11 +125,25 @@ func (controller *coloniesController) runCron(cronID string) (*core.Cron, error)
 	}
 }
 
-func (controller *coloniesController) removeCron(cronID string) error {
-	cmd := &command{errorChan: make(chan error, 1),
+func (controller *coloniesController) removeCron(cronID string, colonyName string) error {
+	cmd := &command{colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
-			err := controller.db.RemoveCronByID(cronID)
-			cmd.errorChan <- err
+			cron, err := controller.db.GetCronByID(cronID)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+
+			err = controller.db.RemoveCronByID(cronID)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+
+			if cron != nil {
+				controller.eventHandler.publish(core.CreateCronEvent(core.CronRemovedEvent, cron))
+			}
+			cmd.errorChan <- nil
 		}}
 
 	controller.cmdQueue <- cmd
@@ -169,7 +200,7 @@ func (controller *coloniesController) startCron(cron *core.Cron) {
 		}
 	}
 
-	processGraph, err := controller.createProcessGraph(workflowSpec, make([]interface{}, 0), make(map[string]interface{}), rootInput, cron.InitiatorID)
+	processGraph, err := controller.createProcessGraph(workflowSpec, make([]interface{}, 0), make(map[string]interface{}), rootInput, cron.InitiatorID, context.Background())
 	if err != nil {
 		log.WithFields(log.Fields{"Error": err, "CronId": cron.ID}).Error("Failed to create cron processgraph")
 		return
@@ -177,10 +208,16 @@ func (controller *coloniesController) startCron(cron *core.Cron) {
 
 	nextRun := controller.calcNextRun(cron)
 	controller.db.UpdateCron(cron.ID, nextRun, time.Now(), processGraph.ID)
+
+	controller.eventHandler.publish(core.CreateCronEvent(core.CronTriggeredEvent, cron))
 }
 
+// triggerCrons starts the crons that have expired, and returns when they have been started so that the run is fenced
+// by runJob until then
 func (controller *coloniesController) triggerCrons() {
-	cmd := &command{handler: func(cmd *command) {
+	cmd := &command{errorChan: make(chan error, 1), handler: func(cmd *command) {
+		defer func() { cmd.errorChan <- nil }()
+
 		crons, err := controller.db.FindAllCrons()
 		if err != nil {
 			log.WithFields(log.Fields{"Error": err}).Error("Failed getting all crons")
@@ -218,6 +255,7 @@ func (controller *coloniesController) triggerCrons() {
 	}}
 
 	controller.cmdQueue <- cmd
+	<-cmd.errorChan
 }
 
 func (controller *coloniesController) cronTriggerLoop() {
@@ -230,9 +268,6 @@ func (controller *coloniesController) cronTriggerLoop() {
 		}
 		controller.stopMutex.Unlock()
 
-		isLeader := controller.tryBecomeLeader()
-		if isLeader {
-			controller.triggerCrons()
-		}
+		controller.runJob(CRON_JOB, controller.triggerCrons)
 	}
 }
@@ -181,7 +181,7 @@ func (server *ColoniesServer) handleRunCronHTTPRequest(c *gin.Context, recovered
 		return
 	}
 
-	cron, err := server.controller.runCron(msg.CronID)
+	cron, err := server.controller.getCron(msg.CronID)
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		return
 	}
@@ -195,6 +195,15 @@ func (server *ColoniesServer) handleRunCronHTTPRequest(c *gin.Context, recovered
 		return
 	}
 
+	cron, err = server.controller.runCron(cron.ID, cron.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+	if cron == nil {
+		server.handleHTTPError(c, errors.New("Failed to run cron, cron is nil"), http.StatusInternalServerError)
+		return
+	}
+
 	jsonString, err = cron.ToJSON()
 	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
 		return
@@ -232,7 +241,7 @@ func (server *ColoniesServer) handleRemoveCronHTTPRequest(c *gin.Context, recove
 		return
 	}
 
-	err = server.controller.removeCron(cron.ID)
+	err = server.controller.removeCron(cron.ID, cron.ColonyName)
 	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
 		return
 	}
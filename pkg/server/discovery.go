@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/p2p/dht"
+	log "github.com/sirupsen/logrus"
+)
+
+// setDiscovery makes the server publish its endpoint, and the colonies it serves, in the DHT. The values are signed
+// with the server private key and stored under the server ID, so that only the server owner can publish endpoints.
+func (controller *coloniesController) setDiscovery(d dht.DHT, serverPrvKey string, endpoint *core.ServerEndpoint) {
+	controller.discoveryMutex.Lock()
+	defer controller.discoveryMutex.Unlock()
+
+	controller.discovery = d
+	controller.discoveryPrvKey = serverPrvKey
+	controller.discoveryEndpoint = endpoint
+}
+
+// publishEndpoints publishes the endpoint of this server under its own key and under the key of every colony. A
+// colony that fails to be published is logged and does not prevent the other colonies from being published.
+func (controller *coloniesController) publishEndpoints(now time.Time) error {
+	controller.discoveryMutex.Lock()
+	d := controller.discovery
+	prvKey := controller.discoveryPrvKey
+	endpoint := *controller.discoveryEndpoint
+	controller.discoveryMutex.Unlock()
+
+	serverID, err := controller.db.GetServerID()
+	if err != nil {
+		return err
+	}
+
+	colonies, err := controller.db.GetColonies()
+	if err != nil {
+		return err
+	}
+
+	endpoint.Published = now
+	jsonString, err := endpoint.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DISCOVERY_PUBLISH_TIMEOUT)
+	defer cancel()
+
+	serverKey := core.DiscoveryServerKey(endpoint.Name)
+	err = d.Put(serverID, prvKey, serverKey, jsonString, dht.RegistrationReplicationFactor, ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, colony := range colonies {
+		err = d.Put(serverID, prvKey, core.DiscoveryColonyKey(colony.Name)+serverKey, jsonString, dht.RegistrationReplicationFactor, ctx)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ColonyName": colony.Name}).Warn("Failed to publish colony endpoint")
+		}
+	}
+
+	return nil
+}
+
+// discoveryWorker periodically publishes the endpoints of this server. Every server publishes its own endpoint, and
+// endpoints are republished so that clients can tell servers that are gone from servers that are alive.
+func (controller *coloniesController) discoveryWorker() {
+	var lastPublish time.Time
+	for {
+		time.Sleep(time.Second)
+
+		controller.stopMutex.Lock()
+		stopped := controller.stopFlag
+		controller.stopMutex.Unlock()
+		if stopped {
+			return
+		}
+
+		controller.discoveryMutex.Lock()
+		enabled := controller.discovery != nil
+		controller.discoveryMutex.Unlock()
+		if !enabled || time.Since(lastPublish) < DISCOVERY_PUBLISH_PERIOD {
+			continue
+		}
+
+		lastPublish = time.Now()
+		err := controller.publishEndpoints(lastPublish)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err}).Warn("Failed to publish server endpoint")
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/p2p"
+	"github.com/colonyos/colonies/pkg/p2p/dht"
+	"github.com/colonyos/colonies/pkg/p2p/mock"
+	"github.com/colonyos/colonies/pkg/security/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestDHT(t *testing.T, n mock.Network, addr string) *dht.Kademlia {
+	prvKey, err := crypto.CreateCrypto().GeneratePrivateKey()
+	assert.Nil(t, err)
+
+	node := p2p.Node{Addr: addr}
+	contact, err := dht.CreateContact(node, prvKey)
+	assert.Nil(t, err)
+
+	k, err := dht.CreateKademlia(mock.CreateMessenger(n, node), contact)
+	assert.Nil(t, err)
+
+	return k
+}
+
+func TestDiscovery(t *testing.T) {
+	env, _, server, serverPrvKey, done := setupTestEnv2(t)
+
+	n := mock.CreateFakeNetwork()
+	serverDHT := createTestDHT(t, n, "localhost:8000")
+	clientDHT := createTestDHT(t, n, "localhost:8001")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	err := clientDHT.RegisterNetwork(serverDHT.GetContact().Node, clientDHT.Contact.ID.String(), ctx)
+	cancel()
+	assert.Nil(t, err)
+
+	endpoint := core.CreateServerEndpoint("server1", TESTHOST, TESTPORT, EnableTLS)
+
+	// Only the server owner may publish endpoints
+	otherPrvKey, err := crypto.CreateCrypto().GeneratePrivateKey()
+	assert.Nil(t, err)
+	err = server.SetDiscovery(serverDHT, otherPrvKey, endpoint)
+	assert.NotNil(t, err)
+
+	err = server.SetDiscovery(serverDHT, serverPrvKey, endpoint)
+	assert.Nil(t, err)
+
+	err = server.controller.publishEndpoints(time.Now())
+	assert.Nil(t, err)
+
+	serverID, err := server.getServerID()
+	assert.Nil(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	endpoints, err := client.ResolveColony(clientDHT, serverID, env.colonyName, ctx)
+	cancel()
+	assert.Nil(t, err)
+	assert.Len(t, endpoints, 1)
+	assert.True(t, endpoints[0].Equals(endpoint))
+
+	c := client.CreateColoniesClientFromEndpoint(endpoints[0], SkipTLSVerify)
+	colony, err := c.GetColonyByName(env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, env.colonyID, colony.ID)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	_, err = client.ResolveColony(clientDHT, serverID, "does_not_exist", ctx)
+	cancel()
+	assert.NotNil(t, err)
+
+	serverDHT.Shutdown()
+	clientDHT.Shutdown()
+	server.Shutdown()
+	<-done
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// Drain shuts down the server without dropping clients, so that cluster nodes can be upgraded one at a time. New
+// long-polls and subscriptions are rejected, waiting assigns and subscriptions are told to reconnect to another
+// server, in-flight requests and commands are completed, and leader-only jobs and etcd leadership are handed off to
+// other nodes before the server is shut down. The server is shut down anyway when the timeout expires. Drain blocks
+// until the server has been shut down, calling it again only waits for the first call to finish.
+func (server *ColoniesServer) Drain(timeout time.Duration) {
+	server.drainOnce.Do(func() {
+		log.WithFields(log.Fields{"Timeout": timeout}).Info("Draining Colonies server")
+		start := time.Now()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		server.drainCancel()
+		server.controller.drain()
+
+		// Closes the listeners and waits for in-flight requests, which no longer includes long-polls
+		if err := server.httpServer.Shutdown(ctx); err != nil {
+			log.WithFields(log.Fields{"Error": err}).Warning("Timed out waiting for in-flight requests")
+		}
+
+		if err := server.controller.waitForCommands(ctx); err != nil {
+			log.WithFields(log.Fields{"Error": err}).Warning("Timed out waiting for in-flight commands")
+		}
+
+		server.controller.handOffLeadership()
+		server.Shutdown()
+
+		log.WithFields(log.Fields{"Duration": time.Since(start)}).Info("Colonies server drained")
+		close(server.drained)
+	})
+
+	<-server.drained
+}
+
+// Drained returns a channel that is closed when the server has been drained
+func (server *ColoniesServer) Drained() <-chan struct{} {
+	return server.drained
+}
+
+// IsDraining returns true if the server is draining or has been drained
+func (server *ColoniesServer) IsDraining() bool {
+	return server.drainCtx != nil && server.drainCtx.Err() != nil
+}
+
+// cancelOnDrain makes the context be cancelled when the server starts draining, the returned function stops it
+func (server *ColoniesServer) cancelOnDrain(cancelCtx context.CancelFunc) func() bool {
+	if server.drainCtx == nil {
+		return func() bool { return false }
+	}
+
+	return context.AfterFunc(server.drainCtx, cancelCtx)
+}
+
+// closeConnectionsOnDrain makes clients close keep-alive connections to a draining server, so that their next
+// request is sent to another server
+func (server *ColoniesServer) closeConnectionsOnDrain(c *gin.Context) {
+	if server.IsDraining() {
+		c.Header("Connection", "close")
+	}
+	c.Next()
+}
+
+func (server *ColoniesServer) drainError() error {
+	return errors.New(core.ServerDrainingMessage)
+}
+
+// drain stops leader-only jobs from running and ends all websocket subscriptions
+func (controller *coloniesController) drain() {
+	controller.stopMutex.Lock()
+	controller.draining = true
+	controller.stopMutex.Unlock()
+
+	controller.wsSubCtrl.drain()
+}
+
+func (controller *coloniesController) isDraining() bool {
+	controller.stopMutex.Lock()
+	defer controller.stopMutex.Unlock()
+
+	return controller.draining
+}
+
+// waitForCommands waits until all queued and running commands have finished, or the context is done
+func (controller *coloniesController) waitForCommands(ctx context.Context) error {
+	for !controller.cmdScheduler.idle() || !controller.blockingCmdScheduler.idle() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(DRAIN_POLL_PERIOD):
+		}
+	}
+
+	return nil
+}
+
+// handOffLeadership resigns the background jobs this node leads and transfers etcd leadership, so that other nodes
+// take over immediately instead of when the leases expire
+func (controller *coloniesController) handOffLeadership() {
+	controller.stopJobElections()
+
+	ctx, cancel := context.WithTimeout(context.Background(), LEADERSHIP_TRANSFER_TIMEOUT)
+	defer cancel()
+
+	err := controller.etcdServer.TransferLeadership(ctx)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Warn("Failed to transfer etcd leadership")
+	}
+}
@@ -2,18 +2,23 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"strconv"
 	"sync"
+	"sync/atomic"
 
 	"github.com/colonyos/colonies/pkg/cluster"
 	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
 	log "github.com/sirupsen/logrus"
 )
 
 type eventHandler struct {
 	listeners         map[string]map[string]chan *core.Process
 	processIDs        map[string]string
+	eventListeners    map[string]*eventListener
+	logListeners      map[string]*logListener
 	msgQueue          chan *message
 	idCounter         int
 	stopped           bool
@@ -21,6 +26,30 @@ type eventHandler struct {
 	relayServer       *cluster.RelayServer
 	relayChan         chan []byte
 	stopRelayListener chan struct{}
+	db                database.Database // Event log where published events are stored, events are not stored if nil
+	persistQueue      chan *core.Event
+	stopPersistWorker chan struct{}
+	unpersisted       int64 // Number of events published without being stored as the persist queue was full
+}
+
+const eventReplayBatchSize = 100
+const eventPersistQueueSize = 10000
+const eventPersistBatchSize = 100
+
+type eventListener struct {
+	filter *core.EventFilter
+	c      chan *core.Event
+}
+
+type logListener struct {
+	filter *core.LogFilter
+	c      chan *core.Log
+}
+
+// relayEvent wraps events and logs broadcasted to other servers, so that they can be told apart from signaled processes
+type relayEvent struct {
+	Event *core.Event `json:"event,omitempty"`
+	Log   *core.Log   `json:"log,omitempty"`
 }
 
 type message struct {
@@ -31,6 +60,8 @@ type message struct {
 
 type replyMessage struct {
 	processChan  chan *core.Process
+	eventChan    chan *core.Event
+	logChan      chan *core.Log
 	listenerID   string
 	allListeners int  // Just for testing purposes
 	listeners    int  // Just for testing purposes
@@ -38,10 +69,13 @@ type replyMessage struct {
 	stopped      bool // Just for testing purposes
 }
 
-func createEventHandler(relayServer *cluster.RelayServer) *eventHandler {
+func createEventHandler(relayServer *cluster.RelayServer, db database.Database) *eventHandler {
 	handler := &eventHandler{}
+	handler.db = db
 	handler.listeners = make(map[string]map[string]chan *core.Process)
 	handler.processIDs = make(map[string]string)
+	handler.eventListeners = make(map[string]*eventListener)
+	handler.logListeners = make(map[string]*logListener)
 	handler.msgQueue = make(chan *message)
 	handler.relayServer = relayServer
 
@@ -52,6 +86,12 @@ func createEventHandler(relayServer *cluster.RelayServer) *eventHandler {
 	// Start master worker
 	go handler.masterWorker()
 
+	if db != nil {
+		handler.persistQueue = make(chan *core.Event, eventPersistQueueSize)
+		handler.stopPersistWorker = make(chan struct{})
+		go handler.persistWorker()
+	}
+
 	if relayServer != nil {
 		handler.stopRelayListener = make(chan struct{})
 		handler.relayChan = relayServer.Receive()
@@ -65,6 +105,16 @@ func (handler *eventHandler) relayListener() {
 	for {
 		select {
 		case msg := <-handler.relayChan:
+			var r relayEvent
+			if err := json.Unmarshal(msg, &r); err == nil && r.Event != nil {
+				handler.publishNoRelay(r.Event)
+				continue
+			}
+			if r.Log != nil {
+				handler.sendLog(r.Log)
+				continue
+			}
+
 			process, err := core.ConvertJSONToProcess(string(msg))
 			if err != nil {
 				log.WithFields(log.Fields{"Error": err}).Warning("relayListener received invalid process JSON")
@@ -131,6 +181,30 @@ func (handler *eventHandler) unregister(executorType string, state int, listener
 	}
 }
 
+func (handler *eventHandler) registerEventListener(filter *core.EventFilter) (string, chan *core.Event) {
+	c := make(chan *core.Event, 100)
+	listenerID := strconv.Itoa(handler.idCounter)
+	handler.eventListeners[listenerID] = &eventListener{filter: filter, c: c}
+	handler.idCounter++
+	return listenerID, c
+}
+
+func (handler *eventHandler) unregisterEventListener(listenerID string) {
+	delete(handler.eventListeners, listenerID)
+}
+
+func (handler *eventHandler) registerLogListener(filter *core.LogFilter) (string, chan *core.Log) {
+	c := make(chan *core.Log, 100)
+	listenerID := strconv.Itoa(handler.idCounter)
+	handler.logListeners[listenerID] = &logListener{filter: filter, c: c}
+	handler.idCounter++
+	return listenerID, c
+}
+
+func (handler *eventHandler) unregisterLogListener(listenerID string) {
+	delete(handler.logListeners, listenerID)
+}
+
 func (handler *eventHandler) sendSignal(process *core.Process) {
 	msg := &message{reply: make(chan replyMessage, 100), handler: func(msg *message) {
 		t := handler.target(process.FunctionSpec.Conditions.ExecutorType, process.State)
@@ -169,6 +243,155 @@ func (handler *eventHandler) signal(process *core.Process) {
 	}()
 }
 
+func (handler *eventHandler) sendEvent(event *core.Event) {
+	msg := &message{reply: make(chan replyMessage, 100), handler: func(msg *message) {
+		for listenerID, listener := range handler.eventListeners {
+			if listener.filter == nil || listener.filter.Match(event) {
+				select {
+				case listener.c <- event:
+				default:
+					// A slow subscriber must not block the masterworker, and thus all other subscribers
+					log.WithFields(log.Fields{"ListenerID": listenerID, "EventType": event.Type}).Warning("Event listener queue is full, dropping event")
+				}
+			}
+		}
+	}}
+
+	handler.msgQueue <- msg // Send the message to the masterworker
+}
+
+func (handler *eventHandler) publishNoRelay(event *core.Event) {
+	handler.sendEvent(event)
+}
+
+// publish sends an event to all subscribers on this server and broadcasts it to the other servers in the cluster. If
+// there is an event log, the event is queued and published by the persist worker once it has been stored, so that
+// callers do not wait for the database. If the queue is full, the event is published at once without being stored, and
+// thus without a sequence number, rather than blocking the caller until the database catches up.
+func (handler *eventHandler) publish(event *core.Event) {
+	if handler == nil {
+		return
+	}
+
+	if handler.persistQueue != nil {
+		select {
+		case handler.persistQueue <- event:
+			return
+		default:
+			unpersisted := atomic.AddInt64(&handler.unpersisted, 1)
+			log.WithFields(log.Fields{"EventType": event.Type, "Unpersisted": unpersisted}).Warning("Event persist queue is full, publishing event without storing it")
+		}
+	}
+
+	handler.sendEvent(event)
+	handler.relay([]*core.Event{event})
+}
+
+// relay broadcasts the events to the other servers in the cluster. Broadcast only queues the events for every peer, so
+// relay does not block, and events relayed by the same goroutine reach every peer in order.
+func (handler *eventHandler) relay(events []*core.Event) {
+	if handler.relayServer == nil {
+		return
+	}
+
+	for _, event := range events {
+		jsonBytes, err := json.Marshal(&relayEvent{Event: event})
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err}).Error("Failed to parse JSON in publish")
+			continue
+		}
+		handler.relayServer.Broadcast(jsonBytes)
+	}
+}
+
+func (handler *eventHandler) unpersistedEvents() int64 {
+	return atomic.LoadInt64(&handler.unpersisted)
+}
+
+// persistWorker stores queued events in batches, and then publishes them. Storing, sending and relaying events from a
+// single worker makes subscribers on this server, and on the other servers, receive them in sequence order.
+func (handler *eventHandler) persistWorker() {
+	for {
+		select {
+		case event := <-handler.persistQueue:
+			events := []*core.Event{event}
+		batch:
+			for len(events) < eventPersistBatchSize {
+				select {
+				case event := <-handler.persistQueue:
+					events = append(events, event)
+				default:
+					break batch
+				}
+			}
+
+			handler.persist(events)
+			for _, event := range events {
+				handler.sendEvent(event)
+			}
+			handler.relay(events)
+		case <-handler.stopPersistWorker:
+			return
+		}
+	}
+}
+
+// persist stores compacted copies of the events in the event log, and assigns the sequence numbers they got to the
+// events. Events that could not be stored are still published, but without a sequence number.
+func (handler *eventHandler) persist(events []*core.Event) {
+	compacted := make([]*core.Event, len(events))
+	for i, event := range events {
+		compacted[i] = event.Compact()
+	}
+
+	err := handler.db.AddEvents(compacted)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "Count": len(events)}).Error("Failed to add events to event log")
+		return
+	}
+
+	for i, event := range events {
+		event.Seq = compacted[i].Seq
+	}
+}
+
+func (handler *eventHandler) sendLog(l *core.Log) {
+	msg := &message{reply: make(chan replyMessage, 100), handler: func(msg *message) {
+		for listenerID, listener := range handler.logListeners {
+			if listener.filter.Match(l) {
+				select {
+				case listener.c <- l:
+				default:
+					log.WithFields(log.Fields{"ListenerID": listenerID, "ProcessId": l.ProcessID}).Warning("Log listener queue is full, dropping log")
+				}
+			}
+		}
+	}}
+
+	handler.msgQueue <- msg // Send the message to the masterworker
+}
+
+// publishLog sends a log, which has already been stored, to all log subscribers on this server and broadcasts it to the
+// other servers in the cluster
+func (handler *eventHandler) publishLog(l *core.Log) {
+	if handler == nil {
+		return
+	}
+
+	handler.sendLog(l)
+
+	go func() {
+		if handler.relayServer != nil {
+			jsonBytes, err := json.Marshal(&relayEvent{Log: l})
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err}).Error("Failed to parse JSON in publishLog")
+				return
+			}
+			handler.relayServer.Broadcast(jsonBytes)
+		}
+	}()
+}
+
 func (handler *eventHandler) waitForProcess(executorType string, state int, processID string, ctx context.Context) (*core.Process, error) {
 	// Register
 	msg := &message{reply: make(chan replyMessage, 100), handler: func(msg *message) {
@@ -234,7 +457,211 @@ func (handler *eventHandler) subscribe(executorType string, state int, processID
 	return processChan, errChan
 }
 
+// subscribeEvents sends events matching filter to the returned channel until ctx is done. If fromSeq is greater than 0,
+// events stored in the event log with a sequence number greater than or equal to fromSeq are replayed first, which makes
+// it possible to resume a subscription without missing events. A nil filter matches all events, but cannot be replayed.
+func (handler *eventHandler) subscribeEvents(filter *core.EventFilter, fromSeq int64, ctx context.Context) (chan *core.Event, chan error) {
+	replayable := filter != nil && handler.db != nil
+	listenerFilter := filter
+	if replayable {
+		// All events of the colony are listened to, so that gaps in the sequence numbers reveal missed events
+		listenerFilter = &core.EventFilter{ColonyName: filter.ColonyName}
+	}
+
+	// Register
+	msg := &message{reply: make(chan replyMessage, 100), handler: func(msg *message) {
+		listenerID, c := handler.registerEventListener(listenerFilter)
+		r := replyMessage{eventChan: c, listenerID: listenerID}
+		msg.reply <- r
+	}}
+	handler.msgQueue <- msg
+
+	// Wait for the masterworker to execute the handler code
+	r := <-msg.reply
+
+	eventChan := make(chan *core.Event, 100)
+	errChan := make(chan error)
+
+	unregister := func() {
+		msg := &message{reply: make(chan replyMessage, 100), handler: func(msg *message) {
+			handler.unregisterEventListener(r.listenerID)
+		}}
+		handler.msgQueue <- msg
+		errChan <- errors.New("timeout")
+	}
+
+	// Published events are buffered by the listener while replaying, and skipped if they have already been replayed
+	var lastSeq int64
+	var synced bool // Set once lastSeq is known, either from fromSeq or from the first published event
+
+	// replay sends stored events after lastSeq and before toSeq, or all stored events if toSeq is 0, it returns false
+	// if ctx is done
+	replay := func(toSeq int64) bool {
+		for {
+			events, err := handler.db.GetEvents(filter.ColonyName, lastSeq+1, eventReplayBatchSize)
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err, "ColonyName": filter.ColonyName, "FromSeq": lastSeq + 1}).Error("Failed to replay events")
+				return true
+			}
+
+			for _, event := range events {
+				if toSeq > 0 && event.Seq >= toSeq {
+					return true
+				}
+				lastSeq = event.Seq
+				if !filter.Match(event) {
+					continue
+				}
+				select {
+				case eventChan <- event:
+				case <-ctx.Done():
+					return false
+				}
+			}
+
+			if len(events) < eventReplayBatchSize {
+				return true
+			}
+		}
+	}
+
+	go func() {
+		if fromSeq > 0 && replayable {
+			lastSeq = fromSeq - 1
+			synced = true
+			if !replay(0) {
+				unregister()
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				unregister()
+				return
+			case event := <-r.eventChan:
+				if replayable && event.Seq > 0 {
+					if event.Seq <= lastSeq {
+						continue
+					}
+					// Events relayed from other servers may arrive out of order, and events are dropped if the
+					// listener queue is full, so missed events are replayed from the event log before this one
+					if synced && event.Seq > lastSeq+1 {
+						if !replay(event.Seq) {
+							unregister()
+							return
+						}
+					}
+					lastSeq = event.Seq
+					synced = true
+					if !filter.Match(event) {
+						continue
+					}
+				}
+				eventChan <- event
+			}
+		}
+	}()
+
+	return eventChan, errChan
+}
+
+// subscribeLogs sends logs matching filter to the returned channel until ctx is done. If since is greater than 0 and the
+// filter selects a process or an executor, stored logs added after since are replayed first.
+func (handler *eventHandler) subscribeLogs(filter *core.LogFilter, since int64, ctx context.Context) (chan *core.Log, chan error) {
+	// Register
+	msg := &message{reply: make(chan replyMessage, 100), handler: func(msg *message) {
+		listenerID, c := handler.registerLogListener(filter)
+		r := replyMessage{logChan: c, listenerID: listenerID}
+		msg.reply <- r
+	}}
+	handler.msgQueue <- msg
+
+	// Wait for the masterworker to execute the handler code
+	r := <-msg.reply
+
+	logChan := make(chan *core.Log, 100)
+	errChan := make(chan error)
+
+	unregister := func() {
+		msg := &message{reply: make(chan replyMessage, 100), handler: func(msg *message) {
+			handler.unregisterLogListener(r.listenerID)
+		}}
+		handler.msgQueue <- msg
+		errChan <- errors.New("timeout")
+	}
+
+	go func() {
+		// Published logs are buffered by the listener while replaying, and skipped if they have already been replayed
+		var lastTimestamp int64
+		if since > 0 && (filter.ProcessID != "" || filter.ExecutorName != "") && handler.db != nil {
+			lastTimestamp = since
+			for {
+				var logs []*core.Log
+				var err error
+				if filter.ProcessID != "" {
+					logs, err = handler.db.GetLogsByProcessIDSince(filter.ProcessID, eventReplayBatchSize, lastTimestamp)
+				} else {
+					logs, err = handler.db.GetLogsByExecutorSince(filter.ExecutorName, eventReplayBatchSize, lastTimestamp)
+				}
+				if err != nil {
+					log.WithFields(log.Fields{"Error": err, "ProcessId": filter.ProcessID, "ExecutorName": filter.ExecutorName}).Error("Failed to replay logs")
+					break
+				}
+
+				for _, l := range logs {
+					lastTimestamp = l.Timestamp
+					if !filter.Match(l) {
+						continue
+					}
+					select {
+					case logChan <- l:
+					case <-ctx.Done():
+						unregister()
+						return
+					}
+				}
+
+				if len(logs) < eventReplayBatchSize {
+					break
+				}
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				unregister()
+				return
+			case l := <-r.logChan:
+				if l.Timestamp <= lastTimestamp {
+					continue
+				}
+				logChan <- l
+			}
+		}
+	}()
+
+	return logChan, errChan
+}
+
+func (handler *eventHandler) numberOfLogListeners() int { // Just for testing purposes
+	msg := &message{reply: make(chan replyMessage, 100), handler: func(msg *message) {
+		r := replyMessage{listeners: len(handler.logListeners)}
+		msg.reply <- r
+	}}
+
+	handler.msgQueue <- msg
+	r := <-msg.reply
+
+	return r.listeners
+}
+
 func (handler *eventHandler) stop() {
+	if handler.stopPersistWorker != nil {
+		close(handler.stopPersistWorker)
+	}
 	handler.msgQueue <- &message{stop: true}
 	if handler.relayServer != nil {
 		handler.stopRelayListener <- struct{}{}
@@ -257,6 +684,18 @@ func (handler *eventHandler) numberOfListeners(executorType string, state int) (
 	return r.allListeners, r.listeners, r.processIDs
 }
 
+func (handler *eventHandler) numberOfEventListeners() int { // Just for testing purposes
+	msg := &message{reply: make(chan replyMessage, 100), handler: func(msg *message) {
+		r := replyMessage{listeners: len(handler.eventListeners)}
+		msg.reply <- r
+	}}
+
+	handler.msgQueue <- msg
+	r := <-msg.reply
+
+	return r.listeners
+}
+
 func (handler *eventHandler) hasStopped() bool { // Just for testing purposes
 	handler.mutex.Lock()
 	defer handler.mutex.Unlock()
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,6 +17,50 @@ type retValues struct {
 	err     error
 }
 
+// eventLogMock is an in-memory event log
+type eventLogMock struct {
+	dbMock
+	mutex  sync.Mutex
+	events []*core.Event
+}
+
+func (db *eventLogMock) AddEvents(events []*core.Event) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	for _, event := range events {
+		event.Seq = int64(len(db.events) + 1)
+		db.events = append(db.events, event)
+	}
+
+	return nil
+}
+
+func (db *eventLogMock) GetEvents(colonyName string, fromSeq int64, count int) ([]*core.Event, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	var events []*core.Event
+	for _, event := range db.events {
+		if event.ColonyName == colonyName && event.Seq >= fromSeq && len(events) < count {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// blockingEventLogMock is an event log where storing events blocks until unblock is closed
+type blockingEventLogMock struct {
+	eventLogMock
+	unblock chan struct{}
+}
+
+func (db *blockingEventLogMock) AddEvents(events []*core.Event) error {
+	<-db.unblock
+	return db.eventLogMock.AddEvents(events)
+}
+
 func TestEventHandler(t *testing.T) {
 	process := utils.CreateTestProcess(core.GenerateRandomID())
 	process.FunctionSpec.Conditions.ExecutorType = "test_executor_type"
@@ -24,7 +69,7 @@ func TestEventHandler(t *testing.T) {
 	ctx, cancelCtx := context.WithTimeout(context.Background(), 3000*time.Millisecond)
 	defer cancelCtx()
 
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 	retChan := make(chan retValues)
 	go func() {
 		process, err := handler.waitForProcess("test_executor_type", core.WAITING, "", ctx)
@@ -47,7 +92,7 @@ func TestEventHandlerTimeout(t *testing.T) {
 	ctx, cancelCtx := context.WithTimeout(context.Background(), 1*time.Millisecond)
 	defer cancelCtx()
 
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 	retChan := make(chan retValues)
 	go func() {
 		process, err := handler.waitForProcess("test_executor_type", core.WAITING, "", ctx)
@@ -62,7 +107,7 @@ func TestEventHandlerTimeout(t *testing.T) {
 }
 
 func TestEventHandlerStop(t *testing.T) {
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 
 	handler.stop()
 
@@ -80,7 +125,7 @@ func TestEventHandlerTimeout2(t *testing.T) {
 	ctx, cancelCtx := context.WithTimeout(context.Background(), 3000*time.Millisecond)
 	defer cancelCtx()
 
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 	retChan := make(chan retValues)
 	go func() {
 		process, err := handler.waitForProcess("test_executor_type", core.WAITING, "", ctx)
@@ -105,7 +150,7 @@ func TestEventHandlerTimeout3(t *testing.T) {
 	ctx, cancelCtx := context.WithTimeout(context.Background(), 3000*time.Millisecond)
 	defer cancelCtx()
 
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 	retChan := make(chan retValues)
 	go func() {
 		process, err := handler.waitForProcess("test_executor_type", core.WAITING, "", ctx)
@@ -134,7 +179,7 @@ func TestEventHandlerMany(t *testing.T) {
 	ctx, cancelCtx := context.WithTimeout(context.Background(), 2000*time.Millisecond)
 	defer cancelCtx()
 
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 
 	var retChans []chan retValues
 
@@ -177,7 +222,7 @@ func TestEventHandlerUpdate(t *testing.T) {
 	ctx, cancelCtx := context.WithTimeout(context.Background(), 3000*time.Millisecond)
 	defer cancelCtx()
 
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 	retChan := make(chan retValues)
 	go func() {
 		process, err := handler.waitForProcess("test_executor_type", core.WAITING, process.ID, ctx)
@@ -204,7 +249,7 @@ func TestEventHandlerUpdateTimeout(t *testing.T) {
 	ctx, cancelCtx := context.WithTimeout(context.Background(), 3000*time.Millisecond)
 	defer cancelCtx()
 
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 	retChan := make(chan retValues)
 	go func() {
 		// Wait for another process with random ID, i.e. we will time out
@@ -231,7 +276,7 @@ func TestEventHandlerSubscribe(t *testing.T) {
 	defer cancelCtx()
 
 	retChan := make(chan retValues)
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 	processChan, errChan := handler.subscribe("test_executor_type", core.WAITING, "", ctx)
 	go func() {
 		select {
@@ -255,7 +300,7 @@ func TestEventHandlerSubscribeCancel(t *testing.T) {
 	ctx, cancelCtx := context.WithTimeout(context.Background(), 3000*time.Millisecond)
 
 	retChan := make(chan retValues)
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 	processChan, errChan := handler.subscribe("test_executor_type", core.WAITING, "", ctx) // Subscribe to all processes
 	go func() {
 		select {
@@ -279,7 +324,7 @@ func TestEventHandlerSubscribeTimeout(t *testing.T) {
 	defer cancelCtx()
 
 	retChan := make(chan retValues)
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 	processChan, errChan := handler.subscribe("test_executor_type", core.WAITING, "", ctx) // Subscribe to all processes
 	go func() {
 		select {
@@ -302,7 +347,7 @@ func TestEventHandlerSubscribeProcessID(t *testing.T) {
 	defer cancelCtx()
 
 	retChan := make(chan retValues)
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 	processChan, errChan := handler.subscribe("test_executor_type", core.WAITING, process.ID, ctx)
 	go func() {
 		select {
@@ -327,7 +372,7 @@ func TestEventHandlerSubscribeProcessIDFailed(t *testing.T) {
 	defer cancelCtx()
 
 	retChan := make(chan retValues)
-	handler := createEventHandler(nil)
+	handler := createEventHandler(nil, nil)
 	processChan, errChan := handler.subscribe("test_executor_type", core.WAITING, core.GenerateRandomID(), ctx)
 	go func() {
 		select {
@@ -357,9 +402,9 @@ func TestEventHandleRelayServer(t *testing.T) {
 	relayServer2 := cluster.CreateRelayServer(node2, config)
 	relayServer3 := cluster.CreateRelayServer(node3, config)
 
-	handler1 := createEventHandler(relayServer1)
-	handler2 := createEventHandler(relayServer2)
-	handler3 := createEventHandler(relayServer3)
+	handler1 := createEventHandler(relayServer1, nil)
+	handler2 := createEventHandler(relayServer2, nil)
+	handler3 := createEventHandler(relayServer3, nil)
 
 	retChan1 := make(chan retValues)
 	go func() {
@@ -404,3 +449,159 @@ func TestEventHandleRelayServer(t *testing.T) {
 	assert.Nil(t, retVal3.err)
 	assert.True(t, process.Equals(retVal3.process))
 }
+
+func TestEventHandlerSubscribeEvents(t *testing.T) {
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 3000*time.Millisecond)
+	defer cancelCtx()
+
+	handler := createEventHandler(nil, nil)
+	defer handler.stop()
+
+	filter := &core.EventFilter{ColonyName: "test_colony", Types: []string{core.FileEventKind}}
+	eventChan, errChan := handler.subscribeEvents(filter, 0, ctx)
+	assert.Equal(t, 1, handler.numberOfEventListeners())
+
+	snapshot := &core.Snapshot{ID: "test_snapshot_id", ColonyName: "test_colony"}
+	handler.publish(core.CreateSnapshotEvent(core.SnapshotCreatedEvent, snapshot)) // Filtered out, not a file event
+
+	file := &core.File{ID: "test_file_id", ColonyName: "another_colony"}
+	handler.publish(core.CreateFileEvent(core.FileAddedEvent, file)) // Filtered out, another colony
+
+	file = &core.File{ID: "test_file_id", ColonyName: "test_colony"}
+	handler.publish(core.CreateFileEvent(core.FileAddedEvent, file))
+
+	select {
+	case event := <-eventChan:
+		assert.Equal(t, core.FileAddedEvent, event.Type)
+		assert.Equal(t, "test_file_id", event.ObjectID)
+	case err := <-errChan:
+		assert.Fail(t, err.Error())
+	}
+
+	cancelCtx()
+	err := <-errChan
+	assert.NotNil(t, err)
+
+	// Unregistering is done asynchronously by the masterworker
+	for handler.numberOfEventListeners() > 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEventHandlerPublishNilHandler(t *testing.T) {
+	var handler *eventHandler
+	file := &core.File{ID: "test_file_id", ColonyName: "test_colony"}
+	handler.publish(core.CreateFileEvent(core.FileAddedEvent, file)) // Should not panic
+}
+
+func TestEventHandlerPersistEvents(t *testing.T) {
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 3000*time.Millisecond)
+	defer cancelCtx()
+
+	db := &eventLogMock{}
+	handler := createEventHandler(nil, db)
+	defer handler.stop()
+
+	filter := &core.EventFilter{ColonyName: "test_colony", Types: []string{core.ProcessEventKind}}
+	eventChan, errChan := handler.subscribeEvents(filter, 0, ctx)
+
+	process := utils.CreateTestProcess("test_colony")
+	process.Output = []interface{}{"test_output"}
+	for i := 0; i < 3; i++ {
+		handler.publish(core.CreateProcessEvent(process))
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case event := <-eventChan:
+			assert.Equal(t, int64(i+1), event.Seq)
+			assert.Len(t, event.Process.Output, 1)
+		case err := <-errChan:
+			assert.Fail(t, err.Error())
+		}
+	}
+
+	// Only a compacted copy is stored in the event log
+	events, err := db.GetEvents("test_colony", 1, 100)
+	assert.Nil(t, err)
+	assert.Len(t, events, 3)
+	assert.Nil(t, events[0].Process.Output)
+}
+
+func TestEventHandlerSubscribeEventsReplayGap(t *testing.T) {
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 3000*time.Millisecond)
+	defer cancelCtx()
+
+	db := &eventLogMock{}
+	handler := createEventHandler(nil, db)
+	defer handler.stop()
+
+	filter := &core.EventFilter{ColonyName: "test_colony", Types: []string{core.FileEventKind}}
+	eventChan, errChan := handler.subscribeEvents(filter, 0, ctx)
+
+	var events []*core.Event
+	for i := 0; i < 3; i++ {
+		file := &core.File{ID: core.GenerateRandomID(), ColonyName: "test_colony"}
+		events = append(events, core.CreateFileEvent(core.FileAddedEvent, file))
+	}
+	err := db.AddEvents(events)
+	assert.Nil(t, err)
+
+	// Relayed events arrive out of order, the missing event is replayed from the event log and the late event skipped
+	handler.publishNoRelay(events[0])
+	handler.publishNoRelay(events[2])
+	handler.publishNoRelay(events[1])
+
+	for i := 0; i < 3; i++ {
+		select {
+		case event := <-eventChan:
+			assert.Equal(t, int64(i+1), event.Seq)
+		case err := <-errChan:
+			assert.Fail(t, err.Error())
+		}
+	}
+
+	select {
+	case event := <-eventChan:
+		assert.Fail(t, "Unexpected event", event.Seq)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestEventHandlerPersistQueueFull(t *testing.T) {
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelCtx()
+
+	db := &blockingEventLogMock{unblock: make(chan struct{})}
+	handler := createEventHandler(nil, db)
+	defer handler.stop()
+
+	filter := &core.EventFilter{ColonyName: "test_colony", Types: []string{core.FileEventKind}}
+	eventChan, errChan := handler.subscribeEvents(filter, 0, ctx)
+
+	// The persist worker gets stuck storing the first event, fill the queue behind it
+	process := utils.CreateTestProcess("test_colony")
+	handler.publish(core.CreateProcessEvent(process))
+	assert.Eventually(t, func() bool { return len(handler.persistQueue) == 0 }, time.Second, 10*time.Millisecond)
+	for i := 0; i < eventPersistQueueSize; i++ {
+		handler.publish(core.CreateProcessEvent(process))
+	}
+	assert.Equal(t, int64(0), handler.unpersistedEvents())
+
+	// Publishing does not block, the event is published at once without a sequence number
+	file := &core.File{ID: core.GenerateRandomID(), ColonyName: "test_colony"}
+	handler.publish(core.CreateFileEvent(core.FileAddedEvent, file))
+
+	select {
+	case event := <-eventChan:
+		assert.Equal(t, file.ID, event.ObjectID)
+		assert.Equal(t, int64(0), event.Seq)
+	case err := <-errChan:
+		assert.Fail(t, err.Error())
+	case <-ctx.Done():
+		assert.Fail(t, "Timeout waiting for event")
+	}
+	assert.Equal(t, int64(1), handler.unpersistedEvents())
+
+	close(db.unblock)
+}
@@ -55,6 +55,8 @@ func (server *ColoniesServer) handleAddExecutorHTTPRequest(c *gin.Context, recov
 		"ExecutorId":   addedExecutor.ID}).
 		Debug("Adding executor")
 
+	server.publishEvent(core.CreateExecutorEvent(core.ExecutorRegisteredEvent, addedExecutor))
+
 	server.sendHTTPReply(c, payloadType, jsonString)
 }
 
@@ -162,6 +164,8 @@ func (server *ColoniesServer) handleApproveExecutorHTTPRequest(c *gin.Context, r
 
 	log.WithFields(log.Fields{"ExecutorId": executor.ID}).Debug("Approving executor")
 
+	server.publishEvent(core.CreateExecutorEvent(core.ExecutorApprovedEvent, executor))
+
 	server.sendEmptyHTTPReply(c, payloadType)
 }
 
@@ -199,6 +203,8 @@ func (server *ColoniesServer) handleRejectExecutorHTTPRequest(c *gin.Context, re
 
 	log.WithFields(log.Fields{"ExecutorId": executor.ID}).Debug("Rejecting executor")
 
+	server.publishEvent(core.CreateExecutorEvent(core.ExecutorRejectedEvent, executor))
+
 	server.sendEmptyHTTPReply(c, payloadType)
 }
 
@@ -236,6 +242,8 @@ func (server *ColoniesServer) handleRemoveExecutorHTTPRequest(c *gin.Context, re
 
 	log.WithFields(log.Fields{"ExecutorId": executor.ID}).Debug("Removing executor")
 
+	server.publishEvent(core.CreateExecutorEvent(core.ExecutorRemovedEvent, executor))
+
 	server.sendEmptyHTTPReply(c, payloadType)
 }
 
@@ -0,0 +1,348 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// setFederation makes the server forward processes to the federation peers of its colonies. RPCs to peers are
+// signed with the server private key, peers only accept them if they have registered this server ID.
+func (controller *coloniesController) setFederation(serverPrvKey string, skipTLSVerify bool) {
+	controller.federationMutex.Lock()
+	defer controller.federationMutex.Unlock()
+
+	controller.federationPrvKey = serverPrvKey
+	controller.federationSkipTLSVerify = skipTLSVerify
+}
+
+func (controller *coloniesController) getFederation() (string, bool) {
+	controller.federationMutex.Lock()
+	defer controller.federationMutex.Unlock()
+
+	return controller.federationPrvKey, controller.federationSkipTLSVerify
+}
+
+// federationPeerError is returned when forwarding a process fails because of the peer rather than the process, e.g. the
+// peer is unreachable or does not accept processes from this server. No more processes are then forwarded to the peer.
+type federationPeerError struct {
+	err error
+}
+
+func (e *federationPeerError) Error() string {
+	return e.err.Error()
+}
+
+func (e *federationPeerError) Unwrap() error {
+	return e.err
+}
+
+// isFederationPeerError returns true if an RPC to a peer failed because of the peer, i.e. it could not be reached, it
+// rejected this server or it failed internally, e.g. because it is draining, rather than because of the request
+func isFederationPeerError(err error) bool {
+	var coloniesErr *core.ColoniesError
+	if !errors.As(err, &coloniesErr) {
+		return true
+	}
+
+	return coloniesErr.Status == http.StatusUnauthorized || coloniesErr.Status == http.StatusForbidden || coloniesErr.Status >= http.StatusInternalServerError
+}
+
+func (controller *coloniesController) createFederationClient(peer *core.FederationPeer, skipTLSVerify bool) *client.ColoniesClient {
+	return client.CreateColoniesClient(peer.Host, peer.Port, !peer.TLS, skipTLSVerify)
+}
+
+// claimFederatedProcess assigns a waiting process to a peer, so that no local executor is assigned the process while
+// it is forwarded. Processes in colonies where assignments are paused are not claimed.
+func (controller *coloniesController) claimFederatedProcess(processID string, colonyName string, peerID string) error {
+	cmd := &command{threaded: false, colonyName: colonyName, errorChan: make(chan error, 1),
+		name: "claimFederatedProcess",
+		handler: func(cmd *command) {
+			process, err := controller.db.GetProcessByID(processID)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+
+			if process == nil || process.IsAssigned || process.State != core.WAITING {
+				cmd.errorChan <- errors.New("Process with Id <" + processID + "> is no longer waiting")
+				return
+			}
+
+			paused, err := controller.areColonyAssignmentsPaused(process.FunctionSpec.Conditions.ColonyName)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+
+			if paused {
+				cmd.errorChan <- errors.New("Assignments are paused in colony <" + process.FunctionSpec.Conditions.ColonyName + ">")
+				return
+			}
+
+			err = controller.db.Assign(peerID, process)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+
+			controller.eventHandler.publish(core.CreateProcessEvent(process))
+			cmd.errorChan <- nil
+		}}
+
+	controller.blockingCmdQueue <- cmd
+	return <-cmd.errorChan
+}
+
+// forwardProcess submits a copy of a waiting process to the colony of a peer. The ID of this server is appended to
+// the federation path of the copy, so that the process is never forwarded back to this server.
+func (controller *coloniesController) forwardProcess(peer *core.FederationPeer, process *core.Process, serverID string, prvKey string, skipTLSVerify bool) error {
+	path := core.GetFederationPath(&process.FunctionSpec)
+	if len(path) >= FEDERATION_MAX_HOPS {
+		return nil
+	}
+
+	err := controller.claimFederatedProcess(process.ID, process.FunctionSpec.Conditions.ColonyName, peer.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"ProcessId": process.ID, "Peer": peer.Name, "Error": err}).Debug("Failed to claim process, not forwarding process")
+		return nil
+	}
+
+	funcSpec := process.FunctionSpec
+	funcSpec.Conditions.ColonyName = peer.RemoteColonyName
+	funcSpec.Env = make(map[string]string)
+	for k, v := range process.FunctionSpec.Env {
+		funcSpec.Env[k] = v
+	}
+	core.SetFederationPath(&funcSpec, append(path, serverID))
+
+	ctx, cancel := context.WithTimeout(context.Background(), FEDERATION_RPC_TIMEOUT)
+	defer cancel()
+
+	remoteProcess, err := controller.createFederationClient(peer, skipTLSVerify).SubmitFederatedProcess(&funcSpec, prvKey, ctx)
+	if err != nil {
+		// The process is put back in the queue, so that it can be assigned to a local executor or forwarded again
+		err2 := controller.resetProcess(process.ID, process.FunctionSpec.Conditions.ColonyName)
+		if err2 != nil {
+			log.WithFields(log.Fields{"ProcessId": process.ID, "Error": err2}).Error("Failed to reset process")
+		}
+		if isFederationPeerError(err) {
+			return &federationPeerError{err: err}
+		}
+		return err
+	}
+
+	err = controller.db.AddFederatedProcess(core.CreateFederatedProcess(process.ID, process.FunctionSpec.Conditions.ColonyName, peer.ID, remoteProcess.ID))
+	if err != nil {
+		// Without the record the process would never be synced, so it is withdrawn from the peer and put back in the
+		// queue instead of staying assigned to the peer forever
+		ctx, cancel := context.WithTimeout(context.Background(), FEDERATION_RPC_TIMEOUT)
+		defer cancel()
+		err2 := controller.createFederationClient(peer, skipTLSVerify).RemoveFederatedProcess(remoteProcess.ID, prvKey, ctx)
+		if err2 != nil {
+			log.WithFields(log.Fields{"ProcessId": process.ID, "RemoteProcessId": remoteProcess.ID, "Peer": peer.Name, "Error": err2}).Error("Failed to remove process from federation peer")
+		}
+		err2 = controller.resetProcess(process.ID, process.FunctionSpec.Conditions.ColonyName)
+		if err2 != nil {
+			log.WithFields(log.Fields{"ProcessId": process.ID, "Error": err2}).Error("Failed to reset process")
+		}
+		return err
+	}
+
+	log.WithFields(log.Fields{"ProcessId": process.ID, "RemoteProcessId": remoteProcess.ID, "Peer": peer.Name, "RemoteColonyName": peer.RemoteColonyName}).Debug("Forwarded process to federation peer")
+
+	return nil
+}
+
+// forwardProcesses forwards waiting processes matching the executor types and labels of a peer to the peer. A process
+// that fails to be forwarded is logged and does not prevent other processes from being forwarded, while a peer that
+// fails, e.g. because it cannot be reached, does not prevent processes from being forwarded to other peers.
+func (controller *coloniesController) forwardProcesses() {
+	prvKey, skipTLSVerify := controller.getFederation()
+	if prvKey == "" {
+		return
+	}
+
+	serverID, err := controller.db.GetServerID()
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to get server ID, cannot forward processes")
+		return
+	}
+
+	colonies, err := controller.db.GetColonies()
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to get colonies, cannot forward processes")
+		return
+	}
+
+	for _, colony := range colonies {
+		peers, err := controller.db.GetFederationPeersByColonyName(colony.Name)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ColonyName": colony.Name}).Error("Failed to get federation peers")
+			continue
+		}
+
+		for _, peer := range peers {
+			for _, executorType := range peer.ExecutorTypes {
+				err := controller.forwardProcessesOfExecutorType(colony.Name, executorType, peer, serverID, prvKey, skipTLSVerify)
+				if err != nil {
+					log.WithFields(log.Fields{"Error": err, "ColonyName": colony.Name, "Peer": peer.Name}).Warn("Failed to forward processes to federation peer")
+					break
+				}
+			}
+		}
+	}
+}
+
+// forwardProcessesOfExecutorType tries to forward up to FEDERATION_BATCH_SIZE waiting processes of an executor type to a peer.
+// The waiting processes are paged through in priority order, so that processes the peer does not forward, e.g.
+// workflow processes, do not hide the processes behind them. An error is only returned if the peer has failed.
+func (controller *coloniesController) forwardProcessesOfExecutorType(colonyName string, executorType string, peer *core.FederationPeer, serverID string, prvKey string, skipTLSVerify bool) error {
+	attempted := 0
+	priorityTime := int64(math.MinInt64)
+	processID := ""
+	for attempted < FEDERATION_BATCH_SIZE {
+		processes, err := controller.db.FindWaitingProcessesAfter(colonyName, executorType, priorityTime, processID, FEDERATION_BATCH_SIZE)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ColonyName": colonyName, "ExecutorType": executorType}).Error("Failed to find waiting processes")
+			return nil
+		}
+
+		for _, process := range processes {
+			priorityTime = process.PriorityTime
+			processID = process.ID
+
+			if !peer.Forwards(process) {
+				continue
+			}
+
+			err = controller.forwardProcess(peer, process, serverID, prvKey, skipTLSVerify)
+			var peerErr *federationPeerError
+			if errors.As(err, &peerErr) {
+				return err
+			}
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err, "ProcessId": process.ID, "Peer": peer.Name}).Warn("Failed to forward process to federation peer")
+			}
+
+			attempted++
+			if attempted == FEDERATION_BATCH_SIZE {
+				break
+			}
+		}
+
+		if len(processes) < FEDERATION_BATCH_SIZE {
+			break
+		}
+	}
+
+	return nil
+}
+
+// syncFederatedProcesses mirrors the outcome of forwarded processes back to the local processes. Only the outcome, i.e.
+// SUCCESS or FAILED, is mirrored: the local process is RUNNING from the moment it is forwarded, and whether the
+// forwarded process is still waiting or running at the peer is not mirrored. Processes that are no longer running
+// locally, e.g. because the max exec time was exceeded, are no longer synced, and processes forwarded to removed peers
+// are put back in the queue.
+func (controller *coloniesController) syncFederatedProcesses() {
+	prvKey, skipTLSVerify := controller.getFederation()
+	if prvKey == "" {
+		return
+	}
+
+	federatedProcesses, err := controller.db.GetFederatedProcesses(MAX_FEDERATED_PROCESSES)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to get federated processes")
+		return
+	}
+
+	for _, federatedProcess := range federatedProcesses {
+		process, err := controller.db.GetProcessByID(federatedProcess.ProcessID)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ProcessId": federatedProcess.ProcessID}).Error("Failed to get federated process")
+			continue
+		}
+
+		if process == nil || process.State != core.RUNNING || process.AssignedExecutorID != federatedProcess.PeerID {
+			err = controller.db.RemoveFederatedProcess(federatedProcess.ProcessID)
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err, "ProcessId": federatedProcess.ProcessID}).Error("Failed to remove federated process")
+			}
+			continue
+		}
+
+		peer, err := controller.db.GetFederationPeerByID(federatedProcess.PeerID)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ProcessId": process.ID}).Error("Failed to get federation peer")
+			continue
+		}
+
+		if peer == nil {
+			err = controller.resetProcess(process.ID, process.FunctionSpec.Conditions.ColonyName)
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err, "ProcessId": process.ID}).Error("Failed to reset process forwarded to removed federation peer")
+				continue
+			}
+			err = controller.db.RemoveFederatedProcess(process.ID)
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err, "ProcessId": process.ID}).Error("Failed to remove federated process")
+			}
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), FEDERATION_RPC_TIMEOUT)
+		remoteProcess, err := controller.createFederationClient(peer, skipTLSVerify).GetFederatedProcess(federatedProcess.RemoteProcessID, prvKey, ctx)
+		cancel()
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ProcessId": process.ID, "Peer": peer.Name}).Warn("Failed to get process from federation peer")
+			continue
+		}
+
+		switch remoteProcess.State {
+		case core.SUCCESS:
+			err = controller.closeSuccessful(process.ID, process.FunctionSpec.Conditions.ColonyName, peer.ID, remoteProcess.Output, context.Background())
+		case core.FAILED:
+			err = controller.closeFailed(process.ID, process.FunctionSpec.Conditions.ColonyName, remoteProcess.Errors, context.Background())
+		default:
+			continue
+		}
+
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ProcessId": process.ID, "Peer": peer.Name}).Error("Failed to close federated process")
+			continue
+		}
+
+		log.WithFields(log.Fields{"ProcessId": process.ID, "RemoteProcessId": remoteProcess.ID, "Peer": peer.Name, "State": remoteProcess.State}).Debug("Closed federated process")
+
+		err = controller.db.RemoveFederatedProcess(process.ID)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ProcessId": process.ID}).Error("Failed to remove federated process")
+		}
+	}
+}
+
+// federationWorker forwards waiting processes to federation peers and syncs forwarded processes every FEDERATION_PERIOD. Only
+// the node leading the federation job forwards processes so that a process is never forwarded twice.
+func (controller *coloniesController) federationWorker() {
+	for {
+		time.Sleep(FEDERATION_PERIOD)
+
+		controller.stopMutex.Lock()
+		stopped := controller.stopFlag
+		controller.stopMutex.Unlock()
+		if stopped {
+			return
+		}
+
+		controller.runJob(FEDERATION_JOB, func() {
+			controller.forwardProcesses()
+			controller.syncFederatedProcesses()
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// findFederationPeer returns the peer of a colony with the given server ID, or nil if the server is not a peer
+func (server *ColoniesServer) findFederationPeer(colonyName string, serverID string) (*core.FederationPeer, error) {
+	peers, err := server.db.GetFederationPeersByColonyName(colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, peer := range peers {
+		if peer.ServerID == serverID {
+			return peer, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (server *ColoniesServer) handleAddFederationPeerHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateAddFederationPeerMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to add federation peer, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to add federation peer, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.FederationPeer == nil {
+		server.handleHTTPError(c, errors.New("Failed to add federation peer, federation peer is <nil>"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireColonyOwner(recoveredID, msg.FederationPeer.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	peer := msg.FederationPeer
+	if peer.Name == "" || peer.ServerID == "" || peer.Host == "" || peer.RemoteColonyName == "" {
+		server.handleHTTPError(c, errors.New("Failed to add federation peer, name, server Id, host and remote colony name must be set"), http.StatusBadRequest)
+		return
+	}
+
+	if peer.Port <= 0 {
+		server.handleHTTPError(c, errors.New("Failed to add federation peer, invalid port"), http.StatusBadRequest)
+		return
+	}
+
+	serverID, err := server.getServerID()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if peer.ServerID == serverID {
+		server.handleHTTPError(c, errors.New("Failed to add federation peer, a server cannot federate with itself"), http.StatusBadRequest)
+		return
+	}
+
+	existingPeer, err := server.db.GetFederationPeerByName(peer.ColonyName, peer.Name)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if existingPeer != nil {
+		server.handleHTTPError(c, errors.New("Failed to add federation peer, federation peer with name <"+peer.Name+"> already exists in colony <"+peer.ColonyName+">"), http.StatusBadRequest)
+		return
+	}
+
+	existingPeer, err = server.findFederationPeer(peer.ColonyName, peer.ServerID)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if existingPeer != nil {
+		server.handleHTTPError(c, errors.New("Failed to add federation peer, server with Id <"+peer.ServerID+"> is already a peer of colony <"+peer.ColonyName+">"), http.StatusBadRequest)
+		return
+	}
+
+	if peer.ExecutorTypes == nil {
+		peer.ExecutorTypes = []string{}
+	}
+	if peer.Labels == nil {
+		peer.Labels = []string{}
+	}
+	peer.ID = core.GenerateRandomID()
+	peer.Added = time.Now()
+
+	err = server.db.AddFederationPeer(peer)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	jsonString, err = peer.ToJSON()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"FederationPeerID": peer.ID, "ColonyName": peer.ColonyName, "Name": peer.Name, "ServerID": peer.ServerID, "RemoteColonyName": peer.RemoteColonyName}).Debug("Adding federation peer")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+func (server *ColoniesServer) handleGetFederationPeersHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetFederationPeersMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to get federation peers, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to get federation peers, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireMembership(recoveredID, msg.ColonyName, true)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	peers, err := server.db.GetFederationPeersByColonyName(msg.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	jsonString, err = core.ConvertFederationPeerArrayToJSON(peers)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName}).Debug("Getting federation peers")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+func (server *ColoniesServer) handleRemoveFederationPeerHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRemoveFederationPeerMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to remove federation peer, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to remove federation peer, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireColonyOwner(recoveredID, msg.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	peer, err := server.db.GetFederationPeerByName(msg.ColonyName, msg.Name)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if peer == nil {
+		server.handleHTTPError(c, errors.New("Failed to remove federation peer, federation peer with name <"+msg.Name+"> not found in colony <"+msg.ColonyName+">"), http.StatusNotFound)
+		return
+	}
+
+	err = server.db.RemoveFederationPeerByName(msg.ColonyName, msg.Name)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"FederationPeerID": peer.ID, "ColonyName": peer.ColonyName, "Name": peer.Name}).Debug("Removing federation peer")
+
+	server.sendEmptyHTTPReply(c, payloadType)
+}
+
+// handleSubmitFederatedProcessHTTPRequest handles processes forwarded by another server. The request must be signed
+// by a server registered as a federation peer of the colony, and processes already forwarded by this server are
+// rejected to prevent forwarding loops.
+func (server *ColoniesServer) handleSubmitFederatedProcessHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateSubmitFederatedProcessMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to submit federated process, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to submit federated process, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.FunctionSpec == nil {
+		server.handleHTTPError(c, errors.New("Failed to submit federated process, function spec is <nil>"), http.StatusBadRequest)
+		return
+	}
+
+	colonyName := msg.FunctionSpec.Conditions.ColonyName
+	peer, err := server.findFederationPeer(colonyName, recoveredID)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if peer == nil {
+		server.handleHTTPError(c, errors.New("Access denied, server with Id <"+recoveredID+"> is not a federation peer of colony <"+colonyName+">"), http.StatusForbidden)
+		return
+	}
+
+	serverID, err := server.getServerID()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	path := core.GetFederationPath(msg.FunctionSpec)
+	if len(path) > FEDERATION_MAX_HOPS {
+		server.handleHTTPError(c, errors.New("Failed to submit federated process, process has been forwarded more than <"+strconv.Itoa(FEDERATION_MAX_HOPS)+"> times"), http.StatusBadRequest)
+		return
+	}
+
+	for _, id := range path {
+		if id == serverID {
+			server.handleHTTPError(c, errors.New("Failed to submit federated process, process has already been forwarded by this server"), http.StatusBadRequest)
+			return
+		}
+	}
+
+	process := core.CreateProcess(msg.FunctionSpec)
+	process.InitiatorID = recoveredID
+	process.InitiatorName = peer.Name
+
+	addedProcess, err := server.controller.addProcess(process, c.Request.Context())
+	if server.handleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+	if addedProcess == nil {
+		server.handleHTTPError(c, errors.New("Failed to submit federated process, addedProcess is nil"), http.StatusInternalServerError)
+		return
+	}
+
+	jsonString, err = addedProcess.ToJSON()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": colonyName, "ProcessId": addedProcess.ID, "Peer": peer.Name}).Debug("Submitting federated process")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+// handleGetFederatedProcessHTTPRequest lets a federation peer follow a process it has forwarded to this server
+func (server *ColoniesServer) handleGetFederatedProcessHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetFederatedProcessMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to get federated process, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to get federated process, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	process, err := server.db.GetProcessByID(msg.ProcessID)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if process == nil {
+		server.handleHTTPError(c, errors.New("Failed to get federated process, process with Id <"+msg.ProcessID+"> not found"), http.StatusNotFound)
+		return
+	}
+
+	peer, err := server.findFederationPeer(process.FunctionSpec.Conditions.ColonyName, recoveredID)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if peer == nil || process.InitiatorID != recoveredID {
+		server.handleHTTPError(c, errors.New("Access denied, process with Id <"+msg.ProcessID+"> was not forwarded by server with Id <"+recoveredID+">"), http.StatusForbidden)
+		return
+	}
+
+	jsonString, err = process.ToJSON()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+// handleRemoveFederatedProcessHTTPRequest lets a federation peer withdraw a process it has forwarded to this server,
+// e.g. when the peer failed to record that it forwarded the process
+func (server *ColoniesServer) handleRemoveFederatedProcessHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRemoveFederatedProcessMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to remove federated process, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to remove federated process, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	process, err := server.db.GetProcessByID(msg.ProcessID)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if process == nil {
+		server.handleHTTPError(c, errors.New("Failed to remove federated process, process with Id <"+msg.ProcessID+"> not found"), http.StatusNotFound)
+		return
+	}
+
+	peer, err := server.findFederationPeer(process.FunctionSpec.Conditions.ColonyName, recoveredID)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if peer == nil || process.InitiatorID != recoveredID {
+		server.handleHTTPError(c, errors.New("Access denied, process with Id <"+msg.ProcessID+"> was not forwarded by server with Id <"+recoveredID+">"), http.StatusForbidden)
+		return
+	}
+
+	err = server.controller.removeProcess(process.ID, process.FunctionSpec.Conditions.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ProcessId": process.ID, "Peer": peer.Name}).Debug("Removing federated process")
+
+	server.sendEmptyHTTPReply(c, payloadType)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddFederationPeerSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	peer := core.CreateFederationPeer(env.colony1Name, "site2", core.GenerateRandomID(), "localhost", 50080, true, "remote_colony", []string{"test_executor_type"}, []string{})
+
+	_, err := client.AddFederationPeer(peer, env.executor1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.AddFederationPeer(peer, env.colony2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.AddFederationPeer(peer, env.colony1PrvKey)
+	assert.Nil(t, err) // Should work
+
+	server.Shutdown()
+	<-done
+}
+
+func TestGetFederationPeersSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	_, err := client.GetFederationPeers(env.colony1Name, env.executor2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.GetFederationPeers(env.colony1Name, env.executor1PrvKey)
+	assert.Nil(t, err) // Should work
+
+	server.Shutdown()
+	<-done
+}
+
+func TestRemoveFederationPeerSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	peer := core.CreateFederationPeer(env.colony1Name, "site2", core.GenerateRandomID(), "localhost", 50080, true, "remote_colony", []string{"test_executor_type"}, []string{})
+	_, err := client.AddFederationPeer(peer, env.colony1PrvKey)
+	assert.Nil(t, err)
+
+	err = client.RemoveFederationPeer(env.colony1Name, "site2", env.executor1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	err = client.RemoveFederationPeer(env.colony1Name, "site2", env.colony2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	err = client.RemoveFederationPeer(env.colony1Name, "site2", env.colony1PrvKey)
+	assert.Nil(t, err) // Should work
+
+	server.Shutdown()
+	<-done
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/crypto"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestPeerKey(t *testing.T) (string, string) {
+	crypto := crypto.CreateCrypto()
+	prvKey, err := crypto.GeneratePrivateKey()
+	assert.Nil(t, err)
+	id, err := crypto.GenerateID(prvKey)
+	assert.Nil(t, err)
+
+	return prvKey, id
+}
+
+func TestAddFederationPeer(t *testing.T) {
+	env, client, server, serverPrvKey, done := setupTestEnv2(t)
+
+	_, peerServerID := createTestPeerKey(t)
+
+	peer := core.CreateFederationPeer(env.colonyName, "site2", peerServerID, "localhost", 50080, true, "remote_colony", []string{"test_executor_type"}, []string{})
+	addedPeer, err := client.AddFederationPeer(peer, env.colonyPrvKey)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, addedPeer.ID)
+
+	peers, err := client.GetFederationPeers(env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, peers, 1)
+	assert.True(t, addedPeer.Equals(peers[0]))
+
+	_, err = client.AddFederationPeer(peer, env.colonyPrvKey)
+	assert.NotNil(t, err) // Name already exists
+
+	invalidPeer := core.CreateFederationPeer(env.colonyName, "site3", peerServerID, "localhost", 50080, true, "remote_colony", []string{}, []string{})
+	_, err = client.AddFederationPeer(invalidPeer, env.colonyPrvKey)
+	assert.NotNil(t, err) // Server is already a peer
+
+	invalidPeer = core.CreateFederationPeer(env.colonyName, "site3", "", "localhost", 50080, true, "remote_colony", []string{}, []string{})
+	_, err = client.AddFederationPeer(invalidPeer, env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	invalidPeer = core.CreateFederationPeer(env.colonyName, "site3", core.GenerateRandomID(), "localhost", 0, true, "remote_colony", []string{}, []string{})
+	_, err = client.AddFederationPeer(invalidPeer, env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	serverID, err := crypto.CreateCrypto().GenerateID(serverPrvKey)
+	assert.Nil(t, err)
+	invalidPeer = core.CreateFederationPeer(env.colonyName, "site3", serverID, "localhost", 50080, true, "remote_colony", []string{}, []string{})
+	_, err = client.AddFederationPeer(invalidPeer, env.colonyPrvKey)
+	assert.NotNil(t, err) // Cannot federate with itself
+
+	err = client.RemoveFederationPeer(env.colonyName, "site2", env.colonyPrvKey)
+	assert.Nil(t, err)
+
+	peers, err = client.GetFederationPeers(env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, peers, 0)
+
+	err = client.RemoveFederationPeer(env.colonyName, "site2", env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestSubmitFederatedProcess(t *testing.T) {
+	env, client, server, serverPrvKey, done := setupTestEnv2(t)
+
+	peerPrvKey, peerServerID := createTestPeerKey(t)
+	otherPrvKey, _ := createTestPeerKey(t)
+
+	peer := core.CreateFederationPeer(env.colonyName, "site2", peerServerID, "localhost", 50080, true, "remote_colony", []string{}, []string{})
+	_, err := client.AddFederationPeer(peer, env.colonyPrvKey)
+	assert.Nil(t, err)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	core.SetFederationPath(funcSpec, []string{peerServerID})
+
+	ctx := context.Background()
+	_, err = client.SubmitFederatedProcess(funcSpec, otherPrvKey, ctx)
+	assert.NotNil(t, err) // Not a peer
+
+	_, err = client.SubmitFederatedProcess(funcSpec, env.executorPrvKey, ctx)
+	assert.NotNil(t, err) // Not a peer
+
+	process, err := client.SubmitFederatedProcess(funcSpec, peerPrvKey, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, peerServerID, process.InitiatorID)
+	assert.Equal(t, "site2", process.InitiatorName)
+	assert.Equal(t, []string{peerServerID}, core.GetFederationPath(&process.FunctionSpec))
+
+	// Processes already forwarded by this server are rejected
+	serverID, err := crypto.CreateCrypto().GenerateID(serverPrvKey)
+	assert.Nil(t, err)
+	loopFuncSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	core.SetFederationPath(loopFuncSpec, []string{serverID, peerServerID})
+	_, err = client.SubmitFederatedProcess(loopFuncSpec, peerPrvKey, ctx)
+	assert.NotNil(t, err)
+
+	var path []string
+	for i := 0; i <= FEDERATION_MAX_HOPS; i++ {
+		path = append(path, core.GenerateRandomID())
+	}
+	core.SetFederationPath(loopFuncSpec, path)
+	_, err = client.SubmitFederatedProcess(loopFuncSpec, peerPrvKey, ctx)
+	assert.NotNil(t, err) // Too many hops
+
+	_, err = client.GetFederatedProcess(process.ID, otherPrvKey, ctx)
+	assert.NotNil(t, err)
+
+	_, err = client.GetFederatedProcess(process.ID, env.executorPrvKey, ctx)
+	assert.NotNil(t, err)
+
+	assignedProcess, err := client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, process.ID, assignedProcess.ID)
+
+	err = client.CloseWithOutput(process.ID, []interface{}{"result"}, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	processFromServer, err := client.GetFederatedProcess(process.ID, peerPrvKey, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, core.SUCCESS, processFromServer.State)
+	assert.Equal(t, []interface{}{"result"}, processFromServer.Output)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestRemoveFederatedProcess(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	peerPrvKey, peerServerID := createTestPeerKey(t)
+	otherPrvKey, _ := createTestPeerKey(t)
+
+	peer := core.CreateFederationPeer(env.colonyName, "site2", peerServerID, "localhost", 50080, true, "remote_colony", []string{}, []string{})
+	_, err := client.AddFederationPeer(peer, env.colonyPrvKey)
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+	process, err := client.SubmitFederatedProcess(utils.CreateTestFunctionSpec(env.colonyName), peerPrvKey, ctx)
+	assert.Nil(t, err)
+
+	err = client.RemoveFederatedProcess(process.ID, otherPrvKey, ctx)
+	assert.NotNil(t, err) // Not a peer
+
+	err = client.RemoveFederatedProcess(process.ID, env.executorPrvKey, ctx)
+	assert.NotNil(t, err) // Not a peer
+
+	err = client.RemoveFederatedProcess(process.ID, peerPrvKey, ctx)
+	assert.Nil(t, err)
+
+	_, err = client.GetFederatedProcess(process.ID, peerPrvKey, ctx)
+	assert.NotNil(t, err) // Removed
+
+	server.Shutdown()
+	<-done
+}
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/cluster"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database/postgresql"
+	"github.com/colonyos/colonies/pkg/security/crypto"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const FEDERATION_TESTPORT = 28089
+
+func startFederationTestServer(t *testing.T) (*client.ColoniesClient, *ColoniesServer, string, chan bool) {
+	db, err := postgresql.PrepareTestsWithPrefix("TEST_FEDERATION_")
+	assert.Nil(t, err)
+
+	crypto := crypto.CreateCrypto()
+	serverPrvKey, err := crypto.GeneratePrivateKey()
+	assert.Nil(t, err)
+	serverID, err := crypto.GenerateID(serverPrvKey)
+	assert.Nil(t, err)
+
+	err = db.SetServerID("", serverID)
+	assert.Nil(t, err)
+
+	node := cluster.Node{Name: "etcd", Host: "localhost", EtcdClientPort: 24990, EtcdPeerPort: 23990, RelayPort: 25990, APIPort: FEDERATION_TESTPORT}
+	clusterConfig := cluster.Config{}
+	clusterConfig.AddNode(node)
+	server := CreateColoniesServer(db, FEDERATION_TESTPORT, EnableTLS, "../../cert/key.pem", "../../cert/cert.pem", node, clusterConfig, "/tmp/colonies/etcd_federation", GENERATOR_TRIGGER_PERIOD, CRON_TRIGGER_PERIOD, true, false, false, 1, 500, false)
+
+	done := make(chan bool)
+	go func() {
+		server.ServeForever()
+		db.Close()
+		done <- true
+	}()
+
+	c := client.CreateColoniesClient(TESTHOST, FEDERATION_TESTPORT, Insecure, SkipTLSVerify)
+	for i := 0; i < 100; i++ {
+		if c.CheckHealth() == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return c, server, serverPrvKey, done
+}
+
+func TestFederation(t *testing.T) {
+	env, client1, server1, server1PrvKey, done1 := setupTestEnv2(t)
+	client2, server2, server2PrvKey, done2 := startFederationTestServer(t)
+
+	server1ID, err := crypto.CreateCrypto().GenerateID(server1PrvKey)
+	assert.Nil(t, err)
+	server2ID, err := crypto.CreateCrypto().GenerateID(server2PrvKey)
+	assert.Nil(t, err)
+
+	remoteColony, remoteColonyPrvKey, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	_, err = client2.AddColony(remoteColony, server2PrvKey)
+	assert.Nil(t, err)
+
+	remoteExecutor, remoteExecutorPrvKey, err := utils.CreateTestExecutorWithKey(remoteColony.Name)
+	assert.Nil(t, err)
+	remoteExecutor.Type = "federated_executor_type"
+	_, err = client2.AddExecutor(remoteExecutor, remoteColonyPrvKey)
+	assert.Nil(t, err)
+	err = client2.ApproveExecutor(remoteColony.Name, remoteExecutor.Name, remoteColonyPrvKey)
+	assert.Nil(t, err)
+
+	peer := core.CreateFederationPeer(env.colonyName, "site2", server2ID, TESTHOST, FEDERATION_TESTPORT, true, remoteColony.Name, []string{"federated_executor_type"}, []string{})
+	peer, err = client1.AddFederationPeer(peer, env.colonyPrvKey)
+	assert.Nil(t, err)
+
+	// The remote peer forwards the same executor type back, which must not cause a forwarding loop
+	remotePeer := core.CreateFederationPeer(remoteColony.Name, "site1", server1ID, TESTHOST, TESTPORT, true, env.colonyName, []string{"federated_executor_type"}, []string{})
+	_, err = client2.AddFederationPeer(remotePeer, remoteColonyPrvKey)
+	assert.Nil(t, err)
+
+	err = server1.SetFederation(server2PrvKey, SkipTLSVerify)
+	assert.NotNil(t, err) // Not the server key
+	err = server1.SetFederation(server1PrvKey, SkipTLSVerify)
+	assert.Nil(t, err)
+	err = server2.SetFederation(server2PrvKey, SkipTLSVerify)
+	assert.Nil(t, err)
+
+	// Processes for other executor types are not forwarded
+	localProcess, err := client1.Submit(utils.CreateTestFunctionSpec(env.colonyName), env.executorPrvKey)
+	assert.Nil(t, err)
+
+	process, err := client1.Submit(utils.CreateTestFunctionSpecWithType(env.colonyName, "federated_executor_type"), env.executorPrvKey)
+	assert.Nil(t, err)
+
+	server1.controller.forwardProcesses()
+
+	localProcess, err = client1.GetProcess(localProcess.ID, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, core.WAITING, localProcess.State)
+
+	process, err = client1.GetProcess(process.ID, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, core.RUNNING, process.State)
+	assert.Equal(t, peer.ID, process.AssignedExecutorID)
+
+	server2.controller.forwardProcesses()
+
+	remoteProcess, err := client2.Assign(remoteColony.Name, -1, "", "", remoteExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{server1ID}, core.GetFederationPath(&remoteProcess.FunctionSpec))
+	assert.Equal(t, "site1", remoteProcess.InitiatorName)
+
+	err = client2.CloseWithOutput(remoteProcess.ID, []interface{}{"result"}, remoteExecutorPrvKey)
+	assert.Nil(t, err)
+
+	server1.controller.syncFederatedProcesses()
+
+	process, err = client1.GetProcess(process.ID, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, core.SUCCESS, process.State)
+	assert.Equal(t, []interface{}{"result"}, process.Output)
+
+	// Failed processes are failed locally
+	process, err = client1.Submit(utils.CreateTestFunctionSpecWithType(env.colonyName, "federated_executor_type"), env.executorPrvKey)
+	assert.Nil(t, err)
+
+	server1.controller.forwardProcesses()
+
+	remoteProcess, err = client2.Assign(remoteColony.Name, -1, "", "", remoteExecutorPrvKey)
+	assert.Nil(t, err)
+
+	err = client2.Fail(remoteProcess.ID, []string{"error"}, remoteExecutorPrvKey)
+	assert.Nil(t, err)
+
+	server1.controller.syncFederatedProcesses()
+
+	process, err = client1.GetProcess(process.ID, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, core.FAILED, process.State)
+	assert.Equal(t, []string{"error"}, process.Errors)
+
+	// Processes forwarded to removed peers are put back in the queue
+	process, err = client1.Submit(utils.CreateTestFunctionSpecWithType(env.colonyName, "federated_executor_type"), env.executorPrvKey)
+	assert.Nil(t, err)
+
+	server1.controller.forwardProcesses()
+
+	err = client1.RemoveFederationPeer(env.colonyName, "site2", env.colonyPrvKey)
+	assert.Nil(t, err)
+
+	server1.controller.syncFederatedProcesses()
+
+	process, err = client1.GetProcess(process.ID, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, core.WAITING, process.State)
+	assert.False(t, process.IsAssigned)
+
+	server2.Shutdown()
+	<-done2
+	server1.Shutdown()
+	<-done1
+}
+
+func TestFederationForwardPastSkippedProcesses(t *testing.T) {
+	env, client1, server1, server1PrvKey, done1 := setupTestEnv2(t)
+	client2, server2, server2PrvKey, done2 := startFederationTestServer(t)
+
+	server1ID, err := crypto.CreateCrypto().GenerateID(server1PrvKey)
+	assert.Nil(t, err)
+	server2ID, err := crypto.CreateCrypto().GenerateID(server2PrvKey)
+	assert.Nil(t, err)
+
+	remoteColony, remoteColonyPrvKey, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	_, err = client2.AddColony(remoteColony, server2PrvKey)
+	assert.Nil(t, err)
+
+	peer := core.CreateFederationPeer(env.colonyName, "site2", server2ID, TESTHOST, FEDERATION_TESTPORT, true, remoteColony.Name, []string{"federated_executor_type"}, []string{})
+	peer, err = client1.AddFederationPeer(peer, env.colonyPrvKey)
+	assert.Nil(t, err)
+
+	remotePeer := core.CreateFederationPeer(remoteColony.Name, "site1", server1ID, TESTHOST, TESTPORT, true, env.colonyName, []string{}, []string{})
+	_, err = client2.AddFederationPeer(remotePeer, remoteColonyPrvKey)
+	assert.Nil(t, err)
+
+	err = server1.SetFederation(server1PrvKey, SkipTLSVerify)
+	assert.Nil(t, err)
+
+	// A full batch of processes pinned to named executors, which are never forwarded, is queued before the process
+	for i := 0; i < FEDERATION_BATCH_SIZE; i++ {
+		funcSpec := utils.CreateTestFunctionSpecWithType(env.colonyName, "federated_executor_type")
+		funcSpec.Conditions.ExecutorNames = []string{"local_executor"}
+		_, err := client1.Submit(funcSpec, env.executorPrvKey)
+		assert.Nil(t, err)
+	}
+
+	process, err := client1.Submit(utils.CreateTestFunctionSpecWithType(env.colonyName, "federated_executor_type"), env.executorPrvKey)
+	assert.Nil(t, err)
+
+	server1.controller.forwardProcesses()
+
+	process, err = client1.GetProcess(process.ID, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, core.RUNNING, process.State)
+	assert.Equal(t, peer.ID, process.AssignedExecutorID)
+
+	server2.Shutdown()
+	<-done2
+	server1.Shutdown()
+	<-done1
+}
+
+func TestIsFederationPeerError(t *testing.T) {
+	assert.True(t, isFederationPeerError(errors.New("connection refused")))
+	assert.True(t, isFederationPeerError(&core.ColoniesError{Status: http.StatusForbidden}))
+	assert.True(t, isFederationPeerError(&core.ColoniesError{Status: http.StatusServiceUnavailable}))
+	assert.False(t, isFederationPeerError(&core.ColoniesError{Status: http.StatusBadRequest}))
+}
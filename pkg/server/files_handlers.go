@@ -3,6 +3,7 @@ package server
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/rpc"
@@ -53,6 +54,8 @@ func (server *ColoniesServer) handleAddFileHTTPRequest(c *gin.Context, recovered
 
 	log.WithFields(log.Fields{"FileID": file.ID}).Debug("Adding file")
 
+	server.publishEvent(core.CreateFileEvent(core.FileAddedEvent, addedFile))
+
 	server.sendHTTPReply(c, payloadType, jsonStr)
 }
 
@@ -93,7 +96,14 @@ func (server *ColoniesServer) handleGetFileHTTPRequest(c *gin.Context, recovered
 		}
 		files = []*core.File{file}
 	} else if msg.Label != "" && msg.Name != "" {
-		if msg.Latest {
+		if msg.AsOf > 0 {
+			files, err = server.db.GetFileByNameAsOf(msg.ColonyName, msg.Label, msg.Name, time.Unix(0, msg.AsOf))
+			if server.handleHTTPError(c, err, http.StatusBadRequest) {
+				log.WithFields(log.Fields{"Error": err}).Debug("Failed to get file")
+				server.handleHTTPError(c, err, http.StatusInternalServerError)
+				return
+			}
+		} else if msg.Latest {
 			files, err = server.db.GetLatestFileByName(msg.ColonyName, msg.Label, msg.Name)
 			if server.handleHTTPError(c, err, http.StatusBadRequest) {
 				log.WithFields(log.Fields{"Error": err}).Debug("Failed to get file")
@@ -140,7 +150,7 @@ func (server *ColoniesServer) handleGetFileHTTPRequest(c *gin.Context, recovered
 		return
 	}
 
-	log.WithFields(log.Fields{"FileID": msg.FileID, "Label": msg.Label, "Name": msg.Name, "Latest": msg.Latest}).Debug("Getting file")
+	log.WithFields(log.Fields{"FileID": msg.FileID, "Label": msg.Label, "Name": msg.Name, "Latest": msg.Latest, "AsOf": msg.AsOf}).Debug("Getting file")
 
 	server.sendHTTPReply(c, payloadType, jsonStr)
 }
@@ -164,10 +174,19 @@ func (server *ColoniesServer) handleGetFilesHTTPRequest(c *gin.Context, recovere
 		return
 	}
 
-	fileDataArr, err := server.db.GetFileDataByLabel(msg.ColonyName, msg.Label)
-	if server.handleHTTPError(c, err, http.StatusBadRequest) {
-		log.Error(err)
-		return
+	var fileDataArr []*core.FileData
+	if msg.AsOf > 0 {
+		fileDataArr, err = server.db.GetFileDataByLabelAsOf(msg.ColonyName, msg.Label, time.Unix(0, msg.AsOf))
+		if server.handleHTTPError(c, err, http.StatusBadRequest) {
+			log.Error(err)
+			return
+		}
+	} else {
+		fileDataArr, err = server.db.GetFileDataByLabel(msg.ColonyName, msg.Label)
+		if server.handleHTTPError(c, err, http.StatusBadRequest) {
+			log.Error(err)
+			return
+		}
 	}
 
 	jsonBytes, err := core.ConvertFileDataArrayToJSON(fileDataArr)
@@ -242,12 +261,21 @@ func (server *ColoniesServer) handleRemoveFileHTTPRequest(c *gin.Context, recove
 		return
 	}
 
+	var removedFiles []*core.File
 	if msg.FileID != "" {
+		file, err := server.db.GetFileByID(msg.ColonyName, msg.FileID)
+		if err == nil && file != nil {
+			removedFiles = append(removedFiles, file)
+		}
 		err = server.db.RemoveFileByID(msg.ColonyName, msg.FileID)
 		if server.handleHTTPError(c, err, http.StatusBadRequest) {
 			return
 		}
 	} else if msg.Label != "" && msg.Name != "" {
+		files, err := server.db.GetFileByName(msg.ColonyName, msg.Label, msg.Name)
+		if err == nil {
+			removedFiles = files
+		}
 		err = server.db.RemoveFileByName(msg.ColonyName, msg.Label, msg.Name)
 		if server.handleHTTPError(c, err, http.StatusBadRequest) {
 			return
@@ -258,5 +286,9 @@ func (server *ColoniesServer) handleRemoveFileHTTPRequest(c *gin.Context, recove
 		}
 	}
 
+	for _, file := range removedFiles {
+		server.publishEvent(core.CreateFileEvent(core.FileRemovedEvent, file))
+	}
+
 	server.sendEmptyHTTPReply(c, payloadType)
 }
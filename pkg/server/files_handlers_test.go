@@ -2,6 +2,7 @@ package server
 
 import (
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/utils"
 	"github.com/stretchr/testify/assert"
@@ -129,6 +130,56 @@ func TestGetFiles(t *testing.T) {
 	<-done
 }
 
+func TestGetFileByNameAsOf(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	label := "/testprefix"
+	name := "testfile"
+
+	file := utils.CreateTestFile(env.colonyName)
+	file.Label = label
+	file.Name = name
+	file.Size = 1
+	addedFile1, err := client.AddFile(file, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	afterFirst := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	file = utils.CreateTestFile(env.colonyName)
+	file.Label = label
+	file.Name = name
+	file.Size = 2
+	addedFile2, err := client.AddFile(file, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	fileFromServer, err := client.GetFileByNameAsOf(env.colonyName, label, name, afterFirst, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, fileFromServer, 1)
+	assert.Equal(t, fileFromServer[0].ID, addedFile1.ID)
+
+	fileFromServer, err = client.GetFileByNameAsOf(env.colonyName, label, name, time.Now(), env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, fileFromServer, 1)
+	assert.Equal(t, fileFromServer[0].ID, addedFile2.ID)
+
+	_, err = client.GetFileByNameAsOf(env.colonyName, label, name, afterFirst.Add(-time.Hour), env.executorPrvKey)
+	assert.NotNil(t, err) // No revision existed at that time
+
+	fileData, err := client.GetFileDataAsOf(env.colonyName, label, afterFirst, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, fileData, 1)
+	assert.Equal(t, fileData[0].Size, int64(1))
+
+	fileData, err = client.GetFileDataAsOf(env.colonyName, label, afterFirst.Add(-time.Hour), env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, fileData, 0)
+
+	server.Shutdown()
+	<-done
+}
+
 func TestGetFileLabels(t *testing.T) {
 	env, client, server, _, done := setupTestEnv2(t)
 
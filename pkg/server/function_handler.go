@@ -3,6 +3,8 @@ package server
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/rpc"
@@ -170,7 +172,7 @@ func (server *ColoniesServer) handleRemoveFunctionHTTPRequest(c *gin.Context, re
 		}
 	}
 
-	err = server.controller.removeFunction(msg.FunctionID)
+	err = server.controller.removeFunction(msg.FunctionID, function.ColonyName)
 	if server.handleHTTPError(c, err, http.StatusForbidden) {
 		return
 	}
@@ -179,3 +181,49 @@ func (server *ColoniesServer) handleRemoveFunctionHTTPRequest(c *gin.Context, re
 
 	server.sendEmptyHTTPReply(c, payloadType)
 }
+
+func (server *ColoniesServer) handleGetFunctionStatsHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetFunctionStatsMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to get function stats, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to get function stats, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireMembership(recoveredID, msg.ColonyName, true)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	if msg.Seconds <= 0 || msg.Seconds > MAX_DAYS*24*3600 {
+		server.handleHTTPError(c, errors.New("Failed to get function stats, seconds must be between 1 and "+strconv.Itoa(MAX_DAYS*24*3600)), http.StatusBadRequest)
+		return
+	}
+
+	if msg.BucketSeconds < 0 || (msg.BucketSeconds > 0 && msg.Seconds/msg.BucketSeconds > MAX_FUNCTION_STATS_BUCKETS) {
+		server.handleHTTPError(c, errors.New("Failed to get function stats, number of buckets exceeds max bucket count ("+strconv.Itoa(MAX_FUNCTION_STATS_BUCKETS)+")"), http.StatusBadRequest)
+		return
+	}
+
+	end := time.Now()
+	start := end.Add(-time.Duration(msg.Seconds) * time.Second)
+	runs, err := server.db.GetFunctionRuns(msg.ColonyName, msg.FuncName, msg.ExecutorType, start, MAX_FUNCTION_RUNS)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	stats := core.CalcFunctionStats(runs, start, end, time.Duration(msg.BucketSeconds)*time.Second)
+	jsonString, err = core.ConvertFunctionStatsArrayToJSON(stats)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "FuncName": msg.FuncName, "ExecutorType": msg.ExecutorType, "Seconds": msg.Seconds, "Runs": len(runs)}).Debug("Getting function stats")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
@@ -119,3 +119,23 @@ func TestRemoveFunctionSecurity(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+func TestGetFunctionStatsSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	_, err := client.GetFunctionStats(env.colony1Name, "", "", 3600, 0, env.executor2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.GetFunctionStats(env.colony1Name, "", "", 3600, 0, env.colony1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.GetFunctionStats(env.colony1Name, "", "", 3600, 0, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	server.Shutdown()
+	<-done
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/utils"
@@ -108,3 +109,96 @@ func TestRemoveFunction(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+func TestGetFunctionStats(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	for i := 0; i < core.MinAnomalySamples; i++ {
+		_, err := client.Submit(utils.CreateTestFunctionSpec(env.colonyName), env.executorPrvKey)
+		assert.Nil(t, err)
+		process, err := client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+		assert.Nil(t, err)
+		if i == 0 {
+			err = client.Fail(process.ID, []string{"error"}, env.executorPrvKey)
+		} else {
+			err = client.Close(process.ID, env.executorPrvKey)
+		}
+		assert.Nil(t, err)
+	}
+
+	subscription, err := client.SubscribeEvents(env.colonyName, []string{core.FunctionAnomalyEvent}, "", 0, 100, env.executorPrvKey)
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+
+	// Runs much longer than earlier runs
+	_, err = client.Submit(utils.CreateTestFunctionSpec(env.colonyName), env.executorPrvKey)
+	assert.Nil(t, err)
+	process, err := client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+	time.Sleep(1500 * time.Millisecond)
+	err = client.Close(process.ID, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	select {
+	case event := <-subscription.EventChan:
+		assert.Equal(t, core.FunctionAnomalyEvent, event.Type)
+		assert.Equal(t, process.ID, event.ObjectID)
+		assert.NotNil(t, event.FunctionRun)
+		assert.True(t, event.FunctionRun.Anomaly)
+		assert.Equal(t, env.executorName, event.FunctionRun.ExecutorName)
+	case err := <-subscription.ErrChan:
+		assert.Fail(t, err.Error())
+	}
+	subscription.Close()
+
+	stats, err := client.GetFunctionStats(env.colonyName, "", "", 3600, 60, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, "test_func", stats[0].FuncName)
+	assert.Equal(t, "test_executor_type", stats[0].ExecutorType)
+	assert.Equal(t, core.MinAnomalySamples+1, stats[0].Runs)
+	assert.Equal(t, 1, stats[0].Failed)
+	assert.Equal(t, 1, stats[0].Anomalies)
+	assert.Greater(t, stats[0].ExecTimeP99, 1.0)
+	assert.NotEmpty(t, stats[0].Buckets)
+
+	stats, err = client.GetFunctionStats(env.colonyName, "another_func", "", 3600, 0, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, stats, 0)
+
+	_, err = client.GetFunctionStats(env.colonyName, "", "", 0, 0, env.executorPrvKey)
+	assert.NotNil(t, err)
+
+	_, err = client.GetFunctionStats(env.colonyName, "", "", 3600, 1, env.executorPrvKey)
+	assert.NotNil(t, err) // Too many buckets
+
+	server.Shutdown()
+	<-done
+}
+
+func TestFunctionAnomalyDisabled(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	server.SetAnomalyFactor(0)
+
+	for i := 0; i < core.MinAnomalySamples+1; i++ {
+		_, err := client.Submit(utils.CreateTestFunctionSpec(env.colonyName), env.executorPrvKey)
+		assert.Nil(t, err)
+		process, err := client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+		assert.Nil(t, err)
+		if i == core.MinAnomalySamples {
+			time.Sleep(1500 * time.Millisecond)
+		}
+		err = client.Close(process.ID, env.executorPrvKey)
+		assert.Nil(t, err)
+	}
+
+	stats, err := client.GetFunctionStats(env.colonyName, "test_func", "test_executor_type", 3600, 0, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, core.MinAnomalySamples+1, stats[0].Runs)
+	assert.Equal(t, 0, stats[0].Anomalies)
+
+	server.Shutdown()
+	<-done
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/parsers"
+	log "github.com/sirupsen/logrus"
+)
+
+// setAnomalyFactor sets how many times longer than the historical p95 exec time a run must take to be flagged as an
+// anomaly, a factor of 0 disables anomaly detection
+func (controller *coloniesController) setAnomalyFactor(factor float64) {
+	controller.anomalyFactorMux.Lock()
+	defer controller.anomalyFactorMux.Unlock()
+
+	controller.anomalyFactor = factor
+}
+
+func (controller *coloniesController) getAnomalyFactor() float64 {
+	controller.anomalyFactorMux.Lock()
+	defer controller.anomalyFactorMux.Unlock()
+
+	return controller.anomalyFactor
+}
+
+// recordFunctionRun adds a closed process to the function run history. The run is flagged as an anomaly if it took
+// longer than the anomaly factor times the p95 exec time of earlier runs of the function on the same executor type.
+// Failing to record a run does not fail closing the process.
+func (controller *coloniesController) recordFunctionRun(process *core.Process, waitTime float64, execTime float64, successful bool, endTime time.Time) {
+	run := &core.FunctionRun{
+		ProcessID:     process.ID,
+		ColonyName:    process.FunctionSpec.Conditions.ColonyName,
+		FuncName:      process.FunctionSpec.FuncName,
+		ExecutorType:  process.FunctionSpec.Conditions.ExecutorType,
+		WaitTime:      waitTime,
+		ExecTime:      execTime,
+		Successful:    successful,
+		EndTime:       endTime,
+		InitiatorName: process.InitiatorName,
+		Label:         process.FunctionSpec.Label,
+	}
+
+	// Usage is based on the requested resources, as executors do not report what a process actually consumed
+	nodes := process.FunctionSpec.Conditions.Nodes
+	if nodes < 1 {
+		nodes = 1
+	}
+	cpu, err := parsers.ConvertCPUToInt(process.FunctionSpec.Conditions.CPU)
+	if err == nil {
+		run.CPU = cpu * int64(nodes)
+	}
+	run.GPUs = process.FunctionSpec.Conditions.GPU.Count * nodes
+
+	executor, err := controller.db.GetExecutorByID(process.AssignedExecutorID)
+	if err == nil && executor != nil {
+		run.ExecutorType = executor.Type
+		run.ExecutorName = executor.Name
+	}
+
+	history, err := controller.db.GetFunctionRuns(run.ColonyName, run.FuncName, run.ExecutorType, endTime.Add(-ANOMALY_HISTORY_PERIOD), MAX_ANOMALY_HISTORY)
+	if err != nil {
+		log.WithFields(log.Fields{"ProcessId": process.ID, "Error": err}).Error("Failed to get function run history")
+	} else {
+		run.Anomaly = core.IsAnomaly(execTime, history, controller.getAnomalyFactor())
+	}
+
+	err = controller.db.AddFunctionRun(run)
+	if err != nil {
+		log.WithFields(log.Fields{"ProcessId": process.ID, "Error": err}).Error("Failed to add function run")
+		return
+	}
+
+	if run.Anomaly {
+		log.WithFields(log.Fields{
+			"ProcessId":    run.ProcessID,
+			"ColonyName":   run.ColonyName,
+			"FuncName":     run.FuncName,
+			"ExecutorType": run.ExecutorType,
+			"ExecTime":     run.ExecTime}).
+			Warn("Function run took abnormally long")
+		controller.eventHandler.publish(core.CreateFunctionAnomalyEvent(run, process.FunctionSpec.Label))
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
@@ -8,7 +9,7 @@ import (
 )
 
 func (controller *coloniesController) addGenerator(generator *core.Generator) (*core.Generator, error) {
-	cmd := &command{generatorReplyChan: make(chan *core.Generator, 1),
+	cmd := &command{colonyName: generator.ColonyName, generatorReplyChan: make(chan *core.Generator, 1),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			err := controller.db.AddGenerator(generator)
@@ -21,6 +22,7 @@ func (controller *coloniesController) addGenerator(generator *core.Generator) (*
 				cmd.errorChan <- err
 				return
 			}
+			controller.eventHandler.publish(core.CreateGeneratorEvent(core.GeneratorAddedEvent, addedGenerator))
 			cmd.generatorReplyChan <- addedGenerator
 		}}
 
@@ -33,8 +35,12 @@ func (controller *coloniesController) addGenerator(generator *core.Generator) (*
 	}
 }
 
+// triggerGenerators submits the workflows of the generators that are ready, and returns when they have been submitted
+// so that the run is fenced by runJob until then
 func (controller *coloniesController) triggerGenerators() {
-	cmd := &command{handler: func(cmd *command) {
+	cmd := &command{errorChan: make(chan error, 1), handler: func(cmd *command) {
+		defer func() { cmd.errorChan <- nil }()
+
 		generatorsFromDB, err := controller.db.FindAllGenerators()
 		if err != nil {
 			log.WithFields(log.Fields{"Error": err}).Error("Failed get all generators from db")
@@ -79,6 +85,7 @@ func (controller *coloniesController) triggerGenerators() {
 	}}
 
 	controller.cmdQueue <- cmd
+	<-cmd.errorChan
 }
 
 func (controller *coloniesController) getGenerator(generatorID string) (*core.Generator, error) {
@@ -103,7 +110,7 @@ func (controller *coloniesController) getGenerator(generatorID string) (*core.Ge
 }
 
 func (controller *coloniesController) resolveGenerator(colonyName string, generatorName string) (*core.Generator, error) {
-	cmd := &command{generatorReplyChan: make(chan *core.Generator, 1),
+	cmd := &command{colonyName: colonyName, generatorReplyChan: make(chan *core.Generator, 1),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			generator, err := controller.db.GetGeneratorByName(colonyName, generatorName)
@@ -124,7 +131,7 @@ func (controller *coloniesController) resolveGenerator(colonyName string, genera
 }
 
 func (controller *coloniesController) getGenerators(colonyName string, count int) ([]*core.Generator, error) {
-	cmd := &command{generatorsReplyChan: make(chan []*core.Generator, 1),
+	cmd := &command{colonyName: colonyName, generatorsReplyChan: make(chan []*core.Generator, 1),
 		errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			generators, err := controller.db.FindGeneratorsByColonyName(colonyName, count)
@@ -145,7 +152,7 @@ func (controller *coloniesController) getGenerators(colonyName string, count int
 }
 
 func (controller *coloniesController) packGenerator(generatorID string, colonyName, arg string) error {
-	cmd := &command{errorChan: make(chan error, 1),
+	cmd := &command{colonyName: colonyName, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			generatorArg := core.CreateGeneratorArg(generatorID, colonyName, arg)
 			err := controller.db.AddGeneratorArg(generatorArg)
@@ -190,10 +197,7 @@ func (controller *coloniesController) generatorTriggerLoop() {
 		}
 		controller.stopMutex.Unlock()
 
-		isLeader := controller.tryBecomeLeader()
-		if isLeader {
-			controller.triggerGenerators()
-		}
+		controller.runJob(GENERATOR_JOB, controller.triggerGenerators)
 	}
 }
 
@@ -222,7 +226,7 @@ func (controller *coloniesController) submitWorkflow(generator *core.Generator,
 		argsif[i] = v
 	}
 
-	_, err = controller.createProcessGraph(workflowSpec, argsif, make(map[string]interface{}), make([]interface{}, 0), recoveredID)
+	_, err = controller.createProcessGraph(workflowSpec, argsif, make(map[string]interface{}), make([]interface{}, 0), recoveredID, context.Background())
 	if err != nil {
 		log.WithFields(log.Fields{
 			"Error": err}).
@@ -230,6 +234,8 @@ func (controller *coloniesController) submitWorkflow(generator *core.Generator,
 		return
 	}
 
+	controller.eventHandler.publish(core.CreateGeneratorEvent(core.GeneratorTriggeredEvent, generator))
+
 	// Now it safe to remove the args since they are now attached to a process graph
 	for _, generatorArg := range generatorArgs {
 		count, err := controller.db.CountGeneratorArgs(generator.ID)
@@ -250,7 +250,7 @@ func (server *ColoniesServer) handleRemoveGeneratorHTTPRequest(c *gin.Context, r
 		return
 	}
 
-	err = server.controller.removeGenerator(generator.ID)
+	err = server.controller.removeGenerator(generator.ID, generator.ColonyName)
 	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
 		return
 	}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/cluster"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// probeCmdQueue sends a no-op command through the command queue and returns the time until it was handled. Since the
+// command queue is unbuffered, the latency grows with the number of commands waiting to be handled.
+func (controller *coloniesController) probeCmdQueue(timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	done := make(chan struct{})
+	cmd := &command{name: "probe", handler: func(cmd *command) {
+		close(done)
+	}}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case controller.cmdQueue <- cmd:
+	case <-timer.C:
+		return time.Since(start), errors.New("Timed out waiting to add command to command queue")
+	}
+
+	select {
+	case <-done:
+		return time.Since(start), nil
+	case <-timer.C:
+		return time.Since(start), errors.New("Timed out waiting for command to be handled")
+	}
+}
+
+func (controller *coloniesController) getRelayServer() *cluster.RelayServer {
+	return controller.relayServer
+}
+
+func runHealthCheck(name string, check func(check *core.HealthCheck) error) *core.HealthCheck {
+	healthCheck := &core.HealthCheck{Name: name, Status: core.HealthStatusOK, Details: make(map[string]string)}
+	start := time.Now()
+	err := check(healthCheck)
+	healthCheck.Duration = time.Since(start).Seconds()
+	if err != nil {
+		healthCheck.Status = core.HealthStatusFail
+		healthCheck.Message = err.Error()
+	}
+
+	return healthCheck
+}
+
+func (server *ColoniesServer) checkDatabase() *core.HealthCheck {
+	return runHealthCheck("database", func(check *core.HealthCheck) error {
+		ctx, cancel := context.WithTimeout(context.Background(), HEALTH_CHECK_TIMEOUT)
+		defer cancel()
+
+		return server.db.Ping(ctx)
+	})
+}
+
+func (server *ColoniesServer) checkEtcd() *core.HealthCheck {
+	return runHealthCheck("etcd", func(check *core.HealthCheck) error {
+		etcdServer := server.controller.getEtcdServer()
+		if etcdServer == nil {
+			return errors.New("etcd server is not started")
+		}
+
+		leader := etcdServer.Leader()
+		isMember := etcdServer.IsMember()
+		check.Details["leader"] = leader
+		check.Details["members"] = strconv.Itoa(len(etcdServer.Members()))
+		check.Details["member"] = strconv.FormatBool(isMember)
+
+		if !isMember {
+			return errors.New("Node is not a member of the etcd cluster")
+		}
+
+		if leader == "" {
+			return errors.New("etcd cluster has no leader")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), HEALTH_CHECK_TIMEOUT)
+		defer cancel()
+
+		return etcdServer.CheckQuorum(ctx)
+	})
+}
+
+func (server *ColoniesServer) checkRelayServer() *core.HealthCheck {
+	return runHealthCheck("relay", func(check *core.HealthCheck) error {
+		relayServer := server.controller.getRelayServer()
+		if relayServer == nil {
+			return errors.New("Relay server is not started")
+		}
+
+		return relayServer.Err()
+	})
+}
+
+func (server *ColoniesServer) checkCmdQueue() *core.HealthCheck {
+	return runHealthCheck("cmdqueue", func(check *core.HealthCheck) error {
+		latency, err := server.controller.probeCmdQueue(HEALTH_CHECK_TIMEOUT)
+		check.Details["latency"] = strconv.FormatFloat(latency.Seconds(), 'f', 6, 64)
+
+		return err
+	})
+}
+
+func (server *ColoniesServer) checkDraining() *core.HealthCheck {
+	return runHealthCheck("drain", func(check *core.HealthCheck) error {
+		if server.IsDraining() {
+			return server.drainError()
+		}
+
+		return nil
+	})
+}
+
+func (server *ColoniesServer) sendHealthReport(c *gin.Context, checks []*core.HealthCheck) {
+	report := core.CreateHealthReport(server.controller.getThisNode().Name, checks)
+	jsonString, err := report.ToJSON()
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	statusCode := http.StatusOK
+	if !report.IsOK() {
+		log.WithFields(log.Fields{"Report": jsonString}).Warn("Health check failed")
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.String(statusCode, jsonString)
+}
+
+// handleLivenessRequest only checks that the server itself is responsive, so that a server is not restarted because
+// a dependency, e.g. the database, is unreachable
+func (server *ColoniesServer) handleLivenessRequest(c *gin.Context) {
+	server.sendHealthReport(c, []*core.HealthCheck{server.checkCmdQueue()})
+}
+
+// handleReadinessRequest checks that the server and all its dependencies are working, so that it can serve requests
+func (server *ColoniesServer) handleReadinessRequest(c *gin.Context) {
+	server.sendHealthReport(c, []*core.HealthCheck{
+		server.checkDraining(),
+		server.checkDatabase(),
+		server.checkEtcd(),
+		server.checkRelayServer(),
+		server.checkCmdQueue(),
+	})
+}
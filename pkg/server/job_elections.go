@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/cluster"
+	log "github.com/sirupsen/logrus"
+)
+
+// startJobElections starts an election for every singleton background job. Unlike isLeader, which follows the etcd
+// raft leader, each job has its own lease, so a job never runs on two nodes at the same time, and different jobs may
+// run on different nodes.
+func (controller *coloniesController) startJobElections() {
+	controller.jobElections = make(map[string]*cluster.JobElection)
+	controller.jobLeaderships = make(map[string]*jobLeadership)
+	for _, job := range []string{CRON_JOB, GENERATOR_JOB, RETENTION_JOB, TIMEOUT_JOB, WEBHOOK_JOB, ALERT_JOB, STATISTICS_JOB, FEDERATION_JOB} {
+		controller.jobElections[job] = controller.etcdServer.CreateJobElection(job, JOB_ELECTION_TTL, controller.jobElectionDelay)
+	}
+
+	for _, election := range controller.jobElections {
+		election.Start()
+	}
+}
+
+func (controller *coloniesController) stopJobElections() {
+	for _, election := range controller.jobElections {
+		election.Stop()
+	}
+}
+
+// jobElectionDelay makes a node wait before campaigning for a job, longer the more jobs it already leads
+func (controller *coloniesController) jobElectionDelay() time.Duration {
+	led := 0
+	for _, election := range controller.jobElections {
+		if election.IsLeader() {
+			led++
+		}
+	}
+
+	return time.Duration(led) * JOB_ELECTION_SPREAD_DELAY
+}
+
+// jobLeadership is a verified leadership of a job, it is trusted for JOB_LEADER_CACHE_PERIOD
+type jobLeadership struct {
+	token    int64
+	verified time.Time
+}
+
+// isJobLeader returns true if this node leads the job, see jobToken
+func (controller *coloniesController) isJobLeader(job string) bool {
+	_, ok := controller.jobToken(job)
+	return ok
+}
+
+// jobToken returns the fencing token of the job if this node leads it. Leadership is verified against etcd, and the
+// fencing token is then recorded in the database, which rejects it if a later leader has already run the job. A
+// verified leadership is trusted for JOB_LEADER_CACHE_PERIOD, runs of the job are still rejected by runJob as soon as a
+// later leader has recorded its token. A draining node does not run jobs, so that it stops creating commands before
+// handing the jobs off.
+func (controller *coloniesController) jobToken(job string) (int64, bool) {
+	if controller.isDraining() {
+		return 0, false
+	}
+
+	election, ok := controller.jobElections[job]
+	if !ok || !election.IsLeader() {
+		return 0, false
+	}
+
+	controller.jobLeadershipMux.Lock()
+	leadership, ok := controller.jobLeaderships[job]
+	controller.jobLeadershipMux.Unlock()
+	if ok && time.Since(leadership.verified) < JOB_LEADER_CACHE_PERIOD {
+		return leadership.token, true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), JOB_LEADER_VERIFY_TIMEOUT)
+	defer cancel()
+
+	token, err := election.Verify(ctx)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "Job": job}).Warn("Failed to verify job leadership")
+		controller.forgetJobLeadership(job)
+		return 0, false
+	}
+
+	err = controller.db.FenceJob(job, token, controller.jobFenceTTL(job))
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "Job": job, "FencingToken": token}).Warn("Job is fenced off")
+		controller.forgetJobLeadership(job)
+		return 0, false
+	}
+
+	log.WithFields(log.Fields{"Job": job, "FencingToken": token}).Debug("Running job as leader")
+
+	controller.jobLeadershipMux.Lock()
+	controller.jobLeaderships[job] = &jobLeadership{token: token, verified: time.Now()}
+	controller.jobLeadershipMux.Unlock()
+
+	return token, true
+}
+
+func (controller *coloniesController) forgetJobLeadership(job string) {
+	controller.jobLeadershipMux.Lock()
+	defer controller.jobLeadershipMux.Unlock()
+
+	delete(controller.jobLeaderships, job)
+}
+
+// runJob runs the job if this node leads it. The run is fenced with the fencing token of this node, so it is rejected
+// if a later leader has recorded its token, and a later leader cannot record its token until the run has completed.
+// The writes of a job are thus never interleaved with the writes of another leader of the job.
+func (controller *coloniesController) runJob(job string, run func()) bool {
+	token, ok := controller.jobToken(job)
+	if !ok {
+		return false
+	}
+
+	err := controller.db.RunFencedJob(job, token, func() error {
+		run()
+		return nil
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "Job": job, "FencingToken": token}).Warn("Job is fenced off")
+		controller.forgetJobLeadership(job)
+		return false
+	}
+
+	return true
+}
+
+// jobFenceTTL is how long the fencing token of a job blocks leaders with older tokens. It must be longer than the
+// period of the job, otherwise the token of a leader expires between its runs and a stale leader is let through.
+func (controller *coloniesController) jobFenceTTL(job string) time.Duration {
+	var period time.Duration
+	switch job {
+	case CRON_JOB:
+		period = time.Duration(controller.cronPeriod) * time.Millisecond
+	case GENERATOR_JOB:
+		period = time.Duration(controller.generatorPeriod) * time.Millisecond
+	case RETENTION_JOB:
+		period = time.Duration(controller.retentionPeriod) * time.Millisecond
+	case TIMEOUT_JOB:
+		period = RELEASE_PERIOD * time.Second
+	case WEBHOOK_JOB:
+		period = webhookDeliveryCleanupPeriod
+	case ALERT_JOB:
+		period = ALERT_EVALUATION_PERIOD
+	case STATISTICS_JOB:
+		period = controller.getStatisticsPeriod()
+	case FEDERATION_JOB:
+		period = FEDERATION_PERIOD
+	}
+
+	return JOB_FENCE_TTL + JOB_FENCE_PERIODS*period
+}
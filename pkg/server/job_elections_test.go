@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobElections(t *testing.T) {
+	_, _, server, _, done := setupTestEnv2(t)
+
+	thisNode := server.controller.getThisNode()
+	for _, job := range []string{CRON_JOB, GENERATOR_JOB, RETENTION_JOB, TIMEOUT_JOB} {
+		isLeader := false
+		for i := 0; i < 50 && !isLeader; i++ {
+			isLeader = server.controller.isJobLeader(job)
+			if !isLeader {
+				time.Sleep(100 * time.Millisecond)
+			}
+		}
+		assert.True(t, isLeader)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		name, token, err := server.controller.getEtcdServer().JobLeader(ctx, job)
+		cancel()
+		assert.Nil(t, err)
+		assert.Equal(t, thisNode.Name, name)
+		assert.True(t, token > 0)
+	}
+
+	assert.False(t, server.controller.isJobLeader("does_not_exist"))
+
+	server.Shutdown()
+	<-done
+}
+
+func TestRunJobFencedOff(t *testing.T) {
+	_, _, server, _, done := setupTestEnv2(t)
+
+	controller := server.controller.(*coloniesController)
+
+	ran := false
+	for i := 0; i < 50 && !ran; i++ {
+		ran = controller.runJob(CRON_JOB, func() {})
+		if !ran {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	assert.True(t, ran)
+
+	// A later leader records its fencing token, the cached leadership of this node is then rejected
+	token, ok := controller.jobToken(CRON_JOB)
+	assert.True(t, ok)
+	err := server.db.FenceJob(CRON_JOB, token+1, controller.jobFenceTTL(CRON_JOB))
+	assert.Nil(t, err)
+
+	ran = controller.runJob(CRON_JOB, func() { assert.Fail(t, "Fenced off job was run") })
+	assert.False(t, ran)
+	_, ok = controller.jobToken(CRON_JOB)
+	assert.False(t, ok)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestJobFenceTTL(t *testing.T) {
+	controller := &coloniesController{cronPeriod: 1000, retentionPeriod: 3600000}
+
+	assert.Equal(t, JOB_FENCE_TTL+2*time.Second, controller.jobFenceTTL(CRON_JOB))
+	assert.Equal(t, JOB_FENCE_TTL+2*time.Hour, controller.jobFenceTTL(RETENTION_JOB))
+	assert.Equal(t, JOB_FENCE_TTL+2*ALERT_EVALUATION_PERIOD, controller.jobFenceTTL(ALERT_JOB))
+}
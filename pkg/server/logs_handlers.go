@@ -25,6 +25,20 @@ func (server *ColoniesServer) handleAddLogHTTPRequest(c *gin.Context, recoveredI
 		return
 	}
 
+	if msg.Level == "" {
+		msg.Level = core.LogLevelInfo
+	}
+
+	if !core.IsValidLogLevel(msg.Level) {
+		server.handleHTTPError(c, errors.New("Failed to add log, invalid level <"+msg.Level+">"), http.StatusBadRequest)
+		return
+	}
+
+	if !core.IsValidLogStream(msg.Stream) {
+		server.handleHTTPError(c, errors.New("Failed to add log, invalid stream <"+msg.Stream+">"), http.StatusBadRequest)
+		return
+	}
+
 	process, err := server.controller.getProcess(msg.ProcessID)
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		return
@@ -64,14 +78,25 @@ func (server *ColoniesServer) handleAddLogHTTPRequest(c *gin.Context, recoveredI
 		return
 	}
 
-	colonyName := process.FunctionSpec.Conditions.ColonyName
-	err = server.db.AddLog(process.ID, colonyName, executor.Name, time.Now().UTC().UnixNano(), msg.Message)
+	l := &core.Log{
+		ProcessID:    process.ID,
+		ColonyName:   process.FunctionSpec.Conditions.ColonyName,
+		ExecutorName: executor.Name,
+		Message:      msg.Message,
+		Timestamp:    time.Now().UTC().UnixNano(),
+		Level:        msg.Level,
+		Stream:       msg.Stream,
+		Fields:       msg.Fields,
+	}
+	err = server.db.AddStructuredLog(l)
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		log.WithFields(log.Fields{"Error": err}).Debug("Failed to add log")
 		server.handleHTTPError(c, err, http.StatusInternalServerError)
 		return
 	}
 
+	server.controller.getEventHandler().publishLog(l)
+
 	log.WithFields(log.Fields{"ProcessId": process.ID}).Debug("Adding log")
 
 	server.sendEmptyHTTPReply(c, payloadType)
@@ -200,7 +225,12 @@ func (server *ColoniesServer) handleSearchLogsHTTPRequest(c *gin.Context, recove
 		}
 	}
 
-	logs, err := server.db.SearchLogs(msg.ColonyName, msg.Text, msg.Days, msg.Count)
+	if msg.Level != "" && !core.IsValidLogLevel(msg.Level) {
+		server.handleHTTPError(c, errors.New("Failed to search logs, invalid level <"+msg.Level+">"), http.StatusBadRequest)
+		return
+	}
+
+	logs, err := server.db.SearchStructuredLogs(msg.ColonyName, msg.Text, msg.Level, msg.Fields, msg.Days, msg.Count)
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		log.WithFields(log.Fields{"Error": err, "ColonyName": msg.ColonyName}).Debug("Failed to search logs")
 		return
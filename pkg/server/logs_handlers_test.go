@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -165,3 +166,74 @@ func TestSearchLogsByExecutor(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+func TestAddStructuredLog(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	_, err := client.Submit(funcSpec, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err := client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+
+	err = client.AddStructuredLog(assignedProcess.ID, "invalid_level", "", "test_msg", nil, env.executorPrvKey)
+	assert.NotNil(t, err)
+
+	err = client.AddStructuredLog(assignedProcess.ID, core.LogLevelInfo, "invalid_stream", "test_msg", nil, env.executorPrvKey)
+	assert.NotNil(t, err)
+
+	err = client.AddStructuredLog(assignedProcess.ID, core.LogLevelError, core.LogStreamStderr, "test_msg", map[string]string{"key": "value"}, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	err = client.AddLog(assignedProcess.ID, "test_msg2", env.executorPrvKey)
+	assert.Nil(t, err)
+
+	logs, err := client.GetLogsByProcess(env.colonyName, assignedProcess.ID, 100, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, core.LogLevelError, logs[0].Level)
+	assert.Equal(t, core.LogStreamStderr, logs[0].Stream)
+	assert.Equal(t, "value", logs[0].Fields["key"])
+	assert.Equal(t, core.LogLevelInfo, logs[1].Level)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestSearchLogsWithFilter(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	_, err := client.Submit(funcSpec, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err := client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+
+	err = client.AddStructuredLog(assignedProcess.ID, core.LogLevelDebug, "", "test_msg", map[string]string{"request": "1"}, env.executorPrvKey)
+	assert.Nil(t, err)
+	err = client.AddStructuredLog(assignedProcess.ID, core.LogLevelWarn, "", "test_msg", map[string]string{"request": "2"}, env.executorPrvKey)
+	assert.Nil(t, err)
+	err = client.AddStructuredLog(assignedProcess.ID, core.LogLevelError, "", "test_msg", map[string]string{"request": "2"}, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	_, err = client.SearchLogsWithFilter(env.colonyName, "", "invalid_level", nil, 1, 10, env.executorPrvKey)
+	assert.NotNil(t, err)
+
+	logs, err := client.SearchLogsWithFilter(env.colonyName, "test_msg", core.LogLevelWarn, nil, 1, 10, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+
+	logs, err = client.SearchLogsWithFilter(env.colonyName, "", "", map[string]string{"request": "1"}, 1, 10, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, core.LogLevelDebug, logs[0].Level)
+
+	logs, err = client.SearchLogsWithFilter(env.colonyName, "", core.LogLevelError, map[string]string{"request": "2"}, 1, 10, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+
+	server.Shutdown()
+	<-done
+}
@@ -0,0 +1,360 @@
+package server
+
+import (
+	"time"
+
+	"github.com/colonyos/colonies/pkg/cluster"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+)
+
+const metricsNamespace = "colonies"
+
+// serverMetrics are exported in the Prometheus text format on /metrics. Every server has its own registry so that
+// several servers can run in the same process, e.g. in tests. All methods can be called on a nil *serverMetrics.
+type serverMetrics struct {
+	registry        *prometheus.Registry
+	rpcRequests     *prometheus.CounterVec
+	rpcDuration     *prometheus.HistogramVec
+	assignLatency   *prometheus.HistogramVec
+	processWaitTime *prometheus.HistogramVec
+	processExecTime *prometheus.HistogramVec
+	dbQueryDuration *prometheus.HistogramVec
+	cmdQueueWait    *prometheus.HistogramVec
+}
+
+func createServerMetrics() *serverMetrics {
+	metrics := &serverMetrics{}
+	metrics.registry = prometheus.NewRegistry()
+
+	metrics.rpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "rpc_requests_total",
+		Help:      "Number of RPC requests by payload type and HTTP status code",
+	}, []string{"payloadtype", "code"})
+
+	metrics.rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "rpc_request_duration_seconds",
+		Help:      "RPC request latency by payload type, assign requests include the time spent waiting for a process",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 18),
+	}, []string{"payloadtype"})
+
+	metrics.assignLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "assign_latency_seconds",
+		Help:      "Time from an assign request is received until a process is assigned",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 18),
+	}, []string{"colony", "executortype"})
+
+	metrics.processWaitTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "process_wait_time_seconds",
+		Help:      "Time processes spend waiting to be assigned by function",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 20),
+	}, []string{"colony", "function"})
+
+	metrics.processExecTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "process_exec_time_seconds",
+		Help:      "Time from processes are assigned until they are closed by function and state",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 20),
+	}, []string{"colony", "function", "state"})
+
+	metrics.dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by operation",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 16),
+	}, []string{"operation"})
+
+	metrics.cmdQueueWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "command_queue_wait_seconds",
+		Help:      "Time commands wait in the command queue of their colony before they are run",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 18),
+	}, []string{"colony", "queue"})
+
+	metrics.registry.MustRegister(metrics.rpcRequests,
+		metrics.rpcDuration,
+		metrics.assignLatency,
+		metrics.processWaitTime,
+		metrics.processExecTime,
+		metrics.dbQueryDuration,
+		metrics.cmdQueueWait,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	return metrics
+}
+
+// registerClusterMetrics registers metrics that are collected from the database and the cluster when scraped
+func (metrics *serverMetrics) registerClusterMetrics(db database.Database, isLeader func() bool) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.registry.MustRegister(&queueDepthCollector{
+		db: db,
+		desc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "queue_depth"),
+			"Number of waiting processes by colony and executor type",
+			[]string{"colony", "executortype"}, nil),
+	})
+
+	metrics.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "cluster_leader",
+		Help:      "1 if this server is the cluster leader, otherwise 0",
+	}, func() float64 {
+		if isLeader() {
+			return 1
+		}
+		return 0
+	}))
+}
+
+// registerRelayMetrics registers metrics on the delivery of events relayed to the other servers in the cluster
+func (metrics *serverMetrics) registerRelayMetrics(relayServer *cluster.RelayServer) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.registry.MustRegister(&relayCollector{
+		relayServer: relayServer,
+		pendingDesc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "relay", "pending_messages"),
+			"Number of relayed messages not yet acknowledged by node", []string{"node"}, nil),
+		lagDesc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "relay", "lag_seconds"),
+			"Age of the oldest relayed message not yet acknowledged by node", []string{"node"}, nil),
+		deliveredDesc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "relay", "delivered_total"),
+			"Number of relayed messages acknowledged by node", []string{"node"}, nil),
+		failuresDesc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "relay", "failures_total"),
+			"Number of failed relay attempts by node", []string{"node"}, nil),
+		droppedDesc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "relay", "dropped_total"),
+			"Number of relayed messages dropped since the buffer of a node was full", []string{"node"}, nil),
+	})
+}
+
+// registerColonyQueueMetrics registers metrics on the command queues of the colonies, commands not bound to a colony
+// are reported with an empty colony label
+func (metrics *serverMetrics) registerColonyQueueMetrics(schedulers ...*colonyScheduler) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.registry.MustRegister(&colonyQueueCollector{
+		schedulers: schedulers,
+		depthDesc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "command_queue_depth"),
+			"Number of commands waiting to be run by colony and queue", []string{"colony", "queue"}, nil),
+		runningDesc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "commands_running"),
+			"Number of commands running by colony and queue, limited by the database budget of the colony", []string{"colony", "queue"}, nil),
+		scheduledDesc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "commands_total"),
+			"Number of commands run by colony and queue", []string{"colony", "queue"}, nil),
+	})
+}
+
+func (metrics *serverMetrics) handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(metrics.registry, promhttp.HandlerOpts{}))
+}
+
+func (metrics *serverMetrics) observeRPC(payloadType string, code int, start time.Time) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.rpcRequests.WithLabelValues(payloadType, httpStatusCode(code)).Inc()
+	metrics.rpcDuration.WithLabelValues(payloadType).Observe(time.Since(start).Seconds())
+}
+
+func (metrics *serverMetrics) observeAssign(process *core.Process, start time.Time) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.assignLatency.WithLabelValues(process.FunctionSpec.Conditions.ColonyName, process.FunctionSpec.Conditions.ExecutorType).Observe(time.Since(start).Seconds())
+}
+
+func (metrics *serverMetrics) observeProcessWaitTime(process *core.Process, waitTime float64) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.processWaitTime.WithLabelValues(process.FunctionSpec.Conditions.ColonyName, process.FunctionSpec.FuncName).Observe(waitTime)
+}
+
+func (metrics *serverMetrics) observeProcessExecTime(process *core.Process, state string, execTime float64) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.processExecTime.WithLabelValues(process.FunctionSpec.Conditions.ColonyName, process.FunctionSpec.FuncName, state).Observe(execTime)
+}
+
+func (metrics *serverMetrics) observeDBQuery(operation string, start time.Time) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.dbQueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+func (metrics *serverMetrics) observeCommandWait(colonyName string, queue string, enqueued time.Time) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.cmdQueueWait.WithLabelValues(colonyName, queue).Observe(time.Since(enqueued).Seconds())
+}
+
+func httpStatusCode(code int) string {
+	switch {
+	case code >= 500:
+		return "5xx"
+	case code >= 400:
+		return "4xx"
+	case code >= 300:
+		return "3xx"
+	default:
+		return "2xx"
+	}
+}
+
+type queueDepthCollector struct {
+	db   database.Database
+	desc *prometheus.Desc
+}
+
+func (collector *queueDepthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.desc
+}
+
+func (collector *queueDepthCollector) Collect(ch chan<- prometheus.Metric) {
+	queueDepths, err := collector.db.CountWaitingProcessesByExecutorType()
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to count waiting processes")
+		return
+	}
+
+	for _, queueDepth := range queueDepths {
+		ch <- prometheus.MustNewConstMetric(collector.desc, prometheus.GaugeValue, float64(queueDepth.Count), queueDepth.ColonyName, queueDepth.ExecutorType)
+	}
+}
+
+type relayCollector struct {
+	relayServer   *cluster.RelayServer
+	pendingDesc   *prometheus.Desc
+	lagDesc       *prometheus.Desc
+	deliveredDesc *prometheus.Desc
+	failuresDesc  *prometheus.Desc
+	droppedDesc   *prometheus.Desc
+}
+
+func (collector *relayCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.pendingDesc
+	ch <- collector.lagDesc
+	ch <- collector.deliveredDesc
+	ch <- collector.failuresDesc
+	ch <- collector.droppedDesc
+}
+
+func (collector *relayCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stats := range collector.relayServer.Stats() {
+		ch <- prometheus.MustNewConstMetric(collector.pendingDesc, prometheus.GaugeValue, float64(stats.Pending), stats.Node)
+		ch <- prometheus.MustNewConstMetric(collector.lagDesc, prometheus.GaugeValue, stats.Lag.Seconds(), stats.Node)
+		ch <- prometheus.MustNewConstMetric(collector.deliveredDesc, prometheus.CounterValue, float64(stats.Delivered), stats.Node)
+		ch <- prometheus.MustNewConstMetric(collector.failuresDesc, prometheus.CounterValue, float64(stats.Failures), stats.Node)
+		ch <- prometheus.MustNewConstMetric(collector.droppedDesc, prometheus.CounterValue, float64(stats.Dropped), stats.Node)
+	}
+}
+
+type colonyQueueCollector struct {
+	schedulers    []*colonyScheduler
+	depthDesc     *prometheus.Desc
+	runningDesc   *prometheus.Desc
+	scheduledDesc *prometheus.Desc
+}
+
+func (collector *colonyQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.depthDesc
+	ch <- collector.runningDesc
+	ch <- collector.scheduledDesc
+}
+
+func (collector *colonyQueueCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, scheduler := range collector.schedulers {
+		for _, stats := range scheduler.stats() {
+			ch <- prometheus.MustNewConstMetric(collector.depthDesc, prometheus.GaugeValue, float64(stats.Depth), stats.ColonyName, stats.Queue)
+			ch <- prometheus.MustNewConstMetric(collector.runningDesc, prometheus.GaugeValue, float64(stats.Running), stats.ColonyName, stats.Queue)
+			ch <- prometheus.MustNewConstMetric(collector.scheduledDesc, prometheus.CounterValue, float64(stats.Scheduled), stats.ColonyName, stats.Queue)
+		}
+	}
+}
+
+// instrumentedDatabase times the database operations on the hot path of the scheduler
+type instrumentedDatabase struct {
+	database.Database
+	metrics *serverMetrics
+}
+
+func createInstrumentedDatabase(db database.Database, metrics *serverMetrics) database.Database {
+	return &instrumentedDatabase{Database: db, metrics: metrics}
+}
+
+func (db *instrumentedDatabase) AddProcess(process *core.Process) error {
+	defer db.metrics.observeDBQuery("add_process", time.Now())
+	return db.Database.AddProcess(process)
+}
+
+func (db *instrumentedDatabase) GetProcessByID(processID string) (*core.Process, error) {
+	defer db.metrics.observeDBQuery("get_process", time.Now())
+	return db.Database.GetProcessByID(processID)
+}
+
+func (db *instrumentedDatabase) GetExecutorByID(executorID string) (*core.Executor, error) {
+	defer db.metrics.observeDBQuery("get_executor", time.Now())
+	return db.Database.GetExecutorByID(executorID)
+}
+
+func (db *instrumentedDatabase) MarkAlive(executor *core.Executor) error {
+	defer db.metrics.observeDBQuery("mark_alive", time.Now())
+	return db.Database.MarkAlive(executor)
+}
+
+func (db *instrumentedDatabase) FindCandidates(colonyName string, executorType string, cpu int64, memory int64, storage int64, nodes int, processes int, processesPerNode int, count int) ([]*core.Process, error) {
+	defer db.metrics.observeDBQuery("find_candidates", time.Now())
+	return db.Database.FindCandidates(colonyName, executorType, cpu, memory, storage, nodes, processes, processesPerNode, count)
+}
+
+func (db *instrumentedDatabase) FindCandidatesByName(colonyName string, executorName string, executorType string, cpu int64, memory int64, storage int64, nodes int, processes int, processesPerNode int, count int) ([]*core.Process, error) {
+	defer db.metrics.observeDBQuery("find_candidates_by_name", time.Now())
+	return db.Database.FindCandidatesByName(colonyName, executorName, executorType, cpu, memory, storage, nodes, processes, processesPerNode, count)
+}
+
+func (db *instrumentedDatabase) Assign(executorID string, process *core.Process) error {
+	defer db.metrics.observeDBQuery("assign", time.Now())
+	return db.Database.Assign(executorID, process)
+}
+
+func (db *instrumentedDatabase) MarkSuccessful(processID string) (float64, float64, error) {
+	defer db.metrics.observeDBQuery("mark_successful", time.Now())
+	return db.Database.MarkSuccessful(processID)
+}
+
+func (db *instrumentedDatabase) MarkFailed(processID string, errs []string) error {
+	defer db.metrics.observeDBQuery("mark_failed", time.Now())
+	return db.Database.MarkFailed(processID, errs)
+}
+
+func (db *instrumentedDatabase) AddEvent(event *core.Event) error {
+	defer db.metrics.observeDBQuery("add_event", time.Now())
+	return db.Database.AddEvent(event)
+}
+
+func (db *instrumentedDatabase) CountWaitingProcessesByExecutorType() ([]database.QueueDepth, error) {
+	defer db.metrics.observeDBQuery("count_waiting_processes", time.Now())
+	return db.Database.CountWaitingProcessesByExecutorType()
+}
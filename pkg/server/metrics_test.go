@@ -0,0 +1,96 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrapeMetrics(t *testing.T, server *ColoniesServer) string {
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	server.ginHandler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	return w.Body.String()
+}
+
+func TestMetrics(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	funcSpec.FuncName = "test_func"
+	_, err := client.Submit(funcSpec, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	_, err = client.Submit(funcSpec, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	metrics := scrapeMetrics(t, server)
+	assert.Contains(t, metrics, `colonies_queue_depth{colony="`+env.colonyName+`",executortype="`+funcSpec.Conditions.ExecutorType+`"} 2`)
+	assert.Contains(t, metrics, `colonies_rpc_requests_total{code="2xx",payloadtype="`+rpc.SubmitFunctionSpecPayloadType+`"} 2`)
+	assert.Contains(t, metrics, `colonies_cluster_leader 1`)
+
+	assignedProcess, err := client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+
+	err = client.Close(assignedProcess.ID, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err = client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+
+	err = client.Fail(assignedProcess.ID, []string{"error"}, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	metrics = scrapeMetrics(t, server)
+	assert.NotContains(t, metrics, `colonies_queue_depth{colony="`+env.colonyName+`"`)
+	assert.Contains(t, metrics, `colonies_rpc_request_duration_seconds_count{payloadtype="`+rpc.AssignProcessPayloadType+`"} 2`)
+	assert.Contains(t, metrics, `colonies_assign_latency_seconds_count{colony="`+env.colonyName+`",executortype="`+funcSpec.Conditions.ExecutorType+`"} 2`)
+	assert.Contains(t, metrics, `colonies_process_wait_time_seconds_count{colony="`+env.colonyName+`",function="test_func"} 2`)
+	assert.Contains(t, metrics, `colonies_process_exec_time_seconds_count{colony="`+env.colonyName+`",function="test_func",state="successful"} 1`)
+	assert.Contains(t, metrics, `colonies_process_exec_time_seconds_count{colony="`+env.colonyName+`",function="test_func",state="failed"} 1`)
+	assert.Contains(t, metrics, `colonies_db_query_duration_seconds_count{operation="assign"} 2`)
+	assert.Contains(t, metrics, `colonies_commands_total{colony="`+env.colonyName+`",queue="concurrent"}`)
+	assert.Contains(t, metrics, `colonies_commands_total{colony="`+env.colonyName+`",queue="serial"} 2`)
+	assert.Contains(t, metrics, `colonies_command_queue_wait_seconds_count{colony="`+env.colonyName+`",queue="serial"} 2`)
+	assert.Contains(t, metrics, `colonies_command_queue_depth{colony="`+env.colonyName+`",queue="serial"} 0`)
+	assert.Contains(t, metrics, `colonies_commands_running{colony="`+env.colonyName+`",queue="serial"} 0`)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestMetricsUnknownPayloadType(t *testing.T) {
+	_, client, server, _, done := setupTestEnv2(t)
+
+	_, err := client.SendRawMessage(`{"payloadtype":"invalid_payload_type","payload":"","signature":"invalid_signature"}`, Insecure)
+	assert.Nil(t, err) // The error is returned in the reply
+
+	metrics := scrapeMetrics(t, server)
+	assert.NotContains(t, metrics, "invalid_payload_type")
+	assert.Contains(t, metrics, `colonies_rpc_requests_total{code="4xx",payloadtype="invalid_signature"} 1`)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestHTTPStatusCode(t *testing.T) {
+	assert.Equal(t, "2xx", httpStatusCode(http.StatusOK))
+	assert.Equal(t, "3xx", httpStatusCode(http.StatusFound))
+	assert.Equal(t, "4xx", httpStatusCode(http.StatusForbidden))
+	assert.Equal(t, "5xx", httpStatusCode(http.StatusInternalServerError))
+}
+
+func TestServerMetricsNil(t *testing.T) {
+	var metrics *serverMetrics
+	metrics.observeDBQuery("assign", time.Now())
+	metrics.observeRPC(rpc.AssignProcessPayloadType, http.StatusOK, time.Now())
+}
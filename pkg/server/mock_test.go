@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/colonyos/colonies/pkg/cluster"
 	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/p2p/dht"
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -53,19 +56,35 @@ func (v *controllerMock) getEtcdServer() *cluster.EtcdServer {
 	return nil
 }
 
+func (v *controllerMock) getRelayServer() *cluster.RelayServer {
+	return nil
+}
+
 func (v *controllerMock) getEventHandler() *eventHandler {
 	return nil
 }
 
+func (v *controllerMock) getMetrics() *serverMetrics {
+	return nil
+}
+
 func (v *controllerMock) getThisNode() cluster.Node {
 	return cluster.Node{}
 }
 
-func (v *controllerMock) subscribeProcesses(executorID string, subscription *subscription) error {
+func (v *controllerMock) subscribeProcesses(executorID string, colonyName string, subscription *subscription) error {
+	return nil
+}
+
+func (v *controllerMock) subscribeProcess(executorID string, colonyName string, subscription *subscription) error {
+	return nil
+}
+
+func (v *controllerMock) subscribeEvents(executorID string, subscription *subscription) error {
 	return nil
 }
 
-func (v *controllerMock) subscribeProcess(executorID string, subscription *subscription) error {
+func (v *controllerMock) subscribeLogs(executorID string, subscription *subscription) error {
 	return nil
 }
 
@@ -105,11 +124,11 @@ func (v *controllerMock) addProcessToDB(process *core.Process) (*core.Process, e
 	return nil, nil
 }
 
-func (v *controllerMock) addProcess(process *core.Process) (*core.Process, error) {
+func (v *controllerMock) addProcess(process *core.Process, ctx context.Context) (*core.Process, error) {
 	return nil, nil
 }
 
-func (v *controllerMock) addChild(processGraphID string, parentProcessID string, childProcessID string, process *core.Process, executorID string, insert bool) (*core.Process, error) {
+func (v *controllerMock) addChild(processGraphID string, parentProcessID string, childProcessID string, process *core.Process, executorID string, insert bool, ctx context.Context) (*core.Process, error) {
 	return nil, nil
 }
 
@@ -125,11 +144,11 @@ func (v *controllerMock) updateProcessGraph(graph *core.ProcessGraph) error {
 	return nil
 }
 
-func (v *controllerMock) createProcessGraph(workflowSpec *core.WorkflowSpec, args []interface{}, kwargs map[string]interface{}, rootInput []interface{}, recoveredID string) (*core.ProcessGraph, error) {
+func (v *controllerMock) createProcessGraph(workflowSpec *core.WorkflowSpec, args []interface{}, kwargs map[string]interface{}, rootInput []interface{}, recoveredID string, ctx context.Context) (*core.ProcessGraph, error) {
 	return nil, nil
 }
 
-func (v *controllerMock) submitWorkflowSpec(workflowSpec *core.WorkflowSpec, recoveredID string) (*core.ProcessGraph, error) {
+func (v *controllerMock) submitWorkflowSpec(workflowSpec *core.WorkflowSpec, recoveredID string, ctx context.Context) (*core.ProcessGraph, error) {
 	return nil, nil
 }
 
@@ -153,7 +172,7 @@ func (v *controllerMock) findFailedProcessGraphs(colonyName string, count int) (
 	return nil, nil
 }
 
-func (v *controllerMock) removeProcess(processID string) error {
+func (v *controllerMock) removeProcess(processID string, colonyName string) error {
 	return nil
 }
 
@@ -161,7 +180,7 @@ func (v *controllerMock) removeAllProcesses(colonyName string, state int) error
 	return nil
 }
 
-func (v *controllerMock) removeProcessGraph(processID string) error {
+func (v *controllerMock) removeProcessGraph(processID string, colonyName string) error {
 	return nil
 }
 
@@ -169,11 +188,11 @@ func (v *controllerMock) removeAllProcessGraphs(colonyName string, state int) er
 	return nil
 }
 
-func (v *controllerMock) setOutput(processID string, output []interface{}) error {
+func (v *controllerMock) setOutput(processID string, colonyName string, output []interface{}) error {
 	return nil
 }
 
-func (v *controllerMock) closeSuccessful(processID string, executorID string, output []interface{}) error {
+func (v *controllerMock) closeSuccessful(processID string, colonyName string, executorID string, output []interface{}, ctx context.Context) error {
 	return nil
 }
 
@@ -181,7 +200,7 @@ func (v *controllerMock) notifyChildren(process *core.Process) error {
 	return nil
 }
 
-func (v *controllerMock) closeFailed(processID string, errs []string) error {
+func (v *controllerMock) closeFailed(processID string, colonyName string, errs []string, ctx context.Context) error {
 	return nil
 }
 
@@ -189,7 +208,7 @@ func (v *controllerMock) handleDefunctProcessgraph(processGraphID string, proces
 	return nil
 }
 
-func (v *controllerMock) assign(executorID string, colonyName string, cpu int64, memory int64) (*AssignResult, error) {
+func (v *controllerMock) assign(executorID string, colonyName string, cpu int64, memory int64, ctx context.Context) (*AssignResult, error) {
 	result := &AssignResult{
 		Process:       nil,
 		IsPaused:      false,
@@ -198,11 +217,11 @@ func (v *controllerMock) assign(executorID string, colonyName string, cpu int64,
 	return result, nil
 }
 
-func (v *controllerMock) unassignExecutor(processID string) error {
+func (v *controllerMock) unassignExecutor(processID string, colonyName string) error {
 	return nil
 }
 
-func (v *controllerMock) resetProcess(processID string) error {
+func (v *controllerMock) resetProcess(processID string, colonyName string) error {
 	return nil
 }
 
@@ -238,7 +257,7 @@ func (v *controllerMock) getFunctionByID(functionID string) (*core.Function, err
 	return nil, nil
 }
 
-func (v *controllerMock) removeFunction(functionID string) error {
+func (v *controllerMock) removeFunction(functionID string, colonyName string) error {
 	return nil
 }
 
@@ -287,7 +306,7 @@ func (v *controllerMock) addCron(cron *core.Cron) (*core.Cron, error) {
 	return nil, nil
 }
 
-func (v *controllerMock) removeGenerator(generatorID string) error {
+func (v *controllerMock) removeGenerator(generatorID string, colonyName string) error {
 	return nil
 }
 
@@ -299,11 +318,11 @@ func (v *controllerMock) getCrons(colonyName string, count int) ([]*core.Cron, e
 	return nil, nil
 }
 
-func (v *controllerMock) runCron(cronID string) (*core.Cron, error) {
+func (v *controllerMock) runCron(cronID string, colonyName string) (*core.Cron, error) {
 	return nil, nil
 }
 
-func (v *controllerMock) removeCron(cronID string) error {
+func (v *controllerMock) removeCron(cronID string, colonyName string) error {
 	return nil
 }
 
@@ -327,6 +346,20 @@ func (v *controllerMock) resetDatabase() error {
 func (v *controllerMock) stop() {
 }
 
+func (v *controllerMock) drain() {
+}
+
+func (v *controllerMock) isDraining() bool {
+	return false
+}
+
+func (v *controllerMock) waitForCommands(ctx context.Context) error {
+	return nil
+}
+
+func (v *controllerMock) handOffLeadership() {
+}
+
 func (v *controllerMock) isLeader() bool {
 	return false
 }
@@ -344,6 +377,70 @@ func (v *controllerMock) blockingCmdQueueWorker() {
 func (v *controllerMock) retentionWorker() {
 }
 
+func (v *controllerMock) webhookWorker() {
+}
+
+func (v *controllerMock) setFileStore(fileStore FileStore) {
+}
+
+func (v *controllerMock) setAnomalyFactor(factor float64) {
+}
+
+func (v *controllerMock) statisticsWorker() {
+}
+
+func (v *controllerMock) setStatisticsPeriod(period time.Duration) {
+}
+
+func (v *controllerMock) setColonyWeight(colonyName string, weight int) {
+}
+
+func (v *controllerMock) setColonyDBBudget(budget int) {
+}
+
+func (v *controllerMock) discoveryWorker() {
+}
+
+func (v *controllerMock) setDiscovery(d dht.DHT, serverPrvKey string, endpoint *core.ServerEndpoint) {
+}
+
+func (v *controllerMock) publishEndpoints(now time.Time) error {
+	return nil
+}
+
+func (v *controllerMock) setFederation(serverPrvKey string, skipTLSVerify bool) {
+}
+
+func (v *controllerMock) federationWorker() {
+}
+
+func (v *controllerMock) forwardProcesses() {
+}
+
+func (v *controllerMock) syncFederatedProcesses() {
+}
+
+func (v *controllerMock) alertWorker() {
+}
+
+func (v *controllerMock) evaluateAlertRules(now time.Time) {
+}
+
+func (v *controllerMock) isJobLeader(job string) bool {
+	return false
+}
+
+func (v *controllerMock) clusterMembershipWorker() {
+}
+
+func (v *controllerMock) syncClusterMembership() bool {
+	return true
+}
+
+func (v *controllerMock) probeCmdQueue(timeout time.Duration) (time.Duration, error) {
+	return 0, nil
+}
+
 func (v *controllerMock) cmdQueueWorker() {
 }
 
@@ -395,6 +492,10 @@ type dbMock struct {
 func (db *dbMock) Close() {
 }
 
+func (db *dbMock) Ping(ctx context.Context) error {
+	return nil
+}
+
 func (db *dbMock) Initialize() error {
 	return nil
 }
@@ -403,6 +504,22 @@ func (db *dbMock) Drop() error {
 	return nil
 }
 
+func (db *dbMock) SchemaVersion() (int, error) {
+	return 1, nil
+}
+
+func (db *dbMock) LatestSchemaVersion() int {
+	return 1
+}
+
+func (db *dbMock) Migrations() []database.Migration {
+	return nil
+}
+
+func (db *dbMock) Migrate() error {
+	return nil
+}
+
 func (db *dbMock) AddUser(user *core.User) error {
 	return nil
 }
@@ -653,6 +770,10 @@ func (db *dbMock) FindWaitingProcesses(colonyName string, executorType string, l
 	return nil, nil
 }
 
+func (db *dbMock) FindWaitingProcessesAfter(colonyName string, executorType string, priorityTime int64, processID string, count int) ([]*core.Process, error) {
+	return nil, nil
+}
+
 func (db *dbMock) FindRunningProcesses(colonyName string, executorType string, label string, initiator string, count int) ([]*core.Process, error) {
 	return nil, nil
 }
@@ -801,6 +922,10 @@ func (db *dbMock) CountFailedProcessesByColonyName(colonyName string) (int, erro
 	return -1, nil
 }
 
+func (db *dbMock) CountWaitingProcessesByExecutorType() ([]database.QueueDepth, error) {
+	return nil, nil
+}
+
 func (db *dbMock) AddAttribute(attribute core.Attribute) error {
 	return nil
 }
@@ -1062,10 +1187,218 @@ func (db *dbMock) ApplyRetentionPolicy(retentionPeriod int64) error {
 	return nil
 }
 
+func (db *dbMock) SetRetentionPolicy(policy *core.RetentionPolicy) error {
+	return nil
+}
+
+func (db *dbMock) GetRetentionPolicy(colonyName string) (*core.RetentionPolicy, error) {
+	return nil, nil
+}
+
+func (db *dbMock) GetRetentionPolicies() ([]*core.RetentionPolicy, error) {
+	return nil, nil
+}
+
+func (db *dbMock) RemoveRetentionPolicy(colonyName string) error {
+	return nil
+}
+
+func (db *dbMock) AddWebhook(webhook *core.Webhook) error {
+	return nil
+}
+
+func (db *dbMock) GetWebhookByID(webhookID string) (*core.Webhook, error) {
+	return nil, nil
+}
+
+func (db *dbMock) GetWebhooksByColonyName(colonyName string) ([]*core.Webhook, error) {
+	return nil, nil
+}
+
+func (db *dbMock) RemoveWebhookByID(webhookID string) error {
+	return nil
+}
+
+func (db *dbMock) RemoveWebhooksByColonyName(colonyName string) error {
+	return nil
+}
+
+func (db *dbMock) AddWebhookDelivery(delivery *core.WebhookDelivery) error {
+	return nil
+}
+
+func (db *dbMock) GetWebhookDeliveries(webhookID string, count int) ([]*core.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (db *dbMock) RemoveExpiredWebhookDeliveries(before time.Time) error {
+	return nil
+}
+
+func (db *dbMock) RemoveExcessWebhookDeliveries(count int) error {
+	return nil
+}
+
+func (db *dbMock) AddFunctionRun(run *core.FunctionRun) error {
+	return nil
+}
+
+func (db *dbMock) GetFunctionRuns(colonyName string, funcName string, executorType string, since time.Time, count int) ([]*core.FunctionRun, error) {
+	return nil, nil
+}
+
+func (db *dbMock) RemoveFunctionRunsByColonyName(colonyName string) error {
+	return nil
+}
+
+func (db *dbMock) GetFunctionRunsBetween(colonyName string, from time.Time, to time.Time, count int) ([]*core.FunctionRun, error) {
+	return nil, nil
+}
+
+func (db *dbMock) RemoveExpiredFunctionRuns(colonyName string, before time.Time) error {
+	return nil
+}
+
+func (db *dbMock) AddStatisticsSnapshot(snapshot *core.StatisticsSnapshot) error {
+	return nil
+}
+
+func (db *dbMock) GetStatisticsSnapshots(colonyName string, from time.Time, to time.Time, count int) ([]*core.StatisticsSnapshot, error) {
+	return nil, nil
+}
+
+func (db *dbMock) RemoveStatisticsSnapshotsByColonyName(colonyName string) error {
+	return nil
+}
+
+func (db *dbMock) AddAlertRule(rule *core.AlertRule) error {
+	return nil
+}
+
+func (db *dbMock) GetAlertRuleByName(colonyName string, name string) (*core.AlertRule, error) {
+	return nil, nil
+}
+
+func (db *dbMock) GetAlertRulesByColonyName(colonyName string) ([]*core.AlertRule, error) {
+	return nil, nil
+}
+
+func (db *dbMock) UpdateAlertRuleState(rule *core.AlertRule) error {
+	return nil
+}
+
+func (db *dbMock) RemoveAlertRuleByName(colonyName string, name string) error {
+	return nil
+}
+
+func (db *dbMock) RemoveAlertRulesByColonyName(colonyName string) error {
+	return nil
+}
+
+func (db *dbMock) AddFederationPeer(peer *core.FederationPeer) error {
+	return nil
+}
+
+func (db *dbMock) GetFederationPeerByID(peerID string) (*core.FederationPeer, error) {
+	return nil, nil
+}
+
+func (db *dbMock) GetFederationPeerByName(colonyName string, name string) (*core.FederationPeer, error) {
+	return nil, nil
+}
+
+func (db *dbMock) GetFederationPeersByColonyName(colonyName string) ([]*core.FederationPeer, error) {
+	return nil, nil
+}
+
+func (db *dbMock) RemoveFederationPeerByName(colonyName string, name string) error {
+	return nil
+}
+
+func (db *dbMock) RemoveFederationPeersByColonyName(colonyName string) error {
+	return nil
+}
+
+func (db *dbMock) AddFederatedProcess(federatedProcess *core.FederatedProcess) error {
+	return nil
+}
+
+func (db *dbMock) GetFederatedProcesses(count int) ([]*core.FederatedProcess, error) {
+	return nil, nil
+}
+
+func (db *dbMock) RemoveFederatedProcess(processID string) error {
+	return nil
+}
+
+func (db *dbMock) FenceJob(job string, token int64, ttl time.Duration) error {
+	return nil
+}
+
+func (db *dbMock) RunFencedJob(job string, token int64, run func() error) error {
+	return run()
+}
+
+func (db *dbMock) RemoveExpiredStatisticsSnapshots(colonyName string, before time.Time) error {
+	return nil
+}
+
+func (db *dbMock) AddEvent(event *core.Event) error {
+	return nil
+}
+
+func (db *dbMock) AddEvents(events []*core.Event) error {
+	return nil
+}
+
+func (db *dbMock) GetEvents(colonyName string, fromSeq int64, count int) ([]*core.Event, error) {
+	return nil, nil
+}
+
+func (db *dbMock) RemoveEventsByColonyName(colonyName string) error {
+	return nil
+}
+
+func (db *dbMock) RemoveExpiredEvents(colonyName string, before time.Time) error {
+	return nil
+}
+
+func (db *dbMock) RemoveAllExpiredEvents(before time.Time) error {
+	return nil
+}
+
+func (db *dbMock) RemoveExcessEvents(count int) error {
+	return nil
+}
+
+func (db *dbMock) FindExpiredProcesses(colonyName string, state int, before time.Time, count int) ([]*core.Process, error) {
+	return nil, nil
+}
+
+func (db *dbMock) FindExpiredProcessGraphs(colonyName string, before time.Time, count int) ([]*core.ProcessGraph, error) {
+	return nil, nil
+}
+
+func (db *dbMock) FindExpiredLogs(colonyName string, before time.Time, count int) ([]*core.Log, error) {
+	return nil, nil
+}
+
+func (db *dbMock) RemoveExpiredLogs(colonyName string, before time.Time, count int) error {
+	return nil
+}
+
+func (db *dbMock) FindExpiredFiles(colonyName string, before time.Time, count int) ([]*core.File, error) {
+	return nil, nil
+}
+
 func (db *dbMock) AddLog(processID string, colonyName string, executorName string, timestamp int64, msg string) error {
 	return nil
 }
 
+func (db *dbMock) AddStructuredLog(log *core.Log) error {
+	return nil
+}
+
 func (db *dbMock) GetLogsByProcessID(processID string, limit int) ([]*core.Log, error) {
 	return []*core.Log{}, nil
 }
@@ -1094,6 +1427,10 @@ func (db *dbMock) SearchLogs(colonyName string, text string, days int, count int
 	return nil, nil
 }
 
+func (db *dbMock) SearchStructuredLogs(colonyName string, text string, level string, fields map[string]string, days int, count int) ([]*core.Log, error) {
+	return nil, nil
+}
+
 func (db *dbMock) AddFile(file *core.File) error {
 	return nil
 }
@@ -1110,6 +1447,10 @@ func (db *dbMock) GetFileByName(colonyName string, label string, name string) ([
 	return nil, nil
 }
 
+func (db *dbMock) GetFileByNameAsOf(colonyName string, label string, name string, asOf time.Time) ([]*core.File, error) {
+	return nil, nil
+}
+
 func (db *dbMock) GetFilenamesByLabel(colonyName string, label string) ([]string, error) {
 	return nil, nil
 }
@@ -1118,6 +1459,10 @@ func (db *dbMock) GetFileDataByLabel(colonyName string, label string) ([]*core.F
 	return nil, nil
 }
 
+func (db *dbMock) GetFileDataByLabelAsOf(colonyName string, label string, asOf time.Time) ([]*core.FileData, error) {
+	return nil, nil
+}
+
 func (db *dbMock) RemoveFileByID(colonyName string, fileID string) error {
 	return nil
 }
@@ -1138,6 +1483,10 @@ func (db *dbMock) CountFiles(colonyName string) (int, error) {
 	return 0, nil
 }
 
+func (db *dbMock) GetStorageUsageByLabel(colonyName string, before time.Time) (map[string]int64, error) {
+	return nil, nil
+}
+
 func (db *dbMock) CountFilesWithLabel(colonyName string, label string) (int, error) {
 	return 0, nil
 }
@@ -66,7 +66,7 @@ func (server *ColoniesServer) handleSubmitHTTPRequest(c *gin.Context, recoveredI
 	process.InitiatorID = recoveredID
 	process.InitiatorName = initiator.Name
 
-	addedProcess, err := server.controller.addProcess(process)
+	addedProcess, err := server.controller.addProcess(process, c.Request.Context())
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		return
 	}
@@ -110,6 +110,7 @@ func (server *ColoniesServer) handleSubmitHTTPRequest(c *gin.Context, recoveredI
 //   - jsonString: Request body containing assignment parameters
 //   - originalRequest: Raw request for leader redirection in cluster mode
 func (server *ColoniesServer) handleAssignProcessHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string, originalRequest string) {
+	start := time.Now()
 	var err error
 	if server.exclusiveAssign && !server.controller.isLeader() {
 		// Find out who is the leader
@@ -153,6 +154,12 @@ func (server *ColoniesServer) handleAssignProcessHTTPRequest(c *gin.Context, rec
 		return
 	}
 
+	// A draining server does not accept new long-polls, the executor should wait on another server
+	if msg.Timeout > 0 && server.IsDraining() {
+		server.handleHTTPError(c, server.drainError(), http.StatusServiceUnavailable)
+		return
+	}
+
 	executor, err := server.controller.getExecutor(recoveredID)
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		return
@@ -201,10 +208,12 @@ func (server *ColoniesServer) handleAssignProcessHTTPRequest(c *gin.Context, rec
 	if msg.Timeout > 0 {
 		ctx, cancelCtx = context.WithTimeout(c.Request.Context(), time.Duration(msg.Timeout)*time.Second)
 		defer cancelCtx()
+		stopCancelOnDrain := server.cancelOnDrain(cancelCtx)
+		defer stopCancelOnDrain()
 	}
 
 	for {
-		result, assignErr := server.controller.assign(recoveredID, msg.ColonyName, cpu, memory)
+		result, assignErr := server.controller.assign(recoveredID, msg.ColonyName, cpu, memory, c.Request.Context())
 		if assignErr != nil {
 			server.handleHTTPError(c, assignErr, http.StatusInternalServerError)
 			return
@@ -219,6 +228,10 @@ func (server *ColoniesServer) handleAssignProcessHTTPRequest(c *gin.Context, rec
 					// Assignments resumed, continue to next retry
 					continue
 				case <-ctx.Done():
+					if server.IsDraining() {
+						server.handleHTTPError(c, server.drainError(), http.StatusServiceUnavailable)
+						return
+					}
 					// Timeout
 					server.handleHTTPError(c, errors.New("Assignment timeout: colony assignments are paused"), http.StatusRequestTimeout)
 					return
@@ -255,6 +268,13 @@ func (server *ColoniesServer) handleAssignProcessHTTPRequest(c *gin.Context, rec
 		break
 	}
 
+	// A draining server ends waiting assigns, the executor should wait on another server
+	if process == nil && msg.Timeout > 0 && server.IsDraining() {
+		log.WithFields(log.Fields{"ExecutorId": recoveredID, "ColonyName": msg.ColonyName}).Debug("Assign ended, server is draining")
+		server.handleHTTPError(c, server.drainError(), http.StatusServiceUnavailable)
+		return
+	}
+
 	// Check if we still don't have a process after all attempts
 	if process == nil {
 		log.WithFields(log.Fields{"ExecutorId": recoveredID, "ColonyName": msg.ColonyName}).Debug("No process can be assigned")
@@ -267,6 +287,8 @@ func (server *ColoniesServer) handleAssignProcessHTTPRequest(c *gin.Context, rec
 		return
 	}
 
+	server.metrics.observeAssign(process, start)
+
 	log.WithFields(log.Fields{"ProcessId": process.ID, "ExecutorId": process.AssignedExecutorID}).Debug("Assigning process")
 
 	server.sendHTTPReply(c, payloadType, jsonString)
@@ -476,7 +498,7 @@ func (server *ColoniesServer) handleRemoveProcessHTTPRequest(c *gin.Context, rec
 		return
 	}
 
-	err = server.controller.removeProcess(msg.ProcessID)
+	err = server.controller.removeProcess(msg.ProcessID, process.FunctionSpec.Conditions.ColonyName)
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		return
 	}
@@ -560,7 +582,7 @@ func (server *ColoniesServer) handleSetOutputHTTPRequest(c *gin.Context, recover
 		return
 	}
 
-	err = server.controller.setOutput(process.ID, msg.Output)
+	err = server.controller.setOutput(process.ID, process.FunctionSpec.Conditions.ColonyName, msg.Output)
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		log.WithFields(log.Fields{"Error": err}).Debug("Failed to set output")
 		server.handleHTTPError(c, err, http.StatusInternalServerError)
@@ -618,7 +640,7 @@ func (server *ColoniesServer) handleCloseSuccessfulHTTPRequest(c *gin.Context, r
 		return
 	}
 
-	err = server.controller.closeSuccessful(process.ID, recoveredID, msg.Output)
+	err = server.controller.closeSuccessful(process.ID, process.FunctionSpec.Conditions.ColonyName, recoveredID, msg.Output, c.Request.Context())
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		log.WithFields(log.Fields{"Error": err}).Debug("Failed to close process as successful")
 		server.handleHTTPError(c, err, http.StatusInternalServerError)
@@ -675,7 +697,7 @@ func (server *ColoniesServer) handleCloseFailedHTTPRequest(c *gin.Context, recov
 		return
 	}
 
-	err = server.controller.closeFailed(process.ID, msg.Errors)
+	err = server.controller.closeFailed(process.ID, process.FunctionSpec.Conditions.ColonyName, msg.Errors, c.Request.Context())
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		log.WithFields(log.Fields{"Error": err}).Debug("Failed to close process as failed")
 		server.handleHTTPError(c, err, http.StatusInternalServerError)
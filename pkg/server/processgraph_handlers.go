@@ -33,7 +33,7 @@ func (server *ColoniesServer) handleSubmitWorkflowHTTPRequest(c *gin.Context, re
 		return
 	}
 
-	processGraph, err := server.controller.submitWorkflowSpec(msg.WorkflowSpec, recoveredID)
+	processGraph, err := server.controller.submitWorkflowSpec(msg.WorkflowSpec, recoveredID, c.Request.Context())
 	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
 		return
 	}
@@ -178,7 +178,7 @@ func (server *ColoniesServer) handleRemoveProcessGraphHTTPRequest(c *gin.Context
 		return
 	}
 
-	err = server.controller.removeProcessGraph(msg.ProcessGraphID)
+	err = server.controller.removeProcessGraph(msg.ProcessGraphID, graph.ColonyName)
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		return
 	}
@@ -239,7 +239,7 @@ func (server *ColoniesServer) handleAddChildHTTPRequest(c *gin.Context, recovere
 	}
 
 	process := core.CreateProcess(msg.FunctionSpec)
-	addedProcess, err := server.controller.addChild(msg.ProcessGraphID, msg.ParentProcessID, msg.ChildProcessID, process, recoveredID, msg.Insert)
+	addedProcess, err := server.controller.addChild(msg.ProcessGraphID, msg.ParentProcessID, msg.ChildProcessID, process, recoveredID, msg.Insert, c.Request.Context())
 	if server.handleHTTPError(c, err, http.StatusBadRequest) {
 		return
 	}
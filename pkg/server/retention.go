@@ -0,0 +1,292 @@
+package server
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+)
+
+const retentionBatchSize = 1000
+const archiveLabel = "/archive"
+
+// FileStore is used by the retention worker to archive expired rows as compressed files in ColoniesFS and
+// to remove the objects of expired file revisions, see fs.RetentionStore
+type FileStore interface {
+	Archive(colonyName string, label string, name string, data []byte) error
+	Remove(file *core.File) error
+}
+
+func (controller *coloniesController) setFileStore(fileStore FileStore) {
+	controller.fileStoreMutex.Lock()
+	defer controller.fileStoreMutex.Unlock()
+
+	controller.fileStore = fileStore
+}
+
+func (controller *coloniesController) getFileStore() FileStore {
+	controller.fileStoreMutex.Lock()
+	defer controller.fileStoreMutex.Unlock()
+
+	return controller.fileStore
+}
+
+// applyRetentionPolicies applies the server wide retention period if retention is enabled, and the retention policies
+// of the colonies, which are applied even if server wide retention is disabled. The event log is always capped, as
+// every state transition adds an event to it.
+func (controller *coloniesController) applyRetentionPolicies() {
+	controller.removeOldEvents(time.Now())
+
+	if controller.retention {
+		err := controller.db.ApplyRetentionPolicy(controller.retentionPolicy)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err}).Error("Failed to apply retention policy")
+		}
+	}
+
+	policies, err := controller.db.GetRetentionPolicies()
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to get retention policies")
+		return
+	}
+
+	for _, policy := range policies {
+		err := controller.applyColonyRetentionPolicy(policy, time.Now())
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ColonyName": policy.ColonyName}).Error("Failed to apply colony retention policy")
+		}
+	}
+}
+
+// removeOldEvents removes events older than EVENT_TTL, and all but the latest MAX_EVENTS events of every colony
+func (controller *coloniesController) removeOldEvents(now time.Time) {
+	err := controller.db.RemoveAllExpiredEvents(now.Add(-EVENT_TTL))
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to remove expired events")
+	}
+
+	err = controller.db.RemoveExcessEvents(MAX_EVENTS)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to remove excess events")
+	}
+}
+
+func expiryTime(now time.Time, retentionPeriod int64) time.Time {
+	return now.Add(-time.Duration(retentionPeriod) * time.Second)
+}
+
+func (controller *coloniesController) applyColonyRetentionPolicy(policy *core.RetentionPolicy, now time.Time) error {
+	fileStore := controller.getFileStore()
+	if policy.Archive && fileStore == nil {
+		// Removing rows that are supposed to be archived would lose them for good, better to keep them for now
+		log.WithFields(log.Fields{"ColonyName": policy.ColonyName}).Warn("Retention policy requires archiving, but no file store is configured, skipping colony")
+		return nil
+	}
+
+	if policy.SuccessfulProcesses > 0 {
+		err := controller.removeExpiredProcesses(policy, core.SUCCESS, "successful", expiryTime(now, policy.SuccessfulProcesses), fileStore)
+		if err != nil {
+			return err
+		}
+	}
+
+	if policy.FailedProcesses > 0 {
+		err := controller.removeExpiredProcesses(policy, core.FAILED, "failed", expiryTime(now, policy.FailedProcesses), fileStore)
+		if err != nil {
+			return err
+		}
+	}
+
+	if policy.ProcessGraphs > 0 {
+		err := controller.removeExpiredProcessGraphs(policy, expiryTime(now, policy.ProcessGraphs), fileStore)
+		if err != nil {
+			return err
+		}
+	}
+
+	if policy.Logs > 0 {
+		err := controller.removeExpiredLogs(policy, expiryTime(now, policy.Logs), fileStore)
+		if err != nil {
+			return err
+		}
+
+		// Events are kept as long as logs so that subscribers can resume from any logged state transition
+		err = controller.db.RemoveExpiredEvents(policy.ColonyName, expiryTime(now, policy.Logs))
+		if err != nil {
+			return err
+		}
+
+		err = controller.db.RemoveExpiredFunctionRuns(policy.ColonyName, expiryTime(now, policy.Logs))
+		if err != nil {
+			return err
+		}
+
+		err = controller.db.RemoveExpiredStatisticsSnapshots(policy.ColonyName, expiryTime(now, policy.Logs))
+		if err != nil {
+			return err
+		}
+	}
+
+	if policy.Files > 0 {
+		if fileStore == nil {
+			log.WithFields(log.Fields{"ColonyName": policy.ColonyName}).Debug("No file store is configured, cannot remove expired file revisions")
+			return nil
+		}
+
+		err := controller.removeExpiredFiles(policy, expiryTime(now, policy.Files), fileStore)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (controller *coloniesController) archive(fileStore FileStore, colonyName string, objectType string, rows interface{}, count int) error {
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+
+	name := objectType + "-" + time.Now().UTC().Format("20060102T150405.000000000") + ".json.gz"
+	label := archiveLabel + "/" + objectType
+
+	err = fileStore.Archive(colonyName, label, name, data)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{"ColonyName": colonyName, "Label": label, "Name": name, "Count": count}).Debug("Archived expired rows")
+
+	return nil
+}
+
+func (controller *coloniesController) removeExpiredProcesses(policy *core.RetentionPolicy, state int, stateStr string, before time.Time, fileStore FileStore) error {
+	for {
+		processes, err := controller.db.FindExpiredProcesses(policy.ColonyName, state, before, retentionBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(processes) == 0 {
+			return nil
+		}
+
+		if policy.Archive {
+			err = controller.archive(fileStore, policy.ColonyName, "processes/"+stateStr, processes, len(processes))
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, process := range processes {
+			err = controller.db.RemoveProcessByID(process.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		log.WithFields(log.Fields{"ColonyName": policy.ColonyName, "State": stateStr, "Count": len(processes)}).Debug("Removed expired processes")
+
+		if len(processes) < retentionBatchSize {
+			return nil
+		}
+	}
+}
+
+func (controller *coloniesController) removeExpiredProcessGraphs(policy *core.RetentionPolicy, before time.Time, fileStore FileStore) error {
+	for {
+		graphs, err := controller.db.FindExpiredProcessGraphs(policy.ColonyName, before, retentionBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(graphs) == 0 {
+			return nil
+		}
+
+		if policy.Archive {
+			err = controller.archive(fileStore, policy.ColonyName, "processgraphs", graphs, len(graphs))
+			if err != nil {
+				return err
+			}
+		}
+
+		// Note that the processes of the graph are removed as well
+		for _, graph := range graphs {
+			err = controller.db.RemoveProcessGraphByID(graph.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		log.WithFields(log.Fields{"ColonyName": policy.ColonyName, "Count": len(graphs)}).Debug("Removed expired process graphs")
+
+		if len(graphs) < retentionBatchSize {
+			return nil
+		}
+	}
+}
+
+func (controller *coloniesController) removeExpiredLogs(policy *core.RetentionPolicy, before time.Time, fileStore FileStore) error {
+	if !policy.Archive {
+		return controller.db.RemoveExpiredLogs(policy.ColonyName, before, 0)
+	}
+
+	for {
+		logs, err := controller.db.FindExpiredLogs(policy.ColonyName, before, retentionBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(logs) == 0 {
+			return nil
+		}
+
+		err = controller.archive(fileStore, policy.ColonyName, "logs", logs, len(logs))
+		if err != nil {
+			return err
+		}
+
+		err = controller.db.RemoveExpiredLogs(policy.ColonyName, before, retentionBatchSize)
+		if err != nil {
+			return err
+		}
+
+		log.WithFields(log.Fields{"ColonyName": policy.ColonyName, "Count": len(logs)}).Debug("Removed expired logs")
+
+		if len(logs) < retentionBatchSize {
+			return nil
+		}
+	}
+}
+
+func (controller *coloniesController) removeExpiredFiles(policy *core.RetentionPolicy, before time.Time, fileStore FileStore) error {
+	for {
+		files, err := controller.db.FindExpiredFiles(policy.ColonyName, before, retentionBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			err = fileStore.Remove(file)
+			if err != nil {
+				return err
+			}
+
+			err = controller.db.RemoveFileByID(policy.ColonyName, file.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(files) > 0 {
+			log.WithFields(log.Fields{"ColonyName": policy.ColonyName, "Count": len(files)}).Debug("Removed expired file revisions")
+		}
+
+		if len(files) < retentionBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+func (server *ColoniesServer) handleSetRetentionPolicyHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateSetRetentionPolicyMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to set retention policy, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to set retention policy, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.RetentionPolicy == nil {
+		server.handleHTTPError(c, errors.New("Failed to set retention policy, retention policy is <nil>"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireColonyOwner(recoveredID, msg.RetentionPolicy.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	policy := msg.RetentionPolicy
+	if policy.SuccessfulProcesses < 0 || policy.FailedProcesses < 0 || policy.ProcessGraphs < 0 || policy.Logs < 0 || policy.Files < 0 {
+		server.handleHTTPError(c, errors.New("Failed to set retention policy, retention periods cannot be negative"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.db.SetRetentionPolicy(policy)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	jsonString, err = policy.ToJSON()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": policy.ColonyName,
+		"SuccessfulProcesses": policy.SuccessfulProcesses,
+		"FailedProcesses":     policy.FailedProcesses,
+		"ProcessGraphs":       policy.ProcessGraphs,
+		"Logs":                policy.Logs,
+		"Files":               policy.Files,
+		"Archive":             policy.Archive}).
+		Debug("Setting retention policy")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+func (server *ColoniesServer) handleGetRetentionPolicyHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetRetentionPolicyMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to get retention policy, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to get retention policy, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireColonyOwner(recoveredID, msg.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	policy, err := server.db.GetRetentionPolicy(msg.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if policy == nil {
+		server.handleHTTPError(c, errors.New("Failed to get retention policy, colony <"+msg.ColonyName+"> has no retention policy"), http.StatusNotFound)
+		return
+	}
+
+	jsonString, err = policy.ToJSON()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName}).Debug("Getting retention policy")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+func (server *ColoniesServer) handleRemoveRetentionPolicyHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRemoveRetentionPolicyMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to remove retention policy, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to remove retention policy, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireColonyOwner(recoveredID, msg.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	err = server.db.RemoveRetentionPolicy(msg.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName}).Debug("Removing retention policy")
+
+	server.sendEmptyHTTPReply(c, payloadType)
+}
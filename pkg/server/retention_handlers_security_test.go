@@ -0,0 +1,79 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetRetentionPolicySecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	policy := core.CreateRetentionPolicy(env.colony1Name)
+	policy.FailedProcesses = 3600
+
+	_, err := client.SetRetentionPolicy(policy, env.executor1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.SetRetentionPolicy(policy, env.colony2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.SetRetentionPolicy(policy, env.colony1PrvKey)
+	assert.Nil(t, err) // Should work
+
+	server.Shutdown()
+	<-done
+}
+
+func TestGetRetentionPolicySecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	policy := core.CreateRetentionPolicy(env.colony1Name)
+	_, err := client.SetRetentionPolicy(policy, env.colony1PrvKey)
+	assert.Nil(t, err)
+
+	_, err = client.GetRetentionPolicy(env.colony1Name, env.executor2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.GetRetentionPolicy(env.colony1Name, env.colony2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	_, err = client.GetRetentionPolicy(env.colony1Name, env.colony1PrvKey)
+	assert.Nil(t, err) // Should work
+
+	server.Shutdown()
+	<-done
+}
+
+func TestRemoveRetentionPolicySecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	policy := core.CreateRetentionPolicy(env.colony1Name)
+	_, err := client.SetRetentionPolicy(policy, env.colony1PrvKey)
+	assert.Nil(t, err)
+
+	err = client.RemoveRetentionPolicy(env.colony1Name, env.executor1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	err = client.RemoveRetentionPolicy(env.colony1Name, env.colony2PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	err = client.RemoveRetentionPolicy(env.colony1Name, env.colony1PrvKey)
+	assert.Nil(t, err) // Should work
+
+	server.Shutdown()
+	<-done
+}
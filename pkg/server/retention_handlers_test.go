@@ -0,0 +1,40 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetRetentionPolicy(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	_, err := client.GetRetentionPolicy(env.colonyName, env.colonyPrvKey)
+	assert.NotNil(t, err) // No policy has been set
+
+	policy := core.CreateRetentionPolicy(env.colonyName)
+	policy.SuccessfulProcesses = 60
+	policy.FailedProcesses = 3600
+	policy.Archive = true
+	addedPolicy, err := client.SetRetentionPolicy(policy, env.colonyPrvKey)
+	assert.Nil(t, err)
+	assert.True(t, policy.Equals(addedPolicy))
+
+	policyFromServer, err := client.GetRetentionPolicy(env.colonyName, env.colonyPrvKey)
+	assert.Nil(t, err)
+	assert.True(t, policy.Equals(policyFromServer))
+
+	policy.FailedProcesses = -1
+	_, err = client.SetRetentionPolicy(policy, env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	err = client.RemoveRetentionPolicy(env.colonyName, env.colonyPrvKey)
+	assert.Nil(t, err)
+
+	_, err = client.GetRetentionPolicy(env.colonyName, env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
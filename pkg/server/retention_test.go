@@ -2,9 +2,11 @@ package server
 
 import (
 	"io/ioutil"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -52,3 +54,91 @@ func TestRetention(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+type fileStoreMock struct {
+	mutex    sync.Mutex
+	archives map[string][]byte
+}
+
+func (store *fileStoreMock) Archive(colonyName string, label string, name string, data []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.archives[label+"/"+name] = data
+	return nil
+}
+
+func (store *fileStoreMock) Remove(file *core.File) error {
+	return nil
+}
+
+func (store *fileStoreMock) count() int {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return len(store.archives)
+}
+
+func TestColonyRetentionPolicy(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	gin.DefaultWriter = ioutil.Discard
+
+	// Colony retention policies are applied even if server wide retention is disabled
+	client, server, serverPrvKey, done := prepareTestsWithRetention(t, false)
+
+	colony, colonyPrvKey, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	_, err = client.AddColony(colony, serverPrvKey)
+	assert.Nil(t, err)
+
+	executor, executorPrvKey, err := utils.CreateTestExecutorWithKey(colony.Name)
+	_, err = client.AddExecutor(executor, colonyPrvKey)
+	assert.Nil(t, err)
+
+	err = client.ApproveExecutor(colony.Name, executor.Name, colonyPrvKey)
+	assert.Nil(t, err)
+
+	// Keep successful processes forever, but archive and remove failed processes after 1 second
+	policy := core.CreateRetentionPolicy(colony.Name)
+	policy.FailedProcesses = 1
+	policy.Archive = true
+	_, err = client.SetRetentionPolicy(policy, colonyPrvKey)
+	assert.Nil(t, err)
+
+	funcSpec := utils.CreateTestFunctionSpec(colony.Name)
+	_, err = client.Submit(funcSpec, executorPrvKey)
+	assert.Nil(t, err)
+	process, err := client.Assign(colony.Name, -1, "", "", executorPrvKey)
+	assert.Nil(t, err)
+	err = client.Close(process.ID, executorPrvKey)
+	assert.Nil(t, err)
+
+	_, err = client.Submit(funcSpec, executorPrvKey)
+	assert.Nil(t, err)
+	process, err = client.Assign(colony.Name, -1, "", "", executorPrvKey)
+	assert.Nil(t, err)
+	err = client.Fail(process.ID, []string{"error"}, executorPrvKey)
+	assert.Nil(t, err)
+
+	// No file store is configured, failed processes must not be removed without being archived
+	time.Sleep(2 * time.Second)
+
+	stat, err := client.ColonyStatistics(colony.Name, executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, stat.SuccessfulProcesses, 1)
+	assert.Equal(t, stat.FailedProcesses, 1)
+
+	fileStore := &fileStoreMock{archives: make(map[string][]byte)}
+	server.SetFileStore(fileStore)
+
+	time.Sleep(2 * time.Second)
+
+	stat, err = client.ColonyStatistics(colony.Name, executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, stat.SuccessfulProcesses, 1)
+	assert.Equal(t, stat.FailedProcesses, 0)
+	assert.Equal(t, fileStore.count(), 1)
+
+	server.Shutdown()
+	<-done
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/build"
 	"github.com/colonyos/colonies/pkg/rpc"
@@ -102,3 +103,38 @@ func (server *ColoniesServer) handleGetClusterHTTPRequest(c *gin.Context, recove
 
 	server.sendHTTPReply(c, payloadType, jsonString)
 }
+
+func (server *ColoniesServer) handleDrainServerHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateDrainServerMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to drain server, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to drain server, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	serverID, err := server.getServerID()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	err = server.validator.RequireServerOwner(recoveredID, serverID)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	timeout := DEFAULT_DRAIN_TIMEOUT
+	if msg.Timeout > 0 {
+		timeout = time.Duration(msg.Timeout) * time.Second
+	}
+
+	log.WithFields(log.Fields{"Timeout": timeout}).Info("Drain requested")
+
+	// The reply is sent before draining, since draining waits for this request to finish
+	server.sendEmptyHTTPReply(c, payloadType)
+	go server.Drain(timeout)
+}
@@ -57,3 +57,25 @@ func TestGetClusterInfoSecurity(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+func TestDrainServerSecurity(t *testing.T) {
+	env, client, server, serverPrvKey, done := setupTestEnv1(t)
+
+	// The setup looks like this:
+	//   executor1 is member of colony1
+	//   executor2 is member of colony2
+
+	err := client.DrainServer(10, env.executor1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	err = client.DrainServer(10, env.colony1PrvKey)
+	assert.NotNil(t, err) // Should not work
+
+	assert.False(t, server.IsDraining())
+
+	err = client.DrainServer(10, serverPrvKey)
+	assert.Nil(t, err) // Should work
+
+	<-server.Drained()
+	<-done
+}
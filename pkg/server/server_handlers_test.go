@@ -2,7 +2,10 @@ package server
 
 import (
 	"testing"
+	"time"
 
+	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -100,6 +103,51 @@ func TestCheckHealth(t *testing.T) {
 	<-done
 }
 
+func TestLivenessAndReadiness(t *testing.T) {
+	_, client, server, _, done := setupTestEnv2(t)
+
+	report, err := client.Liveness()
+	assert.Nil(t, err)
+	assert.True(t, report.IsOK())
+	assert.Len(t, report.Checks, 1)
+	assert.Equal(t, "cmdqueue", report.Checks[0].Name)
+
+	report, err = client.Readiness()
+	assert.Nil(t, err)
+	assert.True(t, report.IsOK())
+	assert.Len(t, report.Checks, 5)
+	checks := make(map[string]*core.HealthCheck)
+	for _, check := range report.Checks {
+		checks[check.Name] = check
+	}
+	assert.Equal(t, core.HealthStatusOK, checks["drain"].Status)
+	assert.Equal(t, core.HealthStatusOK, checks["database"].Status)
+	assert.Equal(t, core.HealthStatusOK, checks["etcd"].Status)
+	assert.Equal(t, report.Node, checks["etcd"].Details["leader"])
+	assert.Equal(t, "true", checks["etcd"].Details["member"])
+	assert.Equal(t, core.HealthStatusOK, checks["relay"].Status)
+	assert.Equal(t, core.HealthStatusOK, checks["cmdqueue"].Status)
+
+	server.db.Close()
+
+	report, err = client.Readiness()
+	assert.Nil(t, err)
+	assert.False(t, report.IsOK())
+	for _, check := range report.Checks {
+		if check.Name == "database" {
+			assert.Equal(t, core.HealthStatusFail, check.Status)
+			assert.NotEmpty(t, check.Message)
+		}
+	}
+
+	report, err = client.Liveness()
+	assert.Nil(t, err)
+	assert.True(t, report.IsOK()) // Liveness does not depend on the database
+
+	server.Shutdown()
+	<-done
+}
+
 // func TestResetDatabase(t *testing.T) {
 // 	_, client, server, serverPrvKey, done := setupTestEnv2(t)
 //
@@ -117,3 +165,33 @@ func TestCheckHealth(t *testing.T) {
 // 	server.Shutdown()
 // 	<-done
 // }
+
+func TestDrainServer(t *testing.T) {
+	env, coloniesClient, server, serverPrvKey, done := setupTestEnv1(t)
+
+	subscription, err := coloniesClient.SubscribeProcesses(env.colony1Name, "test_executor_type", core.WAITING, 100, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	assignErr := make(chan error)
+	go func() {
+		_, err := coloniesClient.Assign(env.colony1Name, 100, "", "", env.executor1PrvKey)
+		assignErr <- err
+	}()
+
+	time.Sleep(1 * time.Second)
+
+	err = coloniesClient.DrainServer(10, serverPrvKey)
+	assert.Nil(t, err)
+
+	// The waiting assign and the subscription are told to reconnect to another server
+	err = <-assignErr
+	assert.NotNil(t, err)
+	assert.True(t, client.IsServerDraining(err))
+
+	err = <-subscription.ErrChan
+	assert.True(t, client.IsServerDraining(err))
+
+	<-server.Drained()
+	assert.True(t, server.IsDraining())
+	<-done
+}
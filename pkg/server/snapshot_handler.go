@@ -43,6 +43,8 @@ func (server *ColoniesServer) handleCreateSnapshotHTTPRequest(c *gin.Context, re
 
 	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "SnapshotID": snapshot.ID}).Debug("Creating snapshot")
 
+	server.publishEvent(core.CreateSnapshotEvent(core.SnapshotCreatedEvent, snapshot))
+
 	server.sendHTTPReply(c, payloadType, jsonStr)
 }
 
@@ -151,13 +153,16 @@ func (server *ColoniesServer) handleRemoveSnapshotHTTPRequest(c *gin.Context, re
 		return
 	}
 
+	var removedSnapshot *core.Snapshot
 	if msg.SnapshotID != "" {
+		removedSnapshot, _ = server.db.GetSnapshotByID(msg.ColonyName, msg.SnapshotID)
 		err = server.db.RemoveSnapshotByID(msg.ColonyName, msg.SnapshotID)
 		if server.handleHTTPError(c, err, http.StatusInternalServerError) {
 			log.Error(err)
 			return
 		}
 	} else if msg.Name != "" {
+		removedSnapshot, _ = server.db.GetSnapshotByName(msg.ColonyName, msg.Name)
 		err = server.db.RemoveSnapshotByName(msg.ColonyName, msg.Name)
 		if server.handleHTTPError(c, err, http.StatusInternalServerError) {
 			log.Error(err)
@@ -172,6 +177,10 @@ func (server *ColoniesServer) handleRemoveSnapshotHTTPRequest(c *gin.Context, re
 
 	log.WithFields(log.Fields{"SnapshotID": msg.SnapshotID, "ColonyName": msg.ColonyName}).Debug("Removing snapshot")
 
+	if removedSnapshot != nil {
+		server.publishEvent(core.CreateSnapshotEvent(core.SnapshotRemovedEvent, removedSnapshot))
+	}
+
 	server.sendEmptyHTTPReply(c, payloadType)
 }
 
@@ -194,6 +203,7 @@ func (server *ColoniesServer) handleRemoveAllSnapshotsHTTPRequest(c *gin.Context
 		return
 	}
 
+	removedSnapshots, _ := server.db.GetSnapshotsByColonyName(msg.ColonyName)
 	err = server.db.RemoveSnapshotsByColonyName(msg.ColonyName)
 	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
 		log.Error(err)
@@ -202,5 +212,9 @@ func (server *ColoniesServer) handleRemoveAllSnapshotsHTTPRequest(c *gin.Context
 
 	log.WithFields(log.Fields{"ColonyName": msg.ColonyName}).Debug("Removing all snapshots")
 
+	for _, snapshot := range removedSnapshots {
+		server.publishEvent(core.CreateSnapshotEvent(core.SnapshotRemovedEvent, snapshot))
+	}
+
 	server.sendEmptyHTTPReply(c, payloadType)
 }
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// setStatisticsPeriod sets how often colony statistics are stored as snapshots, a period of 0 disables snapshots
+func (controller *coloniesController) setStatisticsPeriod(period time.Duration) {
+	controller.statsPeriodMux.Lock()
+	defer controller.statsPeriodMux.Unlock()
+
+	controller.statsPeriod = period
+}
+
+func (controller *coloniesController) getStatisticsPeriod() time.Duration {
+	controller.statsPeriodMux.Lock()
+	defer controller.statsPeriodMux.Unlock()
+
+	return controller.statsPeriod
+}
+
+// takeStatisticsSnapshots stores the current statistics of every colony. Failing to snapshot a colony is logged and
+// does not prevent the other colonies from being snapshotted.
+func (controller *coloniesController) takeStatisticsSnapshots(now time.Time) {
+	colonies, err := controller.db.GetColonies()
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to get colonies, cannot take statistics snapshots")
+		return
+	}
+
+	for _, colony := range colonies {
+		stat, err := controller.getColonyStatistics(colony.Name)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ColonyName": colony.Name}).Error("Failed to get colony statistics")
+			continue
+		}
+
+		err = controller.db.AddStatisticsSnapshot(core.CreateStatisticsSnapshot(colony.Name, now, stat))
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ColonyName": colony.Name}).Error("Failed to add statistics snapshot")
+		}
+	}
+}
+
+// statisticsWorker periodically snapshots the statistics of all colonies. Only the leader takes snapshots so that
+// each snapshot is stored once. The period is checked every second so that period changes take effect quickly.
+func (controller *coloniesController) statisticsWorker() {
+	var lastSnapshot time.Time
+	for {
+		time.Sleep(time.Second)
+
+		controller.stopMutex.Lock()
+		stopped := controller.stopFlag
+		controller.stopMutex.Unlock()
+		if stopped {
+			return
+		}
+
+		period := controller.getStatisticsPeriod()
+		if period <= 0 || time.Since(lastSnapshot) < period {
+			continue
+		}
+
+		controller.runJob(STATISTICS_JOB, func() {
+			log.Debug("Taking statistics snapshots")
+			lastSnapshot = time.Now()
+			controller.takeStatisticsSnapshots(lastSnapshot)
+		})
+	}
+}
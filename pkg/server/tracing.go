@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// runCommand runs a command popped from a command queue. If the command was issued as part of a trace, e.g. by an RPC
+// handler, it runs in a child span of that trace. Commands issued by background loops are not traced.
+func (controller *coloniesController) runCommand(cmd *command) {
+	if cmd.ctx == nil {
+		cmd.ctx = context.Background()
+	}
+
+	if trace.SpanContextFromContext(cmd.ctx).IsValid() {
+		ctx, span := tracing.Tracer().Start(cmd.ctx, "colonies.controller/"+cmd.name)
+		defer span.End()
+		cmd.ctx = ctx
+	}
+
+	cmd.handler(cmd)
+}
+
+// stampTraceContext stores the trace context in ctx on the process, so that the process, and the executor running
+// it, continues the trace it was submitted in
+func stampTraceContext(process *core.Process, ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+
+	process.TraceParent, process.TraceState = tracing.Inject(ctx)
+}
+
+// traceProcessSpan records a span covering start to end in the trace the process was submitted in, e.g. the time
+// the process waited to be assigned. The span is linked to the span in ctx, which may belong to another trace, e.g.
+// the assign request of an executor.
+func traceProcessSpan(process *core.Process, name string, start time.Time, end time.Time, ctx context.Context) {
+	if process.TraceParent == "" || start.IsZero() {
+		return
+	}
+
+	options := []trace.SpanStartOption{trace.WithTimestamp(start), trace.WithAttributes(tracing.ProcessAttributes(process)...)}
+	if ctx != nil {
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+			options = append(options, trace.WithLinks(trace.Link{SpanContext: spanContext}))
+		}
+	}
+
+	_, span := tracing.Tracer().Start(tracing.ProcessContext(context.Background(), process), name, options...)
+	span.End(trace.WithTimestamp(end))
+}
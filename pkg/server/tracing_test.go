@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/tracing"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// testCollector is an in-process OTLP trace collector
+type testCollector struct {
+	collectortrace.UnimplementedTraceServiceServer
+	mutex sync.Mutex
+	spans map[string][]trace.TraceID // Trace Ids by span name
+}
+
+func (collector *testCollector) Export(ctx context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	for _, resourceSpans := range req.ResourceSpans {
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				var traceID trace.TraceID
+				copy(traceID[:], span.TraceId)
+				collector.spans[span.Name] = append(collector.spans[span.Name], traceID)
+			}
+		}
+	}
+
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+func startTestCollector(t *testing.T) (*testCollector, string, func()) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	assert.Nil(t, err)
+
+	collector := &testCollector{spans: make(map[string][]trace.TraceID)}
+	grpcServer := grpc.NewServer()
+	collectortrace.RegisterTraceServiceServer(grpcServer, collector)
+	go grpcServer.Serve(listener)
+
+	return collector, listener.Addr().String(), grpcServer.Stop
+}
+
+func TestTracing(t *testing.T) {
+	collector, endpoint, stopCollector := startTestCollector(t)
+	defer stopCollector()
+
+	shutdown, err := tracing.Setup("colonies-test", endpoint, true)
+	assert.Nil(t, err)
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	env, client, server, _, done := setupTestEnv2(t)
+
+	ctx, submitSpan := tracing.Tracer().Start(context.Background(), "test/submit")
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	addedProcess, err := client.SubmitWithContext(funcSpec, env.executorPrvKey, ctx)
+	assert.Nil(t, err)
+	submitSpan.End()
+	assert.NotEqual(t, "", addedProcess.TraceParent)
+
+	// The executor continues the trace the process was submitted in
+	assignedProcess, err := client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, addedProcess.TraceParent, assignedProcess.TraceParent)
+
+	execCtx, execSpan := tracing.StartProcessSpan(context.Background(), assignedProcess, "test/exec")
+	err = client.CloseWithContext(assignedProcess.ID, nil, env.executorPrvKey, execCtx)
+	assert.Nil(t, err)
+	execSpan.End()
+
+	server.Shutdown()
+	<-done
+
+	assert.Nil(t, shutdown(context.Background())) // Flush all spans
+
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	traceID := submitSpan.SpanContext().TraceID()
+	for _, name := range []string{
+		"test/submit",
+		"colonies.rpc/" + rpc.SubmitFunctionSpecPayloadType,
+		"colonies.controller/addProcess",
+		"colonies.process/wait",
+		"test/exec",
+		"colonies.rpc/" + rpc.CloseSuccessfulPayloadType,
+		"colonies.controller/closeSuccessful",
+		"colonies.process/exec"} {
+		assert.Contains(t, collector.spans[name], traceID, name)
+	}
+
+	// The assign request itself was not part of a trace
+	assert.Len(t, collector.spans["colonies.rpc/"+rpc.AssignProcessPayloadType], 1)
+	assert.NotContains(t, collector.spans["colonies.rpc/"+rpc.AssignProcessPayloadType], traceID)
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+func (server *ColoniesServer) handleAddWebhookHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateAddWebhookMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to add webhook, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to add webhook, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.Webhook == nil {
+		server.handleHTTPError(c, errors.New("Failed to add webhook, webhook is <nil>"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireColonyOwner(recoveredID, msg.Webhook.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	webhook := msg.Webhook
+	if webhook.Name == "" {
+		server.handleHTTPError(c, errors.New("Failed to add webhook, name is empty"), http.StatusBadRequest)
+		return
+	}
+
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		server.handleHTTPError(c, errors.New("Failed to add webhook, invalid URL <"+webhook.URL+">"), http.StatusBadRequest)
+		return
+	}
+
+	if webhook.Types == nil {
+		webhook.Types = []string{}
+	}
+	webhook.ID = core.GenerateRandomID()
+	webhook.Added = time.Now()
+
+	err = server.db.AddWebhook(webhook)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	webhook.Secret = "" // Secrets are never sent back to clients
+	jsonString, err = webhook.ToJSON()
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"WebhookID": webhook.ID, "ColonyName": webhook.ColonyName, "URL": webhook.URL, "Types": webhook.Types}).Debug("Adding webhook")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+func (server *ColoniesServer) handleGetWebhooksHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetWebhooksMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to get webhooks, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to get webhooks, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = server.validator.RequireColonyOwner(recoveredID, msg.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	webhooks, err := server.db.GetWebhooksByColonyName(msg.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+
+	jsonString, err = core.ConvertWebhookArrayToJSON(webhooks)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName}).Debug("Getting webhooks")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
+
+func (server *ColoniesServer) handleRemoveWebhookHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRemoveWebhookMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to remove webhook, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to remove webhook, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := server.db.GetWebhookByID(msg.WebhookID)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if webhook == nil {
+		server.handleHTTPError(c, errors.New("Failed to remove webhook, webhook with Id <"+msg.WebhookID+"> not found"), http.StatusNotFound)
+		return
+	}
+
+	err = server.validator.RequireColonyOwner(recoveredID, webhook.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	err = server.db.RemoveWebhookByID(webhook.ID)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"WebhookID": webhook.ID, "ColonyName": webhook.ColonyName}).Debug("Removing webhook")
+
+	server.sendEmptyHTTPReply(c, payloadType)
+}
+
+func (server *ColoniesServer) handleGetWebhookDeliveriesHTTPRequest(c *gin.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetWebhookDeliveriesMsgFromJSON(jsonString)
+	if err != nil {
+		if server.handleHTTPError(c, errors.New("Failed to get webhook deliveries, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		server.handleHTTPError(c, errors.New("Failed to get webhook deliveries, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.Count > MAX_COUNT {
+		server.handleHTTPError(c, errors.New("Failed to get webhook deliveries, count is larger than MaxCount limit <"+strconv.Itoa(MAX_COUNT)+">"), http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := server.db.GetWebhookByID(msg.WebhookID)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if webhook == nil {
+		server.handleHTTPError(c, errors.New("Failed to get webhook deliveries, webhook with Id <"+msg.WebhookID+"> not found"), http.StatusNotFound)
+		return
+	}
+
+	err = server.validator.RequireColonyOwner(recoveredID, webhook.ColonyName)
+	if server.handleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	deliveries, err := server.db.GetWebhookDeliveries(webhook.ID, msg.Count)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	jsonString, err = core.ConvertWebhookDeliveryArrayToJSON(deliveries)
+	if server.handleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"WebhookID": webhook.ID, "Count": msg.Count}).Debug("Getting webhook deliveries")
+
+	server.sendHTTPReply(c, payloadType, jsonString)
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddWebhook(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	webhook := core.CreateWebhook(env.colonyName, "test_webhook", "http://localhost/hook", []string{core.ProcessGraphEventKind}, "", "test_secret")
+	addedWebhook, err := client.AddWebhook(webhook, env.colonyPrvKey)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, addedWebhook.ID)
+	assert.Equal(t, webhook.URL, addedWebhook.URL)
+	assert.Empty(t, addedWebhook.Secret) // Secrets are never returned
+
+	webhooks, err := client.GetWebhooks(env.colonyName, env.colonyPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 1)
+	assert.True(t, addedWebhook.Equals(webhooks[0]))
+
+	invalidWebhook := core.CreateWebhook(env.colonyName, "test_webhook", "ftp://localhost/hook", []string{}, "", "")
+	_, err = client.AddWebhook(invalidWebhook, env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	invalidWebhook = core.CreateWebhook(env.colonyName, "", "http://localhost/hook", []string{}, "", "")
+	_, err = client.AddWebhook(invalidWebhook, env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	err = client.RemoveWebhook(addedWebhook.ID, env.colonyPrvKey)
+	assert.Nil(t, err)
+
+	webhooks, err = client.GetWebhooks(env.colonyName, env.colonyPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 0)
+
+	err = client.RemoveWebhook(addedWebhook.ID, env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	env, client, server, _, done := setupTestEnv2(t)
+
+	type request struct {
+		body      []byte
+		eventType string
+		signature string
+	}
+	requests := make(chan request, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{body: body, eventType: r.Header.Get("X-Colonies-Event"), signature: r.Header.Get("X-Colonies-Signature")}
+	}))
+	defer receiver.Close()
+
+	webhook := core.CreateWebhook(env.colonyName, "test_webhook", receiver.URL, []string{core.ProcessSuccessfulEvent}, "", "test_secret")
+	addedWebhook, err := client.AddWebhook(webhook, env.colonyPrvKey)
+	assert.Nil(t, err)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	addedProcess, err := client.Submit(funcSpec, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err := client.Assign(env.colonyName, -1, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+
+	err = client.Close(assignedProcess.ID, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	select {
+	case r := <-requests:
+		assert.Equal(t, core.ProcessSuccessfulEvent, r.eventType)
+		assert.True(t, core.VerifyWebhookSignature("test_secret", r.body, r.signature))
+		event, err := core.ConvertJSONToEvent(string(r.body))
+		assert.Nil(t, err)
+		assert.Equal(t, addedProcess.ID, event.ObjectID)
+	case <-time.After(10 * time.Second):
+		assert.Fail(t, "Webhook was never called")
+	}
+
+	var deliveries []*core.WebhookDelivery
+	for i := 0; i < 50; i++ {
+		deliveries, err = client.GetWebhookDeliveries(addedWebhook.ID, 10, env.colonyPrvKey)
+		assert.Nil(t, err)
+		if len(deliveries) > 0 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Len(t, deliveries, 1)
+	assert.True(t, deliveries[0].Delivered)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+	assert.Equal(t, addedProcess.ID, deliveries[0].ObjectID)
+
+	_, err = client.GetWebhookDeliveries(addedWebhook.ID, MAX_COUNT+1, env.colonyPrvKey)
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+)
+
+const webhookQueueSize = 1000
+const webhookDeliveryWorkers = 4
+const webhookMaxAttempts = 5
+const webhookTimeout = 10 * time.Second
+const webhookDeliveryTTL = 7 * 24 * time.Hour
+const webhookMaxDeliveries = 1000
+const webhookDeliveryCleanupPeriod = 10 * time.Minute
+
+// Time to wait before the first retry, the backoff is doubled for every failed attempt
+var webhookRetryBackoff = 1 * time.Second
+
+type webhookJob struct {
+	webhook *core.Webhook
+	event   *core.Event
+}
+
+// webhookWorker delivers events to the webhooks registered in each colony. Every server in a cluster receives all
+// events, so only the node leading the webhook job delivers them, otherwise webhooks would be called once per server.
+// The leader also removes old deliveries, so that the delivery history does not grow forever. Leadership is cached for
+// JOB_LEADER_CACHE_PERIOD by isJobLeader, while every delivery attempt is fenced by runJob.
+func (controller *coloniesController) webhookWorker() {
+	defer close(controller.webhookDone)
+
+	for i := 0; i < webhookDeliveryWorkers; i++ {
+		go controller.webhookDeliveryWorker()
+	}
+
+	cleanupTicker := time.NewTicker(webhookDeliveryCleanupPeriod)
+	defer cleanupTicker.Stop()
+
+	eventChan, errChan := controller.eventHandler.subscribeEvents(nil, 0, controller.webhookCtx)
+	for {
+		select {
+		case <-cleanupTicker.C:
+			controller.runJob(WEBHOOK_JOB, func() {
+				controller.removeOldWebhookDeliveries(time.Now())
+			})
+		case event := <-eventChan:
+			if controller.isJobLeader(WEBHOOK_JOB) {
+				controller.dispatchWebhooks(event)
+			}
+		case <-errChan:
+			return
+		}
+	}
+}
+
+// removeOldWebhookDeliveries removes deliveries older than webhookDeliveryTTL, and all but the latest
+// webhookMaxDeliveries deliveries of every webhook
+func (controller *coloniesController) removeOldWebhookDeliveries(now time.Time) {
+	err := controller.db.RemoveExpiredWebhookDeliveries(now.Add(-webhookDeliveryTTL))
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to remove expired webhook deliveries")
+	}
+
+	err = controller.db.RemoveExcessWebhookDeliveries(webhookMaxDeliveries)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to remove excess webhook deliveries")
+	}
+}
+
+func (controller *coloniesController) dispatchWebhooks(event *core.Event) {
+	webhooks, err := controller.db.GetWebhooksByColonyName(event.ColonyName)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "ColonyName": event.ColonyName}).Error("Failed to get webhooks")
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Filter().Match(event) {
+			continue
+		}
+
+		select {
+		case controller.webhookQueue <- &webhookJob{webhook: webhook, event: event}:
+		default:
+			log.WithFields(log.Fields{"WebhookID": webhook.ID, "EventType": event.Type}).Warning("Webhook queue is full, dropping event")
+		}
+	}
+}
+
+func (controller *coloniesController) webhookDeliveryWorker() {
+	client := &http.Client{Timeout: webhookTimeout}
+	for {
+		select {
+		case job := <-controller.webhookQueue:
+			runAttempt := func(attempt func()) bool {
+				return controller.runJob(WEBHOOK_JOB, attempt)
+			}
+			delivery := deliverWebhook(controller.webhookCtx, client, job.webhook, job.event, runAttempt)
+			if delivery == nil {
+				log.WithFields(log.Fields{"WebhookID": job.webhook.ID, "EventType": job.event.Type}).Warning("Webhook job is fenced off, dropping event")
+				continue
+			}
+
+			controller.runJob(WEBHOOK_JOB, func() {
+				err := controller.db.AddWebhookDelivery(delivery)
+				if err != nil {
+					log.WithFields(log.Fields{"Error": err, "WebhookID": job.webhook.ID}).Error("Failed to add webhook delivery")
+				}
+			})
+		case <-controller.webhookCtx.Done():
+			return
+		}
+	}
+}
+
+// deliverWebhook posts the event to the webhook URL and retries with exponential backoff until the webhook responds
+// with a 2xx status code, the maximum number of attempts is reached, or ctx is cancelled. Every attempt is run by
+// runAttempt, which fences it by the webhook job, nil is returned if runAttempt refuses to run an attempt.
+func deliverWebhook(ctx context.Context, client *http.Client, webhook *core.Webhook, event *core.Event, runAttempt func(attempt func()) bool) *core.WebhookDelivery {
+	delivery := &core.WebhookDelivery{
+		ID:         core.GenerateRandomID(),
+		WebhookID:  webhook.ID,
+		ColonyName: webhook.ColonyName,
+		EventType:  event.Type,
+		ObjectID:   event.ObjectID,
+	}
+
+	jsonString, err := event.ToJSON()
+	if err != nil {
+		delivery.Error = err.Error()
+		delivery.Time = time.Now()
+		return delivery
+	}
+	payload := []byte(jsonString)
+
+	backoff := webhookRetryBackoff
+	for delivery.Attempts < webhookMaxAttempts {
+		if delivery.Attempts > 0 {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-ctx.Done():
+				delivery.Time = time.Now()
+				return delivery
+			}
+		}
+
+		ran := runAttempt(func() {
+			delivery.Attempts++
+			delivery.StatusCode, err = postWebhook(ctx, client, webhook, delivery.ID, event.Type, payload)
+		})
+		if !ran {
+			return nil
+		}
+		if err == nil {
+			delivery.Delivered = true
+			delivery.Error = ""
+			break
+		}
+
+		delivery.Error = err.Error()
+		log.WithFields(log.Fields{"WebhookID": webhook.ID, "URL": webhook.URL, "Attempt": delivery.Attempts, "Error": err}).Debug("Failed to deliver webhook")
+	}
+
+	delivery.Time = time.Now()
+
+	return delivery
+}
+
+func postWebhook(ctx context.Context, client *http.Client, webhook *core.Webhook, deliveryID string, eventType string, payload []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Colonies-Event", eventType)
+	req.Header.Set("X-Colonies-Delivery", deliveryID)
+	if webhook.Secret != "" {
+		req.Header.Set("X-Colonies-Signature", core.SignWebhookPayload(webhook.Secret, payload))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.New("Webhook responded with status code " + strconv.Itoa(resp.StatusCode))
+	}
+
+	return resp.StatusCode, nil
+}
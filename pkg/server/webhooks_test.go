@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestWebhookEvent() *core.Event {
+	graph, _ := core.CreateProcessGraph("test_colony")
+	graph.State = core.FAILED
+	return core.CreateProcessGraphEvent(graph)
+}
+
+func runUnfenced(attempt func()) bool {
+	attempt()
+	return true
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, core.ProcessGraphFailedEvent, r.Header.Get("X-Colonies-Event"))
+		assert.NotEmpty(t, r.Header.Get("X-Colonies-Delivery"))
+		assert.NotEmpty(t, r.Header.Get("X-Colonies-Signature"))
+	}))
+	defer receiver.Close()
+
+	webhook := core.CreateWebhook("test_colony", "test_webhook", receiver.URL, []string{}, "", "test_secret")
+	event := createTestWebhookEvent()
+
+	delivery := deliverWebhook(context.Background(), receiver.Client(), webhook, event, runUnfenced)
+	assert.True(t, delivery.Delivered)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusOK, delivery.StatusCode)
+	assert.Equal(t, event.ObjectID, delivery.ObjectID)
+	assert.Empty(t, delivery.Error)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestDeliverWebhookRetry(t *testing.T) {
+	savedBackoff := webhookRetryBackoff
+	webhookRetryBackoff = 10 * time.Millisecond
+	defer func() { webhookRetryBackoff = savedBackoff }()
+
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer receiver.Close()
+
+	webhook := core.CreateWebhook("test_colony", "test_webhook", receiver.URL, []string{}, "", "")
+	delivery := deliverWebhook(context.Background(), receiver.Client(), webhook, createTestWebhookEvent(), runUnfenced)
+	assert.True(t, delivery.Delivered)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, http.StatusOK, delivery.StatusCode)
+}
+
+func TestDeliverWebhookFailed(t *testing.T) {
+	savedBackoff := webhookRetryBackoff
+	webhookRetryBackoff = 10 * time.Millisecond
+	defer func() { webhookRetryBackoff = savedBackoff }()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	webhook := core.CreateWebhook("test_colony", "test_webhook", receiver.URL, []string{}, "", "")
+	delivery := deliverWebhook(context.Background(), receiver.Client(), webhook, createTestWebhookEvent(), runUnfenced)
+	assert.False(t, delivery.Delivered)
+	assert.Equal(t, webhookMaxAttempts, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.StatusCode)
+	assert.NotEmpty(t, delivery.Error)
+}
+
+func TestDeliverWebhookCancelled(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	webhook := core.CreateWebhook("test_colony", "test_webhook", receiver.URL, []string{}, "", "")
+	delivery := deliverWebhook(ctx, receiver.Client(), webhook, createTestWebhookEvent(), runUnfenced)
+	assert.False(t, delivery.Delivered)
+	assert.Equal(t, 1, delivery.Attempts)
+}
+
+func TestDeliverWebhookFencedOff(t *testing.T) {
+	savedBackoff := webhookRetryBackoff
+	webhookRetryBackoff = 10 * time.Millisecond
+	defer func() { webhookRetryBackoff = savedBackoff }()
+
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	// Another node takes over the webhook job after the first attempt, the delivery is then dropped
+	var attempts int
+	runAttempt := func(attempt func()) bool {
+		attempts++
+		if attempts > 1 {
+			return false
+		}
+		attempt()
+		return true
+	}
+
+	webhook := core.CreateWebhook("test_colony", "test_webhook", receiver.URL, []string{}, "", "")
+	delivery := deliverWebhook(context.Background(), receiver.Client(), webhook, createTestWebhookEvent(), runAttempt)
+	assert.Nil(t, delivery)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
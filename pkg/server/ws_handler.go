@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -45,6 +46,12 @@ func (server *ColoniesServer) handleWSRequest(c *gin.Context) {
 		return
 	}
 
+	// A draining server does not accept new subscriptions, the client should subscribe on another server
+	if server.IsDraining() {
+		closeDraining(wsConn)
+		return
+	}
+
 	for {
 		wsMsgType, data, err := wsConn.ReadMessage()
 		if err != nil {
@@ -93,7 +100,7 @@ func (server *ColoniesServer) handleWSRequest(c *gin.Context) {
 			}
 
 			processSubcription := createProcessesSubscription(wsConn, wsMsgType, msg.ExecutorType, msg.Timeout, msg.State)
-			err = server.controller.subscribeProcesses(recoveredID, processSubcription)
+			err = server.controller.subscribeProcesses(recoveredID, msg.ColonyName, processSubcription)
 			if err != nil {
 				err := server.sendWSErrorMsg(err, http.StatusForbidden, wsConn, wsMsgType)
 				if err != nil {
@@ -150,7 +157,7 @@ func (server *ColoniesServer) handleWSRequest(c *gin.Context) {
 			}
 
 			processSubcription := createProcessSubscription(wsConn, wsMsgType, msg.ProcessID, msg.ExecutorType, msg.Timeout, msg.State)
-			err = server.controller.subscribeProcess(recoveredID, processSubcription)
+			err = server.controller.subscribeProcess(recoveredID, msg.ColonyName, processSubcription)
 			if err != nil {
 				err := server.sendWSErrorMsg(err, http.StatusForbidden, wsConn, wsMsgType)
 				if err != nil {
@@ -158,6 +165,81 @@ func (server *ColoniesServer) handleWSRequest(c *gin.Context) {
 				}
 				return
 			}
+
+		case rpc.SubscribeEventsPayloadType:
+			msg, err := rpc.CreateSubscribeEventsMsgFromJSON(rpcMsg.DecodePayload())
+			if server.handleHTTPError(c, err, http.StatusBadRequest) {
+				return
+			}
+			if msg.MsgType != rpcMsg.PayloadType {
+				err := server.sendWSErrorMsg(errors.New("Failed to subscribe to events, msg.msgType does not match rpcMsg.PayloadType"), http.StatusForbidden, wsConn, wsMsgType)
+				if err != nil {
+					log.WithFields(log.Fields{"Error": err}).Error("Failed to subscribe to events, failed to call server.sendWSErrorMsg()")
+				}
+				return
+			}
+
+			err = server.validator.RequireMembership(recoveredID, msg.ColonyName, true)
+			if err != nil {
+				err := server.sendWSErrorMsg(err, http.StatusForbidden, wsConn, wsMsgType)
+				if err != nil {
+					log.WithFields(log.Fields{"Error": err}).Error("Failed to subscribe to events, not member of colony")
+				}
+				return
+			}
+
+			eventFilter := &core.EventFilter{ColonyName: msg.ColonyName, Types: msg.Types, Label: msg.Label}
+			eventsSubscription := createEventsSubscription(wsConn, wsMsgType, eventFilter, msg.FromSeq, msg.Timeout)
+			err = server.controller.subscribeEvents(recoveredID, eventsSubscription)
+			if err != nil {
+				err := server.sendWSErrorMsg(err, http.StatusForbidden, wsConn, wsMsgType)
+				if err != nil {
+					log.WithFields(log.Fields{"Error": err}).Error("Failed to subscribe to events")
+				}
+				return
+			}
+
+		case rpc.SubscribeLogsPayloadType:
+			msg, err := rpc.CreateSubscribeLogsMsgFromJSON(rpcMsg.DecodePayload())
+			if server.handleHTTPError(c, err, http.StatusBadRequest) {
+				return
+			}
+			if msg.MsgType != rpcMsg.PayloadType {
+				err := server.sendWSErrorMsg(errors.New("Failed to subscribe to logs, msg.msgType does not match rpcMsg.PayloadType"), http.StatusForbidden, wsConn, wsMsgType)
+				if err != nil {
+					log.WithFields(log.Fields{"Error": err}).Error("Failed to subscribe to logs, failed to call server.sendWSErrorMsg()")
+				}
+				return
+			}
+
+			if msg.Level != "" && !core.IsValidLogLevel(msg.Level) {
+				err := server.sendWSErrorMsg(errors.New("Failed to subscribe to logs, invalid level <"+msg.Level+">"), http.StatusBadRequest, wsConn, wsMsgType)
+				if err != nil {
+					log.WithFields(log.Fields{"Error": err}).Error("Failed to subscribe to logs, failed to call server.sendWSErrorMsg()")
+				}
+				return
+			}
+
+			// Logs are only published to subscribers in the same colony, see core.LogFilter
+			err = server.validator.RequireMembership(recoveredID, msg.ColonyName, true)
+			if err != nil {
+				err := server.sendWSErrorMsg(err, http.StatusForbidden, wsConn, wsMsgType)
+				if err != nil {
+					log.WithFields(log.Fields{"Error": err}).Error("Failed to subscribe to logs, not member of colony")
+				}
+				return
+			}
+
+			logFilter := &core.LogFilter{ColonyName: msg.ColonyName, ProcessID: msg.ProcessID, ExecutorName: msg.ExecutorName, Level: msg.Level, Fields: msg.Fields}
+			logsSubscription := createLogsSubscription(wsConn, wsMsgType, logFilter, msg.Since, msg.Timeout)
+			err = server.controller.subscribeLogs(recoveredID, logsSubscription)
+			if err != nil {
+				err := server.sendWSErrorMsg(err, http.StatusForbidden, wsConn, wsMsgType)
+				if err != nil {
+					log.WithFields(log.Fields{"Error": err}).Error("Failed to subscribe to logs")
+				}
+				return
+			}
 		}
 	}
 }
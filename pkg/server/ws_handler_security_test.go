@@ -111,3 +111,51 @@ func TestSubscribeProcessSecurityInvalidProcessID(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+func TestSubscribeEventsSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// Executor 2 is not member of colony 1
+	subscription, err := client.SubscribeEvents(env.colony1Name, []string{}, "", 0, 100, env.executor2PrvKey)
+	assert.Nil(t, err)
+
+	waitForEvent := make(chan error)
+	go func() {
+		select {
+		case <-subscription.EventChan:
+			waitForEvent <- nil
+		case err := <-subscription.ErrChan:
+			waitForEvent <- err
+		}
+	}()
+
+	err = <-waitForEvent
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestSubscribeLogsSecurity(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	// Executor 2 is not member of colony 1
+	subscription, err := client.SubscribeLogs(env.colony1Name, "", "", "", nil, 0, 100, env.executor2PrvKey)
+	assert.Nil(t, err)
+
+	waitForLog := make(chan error)
+	go func() {
+		select {
+		case <-subscription.LogChan:
+			waitForLog <- nil
+		case err := <-subscription.ErrChan:
+			waitForLog <- err
+		}
+	}()
+
+	err = <-waitForLog
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
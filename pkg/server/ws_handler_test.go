@@ -168,3 +168,210 @@ func TestSubscribeChangeStateProcess2(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+// Executor 1 subscribes on process events in a colony
+// Executor 1 submits a process, gets it assigned and closes it
+// Executor 1 receives an event for every state transition
+func TestSubscribeEvents(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	subscription, err := client.SubscribeEvents(env.colony1Name, []string{core.ProcessEventKind}, "", 0, 100, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	time.Sleep(1 * time.Second)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colony1Name)
+	addedProcess, err := client.Submit(funcSpec, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err := client.Assign(env.colony1Name, -1, "", "", env.executor1PrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, addedProcess.ID, assignedProcess.ID)
+
+	err = client.Close(assignedProcess.ID, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	expectedTypes := []string{core.ProcessWaitingEvent, core.ProcessRunningEvent, core.ProcessSuccessfulEvent}
+	for _, expectedType := range expectedTypes {
+		select {
+		case event := <-subscription.EventChan:
+			assert.Equal(t, expectedType, event.Type)
+			assert.Equal(t, addedProcess.ID, event.ObjectID)
+			assert.Equal(t, env.colony1Name, event.ColonyName)
+			assert.NotNil(t, event.Process)
+		case err := <-subscription.ErrChan:
+			assert.Fail(t, err.Error())
+		}
+	}
+
+	subscription.Close()
+	server.Shutdown()
+	<-done
+}
+
+// Executor 1 submits a process, gets it assigned and closes it before subscribing
+// Executor 1 subscribes from the first sequence number and receives the stored events followed by new events
+func TestSubscribeEventsResume(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colony1Name)
+	addedProcess, err := client.Submit(funcSpec, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err := client.Assign(env.colony1Name, -1, "", "", env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	err = client.Close(assignedProcess.ID, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	subscription, err := client.SubscribeEvents(env.colony1Name, []string{core.ProcessEventKind}, "", 1, 100, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	lastSeq := int64(0)
+	expectedTypes := []string{core.ProcessWaitingEvent, core.ProcessRunningEvent, core.ProcessSuccessfulEvent}
+	for _, expectedType := range expectedTypes {
+		select {
+		case event := <-subscription.EventChan:
+			assert.Equal(t, expectedType, event.Type)
+			assert.Equal(t, addedProcess.ID, event.ObjectID)
+			assert.Greater(t, event.Seq, lastSeq)
+			lastSeq = event.Seq
+		case err := <-subscription.ErrChan:
+			assert.Fail(t, err.Error())
+		}
+	}
+
+	time.Sleep(1 * time.Second)
+
+	addedProcess2, err := client.Submit(funcSpec, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	select {
+	case event := <-subscription.EventChan:
+		assert.Equal(t, core.ProcessWaitingEvent, event.Type)
+		assert.Equal(t, addedProcess2.ID, event.ObjectID)
+		assert.Greater(t, event.Seq, lastSeq)
+	case err := <-subscription.ErrChan:
+		assert.Fail(t, err.Error())
+	}
+
+	subscription.Close()
+	server.Shutdown()
+	<-done
+}
+
+// Subscribers only receive events matching the colony, types and label in the subscription
+func TestSubscribeEventsFilter(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	subscription, err := client.SubscribeEvents(env.colony1Name, []string{core.SnapshotCreatedEvent}, "/test_label", 0, 100, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	time.Sleep(1 * time.Second)
+
+	// Not matching, another type
+	funcSpec := utils.CreateTestFunctionSpec(env.colony1Name)
+	_, err = client.Submit(funcSpec, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	// Not matching, another colony
+	_, err = client.CreateSnapshot(env.colony2Name, "/test_label", "test_snapshot1", env.executor2PrvKey)
+	assert.Nil(t, err)
+
+	// Not matching, another label
+	_, err = client.CreateSnapshot(env.colony1Name, "/another_label", "test_snapshot2", env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	snapshot, err := client.CreateSnapshot(env.colony1Name, "/test_label", "test_snapshot3", env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	select {
+	case event := <-subscription.EventChan:
+		assert.Equal(t, core.SnapshotCreatedEvent, event.Type)
+		assert.Equal(t, snapshot.ID, event.ObjectID)
+		assert.True(t, snapshot.Equals(event.Snapshot))
+	case err := <-subscription.ErrChan:
+		assert.Fail(t, err.Error())
+	}
+
+	subscription.Close()
+	server.Shutdown()
+	<-done
+}
+
+func TestSubscribeLogs(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colony1Name)
+	_, err := client.Submit(funcSpec, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err := client.Assign(env.colony1Name, -1, "", "", env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	subscription, err := client.SubscribeLogs(env.colony1Name, assignedProcess.ID, "", core.LogLevelWarn, nil, 0, 100, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	time.Sleep(1 * time.Second)
+
+	// Not matching, below the subscribed level
+	err = client.AddStructuredLog(assignedProcess.ID, core.LogLevelInfo, core.LogStreamStdout, "test_msg1", nil, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	err = client.AddStructuredLog(assignedProcess.ID, core.LogLevelError, core.LogStreamStderr, "test_msg2", map[string]string{"key": "value"}, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	select {
+	case l := <-subscription.LogChan:
+		assert.Equal(t, "test_msg2", l.Message)
+		assert.Equal(t, core.LogLevelError, l.Level)
+		assert.Equal(t, core.LogStreamStderr, l.Stream)
+		assert.Equal(t, "value", l.Fields["key"])
+		assert.Equal(t, assignedProcess.ID, l.ProcessID)
+	case err := <-subscription.ErrChan:
+		assert.Fail(t, err.Error())
+	}
+
+	subscription.Close()
+	server.Shutdown()
+	<-done
+}
+
+// Stored logs added after since are sent before new logs
+func TestSubscribeLogsSince(t *testing.T) {
+	env, client, server, _, done := setupTestEnv1(t)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colony1Name)
+	_, err := client.Submit(funcSpec, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err := client.Assign(env.colony1Name, -1, "", "", env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	err = client.AddLog(assignedProcess.ID, "test_msg1", env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	err = client.AddLog(assignedProcess.ID, "test_msg2", env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	subscription, err := client.SubscribeLogs(env.colony1Name, assignedProcess.ID, "", "", nil, 1, 100, env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	time.Sleep(1 * time.Second)
+
+	err = client.AddLog(assignedProcess.ID, "test_msg3", env.executor1PrvKey)
+	assert.Nil(t, err)
+
+	for _, expectedMsg := range []string{"test_msg1", "test_msg2", "test_msg3"} {
+		select {
+		case l := <-subscription.LogChan:
+			assert.Equal(t, expectedMsg, l.Message)
+		case err := <-subscription.ErrChan:
+			assert.Fail(t, err.Error())
+		}
+	}
+
+	subscription.Close()
+	server.Shutdown()
+	<-done
+}
@@ -17,10 +17,16 @@ type subscription struct {
 	executorType string
 	state        int
 	processID    string
+	eventFilter  *core.EventFilter
+	fromSeq      int64
+	logFilter    *core.LogFilter
+	since        int64
 }
 
 type wsSubscriptionController struct {
 	eventHandler *eventHandler
+	drainCtx     context.Context // Cancelled when the server starts draining
+	drainCancel  context.CancelFunc
 }
 
 // Used by ColoniesServer
@@ -51,14 +57,47 @@ func createProcessSubscription(wsConn *websocket.Conn, wsMsgType int, processID
 		state:        state}
 }
 
+func createEventsSubscription(wsConn *websocket.Conn, wsMsgType int, eventFilter *core.EventFilter, fromSeq int64, timeout int) *subscription {
+	return &subscription{wsConn: wsConn,
+		wsMsgType:   wsMsgType,
+		timeout:     timeout,
+		eventFilter: eventFilter,
+		fromSeq:     fromSeq}
+}
+
+func createLogsSubscription(wsConn *websocket.Conn, wsMsgType int, logFilter *core.LogFilter, since int64, timeout int) *subscription {
+	return &subscription{wsConn: wsConn,
+		wsMsgType: wsMsgType,
+		timeout:   timeout,
+		logFilter: logFilter,
+		since:     since}
+}
+
 // Used by coloniesController
 func createWSSubscriptionController(eventHandler *eventHandler) *wsSubscriptionController {
 	wsSubCtrl := &wsSubscriptionController{}
 	wsSubCtrl.eventHandler = eventHandler
+	wsSubCtrl.drainCtx, wsSubCtrl.drainCancel = context.WithCancel(context.Background())
 
 	return wsSubCtrl
 }
 
+// drain tells all subscribers to reconnect to another server and closes their connections
+func (wsSubCtrl *wsSubscriptionController) drain() {
+	wsSubCtrl.drainCancel()
+}
+
+// closeDraining closes the connection with a service restart close frame, which clients use to reconnect to another
+// server instead of treating the closed connection as an error
+func closeDraining(wsConn *websocket.Conn) {
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseServiceRestart, core.ServerDrainingMessage)
+	err := wsConn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Debug("Failed to send close frame to subscriber")
+	}
+	wsConn.Close()
+}
+
 func (wsSubCtrl *wsSubscriptionController) sendProcessToWS(executorID string,
 	process *core.Process,
 	wsConn *websocket.Conn,
@@ -124,6 +163,9 @@ func (wsSubCtrl *wsSubscriptionController) subscribe(executorID string, processI
 					Debug("Subscriber timed out")
 				subscription.wsConn.Close()
 				return // This will kill the go-routine, also note all cancelCtx will result in an err to errChan
+			case <-wsSubCtrl.drainCtx.Done():
+				closeDraining(subscription.wsConn)
+				return
 			case process := <-processChan:
 				wsSubCtrl.sendProcessToWS(executorID, process, subscription.wsConn, subscription.wsMsgType, func() { cancelCtx() })
 			}
@@ -144,3 +186,150 @@ func (wsSubCtrl *wsSubscriptionController) addProcessSubscriber(executorID strin
 		wsSubCtrl.sendProcessToWS(executorID, process, subscription.wsConn, subscription.wsMsgType, func() {})
 	}
 }
+
+func (wsSubCtrl *wsSubscriptionController) sendEventToWS(executorID string,
+	event *core.Event,
+	wsConn *websocket.Conn,
+	wsMsgType int,
+	cancel func()) {
+	jsonString, err := event.ToJSON()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ExecutorID": executorID,
+			"EventType":  event.Type,
+			"Error":      err}).
+			Error("Failed to create Event JSON when subscribing to events")
+		cancel()
+		return
+	}
+	rpcReplyMsg, err := rpc.CreateRPCReplyMsg(rpc.SubscribeEventsPayloadType, jsonString)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ExecutorID": executorID,
+			"EventType":  event.Type,
+			"Error":      err}).
+			Error("Failed to create RPCReplyMsg when subscribing to events")
+		cancel()
+		return
+	}
+	rpcReplyJSONString, err := rpcReplyMsg.ToJSON()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ExecutorID": executorID,
+			"EventType":  event.Type,
+			"Error":      err}).
+			Error("Failed to create RPCReplyMsg JSON when subscribing to events")
+		cancel()
+		return
+	}
+	err = wsConn.WriteMessage(wsMsgType, []byte(rpcReplyJSONString))
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ExecutorID": executorID,
+			"EventType":  event.Type,
+			"Error":      err}).
+			Error("Failed to write RPCReplyMsg JSON to WS when subscribing to events")
+		cancel()
+	}
+}
+
+func (wsSubCtrl *wsSubscriptionController) addEventsSubscriber(executorID string, subscription *subscription) {
+	go func() {
+		ctx, cancelCtx := context.WithTimeout(context.Background(), time.Duration(subscription.timeout)*time.Second)
+		defer cancelCtx()
+
+		eventChan, errChan := wsSubCtrl.eventHandler.subscribeEvents(subscription.eventFilter, subscription.fromSeq, ctx)
+		for {
+			select {
+			case err := <-errChan:
+				log.WithFields(log.Fields{
+					"ExecutorID": executorID,
+					"ColonyName": subscription.eventFilter.ColonyName,
+					"Types":      subscription.eventFilter.Types,
+					"Error":      err}).
+					Debug("Event subscriber timed out")
+				subscription.wsConn.Close()
+				return
+			case <-wsSubCtrl.drainCtx.Done():
+				closeDraining(subscription.wsConn)
+				return
+			case event := <-eventChan:
+				wsSubCtrl.sendEventToWS(executorID, event, subscription.wsConn, subscription.wsMsgType, func() { cancelCtx() })
+			}
+		}
+	}()
+}
+
+func (wsSubCtrl *wsSubscriptionController) sendLogToWS(executorID string,
+	l *core.Log,
+	wsConn *websocket.Conn,
+	wsMsgType int,
+	cancel func()) {
+	jsonString, err := l.ToJSON()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ExecutorID": executorID,
+			"ProcessId":  l.ProcessID,
+			"Error":      err}).
+			Error("Failed to create Log JSON when subscribing to logs")
+		cancel()
+		return
+	}
+	rpcReplyMsg, err := rpc.CreateRPCReplyMsg(rpc.SubscribeLogsPayloadType, jsonString)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ExecutorID": executorID,
+			"ProcessId":  l.ProcessID,
+			"Error":      err}).
+			Error("Failed to create RPCReplyMsg when subscribing to logs")
+		cancel()
+		return
+	}
+	rpcReplyJSONString, err := rpcReplyMsg.ToJSON()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ExecutorID": executorID,
+			"ProcessId":  l.ProcessID,
+			"Error":      err}).
+			Error("Failed to create RPCReplyMsg JSON when subscribing to logs")
+		cancel()
+		return
+	}
+	err = wsConn.WriteMessage(wsMsgType, []byte(rpcReplyJSONString))
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ExecutorID": executorID,
+			"ProcessId":  l.ProcessID,
+			"Error":      err}).
+			Error("Failed to write RPCReplyMsg JSON to WS when subscribing to logs")
+		cancel()
+	}
+}
+
+func (wsSubCtrl *wsSubscriptionController) addLogsSubscriber(executorID string, subscription *subscription) {
+	go func() {
+		ctx, cancelCtx := context.WithTimeout(context.Background(), time.Duration(subscription.timeout)*time.Second)
+		defer cancelCtx()
+
+		logChan, errChan := wsSubCtrl.eventHandler.subscribeLogs(subscription.logFilter, subscription.since, ctx)
+		for {
+			select {
+			case err := <-errChan:
+				log.WithFields(log.Fields{
+					"ExecutorID":   executorID,
+					"ColonyName":   subscription.logFilter.ColonyName,
+					"ProcessId":    subscription.logFilter.ProcessID,
+					"ExecutorName": subscription.logFilter.ExecutorName,
+					"Error":        err}).
+					Debug("Log subscriber timed out")
+				subscription.wsConn.Close()
+				return
+			case <-wsSubCtrl.drainCtx.Done():
+				closeDraining(subscription.wsConn)
+				return
+			case l := <-logChan:
+				wsSubCtrl.sendLogToWS(executorID, l, subscription.wsConn, subscription.wsMsgType, func() { cancelCtx() })
+			}
+		}
+	}()
+}
@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const TracerName = "github.com/colonyos/colonies"
+
+const (
+	traceParentHeader = "traceparent"
+	traceStateHeader  = "tracestate"
+)
+
+// Trace context is always propagated in the W3C format, independent of the global propagator
+var propagator = propagation.TraceContext{}
+
+// Setup configures the global tracer provider to export spans using OTLP over gRPC to endpoint, e.g. localhost:4317.
+// If endpoint is empty, tracing is disabled and spans are not recorded, but incoming trace context is still propagated.
+// The returned function flushes remaining spans and stops the exporter.
+func Setup(serviceName string, endpoint string, insecure bool) (func(context.Context) error, error) {
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	if serviceName == "" {
+		return nil, errors.New("Service name must be set")
+	}
+
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))))
+	otel.SetTracerProvider(tracerProvider)
+
+	return tracerProvider.Shutdown, nil
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Inject returns the W3C traceparent and tracestate of the span in ctx, both are empty if ctx has no valid span
+func Inject(ctx context.Context) (string, string) {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+
+	return carrier.Get(traceParentHeader), carrier.Get(traceStateHeader)
+}
+
+// Extract returns a copy of ctx with the remote span described by traceParent and traceState as parent, ctx is
+// returned unchanged if traceParent is empty or invalid
+func Extract(ctx context.Context, traceParent string, traceState string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+
+	carrier := propagation.MapCarrier{traceParentHeader: traceParent}
+	if traceState != "" {
+		carrier.Set(traceStateHeader, traceState)
+	}
+
+	return propagator.Extract(ctx, carrier)
+}
+
+// ProcessContext returns a copy of ctx continuing the trace the process was submitted in
+func ProcessContext(ctx context.Context, process *core.Process) context.Context {
+	return Extract(ctx, process.TraceParent, process.TraceState)
+}
+
+// StartProcessSpan starts a span in the trace the process was submitted in. Executors use it to trace the execution of
+// a process, pass the returned context to e.g. ColoniesClient.CloseWithContext to continue the trace.
+func StartProcessSpan(ctx context.Context, process *core.Process, name string) (context.Context, trace.Span) {
+	return Tracer().Start(ProcessContext(ctx, process), name, trace.WithAttributes(ProcessAttributes(process)...))
+}
+
+func ProcessAttributes(process *core.Process) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("colonies.process.id", process.ID),
+		attribute.String("colonies.colony.name", process.FunctionSpec.Conditions.ColonyName),
+		attribute.String("colonies.executor.type", process.FunctionSpec.Conditions.ExecutorType),
+		attribute.String("colonies.function.name", process.FunctionSpec.FuncName),
+	}
+}
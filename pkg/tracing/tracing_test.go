@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectExtract(t *testing.T) {
+	traceParent, traceState := Inject(context.Background())
+	assert.Equal(t, "", traceParent)
+	assert.Equal(t, "", traceState)
+
+	tracerProvider := sdktrace.NewTracerProvider()
+	ctx, span := tracerProvider.Tracer(TracerName).Start(context.Background(), "test")
+	defer span.End()
+
+	traceParent, _ = Inject(ctx)
+	assert.NotEqual(t, "", traceParent)
+
+	spanContext := trace.SpanContextFromContext(Extract(context.Background(), traceParent, ""))
+	assert.True(t, spanContext.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), spanContext.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), spanContext.SpanID())
+
+	ctx = Extract(context.Background(), "invalid", "")
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
+
+func TestProcessContext(t *testing.T) {
+	tracerProvider := sdktrace.NewTracerProvider()
+	ctx, span := tracerProvider.Tracer(TracerName).Start(context.Background(), "test")
+	defer span.End()
+
+	process := core.CreateProcess(core.CreateEmptyFunctionSpec())
+	assert.False(t, trace.SpanContextFromContext(ProcessContext(context.Background(), process)).IsValid())
+
+	process.TraceParent, process.TraceState = Inject(ctx)
+	spanContext := trace.SpanContextFromContext(ProcessContext(context.Background(), process))
+	assert.Equal(t, span.SpanContext().TraceID(), spanContext.TraceID())
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup("test", "", false)
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+}